@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/similarity"
 )
 
@@ -22,7 +23,7 @@ func newDuplicationCmd() *cobra.Command {
 		Short: "Find duplicated or near-identical functions within the codebase",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -40,16 +41,9 @@ func newDuplicationCmd() *cobra.Command {
 
 			if jsonOutput {
 				if countOnly {
-					return emitJSON(struct {
-						Count int `json:"count"`
-					}{Count: len(pairs)})
+					return emitJSON(report.CountReport{Count: len(pairs)})
 				}
-				return emitJSON(struct {
-					Threshold float64           `json:"threshold"`
-					Limit     int               `json:"limit"`
-					Count     int               `json:"count"`
-					Pairs     []similarity.Pair `json:"pairs,omitempty"`
-				}{
+				return emitJSON(report.DuplicationReport{
 					Threshold: threshold,
 					Limit:     limit,
 					Count:     len(pairs),
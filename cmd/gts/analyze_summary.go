@@ -71,7 +71,7 @@ func newSummaryCmd() *cobra.Command {
 		Short: "Aggregate structural metrics dashboard for a codebase",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
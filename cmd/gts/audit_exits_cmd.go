@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/exits"
+	"github.com/odvcencio/gts-suite/internal/lint"
+	"github.com/odvcencio/gts-suite/pkg/report"
+)
+
+func newAuditExitsCmd() *cobra.Command {
+	var cachePath string
+	var noCache bool
+	var allow []string
+	var noDefaults bool
+	var showAll bool
+	var jsonOutput bool
+	var countOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "exits [path]",
+		Short: "Find panic, os.Exit, log.Fatal, process.exit, and sys.exit calls outside approved files",
+		Long: `Find panic, os.Exit, log.Fatal, process.exit, and sys.exit calls outside approved files.
+
+Audits calls that end the process or unwind the stack: panic, os.Exit, and
+log.Fatal/log.Panic in Go; process.exit in JavaScript and TypeScript;
+sys.exit, os._exit, and exit in Python; System.exit in Java; panic! and
+process::exit in Rust; exit and abort in Ruby and C.
+
+Go main packages, Python __main__.py, Rust main.rs and src/bin, scripts under
+bin/, test files, and test helper directories (testutil, testing, fixtures)
+are approved unless --no-defaults is set. Approve more with --allow globs or
+.gtslint ignore directives:
+
+  ignore exits in tools/            # every audited call under tools/
+  ignore os.exit in cmd/serve.go    # one call in one file
+  ignore panic in pkg/must.go:Must  # one kind in one function
+
+The command exits 1 when unapproved calls are found.
+
+Examples:
+  gts audit exits
+  gts audit exits --allow "scripts/**" --json
+  gts audit exits --all`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := defaultTarget()
+			if len(args) == 1 {
+				target = args[0]
+			}
+
+			idx, err := loadOrBuild(cachePath, target, noCache)
+			if err != nil {
+				return err
+			}
+			cfg, err := lint.LoadConfig(idx.Root)
+			if err != nil {
+				return fmt.Errorf("loading .gtslint: %w", err)
+			}
+			findings, err := exits.Audit(idx, exits.Options{Allow: allow, NoDefaults: noDefaults, Config: cfg})
+			if err != nil {
+				return err
+			}
+
+			violations := 0
+			shown := findings[:0]
+			for _, finding := range findings {
+				if !finding.Allowed {
+					violations++
+				}
+				if showAll || !finding.Allowed {
+					shown = append(shown, finding)
+				}
+			}
+
+			switch {
+			case jsonOutput && countOnly:
+				if err := emitJSON(report.CountReport{Count: violations}); err != nil {
+					return err
+				}
+			case jsonOutput:
+				if err := emitJSON(report.ExitsReport{Violations: violations, Findings: shown}); err != nil {
+					return err
+				}
+			case countOnly:
+				fmt.Println(violations)
+			default:
+				for _, finding := range shown {
+					line := fmt.Sprintf("%s:%d %s %s", finding.File, finding.Line, finding.Kind, finding.Call)
+					if finding.Symbol != "" {
+						line += " in " + finding.Symbol
+					}
+					if finding.Allowed {
+						line += " (" + finding.Reason + ")"
+					}
+					fmt.Println(line)
+				}
+				fmt.Printf("audit exits: calls=%d violations=%d\n", len(findings), violations)
+			}
+
+			if violations > 0 {
+				return exitCodeError{code: 1, err: fmt.Errorf("audit found %d unapproved exit calls", violations)}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
+	cmd.Flags().StringArrayVar(&allow, "allow", nil, "approve calls in files matching this glob or directory (repeatable)")
+	cmd.Flags().BoolVar(&noDefaults, "no-defaults", false, "do not approve main packages, entry points, and test files")
+	cmd.Flags().BoolVar(&showAll, "all", false, "list approved calls too, with the reason")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of unapproved calls")
+	return cmd
+}
+
+func runAuditExits(args []string) error {
+	cmd := newAuditExitsCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
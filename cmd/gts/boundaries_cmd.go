@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/odvcencio/gts-suite/internal/deps"
+	"github.com/odvcencio/gts-suite/pkg/annotate"
 	"github.com/odvcencio/gts-suite/pkg/boundaries"
 	"github.com/odvcencio/gts-suite/pkg/sarif"
 )
@@ -34,7 +35,7 @@ func newBoundariesCmd() *cobra.Command {
 		Short: "Check module boundary rules defined in .gtsboundaries",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -129,6 +130,19 @@ func newBoundariesCmd() *cobra.Command {
 				if err := log.Encode(os.Stdout); err != nil {
 					return err
 				}
+			case "github", "gitlab":
+				annotations := make([]annotate.Annotation, 0, len(violations))
+				for _, v := range violations {
+					annotations = append(annotations, annotate.Annotation{
+						RuleID:   "boundary-violation",
+						Severity: "error",
+						Message:  v.Message,
+						File:     v.From,
+					})
+				}
+				if err := writeAnnotations(outputFmt, annotations); err != nil {
+					return err
+				}
 			case "json":
 				if err := emitJSON(result); err != nil {
 					return err
@@ -154,7 +168,7 @@ func newBoundariesCmd() *cobra.Command {
 	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
-	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, sarif")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, sarif, github, gitlab")
 	cmd.Flags().StringVar(&base, "base", "", "git ref to diff against -- only report violations in changed files")
 	return cmd
 }
@@ -19,6 +19,7 @@ func newBridgeCmd() *cobra.Command {
 	var jsonOutput bool
 	var countOnly bool
 	var dotOutput bool
+	var resultCache bool
 
 	cmd := &cobra.Command{
 		Use:     "bridge [path]",
@@ -33,7 +34,7 @@ func newBridgeCmd() *cobra.Command {
 				return fmt.Errorf("depth must be > 0")
 			}
 
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -43,11 +44,14 @@ func newBridgeCmd() *cobra.Command {
 				return err
 			}
 
-			report, err := bridge.Build(idx, bridge.Options{
+			opts := bridge.Options{
 				Top:     top,
 				Focus:   focus,
 				Depth:   depth,
 				Reverse: reverse,
+			}
+			report, err := cachedResult(resultCache, idx, "bridge", opts, func() (bridge.Report, error) {
+				return bridge.Build(idx, opts)
 			})
 			if err != nil {
 				return err
@@ -137,6 +141,7 @@ func newBridgeCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print only the count of bridge edges")
 	cmd.Flags().BoolVar(&dotOutput, "dot", false, "emit DOT graph for Graphviz visualization")
+	cmd.Flags().BoolVar(&resultCache, "result-cache", false, "reuse results stored under .gts/results for an identical index and flags, storing them on a miss")
 	return cmd
 }
 
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/cachedir"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and prune the .gts cache directory",
+		Long: `Inspect and prune the .gts cache directory.
+
+  info   sizes and ages of the index cache, cached results, and other files
+  clean  drop files no longer on disk from the index cache
+  gc     delete leftover temporary files, a dead daemon socket, and old results`,
+	}
+	cmd.AddCommand(newCacheInfoCmd(), newCacheCleanCmd(), newCacheGCCmd())
+	return cmd
+}
+
+// cacheTarget resolves the project root and index cache path of a cache
+// subcommand.
+func cacheTarget(args []string, cachePath string) (string, string, error) {
+	target := defaultTarget()
+	if len(args) == 1 {
+		target = args[0]
+	}
+	root, err := filepath.Abs(target)
+	if err != nil {
+		return "", "", err
+	}
+	if strings.TrimSpace(cachePath) == "" {
+		cachePath = projectConfig.CachePath(root)
+	}
+	return root, cachePath, nil
+}
+
+func newCacheInfoCmd() *cobra.Command {
+	var cachePath string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "info [path]",
+		Short: "Report the size, age, and schema version of cached data",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, indexPath, err := cacheTarget(args, cachePath)
+			if err != nil {
+				return err
+			}
+			info, err := cachedir.Inspect(root, indexPath)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return emitJSON(info)
+			}
+			printCacheInfo(info)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&cachePath, "cache", "", "index cache to inspect (default: .gts/index.json under path)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+func printCacheInfo(info *cachedir.Info) {
+	if !info.Exists && info.Index == nil {
+		fmt.Printf("cache: %s does not exist\n", info.Dir)
+		return
+	}
+	fmt.Printf("cache: %s total=%s\n", info.Dir, formatByteSize(info.TotalBytes))
+	if idx := info.Index; idx != nil {
+		fmt.Printf("index: %s size=%s age=%s", idx.Path, formatByteSize(idx.SizeBytes), cacheAge(idx.ModTime))
+		if idx.Error != "" {
+			fmt.Printf(" error=%q\n", idx.Error)
+		} else {
+			fmt.Printf(" schema=%s files=%d stale=%d\n", idx.SchemaVersion, idx.Files, idx.StaleFiles)
+		}
+		if idx.Error == "" && !idx.Current {
+			fmt.Printf("  schema %s is not current; the next build rebuilds it\n", idx.SchemaVersion)
+		}
+		if idx.StaleFiles > 0 {
+			fmt.Printf("  %d indexed files no longer exist; run gts cache clean\n", idx.StaleFiles)
+		}
+	} else {
+		fmt.Println("index: none")
+	}
+	results := info.Results
+	fmt.Printf("results: entries=%d size=%s", results.Entries, formatByteSize(results.SizeBytes))
+	if results.Oldest != nil && results.Newest != nil {
+		fmt.Printf(" oldest=%s newest=%s", cacheAge(*results.Oldest), cacheAge(*results.Newest))
+	}
+	fmt.Println()
+	if len(info.Temp) > 0 {
+		var size int64
+		for _, entry := range info.Temp {
+			size += entry.SizeBytes
+		}
+		fmt.Printf("temp: files=%d size=%s (run gts cache gc)\n", len(info.Temp), formatByteSize(size))
+	}
+	for _, entry := range info.Other {
+		rel, err := filepath.Rel(info.Dir, entry.Path)
+		if err != nil {
+			rel = entry.Path
+		}
+		fmt.Printf("other: %s size=%s age=%s\n", filepath.ToSlash(rel), formatByteSize(entry.SizeBytes), cacheAge(entry.ModTime))
+	}
+}
+
+func newCacheCleanCmd() *cobra.Command {
+	var cachePath string
+	var dryRun bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "clean [path]",
+		Short: "Drop files no longer on disk from the index cache",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, indexPath, err := cacheTarget(args, cachePath)
+			if err != nil {
+				return err
+			}
+			report, err := cachedir.Clean(indexPath, dryRun)
+			if err != nil {
+				return fmt.Errorf("clean %s: %w", indexPath, err)
+			}
+			if jsonOutput {
+				return emitJSON(report)
+			}
+			verb := "removed"
+			if dryRun {
+				verb = "would remove"
+			}
+			for _, path := range report.Removed {
+				fmt.Printf("%s %s\n", verb, path)
+			}
+			fmt.Printf("clean: %s %d stale entries from %s\n", verb, len(report.Removed), report.Index)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&cachePath, "cache", "", "index cache to clean (default: .gts/index.json under path)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "list stale entries without saving the index")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+func newCacheGCCmd() *cobra.Command {
+	var maxAge time.Duration
+	var dryRun bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "gc [path]",
+		Short: "Delete leftover temporary files, a dead daemon socket, and old cached results",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _, err := cacheTarget(args, "")
+			if err != nil {
+				return err
+			}
+			report, err := cachedir.GC(root, cachedir.GCOptions{MaxAge: maxAge, DryRun: dryRun})
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return emitJSON(report)
+			}
+			verb := "removed"
+			if dryRun {
+				verb = "would remove"
+			}
+			for _, entry := range report.Removed {
+				fmt.Printf("%s %s (%s)\n", verb, entry.Path, formatByteSize(entry.SizeBytes))
+			}
+			fmt.Printf("gc: %s %d files, %s\n", verb, len(report.Removed), formatByteSize(report.FreedBytes))
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&maxAge, "max-age", 7*24*time.Hour, "delete cached results stored longer ago than this (0 keeps them)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "list what would be deleted without deleting it")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+func cacheAge(t time.Time) time.Duration {
+	return time.Since(t).Truncate(time.Second)
+}
+
+// formatByteSize renders n with a binary unit, as in "1.5MB".
+func formatByteSize(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1fGB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
@@ -8,6 +8,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/internal/routes"
+	"github.com/odvcencio/gts-suite/internal/sqlusage"
+	"github.com/odvcencio/gts-suite/internal/walkfilter"
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
@@ -21,20 +25,72 @@ func newCallgraphCmd() *cobra.Command {
 	var countOnly bool
 	var dotOutput bool
 	var kind string
+	var entries []string
+	var routeSpecs []string
+	var tables []string
+	var aggregate string
+	var filter walkfilter.Options
+	var dynamic bool
 
 	cmd := &cobra.Command{
-		Use:     "calls <name|regex> [path]",
+		Use:     "calls <name|regex> [path] | --route <spec> [path] | --table <name> [path]",
 		Aliases: []string{"callgraph", "gtscallgraph"},
 		Short:   "Build call graph edges rooted at matching callable definitions",
-		Args:    cobra.RangeArgs(1, 2),
+		Long: `Build call graph edges rooted at matching callable definitions.
+
+Additional entry points can be given with repeatable --entry. With --aggregate package the
+walked graph is collapsed to package-level edges, showing which packages a
+feature touches.
+
+--route roots the graph at the handlers of the HTTP routes a request would
+hit (see gts routes), answering "what does GET /users/42 call". --table roots
+it at the functions querying a database table (see gts sql); with --reverse
+it lists everything a schema change could reach. With either flag the name
+argument is dropped and the only argument is the path.
+
+--exclude-tests, --only-project, and --exclude-package keep test helpers,
+vendored or generated code, and chosen packages out of the walk: edges into
+them are neither shown nor followed.
+
+--dynamic links method calls made through a value, such as s.Get(), to every
+other method of that name and parameter count, with resolution=interface.
+These are the implementations an interface call could dispatch to, so
+--reverse from a concrete method finds its polymorphic callers.
+
+Examples:
+  gts calls Serve --entry Shutdown --depth 3
+  gts calls 'Handle.*' --regex --aggregate package internal/
+  gts calls --route "GET /users/42" --depth 3
+  gts calls --table users --reverse
+  gts calls Get --reverse --dynamic internal/store/
+  gts calls Serve --exclude-tests --only-project --exclude-package 'internal/testutil/...'`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(routeSpecs) > 0 || len(tables) > 0 {
+				return cobra.MaximumNArgs(1)(cmd, args)
+			}
+			return cobra.RangeArgs(1, 2)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if depth <= 0 {
 				return fmt.Errorf("depth must be > 0")
 			}
+			switch strings.ToLower(strings.TrimSpace(aggregate)) {
+			case "", "package":
+			default:
+				return fmt.Errorf("unsupported --aggregate %q (expected package)", aggregate)
+			}
 
-			target := "."
-			if len(args) == 2 {
-				target = args[1]
+			names := entries
+			target := defaultTarget()
+			if len(routeSpecs) > 0 || len(tables) > 0 {
+				if len(args) == 1 {
+					target = args[0]
+				}
+			} else {
+				names = append([]string{args[0]}, entries...)
+				if len(args) == 2 {
+					target = args[1]
+				}
 			}
 
 			idx, err := loadOrBuild(cachePath, target, noCache)
@@ -42,14 +98,39 @@ func newCallgraphCmd() *cobra.Command {
 				return err
 			}
 
-			graph, err := xref.Build(idx)
+			graph, err := xref.BuildWithOptions(idx, xref.Options{Dynamic: dynamic})
 			if err != nil {
 				return err
 			}
 
-			roots, err := graph.FindDefinitions(args[0], regexMode)
-			if err != nil {
-				return err
+			var roots []xref.Definition
+			if len(names) > 0 {
+				roots, err = graph.FindDefinitionsAny(names, regexMode)
+				if err != nil {
+					return err
+				}
+			}
+			if len(routeSpecs) > 0 {
+				routeList, err := routes.Extract(idx, &graph)
+				if err != nil {
+					return err
+				}
+				handlers, err := routeRoots(routeList, routeSpecs)
+				if err != nil {
+					return err
+				}
+				roots = append(roots, handlers...)
+			}
+			if len(tables) > 0 {
+				usages, err := sqlusage.Extract(idx, &graph)
+				if err != nil {
+					return err
+				}
+				queriers, err := tableRoots(usages, tables)
+				if err != nil {
+					return err
+				}
+				roots = append(roots, queriers...)
 			}
 
 			if kind != "" {
@@ -77,7 +158,15 @@ func newCallgraphCmd() *cobra.Command {
 			for _, root := range roots {
 				rootIDs = append(rootIDs, root.ID)
 			}
-			walk := graph.Walk(rootIDs, depth, reverse)
+			keep, err := filter.Keep(idx)
+			if err != nil {
+				return err
+			}
+			walk := graph.WalkFiltered(rootIDs, depth, reverse, keep)
+
+			if aggregate != "" {
+				return printPackageAggregate(walk, jsonOutput, countOnly, dotOutput)
+			}
 
 			if dotOutput {
 				fmt.Println("digraph callgraph {")
@@ -92,16 +181,11 @@ func newCallgraphCmd() *cobra.Command {
 
 			if jsonOutput {
 				if countOnly {
-					return emitJSON(struct {
-						RootCount      int `json:"root_count"`
-						NodeCount      int `json:"node_count"`
-						EdgeCount      int `json:"edge_count"`
-						UnresolvedCall int `json:"unresolved_call_count"`
-					}{
-						RootCount:      len(walk.Roots),
-						NodeCount:      len(walk.Nodes),
-						EdgeCount:      len(walk.Edges),
-						UnresolvedCall: len(graph.Unresolved),
+					return emitJSON(report.CallgraphCountReport{
+						RootCount:           len(walk.Roots),
+						NodeCount:           len(walk.Nodes),
+						EdgeCount:           len(walk.Edges),
+						UnresolvedCallCount: len(graph.Unresolved),
 					})
 				}
 				return streamCallgraphJSON(&graph, walk, len(graph.Unresolved))
@@ -166,9 +250,70 @@ func newCallgraphCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of traversed edges")
 	cmd.Flags().BoolVar(&dotOutput, "dot", false, "emit DOT graph for Graphviz visualization")
 	cmd.Flags().StringVar(&kind, "kind", "", "filter root definitions by kind (function|method)")
+	cmd.Flags().StringArrayVar(&entries, "entry", nil, "additional entry point name or regex to root the graph at (repeatable)")
+	cmd.Flags().StringArrayVar(&tables, "table", nil, "root at the functions querying a database table (repeatable)")
+	cmd.Flags().StringArrayVar(&routeSpecs, "route", nil, "root at the handlers of an HTTP route, e.g. \"GET /users/42\" (repeatable)")
+	cmd.Flags().StringVar(&aggregate, "aggregate", "", "collapse the walked graph: package")
+	cmd.Flags().BoolVar(&dynamic, "dynamic", false, "add resolution=interface edges from method calls to other implementations of the method")
+	cmd.Flags().BoolVar(&filter.ExcludeTests, "exclude-tests", false, "skip definitions in test files")
+	cmd.Flags().BoolVar(&filter.OnlyProject, "only-project", false, "skip generated files and dependency directories such as vendor and node_modules")
+	cmd.Flags().StringArrayVar(&filter.ExcludePackages, "exclude-package", nil, "skip packages matching a glob, e.g. 'internal/testutil/...' (repeatable)")
 	return cmd
 }
 
+func printPackageAggregate(walk xref.Walk, jsonOutput, countOnly, dotOutput bool) error {
+	packages := walk.AggregatePackages()
+
+	if dotOutput {
+		fmt.Println("digraph packages {")
+		for _, pkg := range packages.Packages {
+			fmt.Printf("  %q;\n", pkg.Package)
+		}
+		for _, edge := range packages.Edges {
+			fmt.Printf("  %q -> %q [label=%q];\n", edge.From, edge.To, fmt.Sprintf("%d", edge.Calls))
+		}
+		fmt.Println("}")
+		return nil
+	}
+
+	if jsonOutput {
+		if countOnly {
+			return emitJSON(report.PackageCallgraphCountReport{
+				PackageCount: len(packages.Packages),
+				EdgeCount:    len(packages.Edges),
+			})
+		}
+		return emitJSON(report.PackageCallgraphReport{
+			Roots:    walk.Roots,
+			Depth:    walk.Depth,
+			Reverse:  walk.Reverse,
+			Packages: packages,
+		})
+	}
+
+	if countOnly {
+		fmt.Println(len(packages.Edges))
+		return nil
+	}
+
+	fmt.Printf(
+		"callgraph: roots=%d packages=%d package_edges=%d internal_calls=%d depth=%d reverse=%t\n",
+		len(walk.Roots),
+		len(packages.Packages),
+		len(packages.Edges),
+		packages.Internal,
+		walk.Depth,
+		walk.Reverse,
+	)
+	for _, pkg := range packages.Packages {
+		fmt.Printf("package: %s definitions=%d\n", pkg.Package, pkg.Definitions)
+	}
+	for _, edge := range packages.Edges {
+		fmt.Printf("%s -> %s calls=%d edges=%d\n", edge.From, edge.To, edge.Calls, edge.Edges)
+	}
+	return nil
+}
+
 func runCallgraph(args []string) error {
 	cmd := newCallgraphCmd()
 	cmd.SilenceUsage = true
@@ -178,10 +323,12 @@ func runCallgraph(args []string) error {
 }
 
 // streamCallgraphJSON writes callgraph JSON output, materializing one edge at a time
-// instead of building the full []MaterializedEdge slice.
+// instead of building the full []MaterializedEdge slice. The output is a
+// report.CallgraphReport.
 func streamCallgraphJSON(graph *xref.Graph, walk xref.Walk, unresolvedCount int) error {
 	w := os.Stdout
 	fmt.Fprintf(w, "{\n")
+	fmt.Fprintf(w, "  \"schema_version\": %d,\n", report.SchemaVersion)
 
 	// Roots
 	rootsData, _ := json.MarshalIndent(walk.Roots, "  ", "  ")
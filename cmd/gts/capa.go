@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/odvcencio/gts-suite/pkg/capa"
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
 func newCapaCmd() *cobra.Command {
@@ -24,7 +25,7 @@ func newCapaCmd() *cobra.Command {
 		Short:   "Detect capabilities from structural API/import patterns with MITRE ATT&CK mapping",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -68,10 +69,7 @@ func newCapaCmd() *cobra.Command {
 			}
 
 			if jsonOutput {
-				return emitJSON(struct {
-					Count   int          `json:"count"`
-					Matches []capa.Match `json:"matches,omitempty"`
-				}{
+				return emitJSON(report.CapaReport{
 					Count:   len(matches),
 					Matches: matches,
 				})
@@ -67,7 +67,7 @@ func newCheckCmd() *cobra.Command {
 		Short: "Run quality gates for CI -- exits non-zero on violations",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -22,18 +23,42 @@ func newChunkCmd() *cobra.Command {
 	var lang string
 	var countOnly bool
 	var format string
+	var since string
+	var writeManifest string
+	var manifest string
+	var watch bool
+	var poll bool
+	var interval time.Duration
+	var policy watchPolicy
+	var maxMemory string
 
 	cmd := &cobra.Command{
 		Use:     "chunk [path]",
 		Aliases: []string{"gtschunk"},
 		Short:   "Split code into AST-boundary chunks for RAG/indexing",
+		Long: `Split code into AST-boundary chunks for RAG/indexing.
+
+Every chunk carries a stable id derived from its file, kind, container, and
+symbol name, plus a content hash. With --since <manifest>, chunks are compared
+against an earlier run and only added, modified, and moved chunks are emitted,
+along with the ids of removed chunks, so vector stores only re-embed what
+changed. --write-manifest saves the full chunk set for the next run.
+
+--manifest <path> syncs against a manifest in one step: it prints add, update,
+and delete events as JSON lines and saves the new manifest. With --watch the
+sync repeats on every file change, keeping a live RAG index current.
+
+Examples:
+  gts chunk --write-manifest chunks.json .
+  gts chunk --since chunks.json --write-manifest chunks.json --json .
+  gts chunk --watch --manifest chunks.jsonl . | my-embedder`,
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if tokens <= 0 {
 				return fmt.Errorf("tokens must be > 0")
 			}
 
-			target := "."
+			target := defaultTarget()
 			filter := ""
 			if len(args) == 1 {
 				target = args[0]
@@ -42,29 +67,73 @@ func newChunkCmd() *cobra.Command {
 				}
 			}
 
-			idx, err := loadOrBuild(cachePath, target, noCache)
+			manifest = strings.TrimSpace(manifest)
+			if watch && manifest == "" {
+				return fmt.Errorf("--watch requires --manifest")
+			}
+			if manifest != "" && (since != "" || writeManifest != "" || countOnly || format != "" || jsonOutput) {
+				return fmt.Errorf("--manifest emits JSON line events and cannot be combined with --since, --write-manifest, --count, --format, or --json")
+			}
+			if watch && strings.TrimSpace(cachePath) != "" {
+				return fmt.Errorf("--watch cannot be used with --cache")
+			}
+			if watch && interval <= 0 {
+				return fmt.Errorf("interval must be > 0 in watch mode")
+			}
+			if err := policy.validate(); err != nil {
+				return err
+			}
+			memoryLimit, restore, err := applyMaxMemory(maxMemory)
 			if err != nil {
 				return err
 			}
-			idx = applyGeneratedFilter(cmd, idx)
+			defer restore()
 
-			if lang != "" {
-				filtered := idx.Files[:0]
-				for _, f := range idx.Files {
-					if strings.EqualFold(f.Language, lang) {
-						filtered = append(filtered, f)
-					}
-				}
-				idx.Files = filtered
+			prepare := func(idx *model.Index) *model.Index {
+				return filterLanguage(applyGeneratedFilter(cmd, idx), lang)
 			}
-
-			report, err := chunk.Build(idx, chunk.Options{
+			opts := chunk.Options{
 				TokenBudget: tokens,
 				FilterPath:  filter,
-			})
+			}
+			if watch {
+				return runChunkWatch(target, manifest, opts, prepare, interval, policy, poll)
+			}
+
+			idx, err := loadOrBuildWithin(cachePath, target, noCache, memoryLimit)
+			if err != nil {
+				return err
+			}
+			if manifest != "" {
+				syncer, err := newChunkSyncer(manifest, opts, prepare, os.Stdout)
+				if err != nil {
+					return err
+				}
+				_, err = syncer.Sync(idx)
+				return err
+			}
+			idx = prepare(idx)
+			if strings.TrimSpace(since) != "" {
+				previous, err := chunk.LoadManifest(since)
+				if err != nil {
+					return err
+				}
+				if previous == nil {
+					previous = []chunk.Chunk{}
+				}
+				opts.Previous = previous
+			}
+
+			report, err := chunk.Build(idx, opts)
 			if err != nil {
 				return err
 			}
+			if strings.TrimSpace(writeManifest) != "" {
+				if err := chunk.WriteManifest(writeManifest, report); err != nil {
+					return err
+				}
+			}
+			report = report.Changes()
 
 			if countOnly {
 				fmt.Println(report.ChunkCount)
@@ -80,12 +149,19 @@ func newChunkCmd() *cobra.Command {
 				return emitJSON(report)
 			}
 
-			fmt.Printf("chunks: %d budget=%d root=%s\n", report.ChunkCount, report.TokenBudget, report.Root)
+			if report.Incremental {
+				fmt.Printf("chunks: %d budget=%d root=%s unchanged=%d removed=%d\n", report.ChunkCount, report.TokenBudget, report.Root, report.Unchanged, len(report.Removed))
+			} else {
+				fmt.Printf("chunks: %d budget=%d root=%s\n", report.ChunkCount, report.TokenBudget, report.Root)
+			}
 			for _, item := range report.Chunks {
 				suffix := ""
 				if item.Truncated {
 					suffix = " truncated=true"
 				}
+				if item.Status != "" {
+					suffix += " status=" + item.Status
+				}
 				fmt.Printf(
 					"%s:%d:%d %s %s tokens=%d%s\n",
 					item.File,
@@ -97,6 +173,9 @@ func newChunkCmd() *cobra.Command {
 					suffix,
 				)
 			}
+			for _, id := range report.Removed {
+				fmt.Printf("removed %s\n", id)
+			}
 			return nil
 		},
 	}
@@ -108,12 +187,22 @@ func newChunkCmd() *cobra.Command {
 	cmd.Flags().StringVar(&lang, "lang", "", "filter by file language (e.g. go, python, typescript)")
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print only the count of chunks")
 	cmd.Flags().StringVar(&format, "format", "", "output format: embeddings (JSONL with metadata per chunk)")
+	cmd.Flags().StringVar(&since, "since", "", "previous chunk manifest; emit only added, modified, and moved chunks")
+	cmd.Flags().StringVar(&writeManifest, "write-manifest", "", "write the full chunk manifest (ids and hashes) to this path")
+	cmd.Flags().StringVar(&manifest, "manifest", "", "sync against this manifest: print add/update/delete events as JSON lines and save it")
+	cmd.Flags().BoolVar(&watch, "watch", false, "keep syncing --manifest on every file change")
+	cmd.Flags().BoolVar(&poll, "poll", false, "force polling watch mode instead of fsnotify (automatic on network filesystems)")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "debounce (fsnotify) or poll interval for watch mode")
+	cmd.Flags().StringVar(&maxMemory, "max-memory", "", "memory budget such as 3GB: caps the heap and parses fewer files at once when building the index")
+	addWatchPolicyFlags(cmd, &policy)
 	return cmd
 }
 
 type embeddingChunk struct {
-	Content  string          `json:"content"`
-	Metadata embeddingMeta   `json:"metadata"`
+	ID       string          `json:"id"`
+	Content  string          `json:"content,omitempty"`
+	Metadata *embeddingMeta  `json:"metadata,omitempty"`
+	Deleted  bool            `json:"deleted,omitempty"`
 }
 
 type embeddingMeta struct {
@@ -121,6 +210,8 @@ type embeddingMeta struct {
 	Language   string   `json:"language"`
 	Symbols    []string `json:"symbols"`
 	Complexity int      `json:"complexity,omitempty"`
+	Hash       string   `json:"hash"`
+	Status     string   `json:"status,omitempty"`
 }
 
 func emitEmbeddingsFormat(idx *model.Index, report chunk.Report) error {
@@ -158,18 +249,26 @@ func emitEmbeddingsFormat(idx *model.Index, report chunk.Report) error {
 		}
 
 		entry := embeddingChunk{
+			ID:      c.ID,
 			Content: c.Content,
-			Metadata: embeddingMeta{
+			Metadata: &embeddingMeta{
 				File:       c.File,
 				Language:   fileLang[c.File],
 				Symbols:    symbols,
 				Complexity: cyc,
+				Hash:       c.Hash,
+				Status:     c.Status,
 			},
 		}
 		if err := enc.Encode(entry); err != nil {
 			return err
 		}
 	}
+	for _, id := range report.Removed {
+		if err := enc.Encode(embeddingChunk{ID: id, Deleted: true}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/odvcencio/gts-suite/internal/chunk"
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// chunkSyncer keeps a chunk manifest in step with an index and writes the
+// add/update/delete events for every difference as JSON lines.
+type chunkSyncer struct {
+	manifestPath string
+	opts         chunk.Options
+	filter       func(*model.Index) *model.Index
+	out          *json.Encoder
+}
+
+func newChunkSyncer(manifestPath string, opts chunk.Options, filter func(*model.Index) *model.Index, out io.Writer) (*chunkSyncer, error) {
+	previous, err := chunk.LoadManifest(manifestPath)
+	switch {
+	case err == nil:
+	case os.IsNotExist(err):
+		previous = nil
+	default:
+		return nil, err
+	}
+	if previous == nil {
+		previous = []chunk.Chunk{}
+	}
+	opts.Previous = previous
+	return &chunkSyncer{
+		manifestPath: manifestPath,
+		opts:         opts,
+		filter:       filter,
+		out:          json.NewEncoder(out),
+	}, nil
+}
+
+// Sync chunks idx, emits events against the last manifest, and saves the
+// new manifest. It returns the number of events written.
+func (s *chunkSyncer) Sync(idx *model.Index) (int, error) {
+	report, err := chunk.Build(s.filter(idx), s.opts)
+	if err != nil {
+		return 0, err
+	}
+	events := chunk.Events(report)
+	for _, event := range events {
+		if err := s.out.Encode(event); err != nil {
+			return 0, err
+		}
+	}
+	if len(events) > 0 || len(s.opts.Previous) == 0 {
+		if err := chunk.WriteManifest(s.manifestPath, report); err != nil {
+			return 0, err
+		}
+	}
+	s.opts.Previous = report.Chunks
+	return len(events), nil
+}
+
+func runChunkWatch(target, manifestPath string, opts chunk.Options, filter func(*model.Index) *model.Index, interval time.Duration, policy watchPolicy, poll bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	builder, err := index.NewBuilderWithWorkspaceIgnores(target)
+	if err != nil {
+		return err
+	}
+	current, _, err := builder.BuildPathIncremental(ctx, target, nil)
+	if err != nil {
+		return err
+	}
+
+	syncer, err := newChunkSyncer(manifestPath, opts, filter, os.Stdout)
+	if err != nil {
+		return err
+	}
+	count, err := syncer.Sync(current)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "chunk watch: target=%s manifest=%s events=%d\n", target, manifestPath, count)
+
+	watchState := index.NewWatchState()
+	defer watchState.Release()
+	store := index.NewStore(current)
+
+	onChange := func(changedPaths []string) {
+		var current *model.Index
+		var err error
+		if len(changedPaths) > 0 {
+			current, _, err = store.ApplyWatchChanges(builder, changedPaths, watchState, index.WatchUpdateOptions{
+				SubfileIncremental: true,
+			})
+		} else {
+			current, err = store.Update(func(current *model.Index) (*model.Index, error) {
+				next, _, err := builder.BuildPathIncremental(ctx, target, current)
+				return next, err
+			})
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "chunk watch build error: %v\n", err)
+			return
+		}
+		count, err := syncer.Sync(current)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "chunk watch sync error: %v\n", err)
+			return
+		}
+		if count > 0 {
+			fmt.Fprintf(os.Stderr, "chunk watch: events=%d\n", count)
+		}
+	}
+
+	ignorePaths := map[string]bool{}
+	if absManifest, err := filepath.Abs(manifestPath); err == nil {
+		ignorePaths[filepath.Clean(absManifest)] = true
+		ignorePaths[filepath.Clean(absManifest)+".tmp"] = true
+	}
+
+	if !poll {
+		err := watchWithFSNotify(ctx, target, policy.withDefaults(interval), ignorePaths, builder.Ignore(), onChange)
+		if err == nil {
+			fmt.Fprintln(os.Stderr, "chunk watch: stopped")
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "watch backend fallback to polling: %v\n", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(os.Stderr, "chunk watch: stopped")
+			return nil
+		case <-ticker.C:
+			onChange(nil)
+		}
+	}
+}
+
+// filterLanguage returns idx restricted to files of one language without
+// modifying idx.
+func filterLanguage(idx *model.Index, lang string) *model.Index {
+	if strings.TrimSpace(lang) == "" {
+		return idx
+	}
+	filtered := *idx
+	filtered.Files = make([]model.FileSummary, 0, len(idx.Files))
+	for _, f := range idx.Files {
+		if strings.EqualFold(f.Language, lang) {
+			filtered.Files = append(filtered.Files, f)
+		}
+	}
+	return &filtered
+}
@@ -37,9 +37,24 @@ Command groups:
   search     Find symbols, references, and patterns
   graph      Call graph, dependency, and coverage analysis
   analyze    Quality, complexity, security, and governance
+  audit      Policy audits of risky calls
   transform  Code transformations and output generation
   mcp        MCP stdio server for AI agents (30+ tools)
   init       Project setup and CI workflow generation
+  hook       Git pre-commit/pre-push checks on staged changes
+  daemon     Background process that keeps a warm index for other commands
+  docgen     Generated per-package Markdown reference docs
+  routes     HTTP routes and the handlers serving them
+  sql        SQL queries and tables mapped to the functions issuing them
+  config-usage  Environment variables and config keys the code reads
+  concurrency  Goroutines, channels, mutexes, and WaitGroups per Go package
+  inspect    Tree-sitter syntax tree of a file, for writing queries
+  schema     JSON Schemas of the --json outputs
+  cache      Size, age, and pruning of the .gts cache directory
+  snapshot   Labeled index snapshots to diff releases against
+  hotspots   Definitions ranked by references, calling packages, and churn
+  fix        Organize imports and extract constants, as gtsls code actions do
+  doctor     Check grammars, the cache, watch limits, and the go toolchain
 
 Get started:
   gts index build .              Build a structural index
@@ -47,9 +62,16 @@ Get started:
   gts analyze report             Executive summary of all analyses
   gts mcp --root .               Start MCP server for AI agents`,
 		Version: version,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyProjectConfig(cmd); err != nil {
+				return err
+			}
+			return checkReadOnly(cmd)
+		},
 	}
 	root.PersistentFlags().Bool("include-generated", false, "include generated files in analysis output")
 	root.PersistentFlags().String("generator", "", "filter to a specific generator name (e.g. protobuf, mockgen, human)")
+	root.PersistentFlags().Bool("read-only", false, "refuse flags that change files, such as refactor --write and lint --fix (also GTS_READ_ONLY=1)")
 	root.PersistentFlags().String("federation", "", "directory containing .gtsindex files for multi-repo federated analysis")
 
 	root.AddCommand(
@@ -57,9 +79,24 @@ Get started:
 		newSearchGroup(),
 		newGraphGroup(),
 		newAnalyzeGroup(),
+		newAuditGroup(),
 		newTransformGroup(),
 		newMCPCmd(),
 		newInitCmd(),
+		newHookCmd(),
+		newDaemonCmd(),
+		newDocgenCmd(),
+		newRoutesCmd(),
+		newSQLCmd(),
+		newConfigUsageCmd(),
+		newConcurrencyCmd(),
+		newInspectCmd(),
+		newSchemaCmd(),
+		newCacheCmd(),
+		newSnapshotCmd(),
+		newHotspotsCmd(),
+		newFixCmd(),
+		newDoctorCmd(),
 	)
 	return root
 }
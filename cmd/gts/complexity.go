@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/odvcencio/gts-suite/pkg/complexity"
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
@@ -26,7 +27,7 @@ func newComplexityCmd() *cobra.Command {
 		Short:   "Analyze function complexity metrics across the codebase",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -50,14 +51,14 @@ func newComplexityCmd() *cobra.Command {
 				Top:           top,
 			}
 
-			report, err := complexity.Analyze(idx, idx.Root, opts)
+			result, err := complexity.Analyze(idx, idx.Root, opts)
 			if err != nil {
 				return err
 			}
 
 			graph, err := xref.Build(idx)
 			if err == nil {
-				complexity.EnrichWithXref(report, graph)
+				complexity.EnrichWithXref(result, graph)
 			}
 
 			if kind != "" {
@@ -70,33 +71,29 @@ func newComplexityCmd() *cobra.Command {
 				default:
 					return fmt.Errorf("unsupported --kind %q (expected function|method)", kind)
 				}
-				filtered := report.Functions[:0]
-				for _, fn := range report.Functions {
+				filtered := result.Functions[:0]
+				for _, fn := range result.Functions {
 					if strings.Contains(fn.Kind, prefix) {
 						filtered = append(filtered, fn)
 					}
 				}
-				report.Functions = filtered
-				report.Summary.Count = len(filtered)
+				result.Functions = filtered
+				result.Summary.Count = len(filtered)
 			}
 
 			if jsonOutput {
 				if countOnly {
-					return emitJSON(struct {
-						Count int `json:"count"`
-					}{
-						Count: report.Summary.Count,
-					})
+					return emitJSON(report.CountReport{Count: result.Summary.Count})
 				}
-				return emitJSON(report)
+				return emitJSON(result)
 			}
 
 			if countOnly {
-				fmt.Println(report.Summary.Count)
+				fmt.Println(result.Summary.Count)
 				return nil
 			}
 
-			for _, fn := range report.Functions {
+			for _, fn := range result.Functions {
 				label := symbolLabel(fn.Name, "")
 				fmt.Printf(
 					"%s:%d:%d %s %s cyc=%d cog=%d lines=%d nesting=%d params=%d fan_in=%d fan_out=%d\n",
@@ -117,15 +114,15 @@ func newComplexityCmd() *cobra.Command {
 
 			fmt.Printf(
 				"complexity: count=%d avg_cyc=%.1f max_cyc=%d p90_cyc=%d avg_cog=%.1f max_cog=%d avg_lines=%.1f max_lines=%d avg_nesting=%.1f\n",
-				report.Summary.Count,
-				report.Summary.AvgCyclomatic,
-				report.Summary.MaxCyclomatic,
-				report.Summary.P90Cyclomatic,
-				report.Summary.AvgCognitive,
-				report.Summary.MaxCognitive,
-				report.Summary.AvgLines,
-				report.Summary.MaxLines,
-				report.Summary.AvgMaxNesting,
+				result.Summary.Count,
+				result.Summary.AvgCyclomatic,
+				result.Summary.MaxCyclomatic,
+				result.Summary.P90Cyclomatic,
+				result.Summary.AvgCognitive,
+				result.Summary.MaxCognitive,
+				result.Summary.AvgLines,
+				result.Summary.MaxLines,
+				result.Summary.AvgMaxNesting,
 			)
 			return nil
 		},
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/concurrency"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
+)
+
+func newConcurrencyCmd() *cobra.Command {
+	var cachePath string
+	var noCache bool
+	var kinds []string
+	var packageGlob string
+	var jsonOutput bool
+	var countOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "concurrency [path]",
+		Short: "Inventory goroutines, channels, mutexes, and WaitGroups per Go package",
+		Long: `Inventory goroutines, channels, mutexes, and WaitGroups per Go package.
+
+Lists go statements, make(chan ...) calls, channel sends, and sync.Mutex,
+sync.RWMutex, and sync.WaitGroup declarations with their locations and the
+function or type containing them, grouped by package.
+
+The same primitives can be forbidden with lint rules, e.g.
+  gts analyze lint --rule "no go statement in package api/handlers"
+
+Examples:
+  gts concurrency
+  gts concurrency --kind go --kind chan_send
+  gts concurrency --package "internal/**" --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			wanted := map[string]bool{}
+			for _, raw := range kinds {
+				kind, ok := concurrency.ParseKind(raw)
+				if !ok {
+					return fmt.Errorf("unsupported --kind %q (expected %s)", raw, strings.Join(concurrency.Kinds, "|"))
+				}
+				wanted[kind] = true
+			}
+			target := defaultTarget()
+			if len(args) == 1 {
+				target = args[0]
+			}
+
+			idx, err := loadOrBuild(cachePath, target, noCache)
+			if err != nil {
+				return err
+			}
+			sites, err := concurrency.Extract(idx)
+			if err != nil {
+				return err
+			}
+			filtered := sites[:0]
+			for _, site := range sites {
+				if len(wanted) > 0 && !wanted[site.Kind] {
+					continue
+				}
+				if packageGlob != "" && !model.MatchGlob(packageGlob, site.Package) {
+					continue
+				}
+				filtered = append(filtered, site)
+			}
+			summaries := concurrency.Summarize(filtered)
+
+			if jsonOutput {
+				if countOnly {
+					return emitJSON(report.CountReport{Count: len(filtered)})
+				}
+				return emitJSON(report.ConcurrencyReport{Count: len(filtered), Packages: summaries})
+			}
+			if countOnly {
+				fmt.Println(len(filtered))
+				return nil
+			}
+
+			fmt.Printf("concurrency: packages=%d sites=%d\n", len(summaries), len(filtered))
+			for _, summary := range summaries {
+				counts := make([]string, 0, len(concurrency.Kinds))
+				for _, kind := range concurrency.Kinds {
+					if summary.Counts[kind] > 0 {
+						counts = append(counts, fmt.Sprintf("%s=%d", kind, summary.Counts[kind]))
+					}
+				}
+				fmt.Printf("%s %s\n", summary.Package, strings.Join(counts, " "))
+				for _, site := range summary.Sites {
+					line := fmt.Sprintf("  %s:%d %s %s", site.File, site.Line, site.Kind, site.Detail)
+					if site.Symbol != "" {
+						line += " in " + site.Symbol
+					}
+					fmt.Println(line)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
+	cmd.Flags().StringArrayVar(&kinds, "kind", nil, "only sites of this kind: go, chan_make, chan_send, mutex, waitgroup (repeatable)")
+	cmd.Flags().StringVar(&packageGlob, "package", "", "only packages matching this glob, e.g. internal/** or handlers")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of sites")
+	return cmd
+}
+
+func runConcurrency(args []string) error {
+	cmd := newConcurrencyCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/configusage"
+	"github.com/odvcencio/gts-suite/pkg/report"
+)
+
+func newConfigUsageCmd() *cobra.Command {
+	var cachePath string
+	var noCache bool
+	var kind string
+	var docPaths []string
+	var jsonOutput bool
+	var countOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "config-usage [path]",
+		Short: "Inventory the environment variables and config keys the code reads",
+		Long: `Inventory the environment variables and config keys the code reads.
+
+Finds reads through os.Getenv, os.LookupEnv, viper, and env struct tags in Go;
+process.env and import.meta.env in JavaScript and TypeScript; os.environ and
+os.getenv in Python; System.getenv, getProperty, and @Value in Java; and
+std::env::var and env! in Rust. Each key is listed with its defaults and every
+location reading it.
+
+--docs checks the keys against deployment docs such as .env.example or a
+README: keys no doc mentions are undocumented, and NAME=value lines for
+variables nothing reads are unused. The command exits 1 when either is found.
+
+Examples:
+  gts config-usage
+  gts config-usage --kind env --json
+  gts config-usage --docs .env.example --docs docs/deploy.md`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch kind {
+			case "", configusage.KindEnv, configusage.KindConfig:
+			default:
+				return fmt.Errorf("unsupported --kind %q (expected env|config)", kind)
+			}
+			target := defaultTarget()
+			if len(args) == 1 {
+				target = args[0]
+			}
+
+			idx, err := loadOrBuild(cachePath, target, noCache)
+			if err != nil {
+				return err
+			}
+			keys, err := configusage.Build(idx)
+			if err != nil {
+				return err
+			}
+			if kind != "" {
+				filtered := keys[:0]
+				for _, key := range keys {
+					if key.Kind == kind {
+						filtered = append(filtered, key)
+					}
+				}
+				keys = filtered
+			}
+
+			if len(docPaths) > 0 {
+				docs := make([]configusage.Doc, 0, len(docPaths))
+				for _, path := range docPaths {
+					data, err := os.ReadFile(path)
+					if err != nil {
+						return err
+					}
+					docs = append(docs, configusage.Doc{Path: path, Content: string(data)})
+				}
+				return reportConfigDrift(keys, configusage.CompareDocs(keys, docs), jsonOutput, countOnly)
+			}
+
+			if jsonOutput {
+				if countOnly {
+					return emitJSON(report.CountReport{Count: len(keys)})
+				}
+				return emitJSON(report.ConfigUsageReport{Count: len(keys), Keys: keys})
+			}
+			if countOnly {
+				fmt.Println(len(keys))
+				return nil
+			}
+
+			reads := 0
+			for _, key := range keys {
+				reads += len(key.Reads)
+			}
+			fmt.Printf("config-usage: keys=%d reads=%d\n", len(keys), reads)
+			for _, key := range keys {
+				line := fmt.Sprintf("%s %s reads=%d", key.Kind, key.Name, len(key.Reads))
+				if len(key.Defaults) > 0 {
+					line += fmt.Sprintf(" default=%s", strings.Join(key.Defaults, ","))
+				}
+				fmt.Println(line)
+				for _, read := range key.Reads {
+					fmt.Printf("  %s:%d %s\n", read.File, read.Line, read.Source)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
+	cmd.Flags().StringVar(&kind, "kind", "", "only keys of this kind: env or config")
+	cmd.Flags().StringArrayVar(&docPaths, "docs", nil, "check keys against a deployment doc, e.g. .env.example (repeatable)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of keys (or drifted keys with --docs)")
+	return cmd
+}
+
+func runConfigUsage(args []string) error {
+	cmd := newConfigUsageCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+func reportConfigDrift(keys []configusage.Key, drift configusage.Drift, jsonOutput, countOnly bool) error {
+	drifted := len(drift.Undocumented) + len(drift.Unused)
+	switch {
+	case jsonOutput && countOnly:
+		if err := emitJSON(report.CountReport{Count: drifted}); err != nil {
+			return err
+		}
+	case jsonOutput:
+		if err := emitJSON(drift); err != nil {
+			return err
+		}
+	case countOnly:
+		fmt.Println(drifted)
+	default:
+		firstRead := map[string]configusage.Read{}
+		for _, key := range keys {
+			if _, ok := firstRead[key.Name]; !ok {
+				firstRead[key.Name] = key.Reads[0]
+			}
+		}
+		fmt.Printf("config-usage: keys=%d undocumented=%d unused=%d\n", len(keys), len(drift.Undocumented), len(drift.Unused))
+		for _, name := range drift.Undocumented {
+			read := firstRead[name]
+			fmt.Printf("undocumented: %s (%s:%d)\n", name, read.File, read.Line)
+		}
+		for _, name := range drift.Unused {
+			fmt.Printf("unused: %s\n", name)
+		}
+	}
+	if drifted > 0 {
+		return exitCodeError{code: 1, err: fmt.Errorf("config keys and docs are out of sync")}
+	}
+	return nil
+}
@@ -20,6 +20,8 @@ func newContextCmd() *cobra.Command {
 	var tokens int
 	var semantic bool
 	var semanticDepth int
+	var callers int
+	var compact bool
 	var jsonOutput bool
 	var concept string
 
@@ -76,6 +78,8 @@ func newContextCmd() *cobra.Command {
 				TokenBudget:   tokens,
 				Semantic:      semantic,
 				SemanticDepth: semanticDepth,
+				Callers:       callers,
+				Compact:       compact,
 			})
 			if err != nil {
 				return err
@@ -100,6 +104,13 @@ func newContextCmd() *cobra.Command {
 			}
 			fmt.Printf("snippet [%d:%d]:\n", report.SnippetStart, report.SnippetEnd)
 			fmt.Print(report.Snippet)
+			if len(report.Callers) > 0 {
+				fmt.Println("callers:")
+				for _, caller := range report.Callers {
+					fmt.Printf("  %s %s %s calls=%d [%d:%d]:\n", caller.File, caller.Kind, symbolLabel(caller.Name, caller.Signature), caller.Calls, caller.SnippetStart, caller.SnippetEnd)
+					fmt.Print(caller.Snippet)
+				}
+			}
 			if len(report.Related) > 0 {
 				fmt.Println("related:")
 				for _, symbol := range report.Related {
@@ -120,6 +131,8 @@ func newContextCmd() *cobra.Command {
 	cmd.Flags().IntVar(&tokens, "tokens", 800, "token budget")
 	cmd.Flags().BoolVar(&semantic, "semantic", false, "pack semantic dependency context when possible")
 	cmd.Flags().IntVar(&semanticDepth, "semantic-depth", 1, "dependency traversal depth in semantic mode")
+	cmd.Flags().IntVar(&callers, "callers", 0, "include up to N caller snippets of the focus symbol")
+	cmd.Flags().BoolVar(&compact, "compact", false, "strip comments, fold imports, and fall back to shorter signatures to fit more symbols")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	cmd.Flags().StringVar(&concept, "concept", "", "search concept query: find symbols matching this term and pack related context")
 	return cmd
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// daemonSocketName is the unix socket, relative to the indexed root, on which
+// gts daemon serves its index. loadOrBuild looks for it before reading or
+// building an index.
+const daemonSocketName = ".gts/daemon.sock"
+
+// daemonIndexTimeout bounds fetching the warm index, so a wedged daemon
+// makes loadOrBuild fall back to a local build instead of hanging. It is
+// longer than the status timeout because the whole index is transferred.
+const daemonIndexTimeout = 10 * time.Second
+
+type daemonStatus struct {
+	Root        string    `json:"root"`
+	PID         int       `json:"pid"`
+	Started     time.Time `json:"started"`
+	LastBuild   time.Time `json:"last_build"`
+	Builds      int       `json:"builds"`
+	Files       int       `json:"files"`
+	Symbols     int       `json:"symbols"`
+	ParseErrors int       `json:"parse_errors"`
+	// CallEdges and UnresolvedCalls describe the call graph, which the
+	// daemon keeps current alongside the index.
+	CallEdges       int    `json:"call_edges"`
+	UnresolvedCalls int    `json:"unresolved_calls"`
+	LastError       string `json:"last_error,omitempty"`
+}
+
+func newDaemonCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Keep a warm index in a background process",
+		Long: `Keep a warm index in a background process.
+
+"gts daemon start" indexes a directory, keeps the index up to date as files
+change, and serves it on a unix socket at .gts/daemon.sock. While it runs,
+every command that would otherwise load .gts/index.json or rebuild the index
+for that directory fetches the daemon's index instead. Commands fall back to
+the cache or a fresh build when the daemon is not running; --no-cache or
+GTS_NO_DAEMON=1 bypasses it.
+
+Output from the background process goes to .gts/daemon.log.`,
+	}
+	cmd.AddCommand(newDaemonStartCmd(), newDaemonStopCmd(), newDaemonStatusCmd(), newDaemonRunCmd())
+	return cmd
+}
+
+func newDaemonStartCmd() *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "start [path]",
+		Short: "Start the index daemon in the background",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := daemonRoot(args)
+			if err != nil {
+				return err
+			}
+			if status, err := fetchDaemonStatus(root); err == nil {
+				return fmt.Errorf("daemon already running for %s (pid %d)", status.Root, status.PID)
+			}
+
+			executable, err := os.Executable()
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Join(root, ".gts"), 0o755); err != nil {
+				return err
+			}
+			logPath := filepath.Join(root, ".gts", "daemon.log")
+			logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return err
+			}
+			defer logFile.Close()
+
+			child := exec.Command(executable, "daemon", "run", root)
+			child.Stdout = logFile
+			child.Stderr = logFile
+			detachDaemon(child)
+			if err := child.Start(); err != nil {
+				return err
+			}
+			exited := make(chan error, 1)
+			go func() { exited <- child.Wait() }()
+
+			// The socket only appears once the initial build has finished.
+			deadline := time.Now().Add(timeout)
+			for {
+				if status, err := fetchDaemonStatus(root); err == nil {
+					fmt.Printf("daemon: started pid=%d root=%s files=%d symbols=%d\n", status.PID, status.Root, status.Files, status.Symbols)
+					return nil
+				}
+				select {
+				case err := <-exited:
+					return fmt.Errorf("daemon exited during startup (%v); see %s", err, logPath)
+				case <-time.After(100 * time.Millisecond):
+				}
+				if time.Now().After(deadline) {
+					return fmt.Errorf("daemon did not become ready within %s; see %s", timeout, logPath)
+				}
+			}
+		},
+	}
+	cmd.Flags().DurationVar(&timeout, "timeout", 2*time.Minute, "how long to wait for the initial build")
+	return cmd
+}
+
+func newDaemonStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop [path]",
+		Short: "Stop the index daemon",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := daemonRoot(args)
+			if err != nil {
+				return err
+			}
+			status, err := fetchDaemonStatus(root)
+			if err != nil {
+				fmt.Println("daemon: not running")
+				return nil
+			}
+			resp, err := daemonClient(root).Post("http://gts/stop", "text/plain", nil)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+
+			socket := filepath.Join(root, filepath.FromSlash(daemonSocketName))
+			for i := 0; i < 50; i++ {
+				if _, err := os.Stat(socket); os.IsNotExist(err) {
+					break
+				}
+				time.Sleep(100 * time.Millisecond)
+			}
+			fmt.Printf("daemon: stopped pid=%d\n", status.PID)
+			return nil
+		},
+	}
+}
+
+func newDaemonStatusCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "status [path]",
+		Short: "Show whether the index daemon is running",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := daemonRoot(args)
+			if err != nil {
+				return err
+			}
+			status, err := fetchDaemonStatus(root)
+			if err != nil {
+				return exitCodeError{code: 3, err: errors.New("daemon not running")}
+			}
+			if jsonOutput {
+				return emitJSON(status)
+			}
+			fmt.Printf("daemon: running pid=%d root=%s\n", status.PID, status.Root)
+			fmt.Printf("uptime: %s builds=%d last=%s\n", time.Since(status.Started).Round(time.Second), status.Builds, status.LastBuild.Format(time.RFC3339))
+			fmt.Printf("index: files=%d symbols=%d parse_errors=%d\n", status.Files, status.Symbols, status.ParseErrors)
+			fmt.Printf("calls: edges=%d unresolved=%d\n", status.CallEdges, status.UnresolvedCalls)
+			if status.LastError != "" {
+				fmt.Printf("last error: %s\n", status.LastError)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+func newDaemonRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    "run [path]",
+		Short:  "Run the index daemon in the foreground",
+		Hidden: true,
+		Args:   cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := daemonRoot(args)
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return runDaemon(ctx, root)
+		},
+	}
+}
+
+func daemonRoot(args []string) (string, error) {
+	target := defaultTarget()
+	if len(args) == 1 {
+		target = args[0]
+	}
+	return filepath.Abs(target)
+}
+
+// indexDaemon holds the warm index served by runDaemon. calls is synced with
+// each new index, so only the files that changed are re-resolved.
+type indexDaemon struct {
+	store   *index.Store
+	mu      sync.Mutex
+	status  daemonStatus
+	calls   *xref.Incremental
+	metrics *watchMetrics
+}
+
+func (d *indexDaemon) update(idx *model.Index, duration time.Duration, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status.LastError = ""
+	if err != nil {
+		d.status.LastError = err.Error()
+	} else {
+		d.status.Builds++
+		d.status.LastBuild = time.Now()
+		d.status.Files = idx.FileCount()
+		d.status.Symbols = idx.SymbolCount()
+		d.status.ParseErrors = len(idx.Errors)
+		d.updateCalls(idx)
+	}
+	d.metrics.Observe(duration, idx, err)
+}
+
+// updateCalls brings the call graph up to date with idx. d.mu must be held.
+func (d *indexDaemon) updateCalls(idx *model.Index) {
+	if d.calls == nil {
+		calls, err := xref.NewIncremental(idx)
+		if err != nil {
+			return
+		}
+		d.calls = calls
+	} else {
+		d.calls.Sync(idx)
+	}
+	graph := d.calls.Graph()
+	d.status.CallEdges = len(graph.Edges)
+	d.status.UnresolvedCalls = len(graph.Unresolved)
+}
+
+func (d *indexDaemon) snapshot() (*model.Index, daemonStatus) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.store.Snapshot(), d.status
+}
+
+// runDaemon builds the index for root, then serves it on the daemon socket
+// and rebuilds it incrementally as files change until ctx is done or a client
+// asks it to stop.
+func runDaemon(ctx context.Context, root string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	builder, err := index.NewBuilderWithWorkspaceIgnores(root)
+	if err != nil {
+		return err
+	}
+	daemon := &indexDaemon{
+		store:   index.NewStore(nil),
+		status:  daemonStatus{Root: root, PID: os.Getpid(), Started: time.Now()},
+		metrics: newWatchMetrics(),
+	}
+	rebuild := func() {
+		start := time.Now()
+		next, err := daemon.store.Update(func(current *model.Index) (*model.Index, error) {
+			next, _, err := builder.BuildPathIncrementalWithOptions(ctx, root, current, index.BuildOptions{})
+			return next, err
+		})
+		if err != nil && ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "daemon build error: %v\n", err)
+		}
+		daemon.update(next, time.Since(start), err)
+	}
+	rebuild()
+	if current, _ := daemon.snapshot(); current == nil {
+		return fmt.Errorf("initial build of %s failed", root)
+	}
+
+	socket := filepath.Join(root, filepath.FromSlash(daemonSocketName))
+	if err := os.MkdirAll(filepath.Dir(socket), 0o755); err != nil {
+		return err
+	}
+	_ = os.Remove(socket)
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", socket, err)
+	}
+	defer os.Remove(socket)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index", func(w http.ResponseWriter, r *http.Request) {
+		current, _ := daemon.snapshot()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(current)
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		_, status := daemon.snapshot()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(status)
+	})
+	mux.HandleFunc("/stop", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+		fmt.Fprintln(w, "stopping")
+		cancel()
+	})
+	mux.Handle("/metrics", daemon.metrics)
+	mux.Handle("/healthz", daemon.metrics)
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), time.Second)
+		defer cancelShutdown()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "daemon server error: %v\n", err)
+			cancel()
+		}
+	}()
+	fmt.Fprintf(os.Stderr, "daemon: serving %s on %s\n", root, socket)
+
+	ignorePaths := map[string]bool{filepath.Clean(socket): true}
+	onChange := func([]string) { rebuild() }
+	if err := watchWithFSNotify(ctx, root, watchPolicy{Debounce: 250 * time.Millisecond}, ignorePaths, builder.Ignore(), onChange); err != nil {
+		fmt.Fprintf(os.Stderr, "daemon watch fallback to polling: %v\n", err)
+		ticker := time.NewTicker(2 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				fmt.Fprintln(os.Stderr, "daemon: stopped")
+				return nil
+			case <-ticker.C:
+				rebuild()
+			}
+		}
+	}
+	fmt.Fprintln(os.Stderr, "daemon: stopped")
+	return nil
+}
+
+// daemonClient returns an HTTP client that talks to the daemon for root over
+// its unix socket; request URLs only need a path.
+func daemonClient(root string) *http.Client {
+	socket := filepath.Join(root, filepath.FromSlash(daemonSocketName))
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+}
+
+func fetchDaemonStatus(root string) (daemonStatus, error) {
+	var status daemonStatus
+	client := daemonClient(root)
+	client.Timeout = 2 * time.Second
+	resp, err := client.Get("http://gts/status")
+	if err != nil {
+		return status, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return status, fmt.Errorf("daemon status: %s", resp.Status)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&status)
+	return status, err
+}
+
+// loadDaemonIndex fetches the warm index for target from a running daemon.
+// It reports false, without an error, when no daemon serves target or the
+// daemon fails to answer within daemonIndexTimeout; callers then build the
+// index themselves.
+func loadDaemonIndex(target string) (*model.Index, bool) {
+	if v := strings.TrimSpace(os.Getenv("GTS_NO_DAEMON")); v != "" && v != "0" {
+		return nil, false
+	}
+	root, err := filepath.Abs(target)
+	if err != nil {
+		return nil, false
+	}
+	if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(daemonSocketName))); err != nil {
+		return nil, false
+	}
+	client := daemonClient(root)
+	client.Timeout = daemonIndexTimeout
+	resp, err := client.Get("http://gts/index")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "index: gts daemon unavailable (%v), building locally\n", err)
+		return nil, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "index: gts daemon returned %s, building locally\n", resp.Status)
+		return nil, false
+	}
+	var idx model.Index
+	if err := json.NewDecoder(resp.Body).Decode(&idx); err != nil {
+		fmt.Fprintf(os.Stderr, "index: reading gts daemon index: %v, building locally\n", err)
+		return nil, false
+	}
+	return &idx, true
+}
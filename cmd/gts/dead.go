@@ -3,12 +3,17 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/internal/deadroots"
+	"github.com/odvcencio/gts-suite/pkg/annotate"
 	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/refactor"
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
@@ -18,9 +23,15 @@ func newDeadCmd() *cobra.Command {
 	var kind string
 	var includeEntrypoints bool
 	var includeTests bool
+	var unexportedOnly bool
 	var jsonOutput bool
+	var format string
 	var countOnly bool
 	var limit int
+	var writeChanges bool
+	var resultCache bool
+	var noDynamicRoots bool
+	var rootDecorators []string
 
 	cmd := &cobra.Command{
 		Use:     "dead [path...]",
@@ -30,31 +41,62 @@ func newDeadCmd() *cobra.Command {
 
 Multiple paths can be provided to build the cross-reference graph across
 packages, reducing false positives for exported symbols called from other
-packages.
+packages. A React component counts as called where JSX renders it, and a
+Vue component where a template does; --kind component or --kind hook lists
+only React and Vue components or React hooks.
+
+Python definitions reached dynamically are not reported: names given to
+getattr (a "prefix" + x or f-string name spares every definition with the
+prefix), exported by __all__, or passed to a registration call such as
+Django's path() or admin.site.register(), and functions whose decorator
+registers them with a framework, such as @app.route, @app.task, or
+@receiver. --root-decorator adds a project's own decorator patterns, which
+.gts/config.yaml can set for the project under commands: graph dead;
+--no-dynamic-roots reports them all.
 
 Examples:
   gts dead internal/service/
-  gts dead internal/service/ internal/api/    # cross-package analysis`,
+  gts dead internal/service/ internal/api/    # cross-package analysis
+  gts dead --unexported-only --format github  # inline PR annotations
+  gts dead --unexported-only --write          # delete what is reported
+
+JSON output includes, for each match, the deletion range that removes it
+together with its doc comment and the blank lines after it. --write applies
+those deletions in place; matches that share lines with other declarations
+get no range and are left alone. Review the result: imports used only by
+deleted code are not removed.`,
 		Args:    cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			mode := strings.ToLower(strings.TrimSpace(kind))
 			switch mode {
-			case "callable", "function", "method":
+			case "callable", "function", "method", "component", "hook":
 			default:
-				return fmt.Errorf("unsupported --kind %q (expected callable|function|method)", kind)
+				return fmt.Errorf("unsupported --kind %q (expected callable|function|method|component|hook)", kind)
 			}
 
 			targets := args
 			if len(targets) == 0 {
-				targets = []string{"."}
+				targets = []string{defaultTarget()}
+			}
+
+			if writeChanges && countOnly {
+				return fmt.Errorf("--write cannot be combined with --count")
+			}
+			if writeChanges && format != "text" && format != "json" {
+				return fmt.Errorf("--write cannot be combined with --format %s", format)
 			}
 
 			var idx *model.Index
+			// Paths are relative to their own target's root.
+			fileRoots := map[string]string{}
 			for i, target := range targets {
 				built, err := loadOrBuild(cachePath, target, noCache)
 				if err != nil {
 					return err
 				}
+				for _, file := range built.Files {
+					fileRoots[file.Path] = built.Root
+				}
 				if i == 0 {
 					idx = built
 				} else {
@@ -62,41 +104,72 @@ Examples:
 				}
 			}
 
-			graph, err := xref.Build(idx)
-			if err != nil {
-				return err
-			}
-
-			matches := make([]deadMatch, 0, 64)
-			scanned := 0
-			for _, definition := range graph.Definitions {
-				if !deadKindAllowed(definition, mode) {
-					continue
-				}
-				if !includeEntrypoints && isEntrypointDefinition(definition) {
-					continue
+			params := struct {
+				Kind               string   `json:"kind"`
+				IncludeEntrypoints bool     `json:"include_entrypoints"`
+				IncludeTests       bool     `json:"include_tests"`
+				UnexportedOnly     bool     `json:"unexported_only"`
+				DynamicRoots       bool     `json:"dynamic_roots"`
+				RootDecorators     []string `json:"root_decorators,omitempty"`
+			}{mode, includeEntrypoints, includeTests, unexportedOnly, !noDynamicRoots, rootDecorators}
+			analysis, err := cachedResult(resultCache, idx, "dead", params, func() (deadAnalysis, error) {
+				graph, err := xref.Build(idx)
+				if err != nil {
+					return deadAnalysis{}, err
 				}
-				if !includeTests && isTestSourceFile(definition.File) {
-					continue
+				var roots *deadroots.Roots
+				if !noDynamicRoots {
+					decorators := append(append([]string(nil), deadroots.DefaultDecorators...), rootDecorators...)
+					if roots, err = deadroots.Find(idx, deadroots.Options{Decorators: decorators}); err != nil {
+						return deadAnalysis{}, err
+					}
 				}
 
-				scanned++
-				incoming := graph.IncomingCount(definition.ID)
-				if incoming > 0 {
-					continue
+				analysis := deadAnalysis{Matches: make([]deadMatch, 0, 64)}
+				topLevel := graph.TopLevelCallNames()
+				for _, definition := range graph.Definitions {
+					if !deadKindAllowed(definition, mode) {
+						continue
+					}
+					if !includeEntrypoints && isEntrypointDefinition(definition, topLevel) {
+						continue
+					}
+					if !includeTests && isTestSourceFile(definition.File) {
+						continue
+					}
+					if unexportedOnly && definition.Exported {
+						continue
+					}
+
+					analysis.Scanned++
+					incoming := graph.IncomingCount(definition.ID)
+					if incoming > 0 {
+						continue
+					}
+					if _, ok := roots.Reason(definition.File, definition.Name, definition.StartLine); ok {
+						analysis.DynamicRoots++
+						continue
+					}
+					analysis.Matches = append(analysis.Matches, deadMatch{
+						File:      definition.File,
+						Package:   definition.Package,
+						Kind:      definition.Kind,
+						Name:      definition.Name,
+						Signature: definition.Signature,
+						StartLine: definition.StartLine,
+						EndLine:   definition.EndLine,
+						Exported:  definition.Exported,
+						Incoming:  incoming,
+						Outgoing:  graph.OutgoingCount(definition.ID),
+					})
 				}
-				matches = append(matches, deadMatch{
-					File:      definition.File,
-					Package:   definition.Package,
-					Kind:      definition.Kind,
-					Name:      definition.Name,
-					Signature: definition.Signature,
-					StartLine: definition.StartLine,
-					EndLine:   definition.EndLine,
-					Incoming:  incoming,
-					Outgoing:  graph.OutgoingCount(definition.ID),
-				})
+				return analysis, nil
+			})
+			if err != nil {
+				return err
 			}
+			matches, scanned := analysis.Matches, analysis.Scanned
+			dynamicRoots := analysis.DynamicRoots
 
 			// Filter out generated files unless --include-generated is set.
 			includeGenerated, _ := cmd.Flags().GetBool("include-generated")
@@ -143,43 +216,52 @@ Examples:
 				truncated = true
 			}
 
-			if jsonOutput {
+			outputFmt := format
+			if jsonOutput && outputFmt == "text" {
+				outputFmt = "json"
+			}
+			if (outputFmt == "json" && !countOnly) || writeChanges {
+				sources := attachDeadDeletions(idx, fileRoots, matches)
+				if writeChanges {
+					if err := applyDeadDeletions(fileRoots, sources, matches); err != nil {
+						return err
+					}
+				}
+			}
+			switch outputFmt {
+			case "text", "json":
+			case "github", "gitlab":
+				annotations := make([]annotate.Annotation, 0, len(matches))
+				for _, match := range matches {
+					annotations = append(annotations, annotate.Annotation{
+						RuleID:    "dead-code",
+						Severity:  "warn",
+						Message:   fmt.Sprintf("%s %s has no incoming calls", match.Kind, match.Name),
+						File:      match.File,
+						StartLine: match.StartLine,
+						EndLine:   match.EndLine,
+					})
+				}
+				return writeAnnotations(outputFmt, annotations)
+			default:
+				return fmt.Errorf("unsupported --format %q (expected text|json|github|gitlab)", format)
+			}
+
+			if outputFmt == "json" {
 				if countOnly {
-					return emitJSON(struct {
-						Count     int  `json:"count"`
-						Scanned   int  `json:"scanned"`
-						Truncated bool `json:"truncated,omitempty"`
-					}{
+					return emitJSON(report.DeadCountReport{
 						Count:     len(matches),
 						Scanned:   scanned,
 						Truncated: truncated,
 					})
 				}
-				if truncated {
-					return emitJSON(struct {
-						Kind      string      `json:"kind"`
-						Scanned   int         `json:"scanned"`
-						Count     int         `json:"count"`
-						Truncated bool        `json:"truncated"`
-						Matches   []deadMatch `json:"matches,omitempty"`
-					}{
-						Kind:      mode,
-						Scanned:   scanned,
-						Count:     len(matches),
-						Truncated: true,
-						Matches:   matches,
-					})
-				}
-				return emitJSON(struct {
-					Kind    string      `json:"kind"`
-					Scanned int         `json:"scanned"`
-					Count   int         `json:"count"`
-					Matches []deadMatch `json:"matches,omitempty"`
-				}{
-					Kind:    mode,
-					Scanned: scanned,
-					Count:   len(matches),
-					Matches: matches,
+				return emitJSON(report.DeadReport{
+					Kind:         mode,
+					Scanned:      scanned,
+					DynamicRoots: dynamicRoots,
+					Count:        len(matches),
+					Truncated:    truncated,
+					Matches:      matches,
 				})
 			}
 
@@ -207,7 +289,21 @@ Examples:
 					match.Outgoing,
 				)
 			}
-			fmt.Printf("dead: kind=%s scanned=%d matches=%d\n", mode, scanned, len(matches))
+			if dynamicRoots > 0 {
+				fmt.Printf("dead: kind=%s scanned=%d matches=%d dynamic_roots=%d\n", mode, scanned, len(matches), dynamicRoots)
+			} else {
+				fmt.Printf("dead: kind=%s scanned=%d matches=%d\n", mode, scanned, len(matches))
+			}
+			if writeChanges {
+				removed, files := 0, map[string]bool{}
+				for _, match := range matches {
+					if match.Removed {
+						removed++
+						files[match.File] = true
+					}
+				}
+				fmt.Printf("dead: removed=%d skipped=%d files=%d\n", removed, len(matches)-removed, len(files))
+			}
 			if truncated {
 				fmt.Fprintf(os.Stderr, "warning: results truncated at limit=%d, use --limit 0 for all\n", limit)
 			}
@@ -217,12 +313,18 @@ Examples:
 
 	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
-	cmd.Flags().StringVar(&kind, "kind", "callable", "filter dead definitions by callable|function|method")
+	cmd.Flags().StringVar(&kind, "kind", "callable", "filter dead definitions by callable|function|method|component|hook")
 	cmd.Flags().BoolVar(&includeEntrypoints, "include-entrypoints", false, "include main/init functions in dead code results")
 	cmd.Flags().BoolVar(&includeTests, "include-tests", false, "include _test files in dead code results")
+	cmd.Flags().BoolVar(&unexportedOnly, "unexported-only", false, "skip exported definitions, which may be used outside the indexed tree")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, github, gitlab")
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of dead definitions")
 	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of results (0 for unlimited)")
+	cmd.Flags().BoolVar(&resultCache, "result-cache", false, "reuse results stored under .gts/results for an identical index and flags, storing them on a miss")
+	cmd.Flags().BoolVar(&writeChanges, "write", false, "delete the reported definitions in place (default is report only)")
+	cmd.Flags().BoolVar(&noDynamicRoots, "no-dynamic-roots", false, "report Python definitions reached through getattr, __all__, registration calls, or framework decorators")
+	cmd.Flags().StringArrayVar(&rootDecorators, "root-decorator", nil, "regex matched against decorators, such as '^@my_registry\\.register'; matching definitions count as used (repeatable)")
 	return cmd
 }
 
@@ -234,24 +336,101 @@ func runDead(args []string) error {
 	return cmd.Execute()
 }
 
+// attachDeadDeletions sets the deletion range of each match that owns its
+// lines, and returns the sources it read, keyed by file.
+func attachDeadDeletions(idx *model.Index, fileRoots map[string]string, matches []deadMatch) map[string][]byte {
+	symbolsByFile := make(map[string][]model.Symbol, len(idx.Files))
+	languages := make(map[string]string, len(idx.Files))
+	for _, file := range idx.Files {
+		symbolsByFile[file.Path] = file.Symbols
+		languages[file.Path] = file.Language
+	}
+
+	sources := map[string][]byte{}
+	for i := range matches {
+		match := &matches[i]
+		source, ok := sources[match.File]
+		if !ok {
+			data, err := os.ReadFile(filepath.Join(fileRoots[match.File], filepath.FromSlash(match.File)))
+			if err != nil {
+				continue
+			}
+			source = data
+			sources[match.File] = source
+		}
+		span, ok := refactor.DeletionRange(source, languages[match.File], match.StartLine, match.EndLine)
+		if !ok {
+			continue
+		}
+		shared := false
+		for _, symbol := range symbolsByFile[match.File] {
+			nested := symbol.StartLine >= match.StartLine && symbol.EndLine <= match.EndLine
+			if !nested && symbol.StartLine <= span.EndLine && symbol.EndLine >= span.StartLine {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			match.Deletion = &span
+		}
+	}
+	return sources
+}
+
+// applyDeadDeletions deletes the ranges attached to matches, replacing
+// every edited file or none of them, and marks the matches it removed.
+func applyDeadDeletions(fileRoots map[string]string, sources map[string][]byte, matches []deadMatch) error {
+	rangesByFile := map[string][]refactor.Range{}
+	for _, match := range matches {
+		if match.Deletion != nil {
+			rangesByFile[match.File] = append(rangesByFile[match.File], *match.Deletion)
+		}
+	}
+	contents := make(map[string][]byte, len(rangesByFile))
+	for file, ranges := range rangesByFile {
+		updated, _, err := refactor.ApplyEdits(sources[file], refactor.DeletionEdits(sources[file], file, ranges))
+		if err != nil {
+			return err
+		}
+		contents[filepath.Join(fileRoots[file], filepath.FromSlash(file))] = updated
+	}
+	if err := refactor.WriteFiles(contents); err != nil {
+		return err
+	}
+	for i := range matches {
+		matches[i].Removed = matches[i].Deletion != nil
+	}
+	return nil
+}
+
 func deadKindAllowed(definition xref.Definition, mode string) bool {
 	switch mode {
 	case "callable":
 		return definition.Callable
 	case "function":
-		return definition.Kind == "function_definition"
+		return model.IsFunctionKind(definition.Kind)
 	case "method":
 		return definition.Kind == "method_definition"
+	case "component":
+		return definition.Kind == "component_definition"
+	case "hook":
+		return definition.Kind == "hook_definition"
 	default:
 		return false
 	}
 }
 
-func isEntrypointDefinition(definition xref.Definition) bool {
-	if definition.Kind != "function_definition" {
-		return false
+// isEntrypointDefinition reports whether definition is a program entry point:
+// main or init, or a component rendered outside any function, as a React
+// app's root is.
+func isEntrypointDefinition(definition xref.Definition, topLevel map[string]bool) bool {
+	switch definition.Kind {
+	case "function_definition":
+		return definition.Name == "main" || definition.Name == "init"
+	case "component_definition":
+		return topLevel[definition.Name]
 	}
-	return definition.Name == "main" || definition.Name == "init"
+	return false
 }
 
 func isTestSourceFile(path string) bool {
@@ -22,12 +22,34 @@ func newDepsCmd() *cobra.Command {
 	var countOnly bool
 	var dotOutput bool
 	var cyclesOnly bool
+	var why string
+	var whyLimit int
+	var closure []string
+	var format string
+	var pathPatterns []string
 
 	cmd := &cobra.Command{
 		Use:     "deps [path]",
 		Aliases: []string{"gtsdeps"},
 		Short:   "Analyze dependency graph from structural imports",
-		Args:    cobra.MaximumNArgs(1),
+		Long: `Analyze dependency graph from structural imports.
+
+--why from..to explains why package "from" depends on package "to" by
+printing the shortest import chains between them, with the file and line of
+each import, much like "go mod why" for in-repo imports. "to" may also be an
+external import path.
+
+--closure pkg lists every package that depends on pkg, directly or
+transitively, for build and test target selection. --format picks package
+directories (paths), their files (files), or Bazel labels (bazel).
+
+Examples:
+  gts deps --focus internal/api --depth 2
+  gts deps --why cmd/gts..pkg/model
+  gts deps --why internal/api..github.com/lib/pq
+  gts deps --closure pkg/model --format bazel
+  gts deps --path 'internal/...' --path '!internal/testdata/...'`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if top <= 0 {
 				return fmt.Errorf("top must be > 0")
@@ -36,7 +58,7 @@ func newDepsCmd() *cobra.Command {
 				return fmt.Errorf("depth must be > 0")
 			}
 
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -45,6 +67,59 @@ func newDepsCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			idx = applyGeneratedFilter(cmd, idx)
+			idx, err = idx.FilterByPathPatterns(pathPatterns)
+			if err != nil {
+				return err
+			}
+
+			if why != "" {
+				from, to, ok := strings.Cut(why, "..")
+				if !ok || strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+					return fmt.Errorf("--why expects from..to, got %q", why)
+				}
+				if by != "package" {
+					return fmt.Errorf("--why requires --by package")
+				}
+				report, err := deps.Why(idx, from, to, whyLimit)
+				if err != nil {
+					return err
+				}
+				if jsonOutput {
+					return emitJSON(report)
+				}
+				return printDepsWhy(report)
+			}
+
+			if len(closure) > 0 {
+				if by != "package" {
+					return fmt.Errorf("--closure requires --by package")
+				}
+				result, err := deps.ReverseClosure(idx, closure)
+				if err != nil {
+					return err
+				}
+				if jsonOutput {
+					return emitJSON(result)
+				}
+				switch format {
+				case "paths":
+					for _, pkg := range result.Packages {
+						fmt.Println(pkg)
+					}
+				case "files":
+					for _, file := range result.Files {
+						fmt.Println(file)
+					}
+				case "bazel":
+					for _, pkg := range result.Packages {
+						fmt.Println(deps.BazelLabel(pkg))
+					}
+				default:
+					return fmt.Errorf("unsupported --format %q (expected paths|files|bazel)", format)
+				}
+				return nil
+			}
 
 			report, err := deps.Build(idx, deps.Options{
 				Mode:         by,
@@ -168,9 +243,41 @@ func newDepsCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print only the count of dependency edges")
 	cmd.Flags().BoolVar(&dotOutput, "dot", false, "emit DOT graph for Graphviz visualization")
 	cmd.Flags().BoolVar(&cyclesOnly, "cycles", false, "only show import cycles")
+	cmd.Flags().StringVar(&why, "why", "", "explain a dependency: print import chains from..to between two packages")
+	cmd.Flags().IntVar(&whyLimit, "why-limit", 10, "maximum number of chains printed by --why")
+	cmd.Flags().StringArrayVar(&closure, "closure", nil, "print the reverse dependency closure of a package (repeatable)")
+	cmd.Flags().StringVar(&format, "format", "paths", "--closure output format: paths, files, bazel")
+	addPathFlag(cmd, &pathPatterns)
 	return cmd
 }
 
+func printDepsWhy(report deps.WhyReport) error {
+	if len(report.Chains) == 0 {
+		fmt.Printf("why: %s does not depend on %s\n", report.From, report.To)
+		return nil
+	}
+	hops := len(report.Chains[0])
+	fmt.Printf("why: %s -> %s chains=%d hops=%d\n", report.From, report.To, len(report.Chains), hops)
+	for i, chain := range report.Chains {
+		fmt.Printf("# chain %d\n", i+1)
+		for _, step := range chain {
+			location := step.File
+			if step.Line > 0 {
+				location = fmt.Sprintf("%s:%d", step.File, step.Line)
+			}
+			more := ""
+			if step.Files > 1 {
+				more = fmt.Sprintf(" (+%d files)", step.Files-1)
+			}
+			fmt.Printf("  %s -> %s  %s imports %q%s\n", step.From, step.To, location, step.Import, more)
+		}
+	}
+	if report.Truncated {
+		fmt.Println("truncated: use --why-limit for more chains")
+	}
+	return nil
+}
+
 func runDeps(args []string) error {
 	cmd := newDepsCmd()
 	cmd.SilenceUsage = true
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
 	"github.com/odvcencio/gts-suite/pkg/structdiff"
 )
 
@@ -15,68 +19,71 @@ func newDiffCmd() *cobra.Command {
 	var afterCache string
 	var noCache bool
 	var jsonOutput bool
+	var format string
 	var countOnly bool
+	var beforeRev string
+	var afterRev string
 
 	cmd := &cobra.Command{
 		Use:     "diff [before-path] [after-path]",
 		Aliases: []string{"gtsdiff"},
 		Short:   "Structural diff between two snapshots",
-		Args:    cobra.MaximumNArgs(2),
+		Long: `Structural diff between two snapshots.
+
+Each side is a path, a cache file (--before-cache/--after-cache), or a git
+revision (--before-rev/--after-rev). Revisions are indexed straight from the
+object store, so a dirty worktree does not leak into them. With both revisions
+the optional positional argument is the directory to index.
+
+Examples:
+  gts diff old/ new/
+  gts diff --before-rev HEAD~5 --after-rev HEAD
+  gts diff --before-rev main internal/
+  gts diff --before-rev v1.2.0 --after-rev HEAD --format markdown`,
+		Args: cobra.MaximumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			beforeTarget, afterTarget, err := resolveDiffSources(args, beforeCache, afterCache)
+			if beforeRev != "" && beforeCache != "" {
+				return fmt.Errorf("--before-rev and --before-cache are mutually exclusive")
+			}
+			if afterRev != "" && afterCache != "" {
+				return fmt.Errorf("--after-rev and --after-cache are mutually exclusive")
+			}
+			outputFmt, err := diffOutputFormat(format, jsonOutput)
 			if err != nil {
 				return err
 			}
-
-			beforeIndex, err := loadOrBuild(beforeCache, beforeTarget, noCache)
-			if err != nil {
-				return fmt.Errorf("load before snapshot: %w", err)
+			repoPath := "."
+			if beforeRev != "" && afterRev != "" && len(args) == 1 {
+				repoPath, args = args[0], nil
 			}
-			afterIndex, err := loadOrBuild(afterCache, afterTarget, noCache)
+
+			beforeTarget, afterTarget, err := resolveDiffSources(args, beforeCache+beforeRev, afterCache+afterRev)
 			if err != nil {
-				return fmt.Errorf("load after snapshot: %w", err)
+				return err
 			}
-
-			report := structdiff.Compare(beforeIndex, afterIndex)
-
-			if countOnly {
-				fmt.Println(report.Stats.AddedSymbols + report.Stats.RemovedSymbols + report.Stats.ModifiedSymbols)
-				return nil
+			if beforeTarget != "" && afterRev != "" {
+				repoPath = beforeTarget
 			}
-
-			if jsonOutput {
-				return emitJSON(report)
+			if afterTarget != "" && beforeRev != "" {
+				repoPath = afterTarget
 			}
 
-			fmt.Printf("changed files: %d\n", report.Stats.ChangedFiles)
-			fmt.Printf("symbols: +%d -%d ~%d\n", report.Stats.AddedSymbols, report.Stats.RemovedSymbols, report.Stats.ModifiedSymbols)
-
-			for _, item := range report.AddedSymbols {
-				fmt.Printf("+ %s:%d:%d %s %s\n", item.File, item.StartLine, item.EndLine, item.Kind, symbolLabel(item.Name, item.Signature))
-			}
-			for _, item := range report.RemovedSymbols {
-				fmt.Printf("- %s:%d:%d %s %s\n", item.File, item.StartLine, item.EndLine, item.Kind, symbolLabel(item.Name, item.Signature))
+			loadSide := func(cachePath, rev, target string) (*model.Index, error) {
+				if rev != "" {
+					return buildRevisionIndex(repoPath, rev)
+				}
+				return loadOrBuild(cachePath, target, noCache)
 			}
-			for _, item := range report.ModifiedSymbols {
-				fmt.Printf("~ %s:%d:%d %s %s fields=%s\n",
-					item.After.File,
-					item.After.StartLine,
-					item.After.EndLine,
-					item.After.Kind,
-					symbolLabel(item.After.Name, item.After.Signature),
-					strings.Join(item.Fields, ","))
+			beforeIndex, err := loadSide(beforeCache, beforeRev, beforeTarget)
+			if err != nil {
+				return fmt.Errorf("load before snapshot: %w", err)
 			}
-			for _, change := range report.ImportChanges {
-				parts := make([]string, 0, 2)
-				if len(change.Added) > 0 {
-					parts = append(parts, "added="+strings.Join(change.Added, ","))
-				}
-				if len(change.Removed) > 0 {
-					parts = append(parts, "removed="+strings.Join(change.Removed, ","))
-				}
-				fmt.Printf("i %s %s\n", change.File, strings.Join(parts, " "))
+			afterIndex, err := loadSide(afterCache, afterRev, afterTarget)
+			if err != nil {
+				return fmt.Errorf("load after snapshot: %w", err)
 			}
-			return nil
+
+			return emitDiffReport(outputFmt, structdiff.Compare(beforeIndex, afterIndex), countOnly)
 		},
 	}
 
@@ -84,10 +91,85 @@ func newDiffCmd() *cobra.Command {
 	cmd.Flags().StringVar(&afterCache, "after-cache", "", "load after snapshot from cache file")
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, markdown")
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print only the count of changed symbols")
+	cmd.Flags().StringVar(&beforeRev, "before-rev", "", "index the before snapshot from this git revision")
+	cmd.Flags().StringVar(&afterRev, "after-rev", "", "index the after snapshot from this git revision")
 	return cmd
 }
 
+// diffOutputFormat validates --format, which --json overrides when left at
+// text.
+func diffOutputFormat(format string, jsonOutput bool) (string, error) {
+	outputFmt := format
+	if jsonOutput && outputFmt == "text" {
+		outputFmt = "json"
+	}
+	switch outputFmt {
+	case "text", "json", "markdown":
+		return outputFmt, nil
+	}
+	return "", fmt.Errorf("unsupported --format %q (expected text|json|markdown)", format)
+}
+
+// emitDiffReport prints report in format, or only its count of changed
+// symbols when countOnly is set.
+func emitDiffReport(format string, report structdiff.Report, countOnly bool) error {
+	if countOnly {
+		fmt.Println(report.Stats.AddedSymbols + report.Stats.RemovedSymbols + report.Stats.ModifiedSymbols)
+		return nil
+	}
+
+	switch format {
+	case "json":
+		return emitJSON(report)
+	case "markdown":
+		structdiff.WriteMarkdown(os.Stdout, report)
+		return nil
+	}
+
+	fmt.Printf("changed files: %d\n", report.Stats.ChangedFiles)
+	fmt.Printf("symbols: +%d -%d ~%d\n", report.Stats.AddedSymbols, report.Stats.RemovedSymbols, report.Stats.ModifiedSymbols)
+
+	for _, item := range report.AddedSymbols {
+		fmt.Printf("+ %s:%d:%d %s %s\n", item.File, item.StartLine, item.EndLine, item.Kind, symbolLabel(item.Name, item.Signature))
+	}
+	for _, item := range report.RemovedSymbols {
+		fmt.Printf("- %s:%d:%d %s %s\n", item.File, item.StartLine, item.EndLine, item.Kind, symbolLabel(item.Name, item.Signature))
+	}
+	for _, item := range report.ModifiedSymbols {
+		fmt.Printf("~ %s:%d:%d %s %s fields=%s\n",
+			item.After.File,
+			item.After.StartLine,
+			item.After.EndLine,
+			item.After.Kind,
+			symbolLabel(item.After.Name, item.After.Signature),
+			strings.Join(item.Fields, ","))
+	}
+	for _, change := range report.ImportChanges {
+		parts := make([]string, 0, 2)
+		if len(change.Added) > 0 {
+			parts = append(parts, "added="+strings.Join(change.Added, ","))
+		}
+		if len(change.Removed) > 0 {
+			parts = append(parts, "removed="+strings.Join(change.Removed, ","))
+		}
+		fmt.Printf("i %s %s\n", change.File, strings.Join(parts, " "))
+	}
+	return nil
+}
+
+// buildRevisionIndex indexes target as of a git revision, reading contents
+// from the object store.
+func buildRevisionIndex(target, rev string) (*model.Index, error) {
+	builder, err := index.NewBuilderWithWorkspaceIgnores(target)
+	if err != nil {
+		return nil, err
+	}
+	idx, _, err := builder.BuildRevision(context.Background(), target, rev)
+	return idx, err
+}
+
 func runDiff(args []string) error {
 	cmd := newDiffCmd()
 	cmd.SilenceUsage = true
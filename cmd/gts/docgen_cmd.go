@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/docgen"
+)
+
+func newDocgenCmd() *cobra.Command {
+	var outDir string
+	var cachePath string
+	var noCache bool
+	var check bool
+	var includeUnexported bool
+	var maxCallers int
+
+	cmd := &cobra.Command{
+		Use:   "docgen [path]",
+		Short: "Generate per-package Markdown reference docs from the index",
+		Long: `Generate per-package Markdown reference docs from the index.
+
+Writes one page per package to the output directory, with the package's
+imports and importers, a table of its exported symbols with their signatures,
+and a section per symbol holding its doc comment, a link to its source, and
+the callers with the most call sites. README.md lists every package.
+
+Every generated page starts with a "Code generated by gts docgen" marker.
+Files without it are never overwritten, and generated pages for packages
+that no longer exist are removed. With --check nothing is written; the
+command exits 1 if any page is missing, stale, or left over, so CI can
+require the docs to be regenerated.
+
+Examples:
+  gts docgen
+  gts docgen . --out docs/reference
+  gts docgen --check`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := defaultTarget()
+			if len(args) == 1 {
+				target = args[0]
+			}
+			if strings.TrimSpace(outDir) == "" {
+				return fmt.Errorf("--out must not be empty")
+			}
+
+			idx, err := loadOrBuild(cachePath, target, noCache)
+			if err != nil {
+				return err
+			}
+			absOut, err := filepath.Abs(outDir)
+			if err != nil {
+				return err
+			}
+			prefix, err := filepath.Rel(absOut, idx.Root)
+			if err != nil {
+				return err
+			}
+
+			pages, err := docgen.Generate(idx, docgen.Options{
+				SourcePrefix:      filepath.ToSlash(prefix) + "/",
+				MaxCallers:        maxCallers,
+				IncludeUnexported: includeUnexported,
+			})
+			if err != nil {
+				return err
+			}
+
+			existing, err := generatedDocPages(outDir)
+			if err != nil {
+				return err
+			}
+			var written, unchanged, removed []string
+			wanted := map[string]bool{}
+			for _, page := range pages {
+				wanted[page.Path] = true
+				pagePath := filepath.Join(outDir, filepath.FromSlash(page.Path))
+				current, readErr := os.ReadFile(pagePath)
+				if readErr == nil && !bytes.HasPrefix(current, []byte(docgen.Marker)) {
+					return fmt.Errorf("%s exists and was not generated by gts docgen", pagePath)
+				}
+				if readErr == nil && bytes.Equal(current, page.Content) {
+					unchanged = append(unchanged, page.Path)
+					continue
+				}
+				written = append(written, page.Path)
+				if check {
+					continue
+				}
+				if err := os.MkdirAll(filepath.Dir(pagePath), 0o755); err != nil {
+					return err
+				}
+				if err := os.WriteFile(pagePath, page.Content, 0o644); err != nil {
+					return err
+				}
+			}
+			for _, page := range existing {
+				if wanted[page] {
+					continue
+				}
+				removed = append(removed, page)
+				if check {
+					continue
+				}
+				if err := os.Remove(filepath.Join(outDir, filepath.FromSlash(page))); err != nil {
+					return err
+				}
+			}
+
+			if check {
+				for _, page := range written {
+					fmt.Printf("stale: %s\n", page)
+				}
+				for _, page := range removed {
+					fmt.Printf("orphaned: %s\n", page)
+				}
+				fmt.Printf("docgen: pages=%d stale=%d orphaned=%d\n", len(pages), len(written), len(removed))
+				if len(written) > 0 || len(removed) > 0 {
+					return exitCodeError{code: 1, err: fmt.Errorf("docs in %s are out of date, run gts docgen", outDir)}
+				}
+				return nil
+			}
+			fmt.Printf("docgen: pages=%d written=%d unchanged=%d removed=%d out=%s\n", len(pages), len(written), len(unchanged), len(removed), outDir)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&outDir, "out", "docs", "output directory for generated pages")
+	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
+	cmd.Flags().BoolVar(&check, "check", false, "report stale pages without writing; exit 1 if any")
+	cmd.Flags().BoolVar(&includeUnexported, "include-unexported", false, "document unexported symbols as well")
+	cmd.Flags().IntVar(&maxCallers, "max-callers", 5, "maximum key callers listed per symbol")
+	return cmd
+}
+
+func runDocgen(args []string) error {
+	cmd := newDocgenCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+// generatedDocPages returns the slash-separated paths, relative to dir, of the
+// Markdown files under dir that carry the docgen marker.
+func generatedDocPages(dir string) ([]string, error) {
+	var pages []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(data, []byte(docgen.Marker)) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(pages)
+	return pages, err
+}
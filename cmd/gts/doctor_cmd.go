@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odvcencio/gotreesitter/grammars"
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/cachedir"
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/lang/treesitter"
+)
+
+// Statuses of a doctor check.
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// doctorCheck is one finding of gts doctor.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	// Fix says what to do about a warning or failure.
+	Fix string `json:"fix,omitempty"`
+}
+
+type doctorReport struct {
+	Root   string        `json:"root"`
+	Checks []doctorCheck `json:"checks"`
+}
+
+func newDoctorCmd() *cobra.Command {
+	var cachePath string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor [path]",
+		Short: "Check that gts can parse, cache, and watch a project",
+		Long: `Check that gts can parse, cache, and watch a project, and say how to fix
+what it cannot.
+
+  grammars  every language in the project has a grammar that loads
+  cache     the index cache is readable, current, and its directory writable
+  watch     fsnotify can watch the project: its filesystem reports changes
+            and, on Linux, fs.inotify.max_user_watches covers its directories
+  go        a go toolchain is on PATH for refactor's go engine, which
+            type-checks against compiled packages
+
+Warnings leave gts working in a degraded way; failures stop a feature
+working. doctor exits with status 1 when a check fails.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, indexPath, err := cacheTarget(args, cachePath)
+			if err != nil {
+				return err
+			}
+			report, err := runDoctorChecks(root, indexPath)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				if err := emitJSON(report); err != nil {
+					return err
+				}
+			} else {
+				printDoctorReport(report)
+			}
+			failed := 0
+			for _, check := range report.Checks {
+				if check.Status == doctorFail {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return exitCodeError{code: 1, err: fmt.Errorf("doctor found %d failing checks", failed)}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&cachePath, "cache", "", "index cache to check (default: .gts/index.json under path)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+// runDoctorChecks checks the project at root, whose index cache is at
+// indexPath.
+func runDoctorChecks(root, indexPath string) (doctorReport, error) {
+	builder, err := index.NewBuilderWithWorkspaceIgnores(root)
+	if err != nil {
+		return doctorReport{}, err
+	}
+	scan, err := scanDoctorProject(root, builder)
+	if err != nil {
+		return doctorReport{}, err
+	}
+
+	report := doctorReport{Root: root}
+	report.Checks = append(report.Checks, checkGrammars(builder, scan)...)
+	report.Checks = append(report.Checks, checkCache(root, indexPath)...)
+	report.Checks = append(report.Checks, checkWatch(root, scan.dirs)...)
+	report.Checks = append(report.Checks, checkGoToolchain(scan.files["go"]))
+	return report, nil
+}
+
+// doctorScan is what the checks need to know about the project's files.
+type doctorScan struct {
+	// dirs counts the directories watch mode would watch.
+	dirs int
+	// files counts indexable files by language, and samples holds a path of
+	// each language to look its parser up by.
+	files   map[string]int
+	samples map[string]string
+}
+
+// scanDoctorProject walks root as watch mode and the index do, skipping the
+// directories and files they skip.
+func scanDoctorProject(root string, builder *index.Builder) (doctorScan, error) {
+	scan := doctorScan{files: map[string]int{}, samples: map[string]string{}}
+	matcher := builder.Ignore()
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if entry != nil && entry.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return walkErr
+		}
+		if entry.IsDir() {
+			if shouldSkipWatchDir(root, path, entry.Name(), matcher) {
+				return filepath.SkipDir
+			}
+			scan.dirs++
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if rel, err := filepath.Rel(root, path); err == nil && matcher != nil && matcher.Match(filepath.ToSlash(rel), false) {
+			return nil
+		}
+		parser, ok := builder.ParserForPath(path)
+		if !ok {
+			return nil
+		}
+		language := parser.Language()
+		if scan.files[language] == 0 {
+			scan.samples[language] = path
+		}
+		scan.files[language]++
+		return nil
+	})
+	return scan, err
+}
+
+// checkGrammars loads the grammar and symbol query of each language in the
+// project. A grammar that does not load fails; a language without a symbol
+// query only warns, as its files are still listed with a parse error.
+func checkGrammars(builder *index.Builder, scan doctorScan) []doctorCheck {
+	if len(scan.files) == 0 {
+		return []doctorCheck{{Name: "grammars", Status: doctorWarn, Detail: "no files in a supported language", Fix: "check the path, .gtsignore, and .gitignore"}}
+	}
+	languages := make([]string, 0, len(scan.files))
+	for language := range scan.files {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	var loaded, unqueried []string
+	var checks []doctorCheck
+	for _, language := range languages {
+		label := fmt.Sprintf("%s (%d)", language, scan.files[language])
+		if entry := grammars.DetectLanguageByName(language); entry != nil && (entry.Language == nil || entry.Language() == nil) {
+			checks = append(checks, doctorCheck{
+				Name:   "grammars",
+				Status: doctorFail,
+				Detail: "the grammar of " + label + " does not load",
+				Fix:    "reinstall or upgrade gts, or exclude these files in .gtsignore",
+			})
+			continue
+		}
+		parser, _ := builder.ParserForPath(scan.samples[language])
+		if lazy, ok := parser.(interface {
+			TreesitterParser() (*treesitter.Parser, error)
+		}); ok {
+			if _, err := lazy.TreesitterParser(); err != nil {
+				unqueried = append(unqueried, label)
+				continue
+			}
+		}
+		loaded = append(loaded, label)
+	}
+	if len(unqueried) > 0 {
+		checks = append([]doctorCheck{{
+			Name:   "grammars",
+			Status: doctorWarn,
+			Detail: "no symbol query for " + strings.Join(unqueried, ", ") + "; their files are indexed as parse errors",
+			Fix:    "exclude them in .gtsignore unless you query them with gts grep -S",
+		}}, checks...)
+	}
+	if len(loaded) > 0 {
+		checks = append([]doctorCheck{{Name: "grammars", Status: doctorOK, Detail: "loaded " + strings.Join(loaded, ", ")}}, checks...)
+	}
+	return checks
+}
+
+// checkCache checks that the index cache at indexPath can be loaded and
+// that the cache directory takes new files.
+func checkCache(root, indexPath string) []doctorCheck {
+	info, err := cachedir.Inspect(root, indexPath)
+	if err != nil {
+		return []doctorCheck{{Name: "cache", Status: doctorFail, Detail: err.Error(), Fix: "check the permissions of " + filepath.Join(root, cachedir.DirName)}}
+	}
+	build := fmt.Sprintf("run gts index build %s --out %s", root, indexPath)
+
+	var checks []doctorCheck
+	switch idx := info.Index; {
+	case idx == nil:
+		checks = append(checks, doctorCheck{Name: "cache", Status: doctorWarn, Detail: "no index cache at " + indexPath + "; every command parses the project", Fix: build})
+	case idx.Error != "":
+		checks = append(checks, doctorCheck{Name: "cache", Status: doctorFail, Detail: fmt.Sprintf("cannot read %s: %s", indexPath, idx.Error), Fix: "delete it and " + build})
+	case !idx.Current:
+		checks = append(checks, doctorCheck{Name: "cache", Status: doctorWarn, Detail: fmt.Sprintf("%s has schema %s, which this gts rebuilds on every use", indexPath, idx.SchemaVersion), Fix: build})
+	default:
+		checks = append(checks, doctorCheck{Name: "cache", Status: doctorOK, Detail: fmt.Sprintf("%s: schema %s, %d files, built %s ago", indexPath, idx.SchemaVersion, idx.Files, cacheAge(idx.GeneratedAt))})
+		if idx.StaleFiles > 0 {
+			checks = append(checks, doctorCheck{Name: "cache", Status: doctorWarn, Detail: fmt.Sprintf("%d indexed files no longer exist", idx.StaleFiles), Fix: "run gts cache clean"})
+		}
+	}
+	if len(info.Temp) > 0 {
+		checks = append(checks, doctorCheck{Name: "cache", Status: doctorWarn, Detail: fmt.Sprintf("%d temporary files left by interrupted saves", len(info.Temp)), Fix: "run gts cache gc"})
+	}
+	if info.Exists {
+		probe, err := os.CreateTemp(info.Dir, "doctor-*.tmp")
+		if err != nil {
+			checks = append(checks, doctorCheck{Name: "cache", Status: doctorFail, Detail: fmt.Sprintf("%s is not writable: %v", info.Dir, err), Fix: "fix the permissions of " + info.Dir})
+		} else {
+			probe.Close()
+			os.Remove(probe.Name())
+		}
+	}
+	return checks
+}
+
+// checkWatch checks that fsnotify can watch the dirs directories of root.
+func checkWatch(root string, dirs int) []doctorCheck {
+	if fs := unwatchableFilesystem(root); fs != "" {
+		return []doctorCheck{{
+			Name:   "watch",
+			Status: doctorWarn,
+			Detail: fmt.Sprintf("%s is on a %s, which does not report file changes", root, fs),
+			Fix:    "use --poll with index watch and chunk watch",
+		}}
+	}
+	limit, ok := watchLimit()
+	if !ok {
+		return []doctorCheck{{Name: "watch", Status: doctorOK, Detail: fmt.Sprintf("directories to watch: %d", dirs)}}
+	}
+	raise := fmt.Sprintf("run sudo sysctl fs.inotify.max_user_watches=%d, and set it in /etc/sysctl.d to keep it", max(524288, 2*dirs))
+	switch {
+	case dirs > limit:
+		return []doctorCheck{{
+			Name:   "watch",
+			Status: doctorFail,
+			Detail: fmt.Sprintf("watching needs %d directories but fs.inotify.max_user_watches is %d", dirs, limit),
+			Fix:    raise,
+		}}
+	case dirs > limit/2:
+		// Editors and other watchers of the same user share the limit.
+		return []doctorCheck{{
+			Name:   "watch",
+			Status: doctorWarn,
+			Detail: fmt.Sprintf("watching needs %d of the %d inotify watches, which other watchers share", dirs, limit),
+			Fix:    raise,
+		}}
+	}
+	return []doctorCheck{{Name: "watch", Status: doctorOK, Detail: fmt.Sprintf("directories to watch: %d of the inotify limit %d", dirs, limit)}}
+}
+
+// checkGoToolchain checks for the go command refactor's go engine needs to
+// load compiled packages. It only matters when the project has goFiles Go
+// files.
+func checkGoToolchain(goFiles int) doctorCheck {
+	path, err := exec.LookPath("go")
+	if err != nil {
+		if goFiles == 0 {
+			return doctorCheck{Name: "go", Status: doctorOK, Detail: "no go toolchain, and no Go files need one"}
+		}
+		return doctorCheck{
+			Name:   "go",
+			Status: doctorWarn,
+			Detail: "go is not on PATH; refactor's go engine cannot type-check imports",
+			Fix:    "install Go from https://go.dev/dl, or use refactor --engine treesitter",
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "env", "GOVERSION").Output()
+	if err != nil {
+		return doctorCheck{
+			Name:   "go",
+			Status: doctorWarn,
+			Detail: fmt.Sprintf("%s env failed: %v", path, err),
+			Fix:    "check the go installation and GOROOT",
+		}
+	}
+	return doctorCheck{Name: "go", Status: doctorOK, Detail: fmt.Sprintf("%s at %s", strings.TrimSpace(string(out)), path)}
+}
+
+func printDoctorReport(report doctorReport) {
+	counts := map[string]int{}
+	for _, check := range report.Checks {
+		counts[check.Status]++
+		fmt.Printf("%-4s  %-8s  %s\n", check.Status, check.Name, check.Detail)
+		if check.Fix != "" {
+			fmt.Printf("%-4s  %-8s  fix: %s\n", "", "", check.Fix)
+		}
+	}
+	fmt.Printf("doctor: ok=%d warn=%d fail=%d\n", counts[doctorOK], counts[doctorWarn], counts[doctorFail])
+}
+
+func runDoctor(args []string) error {
+	cmd := newDoctorCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
@@ -42,7 +42,7 @@ func newDriftCmd() *cobra.Command {
 		Short: "Compare dependency graph between two git refs",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -23,7 +23,7 @@ func newExportCmd() *cobra.Command {
 		Short: "Export structural index to a portable .gtsindex file",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
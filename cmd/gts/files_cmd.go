@@ -16,6 +16,7 @@ func newFilesCmd() *cobra.Command {
 	var sortBy string
 	var top int
 	var jsonOutput bool
+	var pathPatterns []string
 
 	cmd := &cobra.Command{
 		Use:     "files [path]",
@@ -30,7 +31,7 @@ func newFilesCmd() *cobra.Command {
 				return fmt.Errorf("top must be > 0")
 			}
 
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -42,6 +43,10 @@ func newFilesCmd() *cobra.Command {
 			if gen, _ := cmd.Flags().GetString("generator"); gen != "" {
 				idx = idx.FilterByGenerator(gen)
 			}
+			idx, err = idx.FilterByPathPatterns(pathPatterns)
+			if err != nil {
+				return err
+			}
 
 			report, err := files.Build(idx, files.Options{
 				Language:   language,
@@ -86,6 +91,7 @@ func newFilesCmd() *cobra.Command {
 	cmd.Flags().StringVar(&sortBy, "sort", "symbols", "sort by symbols|imports|size|path")
 	cmd.Flags().IntVar(&top, "top", 50, "maximum files to show")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	addPathFlag(cmd, &pathPatterns)
 	return cmd
 }
 
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter/grammars"
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/lint"
+	"github.com/odvcencio/gts-suite/pkg/codeaction"
+	"github.com/odvcencio/gts-suite/pkg/refactor"
+	"github.com/odvcencio/gts-suite/pkg/textpos"
+)
+
+// fixTarget is a file gts fix changes: its path relative to root, which may
+// be empty for a file given directly, and its index language.
+type fixTarget struct {
+	path     string
+	language string
+}
+
+func newFixCmd() *cobra.Command {
+	var cachePath string
+	var noCache bool
+	var organizeImports bool
+	var removeUnused bool
+	var sortImports bool
+	var extractAt string
+	var constName string
+	var writeChanges bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "fix [path]",
+		Short: "Organize imports and extract constants (dry-run by default)",
+		Long: `Organize imports and extract constants (dry-run by default).
+
+Applies the code actions gtsls offers editors to Go, Python, JavaScript, and
+TypeScript files: removing unused imports, sorting imports, and extracting a
+literal to a named constant. Without an action flag, --organize-imports is
+applied, which removes unused imports and then sorts the rest. Imports are
+sorted within runs on consecutive lines, so blank-line groups are kept.
+
+--extract-constant LINE:COL extracts the literal at that 1-based line and
+byte column of a single file. The constant is declared before the
+top-level declaration holding the literal and named by --name, or after the
+literal's words.
+
+Changes are shown as unified diffs until --write applies them.
+
+Examples:
+  gts fix
+  gts fix internal/server --remove-unused-imports --write
+  gts fix web/app.ts --extract-constant 42:17 --name API_TIMEOUT_MS`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if constName != "" && extractAt == "" {
+				return fmt.Errorf("--name requires --extract-constant")
+			}
+			if !removeUnused && !sortImports && extractAt == "" {
+				organizeImports = true
+			}
+
+			target := defaultTarget()
+			if len(args) == 1 {
+				target = args[0]
+			}
+			root, targets, err := fixTargets(cmd, target, cachePath, noCache)
+			if err != nil {
+				return err
+			}
+			if extractAt != "" && (len(targets) != 1 || root != "") {
+				return fmt.Errorf("--extract-constant needs a single file")
+			}
+
+			var violations []lint.Violation
+			for _, file := range targets {
+				source, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file.path)))
+				if err != nil {
+					return err
+				}
+				updated := source
+				// Extract first, as --extract-constant positions refer to the
+				// file as it is.
+				if extractAt != "" {
+					start, err := fixOffset(source, extractAt)
+					if err != nil {
+						return err
+					}
+					if updated, _, err = codeaction.ExtractConstant(file.language, updated, start, start, constName); err != nil {
+						return fmt.Errorf("%s: %w", file.path, err)
+					}
+				}
+				steps := []struct {
+					on    bool
+					apply func(string, []byte) ([]byte, error)
+				}{
+					{organizeImports, codeaction.OrganizeImports},
+					{removeUnused, codeaction.RemoveUnusedImports},
+					{sortImports, codeaction.SortImports},
+				}
+				for _, step := range steps {
+					if !step.on {
+						continue
+					}
+					if updated, err = step.apply(file.language, updated); err != nil {
+						return fmt.Errorf("%s: %w", file.path, err)
+					}
+				}
+				if edit, ok := codeaction.Edit(file.path, source, updated); ok {
+					violations = append(violations, lint.Violation{File: file.path, Fixes: []refactor.Edit{edit}})
+				}
+			}
+
+			report, err := lint.ApplyFixes(root, violations, writeChanges)
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return emitJSON(report)
+			}
+			for _, fileFix := range report.Files {
+				fmt.Print(fileFix.Diff)
+			}
+			verb := "would change"
+			if writeChanges {
+				verb = "changed"
+			}
+			fmt.Printf("fix: %s %d of %d files\n", verb, len(report.Files), len(targets))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
+	cmd.Flags().BoolVar(&organizeImports, "organize-imports", false, "remove unused imports, then sort imports (default when no other action is given)")
+	cmd.Flags().BoolVar(&removeUnused, "remove-unused-imports", false, "remove imports whose names are never used")
+	cmd.Flags().BoolVar(&sortImports, "sort-imports", false, "sort imports within each run of consecutive lines")
+	cmd.Flags().StringVar(&extractAt, "extract-constant", "", "extract the literal at LINE:COL of a single file to a constant")
+	cmd.Flags().StringVar(&constName, "name", "", "name of the constant --extract-constant declares")
+	cmd.Flags().BoolVar(&writeChanges, "write", false, "apply changes to files")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+// fixTargets returns the files under target that code actions support. A
+// file is returned as given, with an empty root; a directory yields the
+// indexed files, without generated ones, relative to the index root.
+func fixTargets(cmd *cobra.Command, target, cachePath string, noCache bool) (string, []fixTarget, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return "", nil, err
+	}
+	if !info.IsDir() {
+		entry := grammars.DetectLanguage(target)
+		if entry == nil || !codeaction.Supported(entry.Name) {
+			return "", nil, fmt.Errorf("gts fix does not support %s", target)
+		}
+		return "", []fixTarget{{path: filepath.ToSlash(target), language: entry.Name}}, nil
+	}
+
+	idx, err := loadOrBuild(cachePath, target, noCache)
+	if err != nil {
+		return "", nil, err
+	}
+	idx = applyGeneratedFilter(cmd, idx)
+	var targets []fixTarget
+	for _, file := range idx.Files {
+		if codeaction.Supported(file.Language) {
+			targets = append(targets, fixTarget{path: file.Path, language: file.Language})
+		}
+	}
+	return idx.Root, targets, nil
+}
+
+// fixOffset returns the byte offset of a 1-based LINE:COL position in
+// source, with COL counted in bytes.
+func fixOffset(source []byte, position string) (int, error) {
+	lineText, colText, ok := strings.Cut(position, ":")
+	line, lineErr := strconv.Atoi(lineText)
+	col, colErr := strconv.Atoi(colText)
+	if !ok || lineErr != nil || colErr != nil || line < 1 || col < 1 {
+		return 0, fmt.Errorf("invalid position %q (expected LINE:COL)", position)
+	}
+	m := textpos.New(source)
+	if line > m.LineCount() {
+		return 0, fmt.Errorf("position %q is past the end of the file", position)
+	}
+	return m.Offset(line-1, col-1, textpos.UTF8), nil
+}
+
+func runFix(args []string) error {
+	cmd := newFixCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
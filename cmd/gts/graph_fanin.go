@@ -8,22 +8,11 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
-type faninEntry struct {
-	Name      string  `json:"name"`
-	Signature string  `json:"signature,omitempty"`
-	File      string  `json:"file"`
-	Kind      string  `json:"kind"`
-	StartLine int     `json:"start_line"`
-	EndLine   int     `json:"end_line"`
-	Incoming  int     `json:"incoming"`
-	Outgoing  int     `json:"outgoing"`
-	Ratio     float64 `json:"ratio"`
-	Generated string  `json:"generated,omitempty"`
-}
-
 func newFaninCmd() *cobra.Command {
 	var cachePath string
 	var noCache bool
@@ -55,7 +44,7 @@ func newFaninCmd() *cobra.Command {
 				}
 			}
 
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -80,7 +69,7 @@ func newFaninCmd() *cobra.Command {
 				if kind != "" {
 					switch kind {
 					case "function":
-						if def.Kind != "function_definition" {
+						if !model.IsFunctionKind(def.Kind) {
 							continue
 						}
 					case "method":
@@ -148,20 +137,12 @@ func newFaninCmd() *cobra.Command {
 
 			if jsonOutput {
 				if countOnly {
-					return emitJSON(struct {
-						Count int `json:"count"`
-					}{
-						Count: len(entries),
-					})
+					return emitJSON(report.CountReport{Count: len(entries)})
 				}
 				if limit > 0 && len(entries) > limit {
 					entries = entries[:limit]
 				}
-				return emitJSON(struct {
-					Count   int          `json:"count"`
-					SortBy  string       `json:"sort_by"`
-					Entries []faninEntry `json:"entries,omitempty"`
-				}{
+				return emitJSON(report.FaninReport{
 					Count:   len(entries),
 					SortBy:  sortBy,
 					Entries: entries,
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
@@ -24,7 +25,7 @@ func newUnresolvedCmd() *cobra.Command {
 		Short: "Show call references that could not be resolved to a definition",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -69,10 +70,7 @@ func newUnresolvedCmd() *cobra.Command {
 
 			if jsonOutput {
 				if countOnly {
-					return emitJSON(struct {
-						Count int `json:"count"`
-						Total int `json:"total"`
-					}{
+					return emitJSON(report.UnresolvedCountReport{
 						Count: len(matches),
 						Total: len(graph.Unresolved),
 					})
@@ -80,11 +78,7 @@ func newUnresolvedCmd() *cobra.Command {
 				if limit > 0 && len(matches) > limit {
 					matches = matches[:limit]
 				}
-				return emitJSON(struct {
-					Count   int                  `json:"count"`
-					Total   int                  `json:"total"`
-					Entries []xref.UnresolvedCall `json:"entries,omitempty"`
-				}{
+				return emitJSON(report.UnresolvedReport{
 					Count:   len(matches),
 					Total:   len(graph.Unresolved),
 					Entries: matches,
@@ -14,7 +14,9 @@ import (
 
 	tsgrep "github.com/odvcencio/gotreesitter/grep"
 	"github.com/odvcencio/gotreesitter/grammars"
+	"github.com/odvcencio/gts-suite/internal/queries"
 	"github.com/odvcencio/gts-suite/pkg/query"
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
 // grepMode indicates which engine to dispatch to.
@@ -30,7 +32,7 @@ const (
 //
 //  1. If the pattern starts with "find " → structural (full query syntax).
 //  2. If the pattern contains $ followed by a letter → structural (metavariable).
-//  3. If the pattern matches word[ → selector DSL.
+//  3. If the pattern matches word[ or kind > kind → selector DSL.
 //  4. Otherwise → structural (try first, fall back to selector).
 func detectGrepMode(pattern string) grepMode {
 	trimmed := strings.TrimSpace(pattern)
@@ -63,6 +65,12 @@ func detectGrepMode(pattern string) grepMode {
 		return grepModeSelector
 	}
 
+	// Rule 3d: containment between node kinds (e.g. "class_definition > method_definition")
+	containmentRE := regexp.MustCompile(`^(?:\*|[a-z]+_[a-z0-9_]*)(?:\[.*\])?\s*>\s*(?:\*|[a-z]+_[a-z0-9_]*)`)
+	if containmentRE.MatchString(trimmed) {
+		return grepModeSelector
+	}
+
 	// Rule 4: ambiguous — prefer structural.
 	return grepModeStructural
 }
@@ -71,6 +79,7 @@ func newGrepCmd() *cobra.Command {
 	var cachePath string
 	var noCache bool
 	var jsonOutput bool
+	var format string
 	var countOnly bool
 	var forceStructural bool
 	var forceSelector bool
@@ -78,9 +87,10 @@ func newGrepCmd() *cobra.Command {
 	var rewrite string
 	var where string
 	var limit int
+	var orSelectors []string
 
 	cmd := &cobra.Command{
-		Use:     "grep <pattern> [path]",
+		Use:     "grep <pattern|@query> [path]",
 		Aliases: []string{"gtsgrep"},
 		Short:   "Structural grep — code patterns and selector DSL",
 		Long: `Structural grep over source code using two complementary engines.
@@ -93,14 +103,28 @@ STRUCTURAL MODE (code patterns with metavariables):
 SELECTOR MODE (indexed symbol queries):
   Patterns use the selector DSL: kind[filter1,filter2,...] against the
   structural index. Useful for kind-based queries without full parsing.
+  A filter prefixed with ! rejects matches, and outer > inner selects
+  symbols directly contained in a symbol matching outer. Selectors
+  separated by commas outside brackets, or added with --or, are combined:
+  a symbol matching any of them is reported once.
 
 AUTO-DETECTION:
   The engine is chosen automatically based on the pattern syntax:
   - Starts with "find " or contains $+letter → structural
-  - Matches word[ or bare tree-sitter kind      → selector
+  - Matches word[, kind > kind, or bare kind    → selector
   - Otherwise                                    → structural
 
-  Use --structural/-S or --selector to force a specific engine.`,
+  Use --structural/-S or --selector to force a specific engine.
+
+OUTPUT:
+  --format vimgrep prints each match as file:line:col: text, the format
+  Vim's quickfix list (vim -q, :cexpr) and VS Code problem matchers read,
+  with paths relative to the working directory.
+
+SAVED QUERIES:
+  A pattern of the form @name runs the query called name from the nearest
+  .gts/queries.yaml (see "gts search queries list"). The query may set its
+  mode, lang, and where clause; command-line flags override them.`,
 		Example: `  # Structural mode — find Go functions returning error
   gts grep 'func $NAME($$$) error' pkg/
 
@@ -119,25 +143,75 @@ AUTO-DETECTION:
   # Selector mode — methods by receiver
   gts grep 'method_definition[receiver=/Server/]' internal/api/
 
+  # Selector mode — unexported functions only
+  gts grep 'function_definition[exported=false]' pkg/
+
+  # Selector mode — Flask routes, by decorator
+  gts grep 'function_definition[attr=/@app\.route/]' app/
+
+  # Selector mode — functions not named Test*
+  gts grep 'function_definition[!name=/^Test/]' pkg/
+
+  # Selector mode — handle* methods declared in a class
+  gts grep 'class_definition > method_definition[name=/^handle/]' app/
+
+  # Selector mode — functions and types named Old*, in one pass
+  gts grep 'function_definition[name=/^Old/]' --or 'type_definition[name=/^Old/]' pkg/
+
+  # Saved query from .gts/queries.yaml
+  gts grep @handlers internal/api/
+
+  # Load matches into Vim's quickfix list
+  vim -q <(gts grep --format vimgrep 'function_definition[name=/^Test/]' .)
+
   # Force a specific mode
   gts grep -S 'error' pkg/
   gts grep --selector 'type_definition' pkg/`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			pattern := args[0]
-			target := "."
+			target := defaultTarget()
 			if len(args) == 2 {
 				target = args[1]
 			}
 
+			// Expand a saved query from .gts/queries.yaml. Flags given on
+			// the command line win over the query's own settings.
+			presetMode := ""
+			if name, ok := strings.CutPrefix(pattern, "@"); ok {
+				preset, err := resolveGrepPreset(name, target)
+				if err != nil {
+					return err
+				}
+				pattern = preset.Pattern
+				presetMode = preset.Mode
+				if lang == "" {
+					lang = preset.Lang
+				}
+				if where == "" {
+					where = preset.Where
+				}
+			}
+
+			outputFmt, err := outputFormat(format, jsonOutput, "text", "json", "vimgrep")
+			if err != nil {
+				return err
+			}
+
 			// Determine mode.
 			mode := grepModeAuto
 			if forceStructural && forceSelector {
 				return fmt.Errorf("cannot use both --structural and --selector")
 			}
-			if forceStructural {
+			if len(orSelectors) > 0 {
+				if forceStructural {
+					return fmt.Errorf("--or is only supported in selector mode")
+				}
+				pattern = strings.Join(append([]string{pattern}, orSelectors...), ", ")
+				mode = grepModeSelector
+			} else if forceStructural || (!forceSelector && presetMode == "structural") {
 				mode = grepModeStructural
-			} else if forceSelector {
+			} else if forceSelector || presetMode == "selector" {
 				mode = grepModeSelector
 			} else {
 				mode = detectGrepMode(pattern)
@@ -158,12 +232,12 @@ AUTO-DETECTION:
 
 			switch mode {
 			case grepModeStructural:
-				return runStructuralGrep(pattern, target, lang, where, rewrite, jsonOutput, countOnly, limit)
+				return runStructuralGrep(pattern, target, lang, where, rewrite, outputFmt, countOnly, limit)
 			case grepModeSelector:
-				return runSelectorGrep(pattern, target, cachePath, noCache, jsonOutput, countOnly, limit)
+				return runSelectorGrep(pattern, target, cachePath, noCache, outputFmt, countOnly, limit)
 			default:
 				// Auto resolved to structural above; this shouldn't happen.
-				return runStructuralGrep(pattern, target, lang, where, rewrite, jsonOutput, countOnly, limit)
+				return runStructuralGrep(pattern, target, lang, where, rewrite, outputFmt, countOnly, limit)
 			}
 		},
 	}
@@ -171,6 +245,7 @@ AUTO-DETECTION:
 	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing (selector mode)")
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, vimgrep (file:line:col: text)")
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of matches")
 	cmd.Flags().BoolVarP(&forceStructural, "structural", "S", false, "force structural mode (code patterns)")
 	cmd.Flags().BoolVar(&forceSelector, "selector", false, "force selector mode (indexed symbol queries)")
@@ -178,11 +253,29 @@ AUTO-DETECTION:
 	cmd.Flags().StringVar(&rewrite, "rewrite", "", "replacement template for structural matches")
 	cmd.Flags().StringVar(&where, "where", "", "where-clause constraint for structural matches")
 	cmd.Flags().IntVar(&limit, "limit", 1000, "maximum number of results (0 for unlimited)")
+	cmd.Flags().StringArrayVar(&orSelectors, "or", nil, "additional selector whose matches are combined with the pattern's (repeatable; selector mode)")
 	return cmd
 }
 
+// resolveGrepPreset looks up a saved query in the queries file that applies
+// to target.
+func resolveGrepPreset(name, target string) (queries.Query, error) {
+	file, err := queries.Load(target)
+	if err != nil {
+		return queries.Query{}, err
+	}
+	if file == nil {
+		return queries.Query{}, fmt.Errorf("query @%s: no %s found in %s or its parents", name, queries.FileName, target)
+	}
+	preset, ok := file.Lookup(name)
+	if !ok {
+		return queries.Query{}, fmt.Errorf("query @%s not found in %s (available: %s)", name, file.Path, strings.Join(file.Names(), ", "))
+	}
+	return preset, nil
+}
+
 // runSelectorGrep runs the original selector-DSL based grep against the structural index.
-func runSelectorGrep(pattern, target, cachePath string, noCache, jsonOutput, countOnly bool, limit int) error {
+func runSelectorGrep(pattern, target, cachePath string, noCache bool, format string, countOnly bool, limit int) error {
 	selector, err := query.ParseSelector(pattern)
 	if err != nil {
 		return err
@@ -197,18 +290,18 @@ func runSelectorGrep(pattern, target, cachePath string, noCache, jsonOutput, cou
 	matches := make([]grepMatch, 0, 256)
 selectorOuter:
 	for _, file := range idx.Files {
-		for _, symbol := range file.Symbols {
-			if !selector.Match(symbol) {
-				continue
+		for _, symbol := range selector.MatchFile(file.Symbols) {
+			match := grepMatch{
+				File:        file.Path,
+				Kind:        symbol.Kind,
+				Name:        symbol.Name,
+				Signature:   symbol.Signature,
+				StartLine:   symbol.StartLine,
+				EndLine:     symbol.EndLine,
+				StartColumn: symbol.StartColumn,
 			}
-			matches = append(matches, grepMatch{
-				File:      file.Path,
-				Kind:      symbol.Kind,
-				Name:      symbol.Name,
-				Signature: symbol.Signature,
-				StartLine: symbol.StartLine,
-				EndLine:   symbol.EndLine,
-			})
+			match.Cell, match.CellLine, _ = file.CellLine(symbol.StartLine)
+			matches = append(matches, match)
 			if limit > 0 && len(matches) >= limit {
 				truncated = true
 				break selectorOuter
@@ -226,24 +319,15 @@ selectorOuter:
 		return matches[i].File < matches[j].File
 	})
 
-	if jsonOutput {
+	if format == "json" {
 		if countOnly {
-			return emitJSON(struct {
-				Mode      string `json:"mode"`
-				Count     int    `json:"count"`
-				Truncated bool   `json:"truncated,omitempty"`
-			}{
+			return emitJSON(report.GrepCountReport{
 				Mode:      "selector",
 				Count:     len(matches),
 				Truncated: truncated,
 			})
 		}
-		return emitJSON(struct {
-			Mode      string      `json:"mode"`
-			Matches   []grepMatch `json:"matches"`
-			Count     int         `json:"count"`
-			Truncated bool        `json:"truncated,omitempty"`
-		}{
+		return emitJSON(report.GrepReport{
 			Mode:      "selector",
 			Matches:   matches,
 			Count:     len(matches),
@@ -260,11 +344,15 @@ selectorOuter:
 	}
 
 	for _, match := range matches {
+		name := match.Name
 		if match.Signature != "" {
-			fmt.Printf("%s:%d:%d %s %s\n", match.File, match.StartLine, match.EndLine, match.Kind, match.Signature)
+			name = match.Signature
+		}
+		if format == "vimgrep" {
+			fmt.Println(vimgrepLine(idx.Root, match.File, match.StartLine, match.StartColumn, match.Kind+" "+name))
 			continue
 		}
-		fmt.Printf("%s:%d:%d %s %s\n", match.File, match.StartLine, match.EndLine, match.Kind, match.Name)
+		fmt.Printf("%s:%d:%d %s %s%s\n", match.File, match.StartLine, match.EndLine, match.Kind, name, cellSuffix(match.Cell, match.CellLine))
 	}
 	if truncated {
 		fmt.Fprintf(os.Stderr, "warning: results truncated at limit=%d, use --limit 0 for all\n", limit)
@@ -273,7 +361,7 @@ selectorOuter:
 }
 
 // runStructuralGrep runs the gotreesitter structural grep engine over a file tree.
-func runStructuralGrep(pattern, target, langName, whereCl, rewriteTpl string, jsonOutput, countOnly bool, limit int) error {
+func runStructuralGrep(pattern, target, langName, whereCl, rewriteTpl, format string, countOnly bool, limit int) error {
 	// Build the full query string for the gotreesitter grep engine.
 	// If the pattern already starts with "find", use it directly (full query form).
 	// Otherwise, construct the query from flags.
@@ -359,11 +447,13 @@ structuralOuter:
 			}
 
 			matches = append(matches, structuralGrepMatch{
-				File:      relPath,
-				StartLine: startLine,
-				EndLine:   endLine,
-				Text:      matchText,
-				Captures:  caps,
+				File:        relPath,
+				StartLine:   startLine,
+				EndLine:     endLine,
+				StartColumn: byteOffsetToColumn(pf.Source, result.StartByte),
+				EndColumn:   byteOffsetToColumn(pf.Source, result.EndByte),
+				Text:        matchText,
+				Captures:    caps,
 			})
 			if limit > 0 && len(matches) >= limit {
 				truncated = true
@@ -400,25 +490,15 @@ structuralOuter:
 	})
 
 	// Output.
-	if jsonOutput {
+	if format == "json" {
 		if countOnly {
-			return emitJSON(struct {
-				Mode      string `json:"mode"`
-				Count     int    `json:"count"`
-				Truncated bool   `json:"truncated,omitempty"`
-			}{
+			return emitJSON(report.GrepCountReport{
 				Mode:      "structural",
 				Count:     len(matches),
 				Truncated: truncated,
 			})
 		}
-		return emitJSON(struct {
-			Mode      string                    `json:"mode"`
-			Matches   []structuralGrepMatch     `json:"matches"`
-			Count     int                       `json:"count"`
-			Truncated bool                      `json:"truncated,omitempty"`
-			Edits     []structuralRewriteResult `json:"edits,omitempty"`
-		}{
+		return emitJSON(report.StructuralGrepReport{
 			Mode:      "structural",
 			Matches:   matches,
 			Count:     len(matches),
@@ -436,6 +516,10 @@ structuralOuter:
 	}
 
 	for _, m := range matches {
+		if format == "vimgrep" {
+			fmt.Println(vimgrepLine(absTarget, m.File, m.StartLine, m.StartColumn, m.Text))
+			continue
+		}
 		fmt.Printf("%s:%d :: %s\n", m.File, m.StartLine, m.Text)
 		if len(m.Captures) > 0 {
 			// Sort capture names for deterministic output.
@@ -466,12 +550,6 @@ structuralOuter:
 	return nil
 }
 
-// structuralRewriteResult holds rewrite edits for a single file.
-type structuralRewriteResult struct {
-	File  string       `json:"file"`
-	Edits []tsgrep.Edit `json:"edits"`
-}
-
 // buildStructuralQuery constructs a full query string from a pattern and optional flags.
 func buildStructuralQuery(pattern, langName, whereCl, rewriteTpl string) string {
 	trimmed := strings.TrimSpace(pattern)
@@ -520,6 +598,14 @@ func byteOffsetToLine(source []byte, offset uint32) int {
 	return bytes.Count(source[:offset], []byte("\n")) + 1
 }
 
+// byteOffsetToColumn converts a byte offset to a 1-based byte column.
+func byteOffsetToColumn(source []byte, offset uint32) int {
+	if offset > uint32(len(source)) {
+		offset = uint32(len(source))
+	}
+	return int(offset) - bytes.LastIndexByte(source[:offset], '\n')
+}
+
 func runGrep(args []string) error {
 	cmd := newGrepCmd()
 	cmd.SilenceUsage = true
@@ -0,0 +1,14 @@
+package main
+
+import "github.com/spf13/cobra"
+
+func newAuditGroup() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Policy audits of risky calls",
+	}
+	cmd.AddCommand(
+		newAuditExitsCmd(),
+	)
+	return cmd
+}
@@ -10,6 +10,7 @@ func newGraphGroup() *cobra.Command {
 	cmd.AddCommand(
 		newCallgraphCmd(),
 		newDeadCmd(),
+		newUnusedFieldsCmd(),
 		newDepsCmd(),
 		newBridgeCmd(),
 		newImpactCmd(),
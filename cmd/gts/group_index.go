@@ -1,12 +1,38 @@
 package main
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
 
 func newIndexGroup() *cobra.Command {
+	opts := indexBuildOpts{incremental: true, maxFileSize: "4MB"}
+
 	cmd := &cobra.Command{
 		Use:   "index",
 		Short: "Build, inspect, and compare structural indexes",
+		Long: `Build, inspect, and compare structural indexes.
+
+gts index --verify [path] checks the --out cache against the working tree
+without rebuilding and exits 2 when it is stale, like gts index build --verify.
+Pass the build's scanning flags (--ignore, --follow-symlinks, ...) through
+gts index build --verify instead.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !opts.verify {
+				if len(args) > 0 {
+					return fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath())
+				}
+				return cmd.Help()
+			}
+			return runIndexBuild(args, opts)
+		},
 	}
+	cmd.Flags().BoolVar(&opts.verify, "verify", false, "check the --out cache against the working tree without rebuilding; exit 2 when stale")
+	cmd.Flags().StringVar(&opts.outPath, "out", ".gts/index.json", "index cache to verify")
+	cmd.Flags().BoolVar(&opts.jsonOutput, "json", false, "emit the verification report as JSON")
+
 	cmd.AddCommand(
 		newIndexBuildCmd(),
 		newMapCmd(),
@@ -17,6 +43,7 @@ func newIndexGroup() *cobra.Command {
 		newValidateCmd(),
 		newExportCmd(),
 		newImportCmd(),
+		newIndexMergeCmd(),
 	)
 	return cmd
 }
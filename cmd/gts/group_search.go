@@ -15,6 +15,7 @@ func newSearchGroup() *cobra.Command {
 		newContextCmd(),
 		newSymbolsCmd(),
 		newImportsCmd(),
+		newQueriesCmd(),
 	)
 	return cmd
 }
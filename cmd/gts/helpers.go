@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,19 +10,36 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/internal/membudget"
+	"github.com/odvcencio/gts-suite/internal/resultcache"
+	"github.com/odvcencio/gts-suite/pkg/annotate"
 	"github.com/odvcencio/gts-suite/pkg/index"
 	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
 func loadOrBuild(cachePath string, target string, noCache bool) (*model.Index, error) {
+	return loadOrBuildWithin(cachePath, target, noCache, 0)
+}
+
+// loadOrBuildWithin is loadOrBuild with a memory budget of maxMemory bytes
+// (zero for none) bounding how many files a fresh build parses at once.
+func loadOrBuildWithin(cachePath string, target string, noCache bool, maxMemory int64) (*model.Index, error) {
 	if strings.TrimSpace(cachePath) != "" {
 		return index.Load(cachePath)
 	}
 	if !noCache {
-		autoPath := filepath.Join(target, ".gts", "index.json")
+		if idx, ok := loadDaemonIndex(target); ok {
+			fmt.Fprintf(os.Stderr, "index: using warm index from gts daemon (%d files)\n", idx.FileCount())
+			return idx, nil
+		}
+		autoPath := projectConfig.CachePath(target)
 		if fi, err := os.Stat(autoPath); err == nil {
-			if idx, loadErr := index.Load(autoPath); loadErr == nil {
+			idx, loadErr := index.Load(autoPath)
+			var incompatible *index.IncompatibleError
+			switch {
+			case loadErr == nil:
 				age := time.Since(fi.ModTime()).Truncate(time.Second)
 				if idx.ConfigHashes == nil {
 					// Old cache without config tracking — use it but suggest rebuild
@@ -35,6 +52,8 @@ func loadOrBuild(cachePath string, target string, noCache bool) (*model.Index, e
 					return idx, nil
 				}
 				fmt.Fprintf(os.Stderr, "index: config changed since last build, rebuilding...\n")
+			case errors.As(loadErr, &incompatible):
+				fmt.Fprintf(os.Stderr, "index: ignoring cached %s: written by %s in format %s; rebuilding...\n", autoPath, incompatible.Writer(), incompatible.SchemaVersion)
 			}
 		}
 	}
@@ -42,9 +61,49 @@ func loadOrBuild(cachePath string, target string, noCache bool) (*model.Index, e
 	if err != nil {
 		return nil, err
 	}
+	if maxMemory > 0 {
+		builder.SetMaxConcurrent(membudget.Workers(maxMemory))
+	}
 	return builder.BuildPath(target)
 }
 
+// applyMaxMemory parses a --max-memory value such as 3GB and caps the Go
+// heap at it. It returns the budget in bytes, zero when raw is empty, and a
+// function restoring the previous limit.
+func applyMaxMemory(raw string) (int64, func(), error) {
+	limit, err := parseByteSize(raw)
+	if err != nil {
+		return 0, nil, fmt.Errorf("--max-memory: %w", err)
+	}
+	return limit, membudget.Apply(limit), nil
+}
+
+// cachedResult returns compute's result for idx. With enabled, a result
+// stored under .gts/results for the same index contents, gts version,
+// operation, and params is returned instead, and a computed one is stored.
+func cachedResult[T any](enabled bool, idx *model.Index, operation string, params any, compute func() (T, error)) (T, error) {
+	if !enabled {
+		return compute()
+	}
+	key, ok := resultcache.Key(idx, version+"/"+operation, params)
+	if !ok {
+		return compute()
+	}
+	cache := resultcache.ForIndex(idx)
+	var cached T
+	if cache.Load(key, &cached) {
+		return cached, nil
+	}
+	result, err := compute()
+	if err != nil {
+		return result, err
+	}
+	if err := cache.Store(key, result); err != nil {
+		fmt.Fprintf(os.Stderr, "result cache: %v\n", err)
+	}
+	return result, nil
+}
+
 func configHashesMatch(cached, current map[string]string) bool {
 	if len(cached) != len(current) {
 		return false
@@ -57,10 +116,55 @@ func configHashesMatch(cached, current map[string]string) bool {
 	return true
 }
 
+// emitJSON prints value as indented JSON, adding schema_version to objects;
+// see report.Marshal.
 func emitJSON(value any) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(value)
+	data, err := report.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// writeAnnotations prints findings as GitHub workflow commands or a GitLab
+// Code Quality report, for --format github|gitlab.
+func writeAnnotations(format string, annotations []annotate.Annotation) error {
+	if format == "gitlab" {
+		return annotate.WriteGitLab(os.Stdout, annotations)
+	}
+	return annotate.WriteGitHub(os.Stdout, annotations)
+}
+
+// vimgrepLine renders a match as "file:line:col: text", the format of Vim's
+// :vimgrep and ripgrep --vimgrep that editors load into a quickfix list. file
+// is relative to root and is printed relative to the working directory when
+// it lies below it, so an editor started there can open it.
+func vimgrepLine(root, file string, line, column int, text string) string {
+	path := file
+	if root != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	if cwd, err := os.Getwd(); err == nil && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(cwd, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			path = rel
+		}
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", filepath.ToSlash(path), line, max(column, 1), text)
+}
+
+// outputFormat resolves a --format flag that accepts the given formats,
+// the first being the default, against --json, which selects "json".
+func outputFormat(format string, jsonOutput bool, formats ...string) (string, error) {
+	if jsonOutput && format == formats[0] {
+		format = "json"
+	}
+	for _, candidate := range formats {
+		if format == candidate {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported --format %q (expected %s)", format, strings.Join(formats, "|"))
 }
 
 func compactNodeText(text string) string {
@@ -101,6 +205,12 @@ func applyGeneratedFilter(cmd *cobra.Command, idx *model.Index) *model.Index {
 	return idx.WithoutGenerated()
 }
 
+// addPathFlag registers the repeatable --path filter, which narrows an index
+// to a subsystem before computing metrics over it.
+func addPathFlag(cmd *cobra.Command, patterns *[]string) {
+	cmd.Flags().StringArrayVar(patterns, "path", nil, "only include files matching glob; dir/... selects a subtree and a leading ! excludes (repeatable)")
+}
+
 // generatedFileMap builds a path → GeneratedInfo lookup from the index.
 func generatedFileMap(idx *model.Index) map[string]*model.GeneratedInfo {
 	m := make(map[string]*model.GeneratedInfo, len(idx.Files))
@@ -0,0 +1,548 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/deps"
+	"github.com/odvcencio/gts-suite/internal/lint"
+	"github.com/odvcencio/gts-suite/pkg/boundaries"
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/structdiff"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// hookMarker identifies hook scripts written by gts hook install.
+const hookMarker = "# installed by gts hook install"
+
+var hookCheckNames = []string{"lint", "dead", "boundaries"}
+
+type hookFinding struct {
+	Check   string `json:"check"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+
+	key string
+}
+
+type hookResult struct {
+	Status   string        `json:"status"` // PASS, FAIL, or SKIP
+	Stage    string        `json:"stage"`
+	Base     string        `json:"base,omitempty"`
+	Files    int           `json:"files"`
+	Checks   []string      `json:"checks"`
+	Reason   string        `json:"reason,omitempty"`
+	Findings []hookFinding `json:"findings,omitempty"`
+}
+
+func newHookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Run gts checks from git pre-commit and pre-push hooks",
+		Long: `Run gts checks from git pre-commit and pre-push hooks.
+
+"gts hook install" writes a hook script that calls "gts hook run". The run
+mode materializes the staged snapshot (pre-commit) or HEAD (pre-push) from
+git, so unstaged edits never affect the result, and compares it with the
+previous snapshot (HEAD, or the merge base with --base). Only findings in
+changed files that did not already exist in the previous snapshot are
+reported, one "file:line: check: message" line each. When the change has no
+structural effect the checks are skipped unless --always is set.
+
+Checks:
+  lint        .gtslint rules and built-in complexity thresholds
+  dead        unexported callables with no callers
+  boundaries  .gtsboundaries import rules`,
+	}
+	cmd.AddCommand(newHookInstallCmd(), newHookRunCmd())
+	return cmd
+}
+
+func newHookInstallCmd() *cobra.Command {
+	var stage string
+	var checks []string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "install [path]",
+		Short: "Install a git hook that runs gts hook run",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := defaultTarget()
+			if len(args) == 1 {
+				target = args[0]
+			}
+			if err := validateHookStage(stage); err != nil {
+				return err
+			}
+			if _, err := parseHookChecks(checks); err != nil {
+				return err
+			}
+
+			hookPath, err := gitCommandOutput(target, "rev-parse", "--git-path", filepath.Join("hooks", stage))
+			if err != nil {
+				return fmt.Errorf("locate git hooks: %w", err)
+			}
+			if !filepath.IsAbs(hookPath) {
+				hookPath = filepath.Join(target, hookPath)
+			}
+			if existing, err := os.ReadFile(hookPath); err == nil && !force && !bytes.Contains(existing, []byte(hookMarker)) {
+				return fmt.Errorf("%s already exists and was not installed by gts; use --force to replace it", hookPath)
+			}
+
+			script := fmt.Sprintf("#!/bin/sh\n%s\nexec gts hook run --stage %s --checks %s\n", hookMarker, stage, strings.Join(checks, ","))
+			if err := os.MkdirAll(filepath.Dir(hookPath), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(hookPath, []byte(script), 0o755); err != nil {
+				return err
+			}
+			fmt.Printf("hook: installed %s\n", hookPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&stage, "stage", "pre-commit", "hook to install: pre-commit or pre-push")
+	cmd.Flags().StringSliceVar(&checks, "checks", hookCheckNames, "checks the hook runs (lint, dead, boundaries)")
+	cmd.Flags().BoolVar(&force, "force", false, "replace an existing hook not installed by gts")
+	return cmd
+}
+
+func newHookRunCmd() *cobra.Command {
+	var stage string
+	var checks []string
+	var base string
+	var always bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "run [path]",
+		Short: "Run hook checks against staged or pushed changes",
+		Args:  cobra.ArbitraryArgs, // git passes remote name and URL to pre-push hooks
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := defaultTarget()
+			if len(args) == 1 && stage == "pre-commit" {
+				target = args[0]
+			}
+			if err := validateHookStage(stage); err != nil {
+				return err
+			}
+			enabled, err := parseHookChecks(checks)
+			if err != nil {
+				return err
+			}
+
+			result, err := runHookChecks(target, stage, base, enabled, always)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				if err := emitJSON(result); err != nil {
+					return err
+				}
+			} else {
+				for _, finding := range result.Findings {
+					location := finding.File
+					if finding.Line > 0 {
+						location = fmt.Sprintf("%s:%d", finding.File, finding.Line)
+					}
+					fmt.Printf("%s: %s: %s\n", location, finding.Check, finding.Message)
+				}
+				summary := fmt.Sprintf("gts hook %s: %s (%d findings, %d changed files)", result.Stage, result.Status, len(result.Findings), result.Files)
+				if result.Reason != "" {
+					summary += ": " + result.Reason
+				}
+				fmt.Fprintln(os.Stderr, summary)
+			}
+
+			if result.Status == "FAIL" {
+				return exitCodeError{code: 1, err: fmt.Errorf("hook checks found %d problems", len(result.Findings))}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&stage, "stage", "pre-commit", "hook stage: pre-commit (staged files) or pre-push (commits not yet upstream)")
+	cmd.Flags().StringSliceVar(&checks, "checks", hookCheckNames, "checks to run (lint, dead, boundaries)")
+	cmd.Flags().StringVar(&base, "base", "", "ref to compare against in pre-push mode (default: upstream branch)")
+	cmd.Flags().BoolVar(&always, "always", false, "run checks even when the change has no structural effect")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+func runHook(args []string) error {
+	cmd := newHookCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+func validateHookStage(stage string) error {
+	switch stage {
+	case "pre-commit", "pre-push":
+		return nil
+	default:
+		return fmt.Errorf("unsupported --stage %q (expected pre-commit|pre-push)", stage)
+	}
+}
+
+func parseHookChecks(checks []string) (map[string]bool, error) {
+	enabled := map[string]bool{}
+	for _, check := range checks {
+		check = strings.TrimSpace(check)
+		switch check {
+		case "":
+			continue
+		case "lint", "dead", "boundaries":
+			enabled[check] = true
+		default:
+			return nil, fmt.Errorf("unsupported check %q (expected lint|dead|boundaries)", check)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil, fmt.Errorf("at least one check is required")
+	}
+	return enabled, nil
+}
+
+func runHookChecks(target, stage, base string, enabled map[string]bool, always bool) (hookResult, error) {
+	result := hookResult{Status: "PASS", Stage: stage}
+	for _, name := range hookCheckNames {
+		if enabled[name] {
+			result.Checks = append(result.Checks, name)
+		}
+	}
+
+	prefix, err := gitCommandOutput(target, "rev-parse", "--show-prefix")
+	if err != nil {
+		return result, fmt.Errorf("hook requires a git repository: %w", err)
+	}
+
+	// after is the tree being committed or pushed; before is what it
+	// replaces. An empty before tree stands for the initial commit.
+	var afterTree, beforeTree string
+	var diffArgs []string
+	switch stage {
+	case "pre-commit":
+		afterTree, err = gitCommandOutput(target, "write-tree")
+		if err != nil {
+			return result, fmt.Errorf("read staged tree: %w", err)
+		}
+		beforeTree, _ = gitCommandOutput(target, "rev-parse", "--verify", "--quiet", "HEAD^{tree}")
+		diffArgs = []string{"diff", "--cached"}
+	case "pre-push":
+		if base == "" {
+			base, err = gitCommandOutput(target, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+			if err != nil {
+				return result, fmt.Errorf("no upstream branch; pass --base")
+			}
+		}
+		result.Base = base
+		mergeBase, err := gitCommandOutput(target, "merge-base", base, "HEAD")
+		if err != nil {
+			return result, fmt.Errorf("merge-base %s HEAD: %w", base, err)
+		}
+		afterTree, err = gitCommandOutput(target, "rev-parse", "HEAD^{tree}")
+		if err != nil {
+			return result, err
+		}
+		beforeTree, _ = gitCommandOutput(target, "rev-parse", mergeBase+"^{tree}")
+		diffArgs = []string{"diff", mergeBase, "HEAD"}
+	}
+
+	out, err := gitCommandOutput(target, append(diffArgs, "--name-only", "--relative", "--diff-filter=ACMR")...)
+	if err != nil {
+		return result, err
+	}
+	changed := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			changed[line] = true
+		}
+	}
+	result.Files = len(changed)
+	if len(changed) == 0 {
+		result.Status = "SKIP"
+		result.Reason = "no changed files"
+		return result, nil
+	}
+
+	afterIdx, cleanupAfter, err := indexGitTree(target, afterTree, prefix)
+	if err != nil {
+		return result, err
+	}
+	defer cleanupAfter()
+	afterIdx = afterIdx.WithoutGenerated()
+	beforeIdx := &model.Index{}
+	if beforeTree != "" {
+		var cleanupBefore func()
+		beforeIdx, cleanupBefore, err = indexGitTree(target, beforeTree, prefix)
+		if err != nil {
+			return result, err
+		}
+		defer cleanupBefore()
+		beforeIdx = beforeIdx.WithoutGenerated()
+	}
+
+	if !always {
+		diff := structdiff.Compare(beforeIdx, afterIdx)
+		if diff.Stats.ChangedFiles == 0 && parseErrorsEqual(beforeIdx.Errors, afterIdx.Errors) {
+			result.Status = "SKIP"
+			result.Reason = "no structural changes"
+			return result, nil
+		}
+	}
+
+	collect := func(idx *model.Index, files map[string]bool) ([]hookFinding, error) {
+		var findings []hookFinding
+		if enabled["lint"] {
+			found, err := hookLintFindings(idx, files)
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, found...)
+		}
+		if enabled["dead"] {
+			found, err := hookDeadFindings(idx, files)
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, found...)
+		}
+		if enabled["boundaries"] {
+			found, err := hookBoundaryFindings(idx, files)
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, found...)
+		}
+		return findings, nil
+	}
+
+	after, err := collect(afterIdx, changed)
+	if err != nil {
+		return result, err
+	}
+	existing := map[string]bool{}
+	if len(beforeIdx.Files) > 0 {
+		before, err := collect(beforeIdx, changed)
+		if err != nil {
+			return result, err
+		}
+		for _, finding := range before {
+			existing[finding.key] = true
+		}
+	}
+	for _, finding := range after {
+		if !existing[finding.key] {
+			result.Findings = append(result.Findings, finding)
+		}
+	}
+	sort.Slice(result.Findings, func(i, j int) bool {
+		left, right := result.Findings[i], result.Findings[j]
+		if left.File != right.File {
+			return left.File < right.File
+		}
+		if left.Line != right.Line {
+			return left.Line < right.Line
+		}
+		return left.Check < right.Check
+	})
+	if len(result.Findings) > 0 {
+		result.Status = "FAIL"
+	}
+	return result, nil
+}
+
+// indexGitTree checks tree out into a temporary directory through a
+// throwaway git index, leaving the repository's own index and worktree
+// untouched, and indexes the part of it under prefix.
+func indexGitTree(repoDir, tree, prefix string) (*model.Index, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "gts-hook-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+
+	env := append(os.Environ(), "GIT_INDEX_FILE="+filepath.Join(tmpDir, "index"))
+	checkoutDir := filepath.Join(tmpDir, "tree")
+	for _, args := range [][]string{
+		{"read-tree", tree},
+		{"checkout-index", "--all", "--force", "--prefix=" + checkoutDir + "/"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", repoDir}, args...)...)
+		cmd.Env = env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	root := filepath.Join(checkoutDir, filepath.FromSlash(prefix))
+	if _, err := os.Stat(root); err != nil {
+		cleanup()
+		return &model.Index{Root: root}, func() {}, nil
+	}
+	builder, err := index.NewBuilderWithWorkspaceIgnores(root)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	idx, err := builder.BuildPath(root)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return idx, cleanup, nil
+}
+
+func hookLintFindings(idx *model.Index, files map[string]bool) ([]hookFinding, error) {
+	lintCfg, err := lint.LoadConfig(idx.Root)
+	if err != nil {
+		return nil, fmt.Errorf("loading .gtslint: %w", err)
+	}
+	thresholds := make([]lint.ThresholdRule, len(lint.DefaultRules))
+	copy(thresholds, lint.DefaultRules)
+	var rules []lint.Rule
+	if lintCfg != nil {
+		rules = lintCfg.Rules
+		for _, override := range lintCfg.Overrides {
+			if override.Scope != "" {
+				continue
+			}
+			for i := range thresholds {
+				if thresholds[i].Metric == override.Metric {
+					thresholds[i].Threshold = override.Threshold
+				}
+			}
+		}
+	}
+
+	violations := lint.Evaluate(idx.FilterByPaths(files), rules)
+	thresholdViolations, err := lint.EvaluateThresholdsInFiles(idx, thresholds, files)
+	if err != nil {
+		return nil, err
+	}
+	violations = append(violations, thresholdViolations...)
+
+	findings := make([]hookFinding, 0, len(violations))
+	for _, v := range violations {
+		if lintCfg != nil && lintCfg.ShouldIgnore(v.File, v.Name, v.RuleID) {
+			continue
+		}
+		message := v.RuleID
+		if v.Message != "" {
+			message += " " + v.Message
+		}
+		if v.Name != "" {
+			message += " (" + v.Name + ")"
+		}
+		findings = append(findings, hookFinding{
+			Check:   "lint",
+			File:    v.File,
+			Line:    v.StartLine,
+			Name:    v.Name,
+			Message: message,
+			key:     "lint|" + v.RuleID + "|" + v.File + "|" + v.Name,
+		})
+	}
+	return findings, nil
+}
+
+// hookDeadFindings reports unexported callables in files that nothing calls.
+// Exported ones are left out because callers may live outside the tree.
+func hookDeadFindings(idx *model.Index, files map[string]bool) ([]hookFinding, error) {
+	graph, err := xref.Build(idx)
+	if err != nil {
+		return nil, err
+	}
+	var findings []hookFinding
+	topLevel := graph.TopLevelCallNames()
+	for _, definition := range graph.Definitions {
+		if !files[definition.File] || !definition.Callable || definition.Exported {
+			continue
+		}
+		if isEntrypointDefinition(definition, topLevel) || isTestSourceFile(definition.File) {
+			continue
+		}
+		if graph.IncomingCount(definition.ID) > 0 {
+			continue
+		}
+		findings = append(findings, hookFinding{
+			Check:   "dead",
+			File:    definition.File,
+			Line:    definition.StartLine,
+			Name:    definition.Name,
+			Message: fmt.Sprintf("%s %s has no callers", definition.Kind, definition.Name),
+			key:     "dead|" + definition.File + "|" + definition.Name,
+		})
+	}
+	return findings, nil
+}
+
+// hookBoundaryFindings reports .gtsboundaries violations by packages that
+// contain changed files, attributed to the first such file.
+func hookBoundaryFindings(idx *model.Index, files map[string]bool) ([]hookFinding, error) {
+	cfg, err := boundaries.LoadConfig(idx.Root)
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	report, err := deps.Build(idx, deps.Options{Mode: "package", IncludeEdges: true})
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]boundaries.ImportEdge, 0, len(report.Edges))
+	for _, e := range report.Edges {
+		if e.Internal {
+			edges = append(edges, boundaries.ImportEdge{From: e.From, To: e.To})
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var findings []hookFinding
+	for _, v := range boundaries.Evaluate(cfg, edges) {
+		for _, path := range paths {
+			if strings.HasPrefix(filepath.ToSlash(filepath.Dir(path)), v.From) {
+				findings = append(findings, hookFinding{
+					Check:   "boundaries",
+					File:    path,
+					Message: v.Message,
+					key:     "boundaries|" + v.From + "|" + v.To,
+				})
+				break
+			}
+		}
+	}
+	return findings, nil
+}
+
+func gitCommandOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/odvcencio/gts-suite/pkg/hotspot"
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
 func newHotspotCmd() *cobra.Command {
@@ -22,7 +23,7 @@ func newHotspotCmd() *cobra.Command {
 		Short:   "Detect code hotspots from git churn, complexity, and call graph centrality",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -39,26 +40,24 @@ func newHotspotCmd() *cobra.Command {
 				Top:   top,
 			}
 
-			report, err := hotspot.Analyze(idx, opts)
+			result, err := hotspot.Analyze(idx, opts)
 			if err != nil {
 				return err
 			}
 
 			if jsonOutput {
 				if countOnly {
-					return emitJSON(struct {
-						Count int `json:"count"`
-					}{Count: report.Count})
+					return emitJSON(report.CountReport{Count: result.Count})
 				}
-				return emitJSON(report)
+				return emitJSON(result)
 			}
 
 			if countOnly {
-				fmt.Println(report.Count)
+				fmt.Println(result.Count)
 				return nil
 			}
 
-			for _, h := range report.Functions {
+			for _, h := range result.Functions {
 				fmt.Printf(
 					"%s:%d-%d %s  score=%.3f churn=%.2f complexity=%.2f centrality=%.2f  commits=%d authors=%d cyclomatic=%d fan_in=%d\n",
 					h.File, h.StartLine, h.EndLine, h.Name,
@@ -66,7 +65,7 @@ func newHotspotCmd() *cobra.Command {
 					h.Commits, h.Authors, h.Cyclomatic, h.FanIn,
 				)
 			}
-			fmt.Printf("hotspot: count=%d\n", report.Count)
+			fmt.Printf("hotspot: count=%d\n", result.Count)
 			return nil
 		},
 	}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/pkg/hotspot"
+	"github.com/odvcencio/gts-suite/pkg/report"
+)
+
+func newHotspotsCmd() *cobra.Command {
+	var cachePath string
+	var noCache bool
+	var jsonOutput bool
+	var countOnly bool
+	var noGit bool
+	var since string
+	var sortBy string
+	var minRefs int
+	var top int
+
+	cmd := &cobra.Command{
+		Use:   "hotspots [path]",
+		Short: "Rank definitions by incoming references, calling packages, and churn",
+		Long: `Rank callable definitions by how much of the codebase depends on them:
+incoming calls, the number of distinct packages calling them, and the git
+churn of their files. The top entries are load-bearing code that deserves
+extra tests and review.
+
+Unlike gts analyze hotspot, which weighs complexity, hotspots ranks by
+usage. --no-git ranks by references and packages alone.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := defaultTarget()
+			if len(args) == 1 {
+				target = args[0]
+			}
+
+			idx, err := loadOrBuild(cachePath, target, noCache)
+			if err != nil {
+				return err
+			}
+			idx = applyGeneratedFilter(cmd, idx)
+
+			result, err := hotspot.Usage(idx, hotspot.UsageOptions{
+				Root:          target,
+				Since:         since,
+				NoGit:         noGit,
+				MinReferences: minRefs,
+				Top:           top,
+				Sort:          strings.ToLower(strings.TrimSpace(sortBy)),
+			})
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				if countOnly {
+					return emitJSON(report.CountReport{Count: result.Count})
+				}
+				return emitJSON(result)
+			}
+
+			if countOnly {
+				fmt.Println(result.Count)
+				return nil
+			}
+
+			for _, e := range result.Definitions {
+				name := strings.TrimSpace(e.Signature)
+				if name == "" {
+					name = e.Name
+				}
+				churn := ""
+				if result.Churn {
+					churn = fmt.Sprintf(" commits=%d authors=%d", e.Commits, e.Authors)
+				}
+				fmt.Printf("%s:%d %s  score=%.3f refs=%d callers=%d packages=%d%s\n",
+					e.File, e.StartLine, name, e.Score, e.References, e.Callers, len(e.CallerPackages), churn)
+			}
+			fmt.Printf("hotspots: count=%d churn=%t\n", result.Count, result.Churn)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of ranked definitions")
+	cmd.Flags().BoolVar(&noGit, "no-git", false, "skip git churn and rank by references and packages only")
+	cmd.Flags().StringVar(&since, "since", "90d", "git log period for churn (e.g. 90d, 6m, 1y)")
+	cmd.Flags().StringVar(&sortBy, "sort", "score", "ranking: score|refs|packages|churn")
+	cmd.Flags().IntVar(&minRefs, "min-refs", 1, "drop definitions with fewer incoming calls")
+	cmd.Flags().IntVar(&top, "top", 20, "limit to top N results (0 for all)")
+	return cmd
+}
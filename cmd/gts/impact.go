@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/odvcencio/gts-suite/pkg/impact"
+	"github.com/odvcencio/gts-suite/pkg/model"
 )
 
 func newImpactCmd() *cobra.Command {
@@ -19,14 +20,48 @@ func newImpactCmd() *cobra.Command {
 	var maxDepth int
 	var countOnly bool
 	var kind string
+	var beforeCache string
+	var afterCache string
 
 	cmd := &cobra.Command{
 		Use:     "impact [symbol] [path]",
 		Aliases: []string{"gtsimpact"},
 		Short:   "Compute blast radius of changed symbols via reverse call graph",
-		Args:    cobra.MaximumNArgs(2),
+		Long: `Compute blast radius of changed symbols via reverse call graph.
+
+Changed symbols come from --changed, a symbol argument, --diff <git-ref>, or
+two index snapshots given with --before-cache and --after-cache. In snapshot
+mode the structural diff between the caches supplies the changed definitions.
+
+Examples:
+  gts impact ParseConfig
+  gts impact --diff HEAD~1 .
+  gts impact --before-cache main.json --after-cache pr.json --depth 4 --json`,
+		Args: cobra.MaximumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			if (beforeCache == "") != (afterCache == "") {
+				return fmt.Errorf("--before-cache and --after-cache must be used together")
+			}
+			if beforeCache != "" {
+				if len(args) > 0 || strings.TrimSpace(changed) != "" || strings.TrimSpace(diffRef) != "" {
+					return fmt.Errorf("--before-cache/--after-cache cannot be combined with symbols, --changed, or --diff")
+				}
+				before, err := loadOrBuild(beforeCache, ".", true)
+				if err != nil {
+					return fmt.Errorf("load before snapshot: %w", err)
+				}
+				after, err := loadOrBuild(afterCache, ".", true)
+				if err != nil {
+					return fmt.Errorf("load after snapshot: %w", err)
+				}
+				result, err := impact.AnalyzeSnapshots(before, after, maxDepth)
+				if err != nil {
+					return err
+				}
+				return printImpactResult(result, generatedFileMap(after), kind, jsonOutput, countOnly)
+			}
+
+			target := defaultTarget()
 			switch len(args) {
 			case 2:
 				// gts impact <symbol> <path>
@@ -71,61 +106,7 @@ func newImpactCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-
-			if kind != "" {
-				var prefix string
-				switch strings.ToLower(kind) {
-				case "function":
-					prefix = "function"
-				case "method":
-					prefix = "method"
-				default:
-					return fmt.Errorf("unsupported --kind %q (expected function|method)", kind)
-				}
-				filtered := result.Affected[:0]
-				for _, sym := range result.Affected {
-					if strings.Contains(sym.Kind, prefix) {
-						filtered = append(filtered, sym)
-					}
-				}
-				result.Affected = filtered
-				result.TotalAffected = len(filtered)
-			}
-
-			genMap := generatedFileMap(idx)
-
-			if countOnly {
-				fmt.Println(result.TotalAffected)
-				return nil
-			}
-
-			if jsonOutput {
-				return emitJSON(result)
-			}
-
-			for _, sym := range result.Affected {
-				prefix := ""
-				if genMap[sym.File] != nil {
-					prefix = "[gen] "
-				}
-				fmt.Printf(
-					"%s%s:%d-%d %s distance=%d risk=%.2f\n",
-					prefix,
-					sym.File,
-					sym.StartLine,
-					sym.EndLine,
-					sym.Name,
-					sym.Distance,
-					sym.Risk,
-				)
-			}
-			fmt.Printf(
-				"impact: changed=%d affected=%d files=%d\n",
-				len(result.Changed),
-				result.TotalAffected,
-				len(result.AffectedFiles),
-			)
-			return nil
+			return printImpactResult(result, generatedFileMap(idx), kind, jsonOutput, countOnly)
 		},
 	}
 
@@ -135,11 +116,92 @@ func newImpactCmd() *cobra.Command {
 	cmd.Flags().StringVar(&changed, "changed", "", "comma-separated list of changed symbol names")
 	cmd.Flags().StringVar(&diffRef, "diff", "", "git diff ref (e.g. HEAD~1)")
 	cmd.Flags().IntVar(&maxDepth, "max-depth", 10, "max reverse walk depth")
+	cmd.Flags().IntVar(&maxDepth, "depth", 10, "alias for --max-depth")
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print only the count of impacted symbols")
 	cmd.Flags().StringVar(&kind, "kind", "", "filter affected symbols by kind (function|method)")
+	cmd.Flags().StringVar(&beforeCache, "before-cache", "", "index snapshot before the change")
+	cmd.Flags().StringVar(&afterCache, "after-cache", "", "index snapshot after the change")
 	return cmd
 }
 
+func runImpact(args []string) error {
+	cmd := newImpactCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+func printImpactResult(result *impact.Result, genMap map[string]*model.GeneratedInfo, kind string, jsonOutput, countOnly bool) error {
+	if kind != "" {
+		var prefix string
+		switch strings.ToLower(kind) {
+		case "function":
+			prefix = "function"
+		case "method":
+			prefix = "method"
+		default:
+			return fmt.Errorf("unsupported --kind %q (expected function|method)", kind)
+		}
+		filtered := result.Affected[:0]
+		for _, sym := range result.Affected {
+			if strings.Contains(sym.Kind, prefix) {
+				filtered = append(filtered, sym)
+			}
+		}
+		result.Affected = filtered
+		result.TotalAffected = len(filtered)
+	}
+
+	if countOnly {
+		fmt.Println(result.TotalAffected)
+		return nil
+	}
+
+	if jsonOutput {
+		return emitJSON(result)
+	}
+
+	for _, change := range result.Changes {
+		fmt.Printf("%s %s:%d-%d %s %s\n", changeMarker(change.Change), change.File, change.StartLine, change.EndLine, change.Kind, change.Name)
+	}
+	for _, sym := range result.Affected {
+		prefix := ""
+		if genMap[sym.File] != nil {
+			prefix = "[gen] "
+		}
+		fmt.Printf(
+			"%s%s:%d-%d %s distance=%d risk=%.2f\n",
+			prefix,
+			sym.File,
+			sym.StartLine,
+			sym.EndLine,
+			sym.Name,
+			sym.Distance,
+			sym.Risk,
+		)
+	}
+	fmt.Printf(
+		"impact: changed=%d affected=%d files=%d packages=%d\n",
+		len(result.Changed),
+		result.TotalAffected,
+		len(result.AffectedFiles),
+		len(result.AffectedPackages),
+	)
+	return nil
+}
+
+func changeMarker(change string) string {
+	switch change {
+	case "added":
+		return "+"
+	case "removed":
+		return "-"
+	default:
+		return "~"
+	}
+}
+
 func looksLikePath(s string) bool {
 	if strings.ContainsAny(s, "/\\") {
 		return true
@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/internal/config"
+	"github.com/odvcencio/gts-suite/internal/membudget"
 	"github.com/odvcencio/gts-suite/pkg/ignore"
 	"github.com/odvcencio/gts-suite/pkg/index"
 	"github.com/odvcencio/gts-suite/pkg/model"
@@ -31,7 +35,11 @@ func loadIndexIgnoreLines(target string) ([]string, error) {
 		}
 		lines = append(lines, strings.Split(string(data), "\n")...)
 	}
-	return lines, nil
+	cfg, err := config.Load(target)
+	if err != nil {
+		return nil, err
+	}
+	return append(lines, cfg.ExcludePatterns()...), nil
 }
 
 type indexBuildOpts struct {
@@ -43,25 +51,61 @@ type indexBuildOpts struct {
 	poll                bool
 	reportChanges       bool
 	onceIfChanged       bool
+	verify              bool
+	followSymlinks      bool
+	noGitignore         bool
+	skipGenerated       bool
+	maxFileSize         string
+	maxMemory           string
 	interval            time.Duration
+	watchPolicy         watchPolicy
 	ignorePatterns      []string
+	rev                 string
+	only                string
+	progress            bool
+	metricsAddr         string
+	execCommand         string
 }
 
 func runIndexBuild(args []string, opts indexBuildOpts) error {
 	if opts.watch && opts.interval <= 0 {
 		return fmt.Errorf("interval must be > 0 in watch mode")
 	}
+	if err := opts.watchPolicy.validate(); err != nil {
+		return err
+	}
 	if opts.watch && opts.onceIfChanged {
 		return fmt.Errorf("--once-if-changed cannot be used with --watch")
 	}
 	if opts.onceIfChanged && strings.TrimSpace(opts.outPath) == "" {
 		return fmt.Errorf("--once-if-changed requires --out to provide a baseline cache path")
 	}
+	if opts.verify && (opts.watch || opts.onceIfChanged) {
+		return fmt.Errorf("--verify cannot be used with --watch or --once-if-changed")
+	}
+	if opts.verify && strings.TrimSpace(opts.outPath) == "" {
+		return fmt.Errorf("--verify requires --out to provide the cache path")
+	}
+	if opts.rev != "" && (opts.watch || opts.verify) {
+		return fmt.Errorf("--rev cannot be used with --watch or --verify")
+	}
+	if opts.only != "" && (opts.watch || opts.verify || opts.rev != "") {
+		return fmt.Errorf("--only cannot be used with --watch, --verify, or --rev")
+	}
+	if opts.only != "" && strings.TrimSpace(opts.outPath) == "" {
+		return fmt.Errorf("--only requires --out to provide the cache to update")
+	}
+	if opts.metricsAddr != "" && !opts.watch {
+		return fmt.Errorf("--metrics-addr requires --watch")
+	}
+	if opts.execCommand != "" && !opts.watch {
+		return fmt.Errorf("--exec requires --watch")
+	}
 	if opts.onceIfChanged {
 		opts.reportChanges = true
 	}
 
-	target := "."
+	target := defaultTarget()
 	if len(args) == 1 {
 		target = args[0]
 	}
@@ -83,6 +127,28 @@ func runIndexBuild(args []string, opts indexBuildOpts) error {
 	if len(allIgnoreLines) > 0 {
 		builder.SetIgnore(ignore.ParsePatterns(allIgnoreLines))
 	}
+	if opts.noGitignore {
+		builder.SetGitignore(nil)
+	}
+	builder.SetFollowSymlinks(opts.followSymlinks)
+	builder.SetSkipGenerated(opts.skipGenerated)
+	maxFileSize, err := parseByteSize(opts.maxFileSize)
+	if err != nil {
+		return fmt.Errorf("--max-file-size: %w", err)
+	}
+	builder.SetMaxFileSize(maxFileSize)
+	maxMemory, restore, err := applyMaxMemory(opts.maxMemory)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	if maxMemory > 0 {
+		builder.SetMaxConcurrent(membudget.Workers(maxMemory))
+	}
+
+	if opts.verify {
+		return runIndexVerify(ctx, builder, opts)
+	}
 
 	previous, hasBaseline, err := loadBaselineIndex(opts.outPath)
 	if err != nil {
@@ -93,10 +159,25 @@ func runIndexBuild(args []string, opts indexBuildOpts) error {
 	if err != nil {
 		return err
 	}
+	if opts.only != "" && (!hasBaseline || filepath.Clean(previous.Root) != indexRoot) {
+		return fmt.Errorf("--only needs an index of %s cached at %s; run gts index build first", indexRoot, opts.outPath)
+	}
 
+	// Progress is only shown for the initial build, not watch rebuilds.
+	var progress func(index.BuildProgress)
+	if opts.progress {
+		progress = newIndexProgress(os.Stderr).Report
+	}
 	buildOnce := func(base *model.Index, observer func(index.BuildEvent)) (*model.Index, index.BuildStats, error) {
+		if opts.rev != "" {
+			return builder.BuildRevision(ctx, target, opts.rev)
+		}
+		if opts.only != "" {
+			return builder.BuildSubtree(ctx, previous, opts.only)
+		}
 		return builder.BuildPathIncrementalWithOptions(ctx, target, base, index.BuildOptions{
 			Observer: observer,
+			Progress: progress,
 		})
 	}
 
@@ -107,7 +188,18 @@ func runIndexBuild(args []string, opts indexBuildOpts) error {
 
 	checkpointWriter := newIndexCheckpointWriter(opts.outPath, indexRoot, buildBase)
 
+	var metrics *watchMetrics
+	if opts.metricsAddr != "" {
+		metrics = newWatchMetrics()
+		if err := serveWatchMetrics(ctx, opts.metricsAddr, metrics); err != nil {
+			return err
+		}
+	}
+
+	buildStart := time.Now()
 	idx, stats, err := buildOnce(buildBase, checkpointWriter.Observe)
+	progress = nil
+	metrics.Observe(time.Since(buildStart), idx, err)
 	if err != nil {
 		return handleBuildError(err, checkpointWriter, opts.outPath, stats)
 	}
@@ -151,7 +243,78 @@ func runIndexBuild(args []string, opts indexBuildOpts) error {
 		return nil
 	}
 
-	return runIndexWatch(ctx, target, builder, idx, buildOnce, opts)
+	return runIndexWatch(ctx, target, builder, idx, buildOnce, metrics, opts)
+}
+
+func runIndexVerify(ctx context.Context, builder *index.Builder, opts indexBuildOpts) error {
+	cached, err := index.Load(opts.outPath)
+	if err != nil {
+		return fmt.Errorf("load cache %s: %w", opts.outPath, err)
+	}
+	report, err := builder.Verify(ctx, cached)
+	if err != nil {
+		return err
+	}
+
+	if opts.jsonOutput {
+		if err := emitJSON(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("verify: root=%s checked=%d rehashed=%d changed=%d added=%d removed=%d\n",
+			report.Root, report.Checked, report.Rehashed, len(report.Changed), len(report.Added), len(report.Removed))
+		if report.Digest != "" {
+			fmt.Printf("digest: %s\n", report.Digest)
+		}
+		if report.ConfigChanged {
+			fmt.Println("config: changed")
+		}
+		for _, group := range []struct {
+			label string
+			paths []string
+		}{{"changed", report.Changed}, {"added", report.Added}, {"removed", report.Removed}} {
+			if len(group.paths) == 0 {
+				continue
+			}
+			fmt.Printf("%s:\n", group.label)
+			for _, path := range group.paths {
+				fmt.Printf("  %s\n", path)
+			}
+		}
+	}
+
+	if report.Stale() {
+		return exitCodeError{code: 2, err: fmt.Errorf("cache %s is stale", opts.outPath)}
+	}
+	if !opts.jsonOutput {
+		fmt.Println("verify: cache is up to date")
+	}
+	return nil
+}
+
+// parseByteSize parses a size such as "4MB", "512k", or "1048576".
+// Units are binary multiples.
+func parseByteSize(raw string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(raw))
+	if value == "" {
+		return 0, nil
+	}
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}} {
+		if trimmed, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value = strings.TrimSpace(trimmed)
+			multiplier = unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", raw)
+	}
+	return n * multiplier, nil
 }
 
 func loadBaselineIndex(outPath string) (*model.Index, bool, error) {
@@ -159,11 +322,15 @@ func loadBaselineIndex(outPath string) (*model.Index, bool, error) {
 		return nil, false, nil
 	}
 	cached, err := index.Load(outPath)
+	var incompatible *index.IncompatibleError
 	switch {
 	case err == nil:
 		return cached, true, nil
 	case os.IsNotExist(err):
 		return nil, false, nil
+	case errors.As(err, &incompatible):
+		fmt.Fprintf(os.Stderr, "index: %s was written by %s in format %s; rebuilding from scratch\n", outPath, incompatible.Writer(), incompatible.SchemaVersion)
+		return nil, false, nil
 	default:
 		return nil, false, fmt.Errorf("load cache %s: %w", outPath, err)
 	}
@@ -198,12 +365,15 @@ func compareBaseline(previous, idx *model.Index, hasBaseline bool) (structdiff.R
 	return report, changed
 }
 
-func runIndexWatch(ctx context.Context, target string, builder *index.Builder, current *model.Index, buildOnce func(*model.Index, func(index.BuildEvent)) (*model.Index, index.BuildStats, error), opts indexBuildOpts) error {
-	fmt.Printf("watching: interval=%s target=%s subfile-incremental=%t\n", opts.interval.String(), target, opts.subfileIncremental)
+func runIndexWatch(ctx context.Context, target string, builder *index.Builder, current *model.Index, buildOnce func(*model.Index, func(index.BuildEvent)) (*model.Index, index.BuildStats, error), metrics *watchMetrics, opts indexBuildOpts) error {
+	policy := opts.watchPolicy.withDefaults(opts.interval)
+	fmt.Printf("watching: interval=%s debounce=%s target=%s subfile-incremental=%t\n", opts.interval.String(), policy.Debounce.String(), target, opts.subfileIncremental)
 	watchState := index.NewWatchState()
 	defer watchState.Release()
+	store := index.NewStore(current)
 
 	onChange := func(changedPaths []string) {
+		current := store.Snapshot()
 		base := (*model.Index)(nil)
 		if opts.incremental {
 			base = current
@@ -214,9 +384,10 @@ func runIndexWatch(ctx context.Context, target string, builder *index.Builder, c
 			nextStats index.BuildStats
 			err       error
 		)
+		start := time.Now()
 		useSubfile := opts.subfileIncremental && len(changedPaths) > 0
 		if useSubfile {
-			next, nextStats, err = builder.ApplyWatchChanges(current, changedPaths, watchState, index.WatchUpdateOptions{
+			next, nextStats, err = store.ApplyWatchChanges(builder, changedPaths, watchState, index.WatchUpdateOptions{
 				SubfileIncremental: true,
 			})
 		} else {
@@ -226,9 +397,11 @@ func runIndexWatch(ctx context.Context, target string, builder *index.Builder, c
 			}
 		}
 		if err != nil {
+			metrics.Observe(time.Since(start), current, err)
 			fmt.Fprintf(os.Stderr, "watch build error: %v\n", err)
 			return
 		}
+		metrics.Observe(time.Since(start), next, nil)
 
 		watchReport := structdiff.Compare(current, next)
 		watchChanged := watchReport.Stats.ChangedFiles > 0 || !parseErrorsEqual(current.Errors, next.Errors)
@@ -236,13 +409,36 @@ func runIndexWatch(ctx context.Context, target string, builder *index.Builder, c
 			return
 		}
 
-		current = next
+		previous := current
+		store.Replace(next)
 		if strings.TrimSpace(opts.outPath) != "" {
 			if err := index.Save(opts.outPath, next); err != nil {
 				fmt.Fprintf(os.Stderr, "watch save error: %v\n", err)
 			}
 		}
 
+		// The command runs after the cache is saved and the change printed,
+		// so it can read both; it writes to stderr when stdout carries JSON.
+		defer func() {
+			if opts.execCommand == "" {
+				return
+			}
+			commandOut := io.Writer(os.Stdout)
+			if opts.jsonOutput {
+				commandOut = os.Stderr
+			}
+			run := watchExec{
+				Command:      opts.execCommand,
+				Root:         next.Root,
+				CachePath:    opts.outPath,
+				ChangedFiles: structdiff.ChangedFiles(previous, next),
+				Report:       watchReport,
+			}
+			if err := run.Run(commandOut, os.Stderr); err != nil {
+				fmt.Fprintf(os.Stderr, "watch exec error: %v\n", err)
+			}
+		}()
+
 		if opts.jsonOutput {
 			if err := emitJSON(next); err != nil {
 				fmt.Fprintf(os.Stderr, "watch json error: %v\n", err)
@@ -269,7 +465,7 @@ func runIndexWatch(ctx context.Context, target string, builder *index.Builder, c
 	}
 
 	if !opts.poll {
-		if err := watchWithFSNotify(ctx, target, opts.interval, ignorePaths, builder.Ignore(), onChange); err == nil {
+		if err := watchWithFSNotify(ctx, target, policy, ignorePaths, builder.Ignore(), onChange); err == nil {
 			fmt.Println("watch: stopped")
 			return nil
 		} else {
@@ -299,6 +495,11 @@ func newIndexBuildCmd() *cobra.Command {
 		Short:   "Build a structural index and optionally cache it",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// A revision index must not replace the working-tree cache that
+			// other commands discover automatically.
+			if opts.rev != "" && !cmd.Flags().Changed("out") {
+				opts.outPath = ""
+			}
 			return runIndexBuild(args, opts)
 		},
 	}
@@ -308,11 +509,23 @@ func newIndexBuildCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&opts.incremental, "incremental", true, "reuse unchanged files from previous index cache")
 	cmd.Flags().BoolVar(&opts.watch, "watch", false, "watch for structural changes and rebuild continuously")
 	cmd.Flags().BoolVar(&opts.subfileIncremental, "subfile-incremental", true, "reuse per-file parse trees for sub-file incremental updates in watch mode")
-	cmd.Flags().BoolVar(&opts.poll, "poll", false, "force polling watch mode instead of fsnotify")
+	cmd.Flags().BoolVar(&opts.poll, "poll", false, "force polling watch mode instead of fsnotify (automatic on network filesystems)")
 	cmd.Flags().BoolVar(&opts.reportChanges, "report-changes", false, "print grouped structural change summary against previous cache")
 	cmd.Flags().BoolVar(&opts.onceIfChanged, "once-if-changed", false, "exit with code 2 when structural changes are detected")
+	cmd.Flags().BoolVar(&opts.followSymlinks, "follow-symlinks", false, "index files reached through symbolic links (cycles are skipped)")
+	cmd.Flags().BoolVar(&opts.noGitignore, "no-gitignore", false, "index files that .gitignore excludes (build output such as dist/ is skipped by default)")
+	cmd.Flags().BoolVar(&opts.skipGenerated, "skip-generated", false, "leave generated files (e.g. 'Code generated ... DO NOT EDIT') out of the index instead of annotating them")
+	cmd.Flags().StringVar(&opts.maxFileSize, "max-file-size", "4MB", "skip files larger than this (e.g. 512KB, 4MB; 0 disables); skipped files are listed in the index")
+	cmd.Flags().StringVar(&opts.maxMemory, "max-memory", "", "memory budget such as 3GB: caps the heap and parses fewer files at once to stay within it")
+	cmd.Flags().BoolVar(&opts.verify, "verify", false, "check the --out cache against the working tree without rebuilding; exit 2 when stale")
 	cmd.Flags().DurationVar(&opts.interval, "interval", 2*time.Second, "poll interval for watch mode")
+	addWatchPolicyFlags(cmd, &opts.watchPolicy)
 	cmd.Flags().StringArrayVar(&opts.ignorePatterns, "ignore", nil, "additional ignore patterns (repeatable, merged with .graftignore and .gtsignore)")
+	cmd.Flags().BoolVar(&opts.progress, "progress", false, "report files parsed and the current path on stderr while building")
+	cmd.Flags().StringVar(&opts.execCommand, "exec", "", "with --watch, run this shell command after each structural change; GTS_CHANGED_FILES, GTS_CHANGE_REPORT (JSON file), and GTS_*_SYMBOLS describe the change")
+	cmd.Flags().StringVar(&opts.metricsAddr, "metrics-addr", "", "with --watch, serve Prometheus metrics on /metrics and a health check on /healthz at this address (e.g. :9464, which binds to localhost)")
+	cmd.Flags().StringVar(&opts.only, "only", "", "re-index only this directory (relative to the indexed path) and merge it into the --out cache; files elsewhere are kept as cached")
+	cmd.Flags().StringVar(&opts.rev, "rev", "", "index a git revision (commit, branch, tag, or stash@{n}) from the object store instead of the working tree; --out is not written unless given")
 	return cmd
 }
 
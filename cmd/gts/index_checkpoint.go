@@ -31,7 +31,7 @@ type indexCheckpointWriter struct {
 
 func resolveIndexRoot(target string) (string, error) {
 	if strings.TrimSpace(target) == "" {
-		target = "."
+		target = defaultTarget()
 	}
 
 	absTarget, err := filepath.Abs(target)
@@ -17,7 +17,7 @@ func newErrorsCmd() *cobra.Command {
 		Short: "Show files that failed to parse with error details",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
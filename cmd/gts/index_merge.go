@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/federation"
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+func newIndexMergeCmd() *cobra.Command {
+	var output string
+	var onConflict string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "merge <index>... --out <file>",
+		Short: "Merge indexes of several repositories into one",
+		Long: `Merge index caches (index.json) or exported .gtsindex files into one index.
+
+The merged root is the nearest directory containing every input root, and
+each input's paths are rebased onto it, so indexes of sibling checkouts
+merge side by side. Pass the result to --cache of any command to search
+references, trace calls, or bridge dependencies across the repositories.
+
+Inputs sharing a root may hold the same path. --on-conflict decides which
+copy is kept:
+
+  error   fail, listing the conflicting paths (default)
+  newest  keep the copy from the most recently generated index
+  first   keep the copy from the earliest input
+  last    keep the copy from the latest input
+  prefix  place each input under a directory named for its repository`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, err := federation.ParseConflictPolicy(onConflict)
+			if err != nil {
+				return err
+			}
+			inputs := make([]federation.MergeInput, 0, len(args))
+			for _, path := range args {
+				input, err := loadMergeInput(path)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input)
+			}
+
+			merged, report, err := federation.Merge(inputs, policy)
+			if err != nil {
+				return err
+			}
+			if err := index.Save(output, merged); err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return emitJSON(report)
+			}
+			for _, input := range report.Inputs {
+				prefix := input.Prefix
+				if prefix == "" {
+					prefix = "."
+				}
+				fmt.Printf("input: %s repo=%s files=%d prefix=%s\n", input.Source, input.Name, input.Files, prefix)
+			}
+			for _, conflict := range report.Conflicts {
+				fmt.Printf("conflict: %s kept=%s dropped=%s\n", conflict.Path, conflict.Kept, strings.Join(conflict.Dropped, ","))
+			}
+			fmt.Printf("merged: %s root=%s files=%d conflicts=%d\n", output, report.Root, report.Files, len(report.Conflicts))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&output, "out", "", "path to write the merged index")
+	cmd.Flags().StringVar(&onConflict, "on-conflict", string(federation.ConflictError), "how to resolve a path held by more than one input: error, newest, first, last, or prefix")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit the merge report as JSON")
+	_ = cmd.MarkFlagRequired("out")
+	return cmd
+}
+
+// loadMergeInput reads an index cache or an exported .gtsindex file. An
+// export is named by its repository; a cache by the base name of its root.
+func loadMergeInput(path string) (federation.MergeInput, error) {
+	if isGzipFile(path) {
+		exported, err := federation.LoadFile(path)
+		if err != nil {
+			return federation.MergeInput{}, err
+		}
+		return federation.MergeInput{Source: path, Name: exported.RepoName, Index: &exported.Index}, nil
+	}
+	idx, err := index.Load(path)
+	if err != nil {
+		return federation.MergeInput{}, fmt.Errorf("load %s: %w", path, err)
+	}
+	return federation.MergeInput{Source: path, Name: filepath.Base(idx.Root), Index: idx}, nil
+}
+
+func isGzipFile(path string) bool {
+	if strings.HasSuffix(path, ".gtsindex") {
+		return true
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	magic, err := bufio.NewReader(f).Peek(2)
+	return err == nil && magic[0] == 0x1f && magic[1] == 0x8b
+}
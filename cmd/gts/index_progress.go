@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+// indexProgress prints build progress for gts index build --progress. On a
+// terminal it rewrites a single status line; elsewhere, such as CI logs, it
+// prints a line every few seconds.
+type indexProgress struct {
+	out      *os.File
+	terminal bool
+	interval time.Duration
+	start    time.Time
+	last     time.Time
+}
+
+func newIndexProgress(out *os.File) *indexProgress {
+	p := &indexProgress{out: out, interval: 5 * time.Second, start: time.Now()}
+	if info, err := out.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		p.terminal = true
+		p.interval = 100 * time.Millisecond
+	}
+	return p
+}
+
+func (p *indexProgress) Report(progress index.BuildProgress) {
+	now := time.Now()
+	final := progress.Path == "" && progress.WalkDone
+	if !final && now.Sub(p.last) < p.interval {
+		return
+	}
+	p.last = now
+
+	total := fmt.Sprintf("%d", progress.Total)
+	if !progress.WalkDone {
+		total += "+"
+	}
+	line := fmt.Sprintf("index: %d/%s files %s", progress.Done, total, now.Sub(p.start).Round(time.Second))
+	if progress.Path != "" {
+		line += " " + progress.Path
+	}
+	if !p.terminal {
+		fmt.Fprintln(p.out, line)
+		return
+	}
+	// Clear the rest of the previous, possibly longer, line.
+	fmt.Fprintf(p.out, "\r%s\x1b[K", line)
+	if final {
+		fmt.Fprintln(p.out)
+	}
+}
@@ -24,7 +24,7 @@ func newInitCmd() *cobra.Command {
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
-	target := "."
+	target := defaultTarget()
 	if len(args) == 1 {
 		target = args[0]
 	}
@@ -261,7 +261,7 @@ func newInitCICmd() *cobra.Command {
 }
 
 func runInitCI(cmd *cobra.Command, args []string) error {
-	target := "."
+	target := defaultTarget()
 	// Inherit path from parent if invoked as 'gts init ci' with path on parent.
 	abs, err := filepath.Abs(target)
 	if err != nil {
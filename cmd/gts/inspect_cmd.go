@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/inspect"
+)
+
+func newInspectCmd() *cobra.Command {
+	var line int
+	var endLine int
+	var depth int
+	var anonymous bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "inspect <file>",
+		Short: "Print the tree-sitter syntax tree of a file or line range",
+		Long: `Print the tree-sitter syntax tree of a file or line range.
+
+Each line shows a node's field name, type, and range as
+start_line:start_col-end_line:end_col, plus the text of leaf nodes. The node
+types and field names are the ones to use in "gts search query" patterns,
+e.g. "name: identifier" under function_declaration becomes
+(function_declaration name: (identifier) @name).
+
+--line keeps only the nodes overlapping that line (or --line through
+--end-line) and their ancestors. Punctuation and keywords are hidden unless
+--anonymous is given.
+
+Examples:
+  gts inspect main.go --line 12
+  gts inspect src/app.ts --line 10 --end-line 20 --anonymous
+  gts inspect lib/util.py --depth 2 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if line < 0 || endLine < 0 || depth < 0 {
+				return fmt.Errorf("--line, --end-line, and --depth must not be negative")
+			}
+			if endLine > 0 && line == 0 {
+				return fmt.Errorf("--end-line requires --line")
+			}
+			if line > 0 && endLine == 0 {
+				endLine = line
+			}
+
+			report, err := inspect.File(args[0], inspect.Options{
+				StartLine: line,
+				EndLine:   endLine,
+				MaxDepth:  depth,
+				Anonymous: anonymous,
+			})
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return emitJSON(report)
+			}
+			fmt.Printf("%s (%s)\n", report.File, report.Language)
+			fmt.Print(inspect.Format(report.Root))
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&line, "line", 0, "only show nodes overlapping this line (1-based)")
+	cmd.Flags().IntVar(&endLine, "end-line", 0, "with --line, show nodes overlapping --line through this line")
+	cmd.Flags().IntVar(&depth, "depth", 0, "maximum tree depth to print (0 for unlimited)")
+	cmd.Flags().BoolVar(&anonymous, "anonymous", false, "include anonymous nodes such as punctuation and keywords")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+func runInspect(args []string) error {
+	cmd := newInspectCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
@@ -59,7 +59,7 @@ func newLicensesCmd() *cobra.Command {
 		Short: "Detect dependency licenses from manifests and vendored LICENSE files",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
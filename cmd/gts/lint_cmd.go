@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/odvcencio/gts-suite/internal/lint"
+	"github.com/odvcencio/gts-suite/pkg/annotate"
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/sarif"
+	"github.com/odvcencio/gts-suite/pkg/structdiff"
 )
 
 func newLintCmd() *cobra.Command {
@@ -21,6 +29,13 @@ func newLintCmd() *cobra.Command {
 	var rawPatterns []string
 	var noDefaults bool
 	var thresholdOverrides []string
+	var rulePlugins []string
+	var packs []string
+	var packPaths []string
+	var changedOnly bool
+	var sinceCache string
+	var fix bool
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:     "lint [path]",
@@ -36,10 +51,29 @@ defaults.
 Use --no-defaults to disable built-in rules. Use --threshold to override
 individual thresholds (e.g. --threshold cyclomatic=35).
 
-Built-in rules compose with explicit --rule and --pattern flags: all fire together.`,
+Built-in rules compose with explicit --rule and --pattern flags: all fire together.
+
+Use --rules-plugin to run an external rule executable. It receives
+{"version":1,"root":...,"index":...} as JSON on stdin and prints
+{"violations":[...]} on stdout.
+
+Use --pack to load a named rule pack: a directory or tarball holding lint.yaml
+plus .scm patterns, resolved from --pack-path, $GTS_PACK_PATH, .gts/packs,
+and ~/.gts/packs.
+
+Use --changed-only to lint only files that changed since a baseline index
+(--since-cache, else --cache, else .gts/index.json). The workspace is
+re-indexed incrementally against the baseline, so unchanged files are neither
+reparsed nor linted.
+
+Use --fix to apply suggested fixes (e.g. --rule "no unused imports" removes
+unused Go imports). Add --dry-run to preview the fixes as a unified diff.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			if dryRun && !fix {
+				return fmt.Errorf("--dry-run requires --fix")
+			}
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -61,6 +95,18 @@ Built-in rules compose with explicit --rule and --pattern flags: all fire togeth
 				patterns = append(patterns, pattern)
 			}
 
+			registry := lint.PackRegistry(target, packPaths)
+			loadedPacks := make([]lint.Pack, 0, len(packs))
+			for _, name := range packs {
+				pack, err := lint.LoadPack(name, registry)
+				if err != nil {
+					return err
+				}
+				rules = append(rules, pack.Rules...)
+				patterns = append(patterns, pack.Patterns...)
+				loadedPacks = append(loadedPacks, pack)
+			}
+
 			// Determine whether to use built-in threshold rules.
 			useDefaults := !noDefaults
 			var thresholdRules []lint.ThresholdRule
@@ -68,6 +114,13 @@ Built-in rules compose with explicit --rule and --pattern flags: all fire togeth
 				// Copy DefaultRules so overrides don't mutate the package-level slice.
 				thresholdRules = make([]lint.ThresholdRule, len(lint.DefaultRules))
 				copy(thresholdRules, lint.DefaultRules)
+				for _, pack := range loadedPacks {
+					for i := range thresholdRules {
+						if threshold, ok := pack.Thresholds[thresholdRules[i].Metric]; ok {
+							thresholdRules[i].Threshold = threshold
+						}
+					}
+				}
 				for _, override := range thresholdOverrides {
 					if err := lint.ParseThresholdOverride(override, thresholdRules); err != nil {
 						return err
@@ -79,6 +132,9 @@ Built-in rules compose with explicit --rule and --pattern flags: all fire togeth
 			if cfgErr != nil {
 				return fmt.Errorf("loading .gtslint: %w", cfgErr)
 			}
+			if lintCfg != nil {
+				rules = append(rules, lintCfg.Rules...)
+			}
 			if lintCfg != nil && useDefaults {
 				for _, override := range lintCfg.Overrides {
 					if override.Scope != "" {
@@ -97,27 +153,57 @@ Built-in rules compose with explicit --rule and --pattern flags: all fire togeth
 				}
 			}
 
-			idx, err := loadOrBuild(cachePath, target, noCache)
+			var idx *model.Index
+			var changed map[string]bool
+			var err error
+			if changedOnly {
+				baseline := sinceCache
+				if baseline == "" {
+					baseline = cachePath
+				}
+				idx, changed, err = loadChangedIndex(target, baseline)
+			} else {
+				idx, err = loadOrBuild(cachePath, target, noCache)
+			}
 			if err != nil {
 				return err
 			}
 			idx = applyGeneratedFilter(cmd, idx)
+			lintIdx := idx
+			if changed != nil {
+				lintIdx = idx.FilterByPaths(changed)
+			}
 
-			violations := lint.Evaluate(idx, rules)
+			violations := lint.Evaluate(lintIdx, rules)
 
 			// When defaults are enabled, include built-in secrets detection patterns.
 			if useDefaults {
 				patterns = append(patterns, lint.SecretsPatterns()...)
 			}
 
-			patternViolations, err := lint.EvaluatePatterns(idx, patterns)
+			patternViolations, err := lint.EvaluatePatterns(lintIdx, patterns)
 			if err != nil {
 				return err
 			}
 			violations = append(violations, patternViolations...)
 
+			customRules := lint.RegisteredRules()
+			for _, plugin := range rulePlugins {
+				customRules = append(customRules, lint.PluginRule(plugin))
+			}
+			customViolations, err := lint.EvaluateCustom(lintIdx, customRules)
+			if err != nil {
+				return err
+			}
+			violations = append(violations, customViolations...)
+
 			if len(thresholdRules) > 0 {
-				thresholdViolations, err := lint.EvaluateThresholds(idx, thresholdRules)
+				var thresholdViolations []lint.Violation
+				if changed != nil {
+					thresholdViolations, err = lint.EvaluateThresholdsInFiles(idx, thresholdRules, changed)
+				} else {
+					thresholdViolations, err = lint.EvaluateThresholds(idx, thresholdRules)
+				}
 				if err != nil {
 					return err
 				}
@@ -147,6 +233,24 @@ Built-in rules compose with explicit --rule and --pattern flags: all fire togeth
 				return violations[i].File < violations[j].File
 			})
 
+			var fixReport *lint.FixReport
+			if fix {
+				report, err := lint.ApplyFixes(idx.Root, violations, !dryRun)
+				if err != nil {
+					return err
+				}
+				fixReport = &report
+				if !dryRun {
+					remaining := violations[:0]
+					for _, v := range violations {
+						if len(v.Fixes) == 0 {
+							remaining = append(remaining, v)
+						}
+					}
+					violations = remaining
+				}
+			}
+
 			// Resolve output format: --json implies "json" for backward compat.
 			outputFmt := format
 			if jsonOutput && outputFmt == "text" {
@@ -169,21 +273,36 @@ Built-in rules compose with explicit --rule and --pattern flags: all fire togeth
 				if err := log.Encode(os.Stdout); err != nil {
 					return err
 				}
+			case "github", "gitlab":
+				annotations := make([]annotate.Annotation, 0, len(violations))
+				for _, v := range violations {
+					annotations = append(annotations, annotate.Annotation{
+						RuleID:    v.RuleID,
+						Severity:  v.Severity,
+						Message:   lintAnnotationMessage(v),
+						File:      v.File,
+						StartLine: v.StartLine,
+						EndLine:   v.EndLine,
+					})
+				}
+				if err := writeAnnotations(outputFmt, annotations); err != nil {
+					return err
+				}
 			case "json":
-				return emitJSON(struct {
-					Rules          []lint.Rule         `json:"rules,omitempty"`
-					Patterns       []lint.QueryPattern `json:"patterns,omitempty"`
-					ThresholdRules []lint.ThresholdRule `json:"threshold_rules,omitempty"`
-					Violations     []lint.Violation     `json:"violations,omitempty"`
-					Count          int                  `json:"count"`
-				}{
+				return emitJSON(report.LintReport{
 					Rules:          rules,
 					Patterns:       patterns,
 					ThresholdRules: thresholdRules,
 					Violations:     violations,
 					Count:          len(violations),
+					Fix:            fixReport,
 				})
 			default:
+				if fixReport != nil {
+					for _, fileFix := range fixReport.Files {
+						fmt.Print(fileFix.Diff)
+					}
+				}
 				for _, violation := range violations {
 					severity := violation.Severity
 					if severity == "" {
@@ -216,6 +335,16 @@ Built-in rules compose with explicit --rule and --pattern flags: all fire togeth
 
 				thresholdCount := len(thresholdRules)
 				fmt.Printf("lint: rules=%d patterns=%d thresholds=%d violations=%d\n", len(rules), len(patterns), thresholdCount, len(violations))
+				if changed != nil {
+					fmt.Printf("lint: changed files=%d of %d\n", len(changed), len(idx.Files))
+				}
+				if fixReport != nil {
+					verb := "applied"
+					if dryRun {
+						verb = "would apply"
+					}
+					fmt.Printf("lint: fix %s %d edits in %d files (skipped=%d)\n", verb, fixReport.Applied, len(fixReport.Files), fixReport.Skipped)
+				}
 				if len(idx.Errors) > 0 {
 					fmt.Printf("lint: parse errors=%d (ignored)\n", len(idx.Errors))
 				}
@@ -235,14 +364,60 @@ Built-in rules compose with explicit --rule and --pattern flags: all fire togeth
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
 	cmd.Flags().BoolVar(&failOnViolations, "fail-on-violations", true, "exit non-zero when violations are found")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
-	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, sarif")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, sarif, github, gitlab")
 	cmd.Flags().StringArrayVar(&rawRules, "rule", nil, "lint rule expression (repeatable)")
 	cmd.Flags().StringArrayVar(&rawPatterns, "pattern", nil, "tree-sitter query pattern file (.scm) (repeatable)")
 	cmd.Flags().BoolVar(&noDefaults, "no-defaults", false, "disable built-in threshold rules")
 	cmd.Flags().StringArrayVar(&thresholdOverrides, "threshold", nil, "override a built-in threshold (e.g. cyclomatic=35) (repeatable)")
+	cmd.Flags().StringArrayVar(&packs, "pack", nil, "rule pack name or path (repeatable)")
+	cmd.Flags().StringArrayVar(&packPaths, "pack-path", nil, "directory to search for rule packs (repeatable)")
+	cmd.Flags().BoolVar(&changedOnly, "changed-only", false, "lint only files changed since the baseline index")
+	cmd.Flags().StringVar(&sinceCache, "since-cache", "", "baseline index for --changed-only (default: --cache or .gts/index.json)")
+	cmd.Flags().BoolVar(&fix, "fix", false, "apply suggested fixes for violations that have them")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "with --fix, print a diff preview instead of writing files")
+	cmd.Flags().StringArrayVar(&rulePlugins, "rules-plugin", nil, "external rule executable speaking the JSON plugin protocol (repeatable)")
 	return cmd
 }
 
+// loadChangedIndex re-indexes target incrementally against the baseline index
+// and returns the fresh index plus the set of files that changed since it.
+func loadChangedIndex(target, baselinePath string) (*model.Index, map[string]bool, error) {
+	if strings.TrimSpace(baselinePath) == "" {
+		baselinePath = filepath.Join(target, ".gts", "index.json")
+	}
+	baseline, err := index.Load(baselinePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load baseline index for --changed-only: %w", err)
+	}
+	builder, err := index.NewBuilderWithWorkspaceIgnores(target)
+	if err != nil {
+		return nil, nil, err
+	}
+	current, _, err := builder.BuildPathIncremental(context.Background(), target, baseline)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changed := map[string]bool{}
+	for _, path := range structdiff.ChangedFiles(baseline, current) {
+		changed[path] = true
+	}
+	return current, changed, nil
+}
+
+// lintAnnotationMessage prefixes a violation message with the symbol it
+// concerns, since annotations are shown without the text output's columns.
+func lintAnnotationMessage(v lint.Violation) string {
+	message := strings.TrimSpace(v.Message)
+	if message == "" {
+		message = v.RuleID
+	}
+	if v.Name == "" {
+		return message
+	}
+	return strings.TrimSpace(v.Kind+" "+v.Name) + ": " + message
+}
+
 func runLint(args []string) error {
 	cmd := newLintCmd()
 	cmd.SilenceUsage = true
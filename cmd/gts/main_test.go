@@ -2,12 +2,23 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/odvcencio/gts-suite/internal/chunk"
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/structdiff"
 )
 
@@ -160,6 +171,41 @@ func TestShouldIgnoreWatchPath(t *testing.T) {
 	}
 }
 
+func TestWatchPolicyNextRebuild(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+
+	cases := []struct {
+		name                              string
+		policy                            watchPolicy
+		first, last, previous, wantOffset int
+		noPrevious                        bool
+	}{
+		{name: "debounce after last event", policy: watchPolicy{Debounce: 300 * time.Millisecond}, first: 0, last: 500, noPrevious: true, wantOffset: 800},
+		{name: "max wait caps a burst", policy: watchPolicy{Debounce: 300 * time.Millisecond, MaxWait: time.Second}, first: 0, last: 900, noPrevious: true, wantOffset: 1000},
+		{name: "min interval delays", policy: watchPolicy{Debounce: 100 * time.Millisecond, MinInterval: 5 * time.Second}, first: 200, last: 200, previous: 0, wantOffset: 5000},
+		{name: "min interval already elapsed", policy: watchPolicy{Debounce: 100 * time.Millisecond, MinInterval: time.Second}, first: 3000, last: 3000, previous: 0, wantOffset: 3100},
+		{name: "min interval wins over max wait", policy: watchPolicy{Debounce: 100 * time.Millisecond, MaxWait: 200 * time.Millisecond, MinInterval: 2 * time.Second}, first: 100, last: 250, previous: 0, wantOffset: 2000},
+	}
+	for _, tc := range cases {
+		previous := at(tc.previous)
+		if tc.noPrevious {
+			previous = time.Time{}
+		}
+		got := tc.policy.nextRebuild(at(tc.first), at(tc.last), previous)
+		if want := at(tc.wantOffset); !got.Equal(want) {
+			t.Errorf("%s: nextRebuild = +%s, want +%s", tc.name, got.Sub(base), want.Sub(base))
+		}
+	}
+
+	if got := (watchPolicy{}).withDefaults(2 * time.Second).Debounce; got != 2*time.Second {
+		t.Errorf("default debounce = %s, want --interval", got)
+	}
+	if err := (watchPolicy{MaxWait: -time.Second}).validate(); err == nil {
+		t.Error("expected negative --max-wait to be rejected")
+	}
+}
+
 func TestWatchRootsDirectoryAndFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	filePath := filepath.Join(tmpDir, "main.go")
@@ -250,6 +296,299 @@ func TestRunIndexOnceIfChanged(t *testing.T) {
 	assertExitCode(t, err, 2)
 }
 
+func TestRunIndexVerify(t *testing.T) {
+	tmpDir := t.TempDir()
+	outPath := filepath.Join(tmpDir, ".gts", "index.json")
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(sourcePath, []byte("package sample\n\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := runIndex([]string{tmpDir, "--out", outPath}); err != nil {
+		t.Fatalf("runIndex failed: %v", err)
+	}
+	if err := runIndex([]string{tmpDir, "--out", outPath, "--verify"}); err != nil {
+		t.Fatalf("expected fresh cache to verify, got %v", err)
+	}
+
+	if err := os.WriteFile(sourcePath, []byte("package sample\n\nfunc B() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	err := runIndex([]string{tmpDir, "--out", outPath, "--verify"})
+	if err == nil {
+		t.Fatal("expected stale cache to fail verification")
+	}
+	assertExitCode(t, err, 2)
+
+	root := newRootCmd()
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetArgs([]string{"index", tmpDir, "--out", outPath, "--verify"})
+	assertExitCode(t, root.Execute(), 2)
+
+	root = newRootCmd()
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetArgs([]string{"index", tmpDir})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Fatalf("expected a path without --verify to be an unknown command, got %v", err)
+	}
+}
+
+func TestRunIndexOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	outPath := filepath.Join(tmpDir, ".gts", "index.json")
+	write := func(relPath, body string) {
+		t.Helper()
+		absPath := filepath.Join(tmpDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(absPath, []byte(body), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	write("a/a.go", "package a\n\nfunc A() {}\n")
+	write("b/b.go", "package b\n\nfunc B() {}\n")
+
+	if err := runIndex([]string{tmpDir, "--out", outPath, "--only", "a"}); err == nil {
+		t.Fatal("expected --only without a cache to fail")
+	}
+	if err := runIndex([]string{tmpDir, "--out", outPath}); err != nil {
+		t.Fatalf("runIndex failed: %v", err)
+	}
+
+	write("a/a.go", "package a\n\nfunc A() {}\n\nfunc A2() {}\n")
+	write("b/b.go", "package b\n\nfunc B() {}\n\nfunc B2() {}\n")
+	if err := runIndex([]string{tmpDir, "--out", outPath, "--only", "a"}); err != nil {
+		t.Fatalf("runIndex --only failed: %v", err)
+	}
+
+	idx, err := index.Load(outPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	names := map[string]bool{}
+	for _, file := range idx.Files {
+		for _, symbol := range file.Symbols {
+			names[symbol.Name] = true
+		}
+	}
+	if !names["A2"] || names["B2"] || !names["B"] {
+		t.Fatalf("expected only a/ to be re-indexed, got symbols %v", names)
+	}
+}
+
+func TestRunDaemon_ServesWarmIndex(t *testing.T) {
+	t.Setenv("GTS_NO_DAEMON", "")
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package sample\n\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- runDaemon(ctx, root) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("runDaemon returned %v", err)
+		}
+	}()
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	hasSymbol := func(idx *model.Index, name string) bool {
+		for _, file := range idx.Files {
+			for _, symbol := range file.Symbols {
+				if symbol.Name == name {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	waitFor("daemon status", func() bool {
+		_, err := fetchDaemonStatus(root)
+		return err == nil
+	})
+	status, _ := fetchDaemonStatus(root)
+	if status.Root != root || status.Files != 1 || status.PID != os.Getpid() || status.CallEdges != 0 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	idx, err := loadOrBuild("", root, false)
+	if err != nil {
+		t.Fatalf("loadOrBuild failed: %v", err)
+	}
+	if !hasSymbol(idx, "A") {
+		t.Fatalf("daemon index is missing A: %+v", idx.Files)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package sample\n\nfunc A() { B() }\n\nfunc B() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	waitFor("rebuild with B", func() bool {
+		idx, ok := loadDaemonIndex(root)
+		return ok && hasSymbol(idx, "B")
+	})
+	waitFor("call graph with A -> B", func() bool {
+		status, err := fetchDaemonStatus(root)
+		return err == nil && status.CallEdges == 1
+	})
+
+	t.Setenv("GTS_NO_DAEMON", "1")
+	if _, ok := loadDaemonIndex(root); ok {
+		t.Fatal("GTS_NO_DAEMON=1 should bypass the daemon")
+	}
+}
+
+func TestLoadOrBuild_FallsBackWhenDaemonIsUnreachable(t *testing.T) {
+	t.Setenv("GTS_NO_DAEMON", "")
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package sample\n\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	// A socket left behind by a daemon that is no longer running.
+	socket := filepath.Join(root, filepath.FromSlash(daemonSocketName))
+	if err := os.MkdirAll(filepath.Dir(socket), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(socket, nil, 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if _, ok := loadDaemonIndex(root); ok {
+		t.Fatal("expected no index from a stale daemon socket")
+	}
+	idx, err := loadOrBuild("", root, false)
+	if err != nil {
+		t.Fatalf("loadOrBuild failed: %v", err)
+	}
+	if idx.FileCount() != 1 {
+		t.Fatalf("expected a local build with 1 file, got %d", idx.FileCount())
+	}
+}
+
+func TestWatchExecRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	root := t.TempDir()
+	run := watchExec{
+		Command:      `printf '%s|%s|%s\n' "$GTS_CHANGED_FILES" "$GTS_ADDED_SYMBOLS" "$GTS_INDEX" > out.txt && cp "$GTS_CHANGE_REPORT" report.json`,
+		Root:         root,
+		CachePath:    ".gts/index.json",
+		ChangedFiles: []string{"a.go"},
+		Report: structdiff.Report{
+			AddedSymbols: []structdiff.SymbolRef{{File: "a.go", Kind: "function_definition", Name: "B"}},
+			Stats:        structdiff.Stats{AddedSymbols: 1, ChangedFiles: 1},
+		},
+	}
+	var stderr bytes.Buffer
+	if err := run.Run(&stderr, &stderr); err != nil {
+		t.Fatalf("Run failed: %v\n%s", err, stderr.String())
+	}
+
+	out, err := os.ReadFile(filepath.Join(root, "out.txt"))
+	if err != nil {
+		t.Fatalf("command did not run in root: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "a.go|1|.gts/index.json" {
+		t.Fatalf("command saw %q", got)
+	}
+	report, err := os.ReadFile(filepath.Join(root, "report.json"))
+	if err != nil || !strings.Contains(string(report), `"name": "B"`) {
+		t.Fatalf("change report not passed to command: %v\n%s", err, report)
+	}
+
+	run.Command = "exit 3"
+	if err := run.Run(&stderr, &stderr); err == nil {
+		t.Fatal("expected a failing command to return an error")
+	}
+}
+
+func TestWatchMetrics(t *testing.T) {
+	metrics := newWatchMetrics()
+	idx := &model.Index{
+		Files:  []model.FileSummary{{Path: "a.go", Symbols: []model.Symbol{{Name: "A"}}}, {Path: "b.go"}},
+		Errors: []model.ParseError{{Path: "c.go", Error: "syntax"}},
+	}
+	metrics.Observe(1500*time.Millisecond, idx, nil)
+	metrics.Observe(500*time.Millisecond, idx, errors.New("disk full"))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/metrics")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/metrics status = %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE gts_watch_rebuilds_total counter\ngts_watch_rebuilds_total 2\n",
+		"gts_watch_rebuild_failures_total 1\n",
+		"gts_watch_rebuild_duration_seconds_sum 2\n",
+		"gts_watch_rebuild_duration_seconds_count 2\n",
+		"gts_watch_last_rebuild_duration_seconds 0.5\n",
+		"gts_watch_files 2\n",
+		"gts_watch_symbols 1\n",
+		"gts_watch_parse_errors 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics missing %q\n%s", want, body)
+		}
+	}
+
+	if rec := get("/healthz"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("/healthz after failed rebuild = %d, want 503", rec.Code)
+	}
+	metrics.Observe(time.Second, idx, nil)
+	if rec := get("/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("/healthz after successful rebuild = %d, want 200", rec.Code)
+	}
+	if rec := get("/other"); rec.Code != http.StatusNotFound {
+		t.Errorf("/other = %d, want 404", rec.Code)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	cases := map[string]int64{
+		"":        0,
+		"0":       0,
+		"1048576": 1 << 20,
+		"512KB":   512 << 10,
+		"4MB":     4 << 20,
+		"2g":      2 << 30,
+		"10 b":    10,
+	}
+	for input, want := range cases {
+		got, err := parseByteSize(input)
+		if err != nil {
+			t.Fatalf("parseByteSize(%q) returned error: %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("parseByteSize(%q) = %d, want %d", input, got, want)
+		}
+	}
+	if _, err := parseByteSize("lots"); err == nil {
+		t.Fatal("expected error for invalid size")
+	}
+}
+
 func TestRunLint_MaxLinesViolation(t *testing.T) {
 	tmpDir := t.TempDir()
 	sourcePath := filepath.Join(tmpDir, "main.go")
@@ -305,6 +644,79 @@ func A() {
 	assertExitCode(t, err, 3)
 }
 
+func TestRunLint_FixRemovesUnusedImport(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+import (
+	"fmt"
+	"strings"
+)
+
+func A() {
+	fmt.Println("ok")
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := runLint([]string{tmpDir, "--no-defaults", "--no-cache", "--rule", "no unused imports", "--dry-run"}); err == nil || !strings.Contains(err.Error(), "--dry-run requires --fix") {
+		t.Fatalf("expected --dry-run without --fix to fail, got %v", err)
+	}
+
+	err := runLint([]string{tmpDir, "--no-defaults", "--no-cache", "--rule", "no unused imports", "--fix", "--dry-run"})
+	assertExitCode(t, err, 3)
+	unchanged, _ := os.ReadFile(sourcePath)
+	if string(unchanged) != source {
+		t.Fatal("expected --dry-run to leave the file untouched")
+	}
+
+	if err := runLint([]string{tmpDir, "--no-defaults", "--no-cache", "--rule", "no unused imports", "--fix"}); err != nil {
+		t.Fatalf("expected fixed lint run to pass, got %v", err)
+	}
+	fixed, _ := os.ReadFile(sourcePath)
+	if strings.Contains(string(fixed), `"strings"`) || !strings.Contains(string(fixed), `"fmt"`) {
+		t.Fatalf("expected only strings import removed, got:\n%s", fixed)
+	}
+}
+
+func TestRunLint_ChangedOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	longFunc := "package sample\n\nfunc Long() {\n\tprintln(1)\n\tprintln(2)\n\tprintln(3)\n\tprintln(4)\n\tprintln(5)\n}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte(longFunc), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	bPath := filepath.Join(tmpDir, "b.go")
+	if err := os.WriteFile(bPath, []byte("package sample\n\nfunc B() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	baseline, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	baselinePath := filepath.Join(t.TempDir(), "baseline.json")
+	if err := index.Save(baselinePath, baseline); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	if err := os.WriteFile(bPath, []byte("package sample\n\nfunc B() { println(0) }\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	future := time.Now().Add(time.Minute)
+	_ = os.Chtimes(bPath, future, future)
+
+	rule := "no function longer than 5 lines"
+	err = runLint([]string{tmpDir, "--no-defaults", "--no-cache", "--rule", rule})
+	assertExitCode(t, err, 3)
+
+	if err := runLint([]string{tmpDir, "--no-defaults", "--rule", rule, "--changed-only", "--since-cache", baselinePath}); err != nil {
+		t.Fatalf("expected unchanged a.go to be skipped, got %v", err)
+	}
+}
+
 func TestRunLint_QueryPatternViolation(t *testing.T) {
 	tmpDir := t.TempDir()
 	sourcePath := filepath.Join(tmpDir, "main.go")
@@ -375,6 +787,50 @@ func Work() {}
 	}
 }
 
+func TestRunMap_FileFilterAndNesting(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package sample
+
+type Service struct{}
+
+func (s *Service) Work() {}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "other.go"), []byte("package sample\n\nfunc Other() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runMap([]string{tmpDir, "--no-cache", "--file", "main.go"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runMap returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	text := output.String()
+	if strings.Contains(text, "other.go") {
+		t.Fatalf("expected --file to exclude other.go, got:\n%s", text)
+	}
+	if !strings.Contains(text, "\n    method_definition func (s *Service) Work()") {
+		t.Fatalf("expected Work nested under Service, got:\n%s", text)
+	}
+}
+
 func TestRunFiles(t *testing.T) {
 	tmpDir := t.TempDir()
 	sourcePath := filepath.Join(tmpDir, "main.go")
@@ -422,34 +878,16 @@ func Work() {}
 	}
 }
 
-func TestRunDeps(t *testing.T) {
+func TestRunFilesFiltersByPath(t *testing.T) {
 	tmpDir := t.TempDir()
-	if err := os.MkdirAll(filepath.Join(tmpDir, "internal", "x"), 0o755); err != nil {
-		t.Fatalf("MkdirAll failed: %v", err)
-	}
-	mainSource := `package main
-
-import (
-	"fmt"
-	"sample/internal/x"
-)
-
-func main() {
-	_ = fmt.Sprintf("%v", x.Value)
-}
-`
-	xSource := `package x
-
-const Value = 1
-`
-	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module sample\n"), 0o644); err != nil {
-		t.Fatalf("WriteFile go.mod failed: %v", err)
-	}
-	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(mainSource), 0o644); err != nil {
-		t.Fatalf("WriteFile main.go failed: %v", err)
-	}
-	if err := os.WriteFile(filepath.Join(tmpDir, "internal", "x", "x.go"), []byte(xSource), 0o644); err != nil {
-		t.Fatalf("WriteFile x.go failed: %v", err)
+	for _, rel := range []string{"internal/api/api.go", "internal/api/api_test.go", "cmd/tool/main.go"} {
+		path := filepath.Join(tmpDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n\nfunc F() {}\n"), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
 	}
 
 	originalStdout := os.Stdout
@@ -462,17 +900,10 @@ const Value = 1
 		os.Stdout = originalStdout
 	}()
 
-	runErr := runDeps([]string{
-		tmpDir,
-		"--by", "package",
-		"--top", "5",
-		"--focus", ".",
-		"--depth", "2",
-		"--reverse",
-	})
+	runErr := runFiles([]string{tmpDir, "--no-cache", "--sort", "path", "--path", "internal/...", "--path", "!*_test.go"})
 	_ = writePipe.Close()
 	if runErr != nil {
-		t.Fatalf("runDeps returned error: %v", runErr)
+		t.Fatalf("runFiles returned error: %v", runErr)
 	}
 
 	var output bytes.Buffer
@@ -480,24 +911,198 @@ const Value = 1
 		t.Fatalf("ReadFrom failed: %v", err)
 	}
 	text := output.String()
-	for _, expected := range []string{"deps: mode=package", "top outgoing", "top incoming", "focus: . direction=reverse depth=2"} {
-		if !strings.Contains(text, expected) {
-			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
-		}
+	if !strings.Contains(text, "files: total=1 shown=1") || !strings.Contains(text, "internal/api/api.go ") {
+		t.Fatalf("expected only internal/api/api.go, got:\n%s", text)
 	}
 }
 
-func TestRunBridge(t *testing.T) {
+func TestProjectConfigSetsDefaults(t *testing.T) {
 	tmpDir := t.TempDir()
-	if err := os.MkdirAll(filepath.Join(tmpDir, "internal", "x"), 0o755); err != nil {
-		t.Fatalf("MkdirAll failed: %v", err)
+	files := map[string]string{
+		".gts/config.yaml": `root: src
+exclude: [vendor]
+commands:
+  index files:
+    sort: path
+    no-cache: true
+`,
+		"src/api/api.go":      "package api\n\nfunc F() {}\n",
+		"src/vendor/dep/d.go": "package dep\n\nfunc D() {}\n",
+		"docs/tool/main.go":   "package main\n\nfunc main() {}\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
 	}
-	mainSource := `package main
-
-import (
-	"fmt"
-	"sample/internal/x"
-)
+	t.Chdir(filepath.Join(tmpDir, "docs"))
+	defer func() { projectConfig = nil }()
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	root := newRootCmd()
+	root.SetArgs([]string{"index", "files"})
+	runErr := root.Execute()
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("gts index files returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	text := output.String()
+	if !strings.Contains(text, "files: total=1 shown=1") || !strings.Contains(text, "api/api.go ") {
+		t.Fatalf("expected only src/api/api.go, got:\n%s", text)
+	}
+
+	badConfig := "commands:\n  index files:\n    colour: red\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".gts", "config.yaml"), []byte(badConfig), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	root = newRootCmd()
+	root.SetArgs([]string{"index", "files"})
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "unknown flag --colour") {
+		t.Fatalf("expected an unknown flag error, got %v", err)
+	}
+}
+
+func TestReadOnlyRefusesWriteFlags(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := "package x\n\nfunc Old() {}\n"
+	path := filepath.Join(tmpDir, "x.go")
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	run := func(args ...string) error {
+		root := newRootCmd()
+		root.SetArgs(args)
+		root.SilenceUsage = true
+		root.SilenceErrors = true
+		return root.Execute()
+	}
+	err := run("--read-only", "transform", "refactor", "function_declaration[name=/^Old$/]", "New", tmpDir, "--no-cache", "--write")
+	if err == nil || !strings.Contains(err.Error(), "--write is disabled in read-only mode") {
+		t.Fatalf("expected refactor --write to be refused, got %v", err)
+	}
+
+	t.Setenv(readOnlyEnv, "1")
+	if err := run("graph", "dead", tmpDir, "--no-cache", "--write"); err == nil || !strings.Contains(err.Error(), "read-only mode") {
+		t.Fatalf("expected dead --write to be refused, got %v", err)
+	}
+	if err := run("mcp", "--allow-writes"); err == nil || !strings.Contains(err.Error(), "--allow-writes is disabled") {
+		t.Fatalf("expected mcp --allow-writes to be refused, got %v", err)
+	}
+	docsDir := filepath.Join(tmpDir, "docs")
+	if err := run("docgen", tmpDir, "--no-cache", "--out", docsDir); err == nil || !strings.Contains(err.Error(), "docgen writes files and is disabled in read-only mode") {
+		t.Fatalf("expected docgen to be refused, got %v", err)
+	}
+	if _, err := os.Stat(docsDir); !os.IsNotExist(err) {
+		t.Fatalf("expected no docs to be written, got %v", err)
+	}
+	if err := run("docgen", tmpDir, "--no-cache", "--out", docsDir, "--check"); err != nil && strings.Contains(err.Error(), "read-only mode") {
+		t.Fatalf("expected docgen --check to be allowed, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != source {
+		t.Fatalf("expected source to be untouched, got:\n%s", data)
+	}
+}
+
+func TestIndexMergeCombinesRepos(t *testing.T) {
+	tmpDir := t.TempDir()
+	var inputs []string
+	for _, repo := range []string{"api", "web"} {
+		dir := filepath.Join(tmpDir, repo)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		source := "package " + repo + "\n\nfunc Handle() {}\n"
+		if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		idx, err := index.NewBuilder().BuildPath(dir)
+		if err != nil {
+			t.Fatalf("BuildPath failed: %v", err)
+		}
+		cachePath := filepath.Join(tmpDir, repo+".json")
+		if err := index.Save(cachePath, idx); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		inputs = append(inputs, cachePath)
+	}
+
+	out := filepath.Join(tmpDir, "merged.json")
+	cmd := newIndexMergeCmd()
+	cmd.SetArgs(append(inputs, "--out", out, "--json"))
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	runErr := cmd.Execute()
+	_ = writePipe.Close()
+	os.Stdout = originalStdout
+	if runErr != nil {
+		t.Fatalf("gts index merge returned error: %v", runErr)
+	}
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	if !strings.Contains(output.String(), `"files": 2`) {
+		t.Fatalf("expected a report of 2 files, got:\n%s", output.String())
+	}
+
+	merged, err := index.Load(out)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if merged.Root != tmpDir || merged.FileCount() != 2 || merged.Files[0].Path != "api/main.go" || merged.Files[1].Path != "web/main.go" {
+		t.Fatalf("unexpected merged index: root=%s files=%+v", merged.Root, merged.Files)
+	}
+
+	cmd = newIndexMergeCmd()
+	cmd.SetArgs([]string{inputs[0], inputs[0], "--out", out})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--on-conflict") {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+}
+
+func TestRunDeps(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "internal", "x"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	mainSource := `package main
+
+import (
+	"fmt"
+	"sample/internal/x"
+)
 
 func main() {
 	_ = fmt.Sprintf("%v", x.Value)
@@ -527,16 +1132,17 @@ const Value = 1
 		os.Stdout = originalStdout
 	}()
 
-	runErr := runBridge([]string{
+	runErr := runDeps([]string{
 		tmpDir,
+		"--by", "package",
 		"--top", "5",
-		"--focus", "internal/x",
+		"--focus", ".",
 		"--depth", "2",
 		"--reverse",
 	})
 	_ = writePipe.Close()
 	if runErr != nil {
-		t.Fatalf("runBridge returned error: %v", runErr)
+		t.Fatalf("runDeps returned error: %v", runErr)
 	}
 
 	var output bytes.Buffer
@@ -544,23 +1150,41 @@ const Value = 1
 		t.Fatalf("ReadFrom failed: %v", err)
 	}
 	text := output.String()
-	for _, expected := range []string{"bridge:", "components:", "top bridges", "focus: internal/x direction=reverse depth=2", "external pressure"} {
+	for _, expected := range []string{"deps: mode=package", "top outgoing", "top incoming", "focus: . direction=reverse depth=2"} {
 		if !strings.Contains(text, expected) {
 			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
 		}
 	}
 }
 
-func TestRunGrepCount(t *testing.T) {
+func TestRunBridge(t *testing.T) {
 	tmpDir := t.TempDir()
-	sourcePath := filepath.Join(tmpDir, "main.go")
-	source := `package sample
+	if err := os.MkdirAll(filepath.Join(tmpDir, "internal", "x"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	mainSource := `package main
 
-func A() {}
-func B() {}
+import (
+	"fmt"
+	"sample/internal/x"
+)
+
+func main() {
+	_ = fmt.Sprintf("%v", x.Value)
+}
 `
-	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
-		t.Fatalf("WriteFile failed: %v", err)
+	xSource := `package x
+
+const Value = 1
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module sample\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile go.mod failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(mainSource), 0o644); err != nil {
+		t.Fatalf("WriteFile main.go failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "internal", "x", "x.go"), []byte(xSource), 0o644); err != nil {
+		t.Fatalf("WriteFile x.go failed: %v", err)
 	}
 
 	originalStdout := os.Stdout
@@ -573,35 +1197,37 @@ func B() {}
 		os.Stdout = originalStdout
 	}()
 
-	runErr := runGrep([]string{
-		"function_definition[name=/./]",
+	runErr := runBridge([]string{
 		tmpDir,
-		"--count",
+		"--top", "5",
+		"--focus", "internal/x",
+		"--depth", "2",
+		"--reverse",
 	})
 	_ = writePipe.Close()
 	if runErr != nil {
-		t.Fatalf("runGrep returned error: %v", runErr)
+		t.Fatalf("runBridge returned error: %v", runErr)
 	}
 
 	var output bytes.Buffer
 	if _, err := output.ReadFrom(readPipe); err != nil {
 		t.Fatalf("ReadFrom failed: %v", err)
 	}
-	if strings.TrimSpace(output.String()) != "2" {
-		t.Fatalf("unexpected count output %q", output.String())
+	text := output.String()
+	for _, expected := range []string{"bridge:", "components:", "top bridges", "focus: internal/x direction=reverse depth=2", "external pressure"} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
+		}
 	}
 }
 
-func TestRunRefsCount(t *testing.T) {
+func TestRunGrepCount(t *testing.T) {
 	tmpDir := t.TempDir()
 	sourcePath := filepath.Join(tmpDir, "main.go")
 	source := `package sample
 
 func A() {}
-
-func Use() {
-	A()
-}
+func B() {}
 `
 	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
 		t.Fatalf("WriteFile failed: %v", err)
@@ -617,37 +1243,41 @@ func Use() {
 		os.Stdout = originalStdout
 	}()
 
-	runErr := runRefs([]string{
-		"A",
+	runErr := runGrep([]string{
+		"function_definition[name=/./]",
 		tmpDir,
 		"--count",
 	})
 	_ = writePipe.Close()
 	if runErr != nil {
-		t.Fatalf("runRefs returned error: %v", runErr)
+		t.Fatalf("runGrep returned error: %v", runErr)
 	}
 
 	var output bytes.Buffer
 	if _, err := output.ReadFrom(readPipe); err != nil {
 		t.Fatalf("ReadFrom failed: %v", err)
 	}
-	if strings.TrimSpace(output.String()) != "1" {
-		t.Fatalf("unexpected refs count output %q", output.String())
+	if strings.TrimSpace(output.String()) != "2" {
+		t.Fatalf("unexpected count output %q", output.String())
 	}
 }
 
-func TestRunCallgraphCount(t *testing.T) {
+func TestRunGrepSavedQuery(t *testing.T) {
 	tmpDir := t.TempDir()
-	sourcePath := filepath.Join(tmpDir, "main.go")
 	source := `package sample
 
 func A() {}
-
-func main() {
-	A()
-}
+func B() {}
+func TestC() {}
 `
-	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".gts"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	queriesFile := "tests:\n  pattern: function_definition[name=/^Test/]\n  description: test functions\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".gts", "queries.yaml"), []byte(queriesFile), 0o644); err != nil {
 		t.Fatalf("WriteFile failed: %v", err)
 	}
 
@@ -661,37 +1291,43 @@ func main() {
 		os.Stdout = originalStdout
 	}()
 
-	runErr := runCallgraph([]string{
-		"main",
-		tmpDir,
-		"--depth",
-		"2",
-		"--count",
-	})
+	runErr := runGrep([]string{"@tests", tmpDir, "--count", "--no-cache"})
+	listErr := runQueriesList(tmpDir, false)
 	_ = writePipe.Close()
 	if runErr != nil {
-		t.Fatalf("runCallgraph returned error: %v", runErr)
+		t.Fatalf("runGrep returned error: %v", runErr)
+	}
+	if listErr != nil {
+		t.Fatalf("runQueriesList returned error: %v", listErr)
 	}
 
 	var output bytes.Buffer
 	if _, err := output.ReadFrom(readPipe); err != nil {
 		t.Fatalf("ReadFrom failed: %v", err)
 	}
-	if strings.TrimSpace(output.String()) != "1" {
-		t.Fatalf("unexpected callgraph count output %q", output.String())
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if lines[0] != "1" {
+		t.Fatalf("unexpected count output %q", output.String())
+	}
+	if !strings.Contains(output.String(), "@tests [auto] function_definition[name=/^Test/]") {
+		t.Fatalf("queries list missing tests entry:\n%s", output.String())
+	}
+
+	err = runGrep([]string{"@missing", tmpDir})
+	if err == nil || !strings.Contains(err.Error(), "available: tests") {
+		t.Fatalf("expected unknown-query error listing available queries, got %v", err)
 	}
 }
 
-func TestRunDeadCount(t *testing.T) {
+func TestRunRefsCount(t *testing.T) {
 	tmpDir := t.TempDir()
 	sourcePath := filepath.Join(tmpDir, "main.go")
 	source := `package sample
 
-func Used() {}
-func Dead() {}
+func A() {}
 
-func main() {
-	Used()
+func Use() {
+	A()
 }
 `
 	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
@@ -708,15 +1344,14 @@ func main() {
 		os.Stdout = originalStdout
 	}()
 
-	runErr := runDead([]string{
+	runErr := runRefs([]string{
+		"A",
 		tmpDir,
-		"--kind",
-		"function",
 		"--count",
 	})
 	_ = writePipe.Close()
 	if runErr != nil {
-		t.Fatalf("runDead returned error: %v", runErr)
+		t.Fatalf("runRefs returned error: %v", runErr)
 	}
 
 	var output bytes.Buffer
@@ -724,21 +1359,17 @@ func main() {
 		t.Fatalf("ReadFrom failed: %v", err)
 	}
 	if strings.TrimSpace(output.String()) != "1" {
-		t.Fatalf("unexpected dead count output %q", output.String())
+		t.Fatalf("unexpected refs count output %q", output.String())
 	}
 }
 
-func TestRunQueryCount(t *testing.T) {
+func TestRunGrepAndRefsVimgrepFormat(t *testing.T) {
 	tmpDir := t.TempDir()
-	sourcePath := filepath.Join(tmpDir, "main.go")
-	source := `package sample
-
-func A() {}
-func B() {}
-`
-	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+	source := "package sample\n\nfunc A() {}\n\nfunc Use() {\n\tA()\n}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
 		t.Fatalf("WriteFile failed: %v", err)
 	}
+	t.Chdir(tmpDir)
 
 	originalStdout := os.Stdout
 	readPipe, writePipe, err := os.Pipe()
@@ -750,82 +1381,169 @@ func B() {}
 		os.Stdout = originalStdout
 	}()
 
-	runErr := runQuery([]string{
-		"(function_declaration (identifier) @name)",
-		tmpDir,
-		"--count",
-	})
+	selectorErr := runGrep([]string{"function_definition[name=/^A$/]", tmpDir, "--format", "vimgrep", "--no-cache"})
+	structuralErr := runGrep([]string{"-S", "A()", tmpDir, "--format", "vimgrep"})
+	refsErr := runRefs([]string{"A", tmpDir, "--format", "vimgrep", "--no-cache"})
 	_ = writePipe.Close()
-	if runErr != nil {
-		t.Fatalf("runQuery returned error: %v", runErr)
+	for _, err := range []error{selectorErr, structuralErr, refsErr} {
+		if err != nil {
+			t.Fatalf("run returned error: %v", err)
+		}
 	}
 
 	var output bytes.Buffer
 	if _, err := output.ReadFrom(readPipe); err != nil {
 		t.Fatalf("ReadFrom failed: %v", err)
 	}
-	if strings.TrimSpace(output.String()) != "2" {
-		t.Fatalf("unexpected query count output %q", output.String())
+	want := "main.go:3:1: function_definition func A()\n" +
+		"main.go:6:2: A()\n" +
+		"main.go:6:2: reference.call A\n"
+	if output.String() != want {
+		t.Fatalf("vimgrep output = %q, want %q", output.String(), want)
+	}
+
+	if err := runRefs([]string{"A", tmpDir, "--format", "xml"}); err == nil || !strings.Contains(err.Error(), "expected text|json|vimgrep") {
+		t.Fatalf("expected unsupported format error, got %v", err)
 	}
 }
 
-func TestRunScope(t *testing.T) {
+func TestRunDoctor(t *testing.T) {
 	tmpDir := t.TempDir()
-	sourcePath := filepath.Join(tmpDir, "main.go")
-	source := `package sample
-
-import "fmt"
-
-func work(input string) {
-	value := input
-	fmt.Println(value)
-}
-`
-	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package sample\n\nfunc A() {}\n"), 0o644); err != nil {
 		t.Fatalf("WriteFile failed: %v", err)
 	}
-
-	originalStdout := os.Stdout
-	readPipe, writePipe, err := os.Pipe()
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
 	if err != nil {
-		t.Fatalf("os.Pipe failed: %v", err)
+		t.Fatalf("BuildPath failed: %v", err)
 	}
-	os.Stdout = writePipe
-	defer func() {
+	cachePath := filepath.Join(tmpDir, ".gts", "index.json")
+	if err := index.Save(cachePath, idx); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	doctor := func() (doctorReport, error) {
+		t.Helper()
+		originalStdout := os.Stdout
+		readPipe, writePipe, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("os.Pipe failed: %v", err)
+		}
+		os.Stdout = writePipe
+		runErr := runDoctor([]string{tmpDir, "--json"})
+		_ = writePipe.Close()
 		os.Stdout = originalStdout
-	}()
 
-	runErr := runScope([]string{
-		sourcePath,
-		"--root", tmpDir,
-		"--line", "7",
+		var report doctorReport
+		if err := json.NewDecoder(readPipe).Decode(&report); err != nil {
+			t.Fatalf("decode doctor report: %v", err)
+		}
+		return report, runErr
+	}
+	statuses := func(report doctorReport) map[string]string {
+		byName := map[string]string{}
+		for _, check := range report.Checks {
+			if byName[check.Name] != doctorFail {
+				byName[check.Name] = check.Status
+			}
+		}
+		return byName
+	}
+
+	report, err := doctor()
+	if err != nil {
+		t.Fatalf("runDoctor returned error: %v", err)
+	}
+	got := statuses(report)
+	if got["grammars"] != doctorOK || got["cache"] != doctorOK {
+		t.Fatalf("unexpected statuses %v in %+v", got, report.Checks)
+	}
+	for _, name := range []string{"watch", "go"} {
+		if got[name] == "" {
+			t.Fatalf("missing %s check in %+v", name, report.Checks)
+		}
+	}
+
+	if err := os.WriteFile(cachePath, []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	report, err = doctor()
+	if err == nil || !strings.Contains(err.Error(), "1 failing checks") {
+		t.Fatalf("expected a failing cache check, got %v", err)
+	}
+	for _, check := range report.Checks {
+		if check.Name == "cache" && check.Status == doctorFail && !strings.Contains(check.Fix, "gts index build") {
+			t.Fatalf("cache failure lacks a fix: %+v", check)
+		}
+	}
+}
+
+func TestRunRefsAcrossIndexes(t *testing.T) {
+	tmpDir := t.TempDir()
+	repos := map[string]string{
+		"lib": "package lib\n\nfunc Shared() {}\n\nfunc init() {\n\tShared()\n}\n",
+		"api": "package api\n\nfunc Handle() {\n\tShared()\n}\n",
+	}
+	for repo, source := range repos {
+		if err := os.MkdirAll(filepath.Join(tmpDir, repo), 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, repo, "main.go"), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	apiIndex, err := index.NewBuilder().BuildPath(filepath.Join(tmpDir, "api"))
+	if err != nil {
+		t.Fatalf("BuildPath failed: %v", err)
+	}
+	apiCache := filepath.Join(tmpDir, "api.json")
+	if err := index.Save(apiCache, apiIndex); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runRefs([]string{
+		"Shared",
+		filepath.Join(tmpDir, "lib"),
+		"--no-cache",
+		"--index", apiCache,
+		"--json",
 	})
 	_ = writePipe.Close()
 	if runErr != nil {
-		t.Fatalf("runScope returned error: %v", runErr)
+		t.Fatalf("runRefs returned error: %v", runErr)
 	}
 
-	var output bytes.Buffer
-	if _, err := output.ReadFrom(readPipe); err != nil {
-		t.Fatalf("ReadFrom failed: %v", err)
+	var payload report.RefsReport
+	if err := json.NewDecoder(readPipe).Decode(&payload); err != nil {
+		t.Fatalf("Decode failed: %v", err)
 	}
-	text := output.String()
-	for _, expected := range []string{"package: sample", "input (param)", "value (local_var)", "fmt (import)"} {
-		if !strings.Contains(text, expected) {
-			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
-		}
+	matches := payload.Matches
+	if payload.Count != 2 || len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %+v", matches)
+	}
+	if matches[0].Repo != "api" || matches[1].Repo != "lib" {
+		t.Fatalf("expected matches labeled api and lib, got %+v", matches)
 	}
 }
 
-func TestRunContextSemantic(t *testing.T) {
+func TestRunCallgraphCount(t *testing.T) {
 	tmpDir := t.TempDir()
 	sourcePath := filepath.Join(tmpDir, "main.go")
 	source := `package sample
 
-func helper() {}
+func A() {}
 
-func work() {
-	helper()
+func main() {
+	A()
 }
 `
 	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
@@ -842,47 +1560,41 @@ func work() {
 		os.Stdout = originalStdout
 	}()
 
-	runErr := runContext([]string{
-		sourcePath,
-		"--root", tmpDir,
-		"--line", "6",
-		"--tokens", "400",
-		"--semantic",
+	runErr := runCallgraph([]string{
+		"main",
+		tmpDir,
+		"--depth",
+		"2",
+		"--count",
 	})
 	_ = writePipe.Close()
 	if runErr != nil {
-		t.Fatalf("runContext returned error: %v", runErr)
+		t.Fatalf("runCallgraph returned error: %v", runErr)
 	}
 
 	var output bytes.Buffer
 	if _, err := output.ReadFrom(readPipe); err != nil {
 		t.Fatalf("ReadFrom failed: %v", err)
 	}
-	text := output.String()
-	for _, expected := range []string{"semantic: true", "focus: function_definition func work()", "related:", "helper"} {
-		if !strings.Contains(text, expected) {
-			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
-		}
+	if strings.TrimSpace(output.String()) != "1" {
+		t.Fatalf("unexpected callgraph count output %q", output.String())
 	}
 }
 
-func TestRunContextSemanticDepth(t *testing.T) {
+func TestRunCallgraphAggregatePackage(t *testing.T) {
 	tmpDir := t.TempDir()
-	sourcePath := filepath.Join(tmpDir, "main.go")
-	source := `package sample
-
-func leaf() {}
-
-func mid() {
-	leaf()
-}
-
-func work() {
-	mid()
-}
-`
-	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
-		t.Fatalf("WriteFile failed: %v", err)
+	files := map[string]string{
+		"api/api.go":     "package api\n\nfunc Handle() {\n\tSave()\n}\n\nfunc Shutdown() {\n\tSave()\n}\n",
+		"store/store.go": "package store\n\nfunc Save() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
 	}
 
 	originalStdout := os.Stdout
@@ -895,17 +1607,10 @@ func work() {
 		os.Stdout = originalStdout
 	}()
 
-	runErr := runContext([]string{
-		sourcePath,
-		"--root", tmpDir,
-		"--line", "10",
-		"--tokens", "400",
-		"--semantic",
-		"--semantic-depth", "2",
-	})
+	runErr := runCallgraph([]string{"Handle", tmpDir, "--entry", "Shutdown", "--aggregate", "package"})
 	_ = writePipe.Close()
 	if runErr != nil {
-		t.Fatalf("runContext returned error: %v", runErr)
+		t.Fatalf("runCallgraph returned error: %v", runErr)
 	}
 
 	var output bytes.Buffer
@@ -913,25 +1618,36 @@ func work() {
 		t.Fatalf("ReadFrom failed: %v", err)
 	}
 	text := output.String()
-	for _, expected := range []string{"semantic: true", "semantic-depth: 2", "mid", "leaf"} {
-		if !strings.Contains(text, expected) {
-			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
-		}
+	if !strings.Contains(text, "roots=2 packages=2 package_edges=1") || !strings.Contains(text, "api -> store calls=2 edges=2") {
+		t.Fatalf("unexpected aggregate output %q", text)
 	}
 }
 
-func TestRunChunk(t *testing.T) {
+func TestRunImpactSnapshots(t *testing.T) {
 	tmpDir := t.TempDir()
 	sourcePath := filepath.Join(tmpDir, "main.go")
-	source := `package sample
-
-func A() {}
-func B() {}
-`
-	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
-		t.Fatalf("WriteFile failed: %v", err)
+	write := func(source string) {
+		if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	snapshot := func(name string) string {
+		idx, err := index.NewBuilder().BuildPath(tmpDir)
+		if err != nil {
+			t.Fatalf("BuildPath returned error: %v", err)
+		}
+		path := filepath.Join(t.TempDir(), name)
+		if err := index.Save(path, idx); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+		return path
 	}
 
+	write("package sample\n\nfunc Validate(x int) {\n\tprintln(x)\n}\n\nfunc Handle() {\n\tValidate(1)\n}\n")
+	beforePath := snapshot("before.json")
+	write("package sample\n\nfunc Validate(x, y int) {\n\tprintln(x, y)\n}\n\nfunc Handle() {\n\tValidate(1, 0)\n}\n")
+	afterPath := snapshot("after.json")
+
 	originalStdout := os.Stdout
 	readPipe, writePipe, err := os.Pipe()
 	if err != nil {
@@ -942,13 +1658,10 @@ func B() {}
 		os.Stdout = originalStdout
 	}()
 
-	runErr := runChunk([]string{
-		tmpDir,
-		"--tokens", "200",
-	})
+	runErr := runImpact([]string{"--before-cache", beforePath, "--after-cache", afterPath, "--depth", "2"})
 	_ = writePipe.Close()
 	if runErr != nil {
-		t.Fatalf("runChunk returned error: %v", runErr)
+		t.Fatalf("runImpact returned error: %v", runErr)
 	}
 
 	var output bytes.Buffer
@@ -956,195 +1669,1629 @@ func B() {}
 		t.Fatalf("ReadFrom failed: %v", err)
 	}
 	text := output.String()
-	for _, expected := range []string{"chunks:", "function_definition", "func A()"} {
-		if !strings.Contains(text, expected) {
-			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
-		}
+	if !strings.Contains(text, "~ main.go:3-5 function_definition Validate") || !strings.Contains(text, "main.go:7-9 Handle distance=1") {
+		t.Fatalf("unexpected impact output %q", text)
+	}
+	if !strings.Contains(text, "impact: changed=1 affected=1 files=1 packages=1") {
+		t.Fatalf("unexpected impact summary %q", text)
 	}
 }
 
-func TestRunRefactorDryRunAndWrite(t *testing.T) {
+func TestRunDeadCount(t *testing.T) {
 	tmpDir := t.TempDir()
 	sourcePath := filepath.Join(tmpDir, "main.go")
 	source := `package sample
 
-func OldName() {}
+func Used() {}
+func Dead() {}
+
+func main() {
+	Used()
+}
 `
 	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
 		t.Fatalf("WriteFile failed: %v", err)
 	}
 
-	// Dry-run should not mutate.
-	if err := runRefactor([]string{
-		"function_definition[name=/^OldName$/]",
-		"NewName",
-		tmpDir,
-	}); err != nil {
-		t.Fatalf("runRefactor dry-run returned error: %v", err)
-	}
-
-	afterDryRun, err := os.ReadFile(sourcePath)
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
 	if err != nil {
-		t.Fatalf("ReadFile after dry-run failed: %v", err)
-	}
-	if !strings.Contains(string(afterDryRun), "OldName") {
-		t.Fatalf("expected dry-run to preserve original name, got:\n%s", string(afterDryRun))
+		t.Fatalf("os.Pipe failed: %v", err)
 	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
 
-	// Write mode should apply rename.
-	if err := runRefactor([]string{
-		"function_definition[name=/^OldName$/]",
-		"NewName",
+	runErr := runDead([]string{
 		tmpDir,
-		"--write",
-	}); err != nil {
-		t.Fatalf("runRefactor write returned error: %v", err)
+		"--kind",
+		"function",
+		"--count",
+	})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runDead returned error: %v", runErr)
 	}
 
-	afterWrite, err := os.ReadFile(sourcePath)
-	if err != nil {
-		t.Fatalf("ReadFile after write failed: %v", err)
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
 	}
-	if !strings.Contains(string(afterWrite), "NewName") {
-		t.Fatalf("expected write to apply rename, got:\n%s", string(afterWrite))
+	if strings.TrimSpace(output.String()) != "1" {
+		t.Fatalf("unexpected dead count output %q", output.String())
 	}
 }
 
-func TestRunRefactorCallsites(t *testing.T) {
+func TestRunDeadReactComponents(t *testing.T) {
 	tmpDir := t.TempDir()
-	defPath := filepath.Join(tmpDir, "a.go")
-	usePath := filepath.Join(tmpDir, "b.go")
-
-	defSource := `package sample
+	files := map[string]string{
+		"main.tsx": "import { App } from \"./App\";\n\ncreateRoot(document.body).render(<App />);\n",
+		"App.tsx": `import { Layout } from "./Layout";
 
-func OldName() {}
-`
-	useSource := `package sample
+export function App() {
+  const n = useCounter();
+  return <Layout>{n}</Layout>;
+}
 
-func Use() {
-	OldName()
+export function useCounter() {
+  return 1;
 }
-`
-	if err := os.WriteFile(defPath, []byte(defSource), 0o644); err != nil {
-		t.Fatalf("WriteFile a.go failed: %v", err)
-	}
-	if err := os.WriteFile(usePath, []byte(useSource), 0o644); err != nil {
-		t.Fatalf("WriteFile b.go failed: %v", err)
+`,
+		"Layout.tsx": "export const Layout = ({ children }) => <main>{children}</main>;\n\nexport const Sidebar = () => <aside />;\n",
 	}
-
-	if err := runRefactor([]string{
-		"function_definition[name=/^OldName$/]",
-		"NewName",
-		tmpDir,
-		"--callsites",
-		"--write",
-	}); err != nil {
-		t.Fatalf("runRefactor callsites write returned error: %v", err)
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
 	}
 
-	afterUse, err := os.ReadFile(usePath)
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
 	if err != nil {
-		t.Fatalf("ReadFile b.go failed: %v", err)
+		t.Fatalf("os.Pipe failed: %v", err)
 	}
-	if !strings.Contains(string(afterUse), "NewName()") {
-		t.Fatalf("expected callsite to be renamed, got:\n%s", string(afterUse))
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runDead([]string{tmpDir, "--no-cache"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runDead returned error: %v", runErr)
 	}
-}
 
-func TestRunRefactorCrossPackageCallsites(t *testing.T) {
-	tmpDir := t.TempDir()
-	if err := os.MkdirAll(filepath.Join(tmpDir, "lib"), 0o755); err != nil {
-		t.Fatalf("MkdirAll lib failed: %v", err)
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
 	}
-	if err := os.MkdirAll(filepath.Join(tmpDir, "app"), 0o755); err != nil {
-		t.Fatalf("MkdirAll app failed: %v", err)
+	text := output.String()
+	if !strings.Contains(text, "component_definition const Sidebar") {
+		t.Fatalf("expected the unrendered Sidebar component to be dead, got %q", text)
 	}
-	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module sample\n"), 0o644); err != nil {
-		t.Fatalf("WriteFile go.mod failed: %v", err)
+	for _, used := range []string{"App", "Layout", "useCounter"} {
+		if strings.Contains(text, " "+used) {
+			t.Fatalf("expected %s to be used, got %q", used, text)
+		}
 	}
+}
 
-	libSource := `package lib
+func TestRunDeadPythonDynamicRoots(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `__all__ = ["public_helper"]
 
-func OldName() {}
-`
-	appSource := `package app
 
-import "sample/lib"
+@app.route("/")
+def index():
+    pass
 
-func Use() {
+
+@plugins.register
+def plugin():
+    pass
+
+
+def public_helper():
+    pass
+
+
+def handle_create():
+    pass
+
+
+def dispatch(kind):
+    return getattr(handlers, "handle_" + kind)()
+
+
+def really_dead():
+    pass
+
+
+dispatch("create")
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "views.py"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		originalStdout := os.Stdout
+		readPipe, writePipe, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("os.Pipe failed: %v", err)
+		}
+		os.Stdout = writePipe
+		defer func() {
+			os.Stdout = originalStdout
+		}()
+
+		runErr := runDead(append([]string{tmpDir, "--no-cache"}, args...))
+		_ = writePipe.Close()
+		if runErr != nil {
+			t.Fatalf("runDead returned error: %v", runErr)
+		}
+		var output bytes.Buffer
+		if _, err := output.ReadFrom(readPipe); err != nil {
+			t.Fatalf("ReadFrom failed: %v", err)
+		}
+		return output.String()
+	}
+
+	text := run("--root-decorator", `^@plugins\.`)
+	if !strings.Contains(text, "def really_dead()") {
+		t.Fatalf("expected really_dead to be dead, got %q", text)
+	}
+	for _, used := range []string{"index", "plugin", "public_helper", "handle_create"} {
+		if strings.Contains(text, "def "+used+"(") {
+			t.Fatalf("expected %s to be a dynamic root, got %q", used, text)
+		}
+	}
+	if !strings.Contains(text, "dynamic_roots=4") {
+		t.Fatalf("expected 4 dynamic roots in the summary, got %q", text)
+	}
+
+	text = run("--no-dynamic-roots")
+	for _, name := range []string{"index", "plugin", "public_helper", "handle_create"} {
+		if !strings.Contains(text, "def "+name+"(") {
+			t.Fatalf("expected %s to be dead without dynamic roots, got %q", name, text)
+		}
+	}
+}
+
+func TestRunDeadUnexportedOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package sample
+
+func Exported() {}
+func unexported() {}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runDead([]string{tmpDir, "--kind", "function", "--unexported-only"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runDead returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	text := output.String()
+	if !strings.Contains(text, "unexported") || strings.Contains(text, "Exported()") {
+		t.Fatalf("expected only the unexported definition, got %q", text)
+	}
+}
+
+func TestRunDead_ResultCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(sourcePath, []byte("package sample\n\nfunc unused() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	count := func() string {
+		t.Helper()
+		originalStdout := os.Stdout
+		readPipe, writePipe, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("os.Pipe failed: %v", err)
+		}
+		os.Stdout = writePipe
+		runErr := runDead([]string{tmpDir, "--no-cache", "--result-cache", "--count"})
+		_ = writePipe.Close()
+		os.Stdout = originalStdout
+		if runErr != nil {
+			t.Fatalf("runDead returned error: %v", runErr)
+		}
+		var output bytes.Buffer
+		if _, err := output.ReadFrom(readPipe); err != nil {
+			t.Fatalf("ReadFrom failed: %v", err)
+		}
+		return strings.TrimSpace(output.String())
+	}
+
+	if got := count(); got != "1" {
+		t.Fatalf("expected 1 dead definition, got %q", got)
+	}
+	entries, err := filepath.Glob(filepath.Join(tmpDir, ".gts", "results", "*.json"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one cached result, got %v (err=%v)", entries, err)
+	}
+
+	// A planted entry is served as long as the index is unchanged.
+	if err := os.WriteFile(entries[0], []byte(`{"scanned":1,"matches":[]}`), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if got := count(); got != "0" {
+		t.Fatalf("expected the cached result to be reused, got %q", got)
+	}
+
+	if err := os.WriteFile(sourcePath, []byte("package sample\n\nfunc unused() {}\n\nfunc alsoUnused() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if got := count(); got != "2" {
+		t.Fatalf("expected an edit to bypass the cached result, got %q", got)
+	}
+}
+
+func TestRunDead_GitHubFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package sample
+
+func Exported() {}
+func unexported() {}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runDead([]string{tmpDir, "--kind", "function", "--unexported-only", "--format", "github"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runDead returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	want := "::warning file=main.go,line=4,title=dead-code::function_definition unexported has no incoming calls\n"
+	if got := output.String(); got != want {
+		t.Fatalf("unexpected annotation output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunDead_WriteRemovesDefinitions(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+func Used() {}
+
+// unused is never called.
+func unused() {
+	println("dead")
+}
+
+func main() {
+	Used()
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runDead([]string{tmpDir, "--no-cache", "--kind", "function", "--unexported-only", "--write", "--json"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runDead returned error: %v", runErr)
+	}
+
+	var payload struct {
+		Matches []deadMatch `json:"matches"`
+	}
+	if err := json.NewDecoder(readPipe).Decode(&payload); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(payload.Matches) != 1 {
+		t.Fatalf("expected one match, got %+v", payload.Matches)
+	}
+	match := payload.Matches[0]
+	if match.Deletion == nil || !match.Removed {
+		t.Fatalf("expected a removed match with a deletion range, got %+v", match)
+	}
+	if match.Deletion.StartLine != 5 || match.Deletion.EndLine != 9 {
+		t.Fatalf("unexpected deletion lines %d-%d", match.Deletion.StartLine, match.Deletion.EndLine)
+	}
+
+	updated, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	want := `package sample
+
+func Used() {}
+
+func main() {
+	Used()
+}
+`
+	if string(updated) != want {
+		t.Fatalf("unexpected source after --write:\n%s", updated)
+	}
+}
+
+func TestRunUnusedFields(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package sample
+
+type Config struct {
+	Name    string
+	verbose bool
+	retries int
+	Path    string ` + "`json:\"path\"`" + `
+}
+
+func (c *Config) Describe() string {
+	c.retries = 3
+	return c.Name
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runUnusedFields([]string{tmpDir, "--no-cache"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runUnusedFields returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	want := "config.go:5 Config.verbose writes=0\nconfig.go:6 Config.retries writes=1\nunused-fields: scanned=3 matches=2\n"
+	if got := output.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunQueryCount(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+func A() {}
+func B() {}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runQuery([]string{
+		"(function_declaration (identifier) @name)",
+		tmpDir,
+		"--count",
+	})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runQuery returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	if strings.TrimSpace(output.String()) != "2" {
+		t.Fatalf("unexpected query count output %q", output.String())
+	}
+}
+
+func TestRunQueryJSONCarriesSchemaVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package sample\n\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	runErr := runQuery([]string{"(function_declaration (identifier) @name)", tmpDir, "--no-cache", "--json"})
+	_ = writePipe.Close()
+	os.Stdout = originalStdout
+	if runErr != nil {
+		t.Fatalf("runQuery returned error: %v", runErr)
+	}
+
+	var output struct {
+		SchemaVersion int                 `json:"schema_version"`
+		Count         int                 `json:"count"`
+		Matches       []queryCaptureMatch `json:"matches"`
+	}
+	if err := json.NewDecoder(readPipe).Decode(&output); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if output.SchemaVersion != report.SchemaVersion || output.Count != 1 || output.Matches[0].Text != "A" {
+		t.Fatalf("unexpected query output %+v", output)
+	}
+
+	if _, ok := report.Schema("query"); !ok {
+		t.Fatal("expected a published schema for query output")
+	}
+}
+
+func TestRunQueryAcrossFiles_SpillsWithinBudget(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"b.go": "package sample\n\nfunc D() {}\nfunc C() {}\n",
+		"a.go": "package sample\n\nfunc B() {}\nfunc A() {}\n",
+	}
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath failed: %v", err)
+	}
+	queryFor := func(string) (string, bool) { return "(function_declaration (identifier) @name)", true }
+
+	inMemory, err := runQueryAcrossFiles(idx, queryFor, nil, 0, 0)
+	if err != nil {
+		t.Fatalf("runQueryAcrossFiles returned error: %v", err)
+	}
+	// A 1KB budget leaves 256 bytes for matches, so all but the first
+	// couple go to disk.
+	spilled, err := runQueryAcrossFiles(idx, queryFor, nil, 0, 1024)
+	if err != nil {
+		t.Fatalf("runQueryAcrossFiles returned error: %v", err)
+	}
+	if spilled.spool == nil {
+		t.Fatal("expected matches to spill past the budget")
+	}
+	defer spilled.spool.Close()
+
+	got, err := spilled.matches()
+	if err != nil {
+		t.Fatalf("reading spilled matches failed: %v", err)
+	}
+	if spilled.count() != 4 || !reflect.DeepEqual(got, inMemory.Results) {
+		t.Fatalf("expected spilled matches %+v to equal in-memory matches %+v", got, inMemory.Results)
+	}
+	if got[0].File != "a.go" || got[0].Text != "B" || got[3].Text != "C" {
+		t.Fatalf("expected matches sorted by file and line, got %+v", got)
+	}
+}
+
+func TestRunScope(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+import "fmt"
+
+func work(input string) {
+	value := input
+	fmt.Println(value)
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runScope([]string{
+		sourcePath,
+		"--root", tmpDir,
+		"--line", "7",
+	})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runScope returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	text := output.String()
+	for _, expected := range []string{"package: sample", "input (param)", "value (local_var)", "fmt (import)"} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestRunContextSemantic(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+func helper() {}
+
+func work() {
+	helper()
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runContext([]string{
+		sourcePath,
+		"--root", tmpDir,
+		"--line", "6",
+		"--tokens", "400",
+		"--semantic",
+	})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runContext returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	text := output.String()
+	for _, expected := range []string{"semantic: true", "focus: function_definition func work()", "related:", "helper"} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestRunContextSemanticDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+func leaf() {}
+
+func mid() {
+	leaf()
+}
+
+func work() {
+	mid()
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runContext([]string{
+		sourcePath,
+		"--root", tmpDir,
+		"--line", "10",
+		"--tokens", "400",
+		"--semantic",
+		"--semantic-depth", "2",
+	})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runContext returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	text := output.String()
+	for _, expected := range []string{"semantic: true", "semantic-depth: 2", "mid", "leaf"} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestRunChunk(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+func A() {}
+func B() {}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runChunk([]string{
+		tmpDir,
+		"--tokens", "200",
+	})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runChunk returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	text := output.String()
+	for _, expected := range []string{"chunks:", "function_definition", "func A()"} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestRunRefactorDryRunAndWrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+func OldName() {}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	// Dry-run should not mutate.
+	if err := runRefactor([]string{
+		"function_definition[name=/^OldName$/]",
+		"NewName",
+		tmpDir,
+	}); err != nil {
+		t.Fatalf("runRefactor dry-run returned error: %v", err)
+	}
+
+	afterDryRun, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile after dry-run failed: %v", err)
+	}
+	if !strings.Contains(string(afterDryRun), "OldName") {
+		t.Fatalf("expected dry-run to preserve original name, got:\n%s", string(afterDryRun))
+	}
+
+	// Write mode should apply rename.
+	if err := runRefactor([]string{
+		"function_definition[name=/^OldName$/]",
+		"NewName",
+		tmpDir,
+		"--write",
+	}); err != nil {
+		t.Fatalf("runRefactor write returned error: %v", err)
+	}
+
+	afterWrite, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile after write failed: %v", err)
+	}
+	if !strings.Contains(string(afterWrite), "NewName") {
+		t.Fatalf("expected write to apply rename, got:\n%s", string(afterWrite))
+	}
+}
+
+func TestRunRefactorMap(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+type OldType struct{}
+
+func OldName() {}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	mapPath := filepath.Join(t.TempDir(), "renames.csv")
+	if err := os.WriteFile(mapPath, []byte("old,new\nOldType,NewType,type_definition\nOldName,NewName\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := runRefactor([]string{"--map", mapPath, tmpDir, "--write"}); err != nil {
+		t.Fatalf("runRefactor --map returned error: %v", err)
+	}
+	afterWrite, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile after write failed: %v", err)
+	}
+	if got := string(afterWrite); !strings.Contains(got, "type NewType struct{}") || !strings.Contains(got, "func NewName()") {
+		t.Fatalf("expected both renames to apply, got:\n%s", got)
+	}
+
+	if err := runRefactor([]string{"--map", mapPath, "selector", "name", tmpDir}); err == nil {
+		t.Fatal("expected --map with selector arguments to fail")
+	}
+}
+
+func TestRunRefactorCallsites(t *testing.T) {
+	tmpDir := t.TempDir()
+	defPath := filepath.Join(tmpDir, "a.go")
+	usePath := filepath.Join(tmpDir, "b.go")
+
+	defSource := `package sample
+
+func OldName() {}
+`
+	useSource := `package sample
+
+func Use() {
+	OldName()
+}
+`
+	if err := os.WriteFile(defPath, []byte(defSource), 0o644); err != nil {
+		t.Fatalf("WriteFile a.go failed: %v", err)
+	}
+	if err := os.WriteFile(usePath, []byte(useSource), 0o644); err != nil {
+		t.Fatalf("WriteFile b.go failed: %v", err)
+	}
+
+	if err := runRefactor([]string{
+		"function_definition[name=/^OldName$/]",
+		"NewName",
+		tmpDir,
+		"--callsites",
+		"--write",
+	}); err != nil {
+		t.Fatalf("runRefactor callsites write returned error: %v", err)
+	}
+
+	afterUse, err := os.ReadFile(usePath)
+	if err != nil {
+		t.Fatalf("ReadFile b.go failed: %v", err)
+	}
+	if !strings.Contains(string(afterUse), "NewName()") {
+		t.Fatalf("expected callsite to be renamed, got:\n%s", string(afterUse))
+	}
+}
+
+func TestRunRefactorCrossPackageCallsites(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "lib"), 0o755); err != nil {
+		t.Fatalf("MkdirAll lib failed: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "app"), 0o755); err != nil {
+		t.Fatalf("MkdirAll app failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module sample\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile go.mod failed: %v", err)
+	}
+
+	libSource := `package lib
+
+func OldName() {}
+`
+	appSource := `package app
+
+import "sample/lib"
+
+func Use() {
 	lib.OldName()
 }
 `
-	libPath := filepath.Join(tmpDir, "lib", "lib.go")
-	appPath := filepath.Join(tmpDir, "app", "app.go")
-	if err := os.WriteFile(libPath, []byte(libSource), 0o644); err != nil {
-		t.Fatalf("WriteFile lib.go failed: %v", err)
+	libPath := filepath.Join(tmpDir, "lib", "lib.go")
+	appPath := filepath.Join(tmpDir, "app", "app.go")
+	if err := os.WriteFile(libPath, []byte(libSource), 0o644); err != nil {
+		t.Fatalf("WriteFile lib.go failed: %v", err)
+	}
+	if err := os.WriteFile(appPath, []byte(appSource), 0o644); err != nil {
+		t.Fatalf("WriteFile app.go failed: %v", err)
+	}
+
+	if err := runRefactor([]string{
+		"function_definition[name=/^OldName$/]",
+		"NewName",
+		tmpDir,
+		"--callsites",
+		"--cross-package",
+		"--write",
+	}); err != nil {
+		t.Fatalf("runRefactor cross-package write returned error: %v", err)
+	}
+
+	afterApp, err := os.ReadFile(appPath)
+	if err != nil {
+		t.Fatalf("ReadFile app.go failed: %v", err)
+	}
+	if !strings.Contains(string(afterApp), "lib.NewName()") {
+		t.Fatalf("expected cross-package callsite rename, got:\n%s", string(afterApp))
+	}
+}
+
+func TestRunRefactorTreeSitterEngine(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.js")
+	source := `function OldName() {}
+
+function Use() {
+	OldName()
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := runRefactor([]string{
+		"function_definition[name=/^OldName$/]",
+		"NewName",
+		tmpDir,
+		"--engine",
+		"treesitter",
+		"--callsites",
+		"--write",
+	}); err != nil {
+		t.Fatalf("runRefactor treesitter write returned error: %v", err)
+	}
+
+	after, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile main.js failed: %v", err)
+	}
+	if !strings.Contains(string(after), "function NewName()") || !strings.Contains(string(after), "NewName()") {
+		t.Fatalf("expected treesitter refactor rename, got:\n%s", string(after))
+	}
+}
+
+func assertExitCode(t *testing.T, err error, want int) {
+	t.Helper()
+	withCode, ok := err.(interface{ ExitCode() int })
+	if !ok {
+		t.Fatalf("expected error with exit code, got %T (%v)", err, err)
+	}
+	if got := withCode.ExitCode(); got != want {
+		t.Fatalf("unexpected exit code: got=%d want=%d err=%v", got, want, err)
+	}
+}
+
+func TestChunkSyncerEmitsEvents(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(sourcePath, []byte("package sample\n\nfunc A() {}\n\nfunc B() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	manifestPath := filepath.Join(t.TempDir(), "chunks.jsonl")
+	identity := func(idx *model.Index) *model.Index { return idx }
+
+	var out bytes.Buffer
+	syncer, err := newChunkSyncer(manifestPath, chunk.Options{TokenBudget: 200}, identity, &out)
+	if err != nil {
+		t.Fatalf("newChunkSyncer returned error: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	if _, err := syncer.Sync(idx); err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	if got := strings.Count(out.String(), `"event":"add"`); got != 3 {
+		t.Fatalf("expected 3 add events on first sync, got %d:\n%s", got, out.String())
+	}
+	if _, err := os.Stat(manifestPath); err != nil {
+		t.Fatalf("expected manifest to be written: %v", err)
+	}
+
+	// A fresh syncer resumes from the saved manifest.
+	if err := os.WriteFile(sourcePath, []byte("package sample\n\nfunc A() { println() }\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	out.Reset()
+	syncer, err = newChunkSyncer(manifestPath, chunk.Options{TokenBudget: 200}, identity, &out)
+	if err != nil {
+		t.Fatalf("newChunkSyncer returned error: %v", err)
+	}
+	idx, err = index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	count, err := syncer.Sync(idx)
+	if err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	output := out.String()
+	if count != 2 || !strings.Contains(output, `"event":"update"`) || !strings.Contains(output, `"event":"delete"`) {
+		t.Fatalf("expected one update and one delete, got %d events:\n%s", count, output)
+	}
+
+	out.Reset()
+	if count, err := syncer.Sync(idx); err != nil || count != 0 || out.Len() != 0 {
+		t.Fatalf("expected no events for an unchanged index, got %d (%v): %s", count, err, out.String())
+	}
+}
+
+func runGitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func TestRunHookChecks_StagedOnly(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	sourcePath := filepath.Join(repo, "main.go")
+	if err := os.WriteFile(sourcePath, []byte("package sample\n\nfunc Run() {\n\told()\n}\n\nfunc old() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	runGitCmd(t, repo, "init", "-q")
+	runGitCmd(t, repo, "add", ".")
+	runGitCmd(t, repo, "commit", "-q", "-m", "init")
+
+	enabled := map[string]bool{"dead": true}
+	result, err := runHookChecks(repo, "pre-commit", "", enabled, false)
+	if err != nil {
+		t.Fatalf("runHookChecks returned error: %v", err)
+	}
+	if result.Status != "SKIP" {
+		t.Fatalf("expected SKIP with nothing staged, got %+v", result)
+	}
+
+	// Stage a dead helper, then hide it in the worktree: the staged copy wins.
+	if err := os.WriteFile(sourcePath, []byte("package sample\n\nfunc Run() {\n\told()\n}\n\nfunc old() {}\n\nfunc unused() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	runGitCmd(t, repo, "add", "main.go")
+	if err := os.WriteFile(sourcePath, []byte("package sample\n\nfunc Run() {\n\told()\n}\n\nfunc old() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	result, err = runHookChecks(repo, "pre-commit", "", enabled, false)
+	if err != nil {
+		t.Fatalf("runHookChecks returned error: %v", err)
+	}
+	if result.Status != "FAIL" || len(result.Findings) != 1 {
+		t.Fatalf("expected one finding, got %+v", result)
+	}
+	finding := result.Findings[0]
+	if finding.Check != "dead" || finding.File != "main.go" || finding.Name != "unused" || finding.Line != 9 {
+		t.Fatalf("unexpected finding %+v", finding)
+	}
+
+	if err := runHook([]string{"install", repo}); err != nil {
+		t.Fatalf("hook install returned error: %v", err)
+	}
+	script, err := os.ReadFile(filepath.Join(repo, ".git", "hooks", "pre-commit"))
+	if err != nil {
+		t.Fatalf("expected pre-commit hook: %v", err)
+	}
+	if !strings.Contains(string(script), "gts hook run --stage pre-commit") {
+		t.Fatalf("unexpected hook script:\n%s", script)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".git", "hooks", "pre-push"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := runHook([]string{"install", "--stage", "pre-push", repo}); err == nil {
+		t.Fatal("expected install to refuse replacing a foreign hook")
+	}
+}
+
+func TestRunReview_MarkdownBetweenRevisions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	sourcePath := filepath.Join(repo, "main.go")
+	if err := os.WriteFile(sourcePath, []byte("package sample\n\nfunc Run() {\n\thelper()\n}\n\nfunc helper() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	runGitCmd(t, repo, "init", "-q")
+	runGitCmd(t, repo, "add", ".")
+	runGitCmd(t, repo, "commit", "-q", "-m", "before")
+
+	after := "package sample\n\nimport \"os\"\n\nfunc Run(code int) {\n\tos.Exit(code)\n}\n\nfunc New() {}\n\nfunc helper() {}\n"
+	if err := os.WriteFile(sourcePath, []byte(after), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	runGitCmd(t, repo, "commit", "-q", "-am", "after")
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runReview([]string{repo, "--before-rev", "HEAD~1", "--after-rev", "HEAD", "--format", "markdown"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runReview returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	text := output.String()
+	for _, expected := range []string{
+		"## Structural review",
+		"### Public API (1)",
+		"`func New()`",
+		"### Signature changes (1)",
+		"`func Run(code int)`",
+		"### New dependencies (1)",
+		"→ `os` (external)",
+		"### New dead code (1)",
+		"`helper`",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestRunDocgen(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package sample
+
+// Run starts the sample.
+func Run() {}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "sample.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	outDir := filepath.Join(tmpDir, "docs")
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	stalePage := filepath.Join(outDir, "gone.md")
+	if err := os.WriteFile(stalePage, []byte("<!-- Code generated by gts docgen. DO NOT EDIT. -->\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	handWritten := filepath.Join(outDir, "guide.md")
+	if err := os.WriteFile(handWritten, []byte("# Guide\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	os.Stdout = devNull
+	defer func() {
+		os.Stdout = originalStdout
+		_ = devNull.Close()
+	}()
+
+	if err := runDocgen([]string{tmpDir, "--no-cache", "--out", outDir, "--check"}); err == nil {
+		t.Fatal("expected --check to fail before generation")
+	}
+	if err := runDocgen([]string{tmpDir, "--no-cache", "--out", outDir}); err != nil {
+		t.Fatalf("runDocgen returned error: %v", err)
+	}
+	page, err := os.ReadFile(filepath.Join(outDir, "_root.md"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !strings.Contains(string(page), "Run starts the sample.") || !strings.Contains(string(page), "(../sample.go#L4)") {
+		t.Fatalf("unexpected page:\n%s", page)
+	}
+	if _, err := os.Stat(stalePage); !os.IsNotExist(err) {
+		t.Fatalf("expected stale generated page to be removed, stat err=%v", err)
+	}
+	if _, err := os.Stat(handWritten); err != nil {
+		t.Fatalf("expected hand-written page to be kept: %v", err)
+	}
+	if err := runDocgen([]string{tmpDir, "--no-cache", "--out", outDir, "--check"}); err != nil {
+		t.Fatalf("expected --check to pass after generation: %v", err)
+	}
+}
+
+func TestRunRoutes(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package main
+
+import "net/http"
+
+func main() {
+	http.HandleFunc("GET /users/{id}", getUser)
+	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+}
+
+func getUser(w http.ResponseWriter, r *http.Request) {}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runRoutes([]string{tmpDir, "--no-cache"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runRoutes returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	want := "routes: count=2 resolved=1\n" +
+		"main.go:7 ANY /healthz [net/http] -> func literal\n" +
+		"main.go:6 GET /users/{id} [net/http] -> getUser main.go:10\n"
+	if got := output.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunSQLTables(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package main
+
+const countUsers = "SELECT count(*) FROM users"
+
+func count() {
+	db.QueryRow(countUsers)
+}
+
+func rename(id int) {
+	db.Exec("UPDATE users SET name = 'x' WHERE id = $1", id)
+	db.Exec("DELETE FROM sessions")
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
 	}
-	if err := os.WriteFile(appPath, []byte(appSource), 0o644); err != nil {
-		t.Fatalf("WriteFile app.go failed: %v", err)
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
 	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
 
-	if err := runRefactor([]string{
-		"function_definition[name=/^OldName$/]",
-		"NewName",
-		tmpDir,
-		"--callsites",
-		"--cross-package",
-		"--write",
-	}); err != nil {
-		t.Fatalf("runRefactor cross-package write returned error: %v", err)
+	runErr := runSQL([]string{tmpDir, "--no-cache", "--tables"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runSQL returned error: %v", runErr)
 	}
 
-	afterApp, err := os.ReadFile(appPath)
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	want := "sql: queries=3 tables=2\n" +
+		"sessions DELETE queries=1 callers=rename\n" +
+		"users SELECT,UPDATE queries=2 callers=count,rename\n"
+	if got := output.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunConfigUsageDocs(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package main
+
+import "os"
+
+func main() {
+	os.Getenv("PORT")
+	os.Getenv("DATABASE_URL")
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	docPath := filepath.Join(tmpDir, ".env.example")
+	if err := os.WriteFile(docPath, []byte("PORT=8080\nLEGACY_TOKEN=\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
 	if err != nil {
-		t.Fatalf("ReadFile app.go failed: %v", err)
+		t.Fatalf("os.Pipe failed: %v", err)
 	}
-	if !strings.Contains(string(afterApp), "lib.NewName()") {
-		t.Fatalf("expected cross-package callsite rename, got:\n%s", string(afterApp))
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runConfigUsage([]string{tmpDir, "--no-cache", "--docs", docPath})
+	_ = writePipe.Close()
+	var exitErr exitCodeError
+	if !errors.As(runErr, &exitErr) || exitErr.code != 1 {
+		t.Fatalf("expected exit code 1, got %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	want := "config-usage: keys=2 undocumented=1 unused=1\n" +
+		"undocumented: DATABASE_URL (main.go:7)\n" +
+		"unused: LEGACY_TOKEN\n"
+	if got := output.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
 	}
 }
 
-func TestRunRefactorTreeSitterEngine(t *testing.T) {
+func TestRunConcurrency(t *testing.T) {
 	tmpDir := t.TempDir()
-	sourcePath := filepath.Join(tmpDir, "main.js")
-	source := `function OldName() {}
+	source := `package main
 
-function Use() {
-	OldName()
+import "sync"
+
+var mu sync.Mutex
+
+func main() {
+	done := make(chan struct{})
+	go func() { done <- struct{}{} }()
 }
 `
-	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
 		t.Fatalf("WriteFile failed: %v", err)
 	}
 
-	if err := runRefactor([]string{
-		"function_definition[name=/^OldName$/]",
-		"NewName",
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runConcurrency([]string{tmpDir, "--no-cache", "--kind", "go", "--kind", "mutex"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runConcurrency returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	want := "concurrency: packages=1 sites=2\n" +
+		". go=1 mutex=1\n" +
+		"  main.go:5 mutex var mu sync.Mutex\n" +
+		"  main.go:9 go func literal in main\n"
+	if got := output.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunAuditExits(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package lib
+
+import "os"
+
+func Stop() {
+	os.Exit(1)
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "lib.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runAuditExits([]string{tmpDir, "--no-cache"})
+	_ = writePipe.Close()
+	var exitErr exitCodeError
+	if !errors.As(runErr, &exitErr) || exitErr.code != 1 {
+		t.Fatalf("expected exit code 1, got %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	want := "lib.go:6 exit os.Exit in Stop\n" +
+		"audit exits: calls=1 violations=1\n"
+	if got := output.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := runAuditExits([]string{tmpDir, "--no-cache", "--allow", "lib.go", "--count"}); err != nil {
+		t.Fatalf("expected --allow to approve the call, got %v", err)
+	}
+}
+
+func TestRunQueryGroupBy(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "api"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	files := map[string]string{
+		"main.go":    "package main\n\nfunc A() {}\nfunc B() {}\n",
+		"api/api.go": "package api\n\nfunc C() {}\n\ntype T struct{}\n\nfunc (T) M() {}\n",
+	}
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, filepath.FromSlash(name)), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runQuery([]string{
+		"[(function_declaration) (method_declaration)] @decl",
 		tmpDir,
-		"--engine",
-		"treesitter",
-		"--callsites",
-		"--write",
-	}); err != nil {
-		t.Fatalf("runRefactor treesitter write returned error: %v", err)
+		"--no-cache",
+		"--group-by", "package,type",
+		"--agg", "count",
+	})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runQuery returned error: %v", runErr)
 	}
 
-	after, err := os.ReadFile(sourcePath)
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	want := `package="." type="function_declaration" count=2` + "\n" +
+		`package="api" type="function_declaration" count=1` + "\n" +
+		`package="api" type="method_declaration" count=1` + "\n"
+	if got := output.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := runQuery([]string{"(identifier) @id", tmpDir, "--group-by", "module"}); err == nil {
+		t.Fatal("expected unsupported --group-by key to fail")
+	}
+}
+
+func TestRunQueryBundledPattern(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"main.go":  "package main\n\n// TODO: split\nfunc main() {}\n",
+		"app.js":   "// FIXME: cache\nfunction f() {}\n",
+		"notes.md": "TODO: not source\n",
+	}
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
 	if err != nil {
-		t.Fatalf("ReadFile main.js failed: %v", err)
+		t.Fatalf("os.Pipe failed: %v", err)
 	}
-	if !strings.Contains(string(after), "function NewName()") || !strings.Contains(string(after), "NewName()") {
-		t.Fatalf("expected treesitter refactor rename, got:\n%s", string(after))
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runQuery([]string{"@todo-comments", tmpDir, "--no-cache", "--group-by", "file"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runQuery returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	want := `file="app.js" count=1` + "\n" + `file="main.go" count=1` + "\n"
+	if got := output.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	err = runQuery([]string{"@no-such-pattern", tmpDir, "--no-cache"})
+	if err == nil || !strings.Contains(err.Error(), "todo-comments") {
+		t.Fatalf("expected unknown pattern error listing patterns, got %v", err)
 	}
 }
 
-func assertExitCode(t *testing.T, err error, want int) {
-	t.Helper()
-	withCode, ok := err.(interface{ ExitCode() int })
-	if !ok {
-		t.Fatalf("expected error with exit code, got %T (%v)", err, err)
+func TestRunInspect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
 	}
-	if got := withCode.ExitCode(); got != want {
-		t.Fatalf("unexpected exit code: got=%d want=%d err=%v", got, want, err)
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runInspect([]string{path, "--line", "3"})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runInspect returned error: %v", runErr)
+	}
+
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	want := path + " (go)\n" +
+		"source_file [1:1-4:1]\n" +
+		"  function_declaration [3:1-3:15]\n" +
+		"    name: identifier [3:6-3:10] \"main\"\n" +
+		"    parameters: parameter_list [3:10-3:12]\n" +
+		"    body: block [3:13-3:15]\n"
+	if got := output.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := runInspect([]string{path, "--end-line", "3"}); err == nil {
+		t.Fatal("expected --end-line without --line to fail")
+	}
+}
+
+func TestRunFix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	source := "package main\n\nimport (\n\t\"os\"\n\t\"fmt\"\n)\n\nfunc main() { fmt.Println(\"ready\") }\n"
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe failed: %v", err)
+	}
+	os.Stdout = writePipe
+	defer func() {
+		os.Stdout = originalStdout
+	}()
+
+	runErr := runFix([]string{path})
+	_ = writePipe.Close()
+	if runErr != nil {
+		t.Fatalf("runFix returned error: %v", runErr)
+	}
+	var output bytes.Buffer
+	if _, err := output.ReadFrom(readPipe); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	if got := output.String(); !strings.Contains(got, "-\t\"os\"\n") || !strings.Contains(got, "fix: would change 1 of 1 files\n") {
+		t.Fatalf("unexpected dry-run output:\n%s", got)
+	}
+	if data, _ := os.ReadFile(path); string(data) != source {
+		t.Fatalf("dry run changed the file:\n%s", data)
+	}
+
+	os.Stdout, _ = os.Open(os.DevNull)
+	if err := runFix([]string{path, "--extract-constant", "8:28", "--name", "greeting", "--write"}); err != nil {
+		t.Fatalf("runFix --write returned error: %v", err)
+	}
+	want := "package main\n\nimport (\n\t\"os\"\n\t\"fmt\"\n)\n\nconst greeting = \"ready\"\n\nfunc main() { fmt.Println(greeting) }\n"
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Fatalf("unexpected fixed file:\n%s\nwant:\n%s", data, want)
+	}
+
+	if err := runFix([]string{path, "--name", "x"}); err == nil {
+		t.Fatal("expected --name without --extract-constant to fail")
 	}
 }
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/odvcencio/gts-suite/internal/outline"
 	"github.com/odvcencio/gts-suite/pkg/model"
 	"github.com/spf13/cobra"
 )
@@ -17,22 +19,33 @@ func newMapCmd() *cobra.Command {
 	var jsonOutput bool
 	var limit int
 	var countOnly bool
+	var fileGlobs []string
+	var depthValue string
 
 	cmd := &cobra.Command{
-		Use:     "map [path]",
+		Use:     "map [path|file]",
 		Aliases: []string{"gtsmap"},
 		Short:   "Print structural summaries for indexed files",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
 
+			depth, err := outline.ParseDepth(depthValue)
+			if err != nil {
+				return err
+			}
+
 			idx, err := loadOrBuild(cachePath, target, noCache)
 			if err != nil {
 				return err
 			}
+			if relPath, ok := mapFileTarget(idx, target); ok {
+				idx = idx.FilterByPaths(map[string]bool{relPath: true})
+			}
+			idx = idx.FilterByGlobs(fileGlobs)
 
 			if countOnly {
 				fmt.Println(len(idx.Files))
@@ -40,7 +53,21 @@ func newMapCmd() *cobra.Command {
 			}
 
 			if jsonOutput {
-				return streamIndexJSON(os.Stdout, idx, limit)
+				if !cmd.Flags().Changed("depth") {
+					return streamIndexJSON(os.Stdout, idx, limit)
+				}
+				files := make([]outline.File, 0, len(idx.Files))
+				for i, file := range idx.Files {
+					if limit > 0 && i >= limit {
+						break
+					}
+					files = append(files, outline.Build(file, depth))
+				}
+				return emitJSON(map[string]any{
+					"root":  idx.Root,
+					"depth": depth,
+					"files": files,
+				})
 			}
 
 			genMap := generatedFileMap(idx)
@@ -56,16 +83,11 @@ func newMapCmd() *cobra.Command {
 					genTag = fmt.Sprintf(" [gen:%s]", gi.Generator)
 				}
 				fmt.Printf("%s (%s)%s\n", file.Path, file.Language, genTag)
-				if len(file.Imports) > 0 {
-					fmt.Printf("  imports: %s\n", strings.Join(file.Imports, ", "))
-				}
-				for _, symbol := range file.Symbols {
-					if symbol.Signature != "" {
-						fmt.Printf("  %s %s [%d:%d]\n", symbol.Kind, symbol.Signature, symbol.StartLine, symbol.EndLine)
-						continue
-					}
-					fmt.Printf("  %s %s [%d:%d]\n", symbol.Kind, symbol.Name, symbol.StartLine, symbol.EndLine)
+				fileOutline := outline.Build(file, depth)
+				if len(fileOutline.Imports) > 0 {
+					fmt.Printf("  imports: %s\n", strings.Join(fileOutline.Imports, ", "))
 				}
+				printOutlineNodes(fileOutline.Symbols, 1)
 			}
 
 			if limit > 0 && limit < len(idx.Files) {
@@ -84,9 +106,41 @@ func newMapCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	cmd.Flags().IntVar(&limit, "limit", 0, "limit number of files in output (0 for all)")
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print only the count of files")
+	cmd.Flags().StringArrayVar(&fileGlobs, "file", nil, "only include files matching glob (repeatable, supports **)")
+	cmd.Flags().StringVar(&depthValue, "depth", "full", "outline depth: full, symbols (no imports), or types-only; with --json emits nested outlines")
 	return cmd
 }
 
+// mapFileTarget returns the index-relative path when target names a single
+// file, so a positional file argument narrows the map to that file.
+func mapFileTarget(idx *model.Index, target string) (string, bool) {
+	info, err := os.Stat(target)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", false
+	}
+	relPath, err := filepath.Rel(idx.Root, absTarget)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return "", false
+	}
+	return filepath.ToSlash(relPath), true
+}
+
+func printOutlineNodes(nodes []outline.Node, level int) {
+	indent := strings.Repeat("  ", level)
+	for _, node := range nodes {
+		label := node.Name
+		if node.Signature != "" {
+			label = node.Signature
+		}
+		fmt.Printf("%s%s %s [%d:%d]\n", indent, node.Kind, label, node.StartLine, node.EndLine)
+		printOutlineNodes(node.Children, level+1)
+	}
+}
+
 func runMap(args []string) error {
 	cmd := newMapCmd()
 	cmd.SilenceUsage = true
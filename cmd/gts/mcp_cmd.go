@@ -1,7 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -12,6 +15,15 @@ func newMCPCmd() *cobra.Command {
 	var root string
 	var cachePath string
 	var allowWrites bool
+	var timeout time.Duration
+	var toolTimeouts []string
+	var maxConcurrent int
+	var rateLimit float64
+	var rateBurst int
+	var allowRoots []string
+	var tokens int
+	var auditLogPath string
+	var maxOutputTokens int
 
 	cmd := &cobra.Command{
 		Use:     "mcp",
@@ -19,8 +31,34 @@ func newMCPCmd() *cobra.Command {
 		Short:   "Run MCP stdio server for AI-agent tool integration",
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			perTool, err := mcp.ParseToolTimeouts(toolTimeouts)
+			if err != nil {
+				return err
+			}
+			if len(allowRoots) == 0 {
+				allowRoots = []string{root}
+			}
+			var auditLog io.Writer
+			if auditLogPath != "" {
+				file, err := os.OpenFile(auditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+				if err != nil {
+					return fmt.Errorf("open audit log: %w", err)
+				}
+				defer file.Close()
+				auditLog = file
+			}
 			service := mcp.NewServiceWithOptions(root, cachePath, mcp.ServiceOptions{
-				AllowWrites: allowWrites,
+				AllowWrites:        allowWrites,
+				CallTimeout:        timeout,
+				ToolTimeouts:       perTool,
+				MaxConcurrentCalls: maxConcurrent,
+				RateLimit:          rateLimit,
+				RateBurst:          rateBurst,
+				AllowedRoots:       allowRoots,
+				Tokens:             tokens,
+				Config:             projectConfig,
+				AuditLog:           auditLog,
+				MaxOutputTokens:    maxOutputTokens,
 			})
 			return mcp.RunStdio(service, os.Stdin, os.Stdout, os.Stderr)
 		},
@@ -29,6 +67,15 @@ func newMCPCmd() *cobra.Command {
 	cmd.Flags().StringVar(&root, "root", ".", "default root path for tool calls")
 	cmd.Flags().StringVar(&cachePath, "cache", "", "default cache path for tool calls")
 	cmd.Flags().BoolVar(&allowWrites, "allow-writes", false, "allow MCP tools to mutate files (e.g. gts_refactor write mode)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 2*time.Minute, "maximum duration of a single tool call (0 disables)")
+	cmd.Flags().StringArrayVar(&toolTimeouts, "tool-timeout", nil, "per-tool timeout override as tool=duration (repeatable, e.g. gts_query=30s)")
+	cmd.Flags().IntVar(&maxConcurrent, "max-concurrent", 0, "maximum concurrently executing tool calls (0 for unlimited)")
+	cmd.Flags().Float64Var(&rateLimit, "rate-limit", 0, "maximum sustained tool calls per second (0 disables)")
+	cmd.Flags().StringArrayVar(&allowRoots, "allow-root", nil, "directory tool path arguments may resolve into (repeatable, default: --root)")
+	cmd.Flags().IntVar(&tokens, "tokens", 800, "token budget of gts_chunk and gts_context calls that do not pass one")
+	cmd.Flags().IntVar(&maxOutputTokens, "max-output-tokens", 25000, "truncate tool results to about this many tokens unless a call passes max_output_tokens or max_bytes (0 disables)")
+	cmd.Flags().StringVar(&auditLogPath, "audit-log", "", "append a JSON line per tool call (tool, sanitized arguments, duration, result size, error) to this file")
+	cmd.Flags().IntVar(&rateBurst, "rate-burst", 0, "tool calls allowed in a burst above --rate-limit (default: rate limit)")
 	return cmd
 }
 
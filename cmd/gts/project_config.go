@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/odvcencio/gts-suite/internal/config"
+)
+
+// projectConfig is the .gts/config.yaml found for the running command, or
+// nil when there is none.
+var projectConfig *config.Config
+
+// defaultTarget is the path commands analyze when given none: the root set
+// in .gts/config.yaml, or the working directory.
+func defaultTarget() string {
+	if projectConfig != nil && projectConfig.Root != "" {
+		return projectConfig.Root
+	}
+	return "."
+}
+
+// applyProjectConfig loads .gts/config.yaml from the working directory or
+// a parent and fills in the flags of cmd the user did not pass. Defaults
+// configured for the command under "commands" win over the general root,
+// tokens, and cache settings.
+func applyProjectConfig(cmd *cobra.Command) error {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return err
+	}
+	projectConfig = cfg
+	if cfg == nil {
+		return nil
+	}
+
+	name := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	for flag, values := range cfg.Flags(name) {
+		f := cmd.Flags().Lookup(flag)
+		if f == nil {
+			return fmt.Errorf("%s: commands.%s: unknown flag --%s", cfg.Path, name, flag)
+		}
+		if err := setFlagDefault(f, values...); err != nil {
+			return fmt.Errorf("%s: commands.%s: %w", cfg.Path, name, err)
+		}
+	}
+
+	// The cache setting is not a --cache default: that flag loads an index
+	// without checking it is current, so commands find the configured cache
+	// through loadOrBuild instead.
+	general := map[string]string{"root": cfg.Root}
+	if cfg.Tokens > 0 {
+		general["tokens"] = strconv.Itoa(cfg.Tokens)
+	}
+	for flag, value := range general {
+		f := cmd.Flags().Lookup(flag)
+		if f == nil || value == "" || cfg.Flags(name)[flag] != nil {
+			continue
+		}
+		if err := setFlagDefault(f, value); err != nil {
+			return fmt.Errorf("%s: %w", cfg.Path, err)
+		}
+	}
+	if (name == "index build" || name == "index") && cfg.Cache != "" && cfg.Flags(name)["out"] == nil {
+		if f := cmd.Flags().Lookup("out"); f != nil {
+			if err := setFlagDefault(f, cfg.Cache); err != nil {
+				return fmt.Errorf("%s: %w", cfg.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setFlagDefault sets f to values unless the user passed it. The flag is
+// left unchanged in cobra's eyes, so commands that treat an explicit flag
+// specially still see the default as a default.
+func setFlagDefault(f *pflag.Flag, values ...string) error {
+	if f.Changed {
+		return nil
+	}
+	for _, value := range values {
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("--%s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
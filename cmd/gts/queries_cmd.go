@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/queries"
+)
+
+func newQueriesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queries",
+		Short: "Manage saved grep queries from .gts/queries.yaml",
+	}
+	cmd.AddCommand(newQueriesListCmd())
+	return cmd
+}
+
+func newQueriesListCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "list [path]",
+		Short: "List saved queries available to gts grep @name",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := defaultTarget()
+			if len(args) == 1 {
+				target = args[0]
+			}
+			return runQueriesList(target, jsonOutput)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+func runQueriesList(target string, jsonOutput bool) error {
+	file, err := queries.Load(target)
+	if err != nil {
+		return err
+	}
+	if file == nil {
+		if jsonOutput {
+			return emitJSON(queries.File{Queries: []queries.Query{}})
+		}
+		fmt.Printf("queries: no %s found\n", queries.FileName)
+		return nil
+	}
+	if jsonOutput {
+		return emitJSON(file)
+	}
+
+	fmt.Printf("queries: %s (%d)\n", file.Path, len(file.Queries))
+	for _, q := range file.Queries {
+		mode := q.Mode
+		if mode == "" {
+			mode = "auto"
+		}
+		fmt.Printf("  @%s [%s] %s\n", q.Name, mode, q.Pattern)
+		if q.Description != "" {
+			fmt.Printf("      %s\n", q.Description)
+		}
+	}
+	return nil
+}
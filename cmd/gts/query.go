@@ -8,39 +8,130 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/odvcencio/gotreesitter"
-	"github.com/odvcencio/gotreesitter/grammars"
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/internal/membudget"
+	"github.com/odvcencio/gts-suite/internal/parsesession"
+	"github.com/odvcencio/gts-suite/internal/querylib"
 	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
 type queryOpts struct {
-	cachePath  string
-	noCache    bool
-	jsonOutput bool
-	countOnly  bool
-	limit      int
-	captures   []string
+	cachePath   string
+	noCache     bool
+	jsonOutput  bool
+	countOnly   bool
+	limit       int
+	captures    []string
+	groupBy     []string
+	agg         string
+	resultCache bool
+	maxMemory   string
 }
 
+// queryGroupKeys are the --group-by dimensions, each reading one field of a
+// capture match.
+var queryGroupKeys = map[string]func(queryCaptureMatch) string{
+	"capture":  func(m queryCaptureMatch) string { return m.Capture },
+	"file":     func(m queryCaptureMatch) string { return m.File },
+	"language": func(m queryCaptureMatch) string { return m.Language },
+	"package":  func(m queryCaptureMatch) string { return filepath.ToSlash(filepath.Dir(m.File)) },
+	"type":     func(m queryCaptureMatch) string { return m.NodeType },
+	"text":     func(m queryCaptureMatch) string { return m.Text },
+}
+
+type queryGroup = report.QueryGroup
+
 type queryResult struct {
-	results        []queryCaptureMatch
-	languageErrors []queryLanguageError
-	truncated      bool
+	Results        []queryCaptureMatch  `json:"results"`
+	LanguageErrors []queryLanguageError `json:"language_errors,omitempty"`
+	Truncated      bool                 `json:"truncated,omitempty"`
+
+	// spool holds the matches instead of Results when they outgrew the
+	// memory budget and were spilled to disk.
+	spool *membudget.Spool[queryCaptureMatch]
+}
+
+// count returns the number of matches.
+func (qr queryResult) count() int {
+	if qr.spool != nil {
+		return qr.spool.Len()
+	}
+	return len(qr.Results)
+}
+
+// each calls fn with every match in order, reading spilled matches from
+// disk one at a time.
+func (qr queryResult) each(fn func(queryCaptureMatch) error) error {
+	if qr.spool != nil {
+		return qr.spool.Each(fn)
+	}
+	for _, match := range qr.Results {
+		if err := fn(match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matches returns every match as a slice, reading spilled matches back into
+// memory.
+func (qr queryResult) matches() ([]queryCaptureMatch, error) {
+	if qr.spool != nil {
+		return qr.spool.Items()
+	}
+	return qr.Results, nil
 }
 
+// queryBatchFiles is how many files a budgeted query parses between checks
+// of the heap against its budget.
+const queryBatchFiles = 256
+
 func executeQuery(args []string, opts queryOpts) error {
 	queryText := strings.TrimSpace(args[0])
 	if queryText == "" {
 		return errors.New("query pattern cannot be empty")
 	}
+	queryFor := func(string) (string, bool) { return queryText, true }
+	if strings.HasPrefix(queryText, "@") {
+		pattern, ok := querylib.Lookup(queryText)
+		if !ok {
+			return fmt.Errorf("unknown query pattern %q (available: %s)", queryText, strings.Join(querylib.Names(), ", "))
+		}
+		queryFor = pattern.Query
+		if len(opts.captures) == 0 {
+			opts.captures = []string{querylib.MatchCapture}
+		}
+	}
+	for _, key := range opts.groupBy {
+		if _, ok := queryGroupKeys[key]; !ok {
+			return fmt.Errorf("unsupported --group-by %q (expected capture|file|language|package|type|text)", key)
+		}
+	}
+	if opts.agg != "" {
+		if opts.agg != "count" {
+			return fmt.Errorf("unsupported --agg %q (expected count)", opts.agg)
+		}
+		if len(opts.groupBy) == 0 {
+			return errors.New("--agg requires --group-by")
+		}
+	}
+
+	if opts.resultCache && opts.maxMemory != "" {
+		return errors.New("--result-cache cannot be combined with --max-memory")
+	}
+	maxMemory, restore, err := applyMaxMemory(opts.maxMemory)
+	if err != nil {
+		return err
+	}
+	defer restore()
 
-	target := "."
+	target := defaultTarget()
 	if len(args) == 2 {
 		target = args[1]
 	}
-	idx, err := loadOrBuild(opts.cachePath, target, opts.noCache)
+	idx, err := loadOrBuildWithin(opts.cachePath, target, opts.noCache, maxMemory)
 	if err != nil {
 		return err
 	}
@@ -50,55 +141,75 @@ func executeQuery(args []string, opts queryOpts) error {
 		captureFilter[strings.TrimSpace(name)] = true
 	}
 
-	qr := runQueryAcrossFiles(idx, queryText, captureFilter, opts.limit)
+	params := struct {
+		Query    string   `json:"query"`
+		Captures []string `json:"captures"`
+		Limit    int      `json:"limit"`
+	}{queryText, opts.captures, opts.limit}
+	qr, err := cachedResult(opts.resultCache, idx, "query", params, func() (queryResult, error) {
+		return runQueryAcrossFiles(idx, queryFor, captureFilter, opts.limit, maxMemory)
+	})
+	if err != nil {
+		return err
+	}
+	if qr.spool != nil {
+		defer qr.spool.Close()
+	}
 	return formatQueryOutput(qr, opts)
 }
 
-func runQueryAcrossFiles(idx *model.Index, queryText string, captureFilter map[string]bool, limit int) queryResult {
-	entriesByLanguage := map[string]grammars.LangEntry{}
-	for _, entry := range grammars.AllLanguages() {
-		if strings.TrimSpace(entry.Name) == "" || entry.Language == nil {
-			continue
-		}
-		entriesByLanguage[entry.Name] = entry
-	}
-
-	queryByLanguage := map[string]*gotreesitter.Query{}
+// runQueryAcrossFiles runs the query queryFor returns for each file's
+// language, skipping languages it has no query for. With a maxMemory budget
+// in bytes, matches beyond a quarter of it are spilled to disk, and every
+// queryBatchFiles files the session's parsers are dropped and freed memory
+// is returned to the OS if the heap has grown past half of it.
+func runQueryAcrossFiles(idx *model.Index, queryFor func(language string) (string, bool), captureFilter map[string]bool, limit int, maxMemory int64) (queryResult, error) {
+	session := parsesession.New()
 	queryErrorByLanguage := map[string]string{}
-	langByName := map[string]*gotreesitter.Language{}
-	parserByLanguage := map[string]*gotreesitter.Parser{}
+	skippedLanguages := map[string]bool{}
+
+	// Files are visited in path order and each file's matches are sorted
+	// before they are added, so the spool ends up sorted without holding
+	// every match at once.
+	order := make([]int, len(idx.Files))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return idx.Files[order[i]].Path < idx.Files[order[j]].Path })
 
+	spool := membudget.NewSpool[queryCaptureMatch](maxMemory / 4)
 	truncated := false
-	results := make([]queryCaptureMatch, 0, idx.SymbolCount())
+	var fileResults []queryCaptureMatch
 fileLoop:
-	for _, file := range idx.Files {
-		entry, ok := entriesByLanguage[file.Language]
-		if !ok {
+	for visited, i := range order {
+		file := idx.Files[i]
+		if maxMemory > 0 && visited > 0 && visited%queryBatchFiles == 0 && membudget.HeapInUse() > maxMemory/2 {
+			session.ReleaseParsers()
+			membudget.Release()
+		}
+
+		if !session.Supported(file.Language) {
 			continue
 		}
-		if _, failed := queryErrorByLanguage[file.Language]; failed {
+		if _, failed := queryErrorByLanguage[file.Language]; failed || skippedLanguages[file.Language] {
 			continue
 		}
 
-		lang, ok := langByName[file.Language]
+		lang, ok := session.Language(file.Language)
 		if !ok {
-			lang = entry.Language()
-			if lang == nil {
-				queryErrorByLanguage[file.Language] = "language loader returned nil"
-				continue
-			}
-			langByName[file.Language] = lang
+			queryErrorByLanguage[file.Language] = "language loader returned nil"
+			continue
 		}
 
-		queryForLanguage, ok := queryByLanguage[file.Language]
-		if !ok {
-			compiled, compileErr := gotreesitter.NewQuery(queryText, lang)
-			if compileErr != nil {
-				queryErrorByLanguage[file.Language] = compileErr.Error()
-				continue
-			}
-			queryByLanguage[file.Language] = compiled
-			queryForLanguage = compiled
+		queryText, hasQuery := queryFor(file.Language)
+		if !hasQuery {
+			skippedLanguages[file.Language] = true
+			continue
+		}
+		queryForLanguage, compileErr := session.Query(file.Language, queryText)
+		if compileErr != nil {
+			queryErrorByLanguage[file.Language] = compileErr.Error()
+			continue
 		}
 
 		sourcePath := filepath.Join(idx.Root, filepath.FromSlash(file.Path))
@@ -107,31 +218,14 @@ fileLoop:
 			continue
 		}
 
-		parser, ok := parserByLanguage[file.Language]
-		if !ok {
-			parser = gotreesitter.NewParser(lang)
-			parserByLanguage[file.Language] = parser
-		}
-
-		var tree *gotreesitter.Tree
-		var parseErr error
-		if entry.TokenSourceFactory != nil {
-			tokenSource := entry.TokenSourceFactory(source, lang)
-			if tokenSource != nil {
-				tree, parseErr = parser.ParseWithTokenSource(source, tokenSource)
-			}
-		}
-		if tree == nil && parseErr == nil {
-			tree, parseErr = parser.Parse(source)
-		}
+		tree, parseErr := session.Parse(file.Language, source)
 		if parseErr != nil {
 			continue
 		}
-		if tree == nil || tree.RootNode() == nil {
-			continue
-		}
 
 		matches := queryForLanguage.Execute(tree)
+		fileResults = fileResults[:0]
+	matchLoop:
 		for _, match := range matches {
 			for _, capture := range match.Captures {
 				if len(captureFilter) > 0 && !captureFilter[capture.Name] {
@@ -151,7 +245,7 @@ fileLoop:
 				if endColumn < startColumn {
 					endColumn = startColumn
 				}
-				results = append(results, queryCaptureMatch{
+				fileResults = append(fileResults, queryCaptureMatch{
 					File:        file.Path,
 					Language:    file.Language,
 					Pattern:     match.PatternIndex,
@@ -163,28 +257,33 @@ fileLoop:
 					StartColumn: startColumn,
 					EndColumn:   endColumn,
 				})
-				if limit > 0 && len(results) >= limit {
+				if limit > 0 && spool.Len()+len(fileResults) >= limit {
 					truncated = true
-					tree.Release()
-					break fileLoop
+					break matchLoop
 				}
 			}
 		}
 		tree.Release()
-	}
 
-	sort.Slice(results, func(i, j int) bool {
-		if results[i].File == results[j].File {
-			if results[i].StartLine == results[j].StartLine {
-				if results[i].StartColumn == results[j].StartColumn {
-					return results[i].Capture < results[j].Capture
+		sort.Slice(fileResults, func(i, j int) bool {
+			if fileResults[i].StartLine == fileResults[j].StartLine {
+				if fileResults[i].StartColumn == fileResults[j].StartColumn {
+					return fileResults[i].Capture < fileResults[j].Capture
 				}
-				return results[i].StartColumn < results[j].StartColumn
+				return fileResults[i].StartColumn < fileResults[j].StartColumn
+			}
+			return fileResults[i].StartLine < fileResults[j].StartLine
+		})
+		for _, match := range fileResults {
+			if err := spool.Add(match, int64(len(match.File)+len(match.Text)+len(match.NodeType)+128)); err != nil {
+				spool.Close()
+				return queryResult{}, err
 			}
-			return results[i].StartLine < results[j].StartLine
 		}
-		return results[i].File < results[j].File
-	})
+		if truncated {
+			break fileLoop
+		}
+	}
 
 	languageErrors := make([]queryLanguageError, 0, len(queryErrorByLanguage))
 	for language, value := range queryErrorByLanguage {
@@ -197,63 +296,133 @@ fileLoop:
 		return languageErrors[i].Language < languageErrors[j].Language
 	})
 
-	return queryResult{
-		results:        results,
-		languageErrors: languageErrors,
-		truncated:      truncated,
+	qr := queryResult{
+		LanguageErrors: languageErrors,
+		Truncated:      truncated,
+	}
+	if spool.Spilled() {
+		qr.spool = spool
+		return qr, nil
+	}
+	qr.Results, _ = spool.Items()
+	if qr.Results == nil {
+		qr.Results = []queryCaptureMatch{}
+	}
+	return qr, nil
+}
+
+// groupQueryResults counts matches per distinct combination of the keys,
+// largest groups first.
+func groupQueryResults(qr queryResult, keys []string) ([]queryGroup, error) {
+	byKey := map[string]*queryGroup{}
+	order := make([]string, 0)
+	err := qr.each(func(match queryCaptureMatch) error {
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			values[i] = queryGroupKeys[key](match)
+		}
+		id := strings.Join(values, "\x00")
+		group, ok := byKey[id]
+		if !ok {
+			group = &queryGroup{Key: make(map[string]string, len(keys))}
+			for i, key := range keys {
+				group.Key[key] = values[i]
+			}
+			byKey[id] = group
+			order = append(order, id)
+		}
+		group.Count++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(order)
+
+	groups := make([]queryGroup, 0, len(order))
+	for _, id := range order {
+		groups = append(groups, *byKey[id])
 	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Count > groups[j].Count })
+	return groups, nil
+}
+
+func formatQueryGroups(qr queryResult, opts queryOpts) error {
+	groups, err := groupQueryResults(qr, opts.groupBy)
+	if err != nil {
+		return err
+	}
+	if opts.countOnly {
+		if opts.jsonOutput {
+			return emitJSON(report.QueryGroupCountReport{Groups: len(groups)})
+		}
+		fmt.Println(len(groups))
+		return nil
+	}
+	if opts.jsonOutput {
+		return emitJSON(report.QueryGroupsReport{
+			GroupBy:        opts.groupBy,
+			Groups:         groups,
+			Count:          qr.count(),
+			Truncated:      qr.Truncated,
+			LanguageErrors: qr.LanguageErrors,
+		})
+	}
+
+	for _, item := range qr.LanguageErrors {
+		fmt.Fprintf(os.Stderr, "query: skip language=%s err=%s\n", item.Language, item.Error)
+	}
+	for _, group := range groups {
+		fields := make([]string, 0, len(opts.groupBy)+1)
+		for _, key := range opts.groupBy {
+			fields = append(fields, fmt.Sprintf("%s=%q", key, group.Key[key]))
+		}
+		fields = append(fields, fmt.Sprintf("count=%d", group.Count))
+		fmt.Println(strings.Join(fields, " "))
+	}
+	if qr.Truncated {
+		fmt.Fprintf(os.Stderr, "warning: results truncated at limit=%d, use --limit 0 for all\n", opts.limit)
+	}
+	return nil
 }
 
 func formatQueryOutput(qr queryResult, opts queryOpts) error {
+	if len(opts.groupBy) > 0 {
+		return formatQueryGroups(qr, opts)
+	}
 	if opts.jsonOutput {
 		if opts.countOnly {
-			return emitJSON(struct {
-				Count          int                  `json:"count"`
-				Truncated      bool                 `json:"truncated,omitempty"`
-				LanguageErrors []queryLanguageError `json:"language_errors,omitempty"`
-			}{
-				Count:          len(qr.results),
-				Truncated:      qr.truncated,
-				LanguageErrors: qr.languageErrors,
+			return emitJSON(report.QueryCountReport{
+				Count:          qr.count(),
+				Truncated:      qr.Truncated,
+				LanguageErrors: qr.LanguageErrors,
 			})
 		}
-		if qr.truncated {
-			return emitJSON(struct {
-				Matches        []queryCaptureMatch  `json:"matches,omitempty"`
-				Count          int                  `json:"count"`
-				Truncated      bool                 `json:"truncated"`
-				LanguageErrors []queryLanguageError `json:"language_errors,omitempty"`
-			}{
-				Matches:        qr.results,
-				Count:          len(qr.results),
-				Truncated:      true,
-				LanguageErrors: qr.languageErrors,
-			})
+		matches, err := qr.matches()
+		if err != nil {
+			return err
 		}
-		return emitJSON(struct {
-			Matches        []queryCaptureMatch  `json:"matches,omitempty"`
-			Count          int                  `json:"count"`
-			LanguageErrors []queryLanguageError `json:"language_errors,omitempty"`
-		}{
-			Matches:        qr.results,
-			Count:          len(qr.results),
-			LanguageErrors: qr.languageErrors,
+		return emitJSON(report.QueryReport{
+			Matches:        matches,
+			Count:          qr.count(),
+			Truncated:      qr.Truncated,
+			LanguageErrors: qr.LanguageErrors,
 		})
 	}
 
-	for _, item := range qr.languageErrors {
+	for _, item := range qr.LanguageErrors {
 		fmt.Fprintf(os.Stderr, "query: skip language=%s err=%s\n", item.Language, item.Error)
 	}
 
 	if opts.countOnly {
-		fmt.Println(len(qr.results))
-		if qr.truncated {
+		fmt.Println(qr.count())
+		if qr.Truncated {
 			fmt.Printf("truncated: limit=%d\n", opts.limit)
 		}
 		return nil
 	}
 
-	for _, match := range qr.results {
+	err := qr.each(func(match queryCaptureMatch) error {
 		fmt.Printf(
 			"%s:%d:%d capture=%s type=%s text=%q\n",
 			match.File,
@@ -263,8 +432,12 @@ func formatQueryOutput(qr queryResult, opts queryOpts) error {
 			match.NodeType,
 			match.Text,
 		)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	if qr.truncated {
+	if qr.Truncated {
 		fmt.Fprintf(os.Stderr, "warning: results truncated at limit=%d, use --limit 0 for all\n", opts.limit)
 	}
 	return nil
@@ -272,13 +445,49 @@ func formatQueryOutput(qr queryResult, opts queryOpts) error {
 
 func newQueryCmd() *cobra.Command {
 	var opts queryOpts
+	var listPatterns bool
 
 	cmd := &cobra.Command{
 		Use:     "query <pattern> [path]",
 		Aliases: []string{"gtsquery"},
 		Short:   "Run raw tree-sitter S-expression queries across files",
-		Args:    cobra.RangeArgs(1, 2),
+		Long: `Run raw tree-sitter S-expression queries across files.
+
+--group-by aggregates the captures instead of listing them, counting matches
+per capture, file, language, package, node type, or text. Keys combine, e.g.
+--group-by package,type counts each node type per package. Grouped queries
+are not limited unless --limit is given, and --count prints the number of
+groups.
+
+A pattern starting with @ names a bundled query with variants for several
+languages: @todo-comments, @empty-catches, @long-parameter-lists, and
+@nested-ternaries. Files in other languages are skipped, and only the @match
+capture is reported unless --capture is given. --list shows them all.
+
+--max-memory keeps a query over a large repository within a budget: it caps
+the Go heap, parses fewer files at once when building the index, frees
+parsers between batches of files, and spills matches to a temporary file once
+they outgrow a quarter of the budget. Text, --count, and --group-by output
+then stream the matches back from disk; --json reads them into memory.
+
+Examples:
+  gts search query @todo-comments
+  gts search query @long-parameter-lists internal --group-by file
+  gts search query '(call_expression function: (identifier) @fn)' --group-by text
+  gts search query '(_) @node' --group-by package,type --agg count --json`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if listPatterns {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.RangeArgs(1, 2)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if listPatterns {
+				return listQueryPatterns(opts.jsonOutput)
+			}
+			if len(opts.groupBy) > 0 && !cmd.Flags().Changed("limit") {
+				opts.limit = 0
+			}
 			return executeQuery(args, opts)
 		},
 	}
@@ -289,9 +498,25 @@ func newQueryCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&opts.countOnly, "count", false, "print the number of captures")
 	cmd.Flags().IntVar(&opts.limit, "limit", 1000, "maximum number of results (0 for unlimited)")
 	cmd.Flags().StringArrayVar(&opts.captures, "capture", nil, "capture name filter (repeatable)")
+	cmd.Flags().StringSliceVar(&opts.groupBy, "group-by", nil, "aggregate captures by capture, file, language, package, type, or text (comma-separated or repeatable)")
+	cmd.Flags().StringVar(&opts.agg, "agg", "", "aggregation for --group-by groups: count (default)")
+	cmd.Flags().BoolVar(&listPatterns, "list", false, "list the bundled @patterns")
+	cmd.Flags().BoolVar(&opts.resultCache, "result-cache", false, "reuse results stored under .gts/results for an identical index and query, storing them on a miss")
+	cmd.Flags().StringVar(&opts.maxMemory, "max-memory", "", "memory budget such as 3GB: caps the heap, bounds parse concurrency, and spills matches to disk")
 	return cmd
 }
 
+func listQueryPatterns(jsonOutput bool) error {
+	patterns := querylib.All()
+	if jsonOutput {
+		return emitJSON(report.QueryPatternsReport{Patterns: patterns})
+	}
+	for _, pattern := range patterns {
+		fmt.Printf("@%s  %s\n  languages: %s\n", pattern.Name, pattern.Description, strings.Join(pattern.Languages, ", "))
+	}
+	return nil
+}
+
 func runQuery(args []string) error {
 	cmd := newQueryCmd()
 	cmd.SilenceUsage = true
@@ -23,7 +23,7 @@ func newReachabilityCmd() *cobra.Command {
 		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			pkg := args[0]
-			target := "."
+			target := defaultTarget()
 			if len(args) == 2 {
 				target = args[1]
 			}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/config"
+)
+
+// readOnlyEnv names the environment variable that, set to anything but
+// "0" or "false", turns on read-only mode.
+const readOnlyEnv = "GTS_READ_ONLY"
+
+// writeFlags lists, by command path, the flags that make a command change
+// source files. Read-only mode refuses them however they were set: on the
+// command line, or as defaults from .gts/config.yaml.
+var writeFlags = map[string][]string{
+	"analyze lint":        {"fix"},
+	"fix":                 {"write"},
+	"graph dead":          {"write"},
+	"transform refactor":  {"write"},
+	"transform normalize": {"in-place"},
+	"mcp":                 {"allow-writes"},
+}
+
+// writeCommands lists, by command path, the commands that write files unless
+// the named flag is on. Read-only mode refuses them without that flag.
+var writeCommands = map[string]string{
+	"docgen": "check",
+}
+
+// readOnlyMode reports whether --read-only, GTS_READ_ONLY, or read_only in
+// .gts/config.yaml asks that no command change files.
+func readOnlyMode(cmd *cobra.Command) bool {
+	if on, err := cmd.Flags().GetBool("read-only"); err == nil && on {
+		return true
+	}
+	if v := strings.TrimSpace(os.Getenv(readOnlyEnv)); v != "" && v != "0" && !strings.EqualFold(v, "false") {
+		return true
+	}
+	return projectConfig != nil && projectConfig.ReadOnly
+}
+
+// checkReadOnly fails when cmd runs in read-only mode with one of its write
+// flags on, or is a write command without its read-only flag. lint --fix with
+// --dry-run only previews, so it is allowed.
+func checkReadOnly(cmd *cobra.Command) error {
+	if !readOnlyMode(cmd) {
+		return nil
+	}
+	name := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if flag, ok := writeCommands[name]; ok {
+		if f := cmd.Flags().Lookup(flag); f == nil || f.Value.String() != "true" {
+			return fmt.Errorf("%s writes files and is disabled in read-only mode without --%s (set by --read-only, %s, or read_only in %s)", name, flag, readOnlyEnv, config.FileName)
+		}
+	}
+	for _, flag := range writeFlags[name] {
+		f := cmd.Flags().Lookup(flag)
+		if f == nil || f.Value.String() != "true" {
+			continue
+		}
+		if dryRun := cmd.Flags().Lookup("dry-run"); dryRun != nil && dryRun.Value.String() == "true" {
+			continue
+		}
+		return fmt.Errorf("--%s is disabled in read-only mode (set by --read-only, %s, or read_only in %s)", flag, readOnlyEnv, config.FileName)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -18,24 +19,86 @@ func newRefactorCmd() *cobra.Command {
 	var crossPackage bool
 	var writeChanges bool
 	var jsonOutput bool
+	var mapPath string
+	var updateComments bool
+	var updateStrings bool
+	var orSelectors []string
 
 	cmd := &cobra.Command{
 		Use:     "refactor <selector> <new-name> [path]",
 		Aliases: []string{"gtsrefactor"},
 		Short:   "Apply structural declaration renames (dry-run by default)",
-		Args:    cobra.RangeArgs(2, 3),
+		Long: `Apply structural declaration renames (dry-run by default).
+
+Renames that would not compile are reported as skipped with the reason: a new
+name that collides with another declaration in the package, a method or
+field of the same receiver, an imported package, or a predeclared
+identifier, and with --callsites in Go, a callsite where a local variable
+of the new name would capture the reference.
+
+--update-comments and --update-strings also rename whole-word occurrences of
+the old name in comments, docstrings, and string literals of the affected
+packages. They are matched by name only, so they are listed separately as
+lower-confidence edits.
+
+--map renames many declarations in one pass from a CSV file of
+old,new[,selector] rows, e.g.
+
+  # old,new,selector
+  FetchUser,LoadUser
+  Client,APIClient,type_definition[file=/^api\//]
+  Get,Fetch,"method_definition[receiver=/Store/,file=/^db\//]"
+
+A row without a selector renames every declaration named old; quote
+selectors containing commas.
+
+Declarations that no single selector covers can be renamed in one run by
+giving alternative selectors, separated by commas or added with --or; a
+declaration matching any of them is renamed. All renames are planned against the original
+sources and reported together, and renames editing the same text differently
+are rejected.
+
+--write is transactional: every edit is applied in memory and staged beside
+its file before any file is replaced, and if replacing one fails, the files
+already replaced are restored, so either all files change or none do.
+
+Examples:
+  gts transform refactor 'function_definition[name=/^FetchUser$/]' LoadUser --callsites
+  gts transform refactor 'method_definition[name=/^Fetch$/,receiver=/Store/]' --or 'method_definition[name=/^Fetch$/,receiver=/Cache/]' Load
+  gts transform refactor --map renames.csv --callsites --cross-package
+  gts transform refactor --map renames.csv --callsites --write`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if mapPath != "" {
+				return cobra.MaximumNArgs(1)(cmd, args)
+			}
+			return cobra.RangeArgs(2, 3)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if crossPackage && !updateCallsites {
 				return errors.New("--cross-package requires --callsites")
 			}
+			opts := refactor.Options{
+				Write:                 writeChanges,
+				UpdateCallsites:       updateCallsites,
+				CrossPackageCallsites: crossPackage,
+				Engine:                engine,
+				UpdateComments:        updateComments,
+				UpdateStrings:         updateStrings,
+			}
+			if mapPath != "" {
+				if len(orSelectors) > 0 {
+					return errors.New("--or cannot be used with --map; give alternatives in the selector column")
+				}
+				return runRefactorMap(mapPath, args, cachePath, noCache, opts, jsonOutput)
+			}
 
-			selector, err := query.ParseSelector(args[0])
+			selector, err := query.ParseSelectors(append([]string{args[0]}, orSelectors...))
 			if err != nil {
 				return err
 			}
 			newName := args[1]
 
-			target := "."
+			target := defaultTarget()
 			if len(args) == 3 {
 				target = args[2]
 			}
@@ -45,12 +108,7 @@ func newRefactorCmd() *cobra.Command {
 				return err
 			}
 
-			report, err := refactor.RenameDeclarations(idx, selector, newName, refactor.Options{
-				Write:                 writeChanges,
-				UpdateCallsites:       updateCallsites,
-				CrossPackageCallsites: crossPackage,
-				Engine:                engine,
-			})
+			report, err := refactor.RenameDeclarations(idx, selector, newName, opts)
 			if err != nil {
 				return err
 			}
@@ -59,29 +117,9 @@ func newRefactorCmd() *cobra.Command {
 				return emitJSON(report)
 			}
 
-			for _, edit := range report.Edits {
-				if edit.Skipped {
-					fmt.Printf(
-						"%s:%d:%d %s %s %s -> %s skipped=%s\n",
-						edit.File,
-						edit.Line,
-						edit.Column,
-						edit.Category,
-						edit.Kind,
-						edit.OldName,
-						edit.NewName,
-						edit.SkipNote,
-					)
-					continue
-				}
-				status := "planned"
-				if edit.Applied {
-					status = "applied"
-				}
-				fmt.Printf("%s:%d:%d %s %s %s -> %s %s\n", edit.File, edit.Line, edit.Column, edit.Category, edit.Kind, edit.OldName, edit.NewName, status)
-			}
+			printRefactorEdits(report.Edits)
 			fmt.Printf(
-				"refactor: selector=%q new=%q engine=%q callsites=%t cross-package=%t matches=%d planned=%d (decl=%d callsites=%d) applied=%d files=%d\n",
+				"refactor: selector=%q new=%q engine=%q callsites=%t cross-package=%t matches=%d planned=%d (decl=%d callsites=%d text=%d) applied=%d files=%d\n",
 				report.Selector,
 				report.NewName,
 				report.Engine,
@@ -91,11 +129,14 @@ func newRefactorCmd() *cobra.Command {
 				report.PlannedEdits,
 				report.PlannedDeclEdits,
 				report.PlannedUseEdits,
+				report.PlannedTextEdits,
 				report.AppliedEdits,
 				report.ChangedFiles,
 			)
 			if !report.Write {
 				fmt.Println("refactor: dry-run (add --write to apply edits)")
+			} else if report.Transaction != "" {
+				fmt.Printf("refactor: transaction=%s\n", report.Transaction)
 			}
 
 			return nil
@@ -109,9 +150,106 @@ func newRefactorCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&crossPackage, "cross-package", false, "update resolved cross-package callsites within the module")
 	cmd.Flags().BoolVar(&writeChanges, "write", false, "apply edits in-place (default is dry-run)")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().BoolVar(&updateComments, "update-comments", false, "also rename exact-word occurrences in comments and docstrings (lower confidence)")
+	cmd.Flags().BoolVar(&updateStrings, "update-strings", false, "also rename exact-word occurrences in string literals (lower confidence)")
+	cmd.Flags().StringVar(&mapPath, "map", "", "CSV file of old,new[,selector] rows to rename in one pass")
+	cmd.Flags().StringArrayVar(&orSelectors, "or", nil, "additional selector whose matches are renamed too (repeatable)")
 	return cmd
 }
 
+func runRefactorMap(mapPath string, args []string, cachePath string, noCache bool, opts refactor.Options, jsonOutput bool) error {
+	file, err := os.Open(mapPath)
+	if err != nil {
+		return err
+	}
+	renames, err := refactor.ParseRenameMap(file)
+	_ = file.Close()
+	if err != nil {
+		return err
+	}
+
+	target := defaultTarget()
+	if len(args) == 1 {
+		target = args[0]
+	}
+	idx, err := loadOrBuild(cachePath, target, noCache)
+	if err != nil {
+		return err
+	}
+
+	report, err := refactor.RenameBatch(idx, renames, opts)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return emitJSON(report)
+	}
+
+	for _, rename := range report.Renames {
+		fmt.Printf("rename %s -> %s selector=%q engine=%q matches=%d planned=%d skipped=%d\n", rename.Old, rename.New, rename.Selector, rename.Engine, rename.MatchCount, rename.PlannedEdits, rename.SkippedEdits)
+	}
+	printRefactorEdits(report.Edits)
+	fmt.Printf(
+		"refactor: map=%q renames=%d callsites=%t cross-package=%t matches=%d planned=%d applied=%d files=%d\n",
+		mapPath,
+		len(report.Renames),
+		report.UpdateCallsites,
+		report.CrossPackageCallsites,
+		report.MatchCount,
+		report.PlannedEdits,
+		report.AppliedEdits,
+		report.ChangedFiles,
+	)
+	if !report.Write {
+		fmt.Println("refactor: dry-run (add --write to apply edits)")
+	} else if report.Transaction != "" {
+		fmt.Printf("refactor: transaction=%s\n", report.Transaction)
+	}
+	return nil
+}
+
+// printRefactorEdits lists code edits first and the lower-confidence comment
+// and string edits after them.
+func printRefactorEdits(edits []refactor.Edit) {
+	var textEdits []refactor.Edit
+	for _, edit := range edits {
+		if edit.LowConfidence {
+			textEdits = append(textEdits, edit)
+			continue
+		}
+		printRefactorEdit(edit)
+	}
+	if len(textEdits) == 0 {
+		return
+	}
+	fmt.Printf("refactor: %d lower-confidence text edits (matched by name in comments and strings)\n", len(textEdits))
+	for _, edit := range textEdits {
+		printRefactorEdit(edit)
+	}
+}
+
+func printRefactorEdit(edit refactor.Edit) {
+	if edit.Skipped {
+		fmt.Printf(
+			"%s:%d:%d %s %s %s -> %s skipped=%s\n",
+			edit.File,
+			edit.Line,
+			edit.Column,
+			edit.Category,
+			edit.Kind,
+			edit.OldName,
+			edit.NewName,
+			edit.SkipNote,
+		)
+		return
+	}
+	status := "planned"
+	if edit.Applied {
+		status = "applied"
+	}
+	fmt.Printf("%s:%d:%d %s %s %s -> %s %s\n", edit.File, edit.Line, edit.Column, edit.Category, edit.Kind, edit.OldName, edit.NewName, status)
+}
+
 func runRefactor(args []string) error {
 	cmd := newRefactorCmd()
 	cmd.SilenceUsage = true
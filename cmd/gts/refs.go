@@ -4,11 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/federation"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
 func newRefsCmd() *cobra.Command {
@@ -16,21 +21,38 @@ func newRefsCmd() *cobra.Command {
 	var noCache bool
 	var regexMode bool
 	var jsonOutput bool
+	var format string
 	var countOnly bool
 	var limit int
 	var lang string
+	var qualifier string
+	var extraIndexes []string
 
 	cmd := &cobra.Command{
 		Use:     "refs <name|regex> [path]",
 		Aliases: []string{"gtsrefs"},
 		Short:   "Find indexed references by symbol name",
-		Args:    cobra.RangeArgs(1, 2),
+		Long: `Find indexed references by symbol name.
+
+With --index (repeatable) or the global --federation directory, refs also
+searches other repositories' index caches and exported .gtsindex files, and
+labels each match with the repository it was found in.
+
+--format vimgrep prints each reference as file:line:col: text, the format
+Vim's quickfix list (vim -q, :cexpr) and VS Code problem matchers read,
+with paths relative to the working directory.`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 2 {
 				target = args[1]
 			}
 
+			outputFmt, err := outputFormat(format, jsonOutput, "text", "json", "vimgrep")
+			if err != nil {
+				return err
+			}
+
 			idx, err := loadOrBuild(cachePath, target, noCache)
 			if err != nil {
 				return err
@@ -50,41 +72,69 @@ func newRefsCmd() *cobra.Command {
 				matchReference = compiled.MatchString
 			}
 
-			genMap := generatedFileMap(idx)
+			var qualifierRE *regexp.Regexp
+			if strings.TrimSpace(qualifier) != "" {
+				compiled, compileErr := regexp.Compile(qualifier)
+				if compileErr != nil {
+					return fmt.Errorf("compile --qualifier: %w", compileErr)
+				}
+				qualifierRE = compiled
+			}
+
+			federationDir, _ := cmd.Flags().GetString("federation")
+			sources, err := refsSources(idx, extraIndexes, federationDir)
+			if err != nil {
+				return err
+			}
 
 			truncated := false
 			matches := make([]referenceMatch, 0, 256)
+			roots := map[string]string{}
 		outer:
-			for _, file := range idx.Files {
-				if lang != "" && !strings.EqualFold(file.Language, lang) {
-					continue
-				}
-				genTag := ""
-				if gi := genMap[file.Path]; gi != nil {
-					genTag = gi.Generator
-				}
-				for _, reference := range file.References {
-					if !matchReference(reference.Name) {
+			for _, source := range sources {
+				genMap := generatedFileMap(source.Index)
+				roots[source.Name] = source.Index.Root
+				for _, file := range source.Index.Files {
+					if lang != "" && !strings.EqualFold(file.Language, lang) {
 						continue
 					}
-					matches = append(matches, referenceMatch{
-						File:        file.Path,
-						Kind:        reference.Kind,
-						Name:        reference.Name,
-						StartLine:   reference.StartLine,
-						EndLine:     reference.EndLine,
-						StartColumn: reference.StartColumn,
-						EndColumn:   reference.EndColumn,
-						Generated:   genTag,
-					})
-					if limit > 0 && len(matches) >= limit {
-						truncated = true
-						break outer
+					genTag := ""
+					if gi := genMap[file.Path]; gi != nil {
+						genTag = gi.Generator
+					}
+					for _, reference := range file.References {
+						if !matchReference(reference.Name) {
+							continue
+						}
+						if qualifierRE != nil && !qualifierRE.MatchString(reference.Qualifier) {
+							continue
+						}
+						match := referenceMatch{
+							File:        file.Path,
+							Kind:        reference.Kind,
+							Name:        reference.Name,
+							StartLine:   reference.StartLine,
+							EndLine:     reference.EndLine,
+							StartColumn: reference.StartColumn,
+							EndColumn:   reference.EndColumn,
+							Qualifier:   reference.Qualifier,
+							Generated:   genTag,
+							Repo:        source.Name,
+						}
+						match.Cell, match.CellLine, _ = file.CellLine(reference.StartLine)
+						matches = append(matches, match)
+						if limit > 0 && len(matches) >= limit {
+							truncated = true
+							break outer
+						}
 					}
 				}
 			}
 
 			sort.Slice(matches, func(i, j int) bool {
+				if matches[i].Repo != matches[j].Repo {
+					return matches[i].Repo < matches[j].Repo
+				}
 				if matches[i].File == matches[j].File {
 					if matches[i].StartLine == matches[j].StartLine {
 						if matches[i].StartColumn == matches[j].StartColumn {
@@ -97,20 +147,11 @@ func newRefsCmd() *cobra.Command {
 				return matches[i].File < matches[j].File
 			})
 
-			if jsonOutput {
+			if outputFmt == "json" {
 				if countOnly {
-					return emitJSON(struct {
-						Count     int  `json:"count"`
-						Truncated bool `json:"truncated,omitempty"`
-					}{Count: len(matches), Truncated: truncated})
-				}
-				if truncated {
-					return emitJSON(struct {
-						Matches   []referenceMatch `json:"matches"`
-						Truncated bool             `json:"truncated"`
-					}{Matches: matches, Truncated: true})
+					return emitJSON(report.CountReport{Count: len(matches), Truncated: truncated})
 				}
-				return emitJSON(matches)
+				return emitJSON(report.RefsReport{Matches: matches, Count: len(matches), Truncated: truncated})
 			}
 
 			if countOnly {
@@ -121,11 +162,27 @@ func newRefsCmd() *cobra.Command {
 				return nil
 			}
 			for _, match := range matches {
+				if outputFmt == "vimgrep" {
+					name := match.Name
+					if match.Qualifier != "" {
+						name = match.Qualifier + "." + match.Name
+					}
+					fmt.Println(vimgrepLine(roots[match.Repo], match.File, match.StartLine, match.StartColumn, match.Kind+" "+name))
+					continue
+				}
 				genSuffix := ""
 				if match.Generated != "" {
 					genSuffix = fmt.Sprintf(" [gen:%s]", match.Generated)
 				}
-				fmt.Printf("%s:%d:%d %s %s%s\n", match.File, match.StartLine, match.StartColumn, match.Kind, match.Name, genSuffix)
+				name := match.Name
+				if match.Qualifier != "" {
+					name = match.Qualifier + "." + match.Name
+				}
+				if match.Repo != "" {
+					genSuffix += fmt.Sprintf(" [repo:%s]", match.Repo)
+				}
+				genSuffix += cellSuffix(match.Cell, match.CellLine)
+				fmt.Printf("%s:%d:%d %s %s%s\n", match.File, match.StartLine, match.StartColumn, match.Kind, name, genSuffix)
 			}
 			if truncated {
 				fmt.Fprintf(os.Stderr, "warning: results truncated at limit=%d, use --limit 0 for all\n", limit)
@@ -138,12 +195,53 @@ func newRefsCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
 	cmd.Flags().BoolVar(&regexMode, "regex", false, "treat the first argument as a regular expression")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, vimgrep (file:line:col: text)")
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of matches")
 	cmd.Flags().IntVar(&limit, "limit", 1000, "maximum number of results (0 for unlimited)")
 	cmd.Flags().StringVar(&lang, "lang", "", "filter by file language (e.g. go, python, typescript)")
+	cmd.Flags().StringVar(&qualifier, "qualifier", "", "regex matched against the reference qualifier (e.g. '^os$' for os.Exit)")
+	cmd.Flags().StringArrayVar(&extraIndexes, "index", nil, "also search this index cache or .gtsindex file, labeling matches by repo (repeatable)")
 	return cmd
 }
 
+// refsSources returns the indexes refs searches: idx alone, or, when extra
+// indexes or a federation directory are given, idx followed by each of them,
+// named for their repositories.
+func refsSources(idx *model.Index, extra []string, federationDir string) ([]federation.MergeInput, error) {
+	primary := federation.MergeInput{Index: idx}
+	if len(extra) == 0 && strings.TrimSpace(federationDir) == "" {
+		return []federation.MergeInput{primary}, nil
+	}
+	primary.Name = filepath.Base(idx.Root)
+	sources := []federation.MergeInput{primary}
+	for _, path := range extra {
+		input, err := loadMergeInput(path)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, input)
+	}
+	if strings.TrimSpace(federationDir) != "" {
+		fed, err := federation.Load(federationDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range fed.Indexes {
+			sources = append(sources, federation.MergeInput{Source: federationDir, Name: entry.RepoName, Index: entry.Index})
+		}
+	}
+	return sources, nil
+}
+
+// cellSuffix labels a match in a notebook with its cell and the line within
+// it, as " [cell:3:2]"; it is "" for other files.
+func cellSuffix(cell, cellLine int) string {
+	if cell == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [cell:%d:%d]", cell, cellLine)
+}
+
 func runRefs(args []string) error {
 	cmd := newRefsCmd()
 	cmd.SilenceUsage = true
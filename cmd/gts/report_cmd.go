@@ -17,59 +17,10 @@ import (
 	"github.com/odvcencio/gts-suite/pkg/complexity"
 	"github.com/odvcencio/gts-suite/pkg/hotspot"
 	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
-// Report is the top-level executive summary structure produced by `gts analyze report`.
-type Report struct {
-	// Codebase overview
-	Files        int            `json:"files"`
-	Languages    map[string]int `json:"languages"`
-	TotalSymbols int            `json:"total_symbols"`
-	GeneratedPct int            `json:"generated_pct"`
-
-	// Complexity
-	FunctionCount int `json:"function_count"`
-	CyclomaticMax int `json:"cyclomatic_max"`
-	CyclomaticP90 int `json:"cyclomatic_p90"`
-	CognitiveMax  int `json:"cognitive_max"`
-
-	// Architecture
-	BoundaryViolations int `json:"boundary_violations"`
-	ImportCycles       int `json:"import_cycles"`
-
-	// Security
-	Capabilities int `json:"capabilities"`
-
-	// Dead code
-	DeadFunctions int `json:"dead_functions"`
-
-	// Hotspots (top 5)
-	Hotspots []HotspotEntry `json:"hotspots,omitempty"`
-
-	// Team breakdown (only when --by-team is set)
-	Teams map[string]*TeamMetrics `json:"teams,omitempty"`
-}
-
-// HotspotEntry is a simplified hotspot record for the executive report.
-type HotspotEntry struct {
-	File       string  `json:"file"`
-	Name       string  `json:"name"`
-	Cyclomatic int     `json:"cyclomatic"`
-	Score      float64 `json:"score"`
-}
-
-// TeamMetrics holds per-team breakdown of report metrics.
-type TeamMetrics struct {
-	Files            int `json:"files"`
-	Functions        int `json:"functions"`
-	CyclomaticMax    int `json:"cyclomatic_max"`
-	CognitiveMax     int `json:"cognitive_max"`
-	DeadFunctions    int `json:"dead_functions"`
-	BoundaryViolations int `json:"boundary_violations"`
-	Capabilities     int `json:"capabilities"`
-}
-
 // ownerRule maps a path pattern to a team name, from CODEOWNERS or .gtsowners.
 type ownerRule struct {
 	Pattern string
@@ -100,7 +51,7 @@ Examples:
   gts analyze report --by-team`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -111,7 +62,7 @@ Examples:
 			}
 			analysisIdx := applyGeneratedFilter(cmd, idx)
 
-			rpt := Report{
+			rpt := report.ExecutiveReport{
 				Languages: make(map[string]int),
 			}
 
@@ -182,11 +133,12 @@ Examples:
 			if g, xrefErr := xref.Build(analysisIdx); xrefErr == nil {
 				xrefGraph = &g
 				deadCount := 0
+				topLevel := g.TopLevelCallNames()
 				for _, definition := range g.Definitions {
 					if !definition.Callable {
 						continue
 					}
-					if isEntrypointDefinition(definition) {
+					if isEntrypointDefinition(definition, topLevel) {
 						continue
 					}
 					if isTestSourceFile(definition.File) {
@@ -207,7 +159,7 @@ Examples:
 			})
 			if hotspotErr == nil {
 				for _, h := range hotspotReport.Functions {
-					rpt.Hotspots = append(rpt.Hotspots, HotspotEntry{
+					rpt.Hotspots = append(rpt.Hotspots, report.ExecutiveHotspot{
 						File:       h.File,
 						Name:       h.Name,
 						Cyclomatic: h.Cyclomatic,
@@ -225,7 +177,7 @@ Examples:
 			}
 
 			// --- Delta comparison ---
-			var delta *Report
+			var delta *report.ExecutiveReport
 			if compare != "" {
 				delta, err = buildCompareReport(compare, target, cmd)
 				if err != nil {
@@ -242,10 +194,7 @@ Examples:
 			switch outputFmt {
 			case "json":
 				if delta != nil {
-					return emitJSON(struct {
-						Current  Report  `json:"current"`
-						Baseline Report  `json:"baseline"`
-					}{
+					return emitJSON(report.ExecutiveComparison{
 						Current:  rpt,
 						Baseline: *delta,
 					})
@@ -268,7 +217,7 @@ Examples:
 	return cmd
 }
 
-func printMarkdownReport(rpt Report, delta *Report, target string) {
+func printMarkdownReport(rpt report.ExecutiveReport, delta *report.ExecutiveReport, target string) {
 	name := filepath.Base(target)
 	if name == "." {
 		if wd, err := os.Getwd(); err == nil {
@@ -372,10 +321,10 @@ func printDelta(label string, current, baseline int) {
 	fmt.Printf("  > delta %s: %s%d\n", label, sign, diff)
 }
 
-// buildCompareReport builds a Report for a baseline git ref by checking out
+// buildCompareReport builds an executive report for a baseline git ref by checking out
 // the ref into a temporary worktree, building the index, and running the same
 // analyses. Returns nil if the comparison cannot be performed.
-func buildCompareReport(ref, target string, cmd *cobra.Command) (*Report, error) {
+func buildCompareReport(ref, target string, cmd *cobra.Command) (*report.ExecutiveReport, error) {
 	// Create temp worktree directory.
 	tmpDir, err := os.MkdirTemp("", "gts-compare-*")
 	if err != nil {
@@ -401,7 +350,7 @@ func buildCompareReport(ref, target string, cmd *cobra.Command) (*Report, error)
 	}
 	baseAnalysisIdx := applyGeneratedFilter(cmd, baseIdx)
 
-	rpt := Report{
+	rpt := report.ExecutiveReport{
 		Languages: make(map[string]int),
 	}
 
@@ -469,11 +418,12 @@ func buildCompareReport(ref, target string, cmd *cobra.Command) (*Report, error)
 	// Dead code
 	if g, xrefErr := xref.Build(baseAnalysisIdx); xrefErr == nil {
 		deadCount := 0
+		topLevel := g.TopLevelCallNames()
 		for _, definition := range g.Definitions {
 			if !definition.Callable {
 				continue
 			}
-			if isEntrypointDefinition(definition) {
+			if isEntrypointDefinition(definition, topLevel) {
 				continue
 			}
 			if isTestSourceFile(definition.File) {
@@ -602,16 +552,16 @@ func buildTeamMetrics(
 	capaMatches []capa.Match,
 	boundaryCfg *boundaries.Config,
 	target string,
-) map[string]*TeamMetrics {
-	teams := make(map[string]*TeamMetrics)
+) map[string]*report.TeamMetrics {
+	teams := make(map[string]*report.TeamMetrics)
 
-	getTeam := func(filePath string) *TeamMetrics {
+	getTeam := func(filePath string) *report.TeamMetrics {
 		team := resolveOwner(ownerRules, filePath)
 		if team == "" {
 			team = "(unowned)"
 		}
 		if teams[team] == nil {
-			teams[team] = &TeamMetrics{}
+			teams[team] = &report.TeamMetrics{}
 		}
 		return teams[team]
 	}
@@ -638,11 +588,12 @@ func buildTeamMetrics(
 
 	// Dead functions
 	if xrefGraph != nil {
+		topLevel := xrefGraph.TopLevelCallNames()
 		for _, definition := range xrefGraph.Definitions {
 			if !definition.Callable {
 				continue
 			}
-			if isEntrypointDefinition(definition) {
+			if isEntrypointDefinition(definition, topLevel) {
 				continue
 			}
 			if isTestSourceFile(definition.File) {
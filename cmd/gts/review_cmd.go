@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"sort"
 	"strings"
@@ -9,10 +10,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/odvcencio/gts-suite/internal/deps"
+	"github.com/odvcencio/gts-suite/internal/lint"
 	"github.com/odvcencio/gts-suite/pkg/boundaries"
 	"github.com/odvcencio/gts-suite/pkg/capa"
 	"github.com/odvcencio/gts-suite/pkg/complexity"
 	"github.com/odvcencio/gts-suite/pkg/impact"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/structdiff"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
@@ -32,67 +36,170 @@ type reviewCapaMatch struct {
 }
 
 type reviewReport struct {
-	Base            string                  `json:"base"`
-	ChangedFiles    int                     `json:"changed_files"`
-	Files           []string                `json:"files"`
-	ComplexityDelta []reviewComplexityDelta  `json:"complexity_delta,omitempty"`
-	BoundaryIssues  []boundaries.Violation  `json:"boundary_issues,omitempty"`
-	NewCapabilities []reviewCapaMatch       `json:"new_capabilities,omitempty"`
-	BlastRadius     int                     `json:"blast_radius"`
+	Base               string                    `json:"base,omitempty"`
+	ChangedFiles       int                       `json:"changed_files"`
+	Files              []string                  `json:"files"`
+	APIChanges         []reviewAPIChange         `json:"api_changes,omitempty"`
+	SignatureChanges   []reviewSignatureChange   `json:"signature_changes,omitempty"`
+	NewDependencies    []reviewDependency        `json:"new_dependencies,omitempty"`
+	ThresholdCrossings []reviewThresholdCrossing `json:"threshold_crossings,omitempty"`
+	NewDeadCode        []reviewDeadCode          `json:"new_dead_code,omitempty"`
+	ComplexityDelta    []reviewComplexityDelta   `json:"complexity_delta,omitempty"`
+	BoundaryIssues     []boundaries.Violation    `json:"boundary_issues,omitempty"`
+	NewCapabilities    []reviewCapaMatch         `json:"new_capabilities,omitempty"`
+	BlastRadius        int                       `json:"blast_radius"`
 }
 
 func newReviewCmd() *cobra.Command {
 	var (
-		cachePath  string
-		noCache    bool
-		base       string
-		jsonOutput bool
+		cachePath          string
+		noCache            bool
+		base               string
+		jsonOutput         bool
+		format             string
+		beforeCache        string
+		afterCache         string
+		beforeRev          string
+		afterRev           string
+		thresholdOverrides []string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "review [path]",
-		Short: "Aggregate review report for changed files vs a base ref",
-		Args:  cobra.MaximumNArgs(1),
+		Use:     "review [path]",
+		Aliases: []string{"gtsreview"},
+		Short:   "Aggregate review report for changed files vs a base ref",
+		Long: `Aggregate review report for changed files vs a base ref.
+
+The before snapshot is a git ref (--base or --before-rev) or a cache file
+(--before-cache); the after snapshot is the working tree unless --after-rev
+or --after-cache is given. Git refs are checked out into a temporary
+directory, so the worktree is never touched.
+
+The report lists public APIs added or removed, signature changes, package
+dependencies that did not exist before (flagging .gtsboundaries violations),
+functions that grew past the built-in complexity thresholds, and callables
+that lost their last caller. With --base it also includes capabilities and
+the blast radius of the change. --format markdown renders the report as a
+PR comment.
+
+Examples:
+  gts review --base origin/main
+  gts review --before-rev main --after-rev HEAD --format markdown
+  gts review --before-cache base.json --after-cache head.json --json`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if base == "" {
-				return fmt.Errorf("--base is required")
+			if base != "" && (beforeCache != "" || beforeRev != "") {
+				return fmt.Errorf("--base cannot be combined with --before-cache or --before-rev")
+			}
+			if beforeCache != "" && beforeRev != "" {
+				return fmt.Errorf("--before-rev and --before-cache are mutually exclusive")
+			}
+			if afterCache != "" && afterRev != "" {
+				return fmt.Errorf("--after-rev and --after-cache are mutually exclusive")
+			}
+			if base == "" && beforeCache == "" && beforeRev == "" {
+				return fmt.Errorf("--base, --before-rev, or --before-cache is required")
+			}
+			outputFmt := format
+			if jsonOutput && outputFmt == "text" {
+				outputFmt = "json"
+			}
+			switch outputFmt {
+			case "text", "json", "markdown":
+			default:
+				return fmt.Errorf("unsupported --format %q (expected text|json|markdown)", format)
 			}
 
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
 
-			// Get changed files via git diff.
-			changed, err := reviewChangedFiles(target, base)
-			if err != nil {
-				return err
-			}
-			if len(changed) == 0 {
-				report := reviewReport{Base: base, ChangedFiles: 0, Files: []string{}}
-				if jsonOutput {
-					return emitJSON(report)
+			thresholds := make([]lint.ThresholdRule, len(lint.DefaultRules))
+			copy(thresholds, lint.DefaultRules)
+			for _, override := range thresholdOverrides {
+				if err := lint.ParseThresholdOverride(override, thresholds); err != nil {
+					return err
 				}
-				fmt.Printf("review: base=%s changed_files=0\n", base)
-				return nil
 			}
 
-			changedSet := make(map[string]bool, len(changed))
-			for _, f := range changed {
-				changedSet[f] = true
+			var cleanups []func()
+			defer func() {
+				for _, cleanup := range cleanups {
+					cleanup()
+				}
+			}()
+			loadSide := func(cache, rev string) (*model.Index, error) {
+				if rev == "" {
+					if cache == "" {
+						cache = cachePath
+					}
+					return loadOrBuild(cache, target, noCache)
+				}
+				if strings.HasPrefix(rev, "-") {
+					return nil, fmt.Errorf("invalid revision %q", rev)
+				}
+				prefix, err := gitCommandOutput(target, "rev-parse", "--show-prefix")
+				if err != nil {
+					return nil, err
+				}
+				idx, cleanup, err := indexGitTree(target, rev, prefix)
+				if err != nil {
+					return nil, err
+				}
+				cleanups = append(cleanups, cleanup)
+				return idx, nil
 			}
 
-			idx, err := loadOrBuild(cachePath, target, noCache)
+			beforeRef := beforeRev
+			if base != "" {
+				beforeRef = base
+			}
+			before, err := loadSide(beforeCache, beforeRef)
 			if err != nil {
-				return err
+				return fmt.Errorf("load before snapshot: %w", err)
+			}
+			idx, err := loadSide(afterCache, afterRev)
+			if err != nil {
+				return fmt.Errorf("load after snapshot: %w", err)
 			}
+			before = applyGeneratedFilter(cmd, before)
 			idx = applyGeneratedFilter(cmd, idx)
 
+			// Get changed files via git diff when comparing against the
+			// worktree, otherwise from the snapshots themselves.
+			var changed []string
+			if base != "" && afterCache == "" && afterRev == "" {
+				changed, err = reviewChangedFiles(target, base)
+				if err != nil {
+					return err
+				}
+			} else {
+				changed = structdiff.ChangedFiles(before, idx)
+			}
+
 			report := reviewReport{
-				Base:         base,
+				Base:         beforeRef,
 				ChangedFiles: len(changed),
 				Files:        changed,
 			}
+			if len(changed) == 0 {
+				report.Files = []string{}
+				return emitReviewReport(outputFmt, report)
+			}
+
+			changedSet := make(map[string]bool, len(changed))
+			for _, f := range changed {
+				changedSet[f] = true
+			}
+
+			cfg, cfgErr := boundaries.LoadConfig(target)
+			if cfgErr != nil {
+				cfg = nil
+			}
+			if err := summarizeReview(&report, before, idx, thresholds, cfg); err != nil {
+				return err
+			}
 
 			// 1. Complexity for changed files.
 			compReport, compErr := complexity.Analyze(idx, idx.Root, complexity.Options{})
@@ -116,8 +223,7 @@ func newReviewCmd() *cobra.Command {
 			}
 
 			// 2. Boundary violations.
-			cfg, cfgErr := boundaries.LoadConfig(target)
-			if cfgErr == nil && cfg != nil && len(cfg.Rules) > 0 {
+			if cfg != nil && len(cfg.Rules) > 0 {
 				depReport, depErr := deps.Build(idx, deps.Options{Mode: "package", IncludeEdges: true})
 				if depErr == nil {
 					importEdges := make([]boundaries.ImportEdge, 0, len(depReport.Edges))
@@ -158,51 +264,116 @@ func newReviewCmd() *cobra.Command {
 				})
 			}
 
-			// 4. Blast radius.
-			impactResult, impactErr := impact.Analyze(idx, impact.Options{
-				DiffRef:  base,
-				Root:     target,
-				MaxDepth: 5,
-			})
-			if impactErr == nil && impactResult != nil {
-				report.BlastRadius = impactResult.TotalAffected
-			}
-
-			if jsonOutput {
-				return emitJSON(report)
-			}
-
-			// Text output.
-			fmt.Printf("review: base=%s changed_files=%d blast_radius=%d\n", report.Base, report.ChangedFiles, report.BlastRadius)
-			if len(report.ComplexityDelta) > 0 {
-				fmt.Println("\ncomplexity in changed files:")
-				for _, cd := range report.ComplexityDelta {
-					fmt.Printf("  %s %s cyc=%d cog=%d lines=%d\n", cd.File, cd.Name, cd.Cyclomatic, cd.Cognitive, cd.Lines)
-				}
-			}
-			if len(report.BoundaryIssues) > 0 {
-				fmt.Printf("\nboundary violations: %d\n", len(report.BoundaryIssues))
-				for _, v := range report.BoundaryIssues {
-					fmt.Printf("  %s\n", v.Message)
-				}
-			}
-			if len(report.NewCapabilities) > 0 {
-				fmt.Printf("\ncapabilities detected: %d\n", len(report.NewCapabilities))
-				for _, c := range report.NewCapabilities {
-					fmt.Printf("  %s (%s, %s)\n", c.Name, c.Category, c.Confidence)
+			// 4. Blast radius, which needs the worktree diff against --base.
+			if base != "" && afterCache == "" && afterRev == "" {
+				impactResult, impactErr := impact.Analyze(idx, impact.Options{
+					DiffRef:  base,
+					Root:     target,
+					MaxDepth: 5,
+				})
+				if impactErr == nil && impactResult != nil {
+					report.BlastRadius = impactResult.TotalAffected
 				}
 			}
-			return nil
+
+			return emitReviewReport(outputFmt, report)
 		},
 	}
 
 	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
-	cmd.Flags().StringVar(&base, "base", "", "git ref to diff against (required)")
+	cmd.Flags().StringVar(&base, "base", "", "git ref to compare the working tree against")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, markdown")
+	cmd.Flags().StringVar(&beforeCache, "before-cache", "", "load the before snapshot from a cache file")
+	cmd.Flags().StringVar(&afterCache, "after-cache", "", "load the after snapshot from a cache file")
+	cmd.Flags().StringVar(&beforeRev, "before-rev", "", "check out the before snapshot from this git ref")
+	cmd.Flags().StringVar(&afterRev, "after-rev", "", "check out the after snapshot from this git ref")
+	cmd.Flags().StringArrayVar(&thresholdOverrides, "threshold", nil, "override a built-in threshold (e.g. cyclomatic=35) (repeatable)")
 	return cmd
 }
 
+func emitReviewReport(format string, report reviewReport) error {
+	switch format {
+	case "json":
+		return emitJSON(report)
+	case "markdown":
+		writeReviewMarkdown(os.Stdout, report)
+		return nil
+	}
+
+	if report.Base != "" {
+		fmt.Printf("review: base=%s changed_files=%d blast_radius=%d\n", report.Base, report.ChangedFiles, report.BlastRadius)
+	} else {
+		fmt.Printf("review: changed_files=%d\n", report.ChangedFiles)
+	}
+	if len(report.APIChanges) > 0 {
+		fmt.Println("\npublic api:")
+		for _, change := range report.APIChanges {
+			marker := "+"
+			if change.Change == "removed" {
+				marker = "-"
+			}
+			fmt.Printf("  %s %s:%d %s\n", marker, change.File, change.Line, symbolLabel(change.Name, change.Signature))
+		}
+	}
+	if len(report.SignatureChanges) > 0 {
+		fmt.Println("\nsignature changes:")
+		for _, change := range report.SignatureChanges {
+			fmt.Printf("  %s:%d %s -> %s\n", change.File, change.Line, change.Before, change.After)
+		}
+	}
+	if len(report.NewDependencies) > 0 {
+		fmt.Println("\nnew dependencies:")
+		for _, dep := range report.NewDependencies {
+			line := fmt.Sprintf("  %s -> %s", dep.From, dep.To)
+			if dep.Violation != "" {
+				line += " (violates " + dep.Violation + ")"
+			}
+			fmt.Println(line)
+		}
+	}
+	if len(report.ThresholdCrossings) > 0 {
+		fmt.Println("\ngrew past thresholds:")
+		for _, crossing := range report.ThresholdCrossings {
+			fmt.Printf("  %s:%d %s %s=%d threshold=%d\n", crossing.File, crossing.Line, crossing.Name, crossing.Metric, crossing.Value, crossing.Threshold)
+		}
+	}
+	if len(report.NewDeadCode) > 0 {
+		fmt.Println("\nnew dead code:")
+		for _, item := range report.NewDeadCode {
+			fmt.Printf("  %s:%d %s %s\n", item.File, item.Line, item.Kind, item.Name)
+		}
+	}
+	if len(report.ComplexityDelta) > 0 {
+		fmt.Println("\ncomplexity in changed files:")
+		for _, cd := range report.ComplexityDelta {
+			fmt.Printf("  %s %s cyc=%d cog=%d lines=%d\n", cd.File, cd.Name, cd.Cyclomatic, cd.Cognitive, cd.Lines)
+		}
+	}
+	if len(report.BoundaryIssues) > 0 {
+		fmt.Printf("\nboundary violations: %d\n", len(report.BoundaryIssues))
+		for _, v := range report.BoundaryIssues {
+			fmt.Printf("  %s\n", v.Message)
+		}
+	}
+	if len(report.NewCapabilities) > 0 {
+		fmt.Printf("\ncapabilities detected: %d\n", len(report.NewCapabilities))
+		for _, c := range report.NewCapabilities {
+			fmt.Printf("  %s (%s, %s)\n", c.Name, c.Category, c.Confidence)
+		}
+	}
+	return nil
+}
+
+func runReview(args []string) error {
+	cmd := newReviewCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
 func reviewChangedFiles(repoDir, base string) ([]string, error) {
 	gitCmd := exec.Command("git", "-C", repoDir, "diff", "--name-only", base)
 	out, err := gitCmd.Output()
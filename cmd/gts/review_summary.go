@@ -0,0 +1,369 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/deps"
+	"github.com/odvcencio/gts-suite/internal/lint"
+	"github.com/odvcencio/gts-suite/pkg/boundaries"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/structdiff"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+type reviewAPIChange struct {
+	Change    string `json:"change"` // added or removed
+	File      string `json:"file"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Signature string `json:"signature,omitempty"`
+	Line      int    `json:"line"`
+}
+
+type reviewSignatureChange struct {
+	File     string `json:"file"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Exported bool   `json:"exported"`
+	Before   string `json:"before"`
+	After    string `json:"after"`
+	Line     int    `json:"line"`
+}
+
+type reviewDependency struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Internal  bool   `json:"internal"`
+	Violation string `json:"violation,omitempty"`
+}
+
+type reviewThresholdCrossing struct {
+	File      string `json:"file"`
+	Name      string `json:"name"`
+	Line      int    `json:"line"`
+	Metric    string `json:"metric"`
+	Value     int    `json:"value"`
+	Threshold int    `json:"threshold"`
+}
+
+type reviewDeadCode struct {
+	File string `json:"file"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Line int    `json:"line"`
+}
+
+// summarizeReview fills the structural sections of report by comparing the
+// before and after snapshots: public API additions and removals, signature
+// changes, newly crossed package dependencies, functions that grew past a
+// threshold, and definitions that lost their last caller.
+func summarizeReview(report *reviewReport, before, after *model.Index, thresholds []lint.ThresholdRule, cfg *boundaries.Config) error {
+	diff := structdiff.Compare(before, after)
+	for _, symbol := range diff.AddedSymbols {
+		if symbol.Exported {
+			report.APIChanges = append(report.APIChanges, reviewAPIChange{
+				Change: "added", File: symbol.File, Kind: symbol.Kind, Name: symbol.Name, Signature: symbol.Signature, Line: symbol.StartLine,
+			})
+		}
+	}
+	for _, symbol := range diff.RemovedSymbols {
+		if symbol.Exported {
+			report.APIChanges = append(report.APIChanges, reviewAPIChange{
+				Change: "removed", File: symbol.File, Kind: symbol.Kind, Name: symbol.Name, Signature: symbol.Signature, Line: symbol.StartLine,
+			})
+		}
+	}
+	for _, modified := range diff.ModifiedSymbols {
+		if modified.Before.Signature == modified.After.Signature {
+			continue
+		}
+		report.SignatureChanges = append(report.SignatureChanges, reviewSignatureChange{
+			File:     modified.After.File,
+			Kind:     modified.After.Kind,
+			Name:     modified.After.Name,
+			Exported: modified.Before.Exported || modified.After.Exported,
+			Before:   modified.Before.Signature,
+			After:    modified.After.Signature,
+			Line:     modified.After.StartLine,
+		})
+	}
+
+	dependencies, err := newReviewDependencies(before, after, cfg)
+	if err != nil {
+		return err
+	}
+	report.NewDependencies = dependencies
+
+	report.ThresholdCrossings, err = reviewThresholdCrossings(before, after, thresholds)
+	if err != nil {
+		return err
+	}
+
+	report.NewDeadCode, err = reviewNewDeadCode(before, after)
+	return err
+}
+
+func newReviewDependencies(before, after *model.Index, cfg *boundaries.Config) ([]reviewDependency, error) {
+	edges := func(idx *model.Index) ([]deps.Edge, error) {
+		if len(idx.Files) == 0 {
+			return nil, nil
+		}
+		report, err := deps.Build(idx, deps.Options{Mode: "package", IncludeEdges: true})
+		if err != nil {
+			return nil, err
+		}
+		return report.Edges, nil
+	}
+	beforeEdges, err := edges(before)
+	if err != nil {
+		return nil, err
+	}
+	afterEdges, err := edges(after)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(beforeEdges))
+	for _, edge := range beforeEdges {
+		existing[edge.From+"\x00"+edge.To] = true
+	}
+	var added []reviewDependency
+	var internal []boundaries.ImportEdge
+	for _, edge := range afterEdges {
+		if existing[edge.From+"\x00"+edge.To] {
+			continue
+		}
+		added = append(added, reviewDependency{From: edge.From, To: edge.To, Internal: edge.Internal})
+		if edge.Internal {
+			internal = append(internal, boundaries.ImportEdge{From: edge.From, To: edge.To})
+		}
+	}
+
+	if cfg != nil && len(internal) > 0 {
+		violations := map[string]string{}
+		for _, v := range boundaries.Evaluate(cfg, internal) {
+			violations[v.From+"\x00"+v.To] = v.Rule
+		}
+		for i := range added {
+			added[i].Violation = violations[added[i].From+"\x00"+added[i].To]
+		}
+	}
+	sort.Slice(added, func(i, j int) bool {
+		if added[i].From != added[j].From {
+			return added[i].From < added[j].From
+		}
+		return added[i].To < added[j].To
+	})
+	return added, nil
+}
+
+// reviewThresholdCrossings reports threshold violations in after that the
+// same function did not already have in before.
+func reviewThresholdCrossings(before, after *model.Index, thresholds []lint.ThresholdRule) ([]reviewThresholdCrossing, error) {
+	if len(thresholds) == 0 {
+		return nil, nil
+	}
+	changed := map[string]bool{}
+	for _, path := range structdiff.ChangedFiles(before, after) {
+		changed[path] = true
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	existing := map[string]bool{}
+	if len(before.Files) > 0 {
+		violations, err := lint.EvaluateThresholdsInFiles(before, thresholds, changed)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range violations {
+			existing[v.RuleID+"|"+v.File+"|"+v.Name] = true
+		}
+	}
+	violations, err := lint.EvaluateThresholdsInFiles(after, thresholds, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make(map[string]lint.ThresholdRule, len(thresholds))
+	for _, rule := range thresholds {
+		rules[rule.ID] = rule
+	}
+	var crossings []reviewThresholdCrossing
+	for _, v := range violations {
+		if existing[v.RuleID+"|"+v.File+"|"+v.Name] {
+			continue
+		}
+		rule := rules[v.RuleID]
+		crossings = append(crossings, reviewThresholdCrossing{
+			File:      v.File,
+			Name:      v.Name,
+			Line:      v.StartLine,
+			Metric:    rule.Metric,
+			Value:     v.Value,
+			Threshold: rule.Threshold,
+		})
+	}
+	return crossings, nil
+}
+
+// reviewNewDeadCode lists unexported callables with no incoming calls in
+// after that were called, or did not exist, in before. Exported ones may have
+// callers outside the tree, and entrypoints and tests are left out as in
+// gts dead.
+func reviewNewDeadCode(before, after *model.Index) ([]reviewDeadCode, error) {
+	dead := func(idx *model.Index) ([]reviewDeadCode, error) {
+		if len(idx.Files) == 0 {
+			return nil, nil
+		}
+		graph, err := xref.Build(idx)
+		if err != nil {
+			return nil, err
+		}
+		var found []reviewDeadCode
+		topLevel := graph.TopLevelCallNames()
+		for _, definition := range graph.Definitions {
+			if !definition.Callable || definition.Exported || isEntrypointDefinition(definition, topLevel) || isTestSourceFile(definition.File) {
+				continue
+			}
+			if graph.IncomingCount(definition.ID) > 0 {
+				continue
+			}
+			found = append(found, reviewDeadCode{
+				File: definition.File,
+				Kind: definition.Kind,
+				Name: definition.Name,
+				Line: definition.StartLine,
+			})
+		}
+		return found, nil
+	}
+
+	beforeDead, err := dead(before)
+	if err != nil {
+		return nil, err
+	}
+	afterDead, err := dead(after)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(beforeDead))
+	for _, item := range beforeDead {
+		existing[item.File+"|"+item.Kind+"|"+item.Name] = true
+	}
+	var added []reviewDeadCode
+	for _, item := range afterDead {
+		if !existing[item.File+"|"+item.Kind+"|"+item.Name] {
+			added = append(added, item)
+		}
+	}
+	sort.Slice(added, func(i, j int) bool {
+		if added[i].File != added[j].File {
+			return added[i].File < added[j].File
+		}
+		return added[i].Line < added[j].Line
+	})
+	return added, nil
+}
+
+// writeReviewMarkdown renders report as a Markdown PR comment. Empty
+// sections are left out.
+func writeReviewMarkdown(w io.Writer, report reviewReport) {
+	fmt.Fprintln(w, "## Structural review")
+	fmt.Fprintln(w)
+	summary := fmt.Sprintf("**%d** changed files", report.ChangedFiles)
+	if report.Base != "" {
+		summary += fmt.Sprintf(" against `%s`", report.Base)
+	}
+	if report.BlastRadius > 0 {
+		summary += fmt.Sprintf(", blast radius **%d**", report.BlastRadius)
+	}
+	fmt.Fprintln(w, summary+".")
+
+	if len(report.APIChanges) > 0 {
+		fmt.Fprintf(w, "\n### Public API (%d)\n\n", len(report.APIChanges))
+		fmt.Fprintln(w, "| | Symbol | Location |")
+		fmt.Fprintln(w, "|---|---|---|")
+		for _, change := range report.APIChanges {
+			marker := "➕"
+			if change.Change == "removed" {
+				marker = "➖"
+			}
+			fmt.Fprintf(w, "| %s | %s | `%s:%d` |\n", marker, markdownCode(symbolLabel(change.Name, change.Signature)), change.File, change.Line)
+		}
+	}
+
+	if len(report.SignatureChanges) > 0 {
+		fmt.Fprintf(w, "\n### Signature changes (%d)\n\n", len(report.SignatureChanges))
+		fmt.Fprintln(w, "| Symbol | Before | After |")
+		fmt.Fprintln(w, "|---|---|---|")
+		for _, change := range report.SignatureChanges {
+			name := fmt.Sprintf("`%s` `%s:%d`", change.Name, change.File, change.Line)
+			if change.Exported {
+				name = "**" + name + "** (exported)"
+			}
+			fmt.Fprintf(w, "| %s | %s | %s |\n", name, markdownCode(change.Before), markdownCode(change.After))
+		}
+	}
+
+	if len(report.NewDependencies) > 0 {
+		fmt.Fprintf(w, "\n### New dependencies (%d)\n\n", len(report.NewDependencies))
+		for _, dep := range report.NewDependencies {
+			line := fmt.Sprintf("- `%s` → `%s`", dep.From, dep.To)
+			if !dep.Internal {
+				line += " (external)"
+			}
+			if dep.Violation != "" {
+				line += fmt.Sprintf(" ⛔ violates boundary rule `%s`", dep.Violation)
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+
+	if len(report.ThresholdCrossings) > 0 {
+		fmt.Fprintf(w, "\n### Grew past thresholds (%d)\n\n", len(report.ThresholdCrossings))
+		fmt.Fprintln(w, "| Function | Metric | Value | Threshold |")
+		fmt.Fprintln(w, "|---|---|---|---|")
+		for _, crossing := range report.ThresholdCrossings {
+			fmt.Fprintf(w, "| `%s` `%s:%d` | %s | %d | %d |\n", crossing.Name, crossing.File, crossing.Line, crossing.Metric, crossing.Value, crossing.Threshold)
+		}
+	}
+
+	if len(report.NewDeadCode) > 0 {
+		fmt.Fprintf(w, "\n### New dead code (%d)\n\n", len(report.NewDeadCode))
+		for _, item := range report.NewDeadCode {
+			fmt.Fprintf(w, "- `%s` (%s) `%s:%d`\n", item.Name, item.Kind, item.File, item.Line)
+		}
+	}
+
+	if len(report.BoundaryIssues) > 0 {
+		fmt.Fprintf(w, "\n### Boundary violations (%d)\n\n", len(report.BoundaryIssues))
+		for _, v := range report.BoundaryIssues {
+			fmt.Fprintf(w, "- %s\n", v.Message)
+		}
+	}
+
+	if len(report.NewCapabilities) > 0 {
+		fmt.Fprintf(w, "\n### Capabilities in changed files (%d)\n\n", len(report.NewCapabilities))
+		for _, c := range report.NewCapabilities {
+			fmt.Fprintf(w, "- %s (%s, %s confidence)\n", c.Name, c.Category, c.Confidence)
+		}
+	}
+
+	if len(report.APIChanges)+len(report.SignatureChanges)+len(report.NewDependencies)+len(report.ThresholdCrossings)+len(report.NewDeadCode)+len(report.BoundaryIssues) == 0 {
+		fmt.Fprintln(w, "\nNo API, dependency, complexity, or dead-code changes.")
+	}
+}
+
+// markdownCode wraps text in a code span that is safe inside a table cell.
+func markdownCode(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return "—"
+	}
+	return "`" + strings.ReplaceAll(text, "|", "\\|") + "`"
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/routes"
+	"github.com/odvcencio/gts-suite/pkg/report"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+func newRoutesCmd() *cobra.Command {
+	var cachePath string
+	var noCache bool
+	var match string
+	var framework string
+	var jsonOutput bool
+	var countOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "routes [path]",
+		Short: "List HTTP routes and the handlers serving them",
+		Long: `List HTTP routes and the handlers serving them.
+
+Finds route registrations for Go (net/http, gorilla/mux, gin, chi, echo),
+Express-style JavaScript and TypeScript routers, FastAPI and Flask, and Spring
+mapping annotations, and resolves each handler to its definition. Inline
+handlers are listed as "func literal".
+
+--match keeps the routes a request would hit; path parameters in a route
+match any segment, so "GET /users/42" finds "GET /users/{id}". To see what a
+route calls, pass the same spec to "gts graph calls --route".
+
+Examples:
+  gts routes
+  gts routes --match "GET /users/42"
+  gts graph calls --route "GET /users/42" --depth 3`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := defaultTarget()
+			if len(args) == 1 {
+				target = args[0]
+			}
+
+			idx, err := loadOrBuild(cachePath, target, noCache)
+			if err != nil {
+				return err
+			}
+			graph, err := xref.Build(idx)
+			if err != nil {
+				return err
+			}
+			found, err := routes.Extract(idx, &graph)
+			if err != nil {
+				return err
+			}
+
+			if strings.TrimSpace(match) != "" {
+				method, path, err := routes.ParseSpec(match)
+				if err != nil {
+					return err
+				}
+				found = routes.Find(found, method, path)
+			}
+			if framework != "" {
+				filtered := found[:0]
+				for _, route := range found {
+					if strings.EqualFold(route.Framework, framework) {
+						filtered = append(filtered, route)
+					}
+				}
+				found = filtered
+			}
+
+			resolved := 0
+			for _, route := range found {
+				if route.Definition != nil {
+					resolved++
+				}
+			}
+
+			if jsonOutput {
+				if countOnly {
+					return emitJSON(report.RoutesCountReport{Count: len(found), Resolved: resolved})
+				}
+				return emitJSON(report.RoutesReport{Count: len(found), Resolved: resolved, Routes: found})
+			}
+			if countOnly {
+				fmt.Println(len(found))
+				return nil
+			}
+
+			fmt.Printf("routes: count=%d resolved=%d\n", len(found), resolved)
+			for _, route := range found {
+				handler := route.Handler
+				if route.Definition != nil {
+					handler = fmt.Sprintf("%s %s:%d", route.Definition.Name, route.Definition.File, route.Definition.StartLine)
+				}
+				fmt.Printf("%s:%d %s %s [%s] -> %s\n", route.File, route.Line, route.Method, route.Path, route.Framework, handler)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
+	cmd.Flags().StringVar(&match, "match", "", "only routes hit by a request, e.g. \"GET /users/42\"")
+	cmd.Flags().StringVar(&framework, "framework", "", "only routes of a framework (net/http, gin, chi, express, fastapi, spring, ...)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of routes")
+	return cmd
+}
+
+func runRoutes(args []string) error {
+	cmd := newRoutesCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+// routeRoots returns the handler definitions of the routes matching specs.
+func routeRoots(routeList []routes.Route, specs []string) ([]xref.Definition, error) {
+	var roots []xref.Definition
+	seen := map[string]bool{}
+	for _, spec := range specs {
+		method, path, err := routes.ParseSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		matches := routes.Find(routeList, method, path)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no route matches %q", spec)
+		}
+		for _, route := range matches {
+			if route.Definition == nil || seen[route.Definition.ID] {
+				continue
+			}
+			seen[route.Definition.ID] = true
+			roots = append(roots, *route.Definition)
+		}
+	}
+	return roots, nil
+}
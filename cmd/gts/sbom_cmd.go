@@ -66,7 +66,7 @@ func newSBOMCmd() *cobra.Command {
 		Short: "Generate CycloneDX 1.5 SBOM from structural index",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/pkg/report"
+)
+
+func newSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema [document]",
+		Short: "Print the JSON Schema of a command's --json output",
+		Long: `Print the JSON Schema of a command's --json output.
+
+Every JSON object gts prints carries "schema_version", which changes only
+when a field is removed, renamed, or changes type. The documents below are
+also published as Go types in github.com/odvcencio/gts-suite/pkg/report.
+Commands with several output shapes have one document per shape, e.g.
+query, query-count, and query-groups. Without an argument, the documents
+are listed.
+
+Examples:
+  gts schema
+  gts schema query > query.schema.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				fmt.Printf("schema_version: %d\n", report.SchemaVersion)
+				for _, name := range report.Documents() {
+					fmt.Println(name)
+				}
+				return nil
+			}
+			schema, ok := report.Schema(args[0])
+			if !ok {
+				return fmt.Errorf("unknown document %q (available: %s)", args[0], strings.Join(report.Documents(), ", "))
+			}
+			// A schema is not itself a versioned gts document, so it is
+			// encoded directly rather than through emitJSON.
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(schema)
+		},
+	}
+	return cmd
+}
+
+func runSchema(args []string) error {
+	cmd := newSchemaCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
@@ -13,13 +13,14 @@ func newScopeCmd() *cobra.Command {
 	var noCache bool
 	var rootPath string
 	var line int
+	var column int
 	var jsonOutput bool
 	var countOnly bool
 
 	cmd := &cobra.Command{
 		Use:     "scope <file>",
 		Aliases: []string{"gtsscope"},
-		Short:   "Resolve symbols in scope for a file and line",
+		Short:   "Resolve symbols in scope for a file, line, and optional column",
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			filePath := args[0]
@@ -31,6 +32,7 @@ func newScopeCmd() *cobra.Command {
 			report, err := gtsscope.Build(idx, gtsscope.Options{
 				FilePath: filePath,
 				Line:     line,
+				Column:   column,
 			})
 			if err != nil {
 				return err
@@ -47,6 +49,9 @@ func newScopeCmd() *cobra.Command {
 
 			fmt.Printf("file: %s\n", report.File)
 			fmt.Printf("line: %d\n", report.Line)
+			if report.Column > 0 {
+				fmt.Printf("column: %d\n", report.Column)
+			}
 			fmt.Printf("package: %s\n", report.Package)
 			if report.Focus != nil {
 				fmt.Printf("focus: %s %s [%d:%d]\n", report.Focus.Kind, symbolLabel(report.Focus.Name, report.Focus.Signature), report.Focus.StartLine, report.Focus.EndLine)
@@ -68,6 +73,7 @@ func newScopeCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
 	cmd.Flags().StringVar(&rootPath, "root", ".", "parse root path when cache is not provided")
 	cmd.Flags().IntVar(&line, "line", 1, "cursor line (1-based)")
+	cmd.Flags().IntVar(&column, "column", 0, "cursor column (1-based); 0 resolves at end of line")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print only the count of symbols in scope")
 	return cmd
@@ -7,19 +7,9 @@ import (
 	"sort"
 
 	"github.com/spf13/cobra"
-)
-
-type importMatch struct {
-	File      string `json:"file"`
-	Import    string `json:"import"`
-	Generated string `json:"generated,omitempty"`
-}
 
-type importFileMatch struct {
-	File      string   `json:"file"`
-	Imports   []string `json:"imports"`
-	Generated string   `json:"generated,omitempty"`
-}
+	"github.com/odvcencio/gts-suite/pkg/report"
+)
 
 func newImportsCmd() *cobra.Command {
 	var cachePath string
@@ -35,7 +25,7 @@ func newImportsCmd() *cobra.Command {
 		Short: "Search and list import relationships across the codebase",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -100,14 +90,9 @@ func newImportsCmd() *cobra.Command {
 
 				if jsonOutput {
 					if countOnly {
-						return emitJSON(struct {
-							Count int `json:"count"`
-						}{Count: len(matches)})
+						return emitJSON(report.CountReport{Count: len(matches)})
 					}
-					return emitJSON(struct {
-						Files []importFileMatch `json:"files"`
-						Count int               `json:"count"`
-					}{Files: matches, Count: len(matches)})
+					return emitJSON(report.ImportersReport{Files: matches, Count: len(matches)})
 				}
 
 				if countOnly {
@@ -160,16 +145,9 @@ func newImportsCmd() *cobra.Command {
 
 			if jsonOutput {
 				if countOnly {
-					return emitJSON(struct {
-						UniqueImports int `json:"unique_imports"`
-						TotalImports  int `json:"total_imports"`
-					}{UniqueImports: len(uniqueImports), TotalImports: len(allImports)})
+					return emitJSON(report.ImportsCountReport{UniqueImports: len(uniqueImports), TotalImports: len(allImports)})
 				}
-				return emitJSON(struct {
-					Imports       []importMatch `json:"imports"`
-					UniqueImports int           `json:"unique_imports"`
-					TotalImports  int           `json:"total_imports"`
-				}{Imports: allImports, UniqueImports: len(uniqueImports), TotalImports: len(allImports)})
+				return emitJSON(report.ImportsReport{Imports: allImports, UniqueImports: len(uniqueImports), TotalImports: len(allImports)})
 			}
 
 			if countOnly {
@@ -9,19 +9,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/odvcencio/gts-suite/pkg/query"
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
-type symbolMatch struct {
-	File      string `json:"file"`
-	Kind      string `json:"kind"`
-	Name      string `json:"name"`
-	Signature string `json:"signature,omitempty"`
-	Receiver  string `json:"receiver,omitempty"`
-	StartLine int    `json:"start_line"`
-	EndLine   int    `json:"end_line"`
-	Generated string `json:"generated,omitempty"`
-}
-
 func newSymbolsCmd() *cobra.Command {
 	var cachePath string
 	var noCache bool
@@ -38,7 +28,7 @@ func newSymbolsCmd() *cobra.Command {
 		Short: "Search and filter symbols across the index",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -89,12 +79,12 @@ func newSymbolsCmd() *cobra.Command {
 				if gi := genMap[file.Path]; gi != nil {
 					genTag = gi.Generator
 				}
-				for _, sym := range file.Symbols {
-					if selector != nil {
-						if !selector.Match(sym) {
-							continue
-						}
-					} else {
+				symbols := file.Symbols
+				if selector != nil {
+					symbols = selector.MatchFile(symbols)
+				}
+				for _, sym := range symbols {
+					if selector == nil {
 						if kindFilter != "" && sym.Kind != kindFilter {
 							continue
 						}
@@ -131,22 +121,9 @@ func newSymbolsCmd() *cobra.Command {
 
 			if jsonOutput {
 				if countOnly {
-					return emitJSON(struct {
-						Count     int  `json:"count"`
-						Truncated bool `json:"truncated,omitempty"`
-					}{Count: len(matches), Truncated: truncated})
-				}
-				if truncated {
-					return emitJSON(struct {
-						Symbols   []symbolMatch `json:"symbols"`
-						Count     int           `json:"count"`
-						Truncated bool          `json:"truncated"`
-					}{Symbols: matches, Count: len(matches), Truncated: true})
+					return emitJSON(report.CountReport{Count: len(matches), Truncated: truncated})
 				}
-				return emitJSON(struct {
-					Symbols []symbolMatch `json:"symbols"`
-					Count   int           `json:"count"`
-				}{Symbols: matches, Count: len(matches)})
+				return emitJSON(report.SymbolsReport{Symbols: matches, Count: len(matches), Truncated: truncated})
 			}
 
 			if countOnly {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/similarity"
 )
 
@@ -75,12 +76,7 @@ func newSimilarityCmd() *cobra.Command {
 			}
 
 			if jsonOutput {
-				return emitJSON(struct {
-					Threshold float64           `json:"threshold"`
-					Method    string            `json:"method"`
-					Count     int               `json:"count"`
-					Pairs     []similarity.Pair `json:"pairs,omitempty"`
-				}{
+				return emitJSON(report.SimilarityReport{
 					Threshold: threshold,
 					Method:    mode,
 					Count:     len(pairs),
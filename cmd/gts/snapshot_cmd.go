@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/snapshot"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
+	"github.com/odvcencio/gts-suite/pkg/structdiff"
+)
+
+// defaultSnapshotKeep is how many unlabeled snapshots save keeps by default.
+const defaultSnapshotKeep = 10
+
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save, list, compare, and prune index snapshots in .gts/snapshots",
+		Long: `Save, list, compare, and prune index snapshots in .gts/snapshots.
+
+  save   store the current index, or one built from --rev, under a label
+  list   show stored snapshots, oldest first
+  diff   structurally compare two snapshots or git revisions
+  prune  delete old snapshots
+
+A snapshot is named by its label, its timestamp ID, or "latest". Labeled
+snapshots, such as releases, are kept until pruned with --labeled; unlabeled
+ones beyond the newest --keep are pruned on every save.
+
+Examples:
+  gts snapshot save --rev v1.2.0
+  gts snapshot save nightly-baseline
+  gts snapshot diff v1.2.0 HEAD
+  gts snapshot diff v1.2.0 --format markdown`,
+	}
+	cmd.AddCommand(newSnapshotSaveCmd(), newSnapshotListCmd(), newSnapshotDiffCmd(), newSnapshotPruneCmd())
+	return cmd
+}
+
+// snapshotStore returns the snapshot store of the project at root.
+func snapshotStore(root string) (*snapshot.Store, string, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, "", err
+	}
+	return snapshot.ForRoot(abs), abs, nil
+}
+
+func newSnapshotSaveCmd() *cobra.Command {
+	var root string
+	var rev string
+	var cachePath string
+	var noCache bool
+	var keep int
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "save [label]",
+		Short: "Store the current index, or one built from a git revision, as a snapshot",
+		Long: `Store the current index, or one built from a git revision, as a snapshot.
+
+The label defaults to --rev. Saving under a label that is already taken
+replaces that snapshot. After saving, unlabeled snapshots beyond the newest
+--keep are pruned.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, abs, err := snapshotStore(root)
+			if err != nil {
+				return err
+			}
+			label := rev
+			if len(args) == 1 {
+				label = args[0]
+			}
+
+			var idx *model.Index
+			if rev != "" {
+				idx, err = buildRevisionIndex(abs, rev)
+			} else {
+				idx, err = loadOrBuild(cachePath, abs, noCache)
+			}
+			if err != nil {
+				return err
+			}
+
+			snap, err := store.Save(label, idx, time.Now())
+			if err != nil {
+				return fmt.Errorf("save snapshot: %w", err)
+			}
+			pruned, err := store.Prune(snapshot.Policy{Keep: keep}, time.Now())
+			if err != nil {
+				return fmt.Errorf("prune snapshots: %w", err)
+			}
+			if jsonOutput {
+				return emitJSON(report.SnapshotSaveReport{Snapshot: snap, Pruned: pruned})
+			}
+			fmt.Printf("snapshot: saved %s files=%d symbols=%d\n", snapshotLabel(snap), snap.Files, snap.Symbols)
+			for _, old := range pruned {
+				fmt.Printf("pruned %s\n", snapshotLabel(old))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&root, "root", ".", "project root whose .gts/snapshots to use")
+	cmd.Flags().StringVar(&rev, "rev", "", "index this git revision instead of the working tree")
+	cmd.Flags().StringVar(&cachePath, "cache", "", "snapshot this index cache file")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
+	cmd.Flags().IntVar(&keep, "keep", defaultSnapshotKeep, "unlabeled snapshots to keep after saving (0 keeps all)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+func newSnapshotListCmd() *cobra.Command {
+	var root string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List stored snapshots, oldest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, _, err := snapshotStore(root)
+			if err != nil {
+				return err
+			}
+			snapshots, err := store.List()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				if snapshots == nil {
+					snapshots = []snapshot.Snapshot{}
+				}
+				return emitJSON(snapshots)
+			}
+			if len(snapshots) == 0 {
+				fmt.Printf("snapshots: none in %s\n", store.Dir())
+				return nil
+			}
+			fmt.Printf("snapshots: %s (%d)\n", store.Dir(), len(snapshots))
+			for _, snap := range snapshots {
+				line := fmt.Sprintf("  %s created=%s files=%d symbols=%d", snapshotLabel(snap), snap.Created.Local().Format(time.RFC3339), snap.Files, snap.Symbols)
+				if snap.Revision != "" {
+					line += " revision=" + shortRevision(snap.Revision)
+				}
+				fmt.Println(line)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&root, "root", ".", "project root whose .gts/snapshots to use")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+func newSnapshotDiffCmd() *cobra.Command {
+	var root string
+	var noCache bool
+	var jsonOutput bool
+	var format string
+	var countOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "diff <before> [after]",
+		Short: "Structurally compare two snapshots or git revisions",
+		Long: `Structurally compare two snapshots or git revisions.
+
+Each side is a snapshot (label, ID, or "latest") or, failing that, a git
+revision indexed from the object store. Without an after side, the before
+side is compared with the working tree.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputFmt, err := diffOutputFormat(format, jsonOutput)
+			if err != nil {
+				return err
+			}
+			store, abs, err := snapshotStore(root)
+			if err != nil {
+				return err
+			}
+
+			beforeIndex, err := resolveSnapshotRef(store, abs, args[0])
+			if err != nil {
+				return err
+			}
+			var afterIndex *model.Index
+			if len(args) == 2 {
+				afterIndex, err = resolveSnapshotRef(store, abs, args[1])
+			} else {
+				afterIndex, err = loadOrBuild("", abs, noCache)
+			}
+			if err != nil {
+				return err
+			}
+			return emitDiffReport(outputFmt, structdiff.Compare(beforeIndex, afterIndex), countOnly)
+		},
+	}
+	cmd.Flags().StringVar(&root, "root", ".", "project root whose .gts/snapshots to use")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index for the working tree")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, markdown")
+	cmd.Flags().BoolVar(&countOnly, "count", false, "print only the count of changed symbols")
+	return cmd
+}
+
+// resolveSnapshotRef loads the snapshot ref names, or indexes ref as a git
+// revision of the repository at root when no snapshot has that name.
+func resolveSnapshotRef(store *snapshot.Store, root, ref string) (*model.Index, error) {
+	snap, ok, err := store.Find(ref)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		idx, err := store.Load(snap)
+		if err != nil {
+			return nil, fmt.Errorf("load snapshot %s: %w", snap.Name(), err)
+		}
+		return idx, nil
+	}
+	idx, err := buildRevisionIndex(root, ref)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither a snapshot nor a git revision: %w", ref, err)
+	}
+	return idx, nil
+}
+
+func newSnapshotPruneCmd() *cobra.Command {
+	var root string
+	var policy snapshot.Policy
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete snapshots beyond the newest --keep or older than --max-age",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if policy.Keep <= 0 && policy.MaxAge <= 0 {
+				return fmt.Errorf("--keep or --max-age is required")
+			}
+			store, _, err := snapshotStore(root)
+			if err != nil {
+				return err
+			}
+			removed, err := store.Prune(policy, time.Now())
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				if removed == nil {
+					removed = []snapshot.Snapshot{}
+				}
+				return emitJSON(removed)
+			}
+			verb := "removed"
+			if policy.DryRun {
+				verb = "would remove"
+			}
+			for _, snap := range removed {
+				fmt.Printf("%s %s\n", verb, snapshotLabel(snap))
+			}
+			fmt.Printf("prune: %s %d snapshots\n", verb, len(removed))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&root, "root", ".", "project root whose .gts/snapshots to use")
+	cmd.Flags().IntVar(&policy.Keep, "keep", 0, "snapshots to keep, newest first")
+	cmd.Flags().DurationVar(&policy.MaxAge, "max-age", 0, "delete snapshots created longer ago than this")
+	cmd.Flags().BoolVar(&policy.Labeled, "labeled", false, "also prune labeled snapshots")
+	cmd.Flags().BoolVar(&policy.DryRun, "dry-run", false, "list what would be deleted without deleting it")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	return cmd
+}
+
+// snapshotLabel renders a snapshot as "label (id)", or its ID alone.
+func snapshotLabel(snap snapshot.Snapshot) string {
+	if snap.Label == "" {
+		return snap.ID
+	}
+	return fmt.Sprintf("%s (%s)", snap.Label, snap.ID)
+}
+
+func shortRevision(rev string) string {
+	if len(rev) > 12 {
+		return rev[:12]
+	}
+	return rev
+}
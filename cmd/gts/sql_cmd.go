@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/internal/sqlusage"
+	"github.com/odvcencio/gts-suite/pkg/report"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+func newSQLCmd() *cobra.Command {
+	var cachePath string
+	var noCache bool
+	var table string
+	var operation string
+	var tablesOnly bool
+	var jsonOutput bool
+	var countOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "sql [path]",
+		Short: "List SQL queries passed to database calls and the functions issuing them",
+		Long: `List SQL queries passed to database calls and the functions issuing them.
+
+Finds string literals, concatenations of literals, and same-file string
+constants passed to database calls (Exec, Query, Prepare, execute, @Query, and
+their driver variants) in Go, JavaScript, TypeScript, Python, and Java, and
+reads the statement and the tables it names. --tables summarizes the
+operations and calling functions per table.
+
+To see everything a schema change could affect, pass the table to
+"gts graph calls --table users --reverse".
+
+Examples:
+  gts sql
+  gts sql --tables
+  gts sql --table users --operation UPDATE
+  gts graph calls --table users --reverse --depth 3`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := defaultTarget()
+			if len(args) == 1 {
+				target = args[0]
+			}
+
+			idx, err := loadOrBuild(cachePath, target, noCache)
+			if err != nil {
+				return err
+			}
+			graph, err := xref.Build(idx)
+			if err != nil {
+				return err
+			}
+			usages, err := sqlusage.Extract(idx, &graph)
+			if err != nil {
+				return err
+			}
+			usages = filterSQLUsages(usages, table, operation)
+			result := sqlusage.Report{Usages: usages, Tables: sqlusage.Tables(usages)}
+
+			if jsonOutput {
+				if countOnly {
+					return emitJSON(report.SQLCountReport{Queries: len(result.Usages), Tables: len(result.Tables)})
+				}
+				if tablesOnly {
+					return emitJSON(report.SQLTablesReport{Tables: result.Tables})
+				}
+				return emitJSON(result)
+			}
+			if countOnly {
+				if tablesOnly {
+					fmt.Println(len(result.Tables))
+				} else {
+					fmt.Println(len(result.Usages))
+				}
+				return nil
+			}
+
+			fmt.Printf("sql: queries=%d tables=%d\n", len(result.Usages), len(result.Tables))
+			if tablesOnly {
+				for _, usage := range result.Tables {
+					fmt.Printf("%s %s queries=%d callers=%s\n", usage.Table, strings.Join(usage.Operations, ","), usage.Queries, strings.Join(usage.Callers, ","))
+				}
+				return nil
+			}
+			for _, usage := range result.Usages {
+				caller := "-"
+				if usage.Caller != nil {
+					caller = sqlusage.CallerLabel(*usage.Caller)
+				}
+				fmt.Printf("%s:%d %s %s %s %q\n", usage.File, usage.Line, caller, usage.Operation, strings.Join(usage.Tables, ","), truncateSQL(usage.Query, 100))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
+	cmd.Flags().StringVar(&table, "table", "", "only queries naming this table")
+	cmd.Flags().StringVar(&operation, "operation", "", "only statements of this kind (SELECT, INSERT, UPDATE, DELETE, ...)")
+	cmd.Flags().BoolVar(&tablesOnly, "tables", false, "summarize operations and callers per table")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of queries (or tables with --tables)")
+	return cmd
+}
+
+func runSQL(args []string) error {
+	cmd := newSQLCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+func filterSQLUsages(usages []sqlusage.Usage, table, operation string) []sqlusage.Usage {
+	table = strings.TrimSpace(table)
+	operation = strings.TrimSpace(operation)
+	if table == "" && operation == "" {
+		return usages
+	}
+	filtered := usages[:0]
+	for _, usage := range usages {
+		if table != "" && !usage.Touches(table) {
+			continue
+		}
+		if operation != "" && !strings.EqualFold(usage.Operation, operation) {
+			continue
+		}
+		filtered = append(filtered, usage)
+	}
+	return filtered
+}
+
+// tableRoots returns the functions issuing queries against any of tables.
+func tableRoots(usages []sqlusage.Usage, tables []string) ([]xref.Definition, error) {
+	var roots []xref.Definition
+	seen := map[string]bool{}
+	for _, table := range tables {
+		matched := false
+		for _, usage := range usages {
+			if !usage.Touches(table) {
+				continue
+			}
+			matched = true
+			if usage.Caller == nil || seen[usage.Caller.ID] {
+				continue
+			}
+			seen[usage.Caller.ID] = true
+			roots = append(roots, *usage.Caller)
+		}
+		if !matched {
+			return nil, fmt.Errorf("no query names table %q", table)
+		}
+	}
+	return roots, nil
+}
+
+func truncateSQL(query string, limit int) string {
+	if len(query) <= limit {
+		return query
+	}
+	return query[:limit] + "..."
+}
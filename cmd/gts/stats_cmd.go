@@ -2,10 +2,12 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/odvcencio/gts-suite/internal/stats"
+	"github.com/odvcencio/gts-suite/pkg/model"
 )
 
 func newStatsCmd() *cobra.Command {
@@ -14,18 +16,34 @@ func newStatsCmd() *cobra.Command {
 	var top int
 	var jsonOutput bool
 	var countOnly bool
+	var apiMode bool
+	var maxExported int
+	var pathPatterns []string
 
 	cmd := &cobra.Command{
 		Use:     "stats [path]",
 		Aliases: []string{"gtsstats"},
 		Short:   "Report structural codebase metrics from an index",
-		Args:    cobra.MaximumNArgs(1),
+		Long: `Report structural codebase metrics from an index.
+
+Besides symbol, language, and file counts, stats counts references by kind
+and resolves calls as the call graph does, reporting the share it resolved,
+how it resolved them, and why the rest were left unresolved (not_found,
+ambiguous_package, and so on): a measure of how far callgraph, dead, and
+impact results can be trusted for the codebase. local_resolved_ratio leaves
+out calls into external packages, which the index cannot resolve.
+
+With --api, report the public API surface instead: exported functions, types,
+and methods per package, flagging packages that export more than
+--max-exported symbols, and listing exported symbols referenced only from
+their own package as candidates for unexporting.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if top <= 0 {
 				return fmt.Errorf("top must be > 0")
 			}
 
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -38,9 +56,21 @@ func newStatsCmd() *cobra.Command {
 			if gen, _ := cmd.Flags().GetString("generator"); gen != "" {
 				idx = idx.FilterByGenerator(gen)
 			}
+			idx, err = idx.FilterByPathPatterns(pathPatterns)
+			if err != nil {
+				return err
+			}
+
+			if apiMode {
+				if countOnly {
+					return fmt.Errorf("--count cannot be combined with --api")
+				}
+				return printAPIStats(idx, maxExported, jsonOutput)
+			}
 
 			report, err := stats.Build(idx, stats.Options{
 				TopFiles: top,
+				Calls:    !countOnly,
 			})
 			if err != nil {
 				return err
@@ -83,10 +113,21 @@ func newStatsCmd() *cobra.Command {
 					report.Root,
 				)
 			}
+			printIndexProvenance(report)
 			if len(report.Languages) > 0 {
+				grammars := map[string]string{}
+				if report.Provenance != nil {
+					for _, source := range report.Provenance.Languages {
+						grammars[source.Language] = source.Grammar
+					}
+				}
 				fmt.Println("languages:")
 				for _, language := range report.Languages {
-					fmt.Printf("  %s files=%d symbols=%d\n", language.Language, language.Files, language.Symbols)
+					line := fmt.Sprintf("  %s files=%d symbols=%d", language.Language, language.Files, language.Symbols)
+					if grammar := grammars[language.Language]; grammar != "" {
+						line += " grammar=" + grammar
+					}
+					fmt.Println(line)
 				}
 			}
 			if len(report.Generators) > 0 {
@@ -101,6 +142,7 @@ func newStatsCmd() *cobra.Command {
 					fmt.Printf("  %s count=%d\n", kind.Kind, kind.Count)
 				}
 			}
+			printReferenceStats(report)
 			if len(report.TopFiles) > 0 {
 				fmt.Printf("top files (limit=%d):\n", top)
 				for _, file := range report.TopFiles {
@@ -123,9 +165,95 @@ func newStatsCmd() *cobra.Command {
 	cmd.Flags().IntVar(&top, "top", 10, "number of top files by symbol count")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	cmd.Flags().BoolVar(&countOnly, "count", false, "print only the total file count")
+	cmd.Flags().BoolVar(&apiMode, "api", false, "report exported symbols per package and candidates for unexporting")
+	cmd.Flags().IntVar(&maxExported, "max-exported", stats.DefaultMaxExported, "with --api, flag packages exporting more symbols than this")
+	addPathFlag(cmd, &pathPatterns)
 	return cmd
 }
 
+func printAPIStats(idx *model.Index, maxExported int, jsonOutput bool) error {
+	report, err := stats.BuildAPI(idx, stats.APIOptions{MaxExported: maxExported})
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return emitJSON(report)
+	}
+
+	exported := 0
+	for _, pkg := range report.Packages {
+		exported += pkg.Exported
+	}
+	fmt.Printf(
+		"api: packages=%d exported=%d large=%d max-exported=%d root=%s\n",
+		len(report.Packages),
+		exported,
+		report.LargeCount,
+		report.MaxExported,
+		report.Root,
+	)
+	for _, pkg := range report.Packages {
+		flag := ""
+		if pkg.Large {
+			flag = " large"
+		}
+		fmt.Printf(
+			"  %s exported=%d functions=%d types=%d methods=%d%s\n",
+			pkg.Package,
+			pkg.Exported,
+			pkg.Functions,
+			pkg.Types,
+			pkg.Methods,
+			flag,
+		)
+	}
+	if len(report.InternalOnly) > 0 {
+		fmt.Printf("internal-only (candidates for unexporting): %d\n", len(report.InternalOnly))
+		for _, symbol := range report.InternalOnly {
+			fmt.Printf("  %s:%d %s %s references=%d\n", symbol.File, symbol.StartLine, symbol.Kind, symbol.Name, symbol.References)
+		}
+	}
+	return nil
+}
+
+// printReferenceStats prints the references of report by kind and how its
+// calls resolved.
+func printReferenceStats(report stats.Report) {
+	fmt.Printf("references: total=%d\n", report.References.Total)
+	for _, kind := range report.References.Kinds {
+		fmt.Printf("  %s count=%d\n", kind.Kind, kind.Count)
+	}
+	calls := report.Calls
+	if calls == nil {
+		return
+	}
+	fmt.Printf(
+		"calls: total=%d resolved=%d unresolved=%d external=%d resolved_ratio=%.1f%% local_resolved_ratio=%.1f%%\n",
+		calls.Total,
+		calls.Resolved,
+		calls.Unresolved,
+		calls.External,
+		100*calls.ResolvedRatio,
+		100*calls.LocalResolvedRatio,
+	)
+	if len(calls.Resolutions) > 0 {
+		fmt.Println("call resolutions:")
+		for _, resolution := range calls.Resolutions {
+			fmt.Printf("  %s count=%d\n", resolution.Reason, resolution.Count)
+		}
+	}
+	if len(calls.Reasons) > 0 {
+		fmt.Println("unresolved reasons:")
+		for _, reason := range calls.Reasons {
+			line := fmt.Sprintf("  %s count=%d", reason.Reason, reason.Count)
+			if reason.Candidates > 0 {
+				line += fmt.Sprintf(" candidates=%d", reason.Candidates)
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
 func runStats(args []string) error {
 	cmd := newStatsCmd()
 	cmd.SilenceUsage = true
@@ -133,3 +261,34 @@ func runStats(args []string) error {
 	cmd.SetArgs(args)
 	return cmd.Execute()
 }
+
+// printIndexProvenance prints when, how, and by which gts the index behind
+// report was built.
+func printIndexProvenance(report stats.Report) {
+	p := report.Provenance
+	if p == nil {
+		fmt.Printf("built: %s (no provenance recorded; rebuild with gts index build to record it)\n", report.GeneratedAt.Local().Format(time.RFC3339))
+		return
+	}
+	line := "built: " + report.GeneratedAt.Local().Format(time.RFC3339)
+	if p.ToolVersion != "" {
+		line += " gts=" + p.ToolVersion
+	}
+	if p.GoVersion != "" {
+		line += " go=" + p.GoVersion
+	}
+	if p.Grammars != "" {
+		line += " grammars=" + p.Grammars
+	}
+	if p.GitCommit != "" {
+		line += " commit=" + shortRevision(p.GitCommit)
+	}
+	line += fmt.Sprintf(" duration=%s parsed=%d reused=%d", time.Duration(p.DurationMS)*time.Millisecond, p.ParsedFiles, p.ReusedFiles)
+	fmt.Println(line)
+
+	maxSize := "none"
+	if p.Options.MaxFileSize > 0 {
+		maxSize = formatByteSize(p.Options.MaxFileSize)
+	}
+	fmt.Printf("options: follow_symlinks=%t skip_generated=%t max_file_size=%s\n", p.Options.FollowSymlinks, p.Options.SkipGenerated, maxSize)
+}
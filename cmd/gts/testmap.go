@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/testmap"
 )
 
@@ -32,7 +33,7 @@ func newTestmapCmd() *cobra.Command {
 				return fmt.Errorf("unsupported --kind %q (expected function|method or empty for all)", kind)
 			}
 
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -42,7 +43,7 @@ func newTestmapCmd() *cobra.Command {
 				return err
 			}
 
-			report, err := testmap.Map(idx, testmap.Options{
+			result, err := testmap.Map(idx, testmap.Options{
 				UntestedOnly: untestedOnly,
 				Kind:         mode,
 			})
@@ -51,53 +52,39 @@ func newTestmapCmd() *cobra.Command {
 			}
 
 			truncated := false
-			if limit > 0 && len(report.Mappings) > limit {
-				report.Mappings = report.Mappings[:limit]
+			if limit > 0 && len(result.Mappings) > limit {
+				result.Mappings = result.Mappings[:limit]
 				truncated = true
 			}
 
 			if jsonOutput {
 				if countOnly {
-					return emitJSON(struct {
-						TestedCount   int     `json:"tested_count"`
-						UntestedCount int     `json:"untested_count"`
-						Coverage      float64 `json:"coverage"`
-						Truncated     bool    `json:"truncated,omitempty"`
-					}{
-						TestedCount:   report.TestedCount,
-						UntestedCount: report.UntestedCount,
-						Coverage:      report.Coverage,
+					return emitJSON(report.TestmapCountReport{
+						TestedCount:   result.TestedCount,
+						UntestedCount: result.UntestedCount,
+						Coverage:      result.Coverage,
 						Truncated:     truncated,
 					})
 				}
-				if truncated {
-					return emitJSON(struct {
-						TestedCount   int                    `json:"tested_count"`
-						UntestedCount int                    `json:"untested_count"`
-						Coverage      float64                `json:"coverage"`
-						Truncated     bool                   `json:"truncated"`
-						Mappings      []testmap.TestMapping  `json:"mappings,omitempty"`
-					}{
-						TestedCount:   report.TestedCount,
-						UntestedCount: report.UntestedCount,
-						Coverage:      report.Coverage,
-						Truncated:     true,
-						Mappings:      report.Mappings,
-					})
-				}
-				return emitJSON(report)
+				return emitJSON(report.TestmapReport{
+					Mappings:      result.Mappings,
+					TestedCount:   result.TestedCount,
+					UntestedCount: result.UntestedCount,
+					Coverage:      result.Coverage,
+					Truncated:     truncated,
+				})
 			}
 
 			if countOnly {
 				fmt.Printf("tested=%d untested=%d coverage=%.1f%%\n",
-					report.TestedCount, report.UntestedCount, report.Coverage*100)
+					result.TestedCount, result.UntestedCount, result.Coverage*100)
 				if truncated {
 					fmt.Printf("truncated: limit=%d\n", limit)
 				}
 				return nil
 			}
 
-			for _, m := range report.Mappings {
+			for _, m := range result.Mappings {
 				if len(m.Tests) == 0 {
 					fmt.Printf("%s:%d-%d %s [untested]\n",
 						m.File, m.StartLine, m.EndLine, m.Symbol)
@@ -112,7 +99,7 @@ func newTestmapCmd() *cobra.Command {
 				}
 			}
 			fmt.Printf("testmap: tested=%d untested=%d coverage=%.1f%%\n",
-				report.TestedCount, report.UntestedCount, report.Coverage*100)
+				result.TestedCount, result.UntestedCount, result.Coverage*100)
 			if truncated {
 				fmt.Fprintf(os.Stderr, "warning: results truncated at limit=%d, use --limit 0 for all\n", limit)
 			}
@@ -55,7 +55,7 @@ func newTrendsRecordCmd() *cobra.Command {
 		Short: "Record current quality metrics to .gts/trends.jsonl",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -150,7 +150,7 @@ func newTrendsShowCmd() *cobra.Command {
 		Short: "Display trend summary from .gts/trends.jsonl",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
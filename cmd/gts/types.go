@@ -1,59 +1,29 @@
 package main
 
-type grepMatch struct {
-	File      string `json:"file"`
-	Kind      string `json:"kind"`
-	Name      string `json:"name"`
-	Signature string `json:"signature,omitempty"`
-	StartLine int    `json:"start_line"`
-	EndLine   int    `json:"end_line"`
-}
-
-type referenceMatch struct {
-	File        string `json:"file"`
-	Kind        string `json:"kind"`
-	Name        string `json:"name"`
-	StartLine   int    `json:"start_line"`
-	EndLine     int    `json:"end_line"`
-	StartColumn int    `json:"start_column"`
-	EndColumn   int    `json:"end_column"`
-	Generated   string `json:"generated,omitempty"`
-}
+import "github.com/odvcencio/gts-suite/pkg/report"
 
-type queryCaptureMatch struct {
-	File        string `json:"file"`
-	Language    string `json:"language"`
-	Pattern     int    `json:"pattern"`
-	Capture     string `json:"capture"`
-	NodeType    string `json:"node_type"`
-	Text        string `json:"text"`
-	StartLine   int    `json:"start_line"`
-	EndLine     int    `json:"end_line"`
-	StartColumn int    `json:"start_column"`
-	EndColumn   int    `json:"end_column"`
-}
+// The JSON documents of the search and graph commands are published in
+// pkg/report; these aliases keep the command code terse.
+type (
+	grepMatch          = report.SymbolMatch
+	referenceMatch     = report.ReferenceMatch
+	queryCaptureMatch  = report.QueryMatch
+	queryLanguageError = report.QueryLanguageError
+	deadMatch          = report.DeadMatch
 
-type queryLanguageError struct {
-	Language string `json:"language"`
-	Error    string `json:"error"`
-}
-
-type structuralGrepMatch struct {
-	File      string            `json:"file"`
-	StartLine int               `json:"start_line"`
-	EndLine   int               `json:"end_line"`
-	Text      string            `json:"text"`
-	Captures  map[string]string `json:"captures,omitempty"`
-}
+	structuralGrepMatch     = report.StructuralMatch
+	structuralRewriteResult = report.StructuralRewrite
+	symbolMatch             = report.SymbolEntry
+	importMatch             = report.ImportMatch
+	importFileMatch         = report.ImportingFile
+	faninEntry              = report.FaninEntry
+	unusedFieldMatch        = report.UnusedField
+)
 
-type deadMatch struct {
-	File      string `json:"file"`
-	Package   string `json:"package"`
-	Kind      string `json:"kind"`
-	Name      string `json:"name"`
-	Signature string `json:"signature,omitempty"`
-	StartLine int    `json:"start_line"`
-	EndLine   int    `json:"end_line"`
-	Incoming  int    `json:"incoming"`
-	Outgoing  int    `json:"outgoing"`
+// deadAnalysis is the result of dead-code analysis before generated-file
+// filtering, limits, and deletion ranges are applied.
+type deadAnalysis struct {
+	Scanned      int         `json:"scanned"`
+	DynamicRoots int         `json:"dynamic_roots,omitempty"`
+	Matches      []deadMatch `json:"matches"`
 }
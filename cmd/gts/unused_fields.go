@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odvcencio/gts-suite/pkg/annotate"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+func newUnusedFieldsCmd() *cobra.Command {
+	var cachePath string
+	var noCache bool
+	var includeTests bool
+	var includeTagged bool
+	var unexportedOnly bool
+	var jsonOutput bool
+	var format string
+	var countOnly bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "unused-fields [path...]",
+		Short: "List struct fields and class members that are never read",
+		Long: `List struct fields and class members that are never read.
+
+A field is reported when no member access anywhere in the indexed paths reads
+a field of that name: it is only declared, or only assigned. Accesses are
+matched by name, so a field sharing its name with one that is read is not
+reported. Supported for Go, Rust, Python, JavaScript, and TypeScript, whose
+member accesses are always explicit.
+
+Go fields with struct tags are skipped unless --include-tagged is set, since
+they are usually read by reflection (encoding/json and friends).
+
+Examples:
+  gts graph unused-fields
+  gts graph unused-fields internal/ cmd/ --unexported-only
+  gts graph unused-fields --format github`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targets := args
+			if len(targets) == 0 {
+				targets = []string{defaultTarget()}
+			}
+
+			var idx *model.Index
+			for i, target := range targets {
+				built, err := loadOrBuild(cachePath, target, noCache)
+				if err != nil {
+					return err
+				}
+				if i == 0 {
+					idx = built
+				} else {
+					idx.Files = append(idx.Files, built.Files...)
+				}
+			}
+			// Generated code still counts as a reader; only its own fields are
+			// left out.
+			includeGenerated, _ := cmd.Flags().GetBool("include-generated")
+			genMap := generatedFileMap(idx)
+
+			matches := make([]unusedFieldMatch, 0, 32)
+			scanned := 0
+			for _, usage := range xref.FieldUsages(idx) {
+				if !includeTests && isTestSourceFile(usage.File) {
+					continue
+				}
+				if !includeGenerated && genMap[usage.File] != nil {
+					continue
+				}
+				if unexportedOnly && usage.Exported {
+					continue
+				}
+				if !includeTagged && strings.HasSuffix(usage.File, ".go") && strings.Contains(usage.Signature, "`") {
+					continue
+				}
+				scanned++
+				if usage.Reads > 0 {
+					continue
+				}
+				matches = append(matches, unusedFieldMatch{
+					File:      usage.File,
+					Package:   usage.Package,
+					Container: usage.Container,
+					Name:      usage.Name,
+					Signature: usage.Signature,
+					StartLine: usage.StartLine,
+					EndLine:   usage.EndLine,
+					Exported:  usage.Exported,
+					Writes:    usage.Writes,
+				})
+			}
+
+			truncated := false
+			if limit > 0 && len(matches) > limit {
+				matches = matches[:limit]
+				truncated = true
+			}
+
+			outputFmt := format
+			if jsonOutput && outputFmt == "text" {
+				outputFmt = "json"
+			}
+			switch outputFmt {
+			case "text":
+			case "json":
+				if countOnly {
+					return emitJSON(report.UnusedFieldsCountReport{Count: len(matches), Scanned: scanned, Truncated: truncated})
+				}
+				return emitJSON(report.UnusedFieldsReport{Scanned: scanned, Count: len(matches), Truncated: truncated, Matches: matches})
+			case "github", "gitlab":
+				annotations := make([]annotate.Annotation, 0, len(matches))
+				for _, match := range matches {
+					annotations = append(annotations, annotate.Annotation{
+						RuleID:    "unused-field",
+						Severity:  "warn",
+						Message:   fmt.Sprintf("field %s is never read", unusedFieldName(match)),
+						File:      match.File,
+						StartLine: match.StartLine,
+						EndLine:   match.EndLine,
+					})
+				}
+				return writeAnnotations(outputFmt, annotations)
+			default:
+				return fmt.Errorf("unsupported --format %q (expected text|json|github|gitlab)", format)
+			}
+
+			if countOnly {
+				fmt.Println(len(matches))
+				return nil
+			}
+			for _, match := range matches {
+				fmt.Printf("%s:%d %s writes=%d\n", match.File, match.StartLine, unusedFieldName(match), match.Writes)
+			}
+			fmt.Printf("unused-fields: scanned=%d matches=%d\n", scanned, len(matches))
+			if truncated {
+				fmt.Fprintf(os.Stderr, "warning: results truncated at limit=%d, use --limit 0 for all\n", limit)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cachePath, "cache", "", "load index from cache instead of parsing")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "skip auto-discovery of cached index")
+	cmd.Flags().BoolVar(&includeTests, "include-tests", false, "include fields declared in test files")
+	cmd.Flags().BoolVar(&includeTagged, "include-tagged", false, "include Go fields with struct tags")
+	cmd.Flags().BoolVar(&unexportedOnly, "unexported-only", false, "skip exported fields, which may be read outside the indexed tree")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, github, gitlab")
+	cmd.Flags().BoolVar(&countOnly, "count", false, "print the number of unused fields")
+	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of results (0 for unlimited)")
+	return cmd
+}
+
+func runUnusedFields(args []string) error {
+	cmd := newUnusedFieldsCmd()
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+func unusedFieldName(match unusedFieldMatch) string {
+	if match.Container == "" {
+		return match.Name
+	}
+	return match.Container + "." + match.Name
+}
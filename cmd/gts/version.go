@@ -1,3 +1,10 @@
 package main
 
+import "github.com/odvcencio/gts-suite/pkg/index"
+
 const version = "0.14.0"
+
+func init() {
+	// Indexes record the release that built them in their provenance.
+	index.ToolVersion = version
+}
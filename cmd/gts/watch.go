@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
 
 	"github.com/odvcencio/gts-suite/pkg/ignore"
 	"github.com/odvcencio/gts-suite/pkg/index"
@@ -17,11 +18,69 @@ import (
 	"github.com/odvcencio/gts-suite/pkg/structdiff"
 )
 
-func watchWithFSNotify(ctx context.Context, target string, debounce time.Duration, ignorePaths map[string]bool, ignoreMatcher *ignore.Matcher, onChange func(changedPaths []string)) error {
+// watchPolicy decides when a burst of file events becomes a rebuild. Each
+// event restarts the Debounce quiet period, so editors and formatters that
+// write a file several times per save trigger one rebuild. MaxWait bounds how
+// long a steady stream of events can postpone a rebuild, and MinInterval
+// spaces rebuilds out; zero disables either rule.
+type watchPolicy struct {
+	Debounce    time.Duration
+	MaxWait     time.Duration
+	MinInterval time.Duration
+}
+
+// addWatchPolicyFlags registers the coalescing flags shared by watch modes.
+func addWatchPolicyFlags(cmd *cobra.Command, policy *watchPolicy) {
+	cmd.Flags().DurationVar(&policy.Debounce, "debounce", 0, "quiet period after the last file event before rebuilding (default: --interval)")
+	cmd.Flags().DurationVar(&policy.MaxWait, "max-wait", 0, "rebuild at the latest this long after the first pending change, even while events keep arriving (0 = no limit)")
+	cmd.Flags().DurationVar(&policy.MinInterval, "min-rebuild-interval", 0, "minimum time between rebuilds; changes in between are batched (0 = no limit)")
+}
+
+// withDefaults fills in the debounce from the legacy --interval flag.
+func (p watchPolicy) withDefaults(interval time.Duration) watchPolicy {
+	if p.Debounce <= 0 {
+		p.Debounce = interval
+	}
+	if p.Debounce <= 0 {
+		p.Debounce = 250 * time.Millisecond
+	}
+	return p
+}
+
+func (p watchPolicy) validate() error {
+	if p.Debounce < 0 || p.MaxWait < 0 || p.MinInterval < 0 {
+		return fmt.Errorf("--debounce, --max-wait, and --min-rebuild-interval must not be negative")
+	}
+	return nil
+}
+
+// nextRebuild returns when pending changes should be rebuilt, given when the
+// first and last of them arrived and when the previous rebuild started.
+func (p watchPolicy) nextRebuild(firstPending, lastEvent, lastRebuild time.Time) time.Time {
+	due := lastEvent.Add(p.Debounce)
+	if p.MaxWait > 0 {
+		if limit := firstPending.Add(p.MaxWait); limit.Before(due) {
+			due = limit
+		}
+	}
+	if p.MinInterval > 0 && !lastRebuild.IsZero() {
+		if earliest := lastRebuild.Add(p.MinInterval); earliest.After(due) {
+			due = earliest
+		}
+	}
+	return due
+}
+
+func watchWithFSNotify(ctx context.Context, target string, policy watchPolicy, ignorePaths map[string]bool, ignoreMatcher *ignore.Matcher, onChange func(changedPaths []string)) error {
 	roots, err := watchRoots(target)
 	if err != nil {
 		return err
 	}
+	for _, root := range roots {
+		if fs := unwatchableFilesystem(root); fs != "" {
+			return fmt.Errorf("%s is on a %s, which does not report file changes", root, fs)
+		}
+	}
 
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -38,9 +97,7 @@ func watchWithFSNotify(ctx context.Context, target string, debounce time.Duratio
 		}
 	}
 
-	if debounce <= 0 {
-		debounce = 250 * time.Millisecond
-	}
+	policy = policy.withDefaults(0)
 
 	timer := time.NewTimer(time.Hour)
 	if !timer.Stop() {
@@ -51,21 +108,16 @@ func watchWithFSNotify(ctx context.Context, target string, debounce time.Duratio
 	}
 	pending := false
 	pendingPaths := map[string]bool{}
+	var firstPending, lastEvent, lastRebuild time.Time
 
-	resetDebounce := func(path string) {
-		if path != "" {
-			pendingPaths[path] = true
-		}
-		if pending {
-			if !timer.Stop() {
-				select {
-				case <-timer.C:
-				default:
-				}
+	schedule := func() {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
 			}
 		}
-		timer.Reset(debounce)
-		pending = true
+		timer.Reset(time.Until(policy.nextRebuild(firstPending, lastEvent, lastRebuild)))
 	}
 
 	for {
@@ -88,21 +140,35 @@ func watchWithFSNotify(ctx context.Context, target string, debounce time.Duratio
 				}
 			}
 
-			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Remove|fsnotify.Rename|fsnotify.Chmod) == 0 {
+			// Permission and timestamp changes alone never change content.
+			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Remove|fsnotify.Rename) == 0 {
 				continue
 			}
-			resetDebounce(eventPath)
+			now := time.Now()
+			if !pending {
+				firstPending = now
+			}
+			lastEvent = now
+			pendingPaths[eventPath] = true
+			pending = true
+			schedule()
 		case <-timer.C:
-			if pending {
-				pending = false
-				changed := make([]string, 0, len(pendingPaths))
-				for path := range pendingPaths {
-					changed = append(changed, path)
-				}
-				sort.Strings(changed)
-				pendingPaths = map[string]bool{}
-				onChange(changed)
+			if !pending {
+				continue
+			}
+			if due := policy.nextRebuild(firstPending, lastEvent, lastRebuild); time.Now().Before(due) {
+				schedule()
+				continue
+			}
+			pending = false
+			changed := make([]string, 0, len(pendingPaths))
+			for path := range pendingPaths {
+				changed = append(changed, path)
 			}
+			sort.Strings(changed)
+			pendingPaths = map[string]bool{}
+			lastRebuild = time.Now()
+			onChange(changed)
 		case watchErr, ok := <-watcher.Errors:
 			if !ok {
 				return nil
@@ -301,8 +367,13 @@ func printIndexSummary(idx *model.Index, stats index.BuildStats, incremental boo
 			stats.ParsedFiles,
 			stats.ReusedFiles,
 		)
-		return
+	} else {
+		fmt.Printf("indexed: files=%d symbols=%d errors=%d root=%s\n", idx.FileCount(), idx.SymbolCount(), len(idx.Errors), idx.Root)
+	}
+	if idx.Revision != "" {
+		fmt.Printf("revision: %s\n", idx.Revision)
+	}
+	if stats.SkippedFiles > 0 || stats.SkippedGenerated > 0 {
+		fmt.Printf("skipped: files=%d generated=%d\n", stats.SkippedFiles, stats.SkippedGenerated)
 	}
-
-	fmt.Printf("indexed: files=%d symbols=%d errors=%d root=%s\n", idx.FileCount(), idx.SymbolCount(), len(idx.Errors), idx.Root)
 }
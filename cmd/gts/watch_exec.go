@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/structdiff"
+)
+
+// watchExec describes one structural change for gts index build --watch
+// --exec. The command sees it through GTS_* environment variables, and the
+// full structdiff report is written to the JSON file named by
+// GTS_CHANGE_REPORT for the duration of the command.
+type watchExec struct {
+	Command      string
+	Root         string
+	CachePath    string
+	ChangedFiles []string
+	Report       structdiff.Report
+}
+
+// Run runs the command through the platform shell and waits for it. Its
+// output goes to stdout and stderr; a non-zero exit is returned as an error.
+func (w watchExec) Run(stdout, stderr io.Writer) error {
+	reportFile, err := os.CreateTemp("", "gts-change-*.json")
+	if err != nil {
+		return err
+	}
+	reportPath := reportFile.Name()
+	defer os.Remove(reportPath)
+
+	encoder := json.NewEncoder(reportFile)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(w.Report); err != nil {
+		reportFile.Close()
+		return err
+	}
+	if err := reportFile.Close(); err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", w.Command)
+	} else {
+		cmd = exec.Command("sh", "-c", w.Command)
+	}
+	cmd.Dir = w.Root
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(),
+		"GTS_ROOT="+w.Root,
+		"GTS_INDEX="+w.CachePath,
+		"GTS_CHANGE_REPORT="+reportPath,
+		"GTS_CHANGED_FILES="+strings.Join(w.ChangedFiles, "\n"),
+		"GTS_ADDED_SYMBOLS="+strconv.Itoa(w.Report.Stats.AddedSymbols),
+		"GTS_REMOVED_SYMBOLS="+strconv.Itoa(w.Report.Stats.RemovedSymbols),
+		"GTS_MODIFIED_SYMBOLS="+strconv.Itoa(w.Report.Stats.ModifiedSymbols),
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("exec %q: %w", w.Command, err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+//go:build darwin
+
+package main
+
+import "syscall"
+
+// unnotifiedFilesystems lists the network filesystems whose changes made
+// elsewhere never reach FSEvents or kqueue.
+var unnotifiedFilesystems = map[string]bool{
+	"smbfs":  true,
+	"nfs":    true,
+	"afpfs":  true,
+	"webdav": true,
+}
+
+// unwatchableFilesystem describes the filesystem holding dir, such as "smbfs
+// filesystem", when it does not deliver change notifications, or returns ""
+// when fsnotify can watch it.
+func unwatchableFilesystem(dir string) string {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return ""
+	}
+	name := make([]byte, 0, len(stat.Fstypename))
+	for _, c := range stat.Fstypename {
+		if c == 0 {
+			break
+		}
+		name = append(name, byte(c))
+	}
+	if unnotifiedFilesystems[string(name)] {
+		return string(name) + " filesystem"
+	}
+	return ""
+}
+
+// watchLimit reports no limit to check: kqueue holds a descriptor per
+// watched file, bounded by the open-file limit rather than a watch count.
+func watchLimit() (int, bool) { return 0, false }
@@ -0,0 +1,48 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// unnotifiedFilesystems maps the statfs magic numbers of filesystems whose
+// changes made elsewhere never reach inotify to their names. WSL 2 mounts
+// Windows drives over 9p.
+var unnotifiedFilesystems = map[uint32]string{
+	0xFF534D42: "cifs",
+	0xFE534D42: "smb2",
+	0x0000517B: "smb",
+	0x00006969: "nfs",
+	0x01021997: "9p",
+	0x5346414F: "afs",
+	0x786F4256: "vboxsf",
+}
+
+// unwatchableFilesystem describes the filesystem holding dir, such as "cifs
+// filesystem", when it does not deliver change notifications, or returns ""
+// when fsnotify can watch it.
+func unwatchableFilesystem(dir string) string {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return ""
+	}
+	if name, ok := unnotifiedFilesystems[uint32(stat.Type)]; ok {
+		return name + " filesystem"
+	}
+	return ""
+}
+
+// watchLimit returns fs.inotify.max_user_watches, the most directories the
+// user's fsnotify watchers can watch together.
+func watchLimit() (int, bool) {
+	data, err := os.ReadFile("/proc/sys/fs/inotify/max_user_watches")
+	if err != nil {
+		return 0, false
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	return limit, err == nil
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !windows
+
+package main
+
+func unwatchableFilesystem(dir string) string { return "" }
+
+func watchLimit() (int, bool) { return 0, false }
@@ -0,0 +1,40 @@
+//go:build windows
+
+package main
+
+import (
+	"path/filepath"
+	"syscall"
+	"unsafe"
+
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
+)
+
+const driveRemote = 4 // DRIVE_REMOTE from GetDriveTypeW
+
+var procGetDriveType = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDriveTypeW")
+
+// unwatchableFilesystem returns "network drive" when dir is on a UNC share
+// or a mapped network drive, where ReadDirectoryChangesW misses changes made
+// by other machines and fails outright on many NAS servers, or "" otherwise.
+func unwatchableFilesystem(dir string) string {
+	if slashpath.IsUNC(dir) {
+		return "network drive"
+	}
+	volume := filepath.VolumeName(dir)
+	if volume == "" {
+		return ""
+	}
+	root, err := syscall.UTF16PtrFromString(volume + `\`)
+	if err != nil {
+		return ""
+	}
+	if kind, _, _ := procGetDriveType.Call(uintptr(unsafe.Pointer(root))); kind == driveRemote {
+		return "network drive"
+	}
+	return ""
+}
+
+// watchLimit reports no limit: ReadDirectoryChangesW does not cap watched
+// directories the way inotify does.
+func watchLimit() (int, bool) { return 0, false }
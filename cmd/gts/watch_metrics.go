@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// watchMetrics tracks rebuilds of a long-running watcher and serves them in
+// the Prometheus text format on /metrics, with a liveness check on /healthz.
+// A nil *watchMetrics ignores every call, so callers need not check whether
+// --metrics-addr was set.
+type watchMetrics struct {
+	mu            sync.Mutex
+	rebuilds      int
+	failures      int
+	durationSum   float64
+	lastDuration  float64
+	lastRebuild   time.Time
+	lastError     string
+	files         int
+	symbols       int
+	parseErrors   int
+	startedAtUnix int64
+}
+
+func newWatchMetrics() *watchMetrics {
+	return &watchMetrics{startedAtUnix: time.Now().Unix()}
+}
+
+// Observe records one rebuild. idx is the current index after the rebuild,
+// which on failure is the previous one.
+func (m *watchMetrics) Observe(duration time.Duration, idx *model.Index, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rebuilds++
+	m.durationSum += duration.Seconds()
+	m.lastDuration = duration.Seconds()
+	m.lastRebuild = time.Now()
+	m.lastError = ""
+	if err != nil {
+		m.failures++
+		m.lastError = err.Error()
+	}
+	if idx != nil {
+		m.files = idx.FileCount()
+		m.symbols = idx.SymbolCount()
+		m.parseErrors = len(idx.Errors)
+	}
+}
+
+func (m *watchMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/healthz":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if m.lastError != "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "last rebuild failed: %s\n", m.lastError)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	case "/metrics":
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writeMetric(w, "gts_watch_rebuilds_total", "counter", "Index rebuilds run by the watcher, including failed ones.", float64(m.rebuilds))
+		writeMetric(w, "gts_watch_rebuild_failures_total", "counter", "Index rebuilds that returned an error.", float64(m.failures))
+		fmt.Fprintln(w, "# HELP gts_watch_rebuild_duration_seconds Time spent rebuilding the index.")
+		fmt.Fprintln(w, "# TYPE gts_watch_rebuild_duration_seconds summary")
+		fmt.Fprintf(w, "gts_watch_rebuild_duration_seconds_sum %g\n", m.durationSum)
+		fmt.Fprintf(w, "gts_watch_rebuild_duration_seconds_count %d\n", m.rebuilds)
+		writeMetric(w, "gts_watch_last_rebuild_duration_seconds", "gauge", "Duration of the most recent rebuild.", m.lastDuration)
+		lastRebuild := 0.0
+		if !m.lastRebuild.IsZero() {
+			lastRebuild = float64(m.lastRebuild.Unix())
+		}
+		writeMetric(w, "gts_watch_last_rebuild_timestamp_seconds", "gauge", "Unix time of the most recent rebuild.", lastRebuild)
+		writeMetric(w, "gts_watch_files", "gauge", "Files in the current index.", float64(m.files))
+		writeMetric(w, "gts_watch_symbols", "gauge", "Symbols in the current index.", float64(m.symbols))
+		writeMetric(w, "gts_watch_parse_errors", "gauge", "Files that failed to parse in the current index.", float64(m.parseErrors))
+		writeMetric(w, "gts_watch_start_time_seconds", "gauge", "Unix time the watcher started.", float64(m.startedAtUnix))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeMetric(w http.ResponseWriter, name, kind, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", name, help, name, kind, name, value)
+}
+
+// serveWatchMetrics listens on addr until ctx is done. A bare port such as
+// ":9464" binds to localhost only.
+func serveWatchMetrics(ctx context.Context, addr string, metrics *watchMetrics) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("--metrics-addr: %w", err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return fmt.Errorf("--metrics-addr: %w", err)
+	}
+
+	server := &http.Server{Handler: metrics, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "metrics server error: %v\n", err)
+		}
+	}()
+	fmt.Fprintf(os.Stderr, "metrics: http://%s/metrics\n", listener.Addr())
+	return nil
+}
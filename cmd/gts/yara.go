@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/yara"
 )
 
@@ -23,7 +24,7 @@ func newYaraCmd() *cobra.Command {
 		Short:   "Generate YARA rules from structural analysis",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			target := "."
+			target := defaultTarget()
 			if len(args) == 1 {
 				target = args[0]
 			}
@@ -45,10 +46,7 @@ func newYaraCmd() *cobra.Command {
 			}
 
 			if jsonOutput {
-				return emitJSON(struct {
-					Count int                  `json:"count"`
-					Rules []yara.GeneratedRule `json:"rules,omitempty"`
-				}{
+				return emitJSON(report.YaraReport{
 					Count: len(rules),
 					Rules: rules,
 				})
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/odvcencio/gts-suite/pkg/lsp"
 	"github.com/odvcencio/gts-suite/pkg/socket"
@@ -34,7 +35,9 @@ func main() {
 	}
 
 	// LSP mode (default)
-	svc := lsp.NewService(nil)
+	svc := lsp.NewServiceWithOptions(nil, lsp.ServiceOptions{
+		AllowedRoots: filepath.SplitList(os.Getenv("GTSLS_ALLOWED_ROOTS")),
+	})
 	srv := lsp.NewServer(os.Stdin, os.Stdout, os.Stderr)
 	svc.Register(srv)
 
@@ -6,10 +6,10 @@ require (
 	github.com/fsnotify/fsnotify v1.9.0
 	github.com/odvcencio/gotreesitter v0.13.0
 	github.com/spf13/cobra v1.10.2
+	github.com/spf13/pflag v1.0.9
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.9 // indirect
 	golang.org/x/sys v0.41.0 // indirect
 )
@@ -0,0 +1,325 @@
+// Package cachedir inspects and prunes the .gts directory, where gts keeps
+// the index cache, cached analysis results, trend history, and daemon state.
+// Nothing else bounds its size: result entries accumulate as the index
+// changes, and interrupted saves and watches leave temporary files behind.
+package cachedir
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odvcencio/gts-suite/internal/resultcache"
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
+)
+
+// DirName is the cache directory relative to a project root.
+const DirName = ".gts"
+
+// daemonSocket is the daemon's socket relative to a project root.
+const daemonSocket = ".gts/daemon.sock"
+
+// tempGrace is how old a temporary file must be before GC removes it, so
+// saves still in progress are left alone.
+const tempGrace = 10 * time.Minute
+
+// Entry is a file in the cache directory.
+type Entry struct {
+	Path      string    `json:"path"`
+	SizeBytes int64     `json:"size_bytes"`
+	ModTime   time.Time `json:"mod_time"`
+}
+
+// IndexInfo describes an index cache.
+type IndexInfo struct {
+	Entry
+	SchemaVersion string `json:"schema_version"`
+	// Current reports whether this build of gts can load the cache; one
+	// written by an older format is rebuilt on next use.
+	Current     bool      `json:"current"`
+	Root        string    `json:"root"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       int       `json:"files"`
+	// StaleFiles counts indexed files no longer present on disk.
+	StaleFiles int    `json:"stale_files"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ResultsInfo summarizes the result cache.
+type ResultsInfo struct {
+	Entries   int        `json:"entries"`
+	SizeBytes int64      `json:"size_bytes"`
+	Oldest    *time.Time `json:"oldest,omitempty"`
+	Newest    *time.Time `json:"newest,omitempty"`
+}
+
+// Info describes the cache directory of a project.
+type Info struct {
+	Dir        string      `json:"dir"`
+	Exists     bool        `json:"exists"`
+	TotalBytes int64       `json:"total_bytes"`
+	Index      *IndexInfo  `json:"index,omitempty"`
+	Results    ResultsInfo `json:"results"`
+	// Temp lists files left by interrupted saves and watches.
+	Temp []Entry `json:"temp,omitempty"`
+	// Other lists the remaining files, such as trends.jsonl and daemon.log.
+	Other []Entry `json:"other,omitempty"`
+}
+
+// Inspect describes the cache directory under root, reading the index cache
+// at indexPath. indexPath may lie outside the directory.
+func Inspect(root, indexPath string) (*Info, error) {
+	dir := filepath.Join(root, DirName)
+	info := &Info{Dir: dir}
+	if fi, err := os.Stat(indexPath); err == nil {
+		info.Index = inspectIndex(indexPath, fi)
+	}
+
+	resultsDir := filepath.Join(root, filepath.FromSlash(resultcache.Dir))
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			info.Exists = true
+		}
+		if d.IsDir() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		entry := Entry{Path: path, SizeBytes: fi.Size(), ModTime: fi.ModTime()}
+		info.TotalBytes += entry.SizeBytes
+		switch {
+		case isTemp(d.Name()):
+			info.Temp = append(info.Temp, entry)
+		case info.Index != nil && sameFile(path, indexPath):
+			// Reported as Index.
+		case filepath.Dir(path) == resultsDir && strings.HasSuffix(d.Name(), ".json"):
+			info.Results.add(entry)
+		default:
+			info.Other = append(info.Other, entry)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (r *ResultsInfo) add(entry Entry) {
+	r.Entries++
+	r.SizeBytes += entry.SizeBytes
+	modTime := entry.ModTime
+	if r.Oldest == nil || modTime.Before(*r.Oldest) {
+		r.Oldest = &modTime
+	}
+	if r.Newest == nil || modTime.After(*r.Newest) {
+		r.Newest = &modTime
+	}
+}
+
+// indexHeader is the part of a cached index Inspect reads. Decoding it
+// directly, rather than with index.Load, also works for caches in an older
+// format.
+type indexHeader struct {
+	Version     string    `json:"version"`
+	Root        string    `json:"root"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       []struct {
+		Path string `json:"path"`
+	} `json:"files"`
+}
+
+func inspectIndex(path string, fi os.FileInfo) *IndexInfo {
+	info := &IndexInfo{Entry: Entry{Path: path, SizeBytes: fi.Size(), ModTime: fi.ModTime()}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	var header indexHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.SchemaVersion = header.Version
+	info.Current = header.Version == "" || header.Version == index.SchemaVersion()
+	info.Root = header.Root
+	info.GeneratedAt = header.GeneratedAt
+	info.Files = len(header.Files)
+	for _, file := range header.Files {
+		if !exists(header.Root, file.Path) {
+			info.StaleFiles++
+		}
+	}
+	return info
+}
+
+// CleanReport lists the index entries Clean dropped.
+type CleanReport struct {
+	Index   string   `json:"index"`
+	Removed []string `json:"removed"`
+	DryRun  bool     `json:"dry_run,omitempty"`
+}
+
+// Clean drops the files no longer present on disk from the index cached at
+// indexPath, along with their parse errors and skipped-file entries, and
+// saves it unless dryRun is set.
+func Clean(indexPath string, dryRun bool) (*CleanReport, error) {
+	idx, err := index.Load(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	report := &CleanReport{Index: indexPath, Removed: []string{}, DryRun: dryRun}
+
+	files := idx.Files[:0]
+	for _, file := range idx.Files {
+		if exists(idx.Root, file.Path) {
+			files = append(files, file)
+		} else {
+			report.Removed = append(report.Removed, file.Path)
+		}
+	}
+	idx.Files = files
+	errs := idx.Errors[:0]
+	for _, parseErr := range idx.Errors {
+		if exists(idx.Root, parseErr.Path) {
+			errs = append(errs, parseErr)
+		} else {
+			report.Removed = append(report.Removed, parseErr.Path)
+		}
+	}
+	idx.Errors = errs
+	skipped := idx.Skipped[:0]
+	for _, file := range idx.Skipped {
+		if exists(idx.Root, file.Path) {
+			skipped = append(skipped, file)
+		} else {
+			report.Removed = append(report.Removed, file.Path)
+		}
+	}
+	idx.Skipped = skipped
+	sort.Strings(report.Removed)
+
+	if len(report.Removed) == 0 || dryRun {
+		return report, nil
+	}
+	if idx.Digest != "" {
+		idx.Digest = index.Digest(idx)
+	}
+	if err := index.Save(indexPath, idx); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GCOptions controls GC.
+type GCOptions struct {
+	// MaxAge is how long a cached result is kept after it was stored. Zero
+	// keeps results.
+	MaxAge time.Duration
+	// Now is the time ages are measured from; zero means time.Now().
+	Now    time.Time
+	DryRun bool
+}
+
+// GCReport lists the files GC removed.
+type GCReport struct {
+	Dir        string  `json:"dir"`
+	Removed    []Entry `json:"removed"`
+	FreedBytes int64   `json:"freed_bytes"`
+	DryRun     bool    `json:"dry_run,omitempty"`
+}
+
+// GC prunes the cache directory under root: temporary files left by
+// interrupted index saves, result stores, and chunk-watch manifests once
+// they are older than a few minutes; the
+// daemon socket when no daemon is listening on it; and, with opts.MaxAge,
+// results stored longer ago than that. Cached results are keyed by the
+// index they were computed from, so once the index changes the old entries
+// are never read again.
+func GC(root string, opts GCOptions) (*GCReport, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	dir := filepath.Join(root, DirName)
+	resultsDir := filepath.Join(root, filepath.FromSlash(resultcache.Dir))
+	socket := filepath.Join(root, filepath.FromSlash(daemonSocket))
+	report := &GCReport{Dir: dir, Removed: []Entry{}, DryRun: opts.DryRun}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		var remove bool
+		switch {
+		case isTemp(d.Name()):
+			remove = now.Sub(fi.ModTime()) > tempGrace
+		case path == socket:
+			remove = !listening(path)
+		case filepath.Dir(path) == resultsDir && strings.HasSuffix(d.Name(), ".json"):
+			remove = opts.MaxAge > 0 && now.Sub(fi.ModTime()) > opts.MaxAge
+		}
+		if !remove {
+			return nil
+		}
+		if !opts.DryRun {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		report.Removed = append(report.Removed, Entry{Path: path, SizeBytes: fi.Size(), ModTime: fi.ModTime()})
+		report.FreedBytes += fi.Size()
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// isTemp reports whether name is a temporary file of an atomic save:
+// index.Save writes "<name>.tmp-*", the result cache "<key>.*.tmp", and
+// chunk watch "<manifest>.tmp".
+func isTemp(name string) bool {
+	return strings.HasSuffix(name, ".tmp") || strings.Contains(name, ".tmp-")
+}
+
+func listening(socket string) bool {
+	conn, err := net.DialTimeout("unix", socket, 200*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func exists(root, rel string) bool {
+	_, err := os.Lstat(slashpath.Join(root, rel))
+	return err == nil
+}
+
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
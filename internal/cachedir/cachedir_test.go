@@ -0,0 +1,137 @@
+package cachedir
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+// setupProject indexes a.go and b.go under a temporary root, deletes b.go,
+// and adds a cached result, a temporary file, and trend history.
+func setupProject(t *testing.T) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("package x\n\nfunc F() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx, err := index.NewBuilder().BuildPath(root)
+	if err != nil {
+		t.Fatalf("BuildPath: %v", err)
+	}
+	indexPath := filepath.Join(root, ".gts", "index.json")
+	if err := index.Save(indexPath, idx); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, "b.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		".gts/results/old.json":       "{}",
+		".gts/results/new.json":       "{}",
+		".gts/index.json.tmp-123":     "{",
+		".gts/results/new.json.1.tmp": "{",
+		".gts/trends.jsonl":           "{}\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	for _, rel := range []string{".gts/results/old.json", ".gts/index.json.tmp-123"} {
+		if err := os.Chtimes(filepath.Join(root, filepath.FromSlash(rel)), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root, indexPath
+}
+
+func TestInspect(t *testing.T) {
+	root, indexPath := setupProject(t)
+	info, err := Inspect(root, indexPath)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if info.Index == nil || !info.Index.Current || info.Index.Files != 2 || info.Index.StaleFiles != 1 {
+		t.Fatalf("unexpected index info: %+v", info.Index)
+	}
+	if info.Results.Entries != 2 {
+		t.Fatalf("expected 2 results, got %+v", info.Results)
+	}
+	if len(info.Temp) != 2 {
+		t.Fatalf("expected 2 temp files, got %+v", info.Temp)
+	}
+	if len(info.Other) != 1 || filepath.Base(info.Other[0].Path) != "trends.jsonl" {
+		t.Fatalf("expected trends.jsonl as the only other file, got %+v", info.Other)
+	}
+
+	empty, err := Inspect(t.TempDir(), "missing.json")
+	if err != nil || empty.Exists || empty.Index != nil {
+		t.Fatalf("expected an empty report, got %+v, %v", empty, err)
+	}
+}
+
+func TestClean(t *testing.T) {
+	root, indexPath := setupProject(t)
+	report, err := Clean(indexPath, true)
+	if err != nil {
+		t.Fatalf("Clean dry run: %v", err)
+	}
+	if !reflect.DeepEqual(report.Removed, []string{"b.go"}) {
+		t.Fatalf("Removed = %v, want [b.go]", report.Removed)
+	}
+	if idx, _ := index.Load(indexPath); idx.FileCount() != 2 {
+		t.Fatal("expected a dry run to leave the index alone")
+	}
+
+	if _, err := Clean(indexPath, false); err != nil {
+		t.Fatalf("Clean: %v", err)
+	}
+	idx, err := index.Load(indexPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if idx.FileCount() != 1 || idx.Files[0].Path != "a.go" {
+		t.Fatalf("expected only a.go after clean, got %+v", idx.Files)
+	}
+	if idx.Digest != index.Digest(idx) {
+		t.Fatal("expected the digest to be recomputed")
+	}
+	if info, _ := Inspect(root, indexPath); info.Index.StaleFiles != 0 {
+		t.Fatalf("expected no stale files after clean, got %d", info.Index.StaleFiles)
+	}
+}
+
+func TestGC(t *testing.T) {
+	root, _ := setupProject(t)
+	report, err := GC(root, GCOptions{MaxAge: 7 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	var removed []string
+	for _, entry := range report.Removed {
+		rel, _ := filepath.Rel(root, entry.Path)
+		removed = append(removed, filepath.ToSlash(rel))
+	}
+	// The fresh temp file may belong to a save in progress.
+	want := []string{".gts/index.json.tmp-123", ".gts/results/old.json"}
+	if !reflect.DeepEqual(removed, want) {
+		t.Fatalf("removed %v, want %v", removed, want)
+	}
+	for _, rel := range []string{".gts/results/new.json", ".gts/results/new.json.1.tmp", ".gts/trends.jsonl", ".gts/index.json"} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(rel))); err != nil {
+			t.Errorf("expected %s to be kept: %v", rel, err)
+		}
+	}
+}
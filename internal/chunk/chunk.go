@@ -3,23 +3,31 @@ package chunk
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/odvcencio/gts-suite/pkg/lang/notebook"
 	"github.com/odvcencio/gts-suite/pkg/model"
 )
 
 type Options struct {
 	TokenBudget int
 	FilterPath  string
+	// Previous is the chunk manifest of an earlier run. When set, every
+	// chunk gets a Status relative to it and Report.Removed lists the IDs
+	// that disappeared.
+	Previous []Chunk
 }
 
 type Chunk struct {
+	ID        string `json:"id"`
+	Hash      string `json:"hash"`
+	Status    string `json:"status,omitempty"`
 	File      string `json:"file"`
 	Kind      string `json:"kind"`
 	Name      string `json:"name,omitempty"`
+	Container string `json:"container,omitempty"`
 	StartLine int    `json:"start_line"`
 	EndLine   int    `json:"end_line"`
 	Tokens    int    `json:"tokens"`
@@ -32,6 +40,10 @@ type Report struct {
 	TokenBudget int     `json:"token_budget"`
 	ChunkCount  int     `json:"chunk_count"`
 	Chunks      []Chunk `json:"chunks,omitempty"`
+
+	Incremental bool     `json:"incremental,omitempty"`
+	Unchanged   int      `json:"unchanged,omitempty"`
+	Removed     []string `json:"removed,omitempty"`
 }
 
 func Build(idx *model.Index, opts Options) (Report, error) {
@@ -54,14 +66,22 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 		}
 
 		absPath := filepath.Join(idx.Root, filepath.FromSlash(file.Path))
-		source, err := os.ReadFile(absPath)
+		source, err := notebook.ReadSource(absPath)
 		if err != nil {
 			return Report{}, err
 		}
 		lines := splitLines(string(source))
+		ordinals := map[string]int{}
+		identify := func(chunk *Chunk, name string) {
+			key := chunk.Kind + "\x00" + chunk.Container + "\x00" + name
+			chunk.ID = chunkID(chunk.File, chunk.Kind, chunk.Container, name, ordinals[key])
+			chunk.Hash = contentHash(chunk.Content)
+			ordinals[key]++
+		}
 
 		if len(file.Symbols) == 0 {
 			single := makeChunk(file.Path, "file", filepath.Base(file.Path), lines, 1, len(lines), opts.TokenBudget)
+			identify(&single, single.Name)
 			report.Chunks = append(report.Chunks, single)
 			continue
 		}
@@ -76,11 +96,16 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 		if firstStart > 1 {
 			header := makeChunk(file.Path, "file_header", filepath.Base(file.Path), lines, 1, firstStart-1, opts.TokenBudget)
 			if strings.TrimSpace(header.Content) != "" {
+				identify(&header, header.Name)
 				report.Chunks = append(report.Chunks, header)
 			}
 		}
 
 		for _, symbol := range file.Symbols {
+			if symbol.Kind == "closure_definition" {
+				// Closures are already part of their enclosing function's chunk.
+				continue
+			}
 			name := symbol.Name
 			if strings.TrimSpace(symbol.Signature) != "" {
 				name = symbol.Signature
@@ -94,6 +119,8 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 				symbol.EndLine,
 				opts.TokenBudget,
 			)
+			chunk.Container = symbol.ContainerPath
+			identify(&chunk, symbol.Name)
 			report.Chunks = append(report.Chunks, chunk)
 		}
 	}
@@ -108,6 +135,9 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 		return report.Chunks[i].File < report.Chunks[j].File
 	})
 	report.ChunkCount = len(report.Chunks)
+	if opts.Previous != nil {
+		reconcile(&report, opts.Previous)
+	}
 	return report, nil
 }
 
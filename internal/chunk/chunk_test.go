@@ -52,6 +52,37 @@ func B() {
 	}
 }
 
+func TestBuild_RecordsSymbolContainer(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package sample
+
+type Service struct{}
+
+func (s *Service) Run() {}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "sample.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	report, err := Build(idx, Options{TokenBudget: 400})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	for _, chunk := range report.Chunks {
+		if chunk.Kind == "method_definition" {
+			if chunk.Container != "Service" {
+				t.Fatalf("expected method chunk container Service, got %q", chunk.Container)
+			}
+			return
+		}
+	}
+	t.Fatal("expected method_definition chunk")
+}
+
 func TestBuild_TruncatesToTokenBudget(t *testing.T) {
 	tmpDir := t.TempDir()
 	sourcePath := filepath.Join(tmpDir, "sample.go")
@@ -101,3 +132,96 @@ func hasChunkKind(report Report, kind string) bool {
 	}
 	return false
 }
+
+func TestBuild_IncrementalKeepsStableIDs(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "sample.go")
+	before := `package sample
+
+func A() {
+	println(1)
+}
+
+func B() {
+	println(2)
+}
+
+func C() {
+	println(3)
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(before), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	first, err := Build(idx, Options{TokenBudget: 400})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	manifestPath := filepath.Join(t.TempDir(), "chunks.json")
+	if err := WriteManifest(manifestPath, first); err != nil {
+		t.Fatalf("WriteManifest returned error: %v", err)
+	}
+	previous, err := LoadManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("LoadManifest returned error: %v", err)
+	}
+
+	// Insert a comment above A, change B's body, and drop C.
+	after := `package sample
+
+// A returns one.
+func A() {
+	println(1)
+}
+
+func B() {
+	println(20)
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(after), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err = index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	second, err := Build(idx, Options{TokenBudget: 400, Previous: previous})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	ids := map[string]string{}
+	for _, chunk := range first.Chunks {
+		ids[chunk.Name] = chunk.ID
+	}
+	statuses := map[string]string{}
+	for _, chunk := range second.Chunks {
+		if ids[chunk.Name] != "" && ids[chunk.Name] != chunk.ID {
+			t.Fatalf("chunk %q changed id from %s to %s", chunk.Name, ids[chunk.Name], chunk.ID)
+		}
+		statuses[chunk.Name] = chunk.Status
+	}
+	if statuses["func A()"] != StatusMoved {
+		t.Fatalf("expected A to be moved, got %q", statuses["func A()"])
+	}
+	if statuses["func B()"] != StatusModified {
+		t.Fatalf("expected B to be modified, got %q", statuses["func B()"])
+	}
+	if len(second.Removed) != 1 || second.Removed[0] != ids["func C()"] {
+		t.Fatalf("expected C's id to be removed, got %v", second.Removed)
+	}
+
+	changes := second.Changes()
+	if changes.ChunkCount != len(second.Chunks)-second.Unchanged {
+		t.Fatalf("expected %d changed chunks, got %d", len(second.Chunks)-second.Unchanged, changes.ChunkCount)
+	}
+	for _, chunk := range changes.Chunks {
+		if chunk.Status == StatusUnchanged {
+			t.Fatalf("Changes kept unchanged chunk %+v", chunk)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package chunk
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Chunk statuses relative to a previous manifest.
+const (
+	StatusAdded     = "added"
+	StatusModified  = "modified"
+	StatusMoved     = "moved"
+	StatusUnchanged = "unchanged"
+)
+
+// chunkID derives an identifier from what a chunk is rather than where it is,
+// so a chunk keeps its ID when lines above it change. ordinal separates
+// same-named chunks within one file, such as overloads.
+func chunkID(file, kind, container, name string, ordinal int) string {
+	sum := sha256.Sum256([]byte(file + "\x00" + kind + "\x00" + container + "\x00" + name + "\x00" + strconv.Itoa(ordinal)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// reconcile sets each chunk's status against the previous manifest and
+// records the IDs of chunks that no longer exist.
+func reconcile(report *Report, previous []Chunk) {
+	before := make(map[string]Chunk, len(previous))
+	for _, chunk := range previous {
+		before[chunk.ID] = chunk
+	}
+
+	report.Incremental = true
+	seen := make(map[string]bool, len(report.Chunks))
+	for i := range report.Chunks {
+		current := &report.Chunks[i]
+		seen[current.ID] = true
+		old, ok := before[current.ID]
+		switch {
+		case !ok:
+			current.Status = StatusAdded
+		case old.Hash != current.Hash:
+			current.Status = StatusModified
+		case old.StartLine != current.StartLine || old.EndLine != current.EndLine:
+			current.Status = StatusMoved
+		default:
+			current.Status = StatusUnchanged
+			report.Unchanged++
+		}
+	}
+
+	for id := range before {
+		if !seen[id] {
+			report.Removed = append(report.Removed, id)
+		}
+	}
+	sort.Strings(report.Removed)
+}
+
+// Changes returns a copy of an incremental report holding only the chunks
+// that need upserting: added, modified, and moved ones. Moved chunks have
+// the same content and only need their line metadata refreshed.
+func (r Report) Changes() Report {
+	if !r.Incremental {
+		return r
+	}
+	changed := r
+	changed.Chunks = make([]Chunk, 0, len(r.Chunks)-r.Unchanged)
+	for _, chunk := range r.Chunks {
+		if chunk.Status != StatusUnchanged {
+			changed.Chunks = append(changed.Chunks, chunk)
+		}
+	}
+	changed.ChunkCount = len(changed.Chunks)
+	return changed
+}
+
+// Chunk events emitted when syncing against a manifest.
+const (
+	EventAdd    = "add"
+	EventUpdate = "update"
+	EventDelete = "delete"
+)
+
+// Event is one change a downstream store has to apply. Delete events carry
+// only the ID.
+type Event struct {
+	Event string `json:"event"`
+	ID    string `json:"id"`
+	*Chunk
+}
+
+// Events converts an incremental report into the add, update, and delete
+// operations that bring a store holding the previous manifest up to date.
+func Events(report Report) []Event {
+	events := make([]Event, 0, len(report.Chunks)+len(report.Removed))
+	for i := range report.Chunks {
+		chunk := &report.Chunks[i]
+		switch chunk.Status {
+		case StatusUnchanged:
+			continue
+		case StatusModified, StatusMoved:
+			events = append(events, Event{Event: EventUpdate, ID: chunk.ID, Chunk: chunk})
+		default:
+			events = append(events, Event{Event: EventAdd, ID: chunk.ID, Chunk: chunk})
+		}
+	}
+	for _, id := range report.Removed {
+		events = append(events, Event{Event: EventDelete, ID: id})
+	}
+	return events
+}
+
+// LoadManifest reads the chunks of a report previously written by
+// WriteManifest or emitted as JSON. Paths ending in .jsonl hold one chunk
+// per line.
+func LoadManifest(path string) ([]Chunk, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report Report
+	if isJSONL(path) {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var chunk Chunk
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				return nil, fmt.Errorf("parse chunk manifest %s: %w", path, err)
+			}
+			report.Chunks = append(report.Chunks, chunk)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read chunk manifest %s: %w", path, err)
+		}
+	} else if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("parse chunk manifest %s: %w", path, err)
+	}
+	for _, chunk := range report.Chunks {
+		if chunk.ID == "" || chunk.Hash == "" {
+			return nil, fmt.Errorf("chunk manifest %s has chunks without id or hash", path)
+		}
+	}
+	return report.Chunks, nil
+}
+
+// WriteManifest saves every chunk of report without content, for use as the
+// previous manifest of a later incremental run. Paths ending in .jsonl get
+// one chunk per line.
+func WriteManifest(path string, report Report) error {
+	manifest := Report{
+		Root:        report.Root,
+		TokenBudget: report.TokenBudget,
+		ChunkCount:  len(report.Chunks),
+		Chunks:      make([]Chunk, 0, len(report.Chunks)),
+	}
+	for _, chunk := range report.Chunks {
+		chunk.Content = ""
+		chunk.Status = ""
+		manifest.Chunks = append(manifest.Chunks, chunk)
+	}
+
+	var data []byte
+	if isJSONL(path) {
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		for _, chunk := range manifest.Chunks {
+			if err := encoder.Encode(chunk); err != nil {
+				return err
+			}
+		}
+		data = buf.Bytes()
+	} else {
+		encoded, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(encoded, '\n')
+	}
+
+	// Write through a temporary file so a watcher never reads a torn manifest.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func isJSONL(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".jsonl")
+}
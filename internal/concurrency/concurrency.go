@@ -0,0 +1,296 @@
+// Package concurrency inventories the concurrency primitives of indexed Go
+// sources: goroutine launches, channel makes and sends, mutexes, and
+// sync.WaitGroups, grouped by package.
+//
+// Sites are matched with tree-sitter queries. Mutexes and WaitGroups are
+// reported where they are declared — struct fields, variables, composite
+// literals, and new() — not where they are passed as parameters.
+package concurrency
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+	"github.com/odvcencio/gotreesitter/grammars"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Site kinds.
+const (
+	KindGo        = "go"
+	KindChanMake  = "chan_make"
+	KindChanSend  = "chan_send"
+	KindMutex     = "mutex"
+	KindWaitGroup = "waitgroup"
+)
+
+// Kinds lists the site kinds in report order.
+var Kinds = []string{KindGo, KindChanMake, KindChanSend, KindMutex, KindWaitGroup}
+
+// Site is one use of a concurrency primitive.
+type Site struct {
+	Kind    string `json:"kind"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Package string `json:"package"`
+	// Symbol is the innermost function, method, or type containing the site.
+	Symbol string `json:"symbol,omitempty"`
+	// Detail describes the site, e.g. "work(ch)" for a go statement or
+	// "field mu sync.Mutex" for a mutex.
+	Detail string `json:"detail"`
+}
+
+// PackageSummary counts the sites of one package by kind.
+type PackageSummary struct {
+	Package string         `json:"package"`
+	Counts  map[string]int `json:"counts"`
+	Sites   []Site         `json:"sites"`
+}
+
+var kindNames = map[string]string{
+	"go": KindGo, "go statement": KindGo, "goroutine": KindGo,
+	"chan": KindChanMake, "channel": KindChanMake, "chan make": KindChanMake, "channel make": KindChanMake,
+	"chan send": KindChanSend, "channel send": KindChanSend,
+	"mutex": KindMutex, "rwmutex": KindMutex, "sync.mutex": KindMutex, "sync.rwmutex": KindMutex,
+	"waitgroup": KindWaitGroup, "wait group": KindWaitGroup, "sync.waitgroup": KindWaitGroup,
+}
+
+// ParseKind maps a primitive name as written in reports and lint rules, such
+// as "go statement", "goroutines", or "channel send", to its site kind.
+func ParseKind(text string) (string, bool) {
+	normalized := strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(text))
+	normalized = strings.Join(strings.Fields(normalized), " ")
+	for _, candidate := range []string{normalized, strings.TrimSuffix(normalized, "s"), strings.TrimSuffix(normalized, "es")} {
+		if kind, ok := kindNames[candidate]; ok {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+const goConcurrencyQuery = `
+(go_statement (call_expression) @call) @go
+
+(send_statement channel: (_) @channel) @send
+
+(call_expression
+  function: (identifier) @fn
+  arguments: (argument_list (channel_type) @chan)
+  (#eq? @fn "make")) @make
+
+(qualified_type
+  package: (package_identifier) @pkg
+  name: (type_identifier) @type
+  (#eq? @pkg "sync")
+  (#match? @type "^(Mutex|RWMutex|WaitGroup)$")) @sync
+`
+
+// Extract returns the concurrency sites of the indexed Go files, ordered by
+// file and line.
+func Extract(idx *model.Index) ([]Site, error) {
+	if idx == nil {
+		return nil, fmt.Errorf("index is nil")
+	}
+
+	var entry grammars.LangEntry
+	for _, candidate := range grammars.AllLanguages() {
+		if candidate.Name == "go" && candidate.Language != nil {
+			entry = candidate
+			break
+		}
+	}
+	if entry.Language == nil {
+		return nil, nil
+	}
+
+	var (
+		lang   *gotreesitter.Language
+		parser *gotreesitter.Parser
+		query  *gotreesitter.Query
+	)
+	var sites []Site
+	for _, file := range idx.Files {
+		if file.Language != "go" {
+			continue
+		}
+		if lang == nil {
+			lang = entry.Language()
+			if lang == nil {
+				return nil, nil
+			}
+			compiled, err := gotreesitter.NewQuery(goConcurrencyQuery, lang)
+			if err != nil {
+				return nil, fmt.Errorf("compile concurrency query: %w", err)
+			}
+			query = compiled
+			parser = gotreesitter.NewParser(lang)
+		}
+
+		source, err := os.ReadFile(filepath.Join(idx.Root, filepath.FromSlash(file.Path)))
+		if err != nil {
+			continue
+		}
+
+		var tree *gotreesitter.Tree
+		var parseErr error
+		if entry.TokenSourceFactory != nil {
+			tokenSource := entry.TokenSourceFactory(source, lang)
+			if tokenSource != nil {
+				tree, parseErr = parser.ParseWithTokenSource(source, tokenSource)
+			}
+		}
+		if tree == nil && parseErr == nil {
+			tree, parseErr = parser.Parse(source)
+		}
+		if parseErr != nil || tree == nil || tree.RootNode() == nil {
+			continue
+		}
+
+		pkg := filepath.ToSlash(filepath.Dir(file.Path))
+		for _, match := range query.Execute(tree) {
+			captures := map[string]*gotreesitter.Node{}
+			for _, capture := range match.Captures {
+				if capture.Node != nil {
+					captures[capture.Name] = capture.Node
+				}
+			}
+			site, ok := interpret(lang, source, captures)
+			if !ok {
+				continue
+			}
+			site.File = file.Path
+			site.Package = pkg
+			site.Symbol = enclosingSymbol(file.Symbols, site.Line)
+			sites = append(sites, site)
+		}
+		tree.Release()
+	}
+
+	sort.SliceStable(sites, func(i, j int) bool {
+		if sites[i].File == sites[j].File {
+			return sites[i].Line < sites[j].Line
+		}
+		return sites[i].File < sites[j].File
+	})
+	return sites, nil
+}
+
+// Summarize groups sites by package, ordered by package path.
+func Summarize(sites []Site) []PackageSummary {
+	byPackage := map[string]*PackageSummary{}
+	for _, site := range sites {
+		summary, ok := byPackage[site.Package]
+		if !ok {
+			summary = &PackageSummary{Package: site.Package, Counts: map[string]int{}}
+			byPackage[site.Package] = summary
+		}
+		summary.Counts[site.Kind]++
+		summary.Sites = append(summary.Sites, site)
+	}
+
+	summaries := make([]PackageSummary, 0, len(byPackage))
+	for _, summary := range byPackage {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Package < summaries[j].Package })
+	return summaries
+}
+
+func interpret(lang *gotreesitter.Language, src []byte, captures map[string]*gotreesitter.Node) (Site, bool) {
+	text := func(node *gotreesitter.Node) string {
+		return strings.Join(strings.Fields(node.Text(src)), " ")
+	}
+	line := func(node *gotreesitter.Node) int {
+		return int(node.StartPoint().Row) + 1
+	}
+
+	switch {
+	case captures["go"] != nil && captures["call"] != nil:
+		detail := text(captures["call"])
+		if function := captures["call"].ChildByFieldName("function", lang); function != nil && function.Type(lang) == "func_literal" {
+			detail = "func literal"
+		}
+		return Site{Kind: KindGo, Line: line(captures["go"]), Detail: detail}, true
+	case captures["send"] != nil && captures["channel"] != nil:
+		return Site{Kind: KindChanSend, Line: line(captures["send"]), Detail: text(captures["channel"]) + " <-"}, true
+	case captures["make"] != nil && captures["chan"] != nil:
+		return Site{Kind: KindChanMake, Line: line(captures["make"]), Detail: text(captures["chan"])}, true
+	case captures["sync"] != nil && captures["type"] != nil:
+		kind := KindMutex
+		if text(captures["type"]) == "WaitGroup" {
+			kind = KindWaitGroup
+		}
+		detail, ok := declaration(lang, src, captures["sync"])
+		if !ok {
+			return Site{}, false
+		}
+		return Site{Kind: kind, Line: line(captures["sync"]), Detail: detail}, true
+	}
+	return Site{}, false
+}
+
+// declaration describes where a sync type is declared, e.g.
+// "field mu sync.Mutex", or reports false when the type is only referenced,
+// as in a parameter list.
+func declaration(lang *gotreesitter.Language, src []byte, typeNode *gotreesitter.Node) (string, bool) {
+	typeName := strings.TrimSpace(typeNode.Text(src))
+	node := typeNode
+	for parent := node.Parent(); parent != nil; node, parent = parent, parent.Parent() {
+		switch parent.Type(lang) {
+		case "pointer_type":
+			typeName = "*" + typeName
+			continue
+		case "field_declaration":
+			name := parent.ChildByFieldName("name", lang)
+			if name == nil {
+				return "embedded " + typeName, true
+			}
+			return "field " + strings.TrimSpace(name.Text(src)) + " " + typeName, true
+		case "var_spec":
+			name := parent.ChildByFieldName("name", lang)
+			if name == nil {
+				return "var " + typeName, true
+			}
+			return "var " + strings.TrimSpace(name.Text(src)) + " " + typeName, true
+		case "composite_literal":
+			return typeName + "{}", true
+		case "argument_list":
+			if call := parent.Parent(); call != nil && call.Type(lang) == "call_expression" {
+				if function := call.ChildByFieldName("function", lang); function != nil && strings.TrimSpace(function.Text(src)) == "new" {
+					return "new(" + typeName + ")", true
+				}
+			}
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// enclosingSymbol returns the name of the innermost function, method, or type
+// spanning line.
+func enclosingSymbol(symbols []model.Symbol, line int) string {
+	best := -1
+	for i, symbol := range symbols {
+		if !model.IsFunctionKind(symbol.Kind) && symbol.Kind != "method_definition" && symbol.Kind != "type_definition" {
+			continue
+		}
+		if line < symbol.StartLine || line > symbol.EndLine {
+			continue
+		}
+		if best < 0 || symbol.StartLine > symbols[best].StartLine {
+			best = i
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	if receiver := strings.Fields(symbols[best].Receiver); len(receiver) > 0 {
+		return strings.TrimLeft(receiver[len(receiver)-1], "*&") + "." + symbols[best].Name
+	}
+	return symbols[best].Name
+}
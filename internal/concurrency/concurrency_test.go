@@ -0,0 +1,107 @@
+package concurrency
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+func TestExtract(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "worker/pool.go", `package worker
+
+import "sync"
+
+type Pool struct {
+	mu sync.Mutex
+	sync.RWMutex
+	jobs chan int
+}
+
+func (p *Pool) Run(wg *sync.WaitGroup) {
+	var done sync.WaitGroup
+	results := make(chan int, 1)
+	go func() { results <- 1 }()
+	go process(p.jobs)
+	p.jobs <- 2
+	_ = new(sync.WaitGroup)
+}
+
+func process(jobs chan int) {}
+`)
+	writeFile(t, root, "main.go", `package main
+
+func main() { go serve() }
+`)
+
+	idx, err := index.NewBuilder().BuildPath(root)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	sites, err := Extract(idx)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+
+	got := make([]string, 0, len(sites))
+	for _, site := range sites {
+		got = append(got, fmt.Sprintf("%s:%d %s %s [%s]", site.File, site.Line, site.Kind, site.Detail, site.Symbol))
+	}
+	want := []string{
+		"main.go:3 go serve() [main]",
+		"worker/pool.go:6 mutex field mu sync.Mutex [Pool]",
+		"worker/pool.go:7 mutex embedded sync.RWMutex [Pool]",
+		"worker/pool.go:12 waitgroup var done sync.WaitGroup [Pool.Run]",
+		"worker/pool.go:13 chan_make chan int [Pool.Run]",
+		"worker/pool.go:14 go func literal [Pool.Run]",
+		"worker/pool.go:14 chan_send results <- [Pool.Run]",
+		"worker/pool.go:15 go process(p.jobs) [Pool.Run]",
+		"worker/pool.go:16 chan_send p.jobs <- [Pool.Run]",
+		"worker/pool.go:17 waitgroup new(sync.WaitGroup) [Pool.Run]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected sites:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	summaries := Summarize(sites)
+	if len(summaries) != 2 || summaries[0].Package != "." || summaries[1].Package != "worker" {
+		t.Fatalf("unexpected summaries %+v", summaries)
+	}
+	if counts := summaries[1].Counts; counts[KindGo] != 2 || counts[KindChanSend] != 2 || counts[KindMutex] != 2 || counts[KindWaitGroup] != 2 {
+		t.Fatalf("unexpected worker counts %v", counts)
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	cases := map[string]string{
+		"go statement":   KindGo,
+		"goroutines":     KindGo,
+		"channel sends":  KindChanSend,
+		"chan_make":      KindChanMake,
+		"mutexes":        KindMutex,
+		"sync.WaitGroup": KindWaitGroup,
+	}
+	for text, want := range cases {
+		if got, ok := ParseKind(text); !ok || got != want {
+			t.Fatalf("ParseKind(%q) = %q, %v; want %q", text, got, ok, want)
+		}
+	}
+	if _, ok := ParseKind("import unsafe"); ok {
+		t.Fatal("expected ParseKind to reject an import")
+	}
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
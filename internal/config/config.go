@@ -0,0 +1,256 @@
+// Package config loads project defaults from a .gts/config.yaml file so the
+// flags every gts invocation in a project repeats can be written down once:
+//
+//	# Defaults for gts in this repository.
+//	cache: .gts/index.json
+//	root: .
+//	tokens: 1200
+//	read_only: true
+//	exclude:
+//	  - vendor
+//	  - third_party/generated
+//	tags:
+//	  tsx: .gts/tags/react.scm
+//	  python: [.gts/tags/pytest.scm]
+//	commands:
+//	  index build:
+//	    ignore: ["*.pb.go", "*_mock.go"]
+//	    max-memory: 2GB
+//	  search grep:
+//	    json: true
+//	  mcp:
+//	    tool-timeout:
+//	      - gts_query=30s
+//
+// The file is written in the YAML subset of package yamlsubset, shared with
+// .gts/queries.yaml and lint rule packs. Relative cache, root, and tags query
+// paths are resolved against the directory holding .gts.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/yamlsubset"
+)
+
+// FileName is the config file path relative to a project root.
+const FileName = ".gts/config.yaml"
+
+// Config is a parsed config file.
+type Config struct {
+	// Path is the config file, and Dir the directory holding its .gts
+	// directory. Both are empty for a Config from Parse.
+	Path string `json:"path,omitempty"`
+	Dir  string `json:"dir,omitempty"`
+
+	// Cache is where the index of Root is cached, replacing the
+	// .gts/index.json default.
+	Cache string `json:"cache,omitempty"`
+	// Root is the path commands analyze when given none, replacing the
+	// working directory.
+	Root string `json:"root,omitempty"`
+	// Tokens is the default token budget of commands and tools that take one.
+	Tokens int `json:"tokens,omitempty"`
+	// ReadOnly refuses every flag that would change source files, such as
+	// refactor --write and lint --fix.
+	ReadOnly bool `json:"read_only,omitempty"`
+	// Exclude lists directories left out of every index, as gitignore-style
+	// paths.
+	Exclude []string `json:"exclude,omitempty"`
+	// Tags maps a language name such as "tsx" to tags query files whose
+	// patterns are added to its built-in tags query, to capture definitions
+	// and references the built-in query misses.
+	Tags map[string][]string `json:"tags,omitempty"`
+	// Commands maps a command path such as "index build" to flag defaults
+	// for it, keyed by flag name without dashes. Repeatable flags may have
+	// several values.
+	Commands map[string]map[string][]string `json:"commands,omitempty"`
+}
+
+// Flags returns the flag defaults configured for command, a command path
+// without the program name such as "index build". c may be nil.
+func (c *Config) Flags(command string) map[string][]string {
+	if c == nil {
+		return nil
+	}
+	return c.Commands[command]
+}
+
+// ExcludePatterns returns Exclude as ignore patterns matching directories.
+func (c *Config) ExcludePatterns() []string {
+	if c == nil {
+		return nil
+	}
+	patterns := make([]string, 0, len(c.Exclude))
+	for _, dir := range c.Exclude {
+		dir = strings.TrimSpace(filepath.ToSlash(dir))
+		if dir == "" {
+			continue
+		}
+		if !strings.HasSuffix(dir, "/") {
+			dir += "/"
+		}
+		patterns = append(patterns, dir)
+	}
+	return patterns
+}
+
+// CachePath returns where the index of target is cached: Cache when target
+// is the configured root, or the directory holding .gts when no root is
+// set, and target/.gts/index.json otherwise. c may be nil.
+func (c *Config) CachePath(target string) string {
+	fallback := filepath.Join(target, ".gts", "index.json")
+	if c == nil || c.Cache == "" {
+		return fallback
+	}
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return fallback
+	}
+	root := c.Root
+	if root == "" {
+		root = c.Dir
+	}
+	if abs != root {
+		return fallback
+	}
+	return c.Cache
+}
+
+// Parse parses the content of a config file.
+func Parse(content string) (*Config, error) {
+	doc, err := yamlsubset.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	for _, key := range doc.Keys {
+		value := doc.Fields[key]
+		switch key {
+		case "cache":
+			cfg.Cache, err = value.Value(key)
+		case "root":
+			cfg.Root, err = value.Value(key)
+		case "tokens":
+			var raw string
+			if raw, err = value.Value(key); err == nil {
+				cfg.Tokens, err = strconv.Atoi(raw)
+				if err != nil || cfg.Tokens <= 0 {
+					err = fmt.Errorf("line %d: tokens must be a positive integer, got %q", value.Line, raw)
+				}
+			}
+		case "read_only":
+			var raw string
+			if raw, err = value.Value(key); err == nil {
+				cfg.ReadOnly, err = strconv.ParseBool(raw)
+				if err != nil {
+					err = fmt.Errorf("line %d: read_only must be true or false, got %q", value.Line, raw)
+				}
+			}
+		case "exclude":
+			cfg.Exclude, err = value.Values(key)
+		case "tags":
+			cfg.Tags, err = parseTags(value)
+		case "commands":
+			cfg.Commands, err = parseCommands(value)
+		default:
+			err = fmt.Errorf("line %d: unknown setting %q", value.Line, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+func parseTags(value *yamlsubset.Node) (map[string][]string, error) {
+	if !value.IsMap() {
+		return nil, fmt.Errorf("line %d: tags must map language names to query files", value.Line)
+	}
+	tags := make(map[string][]string, len(value.Keys))
+	for _, language := range value.Keys {
+		files, err := value.Fields[language].Values(language)
+		if err != nil {
+			return nil, err
+		}
+		tags[strings.ToLower(language)] = files
+	}
+	return tags, nil
+}
+
+func parseCommands(value *yamlsubset.Node) (map[string]map[string][]string, error) {
+	if !value.IsMap() {
+		return nil, fmt.Errorf("line %d: commands must map command names to flags", value.Line)
+	}
+	commands := make(map[string]map[string][]string, len(value.Keys))
+	for _, name := range value.Keys {
+		block := value.Fields[name]
+		if !block.IsMap() {
+			return nil, fmt.Errorf("line %d: command %q must map flag names to values", block.Line, name)
+		}
+		flags := make(map[string][]string, len(block.Keys))
+		for _, flag := range block.Keys {
+			values, err := block.Fields[flag].Values(flag)
+			if err != nil {
+				return nil, err
+			}
+			flags[strings.TrimLeft(flag, "-")] = values
+		}
+		commands[strings.Join(strings.Fields(name), " ")] = flags
+	}
+	return commands, nil
+}
+
+// Load searches for .gts/config.yaml starting in dir and walking up parent
+// directories. It returns a nil Config with no error when none is found.
+func Load(dir string) (*Config, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving directory: %w", err)
+	}
+	if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+		abs = filepath.Dir(abs)
+	}
+
+	for {
+		candidate := filepath.Join(abs, filepath.FromSlash(FileName))
+		data, err := os.ReadFile(candidate)
+		if err == nil {
+			cfg, parseErr := Parse(string(data))
+			if parseErr != nil {
+				return nil, fmt.Errorf("parsing %s: %w", candidate, parseErr)
+			}
+			cfg.Path = candidate
+			cfg.Dir = abs
+			cfg.Cache = resolve(abs, cfg.Cache)
+			cfg.Root = resolve(abs, cfg.Root)
+			for _, files := range cfg.Tags {
+				for i, file := range files {
+					files[i] = resolve(abs, file)
+				}
+			}
+			return cfg, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading %s: %w", candidate, err)
+		}
+
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return nil, nil
+		}
+		abs = parent
+	}
+}
+
+func resolve(dir, p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, filepath.FromSlash(p))
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `# project defaults
+cache: .gts/cache/index.json # shared with CI
+root: src
+tokens: 1200
+read_only: true
+exclude:
+  - vendor
+  - 'third_party/gen/'
+tags:
+  TSX: .gts/tags/react.scm
+  python: [.gts/tags/pytest.scm, /opt/tags/django.scm]
+commands:
+  index build:
+    ignore: ["*.pb.go", '*_mock.go']
+    max-memory: 2GB
+  search  grep:
+    --json: true
+  mcp:
+    tool-timeout:
+      - gts_query=30s
+      - gts_refs=10s
+`
+	got, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := &Config{
+		Cache:    ".gts/cache/index.json",
+		Root:     "src",
+		Tokens:   1200,
+		ReadOnly: true,
+		Exclude:  []string{"vendor", "third_party/gen/"},
+		Tags: map[string][]string{
+			"tsx":    {".gts/tags/react.scm"},
+			"python": {".gts/tags/pytest.scm", "/opt/tags/django.scm"},
+		},
+		Commands: map[string]map[string][]string{
+			"index build": {"ignore": {"*.pb.go", "*_mock.go"}, "max-memory": {"2GB"}},
+			"search grep": {"json": {"true"}},
+			"mcp":         {"tool-timeout": {"gts_query=30s", "gts_refs=10s"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Parse mismatch\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if patterns := got.ExcludePatterns(); !reflect.DeepEqual(patterns, []string{"vendor/", "third_party/gen/"}) {
+		t.Fatalf("ExcludePatterns = %v", patterns)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"unknown setting":   "colour: red\n",
+		"bad tokens":        "tokens: lots\n",
+		"zero tokens":       "tokens: 0\n",
+		"bad read_only":     "read_only: sometimes\n",
+		"duplicate":         "root: a\nroot: b\n",
+		"list root":         "root: [a, b]\n",
+		"flat command":      "commands:\n  index build: --json\n",
+		"nested flag map":   "commands:\n  mcp:\n    root:\n      a: b\n",
+		"tabs":              "commands:\n\tmcp:\n",
+		"stray indent":      "root: a\n  cache: b\n",
+		"unterminated":      "root: 'a\n",
+		"unterminated list": "exclude: [a, b\n",
+		"flat tags":         "tags: react.scm\n",
+	}
+	for name, content := range cases {
+		if _, err := Parse(content); err == nil {
+			t.Errorf("%s: expected error for %q", name, content)
+		}
+	}
+}
+
+func TestLoadResolvesPaths(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "pkg", "api")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".gts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gts", "config.yaml"), []byte("cache: .gts/shared.json\ntags:\n  tsx: .gts/react.scm\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(nested)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg == nil || cfg.Dir != root {
+		t.Fatalf("expected config in %s, got %+v", root, cfg)
+	}
+	wantCache := filepath.Join(root, ".gts", "shared.json")
+	if cfg.Cache != wantCache {
+		t.Fatalf("Cache = %q, want %q", cfg.Cache, wantCache)
+	}
+	if got, want := cfg.Tags["tsx"], []string{filepath.Join(root, ".gts", "react.scm")}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Tags[tsx] = %v, want %v", got, want)
+	}
+	if got := cfg.CachePath(root); got != wantCache {
+		t.Fatalf("CachePath(root) = %q, want %q", got, wantCache)
+	}
+	if got := cfg.CachePath(nested); got != filepath.Join(nested, ".gts", "index.json") {
+		t.Fatalf("CachePath(nested) = %q, want the default", got)
+	}
+
+	if cfg, err := Load(t.TempDir()); err != nil || cfg != nil {
+		t.Fatalf("expected no config, got %+v, %v", cfg, err)
+	}
+	var none *Config
+	if got := none.CachePath("x"); got != filepath.Join("x", ".gts", "index.json") {
+		t.Fatalf("nil CachePath = %q", got)
+	}
+}
@@ -0,0 +1,214 @@
+// Package configusage inventories the environment variables and config keys
+// read by indexed sources, so deployment docs can be checked against code.
+//
+// Reads are matched with tree-sitter queries: os.Getenv, os.LookupEnv, viper
+// getters, and env struct tags in Go; process.env and import.meta.env in
+// JavaScript and TypeScript; os.environ and os.getenv in Python;
+// System.getenv, getProperty, and Spring @Value in Java; and std::env::var
+// and env! in Rust. Only keys given as string literals are reported.
+package configusage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/internal/parsesession"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Key kinds.
+const (
+	KindEnv    = "env"
+	KindConfig = "config"
+)
+
+// Read is one place a key is read.
+type Read struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Language string `json:"language"`
+	Source   string `json:"source"` // the API reading it, e.g. "os.Getenv"
+	Default  string `json:"default,omitempty"`
+}
+
+// Key is an environment variable or config key and every read of it.
+type Key struct {
+	Name     string   `json:"name"`
+	Kind     string   `json:"kind"`
+	Defaults []string `json:"defaults,omitempty"`
+	Reads    []Read   `json:"reads"`
+}
+
+// Doc is a deployment document, such as .env.example or a README, that keys
+// are checked against.
+type Doc struct {
+	Path    string
+	Content string
+}
+
+// Drift lists the differences between code and docs.
+type Drift struct {
+	// Undocumented are keys read in code but mentioned in no doc.
+	Undocumented []string `json:"undocumented,omitempty"`
+	// Unused are variables assigned in dotenv-style docs (NAME=value) that no
+	// code reads.
+	Unused []string `json:"unused,omitempty"`
+}
+
+// Build returns the keys read in the indexed files, environment variables
+// first, each ordered by name.
+func Build(idx *model.Index) ([]Key, error) {
+	if idx == nil {
+		return nil, fmt.Errorf("index is nil")
+	}
+
+	session := parsesession.New()
+
+	type keyID struct{ name, kind string }
+	keys := map[keyID]*Key{}
+	for _, file := range idx.Files {
+		syntax, ok := syntaxByLanguage[file.Language]
+		if !ok {
+			continue
+		}
+		lang, ok := session.Language(file.Language)
+		if !ok {
+			continue
+		}
+		query, err := session.Query(file.Language, syntax.query)
+		if err != nil {
+			return nil, fmt.Errorf("compile %s config query: %w", file.Language, err)
+		}
+
+		source, err := os.ReadFile(filepath.Join(idx.Root, filepath.FromSlash(file.Path)))
+		if err != nil {
+			continue
+		}
+
+		tree, parseErr := session.Parse(file.Language, source)
+		if parseErr != nil {
+			continue
+		}
+
+		m := matcher{lang: lang, src: source, file: file}
+		for _, match := range query.Execute(tree) {
+			captures := map[string]*gotreesitter.Node{}
+			for _, capture := range match.Captures {
+				if capture.Node != nil {
+					captures[capture.Name] = capture.Node
+				}
+			}
+			if captures["read"] == nil {
+				continue
+			}
+			line := int(captures["read"].StartPoint().Row) + 1
+			for _, found := range syntax.interpret(&m, captures) {
+				if found.name == "" {
+					continue
+				}
+				id := keyID{found.name, found.kind}
+				key, ok := keys[id]
+				if !ok {
+					key = &Key{Name: found.name, Kind: found.kind}
+					keys[id] = key
+				}
+				if found.defaultOnly {
+					key.Defaults = appendUnique(key.Defaults, found.value)
+					continue
+				}
+				key.Reads = append(key.Reads, Read{
+					File:     file.Path,
+					Line:     line,
+					Language: file.Language,
+					Source:   found.source,
+					Default:  found.value,
+				})
+				if found.value != "" {
+					key.Defaults = appendUnique(key.Defaults, found.value)
+				}
+			}
+		}
+		tree.Release()
+	}
+
+	result := make([]Key, 0, len(keys))
+	for _, key := range keys {
+		if len(key.Reads) == 0 {
+			continue // a default for a key nothing reads
+		}
+		sort.SliceStable(key.Reads, func(i, j int) bool {
+			if key.Reads[i].File == key.Reads[j].File {
+				return key.Reads[i].Line < key.Reads[j].Line
+			}
+			return key.Reads[i].File < key.Reads[j].File
+		})
+		result = append(result, *key)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind == KindEnv
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+var dotenvAssignment = regexp.MustCompile(`(?m)^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=`)
+
+// CompareDocs reports the keys missing from docs and the dotenv variables in
+// docs that no code reads. A key is documented when its name appears as a
+// whole word in any doc.
+func CompareDocs(keys []Key, docs []Doc) Drift {
+	var drift Drift
+	read := map[string]bool{}
+	checked := map[string]bool{}
+	for _, key := range keys {
+		if key.Kind == KindEnv {
+			read[key.Name] = true
+		}
+		if checked[key.Name] {
+			continue
+		}
+		checked[key.Name] = true
+		documented := false
+		pattern := regexp.MustCompile(`(^|[^A-Za-z0-9_.])` + regexp.QuoteMeta(key.Name) + `($|[^A-Za-z0-9_])`)
+		for _, doc := range docs {
+			if pattern.MatchString(doc.Content) {
+				documented = true
+				break
+			}
+		}
+		if !documented {
+			drift.Undocumented = append(drift.Undocumented, key.Name)
+		}
+	}
+
+	unused := map[string]bool{}
+	for _, doc := range docs {
+		for _, match := range dotenvAssignment.FindAllStringSubmatch(doc.Content, -1) {
+			if !read[match[1]] {
+				unused[match[1]] = true
+			}
+		}
+	}
+	for name := range unused {
+		drift.Unused = append(drift.Unused, name)
+	}
+	sort.Strings(drift.Undocumented)
+	sort.Strings(drift.Unused)
+	return drift
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,116 @@
+package configusage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+func TestBuild(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "cmd/server/main.go", `package main
+
+import (
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Port  int    `+"`"+`env:"PORT" envDefault:"8080"`+"`"+`
+	Debug bool   `+"`"+`json:"debug"`+"`"+`
+}
+
+func main() {
+	os.Getenv("HOME")
+	viper.SetDefault("db.pool", 10)
+	viper.GetInt("db.pool")
+	viper.BindEnv("db.url", "DATABASE_URL")
+	r.Get("/users", handler)
+}
+`)
+	writeFile(t, root, "web/config.js", `const port = process.env.PORT || "3000";
+const { REDIS_URL, CACHE_TTL = "60" } = process.env;
+const api = import.meta.env.VITE_API;
+`)
+	writeFile(t, root, "app/settings.py", `import os
+
+HOME = os.environ["HOME"]
+DEBUG = os.environ.get("DEBUG", "0")
+`)
+	writeFile(t, root, "java/App.java", `class App {
+    @Value("${server.port:8080}") int port;
+    void f() { System.getenv("HOME"); }
+}
+`)
+	writeFile(t, root, "src/main.rs", `fn main() { let v = env!("CARGO_PKG_VERSION"); let h = std::env::var("HOME"); }
+`)
+
+	idx, err := index.NewBuilder().BuildPath(root)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	keys, err := Build(idx)
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	got := make([]string, 0, len(keys))
+	for _, key := range keys {
+		sources := make([]string, 0, len(key.Reads))
+		for _, read := range key.Reads {
+			sources = append(sources, fmt.Sprintf("%s:%d:%s", read.File, read.Line, read.Source))
+		}
+		got = append(got, fmt.Sprintf("%s %s defaults=%v %s", key.Kind, key.Name, key.Defaults, strings.Join(sources, " ")))
+	}
+	want := []string{
+		"env CACHE_TTL defaults=[60] web/config.js:2:process.env",
+		"env CARGO_PKG_VERSION defaults=[] src/main.rs:1:env!",
+		"env DATABASE_URL defaults=[] cmd/server/main.go:18:viper.BindEnv",
+		"env DEBUG defaults=[0] app/settings.py:4:os.environ.get",
+		"env HOME defaults=[] app/settings.py:3:os.environ cmd/server/main.go:15:os.Getenv java/App.java:3:System.getenv src/main.rs:1:std::env::var",
+		"env PORT defaults=[8080 3000] cmd/server/main.go:10:env tag web/config.js:1:process.env",
+		"env REDIS_URL defaults=[] web/config.js:2:process.env",
+		"env VITE_API defaults=[] web/config.js:3:import.meta.env",
+		"config db.pool defaults=[10] cmd/server/main.go:17:viper.GetInt",
+		"config server.port defaults=[8080] java/App.java:2:@Value",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected keys:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCompareDocs(t *testing.T) {
+	keys := []Key{
+		{Name: "PORT", Kind: KindEnv},
+		{Name: "DATABASE_URL", Kind: KindEnv},
+		{Name: "db.pool", Kind: KindConfig},
+	}
+	docs := []Doc{
+		{Path: ".env.example", Content: "# server\nPORT=8080\nexport LEGACY_TOKEN=\n"},
+		{Path: "README.md", Content: "Set `db.pool` to size the pool. PORTS are not used.\n"},
+	}
+	drift := CompareDocs(keys, docs)
+	if !reflect.DeepEqual(drift.Undocumented, []string{"DATABASE_URL"}) {
+		t.Fatalf("unexpected undocumented keys %v", drift.Undocumented)
+	}
+	if !reflect.DeepEqual(drift.Unused, []string{"LEGACY_TOKEN"}) {
+		t.Fatalf("unexpected unused keys %v", drift.Unused)
+	}
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
@@ -0,0 +1,431 @@
+package configusage
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// readSyntax pairs a language's read query with the function turning its
+// captures into keys. Every pattern captures the read as @read.
+type readSyntax struct {
+	query     string
+	interpret func(m *matcher, captures map[string]*gotreesitter.Node) []found
+}
+
+// found is a key read, or with defaultOnly, a default set for a key.
+type found struct {
+	name        string
+	kind        string
+	source      string
+	value       string
+	defaultOnly bool
+}
+
+var syntaxByLanguage = map[string]readSyntax{
+	"go":         {query: goReadQuery, interpret: goReads},
+	"javascript": {query: jsReadQuery, interpret: jsReads},
+	"typescript": {query: jsReadQuery, interpret: jsReads},
+	"tsx":        {query: jsReadQuery, interpret: jsReads},
+	"python":     {query: pythonReadQuery, interpret: pythonReads},
+	"java":       {query: javaReadQuery, interpret: javaReads},
+	"rust":       {query: rustReadQuery, interpret: rustReads},
+}
+
+const goReadQuery = `
+(call_expression
+  function: (selector_expression
+    operand: (_) @object
+    field: (field_identifier) @verb)
+  arguments: (argument_list) @args
+  (#match? @verb "^(Getenv|LookupEnv|Get|GetString|GetBool|GetInt|GetInt32|GetInt64|GetUint|GetUint32|GetUint64|GetFloat64|GetDuration|GetTime|GetSizeInBytes|GetIntSlice|GetStringSlice|GetStringMap|GetStringMapString|GetStringMapStringSlice|IsSet|SetDefault|BindEnv)$")
+) @read
+
+(field_declaration
+  tag: (raw_string_literal) @tag) @read
+`
+
+const jsReadQuery = `
+(member_expression
+  object: (member_expression
+    property: (property_identifier) @env) @object
+  property: (property_identifier) @key
+  (#eq? @env "env")
+) @read
+
+(subscript_expression
+  object: (member_expression
+    property: (property_identifier) @env) @object
+  index: (string) @key
+  (#eq? @env "env")
+) @read
+
+(variable_declarator
+  name: (object_pattern) @pattern
+  value: (member_expression
+    property: (property_identifier) @env) @object
+  (#eq? @env "env")
+) @read
+`
+
+const pythonReadQuery = `
+(subscript
+  value: (attribute) @object
+  subscript: (string) @key
+) @read
+
+(call
+  function: (attribute
+    object: (_) @object
+    attribute: (identifier) @verb)
+  arguments: (argument_list) @args
+  (#match? @verb "^(getenv|get|setdefault)$")
+) @read
+`
+
+const javaReadQuery = `
+(method_invocation
+  object: (_) @object
+  name: (identifier) @verb
+  arguments: (argument_list) @args
+  (#match? @verb "^(getenv|getProperty|getRequiredProperty)$")
+) @read
+
+(annotation
+  name: (identifier) @verb
+  arguments: (annotation_argument_list) @args
+  (#eq? @verb "Value")
+) @read
+`
+
+const rustReadQuery = `
+(call_expression
+  function: (scoped_identifier
+    name: (identifier) @verb) @object
+  arguments: (arguments) @args
+  (#match? @verb "^(var|var_os)$")
+) @read
+
+(macro_invocation
+  macro: (identifier) @verb
+  (token_tree) @args
+  (#match? @verb "^(env|option_env)$")
+) @read
+`
+
+// matcher carries the file being matched.
+type matcher struct {
+	lang *gotreesitter.Language
+	src  []byte
+	file model.FileSummary
+}
+
+func (m *matcher) text(node *gotreesitter.Node) string {
+	if node == nil {
+		return ""
+	}
+	return strings.TrimSpace(node.Text(m.src))
+}
+
+func (m *matcher) arguments(list *gotreesitter.Node) []*gotreesitter.Node {
+	if list == nil {
+		return nil
+	}
+	var args []*gotreesitter.Node
+	for i := 0; i < list.ChildCount(); i++ {
+		child := list.Child(i)
+		if child != nil && child.IsNamed() && child.Type(m.lang) != "comment" {
+			args = append(args, child)
+		}
+	}
+	return args
+}
+
+// stringValue returns the value of a plain string literal.
+func (m *matcher) stringValue(node *gotreesitter.Node) (string, bool) {
+	if node == nil {
+		return "", false
+	}
+	text := m.text(node)
+	switch node.Type(m.lang) {
+	case "interpreted_string_literal":
+		value, err := strconv.Unquote(text)
+		return value, err == nil
+	case "raw_string_literal", "string_literal", "string":
+		unprefixed := strings.TrimLeft(text, "rRbBuUfF")
+		if strings.ContainsAny(text[:len(text)-len(unprefixed)], "fF") {
+			return "", false // a Python f-string
+		}
+		text = unprefixed
+		for _, quote := range []string{`"""`, `'''`, `"`, `'`, "`"} {
+			if len(text) >= 2*len(quote) && strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) {
+				return text[len(quote) : len(text)-len(quote)], true
+			}
+		}
+	}
+	return "", false
+}
+
+// literal returns the text of a literal value used as a default, or "".
+func (m *matcher) literal(node *gotreesitter.Node) string {
+	if value, ok := m.stringValue(node); ok {
+		return value
+	}
+	switch node.Type(m.lang) {
+	case "int_literal", "float_literal", "number", "integer", "float", "true", "false",
+		"decimal_integer_literal", "decimal_floating_point_literal":
+		return m.text(node)
+	}
+	return ""
+}
+
+func (m *matcher) importsPath(part string) bool {
+	for _, imp := range m.file.Imports {
+		if strings.Contains(imp, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func goReads(m *matcher, captures map[string]*gotreesitter.Node) []found {
+	if tag := captures["tag"]; tag != nil {
+		return goTagReads(m.text(tag))
+	}
+
+	object := m.text(captures["object"])
+	verb := m.text(captures["verb"])
+	args := m.arguments(captures["args"])
+	if len(args) == 0 {
+		return nil
+	}
+	name, ok := m.stringValue(args[0])
+	if !ok {
+		return nil
+	}
+	source := object + "." + verb
+
+	switch {
+	case verb == "Getenv" || verb == "LookupEnv":
+		if object != "os" && object != "syscall" {
+			return nil
+		}
+		return []found{{name: name, kind: KindEnv, source: source}}
+	case object != "viper" && !(m.importsPath("spf13/viper") && verb != "Get"):
+		// Without the viper package in sight, Get-style calls are not config.
+		return nil
+	case verb == "SetDefault":
+		if len(args) < 2 {
+			return nil
+		}
+		return []found{{name: name, kind: KindConfig, value: m.literal(args[1]), defaultOnly: true}}
+	case verb == "BindEnv":
+		// BindEnv("key", "ENV_NAME") reads ENV_NAME; with one argument the
+		// variable is the upper-cased key.
+		env := strings.ToUpper(strings.ReplaceAll(name, ".", "_"))
+		if len(args) > 1 {
+			if value, ok := m.stringValue(args[1]); ok {
+				env = value
+			}
+		}
+		return []found{{name: env, kind: KindEnv, source: source}}
+	}
+	return []found{{name: name, kind: KindConfig, source: "viper." + verb}}
+}
+
+// goTagReads returns the env vars named by env and envconfig struct tags,
+// with an envDefault or default tag as their default.
+func goTagReads(raw string) []found {
+	tag := reflect.StructTag(strings.Trim(raw, "`"))
+	var reads []found
+	for _, key := range []string{"env", "envconfig"} {
+		value, ok := tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name := strings.Split(value, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		def, ok := tag.Lookup("envDefault")
+		if !ok {
+			def = tag.Get("default")
+		}
+		reads = append(reads, found{name: name, kind: KindEnv, source: key + " tag", value: def})
+	}
+	return reads
+}
+
+func jsReads(m *matcher, captures map[string]*gotreesitter.Node) []found {
+	object := m.text(captures["object"])
+	if object != "process.env" && object != "import.meta.env" {
+		return nil
+	}
+
+	if pattern := captures["pattern"]; pattern != nil {
+		// const { PORT, TTL = "60" } = process.env
+		var reads []found
+		for _, property := range m.arguments(pattern) {
+			switch property.Type(m.lang) {
+			case "shorthand_property_identifier_pattern":
+				reads = append(reads, found{name: m.text(property), kind: KindEnv, source: object})
+			case "object_assignment_pattern":
+				left := property.ChildByFieldName("left", m.lang)
+				reads = append(reads, found{
+					name:   m.text(left),
+					kind:   KindEnv,
+					source: object,
+					value:  m.literal(property.ChildByFieldName("right", m.lang)),
+				})
+			case "pair_pattern":
+				if key := property.ChildByFieldName("key", m.lang); key != nil {
+					reads = append(reads, found{name: strings.Trim(m.text(key), `"'`), kind: KindEnv, source: object})
+				}
+			}
+		}
+		return reads
+	}
+
+	key := captures["key"]
+	name := m.text(key)
+	if key.Type(m.lang) == "string" {
+		value, ok := m.stringValue(key)
+		if !ok {
+			return nil
+		}
+		name = value
+	}
+
+	// process.env.PORT || "3000" and process.env.PORT ?? "3000".
+	value := ""
+	read := captures["read"]
+	if parent := read.Parent(); parent != nil && parent.Type(m.lang) == "binary_expression" {
+		operator := parent.ChildByFieldName("operator", m.lang)
+		left := parent.ChildByFieldName("left", m.lang)
+		if operator != nil && (m.text(operator) == "||" || m.text(operator) == "??") &&
+			left != nil && left.StartByte() == read.StartByte() {
+			value = m.literal(parent.ChildByFieldName("right", m.lang))
+		}
+	}
+	return []found{{name: name, kind: KindEnv, source: object, value: value}}
+}
+
+func pythonReads(m *matcher, captures map[string]*gotreesitter.Node) []found {
+	object := m.text(captures["object"])
+	if key := captures["key"]; key != nil {
+		// os.environ["HOME"]
+		if object != "os.environ" && object != "environ" {
+			return nil
+		}
+		name, ok := m.stringValue(key)
+		if !ok {
+			return nil
+		}
+		return []found{{name: name, kind: KindEnv, source: object}}
+	}
+
+	verb := m.text(captures["verb"])
+	switch {
+	case verb == "getenv" && object == "os":
+	case (verb == "get" || verb == "setdefault") && (object == "os.environ" || object == "environ"):
+	default:
+		return nil
+	}
+	args := m.arguments(captures["args"])
+	if len(args) == 0 {
+		return nil
+	}
+	name, ok := m.stringValue(args[0])
+	if !ok {
+		return nil
+	}
+	value := ""
+	if len(args) > 1 {
+		value = m.literal(args[1])
+	}
+	return []found{{name: name, kind: KindEnv, source: object + "." + verb, value: value}}
+}
+
+func javaReads(m *matcher, captures map[string]*gotreesitter.Node) []found {
+	verb := m.text(captures["verb"])
+	args := m.arguments(captures["args"])
+	if len(args) == 0 {
+		return nil
+	}
+
+	if verb == "Value" {
+		// @Value("${server.port:8080}")
+		raw, ok := m.stringValue(args[0])
+		if !ok {
+			return nil
+		}
+		var reads []found
+		for {
+			start := strings.Index(raw, "${")
+			if start < 0 {
+				break
+			}
+			end := strings.Index(raw[start:], "}")
+			if end < 0 {
+				break
+			}
+			name, value, _ := strings.Cut(raw[start+2:start+end], ":")
+			reads = append(reads, found{name: name, kind: KindConfig, source: "@Value", value: value})
+			raw = raw[start+end+1:]
+		}
+		return reads
+	}
+
+	name, ok := m.stringValue(args[0])
+	if !ok {
+		return nil
+	}
+	object := m.text(captures["object"])
+	if verb == "getenv" {
+		if object != "System" {
+			return nil
+		}
+		return []found{{name: name, kind: KindEnv, source: "System.getenv"}}
+	}
+	value := ""
+	if len(args) > 1 {
+		value = m.literal(args[1])
+	}
+	return []found{{name: name, kind: KindConfig, source: object + "." + verb, value: value}}
+}
+
+func rustReads(m *matcher, captures map[string]*gotreesitter.Node) []found {
+	verb := m.text(captures["verb"])
+	var key *gotreesitter.Node
+	if captures["object"] != nil {
+		path := m.text(captures["object"])
+		if path != "env::"+verb && !strings.HasSuffix(path, "::env::"+verb) {
+			return nil
+		}
+		args := m.arguments(captures["args"])
+		if len(args) > 0 {
+			key = args[0]
+		}
+	} else {
+		// env!("NAME") and option_env!("NAME") read at compile time.
+		for _, token := range m.arguments(captures["args"]) {
+			if token.Type(m.lang) == "string_literal" {
+				key = token
+				break
+			}
+		}
+	}
+	name, ok := m.stringValue(key)
+	if !ok {
+		return nil
+	}
+	source := m.text(captures["object"])
+	if source == "" {
+		source = verb + "!"
+	}
+	return []found{{name: name, kind: KindEnv, source: source}}
+}
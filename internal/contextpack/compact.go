@@ -0,0 +1,209 @@
+package contextpack
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Related symbols are packed in tiers. Every symbol costs at least its name;
+// the remaining budget then upgrades symbols, in priority order, to an
+// elided signature and finally to the full signature.
+const (
+	tierName = iota
+	tierElided
+	tierFull
+)
+
+// packRelated trims symbols to the budget. Without compact it keeps full
+// signatures and stops at the first symbol that does not fit. With compact
+// it fits as many distinct symbols as possible, falling back to shorter
+// signature tiers for the lower-priority ones.
+func packRelated(symbols []model.Symbol, budget int, compact bool) []model.Symbol {
+	if budget <= 0 || len(symbols) == 0 {
+		return nil
+	}
+
+	if !compact {
+		trimmed := make([]model.Symbol, 0, len(symbols))
+		used := 0
+		for _, symbol := range symbols {
+			cost := relatedCost(symbol, tierFull)
+			if used+cost > budget {
+				break
+			}
+			trimmed = append(trimmed, symbol)
+			used += cost
+		}
+		return trimmed
+	}
+
+	tiers := make([]int, 0, len(symbols))
+	used := 0
+	for _, symbol := range symbols {
+		cost := relatedCost(symbol, tierName)
+		if used+cost > budget {
+			break
+		}
+		tiers = append(tiers, tierName)
+		used += cost
+	}
+	for _, tier := range []int{tierElided, tierFull} {
+		for i := range tiers {
+			extra := relatedCost(symbols[i], tier) - relatedCost(symbols[i], tiers[i])
+			if used+extra > budget {
+				continue
+			}
+			tiers[i] = tier
+			used += extra
+		}
+	}
+
+	packed := make([]model.Symbol, len(tiers))
+	for i, tier := range tiers {
+		packed[i] = symbols[i]
+		packed[i].Signature = signatureForTier(symbols[i], tier)
+	}
+	return packed
+}
+
+func relatedCost(symbol model.Symbol, tier int) int {
+	return estimateTokens(signatureForTier(symbol, tier)) + estimateTokens(symbol.Name) + 4
+}
+
+func signatureForTier(symbol model.Symbol, tier int) string {
+	switch tier {
+	case tierFull:
+		return symbol.Signature
+	case tierElided:
+		return elideSignature(symbol.Signature, symbol.Name)
+	default:
+		return ""
+	}
+}
+
+// elideSignature replaces the parameter list following name with "...",
+// keeping the receiver and result types.
+func elideSignature(signature, name string) string {
+	offset := 0
+	if name != "" {
+		if at := strings.Index(signature, name); at >= 0 {
+			offset = at + len(name)
+		}
+	}
+	open := strings.IndexByte(signature[offset:], '(')
+	if open < 0 {
+		return signature
+	}
+	open += offset
+
+	depth := 0
+	for i := open; i < len(signature); i++ {
+		switch signature[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				if i == open+1 {
+					return signature
+				}
+				return signature[:open+1] + "..." + signature[i:]
+			}
+		}
+	}
+	return signature
+}
+
+// foldImports drops duplicate imports and folds sibling paths into one
+// brace group, e.g. "pkg/{model,xref}".
+func foldImports(imports []string) []string {
+	if len(imports) == 0 {
+		return nil
+	}
+
+	byDir := map[string][]string{}
+	seen := map[string]bool{}
+	for _, imp := range imports {
+		imp = strings.TrimSpace(imp)
+		if imp == "" || seen[imp] {
+			continue
+		}
+		seen[imp] = true
+		dir, base := path.Split(imp)
+		byDir[dir] = append(byDir[dir], base)
+	}
+
+	folded := make([]string, 0, len(byDir))
+	for dir, bases := range byDir {
+		sort.Strings(bases)
+		if dir == "" || len(bases) == 1 {
+			for _, base := range bases {
+				folded = append(folded, dir+base)
+			}
+			continue
+		}
+		folded = append(folded, fmt.Sprintf("%s{%s}", dir, strings.Join(bases, ",")))
+	}
+	sort.Strings(folded)
+	return folded
+}
+
+// renderCompactSnippet renders like renderSnippet but skips blank lines and
+// lines that hold only a comment. Line numbers are kept so the remaining
+// lines can still be located in the file.
+func renderCompactSnippet(lines []string, start, end int) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	start = clampLine(start, len(lines))
+	end = clampLine(end, len(lines))
+	if end < start {
+		end = start
+	}
+
+	width := len(fmt.Sprintf("%d", end))
+	inBlock := false
+	var builder strings.Builder
+	for i := start; i <= end; i++ {
+		var skip bool
+		skip, inBlock = commentOnlyLine(lines[i-1], inBlock)
+		if skip {
+			continue
+		}
+		fmt.Fprintf(&builder, "%*d | %s\n", width, i, lines[i-1])
+	}
+	return builder.String()
+}
+
+// commentOnlyLine reports whether line is blank or only a comment, and
+// whether a /* */ block comment is still open after it.
+func commentOnlyLine(line string, inBlock bool) (bool, bool) {
+	trimmed := strings.TrimSpace(line)
+	if inBlock {
+		end := strings.Index(trimmed, "*/")
+		if end < 0 {
+			return true, true
+		}
+		return strings.TrimSpace(trimmed[end+2:]) == "", false
+	}
+
+	switch {
+	case trimmed == "":
+		return true, false
+	case strings.HasPrefix(trimmed, "//"):
+		return true, false
+	case trimmed == "#" || strings.HasPrefix(trimmed, "# ") || strings.HasPrefix(trimmed, "#!"):
+		return true, false
+	case strings.HasPrefix(trimmed, "/*"):
+		end := strings.Index(trimmed[2:], "*/")
+		if end < 0 {
+			return true, true
+		}
+		return strings.TrimSpace(trimmed[2+end+2:]) == "", false
+	}
+	return false, false
+}
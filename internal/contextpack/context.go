@@ -18,22 +18,44 @@ type Options struct {
 	TokenBudget   int
 	Semantic      bool
 	SemanticDepth int
+	// Callers is the maximum number of caller snippets of the focus symbol
+	// to include, found through the reverse call graph.
+	Callers int
+	// Compact strips comment-only and blank lines from snippets, folds
+	// duplicate imports, and packs related symbols in signature tiers so
+	// more of them fit in the budget.
+	Compact bool
+}
+
+// CallerSnippet shows one call site of the focus symbol inside its caller.
+type CallerSnippet struct {
+	File         string `json:"file"`
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	Signature    string `json:"signature,omitempty"`
+	CallLine     int    `json:"call_line"`
+	Calls        int    `json:"calls"`
+	SnippetStart int    `json:"snippet_start"`
+	SnippetEnd   int    `json:"snippet_end"`
+	Snippet      string `json:"snippet"`
 }
 
 type Report struct {
-	File            string         `json:"file"`
-	Line            int            `json:"line"`
-	TokenBudget     int            `json:"token_budget"`
-	Semantic        bool           `json:"semantic"`
-	SemanticDepth   int            `json:"semantic_depth,omitempty"`
-	EstimatedTokens int            `json:"estimated_tokens"`
-	Focus           *model.Symbol  `json:"focus,omitempty"`
-	Imports         []string       `json:"imports,omitempty"`
-	SnippetStart    int            `json:"snippet_start"`
-	SnippetEnd      int            `json:"snippet_end"`
-	Snippet         string         `json:"snippet"`
-	Related         []model.Symbol `json:"related,omitempty"`
-	Truncated       bool           `json:"truncated"`
+	File            string          `json:"file"`
+	Line            int             `json:"line"`
+	TokenBudget     int             `json:"token_budget"`
+	Semantic        bool            `json:"semantic"`
+	SemanticDepth   int             `json:"semantic_depth,omitempty"`
+	Compact         bool            `json:"compact,omitempty"`
+	EstimatedTokens int             `json:"estimated_tokens"`
+	Focus           *model.Symbol   `json:"focus,omitempty"`
+	Imports         []string        `json:"imports,omitempty"`
+	SnippetStart    int             `json:"snippet_start"`
+	SnippetEnd      int             `json:"snippet_end"`
+	Snippet         string          `json:"snippet"`
+	Callers         []CallerSnippet `json:"callers,omitempty"`
+	Related         []model.Symbol  `json:"related,omitempty"`
+	Truncated       bool            `json:"truncated"`
 }
 
 func Build(idx *model.Index, opts Options) (Report, error) {
@@ -78,8 +100,14 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 		TokenBudget:   opts.TokenBudget,
 		Semantic:      opts.Semantic,
 		SemanticDepth: opts.SemanticDepth,
+		Compact:       opts.Compact,
 		Imports:       append([]string(nil), fileSummary.Imports...),
 	}
+	render := renderSnippet
+	if opts.Compact {
+		report.Imports = foldImports(report.Imports)
+		render = renderCompactSnippet
+	}
 
 	focus := findFocusSymbol(fileSummary.Symbols, opts.Line)
 	if focus != nil {
@@ -88,13 +116,13 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 	}
 
 	start, end := initialSnippetBounds(report.Focus, opts.Line, len(lines))
-	snippet := renderSnippet(lines, start, end)
+	snippet := render(lines, start, end)
 
 	baseTokens := estimateTokens(renderMetadata(report))
 	snippetTokens := estimateTokens(snippet)
 	for start < end && baseTokens+snippetTokens > opts.TokenBudget {
 		start, end = shrinkWindow(start, end, opts.Line)
-		snippet = renderSnippet(lines, start, end)
+		snippet = render(lines, start, end)
 		snippetTokens = estimateTokens(snippet)
 		report.Truncated = true
 	}
@@ -104,14 +132,28 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 	report.Snippet = snippet
 
 	remaining := opts.TokenBudget - (baseTokens + snippetTokens)
+	var graph *xref.Graph
+	if report.Focus != nil && (opts.Semantic || opts.Callers > 0) {
+		if built, err := xref.Build(idx); err == nil {
+			graph = &built
+		}
+	}
+	if opts.Callers > 0 {
+		var dropped bool
+		report.Callers, dropped = pickCallerSnippets(idx, graph, fileSummary, report.Focus, remaining, opts.Callers, render)
+		if dropped {
+			report.Truncated = true
+		}
+		remaining -= estimateTokens(renderCallers(report.Callers))
+	}
 	if opts.Semantic {
-		report.Related = pickSemanticRelatedSymbols(idx, fileSummary, report.Focus, remaining, opts.SemanticDepth)
+		report.Related = pickSemanticRelatedSymbols(graph, fileSummary, report.Focus, remaining, opts.SemanticDepth, opts.Compact)
 	}
 	if len(report.Related) == 0 {
-		report.Related = pickRelatedSymbols(fileSummary.Symbols, report.Focus, remaining)
+		report.Related = pickRelatedSymbols(fileSummary.Symbols, report.Focus, remaining, opts.Compact)
 	}
 
-	report.EstimatedTokens = estimateTokens(renderMetadata(report) + snippet + renderRelated(report.Related))
+	report.EstimatedTokens = estimateTokens(renderMetadata(report) + snippet + renderCallers(report.Callers) + renderRelated(report.Related))
 	if report.EstimatedTokens > opts.TokenBudget {
 		report.Truncated = true
 	}
@@ -230,7 +272,7 @@ func shrinkWindow(start, end, focusLine int) (int, int) {
 	return start, end - 1
 }
 
-func pickRelatedSymbols(symbols []model.Symbol, focus *model.Symbol, budget int) []model.Symbol {
+func pickRelatedSymbols(symbols []model.Symbol, focus *model.Symbol, budget int, compact bool) []model.Symbol {
 	if budget <= 0 {
 		return nil
 	}
@@ -253,46 +295,18 @@ func pickRelatedSymbols(symbols []model.Symbol, focus *model.Symbol, budget int)
 		return related[i].StartLine < related[j].StartLine
 	})
 
-	trimmed := make([]model.Symbol, 0, len(related))
-	used := 0
-	for _, symbol := range related {
-		cost := estimateTokens(symbol.Signature) + estimateTokens(symbol.Name) + 4
-		if used+cost > budget {
-			break
-		}
-		trimmed = append(trimmed, symbol)
-		used += cost
-	}
-	return trimmed
+	return packRelated(related, budget, compact)
 }
 
-func pickSemanticRelatedSymbols(idx *model.Index, fileSummary model.FileSummary, focus *model.Symbol, budget int, depth int) []model.Symbol {
-	if idx == nil || focus == nil || budget <= 0 {
+func pickSemanticRelatedSymbols(graph *xref.Graph, fileSummary model.FileSummary, focus *model.Symbol, budget int, depth int, compact bool) []model.Symbol {
+	if graph == nil || focus == nil || budget <= 0 {
 		return nil
 	}
 	if depth <= 0 {
 		depth = 1
 	}
 
-	graph, err := xref.Build(idx)
-	if err != nil {
-		return nil
-	}
-
-	focusID := ""
-	for _, definition := range graph.Definitions {
-		if definition.File != fileSummary.Path {
-			continue
-		}
-		if definition.Kind != focus.Kind || definition.Name != focus.Name {
-			continue
-		}
-		if definition.StartLine != focus.StartLine {
-			continue
-		}
-		focusID = definition.ID
-		break
-	}
+	focusID := focusDefinitionID(graph, fileSummary.Path, focus)
 	if focusID == "" {
 		return nil
 	}
@@ -381,17 +395,96 @@ func pickSemanticRelatedSymbols(idx *model.Index, fileSummary model.FileSummary,
 		return scored[i].depth < scored[j].depth
 	})
 
-	trimmed := make([]model.Symbol, 0, len(scored))
-	used := 0
+	symbols := make([]model.Symbol, 0, len(scored))
 	for _, item := range scored {
-		cost := estimateTokens(item.symbol.Signature) + estimateTokens(item.symbol.Name) + 4
-		if used+cost > budget {
+		symbols = append(symbols, item.symbol)
+	}
+	return packRelated(symbols, budget, compact)
+}
+
+func focusDefinitionID(graph *xref.Graph, path string, focus *model.Symbol) string {
+	for _, definition := range graph.Definitions {
+		if definition.File != path {
+			continue
+		}
+		if definition.Kind != focus.Kind || definition.Name != focus.Name {
+			continue
+		}
+		if definition.StartLine != focus.StartLine {
+			continue
+		}
+		return definition.ID
+	}
+	return ""
+}
+
+// callerContextLines is how many lines around a call site a caller snippet
+// shows on each side, clamped to the caller's own span.
+const callerContextLines = 2
+
+// pickCallerSnippets returns up to limit call sites of focus, most frequent
+// callers first, each rendered as a few lines around the first call. The
+// second result reports whether callers were left out for lack of budget.
+func pickCallerSnippets(idx *model.Index, graph *xref.Graph, fileSummary model.FileSummary, focus *model.Symbol, budget, limit int, render func([]string, int, int) string) ([]CallerSnippet, bool) {
+	if graph == nil || focus == nil || limit <= 0 {
+		return nil, false
+	}
+	focusID := focusDefinitionID(graph, fileSummary.Path, focus)
+	if focusID == "" {
+		return nil, false
+	}
+
+	edges := graph.IncomingEdges(focusID)
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].Count > edges[j].Count
+	})
+
+	linesByFile := map[string][]string{}
+	callers := make([]CallerSnippet, 0, limit)
+	used := 0
+	for _, edge := range edges {
+		if len(callers) >= limit {
 			break
 		}
-		trimmed = append(trimmed, item.symbol)
+		caller := graph.EdgeCaller(edge)
+		if caller.ID == focusID || len(edge.Samples) == 0 {
+			continue
+		}
+		lines, ok := linesByFile[caller.File]
+		if !ok {
+			source, err := os.ReadFile(filepath.Join(idx.Root, filepath.FromSlash(caller.File)))
+			if err == nil {
+				lines = splitLines(string(source))
+			}
+			linesByFile[caller.File] = lines
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		callLine := edge.Samples[0].StartLine
+		start := max(callLine-callerContextLines, caller.StartLine)
+		end := min(callLine+callerContextLines, caller.EndLine)
+		snippet := CallerSnippet{
+			File:         caller.File,
+			Kind:         caller.Kind,
+			Name:         caller.Name,
+			Signature:    caller.Signature,
+			CallLine:     callLine,
+			Calls:        edge.Count,
+			SnippetStart: clampLine(start, len(lines)),
+			SnippetEnd:   clampLine(end, len(lines)),
+		}
+		snippet.Snippet = render(lines, snippet.SnippetStart, snippet.SnippetEnd)
+
+		cost := estimateTokens(snippet.Signature) + estimateTokens(snippet.Snippet) + 4
+		if used+cost > budget {
+			return callers, true
+		}
+		callers = append(callers, snippet)
 		used += cost
 	}
-	return trimmed
+	return callers, false
 }
 
 func estimateTokens(text string) int {
@@ -415,12 +508,30 @@ func renderMetadata(report Report) string {
 	return builder.String()
 }
 
+func renderCallers(callers []CallerSnippet) string {
+	if len(callers) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	for _, caller := range callers {
+		builder.WriteString(caller.Signature)
+		builder.WriteByte('\n')
+		builder.WriteString(caller.Snippet)
+	}
+	return builder.String()
+}
+
 func renderRelated(symbols []model.Symbol) string {
 	if len(symbols) == 0 {
 		return ""
 	}
 	var builder strings.Builder
 	for _, symbol := range symbols {
+		if symbol.Signature == "" {
+			builder.WriteString(symbol.Name)
+			builder.WriteByte('\n')
+			continue
+		}
 		builder.WriteString(symbol.Signature)
 		builder.WriteByte('\n')
 	}
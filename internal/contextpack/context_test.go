@@ -3,6 +3,7 @@ package contextpack
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/odvcencio/gts-suite/pkg/model"
@@ -270,3 +271,183 @@ func work() {
 		t.Fatalf("expected depth=2 related to include mid and leaf, got %+v", depthTwo.Related)
 	}
 }
+
+func TestBuild_CallersIncludesCallSiteSnippets(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "sample.go")
+	source := `package sample
+
+func helper() {}
+
+func work() {
+	setup()
+	helper()
+	teardown()
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	idx := &model.Index{
+		Root: tmpDir,
+		Files: []model.FileSummary{
+			{
+				Path: "sample.go",
+				Symbols: []model.Symbol{
+					{
+						File:      "sample.go",
+						Kind:      "function_definition",
+						Name:      "helper",
+						Signature: "func helper()",
+						StartLine: 3,
+						EndLine:   3,
+					},
+					{
+						File:      "sample.go",
+						Kind:      "function_definition",
+						Name:      "work",
+						Signature: "func work()",
+						StartLine: 5,
+						EndLine:   9,
+					},
+				},
+				References: []model.Reference{
+					{
+						File:        "sample.go",
+						Kind:        "reference.call",
+						Name:        "helper",
+						StartLine:   7,
+						EndLine:     7,
+						StartColumn: 2,
+						EndColumn:   8,
+					},
+				},
+			},
+		},
+	}
+
+	report, err := Build(idx, Options{
+		FilePath:    sourcePath,
+		Line:        3,
+		TokenBudget: 400,
+		Callers:     2,
+	})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if report.Focus == nil || report.Focus.Name != "helper" {
+		t.Fatalf("expected focus symbol helper, got %#v", report.Focus)
+	}
+	if len(report.Callers) != 1 {
+		t.Fatalf("expected one caller, got %+v", report.Callers)
+	}
+	caller := report.Callers[0]
+	if caller.Name != "work" || caller.CallLine != 7 {
+		t.Fatalf("unexpected caller %+v", caller)
+	}
+	if caller.SnippetStart != 5 || caller.SnippetEnd != 9 {
+		t.Fatalf("expected snippet [5:9], got [%d:%d]", caller.SnippetStart, caller.SnippetEnd)
+	}
+	if !strings.Contains(caller.Snippet, "7 | \thelper()") {
+		t.Fatalf("expected call line in snippet, got %q", caller.Snippet)
+	}
+}
+
+func TestBuild_CompactStripsCommentsAndFoldsImports(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "sample.go")
+	source := `package sample
+
+// Work does the work.
+func Work() {
+	// first step
+	step()
+
+	/* second
+	   step */
+	step()
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	idx := &model.Index{
+		Root: tmpDir,
+		Files: []model.FileSummary{
+			{
+				Path:    "sample.go",
+				Imports: []string{"fmt", "example.com/app/pkg/model", "example.com/app/pkg/xref", "fmt"},
+				Symbols: []model.Symbol{
+					{
+						File:      "sample.go",
+						Kind:      "function_definition",
+						Name:      "Work",
+						Signature: "func Work()",
+						StartLine: 4,
+						EndLine:   11,
+					},
+				},
+			},
+		},
+	}
+
+	report, err := Build(idx, Options{
+		FilePath:    sourcePath,
+		Line:        6,
+		TokenBudget: 400,
+		Compact:     true,
+	})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	want := " 4 | func Work() {\n 6 | \tstep()\n10 | \tstep()\n11 | }\n"
+	if report.Snippet != want {
+		t.Fatalf("unexpected compact snippet:\n%s", report.Snippet)
+	}
+	if got := strings.Join(report.Imports, " "); got != "example.com/app/pkg/{model,xref} fmt" {
+		t.Fatalf("unexpected folded imports %q", got)
+	}
+}
+
+func TestPackRelated_CompactFitsMoreSymbols(t *testing.T) {
+	symbols := []model.Symbol{
+		{Kind: "function_definition", Name: "alpha", Signature: "func alpha(ctx context.Context, input string, opts Options) error"},
+		{Kind: "function_definition", Name: "beta", Signature: "func beta(ctx context.Context, input string, opts Options) error"},
+		{Kind: "function_definition", Name: "gamma", Signature: "func gamma(ctx context.Context, input string, opts Options) error"},
+	}
+
+	budget := relatedCost(symbols[0], tierFull) + 2*relatedCost(symbols[1], tierName)
+	if got := packRelated(symbols, budget, false); len(got) != 1 {
+		t.Fatalf("expected one full signature without compact, got %d", len(got))
+	}
+
+	packed := packRelated(symbols, budget, true)
+	if len(packed) != 3 {
+		t.Fatalf("expected all three symbols with compact, got %+v", packed)
+	}
+	if packed[0].Signature != "func alpha(...) error" {
+		t.Fatalf("expected highest priority symbol to keep an elided signature, got %q", packed[0].Signature)
+	}
+	if packed[2].Signature != "" {
+		t.Fatalf("expected lowest priority symbol to fall back to its name, got %q", packed[2].Signature)
+	}
+}
+
+func TestElideSignature(t *testing.T) {
+	cases := []struct {
+		signature string
+		name      string
+		want      string
+	}{
+		{"func (s *Service) Work(a int, b string) (int, error)", "Work", "func (s *Service) Work(...) (int, error)"},
+		{"func Empty()", "Empty", "func Empty()"},
+		{"type Service struct{}", "Service", "type Service struct{}"},
+	}
+	for _, tc := range cases {
+		if got := elideSignature(tc.signature, tc.name); got != tc.want {
+			t.Fatalf("elideSignature(%q) = %q, want %q", tc.signature, got, tc.want)
+		}
+	}
+}
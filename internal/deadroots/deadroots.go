@@ -0,0 +1,154 @@
+// Package deadroots finds the definitions dead-code analysis must treat as
+// used although no call reaches them: Python names reached through getattr,
+// exported by __all__, or handed to a framework by a registration call such
+// as Django's path(), and functions whose decorator registers them with a
+// framework, such as Flask routes and Celery tasks.
+package deadroots
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// DefaultDecorators match the decorators of Python framework entry points,
+// as written on the line above the definition.
+var DefaultDecorators = []string{
+	// Flask, FastAPI, and Starlette routes and hooks.
+	`^@[\w.]+\.(route|get|post|put|patch|delete|head|options|api_route|websocket)\(`,
+	`^@[\w.]+\.(before_request|after_request|before_app_request|teardown_request|teardown_appcontext|errorhandler|context_processor|template_filter|on_event|exception_handler|middleware)\b`,
+	// Celery tasks.
+	`^@([\w.]+\.)?(task|shared_task|periodic_task)\b`,
+	// Django signals, admin, and template tags, and Django REST framework views.
+	`^@(receiver|api_view|action|admin\.register|register\.(filter|simple_tag|inclusion_tag|tag))\b`,
+	// Click commands and pytest fixtures.
+	`^@([\w.]+\.)?(command|group)\b`,
+	`^@pytest\.fixture\b`,
+}
+
+// Options configures Find.
+type Options struct {
+	// Decorators are regular expressions matched against each decorator of
+	// a definition; a match makes it a root. Callers usually pass
+	// DefaultDecorators followed by a project's own.
+	Decorators []string
+}
+
+// Roots are the dynamically used definitions of one index. A nil *Roots
+// has none.
+type Roots struct {
+	reasons map[string]string
+}
+
+// Find returns the dynamically used definitions of idx.
+func Find(idx *model.Index, opts Options) (*Roots, error) {
+	decorators := make([]*regexp.Regexp, 0, len(opts.Decorators))
+	for _, pattern := range opts.Decorators {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile decorator pattern %q: %w", pattern, err)
+		}
+		decorators = append(decorators, compiled)
+	}
+
+	// Names reached through getattr or registration calls may belong to any
+	// Python module; names in __all__ belong to the module declaring it.
+	named := map[string]string{}
+	var prefixes []string
+	exported := map[string]map[string]bool{}
+	for _, file := range idx.Files {
+		if file.Language != "python" {
+			continue
+		}
+		for _, ref := range file.References {
+			if ref.Kind != "reference.dynamic" {
+				continue
+			}
+			switch {
+			case ref.Qualifier == "__all__":
+				if exported[file.Path] == nil {
+					exported[file.Path] = map[string]bool{}
+				}
+				exported[file.Path][ref.Name] = true
+			case strings.HasSuffix(ref.Name, "*"):
+				prefixes = append(prefixes, strings.TrimSuffix(ref.Name, "*"))
+			default:
+				if _, ok := named[ref.Name]; !ok {
+					named[ref.Name] = ref.Qualifier
+				}
+			}
+		}
+	}
+
+	roots := &Roots{reasons: map[string]string{}}
+	for _, file := range idx.Files {
+		for _, symbol := range file.Symbols {
+			if reason := decoratorReason(symbol, decorators); reason != "" {
+				roots.reasons[key(file.Path, symbol.Name, symbol.StartLine)] = reason
+				continue
+			}
+			if file.Language != "python" {
+				continue
+			}
+			if reason := nameReason(file.Path, symbol.Name, named, prefixes, exported); reason != "" {
+				roots.reasons[key(file.Path, symbol.Name, symbol.StartLine)] = reason
+			}
+		}
+	}
+	return roots, nil
+}
+
+// Reason returns why the definition of name at line of file is used, such
+// as "__all__", "getattr", "path()", or the decorator registering it, and
+// whether it is.
+func (r *Roots) Reason(file, name string, line int) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	reason, ok := r.reasons[key(file, name, line)]
+	return reason, ok
+}
+
+// Len returns the number of dynamically used definitions.
+func (r *Roots) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.reasons)
+}
+
+func decoratorReason(symbol model.Symbol, decorators []*regexp.Regexp) string {
+	for _, attribute := range symbol.Attributes {
+		for _, decorator := range decorators {
+			if decorator.MatchString(attribute) {
+				return attribute
+			}
+		}
+	}
+	return ""
+}
+
+func nameReason(file, name string, named map[string]string, prefixes []string, exported map[string]map[string]bool) string {
+	if exported[file][name] {
+		return "__all__"
+	}
+	if via, ok := named[name]; ok {
+		if via == "getattr" {
+			return via
+		}
+		return via + "()"
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return "getattr"
+		}
+	}
+	return ""
+}
+
+func key(file, name string, line int) string {
+	return file + "\x00" + name + "\x00" + strconv.Itoa(line)
+}
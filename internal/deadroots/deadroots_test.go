@@ -0,0 +1,88 @@
+package deadroots
+
+import (
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func TestFind(t *testing.T) {
+	idx := &model.Index{
+		Root: "/tmp/repo",
+		Files: []model.FileSummary{
+			{
+				Path:     "app/views.py",
+				Language: "python",
+				Symbols: []model.Symbol{
+					{Kind: "function_definition", Name: "index", StartLine: 2, Attributes: []string{`@app.route("/")`}},
+					{Kind: "function_definition", Name: "send_email", StartLine: 6, Attributes: []string{"@shared_task"}},
+					{Kind: "function_definition", Name: "public_helper", StartLine: 9},
+					{Kind: "function_definition", Name: "handle_create", StartLine: 12},
+					{Kind: "function_definition", Name: "about", StartLine: 15},
+					{Kind: "function_definition", Name: "plugin", StartLine: 19, Attributes: []string{"@plugins.register"}},
+					{Kind: "function_definition", Name: "really_dead", StartLine: 22},
+				},
+				References: []model.Reference{
+					{Kind: "reference.dynamic", Name: "public_helper", Qualifier: "__all__"},
+					{Kind: "reference.dynamic", Name: "handle_*", Qualifier: "getattr"},
+				},
+			},
+			{
+				Path:     "app/urls.py",
+				Language: "python",
+				Symbols: []model.Symbol{
+					{Kind: "function_definition", Name: "public_helper", StartLine: 1},
+				},
+				References: []model.Reference{
+					{Kind: "reference.dynamic", Name: "about", Qualifier: "path"},
+				},
+			},
+		},
+	}
+
+	roots, err := Find(idx, Options{Decorators: append(append([]string(nil), DefaultDecorators...), `^@plugins\.`)})
+	if err != nil {
+		t.Fatalf("Find returned error: %v", err)
+	}
+	cases := []struct {
+		file   string
+		name   string
+		line   int
+		reason string
+	}{
+		{"app/views.py", "index", 2, `@app.route("/")`},
+		{"app/views.py", "send_email", 6, "@shared_task"},
+		{"app/views.py", "public_helper", 9, "__all__"},
+		{"app/views.py", "handle_create", 12, "getattr"},
+		{"app/views.py", "about", 15, "path()"},
+		{"app/views.py", "plugin", 19, "@plugins.register"},
+	}
+	for _, tc := range cases {
+		reason, ok := roots.Reason(tc.file, tc.name, tc.line)
+		if !ok || reason != tc.reason {
+			t.Errorf("Reason(%s, %s) = %q, %v; want %q", tc.file, tc.name, reason, ok, tc.reason)
+		}
+	}
+	if _, ok := roots.Reason("app/views.py", "really_dead", 22); ok {
+		t.Errorf("expected really_dead not to be a root")
+	}
+	if _, ok := roots.Reason("app/urls.py", "public_helper", 1); ok {
+		t.Errorf("expected __all__ to apply only to the module declaring it")
+	}
+	if roots.Len() != len(cases) {
+		t.Fatalf("Len() = %d, want %d", roots.Len(), len(cases))
+	}
+}
+
+func TestFindRejectsInvalidDecoratorPattern(t *testing.T) {
+	if _, err := Find(&model.Index{}, Options{Decorators: []string{"(@"}}); err == nil {
+		t.Fatal("expected an error for an invalid decorator pattern")
+	}
+}
+
+func TestNilRoots(t *testing.T) {
+	var roots *Roots
+	if _, ok := roots.Reason("a.py", "f", 1); ok || roots.Len() != 0 {
+		t.Fatal("expected nil Roots to have no roots")
+	}
+}
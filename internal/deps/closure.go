@@ -0,0 +1,90 @@
+package deps
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Closure is the reverse dependency closure of a set of packages: every
+// package in the index that imports one of them, directly or transitively,
+// and the files those packages contain.
+type Closure struct {
+	Seeds    []string `json:"seeds"`
+	Packages []string `json:"packages"`
+	Files    []string `json:"files"`
+}
+
+// ReverseClosure returns the packages of idx that depend on any of seeds,
+// including seeds that are packages of idx themselves. Seeds use the same
+// package nodes as Build, so an external import path selects every package
+// that reaches it.
+func ReverseClosure(idx *model.Index, seeds []string) (Closure, error) {
+	if idx == nil {
+		return Closure{}, fmt.Errorf("index is nil")
+	}
+	closure := Closure{Seeds: []string{}, Packages: []string{}, Files: []string{}}
+	for _, seed := range seeds {
+		if seed = normalizeFocus(seed, "package", idx.Root); seed != "" {
+			closure.Seeds = append(closure.Seeds, seed)
+		}
+	}
+	if len(closure.Seeds) == 0 {
+		return Closure{}, fmt.Errorf("at least one package is required")
+	}
+
+	modulePath := modulePathFromRoot(idx.Root)
+	importers := map[string][]string{}
+	for _, file := range idx.Files {
+		from := fromNode(file.Path, "package")
+		for _, imp := range file.Imports {
+			if imp = strings.TrimSpace(imp); imp == "" {
+				continue
+			}
+			to, _ := mapImportTarget(imp, "package", modulePath)
+			importers[to] = append(importers[to], from)
+		}
+	}
+
+	reached := map[string]bool{}
+	queue := append([]string(nil), closure.Seeds...)
+	for _, seed := range queue {
+		reached[seed] = true
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, importer := range importers[current] {
+			if !reached[importer] {
+				reached[importer] = true
+				queue = append(queue, importer)
+			}
+		}
+	}
+
+	projectNodes := collectProjectNodes(idx, "package")
+	for node := range reached {
+		if projectNodes[node] {
+			closure.Packages = append(closure.Packages, node)
+		}
+	}
+	for _, file := range idx.Files {
+		if reached[fromNode(file.Path, "package")] {
+			closure.Files = append(closure.Files, file.Path)
+		}
+	}
+	sort.Strings(closure.Packages)
+	sort.Strings(closure.Files)
+	return closure, nil
+}
+
+// BazelLabel converts a package directory to the label selecting all of its
+// targets, e.g. "internal/api" -> "//internal/api:all".
+func BazelLabel(pkg string) string {
+	if pkg == "." || pkg == "" {
+		return "//:all"
+	}
+	return "//" + strings.Trim(pkg, "/") + ":all"
+}
@@ -0,0 +1,59 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func TestReverseClosure(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/repo\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile go.mod failed: %v", err)
+	}
+	idx := &model.Index{
+		Root: tmpDir,
+		Files: []model.FileSummary{
+			{Path: "main.go", Imports: []string{"example.com/repo/internal/api"}},
+			{Path: "internal/api/api.go", Imports: []string{"example.com/repo/internal/store"}},
+			{Path: "internal/api/api_test.go", Imports: []string{"testing"}},
+			{Path: "internal/store/store.go", Imports: []string{"database/sql"}},
+			{Path: "internal/unrelated/u.go", Imports: []string{"fmt"}},
+		},
+	}
+
+	closure, err := ReverseClosure(idx, []string{"internal/store"})
+	if err != nil {
+		t.Fatalf("ReverseClosure returned error: %v", err)
+	}
+	if want := []string{".", "internal/api", "internal/store"}; !reflect.DeepEqual(closure.Packages, want) {
+		t.Fatalf("Packages = %v, want %v", closure.Packages, want)
+	}
+	if want := []string{"internal/api/api.go", "internal/api/api_test.go", "internal/store/store.go", "main.go"}; !reflect.DeepEqual(closure.Files, want) {
+		t.Fatalf("Files = %v, want %v", closure.Files, want)
+	}
+
+	// External seeds select their importers but are not packages themselves.
+	closure, err = ReverseClosure(idx, []string{"database/sql"})
+	if err != nil {
+		t.Fatalf("ReverseClosure returned error: %v", err)
+	}
+	if want := []string{".", "internal/api", "internal/store"}; !reflect.DeepEqual(closure.Packages, want) {
+		t.Fatalf("Packages = %v, want %v", closure.Packages, want)
+	}
+
+	if _, err := ReverseClosure(idx, nil); err == nil {
+		t.Fatal("expected error without seeds")
+	}
+}
+
+func TestBazelLabel(t *testing.T) {
+	for pkg, want := range map[string]string{".": "//:all", "internal/api": "//internal/api:all"} {
+		if got := BazelLabel(pkg); got != want {
+			t.Errorf("BazelLabel(%q) = %q, want %q", pkg, got, want)
+		}
+	}
+}
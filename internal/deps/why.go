@@ -0,0 +1,169 @@
+package deps
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// WhyStep is one import in a chain: a file in From whose import statement
+// at Line pulls in To. Files counts every file in From that imports To.
+type WhyStep struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	File   string `json:"file"`
+	Line   int    `json:"line,omitempty"`
+	Import string `json:"import"`
+	Files  int    `json:"files"`
+}
+
+// WhyReport explains why one package depends on another.
+type WhyReport struct {
+	From      string      `json:"from"`
+	To        string      `json:"to"`
+	Direct    bool        `json:"direct"`
+	Chains    [][]WhyStep `json:"chains"`
+	Truncated bool        `json:"truncated,omitempty"`
+}
+
+// Why finds the shortest import chains from package from to package to,
+// using the same package nodes as Build: directories relative to the index
+// root, or import paths for packages outside the module. At most limit
+// chains are returned; limit <= 0 means 10. An empty Chains means from does
+// not depend on to.
+func Why(idx *model.Index, from, to string, limit int) (WhyReport, error) {
+	if idx == nil {
+		return WhyReport{}, fmt.Errorf("index is nil")
+	}
+	from = normalizeFocus(from, "package", idx.Root)
+	to = normalizeFocus(to, "package", idx.Root)
+	if from == "" || to == "" {
+		return WhyReport{}, fmt.Errorf("both packages are required")
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	// evidence holds, per edge, the files whose imports create it.
+	modulePath := modulePathFromRoot(idx.Root)
+	evidence := map[string][]WhyStep{}
+	adjacency := map[string][]string{}
+	for _, file := range idx.Files {
+		node := fromNode(file.Path, "package")
+		seen := map[string]bool{}
+		for _, imp := range file.Imports {
+			imp = strings.TrimSpace(imp)
+			if imp == "" || seen[imp] {
+				continue
+			}
+			seen[imp] = true
+			target, _ := mapImportTarget(imp, "package", modulePath)
+			key := node + "->" + target
+			if len(evidence[key]) == 0 {
+				adjacency[node] = append(adjacency[node], target)
+			}
+			evidence[key] = append(evidence[key], WhyStep{From: node, To: target, File: file.Path, Import: imp})
+		}
+	}
+	for node := range adjacency {
+		sort.Strings(adjacency[node])
+	}
+
+	report := WhyReport{From: from, To: to, Chains: [][]WhyStep{}}
+	if from == to {
+		return report, nil
+	}
+
+	// Breadth-first distances from the target over reversed edges, so the
+	// walk below only follows edges that lie on a shortest chain.
+	reverse := map[string][]string{}
+	for node, targets := range adjacency {
+		for _, target := range targets {
+			reverse[target] = append(reverse[target], node)
+		}
+	}
+	distance := map[string]int{to: 0}
+	queue := []string{to}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, prev := range reverse[current] {
+			if _, ok := distance[prev]; !ok {
+				distance[prev] = distance[current] + 1
+				queue = append(queue, prev)
+			}
+		}
+	}
+	if _, ok := distance[from]; !ok {
+		return report, nil
+	}
+
+	var walk func(node string, path []string)
+	walk = func(node string, path []string) {
+		if report.Truncated {
+			return
+		}
+		if node == to {
+			if len(report.Chains) == limit {
+				report.Truncated = true
+				return
+			}
+			report.Chains = append(report.Chains, chainSteps(idx.Root, path, evidence))
+			return
+		}
+		for _, next := range adjacency[node] {
+			if d, ok := distance[next]; ok && d == distance[node]-1 {
+				walk(next, append(path, next))
+			}
+		}
+	}
+	walk(from, []string{from})
+	report.Direct = distance[from] == 1
+	return report, nil
+}
+
+func chainSteps(root string, path []string, evidence map[string][]WhyStep) []WhyStep {
+	steps := make([]WhyStep, 0, len(path)-1)
+	for i := 0; i+1 < len(path); i++ {
+		files := evidence[path[i]+"->"+path[i+1]]
+		sort.Slice(files, func(a, b int) bool { return files[a].File < files[b].File })
+		step := files[0]
+		step.Files = len(files)
+		step.Line = importLine(root, step.File, step.Import)
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// importLine returns the first line of file mentioning imp, preferring a
+// quoted occurrence, or 0 when the source cannot be read. Indexes do not
+// record import positions, and a textual match works for every language.
+func importLine(root, file, imp string) int {
+	handle, err := os.Open(filepath.Join(root, filepath.FromSlash(file)))
+	if err != nil {
+		return 0
+	}
+	defer handle.Close()
+
+	fallback := 0
+	scanner := bufio.NewScanner(handle)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if !strings.Contains(text, imp) {
+			continue
+		}
+		if strings.Contains(text, `"`+imp+`"`) || strings.Contains(text, `'`+imp+`'`) {
+			return line
+		}
+		if fallback == 0 {
+			fallback = line
+		}
+	}
+	return fallback
+}
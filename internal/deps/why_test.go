@@ -0,0 +1,79 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func TestWhyFindsShortestChains(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":              "module example.com/repo\n",
+		"cmd/app/main.go":     "package main\n\nimport (\n\t\"fmt\"\n\t\"example.com/repo/internal/api\"\n)\n",
+		"internal/api/a.go":   "package api\n\nimport \"example.com/repo/internal/store\"\n",
+		"internal/api/b.go":   "package api\n\nimport \"example.com/repo/internal/cache\"\n",
+		"internal/cache/c.go": "package cache\n\nimport \"example.com/repo/internal/store\"\n",
+		"internal/store/s.go": "package store\n\nimport \"database/sql\"\n",
+	}
+	for path, content := range files {
+		full := filepath.Join(tmpDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	idx := &model.Index{
+		Root: tmpDir,
+		Files: []model.FileSummary{
+			{Path: "cmd/app/main.go", Imports: []string{"fmt", "example.com/repo/internal/api"}},
+			{Path: "internal/api/a.go", Imports: []string{"example.com/repo/internal/store"}},
+			{Path: "internal/api/b.go", Imports: []string{"example.com/repo/internal/cache"}},
+			{Path: "internal/cache/c.go", Imports: []string{"example.com/repo/internal/store"}},
+			{Path: "internal/store/s.go", Imports: []string{"database/sql"}},
+		},
+	}
+
+	report, err := Why(idx, "cmd/app", "database/sql", 0)
+	if err != nil {
+		t.Fatalf("Why returned error: %v", err)
+	}
+	if report.Direct || len(report.Chains) != 1 {
+		t.Fatalf("expected one indirect chain, got %+v", report)
+	}
+	chain := report.Chains[0]
+	if len(chain) != 3 {
+		t.Fatalf("expected the 3-hop chain through internal/api and internal/store, got %+v", chain)
+	}
+	first := chain[0]
+	if first.From != "cmd/app" || first.To != "internal/api" || first.File != "cmd/app/main.go" || first.Line != 5 {
+		t.Fatalf("unexpected first step %+v", first)
+	}
+	if chain[1].To != "internal/store" || chain[1].File != "internal/api/a.go" || chain[1].Line != 3 {
+		t.Fatalf("expected the direct store import to be the shortest hop, got %+v", chain[1])
+	}
+	if chain[2].To != "database/sql" || chain[2].Files != 1 {
+		t.Fatalf("unexpected last step %+v", chain[2])
+	}
+
+	report, err = Why(idx, "internal/api", "internal/cache", 0)
+	if err != nil {
+		t.Fatalf("Why returned error: %v", err)
+	}
+	if !report.Direct || len(report.Chains) != 1 {
+		t.Fatalf("expected a direct dependency, got %+v", report)
+	}
+
+	report, err = Why(idx, "internal/store", "cmd/app", 0)
+	if err != nil {
+		t.Fatalf("Why returned error: %v", err)
+	}
+	if len(report.Chains) != 0 {
+		t.Fatalf("expected no chains, got %+v", report.Chains)
+	}
+}
@@ -0,0 +1,449 @@
+// Package docgen renders per-package Markdown reference pages from a
+// structural index: symbol tables with signatures and doc comments, the
+// package import graph, and the most frequent callers of each symbol.
+package docgen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/deps"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/testmap"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// Marker heads every generated page. Pages without it are never overwritten
+// or removed.
+const Marker = "<!-- Code generated by gts docgen. DO NOT EDIT. -->"
+
+// IndexPage is the overview page listing every package.
+const IndexPage = "README.md"
+
+type Options struct {
+	// SourcePrefix is prepended to index-relative source paths in links, so
+	// they resolve from the output directory, e.g. "../" for docs/.
+	SourcePrefix string
+	// MaxCallers caps the callers listed per symbol. Defaults to 5.
+	MaxCallers int
+	// IncludeUnexported documents unexported symbols as well.
+	IncludeUnexported bool
+}
+
+// Page is one generated Markdown file.
+type Page struct {
+	Package string `json:"package"`
+	Path    string `json:"path"` // slash-separated, relative to the output directory
+	Content []byte `json:"-"`
+}
+
+type symbolDoc struct {
+	model.Symbol
+	Doc     string
+	Callers []caller
+}
+
+type caller struct {
+	Name  string
+	File  string
+	Line  int
+	Count int
+}
+
+type packageDoc struct {
+	Name       string
+	Files      []string
+	Symbols    []symbolDoc
+	Imports    []string // internal packages
+	External   []string // external imports
+	ImportedBy []string
+}
+
+// PagePath returns the output path of a package's page.
+func PagePath(pkg string) string {
+	if pkg == "." {
+		return "_root.md"
+	}
+	return pkg + ".md"
+}
+
+// Generate renders the index page and one page per package with at least one
+// documented symbol. Doc comments are read from the sources under idx.Root.
+// Test files are skipped.
+func Generate(idx *model.Index, opts Options) ([]Page, error) {
+	if idx == nil {
+		return nil, fmt.Errorf("index is nil")
+	}
+	if opts.MaxCallers <= 0 {
+		opts.MaxCallers = 5
+	}
+
+	graph, err := xref.Build(idx)
+	if err != nil {
+		return nil, err
+	}
+	// Imports made only by tests are not part of a package's dependencies.
+	sources := *idx
+	sources.Files = make([]model.FileSummary, 0, len(idx.Files))
+	testFiles := map[string]bool{}
+	for _, file := range idx.Files {
+		if testmap.IsTestFile(file.Path, file.Language) {
+			testFiles[file.Path] = true
+			continue
+		}
+		sources.Files = append(sources.Files, file)
+	}
+	depReport, err := deps.Build(&sources, deps.Options{Mode: "package", IncludeEdges: true})
+	if err != nil {
+		return nil, err
+	}
+
+	definitionIDs := make(map[string]string, len(graph.Definitions))
+	for _, definition := range graph.Definitions {
+		definitionIDs[definitionKey(definition.File, definition.Kind, definition.Name, definition.StartLine)] = definition.ID
+	}
+
+	packages := map[string]*packageDoc{}
+	for _, file := range sources.Files {
+		pkgName := xref.PackageOf(file.Path)
+		var source []string
+		if data, err := os.ReadFile(filepath.Join(idx.Root, filepath.FromSlash(file.Path))); err == nil {
+			source = strings.Split(string(data), "\n")
+		}
+
+		types := map[string]bool{}
+		for _, symbol := range file.Symbols {
+			if model.IsContainerKind(symbol.Kind) {
+				types[symbol.QualifiedName()] = true
+			}
+		}
+
+		var symbols []symbolDoc
+		for _, symbol := range file.Symbols {
+			if !documented(symbol, types, opts.IncludeUnexported) {
+				continue
+			}
+			symbol.File = file.Path
+			id := definitionIDs[definitionKey(file.Path, symbol.Kind, symbol.Name, symbol.StartLine)]
+			symbols = append(symbols, symbolDoc{
+				Symbol:  symbol,
+				Doc:     DocComment(source, symbol.StartLine),
+				Callers: keyCallers(&graph, id, testFiles, opts.MaxCallers),
+			})
+		}
+		if len(symbols) == 0 {
+			continue
+		}
+		pkg, ok := packages[pkgName]
+		if !ok {
+			pkg = &packageDoc{Name: pkgName}
+			packages[pkgName] = pkg
+		}
+		pkg.Files = append(pkg.Files, file.Path)
+		pkg.Symbols = append(pkg.Symbols, symbols...)
+	}
+
+	for _, edge := range depReport.Edges {
+		if from, ok := packages[edge.From]; ok {
+			if edge.Internal {
+				from.Imports = append(from.Imports, edge.To)
+			} else {
+				from.External = append(from.External, edge.To)
+			}
+		}
+		if to, ok := packages[edge.To]; ok && edge.Internal && edge.From != edge.To {
+			to.ImportedBy = append(to.ImportedBy, edge.From)
+		}
+	}
+
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pages := make([]Page, 0, len(names)+1)
+	pages = append(pages, Page{Path: IndexPage, Content: renderIndex(names, packages)})
+	for _, name := range names {
+		pkg := packages[name]
+		sort.Strings(pkg.Files)
+		sort.Strings(pkg.Imports)
+		sort.Strings(pkg.External)
+		sort.Strings(pkg.ImportedBy)
+		sort.SliceStable(pkg.Symbols, func(i, j int) bool {
+			if pkg.Symbols[i].File == pkg.Symbols[j].File {
+				return pkg.Symbols[i].StartLine < pkg.Symbols[j].StartLine
+			}
+			return pkg.Symbols[i].File < pkg.Symbols[j].File
+		})
+		pages = append(pages, Page{
+			Package: name,
+			Path:    PagePath(name),
+			Content: renderPackage(pkg, packages, opts),
+		})
+	}
+	return pages, nil
+}
+
+// documented reports whether a symbol gets an entry: top-level declarations
+// and the members of types, but not fields, variables, or nested functions.
+func documented(symbol model.Symbol, types map[string]bool, includeUnexported bool) bool {
+	switch symbol.Kind {
+	case "field_definition", "variable_definition", "closure_definition":
+		return false
+	}
+	if symbol.ContainerPath != "" && !types[symbol.ContainerPath] {
+		return false
+	}
+	return includeUnexported || symbol.Exported
+}
+
+// keyCallers returns the non-test callers of a definition with the most call
+// sites.
+func keyCallers(graph *xref.Graph, definitionID string, testFiles map[string]bool, limit int) []caller {
+	if definitionID == "" {
+		return nil
+	}
+	edges := graph.IncomingEdges(definitionID)
+	callers := make([]caller, 0, len(edges))
+	for _, edge := range edges {
+		from := graph.EdgeCaller(edge)
+		if testFiles[from.File] {
+			continue
+		}
+		name := from.Name
+		if from.Receiver != "" {
+			name = receiverType(from.Receiver) + "." + name
+		}
+		callers = append(callers, caller{Name: name, File: from.File, Line: from.StartLine, Count: edge.Count})
+	}
+	sort.SliceStable(callers, func(i, j int) bool {
+		if callers[i].Count == callers[j].Count {
+			if callers[i].File == callers[j].File {
+				return callers[i].Line < callers[j].Line
+			}
+			return callers[i].File < callers[j].File
+		}
+		return callers[i].Count > callers[j].Count
+	})
+	if len(callers) > limit {
+		callers = callers[:limit]
+	}
+	return callers
+}
+
+func definitionKey(file, kind, name string, line int) string {
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%d", file, kind, name, line)
+}
+
+// receiverType reduces a Go receiver such as "s *Server" to "Server".
+func receiverType(receiver string) string {
+	fields := strings.Fields(receiver)
+	if len(fields) == 0 {
+		return receiver
+	}
+	return strings.TrimLeft(fields[len(fields)-1], "*&")
+}
+
+// DocComment returns the comment block directly above line (1-based) of
+// source, or a Python docstring directly below it, with comment markers
+// removed.
+func DocComment(source []string, line int) string {
+	if line < 1 || line > len(source) {
+		return ""
+	}
+
+	var block []string
+	for i := line - 2; i >= 0; i-- {
+		trimmed := strings.TrimSpace(source[i])
+		text, ok := stripCommentMarker(trimmed)
+		if !ok {
+			if strings.HasPrefix(trimmed, "@") || strings.HasPrefix(trimmed, "#[") {
+				continue // decorators and attributes sit between a comment and its declaration
+			}
+			break
+		}
+		block = append([]string{text}, block...)
+	}
+	if len(block) == 0 {
+		block = docstring(source, line)
+	}
+
+	for len(block) > 0 && block[0] == "" {
+		block = block[1:]
+	}
+	for len(block) > 0 && block[len(block)-1] == "" {
+		block = block[:len(block)-1]
+	}
+	return strings.Join(block, "\n")
+}
+
+func stripCommentMarker(line string) (string, bool) {
+	for _, marker := range []string{"///", "//!", "//", "/**", "/*", "*/", "*", "#"} {
+		if strings.HasPrefix(line, marker) {
+			if marker == "#" && strings.HasPrefix(line, "#[") {
+				return "", false
+			}
+			text := strings.TrimPrefix(line, marker)
+			text = strings.TrimSuffix(strings.TrimSpace(text), "*/")
+			return strings.TrimSpace(text), true
+		}
+	}
+	return "", false
+}
+
+// docstring returns the Python docstring opening on the line after a def or
+// class line.
+func docstring(source []string, line int) []string {
+	if line >= len(source) {
+		return nil
+	}
+	first := strings.TrimSpace(source[line])
+	quote := ""
+	for _, candidate := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(first, candidate) {
+			quote = candidate
+		}
+	}
+	if quote == "" {
+		return nil
+	}
+	first = strings.TrimPrefix(first, quote)
+	if end := strings.Index(first, quote); end >= 0 {
+		return []string{strings.TrimSpace(first[:end])}
+	}
+	block := []string{strings.TrimSpace(first)}
+	for i := line + 1; i < len(source); i++ {
+		text := strings.TrimSpace(source[i])
+		if end := strings.Index(text, quote); end >= 0 {
+			return append(block, strings.TrimSpace(text[:end]))
+		}
+		block = append(block, text)
+	}
+	return block
+}
+
+func renderIndex(names []string, packages map[string]*packageDoc) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n\n# Packages\n\n", Marker)
+	b.WriteString("| Package | Files | Symbols | Imported by |\n|---|---|---|---|\n")
+	for _, name := range names {
+		pkg := packages[name]
+		fmt.Fprintf(&b, "| [%s](%s) | %d | %d | %d |\n", name, PagePath(name), len(pkg.Files), len(pkg.Symbols), len(pkg.ImportedBy))
+	}
+	return b.Bytes()
+}
+
+func renderPackage(pkg *packageDoc, packages map[string]*packageDoc, opts Options) []byte {
+	pagePath := PagePath(pkg.Name)
+	pageLink := func(target string) string {
+		if _, ok := packages[target]; !ok {
+			return "`" + target + "`"
+		}
+		return fmt.Sprintf("[%s](%s)", target, relativeLink(pagePath, PagePath(target)))
+	}
+	sourceLink := func(file string, line int) string {
+		return fmt.Sprintf("[%s:%d](%s#L%d)", path.Base(file), line, relativeLink(pagePath, opts.SourcePrefix+file), line)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n\n# %s\n\n", Marker, pkg.Name)
+	fmt.Fprintf(&b, "Files: ")
+	for i, file := range pkg.Files {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "[%s](%s)", path.Base(file), relativeLink(pagePath, opts.SourcePrefix+file))
+	}
+	b.WriteString("\n")
+
+	if len(pkg.Imports) > 0 || len(pkg.External) > 0 || len(pkg.ImportedBy) > 0 {
+		b.WriteString("\n## Imports\n\n")
+		writeList := func(label string, items []string, render func(string) string) {
+			if len(items) == 0 {
+				return
+			}
+			rendered := make([]string, len(items))
+			for i, item := range items {
+				rendered[i] = render(item)
+			}
+			fmt.Fprintf(&b, "- %s: %s\n", label, strings.Join(rendered, ", "))
+		}
+		writeList("Imports", pkg.Imports, pageLink)
+		writeList("External", pkg.External, func(item string) string { return "`" + item + "`" })
+		writeList("Imported by", pkg.ImportedBy, pageLink)
+	}
+
+	b.WriteString("\n## Symbols\n\n| Name | Kind | Signature | Source |\n|---|---|---|---|\n")
+	for _, symbol := range pkg.Symbols {
+		fmt.Fprintf(&b, "| [%s](#%s) | %s | `%s` | %s |\n",
+			symbolTitle(symbol.Symbol),
+			anchor(symbolTitle(symbol.Symbol)),
+			strings.TrimSuffix(symbol.Kind, "_definition"),
+			tableCode(symbol.Signature),
+			sourceLink(symbol.File, symbol.StartLine),
+		)
+	}
+
+	for _, symbol := range pkg.Symbols {
+		fmt.Fprintf(&b, "\n### %s\n\n", symbolTitle(symbol.Symbol))
+		if symbol.Signature != "" {
+			fmt.Fprintf(&b, "```\n%s\n```\n\n", symbol.Signature)
+		}
+		if symbol.Doc != "" {
+			fmt.Fprintf(&b, "%s\n\n", symbol.Doc)
+		}
+		fmt.Fprintf(&b, "Defined in %s.\n", sourceLink(symbol.File, symbol.StartLine))
+		if len(symbol.Callers) > 0 {
+			b.WriteString("\nKey callers:\n\n")
+			for _, c := range symbol.Callers {
+				fmt.Fprintf(&b, "- `%s` in %s (%d)\n", c.Name, sourceLink(c.File, c.Line), c.Count)
+			}
+		}
+	}
+	return b.Bytes()
+}
+
+func symbolTitle(symbol model.Symbol) string {
+	if symbol.ContainerPath != "" {
+		return symbol.ContainerPath + "." + symbol.Name
+	}
+	if symbol.Receiver != "" && symbol.Kind == "method_definition" {
+		return receiverType(symbol.Receiver) + "." + symbol.Name
+	}
+	return symbol.Name
+}
+
+// anchor returns the GitHub heading anchor for title.
+func anchor(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r == ' ' || r == '-':
+			b.WriteRune('-')
+		case r == '_' || ('a' <= r && r <= 'z') || ('0' <= r && r <= '9'):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// tableCode escapes text for inline code inside a Markdown table cell.
+func tableCode(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "`", "'")
+}
+
+// relativeLink returns target relative to the directory of the page at from.
+// Both are slash-separated paths relative to the output directory.
+func relativeLink(from, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
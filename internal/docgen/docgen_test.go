@@ -0,0 +1,120 @@
+package docgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+func TestGenerate(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeFile(t, root, "lib/lib.go", `package lib
+
+// Greet returns a greeting
+// for name.
+func Greet(name string) string {
+	return "hello " + name
+}
+
+func helper() {}
+`)
+	writeFile(t, root, "lib/lib_test.go", `package lib
+
+import "testing"
+
+func TestGreet(t *testing.T) { Greet("x") }
+`)
+	writeFile(t, root, "app/main.go", `package main
+
+import "example.com/app/lib"
+
+func main() {
+	lib.Greet("a")
+	lib.Greet("b")
+}
+`)
+
+	builder := index.NewBuilder()
+	idx, err := builder.BuildPath(root)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+
+	pages, err := Generate(idx, Options{SourcePrefix: "../"})
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	byPath := map[string]string{}
+	for _, page := range pages {
+		byPath[page.Path] = string(page.Content)
+	}
+	if len(pages) != 2 || byPath[IndexPage] == "" || byPath["lib.md"] == "" {
+		t.Fatalf("unexpected pages: %v", pageNames(pages))
+	}
+	if !strings.Contains(byPath[IndexPage], "[lib](lib.md)") {
+		t.Fatalf("index page does not link lib:\n%s", byPath[IndexPage])
+	}
+
+	lib := byPath["lib.md"]
+	if !strings.HasPrefix(lib, Marker) {
+		t.Fatalf("page does not start with marker:\n%s", lib)
+	}
+	for _, want := range []string{
+		"- Imported by: `app`",
+		"| [Greet](#greet) | function | `func Greet(name string) string` | [lib.go:5](../lib/lib.go#L5) |",
+		"Greet returns a greeting\nfor name.",
+		"- `main` in [main.go:5](../app/main.go#L5) (2)",
+	} {
+		if !strings.Contains(lib, want) {
+			t.Fatalf("lib page missing %q:\n%s", want, lib)
+		}
+	}
+	for _, unwanted := range []string{"helper", "TestGreet", "`testing`"} {
+		if strings.Contains(lib, unwanted) {
+			t.Fatalf("lib page should not mention %q:\n%s", unwanted, lib)
+		}
+	}
+}
+
+func TestDocComment(t *testing.T) {
+	source := strings.Split(`# Loads settings.
+@cached
+def load():
+    pass
+
+class Store:
+    """Holds items.
+
+    Thread-safe.
+    """
+`, "\n")
+	if got := DocComment(source, 3); got != "Loads settings." {
+		t.Fatalf("unexpected comment: %q", got)
+	}
+	if got := DocComment(source, 6); got != "Holds items.\n\nThread-safe." {
+		t.Fatalf("unexpected docstring: %q", got)
+	}
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
+
+func pageNames(pages []Page) []string {
+	names := make([]string, 0, len(pages))
+	for _, page := range pages {
+		names = append(names, page.Path)
+	}
+	return names
+}
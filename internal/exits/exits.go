@@ -0,0 +1,285 @@
+// Package exits audits calls that end the process or unwind the stack —
+// panic, os.Exit, log.Fatal, process.exit, sys.exit, and their equivalents —
+// so they can be confined to entry points and test helpers.
+//
+// Calls are found among the indexed call references by name and qualifier.
+// Main packages, entry-point files, and test files are approved by default;
+// further approvals come from allow globs and "ignore" directives in
+// .gtslint.
+package exits
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/lint"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/testmap"
+)
+
+// Call kinds.
+const (
+	KindPanic = "panic"
+	KindExit  = "exit"
+	KindFatal = "fatal"
+)
+
+// Metric is the .gtslint ignore metric approving every audited call, as in
+// "ignore exits in tools/".
+const Metric = "exits"
+
+// Finding is one audited call.
+type Finding struct {
+	Kind     string `json:"kind"`
+	Call     string `json:"call"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Language string `json:"language"`
+	// Symbol is the innermost function or method containing the call.
+	Symbol string `json:"symbol,omitempty"`
+	// Allowed reports whether the call is approved, and Reason says why.
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Options configures an audit.
+type Options struct {
+	// Allow approves calls in files matching any of these globs.
+	Allow []string
+	// NoDefaults disables the built-in approval of main packages, entry
+	// points, and test files.
+	NoDefaults bool
+	// Config approves calls covered by its ignore directives, matched by the
+	// "exits" metric, the call kind, or the call itself (e.g. os.exit).
+	Config *lint.Config
+}
+
+// auditedCall names a call by qualifier and name. An empty qualifier matches
+// only unqualified calls, such as the panic builtin.
+type auditedCall struct {
+	qualifier string
+	name      string
+	kind      string
+}
+
+var callsByLanguage = map[string][]auditedCall{
+	"go": {
+		{"", "panic", KindPanic},
+		{"os", "Exit", KindExit},
+		{"syscall", "Exit", KindExit},
+		{"runtime", "Goexit", KindExit},
+		{"log", "Fatal", KindFatal},
+		{"log", "Fatalf", KindFatal},
+		{"log", "Fatalln", KindFatal},
+		{"log", "Panic", KindPanic},
+		{"log", "Panicf", KindPanic},
+		{"log", "Panicln", KindPanic},
+	},
+	"javascript": jsCalls,
+	"typescript": jsCalls,
+	"tsx":        jsCalls,
+	"python": {
+		{"sys", "exit", KindExit},
+		{"os", "_exit", KindExit},
+		{"os", "abort", KindExit},
+		{"", "exit", KindExit},
+		{"", "quit", KindExit},
+	},
+	"java": {
+		{"System", "exit", KindExit},
+	},
+	"kotlin": {
+		{"", "exitProcess", KindExit},
+		{"System", "exit", KindExit},
+	},
+	"rust": {
+		{"", "panic", KindPanic},
+		{"process", "exit", KindExit},
+		{"process", "abort", KindExit},
+	},
+	"ruby": {
+		{"", "exit", KindExit},
+		{"", "exit!", KindExit},
+		{"", "abort", KindExit},
+	},
+	"c":   cCalls,
+	"cpp": cCalls,
+}
+
+var jsCalls = []auditedCall{
+	{"process", "exit", KindExit},
+	{"process", "abort", KindExit},
+}
+
+var cCalls = []auditedCall{
+	{"", "exit", KindExit},
+	{"", "_Exit", KindExit},
+	{"", "abort", KindExit},
+}
+
+// Audit returns the audited calls of the index ordered by file and line,
+// with Allowed set on the approved ones.
+func Audit(idx *model.Index, opts Options) ([]Finding, error) {
+	if idx == nil {
+		return nil, fmt.Errorf("index is nil")
+	}
+
+	var findings []Finding
+	for _, file := range idx.Files {
+		calls := callsByLanguage[file.Language]
+		if len(calls) == 0 {
+			continue
+		}
+		var entryPoint, checkedEntryPoint bool
+		for _, reference := range file.References {
+			if !strings.Contains(reference.Kind, "call") {
+				continue
+			}
+			call, ok := matchCall(calls, reference)
+			if !ok {
+				continue
+			}
+
+			finding := Finding{
+				Kind:     call.kind,
+				Call:     call.label(file.Language),
+				File:     file.Path,
+				Line:     reference.StartLine,
+				Language: file.Language,
+				Symbol:   enclosingFunction(file.Symbols, reference.StartLine),
+			}
+			if !checkedEntryPoint && !opts.NoDefaults {
+				entryPoint = isEntryPoint(idx.Root, file)
+				checkedEntryPoint = true
+			}
+			finding.Allowed, finding.Reason = approval(file, finding, entryPoint, opts)
+			findings = append(findings, finding)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File == findings[j].File {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].File < findings[j].File
+	})
+	return findings, nil
+}
+
+func matchCall(calls []auditedCall, reference model.Reference) (auditedCall, bool) {
+	qualifier := reference.Qualifier
+	for _, separator := range []string{"::", "."} {
+		if i := strings.LastIndex(qualifier, separator); i >= 0 {
+			qualifier = qualifier[i+len(separator):]
+		}
+	}
+	for _, call := range calls {
+		if call.name == reference.Name && call.qualifier == qualifier {
+			return call, true
+		}
+	}
+	return auditedCall{}, false
+}
+
+func (c auditedCall) label(language string) string {
+	name := c.name
+	if language == "rust" && c.qualifier == "" {
+		name += "!"
+	}
+	if c.qualifier == "" {
+		return name
+	}
+	if language == "rust" {
+		return c.qualifier + "::" + name
+	}
+	return c.qualifier + "." + name
+}
+
+func approval(file model.FileSummary, finding Finding, entryPoint bool, opts Options) (bool, string) {
+	if !opts.NoDefaults {
+		if testmap.IsTestFile(file.Path, file.Language) || isTestHelper(file.Path) {
+			return true, "test file"
+		}
+		if entryPoint {
+			return true, "entry point"
+		}
+	}
+	for _, glob := range opts.Allow {
+		if model.MatchGlob(glob, file.Path) || strings.HasPrefix(file.Path, strings.TrimSuffix(glob, "/")+"/") {
+			return true, "allowed by " + glob
+		}
+	}
+	if opts.Config != nil {
+		for _, metric := range []string{Metric, finding.Kind, finding.Call} {
+			if opts.Config.ShouldIgnore(file.Path, finding.Symbol, metric) {
+				return true, "ignored by .gtslint"
+			}
+		}
+	}
+	return false, ""
+}
+
+// testHelperDirs are directories conventionally holding shared test support
+// code outside test files.
+var testHelperDirs = map[string]bool{
+	"testutil": true, "testutils": true, "testhelper": true, "testhelpers": true,
+	"testing": true, "testdata": true, "fixtures": true,
+}
+
+func isTestHelper(filePath string) bool {
+	for _, dir := range strings.Split(path.Dir(filepath.ToSlash(filePath)), "/") {
+		if testHelperDirs[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+var goPackageClause = regexp.MustCompile(`(?m)^\s*package\s+(\w+)`)
+
+// isEntryPoint reports whether file is a program entry point: a Go main
+// package, a Python __main__.py, a Rust main.rs or src/bin file, or a script
+// under bin/.
+func isEntryPoint(root string, file model.FileSummary) bool {
+	slashed := filepath.ToSlash(file.Path)
+	base := path.Base(slashed)
+	switch file.Language {
+	case "go":
+		source, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file.Path)))
+		if err != nil {
+			return false
+		}
+		match := goPackageClause.FindSubmatch(source)
+		return match != nil && string(match[1]) == "main"
+	case "python":
+		return base == "__main__.py"
+	case "rust":
+		return base == "main.rs" || strings.Contains("/"+slashed, "/src/bin/")
+	}
+	return strings.HasPrefix(slashed, "bin/") || strings.Contains(slashed, "/bin/")
+}
+
+// enclosingFunction returns the innermost function or method spanning line.
+func enclosingFunction(symbols []model.Symbol, line int) string {
+	best := -1
+	for i, symbol := range symbols {
+		if !model.IsFunctionKind(symbol.Kind) && symbol.Kind != "method_definition" {
+			continue
+		}
+		if line < symbol.StartLine || line > symbol.EndLine {
+			continue
+		}
+		if best < 0 || symbol.StartLine > symbols[best].StartLine {
+			best = i
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	return symbols[best].Name
+}
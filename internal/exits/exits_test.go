@@ -0,0 +1,105 @@
+package exits
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/internal/lint"
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+func TestAudit(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "cmd/app/main.go", `package main
+
+import "os"
+
+func main() { os.Exit(run()) }
+`)
+	writeFile(t, root, "lib/lib.go", `package lib
+
+import (
+	"log"
+	"os"
+)
+
+func Must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func Load() {
+	log.Fatalf("load")
+	os.Exit(2)
+	s.Exit()
+}
+`)
+	writeFile(t, root, "lib/lib_test.go", `package lib
+
+import "os"
+
+func TestMain(m *testing.M) { os.Exit(m.Run()) }
+`)
+	writeFile(t, root, "web/server.js", `function stop() { process.exit(1); }
+`)
+	writeFile(t, root, "tools/gen.py", `import sys
+
+def main():
+    sys.exit(1)
+`)
+
+	idx, err := index.NewBuilder().BuildPath(root)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	cfg, err := lint.ParseConfig("ignore panic in lib/lib.go:Must\n")
+	if err != nil {
+		t.Fatalf("ParseConfig returned error: %v", err)
+	}
+	findings, err := Audit(idx, Options{Allow: []string{"tools/"}, Config: cfg})
+	if err != nil {
+		t.Fatalf("Audit returned error: %v", err)
+	}
+
+	got := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		got = append(got, fmt.Sprintf("%s:%d %s %s [%s] allowed=%v %s", finding.File, finding.Line, finding.Kind, finding.Call, finding.Symbol, finding.Allowed, finding.Reason))
+	}
+	want := []string{
+		"cmd/app/main.go:5 exit os.Exit [main] allowed=true entry point",
+		"lib/lib.go:10 panic panic [Must] allowed=true ignored by .gtslint",
+		"lib/lib.go:15 fatal log.Fatalf [Load] allowed=false ",
+		"lib/lib.go:16 exit os.Exit [Load] allowed=false ",
+		"lib/lib_test.go:5 exit os.Exit [TestMain] allowed=true test file",
+		"tools/gen.py:4 exit sys.exit [main] allowed=true allowed by tools/",
+		"web/server.js:1 exit process.exit [stop] allowed=false ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	strict, err := Audit(idx, Options{NoDefaults: true})
+	if err != nil {
+		t.Fatalf("Audit returned error: %v", err)
+	}
+	for _, finding := range strict {
+		if finding.Allowed {
+			t.Fatalf("expected no approvals without defaults, got %+v", finding)
+		}
+	}
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package federation
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
+)
+
+// ConflictPolicy decides what Merge does when two inputs hold the same
+// merged path.
+type ConflictPolicy string
+
+const (
+	// ConflictError fails the merge, listing the conflicting paths.
+	ConflictError ConflictPolicy = "error"
+	// ConflictNewest keeps the file from the input generated last.
+	ConflictNewest ConflictPolicy = "newest"
+	// ConflictFirst keeps the file from the earliest input on the command line.
+	ConflictFirst ConflictPolicy = "first"
+	// ConflictLast keeps the file from the latest input on the command line.
+	ConflictLast ConflictPolicy = "last"
+	// ConflictPrefix places every input's files under a directory named for
+	// its repository, so no two inputs can collide.
+	ConflictPrefix ConflictPolicy = "prefix"
+)
+
+// ParseConflictPolicy returns the ConflictPolicy named name.
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(strings.TrimSpace(name)); policy {
+	case ConflictError, ConflictNewest, ConflictFirst, ConflictLast, ConflictPrefix:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown conflict policy %q (want error, newest, first, last, or prefix)", name)
+}
+
+// MergeInput is one index to merge. Name identifies its repository, for
+// ConflictPrefix and reports.
+type MergeInput struct {
+	Source string
+	Name   string
+	Index  *model.Index
+}
+
+// MergedInput reports where Merge placed an input.
+type MergedInput struct {
+	Source string `json:"source"`
+	Name   string `json:"name"`
+	Root   string `json:"root"`
+	Prefix string `json:"prefix,omitempty"`
+	Files  int    `json:"files"`
+}
+
+// MergeConflict is a merged path that more than one input held.
+type MergeConflict struct {
+	Path    string   `json:"path"`
+	Kept    string   `json:"kept"`
+	Dropped []string `json:"dropped"`
+}
+
+// MergeReport describes a merge.
+type MergeReport struct {
+	Root      string          `json:"root"`
+	Policy    ConflictPolicy  `json:"policy"`
+	Inputs    []MergedInput   `json:"inputs"`
+	Files     int             `json:"files"`
+	Conflicts []MergeConflict `json:"conflicts"`
+}
+
+// Merge combines indexes, possibly of different repositories, into one.
+// The merged root is the deepest directory containing every input root, and
+// each input's paths are rebased onto it, so indexes of sibling checkouts
+// merge without clashing and their files stay readable from the merged
+// root. Inputs with the same root, such as two partial builds of one
+// repository, share paths; when two inputs hold the same merged path, the
+// policy picks one. ConflictPrefix instead places each input under its name.
+func Merge(inputs []MergeInput, policy ConflictPolicy) (*model.Index, *MergeReport, error) {
+	if len(inputs) == 0 {
+		return nil, nil, fmt.Errorf("no indexes to merge")
+	}
+	if policy == "" {
+		policy = ConflictError
+	}
+
+	roots := make([]string, len(inputs))
+	for i, input := range inputs {
+		roots[i] = slashpath.Clean(input.Index.Root)
+	}
+	root := commonDir(roots)
+	report := &MergeReport{Root: filepath.FromSlash(root), Policy: policy, Conflicts: []MergeConflict{}}
+
+	if policy == ConflictPrefix {
+		seen := map[string]string{}
+		for _, input := range inputs {
+			if other, ok := seen[input.Name]; ok {
+				return nil, nil, fmt.Errorf("%s and %s are both named %q; prefix needs distinct repository names", other, input.Source, input.Name)
+			}
+			seen[input.Name] = input.Source
+		}
+	}
+
+	type owner struct {
+		input int
+		file  model.FileSummary
+	}
+	owners := map[string]owner{}
+	dropped := map[string][]int{}
+	var errs []model.ParseError
+	var skipped []model.SkippedFile
+	for i, input := range inputs {
+		prefix := rebasePrefix(root, roots[i])
+		if policy == ConflictPrefix {
+			prefix = input.Name
+		}
+		report.Inputs = append(report.Inputs, MergedInput{
+			Source: input.Source,
+			Name:   input.Name,
+			Root:   input.Index.Root,
+			Prefix: prefix,
+			Files:  len(input.Index.Files),
+		})
+
+		for _, file := range input.Index.Files {
+			file = rebaseFile(file, prefix)
+			current, taken := owners[file.Path]
+			if !taken {
+				owners[file.Path] = owner{input: i, file: file}
+				continue
+			}
+			if keepNew(policy, inputs[current.input].Index, input.Index) {
+				owners[file.Path] = owner{input: i, file: file}
+				dropped[file.Path] = append(dropped[file.Path], current.input)
+			} else {
+				dropped[file.Path] = append(dropped[file.Path], i)
+			}
+		}
+		for _, parseErr := range input.Index.Errors {
+			parseErr.Path = joinPrefix(prefix, parseErr.Path)
+			errs = append(errs, parseErr)
+		}
+		for _, skip := range input.Index.Skipped {
+			skip.Path = joinPrefix(prefix, skip.Path)
+			skipped = append(skipped, skip)
+		}
+	}
+
+	for p, losers := range dropped {
+		conflict := MergeConflict{Path: p, Kept: inputs[owners[p].input].Source}
+		for _, i := range losers {
+			conflict.Dropped = append(conflict.Dropped, inputs[i].Source)
+		}
+		report.Conflicts = append(report.Conflicts, conflict)
+	}
+	sort.Slice(report.Conflicts, func(i, j int) bool { return report.Conflicts[i].Path < report.Conflicts[j].Path })
+	if policy == ConflictError && len(report.Conflicts) > 0 {
+		return nil, report, fmt.Errorf("%d paths are in more than one index, e.g. %s in %s and %s; pick a --on-conflict policy",
+			len(report.Conflicts), report.Conflicts[0].Path, report.Conflicts[0].Kept, strings.Join(report.Conflicts[0].Dropped, ", "))
+	}
+
+	merged := &model.Index{
+		Version:     index.SchemaVersion(),
+		Root:        report.Root,
+		GeneratedAt: time.Now(),
+		Errors:      errs,
+		Skipped:     skipped,
+	}
+	if revision := inputs[0].Index.Revision; revision != "" {
+		same := true
+		for _, input := range inputs[1:] {
+			same = same && input.Index.Revision == revision
+		}
+		if same {
+			merged.Revision = revision
+		}
+	}
+	for _, o := range owners {
+		merged.Files = append(merged.Files, o.file)
+	}
+	sort.Slice(merged.Files, func(i, j int) bool { return merged.Files[i].Path < merged.Files[j].Path })
+	merged.Digest = index.Digest(merged)
+	report.Files = len(merged.Files)
+	return merged, report, nil
+}
+
+// keepNew reports whether a file from next replaces the one from current
+// under policy.
+func keepNew(policy ConflictPolicy, current, next *model.Index) bool {
+	switch policy {
+	case ConflictLast:
+		return true
+	case ConflictNewest:
+		return next.GeneratedAt.After(current.GeneratedAt)
+	}
+	return false
+}
+
+func rebaseFile(file model.FileSummary, prefix string) model.FileSummary {
+	if prefix == "" {
+		return file
+	}
+	file.Path = joinPrefix(prefix, file.Path)
+	symbols := make([]model.Symbol, len(file.Symbols))
+	for i, sym := range file.Symbols {
+		sym.File = joinPrefix(prefix, sym.File)
+		symbols[i] = sym
+	}
+	file.Symbols = symbols
+	refs := make([]model.Reference, len(file.References))
+	for i, ref := range file.References {
+		ref.File = joinPrefix(prefix, ref.File)
+		refs[i] = ref
+	}
+	file.References = refs
+	return file
+}
+
+func joinPrefix(prefix, p string) string {
+	if prefix == "" || p == "" {
+		return p
+	}
+	return path.Join(prefix, p)
+}
+
+// rebasePrefix returns the path of dir relative to its ancestor root. With
+// no common root, dir is kept whole.
+func rebasePrefix(root, dir string) string {
+	switch root {
+	case dir:
+		return ""
+	case "", "/":
+		return strings.TrimLeft(dir, "/")
+	}
+	return strings.TrimPrefix(dir, root+"/")
+}
+
+// commonDir returns the deepest directory containing every one of dirs, all
+// in slash form. Unrelated relative paths, or absolute paths on different
+// volumes, share only "".
+func commonDir(dirs []string) string {
+	common := strings.Split(dirs[0], "/")
+	for _, dir := range dirs[1:] {
+		parts := strings.Split(dir, "/")
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	switch {
+	case len(common) == 0:
+		return ""
+	case len(common) == 1 && common[0] == "":
+		return "/"
+	}
+	return strings.Join(common, "/")
+}
@@ -0,0 +1,118 @@
+package federation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func mergeTestIndex(root string, generated time.Time, paths ...string) *model.Index {
+	idx := &model.Index{Root: root, GeneratedAt: generated}
+	for _, p := range paths {
+		idx.Files = append(idx.Files, model.FileSummary{
+			Path:       p,
+			Symbols:    []model.Symbol{{File: p, Kind: "function_definition", Name: "F"}},
+			References: []model.Reference{{File: p, Kind: "reference.call", Name: "G"}},
+		})
+	}
+	return idx
+}
+
+func mergedPaths(idx *model.Index) []string {
+	var paths []string
+	for _, file := range idx.Files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+func TestMergeRebasesSiblingRepos(t *testing.T) {
+	now := time.Now()
+	merged, report, err := Merge([]MergeInput{
+		{Source: "api.json", Name: "api", Index: mergeTestIndex("/src/api", now, "main.go")},
+		{Source: "web.json", Name: "web", Index: mergeTestIndex("/src/web", now, "main.go", "lib/util.go")},
+	}, ConflictError)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if merged.Root != "/src" && merged.Root != `\src` {
+		t.Fatalf("Root = %q, want /src", merged.Root)
+	}
+	want := []string{"api/main.go", "web/lib/util.go", "web/main.go"}
+	if got := mergedPaths(merged); !reflect.DeepEqual(got, want) {
+		t.Fatalf("paths = %v, want %v", got, want)
+	}
+	file := merged.Files[1]
+	if file.Symbols[0].File != file.Path || file.References[0].File != file.Path {
+		t.Fatalf("expected symbol and reference files to be rebased, got %+v", file)
+	}
+	if len(report.Conflicts) != 0 || report.Inputs[1].Prefix != "web" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if merged.Digest == "" {
+		t.Fatal("expected a digest")
+	}
+}
+
+func TestMergeConflictPolicies(t *testing.T) {
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+	inputs := func() []MergeInput {
+		return []MergeInput{
+			{Source: "a.json", Name: "repo", Index: mergeTestIndex("/src/repo", newer, "main.go", "a.go")},
+			{Source: "b.json", Name: "repo-copy", Index: mergeTestIndex("/src/repo", older, "main.go", "b.go")},
+		}
+	}
+
+	if _, report, err := Merge(inputs(), ConflictError); err == nil || !strings.Contains(err.Error(), "main.go") || len(report.Conflicts) != 1 {
+		t.Fatalf("expected a conflict on main.go, got %v", err)
+	}
+
+	cases := map[ConflictPolicy]string{
+		ConflictNewest: "a.json",
+		ConflictFirst:  "a.json",
+		ConflictLast:   "b.json",
+	}
+	for policy, kept := range cases {
+		merged, report, err := Merge(inputs(), policy)
+		if err != nil {
+			t.Fatalf("%s: Merge: %v", policy, err)
+		}
+		if got := mergedPaths(merged); !reflect.DeepEqual(got, []string{"a.go", "b.go", "main.go"}) {
+			t.Fatalf("%s: paths = %v", policy, got)
+		}
+		if len(report.Conflicts) != 1 || report.Conflicts[0].Kept != kept {
+			t.Fatalf("%s: expected %s kept, got %+v", policy, kept, report.Conflicts)
+		}
+	}
+
+	merged, _, err := Merge(inputs(), ConflictPrefix)
+	if err != nil {
+		t.Fatalf("prefix: Merge: %v", err)
+	}
+	want := []string{"repo-copy/b.go", "repo-copy/main.go", "repo/a.go", "repo/main.go"}
+	if got := mergedPaths(merged); !reflect.DeepEqual(got, want) {
+		t.Fatalf("prefix: paths = %v, want %v", got, want)
+	}
+}
+
+func TestCommonDir(t *testing.T) {
+	cases := []struct {
+		dirs []string
+		want string
+	}{
+		{[]string{"/src/a", "/src/b"}, "/src"},
+		{[]string{"/src/a", "/src/a/lib"}, "/src/a"},
+		{[]string{"/srv", "/home"}, "/"},
+		{[]string{"C:/work/a", "C:/work/b"}, "C:/work"},
+		{[]string{"C:/a", "D:/b"}, ""},
+	}
+	for _, tc := range cases {
+		if got := commonDir(tc.dirs); got != tc.want {
+			t.Errorf("commonDir(%v) = %q, want %q", tc.dirs, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package inspect renders the tree-sitter concrete syntax tree of a file —
+// node types, field names, and ranges — as a reference for writing queries
+// and selectors.
+package inspect
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+	"github.com/odvcencio/gotreesitter/grammars"
+)
+
+// Options selects the part of the tree to render.
+type Options struct {
+	// StartLine and EndLine (1-based, inclusive) keep only nodes overlapping
+	// the range, along with their ancestors. Zero means unbounded.
+	StartLine int
+	EndLine   int
+	// MaxDepth stops descending below this depth; 0 renders the whole tree.
+	MaxDepth int
+	// Anonymous includes unnamed nodes such as punctuation and keywords.
+	Anonymous bool
+}
+
+// Node is one syntax node. Lines and columns are 1-based; columns count bytes.
+type Node struct {
+	Type        string `json:"type"`
+	Field       string `json:"field,omitempty"`
+	Named       bool   `json:"named"`
+	Missing     bool   `json:"missing,omitempty"`
+	StartLine   int    `json:"start_line"`
+	StartColumn int    `json:"start_column"`
+	EndLine     int    `json:"end_line"`
+	EndColumn   int    `json:"end_column"`
+	// Text is the source of leaf nodes, truncated to a single line.
+	Text     string `json:"text,omitempty"`
+	Children []Node `json:"children,omitempty"`
+}
+
+// Report is the inspected tree of one file.
+type Report struct {
+	File     string `json:"file"`
+	Language string `json:"language"`
+	Root     Node   `json:"root"`
+}
+
+// File parses path and returns its tree restricted by opts.
+func File(path string, opts Options) (Report, error) {
+	if opts.EndLine > 0 && opts.StartLine > opts.EndLine {
+		return Report{}, fmt.Errorf("start line %d is after end line %d", opts.StartLine, opts.EndLine)
+	}
+	entry := grammars.DetectLanguage(path)
+	if entry == nil {
+		return Report{}, fmt.Errorf("unsupported language for %s", path)
+	}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return Report{}, err
+	}
+	bound, err := grammars.ParseFile(path, source)
+	if err != nil {
+		return Report{}, fmt.Errorf("tree-sitter parse failed for %s: %w", path, err)
+	}
+	defer bound.Release()
+
+	root := bound.RootNode()
+	if root == nil {
+		return Report{}, fmt.Errorf("tree-sitter produced nil root for %s", path)
+	}
+	return Report{
+		File:     path,
+		Language: entry.Name,
+		Root:     convert(bound, root, "", 0, opts),
+	}, nil
+}
+
+func convert(bound *gotreesitter.BoundTree, node *gotreesitter.Node, field string, depth int, opts Options) Node {
+	start, end := node.StartPoint(), node.EndPoint()
+	result := Node{
+		Type:        bound.NodeType(node),
+		Field:       field,
+		Named:       node.IsNamed(),
+		Missing:     node.IsMissing(),
+		StartLine:   int(start.Row) + 1,
+		StartColumn: int(start.Column) + 1,
+		EndLine:     int(end.Row) + 1,
+		EndColumn:   int(end.Column) + 1,
+	}
+	if node.ChildCount() == 0 {
+		result.Text = leafText(bound.NodeText(node))
+		return result
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		return result
+	}
+	lang := bound.Language()
+	for i := 0; i < node.ChildCount(); i++ {
+		child := node.Child(i)
+		if child == nil || (!child.IsNamed() && !opts.Anonymous) || !overlaps(child, opts) {
+			continue
+		}
+		result.Children = append(result.Children, convert(bound, child, node.FieldNameForChild(i, lang), depth+1, opts))
+	}
+	return result
+}
+
+func overlaps(node *gotreesitter.Node, opts Options) bool {
+	startLine := int(node.StartPoint().Row) + 1
+	endLine := int(node.EndPoint().Row) + 1
+	if node.EndPoint().Column == 0 && endLine > startLine {
+		// A node ending at column 0 stops before that line.
+		endLine--
+	}
+	if opts.StartLine > 0 && endLine < opts.StartLine {
+		return false
+	}
+	if opts.EndLine > 0 && startLine > opts.EndLine {
+		return false
+	}
+	return true
+}
+
+func leafText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	const maxLen = 80
+	if len(text) > maxLen {
+		return text[:maxLen] + "..."
+	}
+	return text
+}
+
+// Format writes the tree as an indented outline, one node per line:
+//
+//	field: type [start_line:start_col-end_line:end_col] "leaf text"
+//
+// Anonymous nodes are shown with their type quoted, as queries spell them.
+func Format(root Node) string {
+	var b strings.Builder
+	format(&b, root, 0)
+	return b.String()
+}
+
+func format(b *strings.Builder, node Node, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	if node.Field != "" {
+		b.WriteString(node.Field + ": ")
+	}
+	if node.Named {
+		b.WriteString(node.Type)
+	} else {
+		fmt.Fprintf(b, "%q", node.Type)
+	}
+	if node.Missing {
+		b.WriteString(" MISSING")
+	}
+	fmt.Fprintf(b, " [%d:%d-%d:%d]", node.StartLine, node.StartColumn, node.EndLine, node.EndColumn)
+	if node.Named && node.Text != "" {
+		fmt.Fprintf(b, " %q", node.Text)
+	}
+	b.WriteByte('\n')
+	for _, child := range node.Children {
+		format(b, child, depth+1)
+	}
+}
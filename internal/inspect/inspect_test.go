@@ -0,0 +1,71 @@
+package inspect
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileLineRange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	source := "package main\n\nfunc main() {\n\tx := f(1)\n}\n\nfunc g() {}\n"
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	report, err := File(path, Options{StartLine: 4, EndLine: 4})
+	if err != nil {
+		t.Fatalf("File returned error: %v", err)
+	}
+	if report.Language != "go" {
+		t.Fatalf("language = %q, want go", report.Language)
+	}
+	got := Format(report.Root)
+	want := `source_file [1:1-8:1]
+  function_declaration [3:1-5:2]
+    body: block [3:13-5:2]
+      statement_list [4:2-5:1]
+        short_var_declaration [4:2-4:11]
+          left: expression_list [4:2-4:3]
+            identifier [4:2-4:3] "x"
+          right: expression_list [4:7-4:11]
+            call_expression [4:7-4:11]
+              function: identifier [4:7-4:8] "f"
+              arguments: argument_list [4:8-4:11]
+                int_literal [4:9-4:10] "1"
+`
+	if got != want {
+		t.Fatalf("unexpected tree:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFileDepthAndAnonymous(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.js")
+	if err := os.WriteFile(path, []byte("let a = [1, 2];\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	shallow, err := File(path, Options{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("File returned error: %v", err)
+	}
+	if len(shallow.Root.Children) != 1 || len(shallow.Root.Children[0].Children) != 0 {
+		t.Fatalf("expected one childless node at depth 1, got %+v", shallow.Root.Children)
+	}
+
+	full, err := File(path, Options{Anonymous: true})
+	if err != nil {
+		t.Fatalf("File returned error: %v", err)
+	}
+	if out := Format(full.Root); !strings.Contains(out, `"let" [1:1-1:4]`) || !strings.Contains(out, `"," [1:11-1:12]`) {
+		t.Fatalf("expected anonymous nodes in output:\n%s", out)
+	}
+
+	if _, err := File(path, Options{StartLine: 3, EndLine: 2}); err == nil {
+		t.Fatal("expected inverted line range to fail")
+	}
+	if _, err := File(filepath.Join(t.TempDir(), "notes.unknownext"), Options{}); err == nil {
+		t.Fatal("expected unsupported language to fail")
+	}
+}
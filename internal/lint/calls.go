@@ -0,0 +1,165 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// noCallRulePattern matches: no call to <name|pkg.Name|/regex/> [in package <glob>]
+var noCallRulePattern = regexp.MustCompile(`^\s*(?i:no\s+call\s+to)\s+(\S+)(?:\s+(?i:in\s+package)\s+(\S+))?\s*$`)
+
+func parseNoCallRule(raw, text string, matches []string) (Rule, error) {
+	target := strings.Trim(matches[1], `"'`)
+	rule := Rule{
+		ID:      "no-call:" + target,
+		Raw:     text,
+		Type:    "no_call",
+		Pattern: target,
+		Package: matches[2],
+	}
+	if len(target) > 2 && strings.HasPrefix(target, "/") && strings.HasSuffix(target, "/") {
+		compiled, err := regexp.Compile(target[1 : len(target)-1])
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid call pattern in rule %q: %w", raw, err)
+		}
+		rule.namePattern = compiled
+	}
+	if rule.Package != "" {
+		rule.ID += ":" + rule.Package
+	}
+	return rule, nil
+}
+
+// noCallViolations reports call references matching the rule. Each call is
+// matched by its bare name, its source qualifier (ioutil.ReadAll), and the
+// import path that qualifier resolves to (io/ioutil.ReadAll).
+func noCallViolations(idx *model.Index, rule Rule) []Violation {
+	matcher := rule.namePattern
+	if matcher == nil && strings.HasPrefix(rule.Pattern, "/") && strings.HasSuffix(rule.Pattern, "/") && len(rule.Pattern) > 2 {
+		var err error
+		if matcher, err = regexp.Compile(rule.Pattern[1 : len(rule.Pattern)-1]); err != nil {
+			return nil
+		}
+	}
+	matches := func(candidate string) bool {
+		if matcher != nil {
+			return matcher.MatchString(candidate)
+		}
+		return candidate == rule.Pattern
+	}
+
+	violations := make([]Violation, 0, 8)
+	for _, file := range idx.Files {
+		if rule.Package != "" && !packageMatches(rule.Package, file.Path) {
+			continue
+		}
+
+		var lines []string
+		for _, reference := range file.References {
+			if !strings.Contains(reference.Kind, "call") {
+				continue
+			}
+			qualifier := indexedCallQualifier(reference)
+			if qualifier == "" && lines == nil {
+				source, err := os.ReadFile(filepath.Join(idx.Root, filepath.FromSlash(file.Path)))
+				if err != nil {
+					lines = []string{}
+				} else {
+					lines = strings.Split(string(source), "\n")
+				}
+			}
+
+			display := reference.Name
+			candidates := []string{reference.Name}
+			if qualifier == "" {
+				qualifier = callQualifier(lines, reference)
+			}
+			if qualifier != "" {
+				display = qualifier + "." + reference.Name
+				candidates = append(candidates, display)
+				for _, imp := range file.Imports {
+					for _, name := range importNameCandidates(imp) {
+						if name == qualifier {
+							candidates = append(candidates, imp+"."+reference.Name)
+						}
+					}
+				}
+			}
+
+			matched := false
+			for _, candidate := range candidates {
+				if matches(candidate) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+
+			violations = append(violations, Violation{
+				RuleID:    rule.ID,
+				File:      file.Path,
+				Kind:      reference.Kind,
+				Name:      display,
+				StartLine: reference.StartLine,
+				EndLine:   reference.EndLine,
+				Span:      1,
+				Message:   fmt.Sprintf("call to %s is forbidden by rule", display),
+			})
+		}
+	}
+	return violations
+}
+
+// indexedCallQualifier returns the last identifier of the qualifier the
+// parser recorded for a dotted call, e.g. "time" for time.Sleep.
+func indexedCallQualifier(reference model.Reference) string {
+	qualifier := reference.Qualifier
+	if qualifier == "" || strings.Contains(qualifier, "::") || strings.Contains(qualifier, "->") {
+		return ""
+	}
+	if idx := strings.LastIndex(qualifier, "."); idx >= 0 {
+		qualifier = qualifier[idx+1:]
+	}
+	return qualifier
+}
+
+// callQualifier returns the identifier immediately before a "." preceding the
+// reference, e.g. "time" for the Sleep in time.Sleep(...).
+func callQualifier(lines []string, reference model.Reference) string {
+	if reference.StartLine <= 0 || reference.StartLine > len(lines) || reference.StartColumn <= 1 {
+		return ""
+	}
+	line := lines[reference.StartLine-1]
+	end := reference.StartColumn - 1
+	if end > len(line) {
+		return ""
+	}
+	prefix := strings.TrimRight(line[:end], " \t")
+	if !strings.HasSuffix(prefix, ".") {
+		return ""
+	}
+	prefix = strings.TrimRight(prefix[:len(prefix)-1], " \t")
+	start := len(prefix)
+	for start > 0 && isIdentByte(prefix[start-1]) {
+		start--
+	}
+	return prefix[start:]
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// packageMatches reports whether the file's directory matches pattern, either
+// as a package glob (pkg/*) or by its last path element (handlers).
+func packageMatches(pattern, filePath string) bool {
+	dir := filepath.ToSlash(filepath.Dir(filePath))
+	return matchPkgGlob(pattern, dir) || filepath.Base(dir) == pattern
+}
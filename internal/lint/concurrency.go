@@ -0,0 +1,82 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/odvcencio/gts-suite/internal/concurrency"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// noConcurrencyRulePattern matches: no <primitive> [in package <glob>], where
+// primitive is a Go concurrency primitive such as "go statement" or "mutex".
+var noConcurrencyRulePattern = regexp.MustCompile(`^\s*(?i:no)\s+(.+?)(?:\s+(?i:in\s+package)\s+(\S+))?\s*$`)
+
+// parseNoConcurrencyRule reports false when the primitive is not one
+// concurrency.ParseKind recognizes, so other "no ..." rules can match.
+func parseNoConcurrencyRule(text string, matches []string) (Rule, bool) {
+	kind, ok := concurrency.ParseKind(matches[1])
+	if !ok {
+		return Rule{}, false
+	}
+	rule := Rule{
+		ID:      "no-concurrency:" + kind,
+		Raw:     text,
+		Type:    "no_concurrency",
+		Pattern: kind,
+		Package: matches[2],
+	}
+	if rule.Package != "" {
+		rule.ID += ":" + rule.Package
+	}
+	return rule, true
+}
+
+var concurrencyLabels = map[string]string{
+	concurrency.KindGo:        "go statement",
+	concurrency.KindChanMake:  "channel make",
+	concurrency.KindChanSend:  "channel send",
+	concurrency.KindMutex:     "mutex",
+	concurrency.KindWaitGroup: "WaitGroup",
+}
+
+// noConcurrencyViolations reports the Go concurrency sites of the rule's kind.
+// sites is extracted once per evaluation and shared between rules.
+func noConcurrencyViolations(idx *model.Index, rule Rule, sites *[]concurrency.Site) []Violation {
+	if *sites == nil {
+		extracted, err := concurrency.Extract(idx)
+		if err != nil {
+			return nil
+		}
+		if extracted == nil {
+			extracted = []concurrency.Site{}
+		}
+		*sites = extracted
+	}
+
+	label := concurrencyLabels[rule.Pattern]
+	violations := make([]Violation, 0, 8)
+	for _, site := range *sites {
+		if site.Kind != rule.Pattern {
+			continue
+		}
+		if rule.Package != "" && !packageMatches(rule.Package, site.File) {
+			continue
+		}
+		message := fmt.Sprintf("%s (%s) is forbidden by rule", label, site.Detail)
+		if rule.Package != "" {
+			message = fmt.Sprintf("%s (%s) is forbidden in package %s", label, site.Detail, rule.Package)
+		}
+		violations = append(violations, Violation{
+			RuleID:    rule.ID,
+			File:      site.File,
+			Kind:      site.Kind,
+			Name:      site.Symbol,
+			StartLine: site.Line,
+			EndLine:   site.Line,
+			Span:      1,
+			Message:   message,
+		})
+	}
+	return violations
+}
@@ -51,6 +51,7 @@ type Config struct {
 	Ignores      []ConfigIgnore   `json:"ignores,omitempty"`
 	PackageRules []PackageRule    `json:"package_rules,omitempty"`
 	LicenseRules []LicenseRule    `json:"license_rules,omitempty"`
+	Rules        []Rule           `json:"rules,omitempty"`
 }
 
 // packageThresholdPattern matches lines like: package import_depth > 5 -> error "dependency chain too deep"
@@ -229,6 +230,36 @@ func ParseConfig(content string) (*Config, error) {
 			continue
 		}
 
+		// Naming rules: naming [exported|unexported] kind regex [for language]
+		if namingRulePattern.MatchString(line) {
+			rule, err := ParseRule(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo+1, err)
+			}
+			cfg.Rules = append(cfg.Rules, rule)
+			continue
+		}
+
+		// Selector rules: no <selector>
+		if noMatchRulePattern.MatchString(line) {
+			rule, err := ParseRule(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo+1, err)
+			}
+			cfg.Rules = append(cfg.Rules, rule)
+			continue
+		}
+
+		// Go error-handling rules: no swallowed|ignored|unwrapped errors
+		if errorRulePattern.MatchString(line) {
+			rule, err := ParseRule(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo+1, err)
+			}
+			cfg.Rules = append(cfg.Rules, rule)
+			continue
+		}
+
 		if m := ignorePattern.FindStringSubmatch(line); m != nil {
 			metric := strings.ToLower(m[1])
 			target := m[2]
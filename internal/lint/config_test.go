@@ -582,3 +582,22 @@ ignore lines in big_test.go
 		t.Errorf("expected 1 ignore, got %d", len(cfg.Ignores))
 	}
 }
+
+func TestParseConfig_NamingRules(t *testing.T) {
+	content := `naming unexported function ^[a-z][A-Za-z0-9]*$ for go
+naming type ^[A-Z]
+`
+	cfg, err := ParseConfig(content)
+	if err != nil {
+		t.Fatalf("ParseConfig returned error: %v", err)
+	}
+	if len(cfg.Rules) != 2 {
+		t.Fatalf("expected 2 naming rules, got %d", len(cfg.Rules))
+	}
+	if cfg.Rules[0].Visibility != "unexported" || cfg.Rules[0].Language != "go" {
+		t.Errorf("unexpected rule[0] %+v", cfg.Rules[0])
+	}
+	if cfg.Rules[1].Kind != "type_definition" || cfg.Rules[1].Pattern != "^[A-Z]" {
+		t.Errorf("unexpected rule[1] %+v", cfg.Rules[1])
+	}
+}
@@ -0,0 +1,335 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/refactor"
+)
+
+// errorRulePattern matches: no swallowed errors | no ignored errors | no unwrapped errors
+var errorRulePattern = regexp.MustCompile(`(?i)^\s*no\s+(swallowed|ignored|unwrapped)\s+errors?\s*$`)
+
+func parseErrorRule(text string, matches []string) Rule {
+	kind := strings.ToLower(matches[1])
+	return Rule{
+		ID:   kind + "-error",
+		Raw:  text,
+		Type: kind + "_error",
+	}
+}
+
+// knownErrorFuncs are standard library functions and methods whose last
+// result is an error, for calls the index cannot resolve.
+var knownErrorFuncs = map[string]bool{
+	"os.Chdir": true, "os.Chmod": true, "os.Chown": true, "os.Mkdir": true, "os.MkdirAll": true,
+	"os.Remove": true, "os.RemoveAll": true, "os.Rename": true, "os.Setenv": true, "os.Symlink": true,
+	"os.Truncate": true, "os.Unsetenv": true, "os.WriteFile": true,
+	"json.Unmarshal": true, "xml.Unmarshal": true, "http.ListenAndServe": true,
+	".Close": true, ".Flush": true, ".Sync": true, ".Shutdown": true,
+}
+
+// goErrorViolations reports the Go error-handling problems a rule targets:
+//
+//   - swallowed_error: a return of only nil values inside "if err != nil"
+//     that never uses err, in a function returning an error.
+//   - ignored_error: "_ = f()" and "x, _ := f()" discarding the error result
+//     of a function declared in the index or a well-known library function.
+//   - unwrapped_error: fmt.Errorf given an error argument without %w. The
+//     suggested fix turns the verb formatting the error into %w.
+//
+// Errors are recognized by name (err, or ending in Err or Error), since files
+// are parsed without type information.
+func goErrorViolations(idx *model.Index, rule Rule) []Violation {
+	type parsedFile struct {
+		path string
+		fset *token.FileSet
+		file *ast.File
+	}
+	var files []parsedFile
+	for _, file := range idx.Files {
+		if !strings.EqualFold(file.Language, "go") && !strings.HasSuffix(file.Path, ".go") {
+			continue
+		}
+		source, err := os.ReadFile(filepath.Join(idx.Root, filepath.FromSlash(file.Path)))
+		if err != nil {
+			continue
+		}
+		fset := token.NewFileSet()
+		parsed, err := parser.ParseFile(fset, file.Path, source, 0)
+		if err != nil {
+			continue
+		}
+		files = append(files, parsedFile{path: file.Path, fset: fset, file: parsed})
+	}
+
+	var errorFuncs map[string]bool
+	if rule.Type == "ignored_error" {
+		errorFuncs = map[string]bool{}
+		for name, returnsError := range knownErrorFuncs {
+			errorFuncs[name] = returnsError
+		}
+		for _, file := range files {
+			collectErrorFuncs(file.file, errorFuncs)
+		}
+	}
+
+	violations := make([]Violation, 0, 8)
+	for _, file := range files {
+		add := func(node ast.Node, name, message string, fixes []refactor.Edit) {
+			start := file.fset.Position(node.Pos())
+			end := file.fset.Position(node.End())
+			violations = append(violations, Violation{
+				RuleID:    rule.ID,
+				File:      file.path,
+				Kind:      rule.Type,
+				Name:      name,
+				StartLine: start.Line,
+				EndLine:   end.Line,
+				Span:      end.Line - start.Line + 1,
+				Message:   message,
+				Fixes:     fixes,
+			})
+		}
+
+		switch rule.Type {
+		case "swallowed_error":
+			for _, decl := range file.file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Body == nil || !returnsError(fn.Type) {
+					continue
+				}
+				ast.Inspect(fn.Body, func(node ast.Node) bool {
+					if lit, ok := node.(*ast.FuncLit); ok {
+						return returnsError(lit.Type)
+					}
+					stmt, ok := node.(*ast.IfStmt)
+					if !ok {
+						return true
+					}
+					errName := nonNilCheck(stmt.Cond)
+					if errName == "" || mentions(stmt.Body, errName) {
+						return true
+					}
+					for _, bodyStmt := range stmt.Body.List {
+						if ret, ok := bodyStmt.(*ast.ReturnStmt); ok && allNil(ret.Results) {
+							add(ret, fn.Name.Name, fmt.Sprintf("%s is checked but dropped: %q returns nil", errName, fn.Name.Name), nil)
+						}
+					}
+					return true
+				})
+			}
+		case "ignored_error":
+			ast.Inspect(file.file, func(node ast.Node) bool {
+				assign, ok := node.(*ast.AssignStmt)
+				if !ok || len(assign.Rhs) != 1 || len(assign.Lhs) == 0 {
+					return true
+				}
+				call, ok := assign.Rhs[0].(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				if last, ok := assign.Lhs[len(assign.Lhs)-1].(*ast.Ident); !ok || last.Name != "_" {
+					return true
+				}
+				name, returnsError := callReturnsError(call, file.file.Name.Name, errorFuncs)
+				if !returnsError {
+					return true
+				}
+				add(assign, name, fmt.Sprintf("error returned by %s is discarded", name), nil)
+				return true
+			})
+		case "unwrapped_error":
+			ast.Inspect(file.file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok || len(call.Args) < 2 || !isSelector(call.Fun, "fmt", "Errorf") {
+					return true
+				}
+				format, ok := call.Args[0].(*ast.BasicLit)
+				if !ok || format.Kind != token.STRING {
+					return true
+				}
+				text, err := strconv.Unquote(format.Value)
+				if err != nil || strings.Contains(text, "%w") {
+					return true
+				}
+				for i, arg := range call.Args[1:] {
+					ident, ok := arg.(*ast.Ident)
+					if !ok || !isErrorName(ident.Name) {
+						continue
+					}
+					var fixes []refactor.Edit
+					if fixed, ok := wrapVerb(text, i); ok {
+						fixes = []refactor.Edit{{
+							File:     file.path,
+							Kind:     "call",
+							Category: "wrap_error",
+							OldName:  format.Value,
+							NewName:  strconv.Quote(fixed),
+							Line:     file.fset.Position(format.Pos()).Line,
+							Column:   file.fset.Position(format.Pos()).Column,
+							Offset:   file.fset.Position(format.Pos()).Offset,
+						}}
+						if strings.HasPrefix(format.Value, "`") && !strings.Contains(fixed, "`") {
+							fixes[0].NewName = "`" + fixed + "`"
+						}
+					}
+					add(call, "fmt.Errorf", fmt.Sprintf("fmt.Errorf formats %s without %%w, so callers cannot unwrap it", ident.Name), fixes)
+					break
+				}
+				return true
+			})
+		}
+	}
+	return violations
+}
+
+// collectErrorFuncs records whether each declared function ("pkg.Name") and
+// method (".Name") returns an error last. A method name declared both ways is
+// marked false, since the receiver type is unknown at the call site.
+func collectErrorFuncs(file *ast.File, errorFuncs map[string]bool) {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		key := file.Name.Name + "." + fn.Name.Name
+		if fn.Recv != nil {
+			key = "." + fn.Name.Name
+		}
+		returns := returnsError(fn.Type)
+		if previous, seen := errorFuncs[key]; seen && previous != returns {
+			returns = false
+		}
+		errorFuncs[key] = returns
+		if fn.Recv == nil {
+			// Unqualified calls from within the declaring package.
+			local := file.Name.Name + ":" + fn.Name.Name
+			errorFuncs[local] = returns
+		}
+	}
+}
+
+// callReturnsError resolves a call made in package pkg against the known
+// error functions and returns its display name.
+func callReturnsError(call *ast.CallExpr, pkg string, errorFuncs map[string]bool) (string, bool) {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name, errorFuncs[pkg+":"+fun.Name]
+	case *ast.SelectorExpr:
+		if qualifier, ok := fun.X.(*ast.Ident); ok && qualifier.Obj == nil {
+			if returns, ok := errorFuncs[qualifier.Name+"."+fun.Sel.Name]; ok {
+				return qualifier.Name + "." + fun.Sel.Name, returns
+			}
+		}
+		return fun.Sel.Name, errorFuncs["."+fun.Sel.Name]
+	}
+	return "", false
+}
+
+func returnsError(fnType *ast.FuncType) bool {
+	if fnType == nil || fnType.Results == nil || len(fnType.Results.List) == 0 {
+		return false
+	}
+	last, ok := fnType.Results.List[len(fnType.Results.List)-1].Type.(*ast.Ident)
+	return ok && last.Name == "error"
+}
+
+// nonNilCheck returns the error name in an "err != nil" condition.
+func nonNilCheck(cond ast.Expr) string {
+	binary, ok := cond.(*ast.BinaryExpr)
+	if !ok || binary.Op != token.NEQ {
+		return ""
+	}
+	left, right := binary.X, binary.Y
+	if ident, ok := left.(*ast.Ident); ok && ident.Name == "nil" {
+		left, right = right, left
+	}
+	ident, ok := left.(*ast.Ident)
+	if !ok || !isErrorName(ident.Name) {
+		return ""
+	}
+	if nilIdent, ok := right.(*ast.Ident); !ok || nilIdent.Name != "nil" {
+		return ""
+	}
+	return ident.Name
+}
+
+func isErrorName(name string) bool {
+	return name == "err" || strings.HasSuffix(name, "Err") || strings.HasSuffix(name, "Error") || strings.HasSuffix(name, "err")
+}
+
+func mentions(node ast.Node, name string) bool {
+	found := false
+	ast.Inspect(node, func(child ast.Node) bool {
+		if ident, ok := child.(*ast.Ident); ok && ident.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func allNil(results []ast.Expr) bool {
+	if len(results) == 0 {
+		return false
+	}
+	for _, result := range results {
+		if ident, ok := result.(*ast.Ident); !ok || ident.Name != "nil" {
+			return false
+		}
+	}
+	return true
+}
+
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != name {
+		return false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	return ok && ident.Name == pkg
+}
+
+// wrapVerb replaces the %v or %s verb consuming argument arg of format with
+// %w. It reports false when that verb is anything else or the format uses
+// explicit argument indexes or * widths.
+func wrapVerb(format string, arg int) (string, bool) {
+	if strings.Contains(format, "%[") || strings.Contains(format, "*") {
+		return "", false
+	}
+	verb := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.", format[j]) >= 0 {
+			j++
+		}
+		if j >= len(format) {
+			return "", false
+		}
+		if format[j] == '%' {
+			i = j
+			continue
+		}
+		if verb == arg {
+			if j != i+1 || (format[j] != 'v' && format[j] != 's') {
+				return "", false
+			}
+			return format[:i] + "%w" + format[j+1:], true
+		}
+		verb++
+		i = j
+	}
+	return "", false
+}
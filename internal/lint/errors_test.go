@@ -0,0 +1,134 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+const errorRulesSource = `package sample
+
+import (
+	"fmt"
+	"os"
+)
+
+func load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	if err := parse(data); err != nil {
+		return fmt.Errorf("parse %s: %v", path, err)
+	}
+	return nil
+}
+
+func parse(data []byte) error {
+	if _, err := os.Stat("x"); err != nil {
+		fmt.Println("stat failed:", err)
+		return nil
+	}
+	return fmt.Errorf("bad data: %w", os.ErrInvalid)
+}
+
+func cleanup() {
+	_ = os.Remove("tmp")
+	_ = parse(nil)
+	n, _ := fmt.Println("done")
+	_ = n
+}
+`
+
+func TestGoErrorRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "sample.go"), []byte(errorRulesSource), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+
+	cases := []struct {
+		rule  string
+		lines []int
+		names []string
+	}{
+		{rule: "no swallowed errors", lines: []int{11}, names: []string{"load"}},
+		{rule: "no ignored errors", lines: []int{28, 29}, names: []string{"os.Remove", "parse"}},
+		{rule: "no unwrapped errors", lines: []int{14}, names: []string{"fmt.Errorf"}},
+	}
+	for _, tc := range cases {
+		rule, err := ParseRule(tc.rule)
+		if err != nil {
+			t.Fatalf("ParseRule(%q) returned error: %v", tc.rule, err)
+		}
+		violations := Evaluate(idx, []Rule{rule})
+		if len(violations) != len(tc.lines) {
+			t.Fatalf("%q: expected %d violations, got %+v", tc.rule, len(tc.lines), violations)
+		}
+		for i, violation := range violations {
+			if violation.StartLine != tc.lines[i] || violation.Name != tc.names[i] {
+				t.Fatalf("%q: unexpected violation %+v", tc.rule, violation)
+			}
+		}
+	}
+}
+
+func TestParseConfig_ErrorRules(t *testing.T) {
+	cfg, err := ParseConfig("no swallowed errors\nno unwrapped errors\n")
+	if err != nil {
+		t.Fatalf("ParseConfig returned error: %v", err)
+	}
+	if len(cfg.Rules) != 2 || cfg.Rules[0].ID != "swallowed-error" || cfg.Rules[1].Type != "unwrapped_error" {
+		t.Fatalf("unexpected rules %+v", cfg.Rules)
+	}
+}
+
+func TestUnwrappedErrorFix(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "sample.go")
+	if err := os.WriteFile(path, []byte(errorRulesSource), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	rule, _ := ParseRule("no unwrapped errors")
+	if _, err := ApplyFixes(tmpDir, Evaluate(idx, []Rule{rule}), true); err != nil {
+		t.Fatalf("ApplyFixes returned error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !strings.Contains(string(data), `fmt.Errorf("parse %s: %w", path, err)`) {
+		t.Fatalf("expected the error verb rewritten to %%w, got:\n%s", data)
+	}
+}
+
+func TestWrapVerb(t *testing.T) {
+	cases := []struct {
+		format string
+		arg    int
+		want   string
+		ok     bool
+	}{
+		{format: "read %s: %v", arg: 1, want: "read %s: %w", ok: true},
+		{format: "100%% failed: %s", arg: 0, want: "100%% failed: %w", ok: true},
+		{format: "code %d: %+v", arg: 1, ok: false},
+		{format: "%*d %v", arg: 1, ok: false},
+		{format: "missing verb", arg: 0, ok: false},
+	}
+	for _, tc := range cases {
+		got, ok := wrapVerb(tc.format, tc.arg)
+		if ok != tc.ok || got != tc.want {
+			t.Fatalf("wrapVerb(%q, %d) = %q, %v; want %q, %v", tc.format, tc.arg, got, ok, tc.want, tc.ok)
+		}
+	}
+}
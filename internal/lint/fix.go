@@ -0,0 +1,213 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/refactor"
+)
+
+// diffContext is the number of unchanged lines shown around each fix hunk.
+const diffContext = 3
+
+// FileFix summarizes the fixes for one file.
+type FileFix struct {
+	File    string `json:"file"`
+	Edits   int    `json:"edits"`
+	Skipped int    `json:"skipped,omitempty"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// FixReport summarizes fixes applied, or previewed when Write is false.
+type FixReport struct {
+	Write   bool      `json:"write"`
+	Files   []FileFix `json:"files,omitempty"`
+	Applied int       `json:"applied"`
+	Skipped int       `json:"skipped"`
+}
+
+// ApplyFixes applies the suggested fixes attached to violations. Edits that
+// overlap an earlier edit in the same file are skipped. With write false the
+// files are left untouched and each FileFix carries a unified diff preview;
+// with write true every changed file is written, or none is.
+func ApplyFixes(root string, violations []Violation, write bool) (FixReport, error) {
+	report := FixReport{Write: write}
+
+	byFile := map[string][]refactor.Edit{}
+	for _, violation := range violations {
+		for _, edit := range violation.Fixes {
+			file := edit.File
+			if file == "" {
+				file = violation.File
+				edit.File = file
+			}
+			byFile[file] = append(byFile[file], edit)
+		}
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	updatedByPath := map[string][]byte{}
+	for _, file := range files {
+		edits, skipped := nonOverlappingEdits(byFile[file])
+		absPath := filepath.Join(root, filepath.FromSlash(file))
+		source, err := os.ReadFile(absPath)
+		if err != nil {
+			return report, err
+		}
+		updated, applied, err := refactor.ApplyEdits(source, edits)
+		if err != nil {
+			return report, fmt.Errorf("fix %s: %w", file, err)
+		}
+
+		fileFix := FileFix{File: file, Edits: applied, Skipped: skipped}
+		if write {
+			if !bytes.Equal(updated, source) {
+				updatedByPath[absPath] = updated
+			}
+		} else {
+			fileFix.Diff = renderFixDiff(file, source, edits)
+		}
+		report.Files = append(report.Files, fileFix)
+		report.Applied += applied
+		report.Skipped += skipped
+	}
+	if len(updatedByPath) > 0 {
+		if err := refactor.WriteFiles(updatedByPath); err != nil {
+			return FixReport{Write: write}, err
+		}
+	}
+	return report, nil
+}
+
+func nonOverlappingEdits(edits []refactor.Edit) ([]refactor.Edit, int) {
+	sorted := append([]refactor.Edit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+
+	kept := make([]refactor.Edit, 0, len(sorted))
+	skipped := 0
+	end := -1
+	for _, edit := range sorted {
+		if edit.Offset < end {
+			skipped++
+			continue
+		}
+		if len(kept) > 0 && edit.Offset == kept[len(kept)-1].Offset && edit.OldName == "" && kept[len(kept)-1].OldName == "" {
+			skipped++
+			continue
+		}
+		kept = append(kept, edit)
+		end = edit.Offset + len(edit.OldName)
+	}
+	return kept, skipped
+}
+
+// renderFixDiff renders a unified diff of edits against source. Hunks are
+// derived from the edit offsets, so only the touched lines are compared.
+func renderFixDiff(file string, source []byte, edits []refactor.Edit) string {
+	if len(edits) == 0 {
+		return ""
+	}
+	lines := splitKeepNewline(source)
+	lineStarts := make([]int, len(lines)+1)
+	for i, line := range lines {
+		lineStarts[i+1] = lineStarts[i] + len(line)
+	}
+	lineOf := func(offset int) int {
+		idx := sort.Search(len(lines), func(i int) bool { return lineStarts[i+1] > offset })
+		if idx >= len(lines) {
+			idx = len(lines) - 1
+		}
+		return idx
+	}
+
+	type hunk struct {
+		first, last int
+		edits       []refactor.Edit
+	}
+	hunks := make([]hunk, 0, len(edits))
+	for _, edit := range edits {
+		first := lineOf(edit.Offset)
+		last := first
+		if len(edit.OldName) > 0 {
+			last = lineOf(edit.Offset + len(edit.OldName) - 1)
+		}
+		first = max(first-diffContext, 0)
+		last = min(last+diffContext, len(lines)-1)
+		if n := len(hunks); n > 0 && first <= hunks[n-1].last+1 {
+			hunks[n-1].last = max(hunks[n-1].last, last)
+			hunks[n-1].edits = append(hunks[n-1].edits, edit)
+			continue
+		}
+		hunks = append(hunks, hunk{first: first, last: last, edits: []refactor.Edit{edit}})
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", file, file)
+	delta := 0
+	for _, h := range hunks {
+		regionStart := lineStarts[h.first]
+		oldText := source[regionStart:lineStarts[h.last+1]]
+		shifted := make([]refactor.Edit, 0, len(h.edits))
+		for _, edit := range h.edits {
+			edit.Offset -= regionStart
+			shifted = append(shifted, edit)
+		}
+		newText, _, err := refactor.ApplyEdits(oldText, shifted)
+		if err != nil {
+			continue
+		}
+		oldLines := splitKeepNewline(oldText)
+		newLines := splitKeepNewline(newText)
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", h.first+1, len(oldLines), h.first+1+delta, len(newLines))
+		prefix := 0
+		for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+			prefix++
+		}
+		suffix := 0
+		for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+			oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+			suffix++
+		}
+		writeDiffLines(&out, " ", oldLines[:prefix])
+		writeDiffLines(&out, "-", oldLines[prefix:len(oldLines)-suffix])
+		writeDiffLines(&out, "+", newLines[prefix:len(newLines)-suffix])
+		writeDiffLines(&out, " ", oldLines[len(oldLines)-suffix:])
+		delta += len(newLines) - len(oldLines)
+	}
+	return out.String()
+}
+
+func splitKeepNewline(text []byte) []string {
+	if len(text) == 0 {
+		return nil
+	}
+	parts := bytes.SplitAfter(text, []byte("\n"))
+	if len(parts[len(parts)-1]) == 0 {
+		parts = parts[:len(parts)-1]
+	}
+	lines := make([]string, len(parts))
+	for i, part := range parts {
+		lines[i] = string(part)
+	}
+	return lines
+}
+
+func writeDiffLines(out *strings.Builder, prefix string, lines []string) {
+	for _, line := range lines {
+		out.WriteString(prefix)
+		out.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+func TestUnusedImportRuleSuggestsRemoval(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package sample
+
+import (
+	"fmt"
+	"os" // unused
+	yaml "gopkg.in/yaml.v3"
+)
+
+func A() {
+	fmt.Println("ok")
+}
+`
+	path := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	rule, err := ParseRule("no unused imports")
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+
+	violations := Evaluate(idx, []Rule{rule})
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 unused imports, got %#v", violations)
+	}
+	for _, violation := range violations {
+		if violation.Name == "fmt" || len(violation.Fixes) != 1 {
+			t.Fatalf("unexpected violation %#v", violation)
+		}
+	}
+
+	preview, err := ApplyFixes(tmpDir, violations, false)
+	if err != nil {
+		t.Fatalf("ApplyFixes dry run returned error: %v", err)
+	}
+	if preview.Applied != 2 || len(preview.Files) != 1 {
+		t.Fatalf("unexpected preview %#v", preview)
+	}
+	diff := preview.Files[0].Diff
+	for _, want := range []string{"--- a/main.go", "@@ -2,8 +2,6 @@", "-\t\"os\" // unused", "-\tyaml \"gopkg.in/yaml.v3\"", " \t\"fmt\""} {
+		if !strings.Contains(diff, want) {
+			t.Fatalf("expected diff to contain %q, got:\n%s", want, diff)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != source {
+		t.Fatal("dry run must not modify the file")
+	}
+
+	if _, err := ApplyFixes(tmpDir, violations, true); err != nil {
+		t.Fatalf("ApplyFixes returned error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), `"os"`) || strings.Contains(string(data), "yaml") {
+		t.Fatalf("expected unused imports removed, got:\n%s", data)
+	}
+}
+
+func TestApplyFixesWritesAllFilesOrNone(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := "package sample\n\nimport \"os\"\n\nfunc A() {}\n"
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	rule, err := ParseRule("no unused imports")
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+	violations := Evaluate(idx, []Rule{rule})
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 unused imports, got %#v", violations)
+	}
+
+	// A non-empty directory where b.go's backup would go makes its commit
+	// fail after a.go has already been replaced.
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".b.go.gts-orig", "keep"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if _, err := ApplyFixes(tmpDir, violations, true); err == nil {
+		t.Fatal("expected ApplyFixes to fail")
+	}
+	for _, name := range []string{"a.go", "b.go"} {
+		if data, _ := os.ReadFile(filepath.Join(tmpDir, name)); string(data) != source {
+			t.Fatalf("expected %s to be left unchanged, got:\n%s", name, data)
+		}
+	}
+}
+
+func TestUnusedImportRuleSkipsAmbiguousImports(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package sample
+
+import "example.com/module/pkgname-differs"
+
+func A() {
+	realname.Do()
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	rule, _ := ParseRule("no unused imports")
+	if violations := Evaluate(idx, []Rule{rule}); len(violations) != 0 {
+		t.Fatalf("expected ambiguous import to be left alone, got %#v", violations)
+	}
+}
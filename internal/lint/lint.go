@@ -13,16 +13,23 @@ import (
 	"unicode/utf8"
 
 	"github.com/odvcencio/gotreesitter"
-	"github.com/odvcencio/gotreesitter/grammars"
 
+	"github.com/odvcencio/gts-suite/internal/concurrency"
 	"github.com/odvcencio/gts-suite/internal/deps"
+	"github.com/odvcencio/gts-suite/internal/parsesession"
 	"github.com/odvcencio/gts-suite/pkg/complexity"
 	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/query"
+	"github.com/odvcencio/gts-suite/pkg/refactor"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
 var maxLinesRulePattern = regexp.MustCompile(`(?i)^\s*no\s+([a-z_]+)s?\s+longer\s+than\s+(\d+)\s+lines?\s*$`)
 var noImportRulePattern = regexp.MustCompile(`(?i)^\s*no\s+import\s+(.+?)\s*$`)
+var unusedImportsRulePattern = regexp.MustCompile(`(?i)^\s*no\s+unused\s+imports?\s*$`)
+
+// namingRulePattern matches: naming [exported|unexported] <kind> <regex> [for <language>]
+var namingRulePattern = regexp.MustCompile(`^\s*(?i:naming)\s+(?:(?i:(exported|unexported))\s+)?(\S+)\s+("[^"]+"|\S+)(?:\s+(?i:for)\s+(\S+))?\s*$`)
 
 type Rule struct {
 	ID         string `json:"id"`
@@ -32,6 +39,15 @@ type Rule struct {
 	KindLabel  string `json:"kind_label,omitempty"`
 	MaxLines   int    `json:"max_lines,omitempty"`
 	ImportPath string `json:"import_path,omitempty"`
+	// Pattern is the name regex of a naming rule or the call matcher of a
+	// no_call rule. Visibility and Language further filter naming rules.
+	Pattern    string `json:"pattern,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+	Language   string `json:"language,omitempty"`
+	Package    string `json:"package,omitempty"`
+
+	namePattern *regexp.Regexp
+	selector    *query.Selector
 }
 
 type QueryPattern struct {
@@ -52,6 +68,8 @@ type Violation struct {
 	Message   string `json:"message"`
 	Severity  string `json:"severity,omitempty"`
 	Value     int    `json:"value,omitempty"`
+	// Fixes are suggested source edits that resolve the violation.
+	Fixes []refactor.Edit `json:"fixes,omitempty"`
 }
 
 // ThresholdRule expresses a simple metric > N threshold check.
@@ -79,11 +97,22 @@ var DefaultRules = []ThresholdRule{
 // It runs complexity analysis and xref graph building to gather per-function metrics,
 // then compares each metric against each rule's threshold.
 func EvaluateThresholds(idx *model.Index, rules []ThresholdRule) ([]Violation, error) {
+	return evaluateThresholds(idx, idx, rules)
+}
+
+// EvaluateThresholdsInFiles is EvaluateThresholds restricted to functions in
+// files. Complexity analysis only parses those files, while fan-in and fan-out
+// still come from the call graph of the whole index.
+func EvaluateThresholdsInFiles(idx *model.Index, rules []ThresholdRule, files map[string]bool) ([]Violation, error) {
+	return evaluateThresholds(idx, idx.FilterByPaths(files), rules)
+}
+
+func evaluateThresholds(idx, analyzed *model.Index, rules []ThresholdRule) ([]Violation, error) {
 	if idx == nil || len(rules) == 0 {
 		return nil, nil
 	}
 
-	report, err := complexity.Analyze(idx, idx.Root, complexity.Options{})
+	report, err := complexity.Analyze(analyzed, idx.Root, complexity.Options{})
 	if err != nil {
 		return nil, fmt.Errorf("complexity analysis: %w", err)
 	}
@@ -208,6 +237,36 @@ func ParseRule(raw string) (Rule, error) {
 		}, nil
 	}
 
+	if matches := namingRulePattern.FindStringSubmatch(text); matches != nil {
+		return parseNamingRule(raw, text, matches)
+	}
+
+	if matches := noCallRulePattern.FindStringSubmatch(text); matches != nil {
+		return parseNoCallRule(raw, text, matches)
+	}
+
+	if matches := errorRulePattern.FindStringSubmatch(text); matches != nil {
+		return parseErrorRule(text, matches), nil
+	}
+
+	if unusedImportsRulePattern.MatchString(text) {
+		return Rule{
+			ID:   "unused-import",
+			Raw:  text,
+			Type: "unused_import",
+		}, nil
+	}
+
+	if matches := noMatchRulePattern.FindStringSubmatch(text); matches != nil {
+		return parseNoMatchRule(raw, text, matches)
+	}
+
+	if matches := noConcurrencyRulePattern.FindStringSubmatch(text); matches != nil {
+		if rule, ok := parseNoConcurrencyRule(text, matches); ok {
+			return rule, nil
+		}
+	}
+
 	matches = noImportRulePattern.FindStringSubmatch(text)
 	if matches != nil {
 		importPath := strings.TrimSpace(matches[1])
@@ -225,6 +284,18 @@ func ParseRule(raw string) (Rule, error) {
 	return Rule{}, fmt.Errorf("unsupported rule %q", raw)
 }
 
+// ruleTargets reports whether a rule on symbols of ruleKind applies to a
+// symbol of kind. Function rules cover React components and hooks too.
+func ruleTargets(ruleKind, kind string) bool {
+	switch ruleKind {
+	case "*", kind:
+		return true
+	case "function_definition":
+		return model.IsFunctionKind(kind)
+	}
+	return false
+}
+
 func normalizeRuleKind(kind string) (string, string, error) {
 	switch strings.ToLower(strings.TrimSpace(kind)) {
 	case "function", "func", "function_definition":
@@ -233,6 +304,10 @@ func normalizeRuleKind(kind string) (string, string, error) {
 		return "method_definition", "method", nil
 	case "type", "type_definition":
 		return "type_definition", "type", nil
+	case "component", "component_definition":
+		return "component_definition", "component", nil
+	case "hook", "hook_definition":
+		return "hook_definition", "hook", nil
 	case "symbol", "any", "all", "*":
 		return "*", "symbol", nil
 	default:
@@ -250,7 +325,13 @@ func LoadQueryPattern(path string) (QueryPattern, error) {
 	if err != nil {
 		return QueryPattern{}, err
 	}
+	return ParseQueryPattern(cleaned, source)
+}
 
+// ParseQueryPattern builds a QueryPattern from .scm source. Leading
+// "; id:" and "; message:" comments override the default ID and message.
+func ParseQueryPattern(path string, source []byte) (QueryPattern, error) {
+	cleaned := strings.TrimSpace(path)
 	queryText := strings.TrimSpace(string(source))
 	if queryText == "" {
 		return QueryPattern{}, fmt.Errorf("pattern %q is empty", cleaned)
@@ -292,13 +373,14 @@ func Evaluate(idx *model.Index, rules []Rule) []Violation {
 	}
 
 	violations := make([]Violation, 0, 16)
+	var sites []concurrency.Site
 	for _, rule := range rules {
 		switch rule.Type {
 		case "max_lines":
 			for _, file := range idx.Files {
 				for _, symbol := range file.Symbols {
 					span := symbolSpan(symbol)
-					if rule.Kind != "*" && symbol.Kind != rule.Kind {
+					if !ruleTargets(rule.Kind, symbol.Kind) {
 						continue
 					}
 					if span <= rule.MaxLines {
@@ -332,6 +414,18 @@ func Evaluate(idx *model.Index, rules []Rule) []Violation {
 					})
 				}
 			}
+		case "unused_import":
+			violations = append(violations, unusedImportViolations(idx, rule)...)
+		case "naming":
+			violations = append(violations, namingViolations(idx, rule)...)
+		case "no_call":
+			violations = append(violations, noCallViolations(idx, rule)...)
+		case "swallowed_error", "ignored_error", "unwrapped_error":
+			violations = append(violations, goErrorViolations(idx, rule)...)
+		case "no_concurrency":
+			violations = append(violations, noConcurrencyViolations(idx, rule, &sites)...)
+		case "no_match":
+			violations = append(violations, noMatchViolations(idx, rule)...)
 		}
 	}
 
@@ -345,82 +439,30 @@ func EvaluatePatterns(idx *model.Index, patterns []QueryPattern) ([]Violation, e
 		return nil, nil
 	}
 
-	entriesByLanguage := map[string]grammars.LangEntry{}
-	for _, entry := range grammars.AllLanguages() {
-		if strings.TrimSpace(entry.Name) == "" || entry.Language == nil {
-			continue
-		}
-		entriesByLanguage[entry.Name] = entry
-	}
-
-	langByName := map[string]*gotreesitter.Language{}
-	parserByLanguage := map[string]*gotreesitter.Parser{}
-	queryByPatternLanguage := map[string]*gotreesitter.Query{}
-	queryCompileErr := map[string]bool{}
-
+	session := parsesession.New()
 	violations := make([]Violation, 0, 32)
 	for _, file := range idx.Files {
-		entry, ok := entriesByLanguage[file.Language]
-		if !ok {
+		if _, ok := session.Language(file.Language); !ok {
 			continue
 		}
 
-		lang, ok := langByName[file.Language]
-		if !ok {
-			lang = entry.Language()
-			if lang == nil {
-				continue
-			}
-			langByName[file.Language] = lang
-		}
-
 		sourcePath := filepath.Join(idx.Root, filepath.FromSlash(file.Path))
 		source, err := os.ReadFile(sourcePath)
 		if err != nil {
 			return nil, err
 		}
 
-		parser, ok := parserByLanguage[file.Language]
-		if !ok {
-			parser = gotreesitter.NewParser(lang)
-			parserByLanguage[file.Language] = parser
-		}
-
-		var tree *gotreesitter.Tree
-		var parseErr error
-		if entry.TokenSourceFactory != nil {
-			tokenSource := entry.TokenSourceFactory(source, lang)
-			if tokenSource != nil {
-				tree, parseErr = parser.ParseWithTokenSource(source, tokenSource)
-			}
-		}
-		if tree == nil && parseErr == nil {
-			tree, parseErr = parser.Parse(source)
-		}
+		tree, parseErr := session.Parse(file.Language, source)
 		if parseErr != nil {
 			continue
 		}
-		if tree == nil || tree.RootNode() == nil {
-			continue
-		}
 
 		for _, pattern := range patterns {
-			key := pattern.ID + "\x00" + file.Language
-			if queryCompileErr[key] {
+			compiled, err := session.Query(file.Language, pattern.Query)
+			if err != nil {
 				continue
 			}
 
-			compiled := queryByPatternLanguage[key]
-			if compiled == nil {
-				query, err := gotreesitter.NewQuery(pattern.Query, lang)
-				if err != nil {
-					queryCompileErr[key] = true
-					continue
-				}
-				queryByPatternLanguage[key] = query
-				compiled = query
-			}
-
 			matches := compiled.Execute(tree)
 			for _, match := range matches {
 				captureName, node := pickViolationCapture(match.Captures)
@@ -591,12 +633,13 @@ func groupByPackage(idx *model.Index) map[string][]model.FileSummary {
 	return result
 }
 
-// countExportedSymbols counts symbols with exported names (uppercase first letter).
+// countExportedSymbols counts exported symbols, falling back to the Go
+// uppercase-name convention for indexes without recorded visibility.
 func countExportedSymbols(files []model.FileSummary) int {
 	count := 0
 	for _, file := range files {
 		for _, sym := range file.Symbols {
-			if isExported(sym.Name) {
+			if sym.Exported || (sym.Visibility == "" && isExported(sym.Name)) {
 				count++
 			}
 		}
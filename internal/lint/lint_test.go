@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/odvcencio/gts-suite/internal/deps"
+	"github.com/odvcencio/gts-suite/pkg/index"
 	"github.com/odvcencio/gts-suite/pkg/model"
 )
 
@@ -25,6 +26,23 @@ func TestParseRule_MaxLinesFunction(t *testing.T) {
 	}
 }
 
+func TestParseRule_ComponentAndHookKinds(t *testing.T) {
+	rule, err := ParseRule("no component longer than 200 lines")
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+	if rule.Kind != "component_definition" || rule.ID != "max-lines:component_definition:200" {
+		t.Fatalf("unexpected rule %+v", rule)
+	}
+	rule, err = ParseRule("naming hook ^use[A-Z] for tsx")
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+	if rule.Kind != "hook_definition" || rule.ID != "naming:hook:tsx" {
+		t.Fatalf("unexpected rule %+v", rule)
+	}
+}
+
 func TestParseRule_Unsupported(t *testing.T) {
 	_, err := ParseRule("ban globals")
 	if err == nil {
@@ -411,3 +429,277 @@ func TestIsExported(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluate_NamingRules(t *testing.T) {
+	idx := &model.Index{
+		Files: []model.FileSummary{
+			{
+				Path:     "main.go",
+				Language: "go",
+				Symbols: []model.Symbol{
+					{File: "main.go", Kind: "function_definition", Name: "Serve_HTTP", StartLine: 3, EndLine: 5},
+					{File: "main.go", Kind: "function_definition", Name: "helper", StartLine: 7, EndLine: 9},
+					{File: "main.go", Kind: "type_definition", Name: "config", StartLine: 11, EndLine: 11},
+				},
+			},
+			{
+				Path:     "util.py",
+				Language: "python",
+				Symbols: []model.Symbol{
+					{File: "util.py", Kind: "function_definition", Name: "loadConfig", StartLine: 1, EndLine: 2},
+				},
+			},
+		},
+	}
+
+	exportedFuncs, err := ParseRule("naming exported function ^[A-Z][A-Za-z0-9]*$ for go")
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+	if exportedFuncs.Type != "naming" || exportedFuncs.Visibility != "exported" || exportedFuncs.Language != "go" {
+		t.Fatalf("unexpected naming rule %+v", exportedFuncs)
+	}
+	pythonFuncs, err := ParseRule(`naming function "^[a-z_][a-z0-9_]*$" for python`)
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+	types, err := ParseRule("naming type ^[A-Z]")
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+
+	violations := Evaluate(idx, []Rule{exportedFuncs, pythonFuncs, types})
+	got := map[string]string{}
+	for _, violation := range violations {
+		got[violation.Name] = violation.RuleID
+	}
+	want := map[string]string{
+		"Serve_HTTP": "naming:exported-function:go",
+		"loadConfig": "naming:function:python",
+		"config":     "naming:type",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %+v", want, violations)
+	}
+	for name, ruleID := range want {
+		if got[name] != ruleID {
+			t.Fatalf("expected %s flagged by %s, got %+v", name, ruleID, violations)
+		}
+	}
+
+	if _, err := ParseRule("naming function ([a-z"); err == nil {
+		t.Fatal("expected invalid naming regex to fail")
+	}
+}
+
+func TestEvaluate_NamingRulesUseRecordedVisibility(t *testing.T) {
+	idx := &model.Index{
+		Files: []model.FileSummary{
+			{
+				Path:     "Service.java",
+				Language: "java",
+				Symbols: []model.Symbol{
+					{File: "Service.java", Kind: "method_definition", Name: "Handle", Visibility: "private", StartLine: 3, EndLine: 5},
+					{File: "Service.java", Kind: "method_definition", Name: "run_all", Visibility: "public", Exported: true, StartLine: 7, EndLine: 9},
+				},
+			},
+		},
+	}
+
+	exported, err := ParseRule("naming exported method ^[a-z][A-Za-z0-9]*$ for java")
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+	unexported, err := ParseRule("naming unexported method ^_?[a-z][A-Za-z0-9]*$ for java")
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+
+	violations := Evaluate(idx, []Rule{exported, unexported})
+	got := map[string]string{}
+	for _, violation := range violations {
+		got[violation.Name] = violation.RuleID
+	}
+	// By name alone, neither method would count as unexported.
+	want := map[string]string{
+		"Handle":  "naming:unexported-method:java",
+		"run_all": "naming:exported-method:java",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %+v", want, violations)
+	}
+	for name, ruleID := range want {
+		if got[name] != ruleID {
+			t.Fatalf("expected %s flagged by %s, got %+v", name, ruleID, violations)
+		}
+	}
+}
+
+func TestEvaluate_NoCallRule(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "handlers"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	files := map[string]string{
+		"main.go": `package main
+
+import (
+	"io/ioutil"
+	"time"
+)
+
+func main() {
+	ioutil.ReadAll(nil)
+	time.Sleep(1)
+}
+`,
+		"handlers/h.go": `package handlers
+
+import "time"
+
+func Handle() {
+	time.Sleep(1)
+}
+`,
+	}
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+
+	cases := []struct {
+		rule  string
+		files []string
+	}{
+		{rule: "no call to ioutil.ReadAll", files: []string{"main.go"}},
+		{rule: "no call to io/ioutil.ReadAll", files: []string{"main.go"}},
+		{rule: "no call to time.Sleep in package handlers", files: []string{"handlers/h.go"}},
+		{rule: "no call to /^time\\./", files: []string{"handlers/h.go", "main.go"}},
+		{rule: "no call to ReadAll", files: []string{"main.go"}},
+		{rule: "no call to os.ReadFile", files: nil},
+	}
+	for _, tc := range cases {
+		rule, err := ParseRule(tc.rule)
+		if err != nil {
+			t.Fatalf("ParseRule(%q) returned error: %v", tc.rule, err)
+		}
+		violations := Evaluate(idx, []Rule{rule})
+		if len(violations) != len(tc.files) {
+			t.Fatalf("%q: expected %d violations, got %+v", tc.rule, len(tc.files), violations)
+		}
+		for i, violation := range violations {
+			if violation.File != tc.files[i] || violation.StartLine == 0 {
+				t.Fatalf("%q: unexpected violation %+v", tc.rule, violation)
+			}
+		}
+	}
+}
+
+func TestEvaluate_NoConcurrency(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "api", "handlers"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	files := map[string]string{
+		"main.go": `package main
+
+func main() {
+	go run()
+}
+`,
+		"api/handlers/h.go": `package handlers
+
+import "sync"
+
+var mu sync.Mutex
+
+func Handle(ch chan int) {
+	go func() { ch <- 1 }()
+}
+`,
+	}
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, filepath.FromSlash(name)), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+
+	cases := []struct {
+		rule  string
+		files []string
+	}{
+		{rule: "no go statement in package api/handlers", files: []string{"api/handlers/h.go"}},
+		{rule: "no goroutines", files: []string{"api/handlers/h.go", "main.go"}},
+		{rule: "no channel sends in package handlers", files: []string{"api/handlers/h.go"}},
+		{rule: "no mutexes in package api/*", files: []string{"api/handlers/h.go"}},
+		{rule: "no waitgroup", files: nil},
+	}
+	for _, tc := range cases {
+		rule, err := ParseRule(tc.rule)
+		if err != nil {
+			t.Fatalf("ParseRule(%q) returned error: %v", tc.rule, err)
+		}
+		if rule.Type != "no_concurrency" {
+			t.Fatalf("%q: unexpected rule type %q", tc.rule, rule.Type)
+		}
+		violations := Evaluate(idx, []Rule{rule})
+		if len(violations) != len(tc.files) {
+			t.Fatalf("%q: expected %d violations, got %+v", tc.rule, len(tc.files), violations)
+		}
+		for i, violation := range violations {
+			if violation.File != tc.files[i] || violation.StartLine == 0 {
+				t.Fatalf("%q: unexpected violation %+v", tc.rule, violation)
+			}
+		}
+	}
+
+	if rule, err := ParseRule("no import unsafe"); err != nil || rule.Type != "no_import" {
+		t.Fatalf("expected no import rule, got %+v (%v)", rule, err)
+	}
+}
+
+func TestEvaluate_NoMatchRule(t *testing.T) {
+	rule, err := ParseRule("no function_definition[attr=/@deprecated/]")
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+	if rule.Type != "no_match" || rule.ID != "no-match:function_definition[attr=/@deprecated/]" {
+		t.Fatalf("unexpected rule %+v", rule)
+	}
+
+	idx := &model.Index{
+		Files: []model.FileSummary{
+			{
+				Path: "app.py",
+				Symbols: []model.Symbol{
+					{File: "app.py", Kind: "function_definition", Name: "old", StartLine: 2, EndLine: 3, Attributes: []string{"@deprecated"}},
+					{File: "app.py", Kind: "function_definition", Name: "current", StartLine: 5, EndLine: 6},
+				},
+			},
+		},
+	}
+	violations := Evaluate(idx, []Rule{rule})
+	if len(violations) != 1 || violations[0].Name != "old" || violations[0].RuleID != rule.ID {
+		t.Fatalf("unexpected violations %+v", violations)
+	}
+
+	cfg, err := ParseConfig("no class_definition > function_definition[name=/^_/]\n")
+	if err != nil {
+		t.Fatalf("ParseConfig returned error: %v", err)
+	}
+	if len(cfg.Rules) != 1 || cfg.Rules[0].Type != "no_match" {
+		t.Fatalf("expected a selector rule from config, got %+v", cfg.Rules)
+	}
+	if _, err := ParseRule("no function_definition[bogus=1]"); err == nil {
+		t.Fatal("expected error for invalid selector")
+	}
+}
@@ -0,0 +1,103 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func parseNamingRule(raw, text string, matches []string) (Rule, error) {
+	kind, kindLabel, err := normalizeRuleKind(matches[2])
+	if err != nil {
+		return Rule{}, err
+	}
+	pattern := strings.Trim(matches[3], `"`)
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid naming pattern in rule %q: %w", raw, err)
+	}
+	visibility := strings.ToLower(matches[1])
+	language := strings.ToLower(matches[4])
+
+	id := "naming:" + kindLabel
+	if visibility != "" {
+		id = "naming:" + visibility + "-" + kindLabel
+	}
+	if language != "" {
+		id += ":" + language
+	}
+
+	return Rule{
+		ID:          id,
+		Raw:         text,
+		Type:        "naming",
+		Kind:        kind,
+		KindLabel:   kindLabel,
+		Pattern:     pattern,
+		Visibility:  visibility,
+		Language:    language,
+		namePattern: compiled,
+	}, nil
+}
+
+func namingViolations(idx *model.Index, rule Rule) []Violation {
+	compiled := rule.namePattern
+	if compiled == nil {
+		var err error
+		if compiled, err = regexp.Compile(rule.Pattern); err != nil {
+			return nil
+		}
+	}
+
+	violations := make([]Violation, 0, 8)
+	for _, file := range idx.Files {
+		if rule.Language != "" && !strings.EqualFold(file.Language, rule.Language) {
+			continue
+		}
+		for _, symbol := range file.Symbols {
+			if !ruleTargets(rule.Kind, symbol.Kind) {
+				continue
+			}
+			if !visibilityMatches(rule.Visibility, file.Language, symbol) {
+				continue
+			}
+			if compiled.MatchString(symbol.Name) {
+				continue
+			}
+			label := rule.KindLabel
+			if rule.Visibility != "" {
+				label = rule.Visibility + " " + label
+			}
+			violations = append(violations, Violation{
+				RuleID:    rule.ID,
+				File:      file.Path,
+				Kind:      symbol.Kind,
+				Name:      symbol.Name,
+				StartLine: symbol.StartLine,
+				EndLine:   symbol.EndLine,
+				Span:      symbolSpan(symbol),
+				Message:   fmt.Sprintf("%s %q does not match naming pattern %s", label, symbol.Name, rule.Pattern),
+			})
+		}
+	}
+	return violations
+}
+
+// visibilityMatches uses the visibility recorded on the symbol. For indexes
+// without one it falls back to the language's export convention: an
+// upper-case initial in Go, no leading underscore elsewhere.
+func visibilityMatches(visibility, language string, symbol model.Symbol) bool {
+	if visibility == "" {
+		return true
+	}
+	exported := symbol.Exported
+	if symbol.Visibility == "" {
+		exported = !strings.HasPrefix(symbol.Name, "_")
+		if strings.EqualFold(language, "go") {
+			exported = isExported(symbol.Name)
+		}
+	}
+	return exported == (visibility == "exported")
+}
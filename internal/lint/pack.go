@@ -0,0 +1,317 @@
+package lint
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/yamlsubset"
+)
+
+// PackManifest is the file at the root of every rule pack.
+const PackManifest = "lint.yaml"
+
+// Pack is a named, shareable bundle of lint rules, query patterns, and
+// threshold overrides.
+type Pack struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Source      string         `json:"source"`
+	Rules       []Rule         `json:"rules,omitempty"`
+	Patterns    []QueryPattern `json:"patterns,omitempty"`
+	Thresholds  map[string]int `json:"thresholds,omitempty"`
+}
+
+// PackRegistry returns the directories searched for named packs, in order:
+// extra, then $GTS_PACK_PATH, then <root>/.gts/packs, then ~/.gts/packs.
+func PackRegistry(root string, extra []string) []string {
+	dirs := append([]string(nil), extra...)
+	dirs = append(dirs, filepath.SplitList(os.Getenv("GTS_PACK_PATH"))...)
+	if strings.TrimSpace(root) != "" {
+		dirs = append(dirs, filepath.Join(root, ".gts", "packs"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".gts", "packs"))
+	}
+
+	out := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if strings.TrimSpace(dir) != "" {
+			out = append(out, dir)
+		}
+	}
+	return out
+}
+
+// ResolvePack finds a pack by name in the registry. A name that is itself a
+// path to a pack directory or tarball is used as-is.
+func ResolvePack(name string, registry []string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("pack name cannot be empty")
+	}
+	if isPackSource(name) {
+		return name, nil
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("pack %q not found", name)
+	}
+	for _, dir := range registry {
+		for _, candidate := range []string{name, name + ".tar.gz", name + ".tgz", name + ".tar"} {
+			full := filepath.Join(dir, candidate)
+			if isPackSource(full) {
+				return full, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("pack %q not found in registry %s", name, strings.Join(registry, string(filepath.ListSeparator)))
+}
+
+func isPackSource(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if info.IsDir() {
+		_, err := os.Stat(filepath.Join(path, PackManifest))
+		return err == nil
+	}
+	return isTarball(path)
+}
+
+func isTarball(path string) bool {
+	return strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz") || strings.HasSuffix(path, ".tar")
+}
+
+// LoadPack resolves name against the registry and loads the pack.
+func LoadPack(name string, registry []string) (Pack, error) {
+	source, err := ResolvePack(name, registry)
+	if err != nil {
+		return Pack{}, err
+	}
+
+	var files map[string][]byte
+	if isTarball(source) {
+		files, err = readPackTarball(source)
+	} else {
+		files, err = readPackDir(source)
+	}
+	if err != nil {
+		return Pack{}, fmt.Errorf("load pack %q: %w", name, err)
+	}
+
+	pack, err := buildPack(files)
+	if err != nil {
+		return Pack{}, fmt.Errorf("load pack %q: %w", name, err)
+	}
+	pack.Source = source
+	return pack, nil
+}
+
+// readPackDir returns the manifest and .scm files of a pack directory keyed by
+// slash-separated path relative to the pack root.
+func readPackDir(dir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isPackFile(d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	return files, err
+}
+
+// readPackTarball reads a pack archive. A single top-level directory wrapping
+// the manifest (name/lint.yaml) is stripped.
+func readPackTarball(archive string) (map[string][]byte, error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if !strings.HasSuffix(archive, ".tar") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg || !isPackFile(path.Base(header.Name)) {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return nil, fmt.Errorf("invalid archive entry %q", header.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, 1<<20))
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+
+	if _, ok := files[PackManifest]; !ok {
+		for name := range files {
+			if path.Base(name) != PackManifest || strings.Count(name, "/") != 1 {
+				continue
+			}
+			prefix := path.Dir(name) + "/"
+			stripped := map[string][]byte{}
+			for inner, data := range files {
+				if strings.HasPrefix(inner, prefix) {
+					stripped[strings.TrimPrefix(inner, prefix)] = data
+				}
+			}
+			files = stripped
+			break
+		}
+	}
+	return files, nil
+}
+
+func isPackFile(name string) bool {
+	return name == PackManifest || strings.HasSuffix(name, ".scm")
+}
+
+func buildPack(files map[string][]byte) (Pack, error) {
+	data, ok := files[PackManifest]
+	if !ok {
+		return Pack{}, fmt.Errorf("missing %s", PackManifest)
+	}
+	manifest, err := yamlsubset.Parse(string(data))
+	if err != nil {
+		return Pack{}, fmt.Errorf("%s: %w", PackManifest, err)
+	}
+
+	var pack Pack
+	if pack.Name, err = manifestValue(manifest, "name"); err != nil {
+		return Pack{}, err
+	}
+	if pack.Description, err = manifestValue(manifest, "description"); err != nil {
+		return Pack{}, err
+	}
+	if pack.Name == "" {
+		return Pack{}, fmt.Errorf("%s: name is required", PackManifest)
+	}
+
+	rules, _, err := manifestList(manifest, "rules")
+	if err != nil {
+		return Pack{}, err
+	}
+	for _, raw := range rules {
+		rule, err := ParseRule(raw)
+		if err != nil {
+			return Pack{}, fmt.Errorf("rule %q: %w", raw, err)
+		}
+		pack.Rules = append(pack.Rules, rule)
+	}
+
+	patternFiles, listed, err := manifestList(manifest, "patterns")
+	if err != nil {
+		return Pack{}, err
+	}
+	if !listed {
+		for name := range files {
+			if strings.HasSuffix(name, ".scm") {
+				patternFiles = append(patternFiles, name)
+			}
+		}
+		sort.Strings(patternFiles)
+	}
+	for _, name := range patternFiles {
+		source, ok := files[path.Clean(name)]
+		if !ok {
+			return Pack{}, fmt.Errorf("pattern %q not found in pack", name)
+		}
+		pattern, err := ParseQueryPattern(pack.Name+"/"+path.Clean(name), source)
+		if err != nil {
+			return Pack{}, err
+		}
+		pack.Patterns = append(pack.Patterns, pattern)
+	}
+
+	if thresholds, ok := manifest.Fields["thresholds"]; ok {
+		if thresholds.IsList || thresholds.Scalar != "" {
+			return Pack{}, fmt.Errorf("%s: line %d: thresholds must map metrics to limits", PackManifest, thresholds.Line)
+		}
+		for _, metric := range thresholds.Keys {
+			raw, err := thresholds.Fields[metric].Value(metric)
+			if err != nil {
+				return Pack{}, fmt.Errorf("%s: %w", PackManifest, err)
+			}
+			threshold, err := strconv.Atoi(raw)
+			if err != nil || threshold <= 0 {
+				return Pack{}, fmt.Errorf("invalid threshold %s: %q", metric, raw)
+			}
+			if pack.Thresholds == nil {
+				pack.Thresholds = map[string]int{}
+			}
+			pack.Thresholds[strings.ToLower(metric)] = threshold
+		}
+	}
+	return pack, nil
+}
+
+// manifestValue returns the single value under key, or "" when the manifest
+// does not set it.
+func manifestValue(manifest *yamlsubset.Node, key string) (string, error) {
+	node, ok := manifest.Fields[key]
+	if !ok {
+		return "", nil
+	}
+	value, err := node.Value(key)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", PackManifest, err)
+	}
+	return value, nil
+}
+
+// manifestList returns the list under key and whether the manifest lists
+// key at all. An explicit empty list ("patterns: []") is listed; a bare
+// "patterns:" with nothing under it is not.
+func manifestList(manifest *yamlsubset.Node, key string) ([]string, bool, error) {
+	node, ok := manifest.Fields[key]
+	if !ok || (!node.IsList && !node.IsMap() && node.Scalar == "") {
+		return nil, false, nil
+	}
+	values, err := node.Values(key)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", PackManifest, err)
+	}
+	return values, true, nil
+}
@@ -0,0 +1,140 @@
+package lint
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPackManifest = `# Organization-wide structural checks
+name: security-basics
+description: "Baseline checks for services"
+rules:
+  - no import unsafe
+  - 'no call to /^exec\./'   # shelling out
+patterns:
+  - patterns/no-empty.scm
+thresholds:
+  cyclomatic: 20
+`
+
+const testPackPattern = "; id: security/no-empty\n; message: empty function\n(function_declaration (block) @violation)\n"
+
+func TestLoadPackFromRegistryDir(t *testing.T) {
+	registry := t.TempDir()
+	packDir := filepath.Join(registry, "security-basics")
+	if err := os.MkdirAll(filepath.Join(packDir, "patterns"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(packDir, PackManifest), []byte(testPackManifest), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(packDir, "patterns", "no-empty.scm"), []byte(testPackPattern), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	pack, err := LoadPack("security-basics", []string{registry})
+	if err != nil {
+		t.Fatalf("LoadPack returned error: %v", err)
+	}
+	assertTestPack(t, pack)
+
+	if _, err := LoadPack("missing-pack", []string{registry}); err == nil {
+		t.Fatal("expected unknown pack to fail")
+	}
+}
+
+func TestLoadPackFromTarball(t *testing.T) {
+	registry := t.TempDir()
+	archive, err := os.Create(filepath.Join(registry, "security-basics.tar.gz"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	gz := gzip.NewWriter(archive)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{
+		"security-basics/lint.yaml":             testPackManifest,
+		"security-basics/patterns/no-empty.scm": testPackPattern,
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("WriteHeader failed: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar Close failed: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip Close failed: %v", err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	pack, err := LoadPack("security-basics", []string{registry})
+	if err != nil {
+		t.Fatalf("LoadPack returned error: %v", err)
+	}
+	assertTestPack(t, pack)
+}
+
+func assertTestPack(t *testing.T, pack Pack) {
+	t.Helper()
+	if pack.Name != "security-basics" || pack.Description != "Baseline checks for services" {
+		t.Fatalf("unexpected pack metadata %+v", pack)
+	}
+	if len(pack.Rules) != 2 || pack.Rules[0].Type != "no_import" || pack.Rules[1].Type != "no_call" || pack.Rules[1].Pattern != `/^exec\./` {
+		t.Fatalf("unexpected pack rules %+v", pack.Rules)
+	}
+	if len(pack.Patterns) != 1 || pack.Patterns[0].ID != "security/no-empty" || pack.Patterns[0].Message != "empty function" {
+		t.Fatalf("unexpected pack patterns %+v", pack.Patterns)
+	}
+	if pack.Thresholds["cyclomatic"] != 20 {
+		t.Fatalf("unexpected pack thresholds %+v", pack.Thresholds)
+	}
+}
+
+func TestBuildPackManifestErrors(t *testing.T) {
+	for _, content := range []string{
+		"name security\n",
+		"  - orphan item\n",
+		"name: \"unterminated\n",
+		"name: x\n\tdescription: tabbed\n",
+		"name: x\nname: y\n",
+		"name: [a, b]\n",
+		"name: x\nthresholds: 5\n",
+	} {
+		if _, err := buildPack(map[string][]byte{PackManifest: []byte(content)}); err == nil {
+			t.Fatalf("expected error for manifest %q", content)
+		}
+	}
+	if _, err := buildPack(map[string][]byte{PackManifest: []byte("description: no name\n")}); err == nil {
+		t.Fatal("expected pack without name to fail")
+	}
+}
+
+func TestBuildPackPatternListing(t *testing.T) {
+	files := map[string][]byte{"patterns/no-empty.scm": []byte(testPackPattern)}
+
+	files[PackManifest] = []byte("name: p\npatterns:\n")
+	pack, err := buildPack(files)
+	if err != nil {
+		t.Fatalf("buildPack returned error: %v", err)
+	}
+	if len(pack.Patterns) != 1 {
+		t.Fatalf("expected unlisted patterns to default to every .scm file, got %+v", pack.Patterns)
+	}
+
+	files[PackManifest] = []byte("name: p\npatterns: []\n")
+	pack, err = buildPack(files)
+	if err != nil {
+		t.Fatalf("buildPack returned error: %v", err)
+	}
+	if len(pack.Patterns) != 0 {
+		t.Fatalf("expected an empty patterns list to load none, got %+v", pack.Patterns)
+	}
+}
@@ -0,0 +1,177 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/odvcencio/gotreesitter"
+	"github.com/odvcencio/gotreesitter/grammars"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// PluginProtocolVersion is the version of the JSON protocol spoken with
+// subprocess rule plugins.
+const PluginProtocolVersion = 1
+
+// RuleContext gives a custom rule access to the index and to the syntax tree
+// of any indexed file.
+type RuleContext struct {
+	Index *model.Index
+}
+
+// Source reads the contents of an indexed file.
+func (c *RuleContext) Source(file model.FileSummary) ([]byte, error) {
+	return os.ReadFile(filepath.Join(c.Index.Root, filepath.FromSlash(file.Path)))
+}
+
+// Parse reads and parses an indexed file. Callers must Release the returned tree.
+func (c *RuleContext) Parse(file model.FileSummary) (*gotreesitter.BoundTree, []byte, error) {
+	source, err := c.Source(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, err := grammars.ParseFile(file.Path, source)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tree, source, nil
+}
+
+// RuleFunc implements a custom lint rule. Violations without a RuleID are
+// attributed to the rule they came from.
+type RuleFunc func(ctx *RuleContext) ([]Violation, error)
+
+// CustomRule is a named RuleFunc.
+type CustomRule struct {
+	ID   string
+	Func RuleFunc
+}
+
+var customRules = struct {
+	sync.Mutex
+	byID map[string]RuleFunc
+}{byID: map[string]RuleFunc{}}
+
+// RegisterRule adds a custom rule that every lint run evaluates. Programs that
+// embed gts-suite call it from init to ship domain-specific rules.
+func RegisterRule(id string, fn RuleFunc) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return errors.New("custom rule id is required")
+	}
+	if fn == nil {
+		return fmt.Errorf("custom rule %q has nil func", id)
+	}
+	customRules.Lock()
+	defer customRules.Unlock()
+	if _, exists := customRules.byID[id]; exists {
+		return fmt.Errorf("custom rule %q already registered", id)
+	}
+	customRules.byID[id] = fn
+	return nil
+}
+
+// RegisteredRules returns all registered custom rules ordered by ID.
+func RegisteredRules() []CustomRule {
+	customRules.Lock()
+	defer customRules.Unlock()
+	rules := make([]CustomRule, 0, len(customRules.byID))
+	for id, fn := range customRules.byID {
+		rules = append(rules, CustomRule{ID: id, Func: fn})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+// EvaluateCustom runs custom rules against the index.
+func EvaluateCustom(idx *model.Index, rules []CustomRule) ([]Violation, error) {
+	if idx == nil || len(rules) == 0 {
+		return nil, nil
+	}
+
+	ctx := &RuleContext{Index: idx}
+	violations := make([]Violation, 0, 16)
+	for _, rule := range rules {
+		found, err := rule.Func(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("custom rule %q: %w", rule.ID, err)
+		}
+		for _, violation := range found {
+			if violation.RuleID == "" {
+				violation.RuleID = rule.ID
+			}
+			if violation.Span == 0 && violation.StartLine > 0 && violation.EndLine >= violation.StartLine {
+				violation.Span = violation.EndLine - violation.StartLine + 1
+			}
+			violations = append(violations, violation)
+		}
+	}
+
+	sortViolations(violations)
+	return violations, nil
+}
+
+// pluginRequest is written to a subprocess plugin's stdin.
+type pluginRequest struct {
+	Version int          `json:"version"`
+	Root    string       `json:"root"`
+	Index   *model.Index `json:"index"`
+}
+
+// pluginResponse is read from a subprocess plugin's stdout.
+type pluginResponse struct {
+	Violations []Violation `json:"violations"`
+}
+
+// PluginRule wraps an executable as a custom rule. The plugin receives a JSON
+// request ({"version", "root", "index"}) on stdin and must print
+// {"violations": [...]} on stdout; a non-zero exit is reported as an error.
+// The rule ID defaults to "plugin/<executable name>".
+func PluginRule(path string) CustomRule {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return CustomRule{
+		ID: "plugin/" + name,
+		Func: func(ctx *RuleContext) ([]Violation, error) {
+			return runPlugin(path, ctx.Index)
+		},
+	}
+}
+
+func runPlugin(path string, idx *model.Index) ([]Violation, error) {
+	payload, err := json.Marshal(pluginRequest{
+		Version: PluginProtocolVersion,
+		Root:    idx.Root,
+		Index:   idx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(path)
+	cmd.Dir = idx.Root
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	var response pluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("decode plugin output: %w", err)
+	}
+	return response.Violations, nil
+}
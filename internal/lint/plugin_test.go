@@ -0,0 +1,117 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+func TestEvaluateCustomRuleWithAST(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := "package sample\n\nfunc Handler() {\n\tprintln(\"x\")\n}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+
+	rule := CustomRule{
+		ID: "custom/no-println",
+		Func: func(ctx *RuleContext) ([]Violation, error) {
+			var out []Violation
+			for _, file := range ctx.Index.Files {
+				tree, src, err := ctx.Parse(file)
+				if err != nil {
+					return nil, err
+				}
+				gotreesitter.Walk(tree.RootNode(), func(node *gotreesitter.Node, depth int) gotreesitter.WalkAction {
+					if tree.NodeType(node) == "call_expression" && strings.HasPrefix(node.Text(src), "println") {
+						line := int(node.StartPoint().Row) + 1
+						out = append(out, Violation{File: file.Path, Kind: "call", Name: "println", StartLine: line, EndLine: line})
+					}
+					return gotreesitter.WalkContinue
+				})
+				tree.Release()
+			}
+			return out, nil
+		},
+	}
+
+	violations, err := EvaluateCustom(idx, []CustomRule{rule})
+	if err != nil {
+		t.Fatalf("EvaluateCustom returned error: %v", err)
+	}
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %#v", violations)
+	}
+	if violations[0].RuleID != "custom/no-println" || violations[0].StartLine != 4 || violations[0].Span != 1 {
+		t.Fatalf("unexpected violation %#v", violations[0])
+	}
+}
+
+func TestRegisterRuleRejectsDuplicates(t *testing.T) {
+	noop := func(*RuleContext) ([]Violation, error) { return nil, nil }
+	if err := RegisterRule("test/register-dup", noop); err != nil {
+		t.Fatalf("RegisterRule returned error: %v", err)
+	}
+	if err := RegisterRule("test/register-dup", noop); err == nil {
+		t.Fatal("expected duplicate registration to fail")
+	}
+	if err := RegisterRule(" ", noop); err == nil {
+		t.Fatal("expected empty id to fail")
+	}
+
+	found := false
+	for _, rule := range RegisteredRules() {
+		if rule.ID == "test/register-dup" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected registered rule to be listed")
+	}
+}
+
+func TestPluginRuleSubprocess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugin requires a POSIX shell")
+	}
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package sample\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+
+	pluginPath := filepath.Join(t.TempDir(), "audit-check.sh")
+	script := "#!/bin/sh\ngrep -q '\"version\":1' || exit 2\necho '{\"violations\":[{\"file\":\"main.go\",\"kind\":\"function\",\"name\":\"Handler\",\"start_line\":1,\"end_line\":1,\"message\":\"missing audit.Log\"}]}'\n"
+	if err := os.WriteFile(pluginPath, []byte(script), 0o755); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	violations, err := EvaluateCustom(idx, []CustomRule{PluginRule(pluginPath)})
+	if err != nil {
+		t.Fatalf("EvaluateCustom returned error: %v", err)
+	}
+	if len(violations) != 1 || violations[0].RuleID != "plugin/audit-check" || violations[0].Message != "missing audit.Log" {
+		t.Fatalf("unexpected plugin violations %#v", violations)
+	}
+
+	failing := filepath.Join(t.TempDir(), "fail.sh")
+	if err := os.WriteFile(failing, []byte("#!/bin/sh\necho boom >&2\nexit 1\n"), 0o755); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := EvaluateCustom(idx, []CustomRule{PluginRule(failing)}); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected plugin stderr in error, got %v", err)
+	}
+}
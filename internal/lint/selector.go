@@ -0,0 +1,57 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/query"
+)
+
+// noMatchRulePattern matches: no <selector>, where the selector starts with
+// a node kind followed by a filter or a containment, e.g.
+// no function_definition[attr=/@deprecated/].
+var noMatchRulePattern = regexp.MustCompile(`^\s*(?i:no)\s+((?:\*|[a-z]+_[a-z0-9_]*)\s*(?:\[|>).*?)\s*$`)
+
+func parseNoMatchRule(raw, text string, matches []string) (Rule, error) {
+	selector, err := query.ParseSelector(matches[1])
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid selector in rule %q: %w", raw, err)
+	}
+	return Rule{
+		ID:       "no-match:" + selector.Raw,
+		Raw:      text,
+		Type:     "no_match",
+		Pattern:  selector.Raw,
+		selector: &selector,
+	}, nil
+}
+
+// noMatchViolations reports each symbol the rule's selector matches.
+func noMatchViolations(idx *model.Index, rule Rule) []Violation {
+	selector := rule.selector
+	if selector == nil {
+		parsed, err := query.ParseSelector(rule.Pattern)
+		if err != nil {
+			return nil
+		}
+		selector = &parsed
+	}
+
+	violations := make([]Violation, 0, 8)
+	for _, file := range idx.Files {
+		for _, symbol := range selector.MatchFile(file.Symbols) {
+			violations = append(violations, Violation{
+				RuleID:    rule.ID,
+				File:      file.Path,
+				Kind:      symbol.Kind,
+				Name:      symbol.Name,
+				StartLine: symbol.StartLine,
+				EndLine:   symbol.EndLine,
+				Span:      symbolSpan(symbol),
+				Message:   fmt.Sprintf("%q matches forbidden selector %s", symbol.Name, selector.Raw),
+			})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,205 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/refactor"
+)
+
+// unusedImportViolations reports Go imports whose package name is never used
+// as a selector base in the importing file. Package names are inferred from
+// the import path, so an unaliased import is only reported when every
+// unresolved selector base in the file is accounted for by some import; that
+// keeps the accompanying removal fix safe.
+func unusedImportViolations(idx *model.Index, rule Rule) []Violation {
+	violations := make([]Violation, 0, 4)
+	for _, file := range idx.Files {
+		if !strings.EqualFold(file.Language, "go") && !strings.HasSuffix(file.Path, ".go") {
+			continue
+		}
+		source, err := os.ReadFile(filepath.Join(idx.Root, filepath.FromSlash(file.Path)))
+		if err != nil {
+			continue
+		}
+		fset := token.NewFileSet()
+		parsed, err := parser.ParseFile(fset, file.Path, source, 0)
+		if err != nil {
+			continue
+		}
+		violations = append(violations, unusedImportsInFile(fset, parsed, source, file.Path, rule)...)
+	}
+	return violations
+}
+
+func unusedImportsInFile(fset *token.FileSet, parsed *ast.File, source []byte, relPath string, rule Rule) []Violation {
+	bases := map[string]bool{}
+	ast.Inspect(parsed, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Obj == nil {
+			bases[ident.Name] = true
+		}
+		return true
+	})
+
+	type importInfo struct {
+		decl       *ast.GenDecl
+		spec       *ast.ImportSpec
+		path       string
+		aliased    bool
+		candidates []string
+	}
+	imports := make([]importInfo, 0, len(parsed.Imports))
+	accounted := map[string]bool{}
+	for _, decl := range parsed.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			importSpec := spec.(*ast.ImportSpec)
+			path, err := strconv.Unquote(importSpec.Path.Value)
+			if err != nil || path == "C" {
+				continue
+			}
+			info := importInfo{decl: gen, spec: importSpec, path: path}
+			if importSpec.Name != nil {
+				if importSpec.Name.Name == "_" || importSpec.Name.Name == "." {
+					continue
+				}
+				info.aliased = true
+				info.candidates = []string{importSpec.Name.Name}
+			} else {
+				info.candidates = importNameCandidates(path)
+			}
+			for _, name := range info.candidates {
+				accounted[name] = true
+			}
+			imports = append(imports, info)
+		}
+	}
+
+	ambiguous := false
+	for base := range bases {
+		if !accounted[base] {
+			ambiguous = true
+			break
+		}
+	}
+
+	violations := make([]Violation, 0, 2)
+	for _, info := range imports {
+		used := false
+		for _, name := range info.candidates {
+			if bases[name] {
+				used = true
+				break
+			}
+		}
+		if used || (ambiguous && !info.aliased) {
+			continue
+		}
+
+		start := fset.Position(info.spec.Pos())
+		end := fset.Position(info.spec.End())
+		violation := Violation{
+			RuleID:    rule.ID,
+			File:      relPath,
+			Kind:      "import",
+			Name:      info.path,
+			StartLine: start.Line,
+			EndLine:   end.Line,
+			Span:      end.Line - start.Line + 1,
+			Message:   fmt.Sprintf("import %q is not used", info.path),
+		}
+
+		// Remove the spec's own line inside a grouped import, or the whole
+		// declaration for a single-line import.
+		target := ast.Node(info.spec)
+		if !info.decl.Lparen.IsValid() {
+			target = info.decl
+		}
+		if edit, ok := lineRemovalEdit(source, relPath, fset.Position(target.Pos()).Offset, fset.Position(target.End()).Offset); ok {
+			violation.Fixes = []refactor.Edit{edit}
+		}
+		violations = append(violations, violation)
+	}
+	return violations
+}
+
+// lineRemovalEdit returns an edit deleting the full line(s) holding
+// source[start:end], provided nothing but whitespace or a trailing line
+// comment shares those lines.
+func lineRemovalEdit(source []byte, relPath string, start, end int) (refactor.Edit, bool) {
+	if start < 0 || end > len(source) || start >= end {
+		return refactor.Edit{}, false
+	}
+	lineStart := bytes.LastIndexByte(source[:start], '\n') + 1
+	if len(bytes.TrimSpace(source[lineStart:start])) > 0 {
+		return refactor.Edit{}, false
+	}
+	lineEnd := len(source)
+	if newline := bytes.IndexByte(source[end:], '\n'); newline >= 0 {
+		lineEnd = end + newline + 1
+	}
+	trailing := bytes.TrimSpace(source[end:lineEnd])
+	if len(trailing) > 0 && !bytes.HasPrefix(trailing, []byte("//")) {
+		return refactor.Edit{}, false
+	}
+
+	return refactor.Edit{
+		File:     relPath,
+		Kind:     "import",
+		Category: "remove_import",
+		OldName:  string(source[lineStart:lineEnd]),
+		NewName:  "",
+		Line:     bytes.Count(source[:lineStart], []byte("\n")) + 1,
+		Column:   1,
+		Offset:   lineStart,
+	}, true
+}
+
+// importNameCandidates guesses the package names an unaliased import path may
+// declare, e.g. "gopkg.in/yaml.v3" -> yaml and "github.com/x/go-redis/v9" -> redis.
+func importNameCandidates(path string) []string {
+	parts := strings.Split(path, "/")
+	last := parts[len(parts)-1]
+	if isMajorVersionElem(last) && len(parts) > 1 {
+		last = parts[len(parts)-2]
+	}
+
+	candidates := []string{last}
+	trimmed := strings.TrimPrefix(last, "go-")
+	trimmed = strings.TrimSuffix(trimmed, "-go")
+	trimmed = strings.TrimSuffix(trimmed, ".go")
+	if dot := strings.Index(trimmed, ".v"); dot > 0 {
+		trimmed = trimmed[:dot]
+	}
+	trimmed = strings.ReplaceAll(trimmed, "-", "")
+	if trimmed != last && trimmed != "" {
+		candidates = append(candidates, trimmed)
+	}
+	if dot := strings.LastIndex(trimmed, "."); dot >= 0 && dot < len(trimmed)-1 {
+		candidates = append(candidates, trimmed[dot+1:])
+	}
+	return candidates
+}
+
+func isMajorVersionElem(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(elem[1:])
+	return err == nil
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// AuditEntry is one line of the tool-call audit log.
+type AuditEntry struct {
+	Time       time.Time      `json:"time"`
+	Tool       string         `json:"tool"`
+	Args       map[string]any `json:"args,omitempty"`
+	DurationMS int64          `json:"duration_ms"`
+	// ResultBytes is the size of the text returned to the client, the
+	// encoded result or the error message.
+	ResultBytes int `json:"result_bytes"`
+	// ResultTokens estimates the context the result costs the agent, at
+	// bytesPerToken bytes per token.
+	ResultTokens int    `json:"result_tokens"`
+	Error        string `json:"error,omitempty"`
+}
+
+const (
+	// auditMaxString is how many bytes of a string argument are logged.
+	auditMaxString = 256
+	// auditMaxItems is how many elements of an array argument are logged.
+	auditMaxItems = 20
+)
+
+// auditSecretArg matches argument names whose values are never logged.
+var auditSecretArg = regexp.MustCompile(`(?i)secret|passw(or)?d|credential|api_?key|(access|auth)_?token`)
+
+// auditLog appends AuditEntry lines to a writer shared by concurrent calls.
+type auditLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	failed bool
+}
+
+func newAuditLog(w io.Writer) *auditLog {
+	if w == nil {
+		return nil
+	}
+	return &auditLog{w: w}
+}
+
+// record appends entry. It returns the first write error only, so a full
+// disk is reported once rather than on every call.
+func (a *auditLog) record(entry AuditEntry) error {
+	if a == nil {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(append(line, '\n')); err != nil && !a.failed {
+		a.failed = true
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// auditToolCall records a finished tools/call on the service's audit log,
+// if it has one. resultText is what the client received.
+func (s *Service) auditToolCall(tool string, args map[string]any, started time.Time, resultText string, callErr error) error {
+	if s.audit == nil {
+		return nil
+	}
+	entry := AuditEntry{
+		Time:         started.UTC(),
+		Tool:         tool,
+		Args:         sanitizeAuditArgs(args),
+		DurationMS:   time.Since(started).Milliseconds(),
+		ResultBytes:  len(resultText),
+		ResultTokens: (len(resultText) + bytesPerToken - 1) / bytesPerToken,
+	}
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	}
+	return s.audit.record(entry)
+}
+
+// sanitizeAuditArgs returns a copy of args fit for the audit log: values
+// of secret-looking arguments are redacted, long strings are cut, and long
+// arrays are shortened.
+func sanitizeAuditArgs(args map[string]any) map[string]any {
+	if len(args) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(args))
+	for key, value := range args {
+		if auditSecretArg.MatchString(key) {
+			out[key] = "[redacted]"
+			continue
+		}
+		out[key] = sanitizeAuditValue(value)
+	}
+	return out
+}
+
+func sanitizeAuditValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		if len(v) <= auditMaxString {
+			return v
+		}
+		cut := auditMaxString
+		for cut > 0 && !utf8.RuneStart(v[cut]) {
+			cut--
+		}
+		return fmt.Sprintf("%s…[%d more bytes]", v[:cut], len(v)-cut)
+	case []any:
+		n := len(v)
+		if n > auditMaxItems {
+			n = auditMaxItems
+		}
+		out := make([]any, 0, n+1)
+		for _, item := range v[:n] {
+			out = append(out, sanitizeAuditValue(item))
+		}
+		if len(v) > n {
+			out = append(out, fmt.Sprintf("…[%d more]", len(v)-n))
+		}
+		return out
+	case map[string]any:
+		return sanitizeAuditArgs(v)
+	}
+	return value
+}
@@ -0,0 +1,254 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// bytesPerToken is the rough size of a token in tool output, used to turn
+// token budgets into byte budgets and result sizes into token estimates.
+const bytesPerToken = 4
+
+// outputBudgetProperties are accepted by every tool to bound its result.
+var outputBudgetProperties = map[string]Property{
+	"max_output_tokens": {Type: "integer", Description: "truncate the result to about this many tokens (default: server limit)"},
+	"max_bytes":         {Type: "integer", Description: "truncate the result to this many bytes of JSON (default: server limit)"},
+}
+
+// outputTruncation describes how a result was cut to fit its budget. It is
+// returned as "truncation" next to "truncated": true.
+type outputTruncation struct {
+	LimitBytes int              `json:"limit_bytes"`
+	TotalBytes int              `json:"total_bytes"`
+	Omitted    []truncatedField `json:"omitted,omitempty"`
+	Hints      []string         `json:"hints"`
+}
+
+// truncatedField reports what was kept of the arrays or strings at one path
+// of the result, such as "files[].symbols[]", summed over all of them.
+type truncatedField struct {
+	Path  string `json:"path"`
+	Unit  string `json:"unit"`
+	Total int    `json:"total"`
+	Kept  int    `json:"kept"`
+}
+
+// outputLimit returns the byte budget of a call: the smaller of its
+// max_bytes and max_output_tokens arguments, else the server default. Zero
+// means unlimited.
+func (s *Service) outputLimit(args map[string]any) int {
+	limit := intArg(args, "max_bytes", 0)
+	if tokens := intArg(args, "max_output_tokens", 0); tokens > 0 && (limit <= 0 || tokens*bytesPerToken < limit) {
+		limit = tokens * bytesPerToken
+	}
+	if limit <= 0 {
+		limit = s.maxOutputBytes
+	}
+	return limit
+}
+
+// limitOutput encodes result as the client receives it and, when that is
+// over the call's budget, cuts it down deterministically: every array is
+// shortened to the longest common length that fits, then, if need be,
+// every string. The cut result is an object with "truncated": true and a
+// "truncation" report hinting how to narrow the call. limitOutput returns
+// the result to send, its encoding, and whether it was truncated.
+func (s *Service) limitOutput(tool string, args map[string]any, result any) (any, []byte, bool) {
+	encoded, err := encodeToolResult(result)
+	if err != nil {
+		return result, []byte(`{"error":"failed to encode result"}`), false
+	}
+	limit := s.outputLimit(args)
+	if limit <= 0 || len(encoded) <= limit {
+		return result, encoded, false
+	}
+
+	var generic any
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return result, encoded, false
+	}
+	truncation := outputTruncation{
+		LimitBytes: limit,
+		TotalBytes: len(encoded),
+		Hints:      s.narrowingHints(tool, limit),
+	}
+
+	// Paths in the report name fields as the client sees them, so a result
+	// that is not an object is reported under "result", where it ends up.
+	root := "result"
+	if _, ok := generic.(map[string]any); ok {
+		root = ""
+	}
+	maxItems, maxString := valueExtents(generic)
+	cut := func(items, stringBytes int) (any, []byte, bool) {
+		c := cutter{maxItems: items, maxString: stringBytes, fields: map[string]*truncatedField{}}
+		value := c.cut(generic, root)
+		report := truncation
+		report.Omitted = c.omitted()
+		wrapped := wrapTruncated(value, report)
+		data, err := encodeToolResult(wrapped)
+		return wrapped, data, err == nil && len(data) <= limit
+	}
+
+	// Keep as many items as fit, with strings whole; only when no items
+	// fit are strings shortened too.
+	if _, _, ok := cut(0, -1); ok {
+		items := largestFitting(maxItems, func(n int) bool { _, _, ok := cut(n, -1); return ok })
+		value, data, _ := cut(items, -1)
+		return value, data, true
+	}
+	if _, _, ok := cut(0, 0); ok {
+		stringBytes := largestFitting(maxString, func(n int) bool { _, _, ok := cut(0, n); return ok })
+		value, data, _ := cut(0, stringBytes)
+		return value, data, true
+	}
+	wrapped := map[string]any{"truncated": true, "truncation": truncation}
+	data, _ := encodeToolResult(wrapped)
+	return wrapped, data, true
+}
+
+func encodeToolResult(result any) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// largestFitting returns the largest n in [0, upper] for which fits holds,
+// given that it holds for 0 and, once false, stays false.
+func largestFitting(upper int, fits func(int) bool) int {
+	lo, hi := 0, upper
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo
+}
+
+// wrapTruncated marks value as truncated. Objects gain the report in place;
+// anything else is returned under "result".
+func wrapTruncated(value any, report outputTruncation) map[string]any {
+	object, ok := value.(map[string]any)
+	if !ok {
+		object = map[string]any{"result": value}
+	}
+	object["truncated"] = true
+	object["truncation"] = report
+	return object
+}
+
+// valueExtents returns the length of the longest array and string in value.
+func valueExtents(value any) (items, stringBytes int) {
+	switch v := value.(type) {
+	case string:
+		return 0, len(v)
+	case []any:
+		items = len(v)
+		for _, item := range v {
+			i, s := valueExtents(item)
+			items, stringBytes = max(items, i), max(stringBytes, s)
+		}
+	case map[string]any:
+		for _, item := range v {
+			i, s := valueExtents(item)
+			items, stringBytes = max(items, i), max(stringBytes, s)
+		}
+	}
+	return items, stringBytes
+}
+
+// cutter copies a decoded result keeping at most maxItems elements of each
+// array and, unless maxString is negative, maxString bytes of each string.
+type cutter struct {
+	maxItems  int
+	maxString int
+	fields    map[string]*truncatedField
+}
+
+func (c *cutter) cut(value any, path string) any {
+	switch v := value.(type) {
+	case string:
+		if c.maxString < 0 || len(v) <= c.maxString {
+			return v
+		}
+		n := c.maxString
+		for n > 0 && !utf8.RuneStart(v[n]) {
+			n--
+		}
+		c.record(path, "bytes", len(v), n)
+		return v[:n]
+	case []any:
+		n := min(len(v), c.maxItems)
+		if n < len(v) {
+			c.record(path+"[]", "items", len(v), n)
+		}
+		out := make([]any, n)
+		for i := range out {
+			out[i] = c.cut(v[i], path+"[]")
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			out[key] = c.cut(item, child)
+		}
+		return out
+	}
+	return value
+}
+
+func (c *cutter) record(path, unit string, total, kept int) {
+	field := c.fields[path]
+	if field == nil {
+		field = &truncatedField{Path: path, Unit: unit}
+		c.fields[path] = field
+	}
+	field.Total += total
+	field.Kept += kept
+}
+
+func (c *cutter) omitted() []truncatedField {
+	fields := make([]truncatedField, 0, len(c.fields))
+	for _, field := range c.fields {
+		fields = append(fields, *field)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields
+}
+
+// narrowingHints suggests how to ask tool for less output, from the
+// arguments it accepts.
+func (s *Service) narrowingHints(tool string, limit int) []string {
+	var properties map[string]any
+	for _, candidate := range s.Tools() {
+		if candidate.Name == tool {
+			properties, _ = candidate.InputSchema["properties"].(map[string]any)
+			break
+		}
+	}
+	var hints []string
+	if _, ok := properties["file"]; ok {
+		hints = append(hints, `pass file globs, e.g. "internal/server/**", to cover fewer files`)
+	}
+	if _, ok := properties["path"]; ok {
+		hints = append(hints, "point path at a subdirectory")
+	}
+	if _, ok := properties["depth"]; ok && tool == "gts_map" {
+		hints = append(hints, `use depth "types-only" for a coarser outline`)
+	}
+	if _, ok := properties["top"]; ok {
+		hints = append(hints, "lower top to return fewer entries")
+	}
+	hints = append(hints, fmt.Sprintf("raise max_output_tokens above %d to see more", limit/bytesPerToken))
+	return hints
+}
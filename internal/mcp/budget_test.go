@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLimitOutputUnderBudget(t *testing.T) {
+	service := NewServiceWithOptions(".", "", ServiceOptions{MaxOutputTokens: 1000})
+	result := map[string]any{"count": 1}
+	got, encoded, truncated := service.limitOutput("gts_map", nil, result)
+	if truncated {
+		t.Fatalf("expected a small result to be left alone")
+	}
+	if _, ok := got.(map[string]any)["truncated"]; ok {
+		t.Fatalf("expected no truncation marker, got %#v", got)
+	}
+	if !bytes.Contains(encoded, []byte(`"count": 1`)) {
+		t.Fatalf("unexpected encoding %s", encoded)
+	}
+}
+
+func TestLimitOutputTruncatesArrays(t *testing.T) {
+	type file struct {
+		Path    string   `json:"path"`
+		Symbols []string `json:"symbols"`
+	}
+	files := make([]file, 0, 50)
+	for i := 0; i < 50; i++ {
+		symbols := make([]string, 30)
+		for j := range symbols {
+			symbols[j] = fmt.Sprintf("Symbol%d_%d", i, j)
+		}
+		files = append(files, file{Path: fmt.Sprintf("pkg/%02d/file.go", i), Symbols: symbols})
+	}
+	result := struct {
+		Files []file `json:"files"`
+	}{files}
+
+	service := NewService(".", "")
+	args := map[string]any{"max_bytes": 2000}
+	got, encoded, truncated := service.limitOutput("gts_map", args, result)
+	if !truncated {
+		t.Fatalf("expected the result to be truncated")
+	}
+	if len(encoded) > 2000 {
+		t.Fatalf("expected at most 2000 bytes, got %d", len(encoded))
+	}
+	_, again, _ := service.limitOutput("gts_map", args, result)
+	if !bytes.Equal(encoded, again) {
+		t.Fatalf("expected deterministic truncation")
+	}
+
+	var decoded struct {
+		Files      []file           `json:"files"`
+		Truncated  bool             `json:"truncated"`
+		Truncation outputTruncation `json:"truncation"`
+	}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("decode truncated result: %v", err)
+	}
+	if !decoded.Truncated || len(decoded.Files) == 0 || len(decoded.Files) >= 50 {
+		t.Fatalf("unexpected truncated result: truncated=%v files=%d", decoded.Truncated, len(decoded.Files))
+	}
+	if decoded.Files[0].Path != "pkg/00/file.go" {
+		t.Fatalf("expected the first files to be kept, got %q", decoded.Files[0].Path)
+	}
+	if decoded.Truncation.LimitBytes != 2000 || decoded.Truncation.TotalBytes <= 2000 {
+		t.Fatalf("unexpected truncation sizes %+v", decoded.Truncation)
+	}
+	if len(decoded.Truncation.Omitted) != 2 || decoded.Truncation.Omitted[0].Path != "files[]" || decoded.Truncation.Omitted[1].Path != "files[].symbols[]" {
+		t.Fatalf("unexpected omitted fields %+v", decoded.Truncation.Omitted)
+	}
+	if omitted := decoded.Truncation.Omitted[0]; omitted.Total != 50 || omitted.Kept != len(decoded.Files) || omitted.Unit != "items" {
+		t.Fatalf("unexpected files truncation %+v", omitted)
+	}
+	hints := strings.Join(decoded.Truncation.Hints, "\n")
+	if !strings.Contains(hints, "file globs") || !strings.Contains(hints, "types-only") || !strings.Contains(hints, "max_output_tokens") {
+		t.Fatalf("expected gts_map narrowing hints, got %q", hints)
+	}
+	if _, ok := got.(map[string]any); !ok {
+		t.Fatalf("expected an object result, got %T", got)
+	}
+}
+
+func TestLimitOutputCutsStrings(t *testing.T) {
+	service := NewService(".", "")
+	result := []string{strings.Repeat("x", 5000)}
+	_, encoded, truncated := service.limitOutput("gts_context", map[string]any{"max_output_tokens": 200}, result)
+	if !truncated || len(encoded) > 800 {
+		t.Fatalf("expected a result of at most 800 bytes, got truncated=%v len=%d", truncated, len(encoded))
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("decode truncated result: %v", err)
+	}
+	if _, ok := decoded["result"]; !ok {
+		t.Fatalf("expected a non-object result under \"result\", got %s", encoded)
+	}
+}
+
+func TestOutputLimit(t *testing.T) {
+	service := NewServiceWithOptions(".", "", ServiceOptions{MaxOutputTokens: 100})
+	tests := []struct {
+		args map[string]any
+		want int
+	}{
+		{nil, 400},
+		{map[string]any{"max_output_tokens": 10}, 40},
+		{map[string]any{"max_bytes": 1000}, 1000},
+		{map[string]any{"max_bytes": 1000, "max_output_tokens": 50}, 200},
+	}
+	for _, tt := range tests {
+		if got := service.outputLimit(tt.args); got != tt.want {
+			t.Fatalf("outputLimit(%v) = %d, want %d", tt.args, got, tt.want)
+		}
+	}
+	if got := NewService(".", "").outputLimit(nil); got != 0 {
+		t.Fatalf("expected no default limit, got %d", got)
+	}
+}
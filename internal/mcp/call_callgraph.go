@@ -1,13 +1,23 @@
 package mcp
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/walkfilter"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
 func (s *Service) callCallgraph(args map[string]any) (any, error) {
-	name, err := requiredStringArg(args, "name")
-	if err != nil {
-		return nil, err
+	patterns := stringSliceArg(args, "name")
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("missing required argument %q", "name")
+	}
+	aggregate := strings.ToLower(stringArg(args, "aggregate"))
+	switch aggregate {
+	case "", "package":
+	default:
+		return nil, fmt.Errorf("unsupported aggregate %q (expected package)", aggregate)
 	}
 	regexMode := boolArg(args, "regex", false)
 	depth := intArg(args, "depth", 2)
@@ -21,11 +31,11 @@ func (s *Service) callCallgraph(args map[string]any) (any, error) {
 	}
 	idx = applyGeneratedFilter(idx, boolArg(args, "include_generated", false), stringArg(args, "generator"))
 
-	graph, err := xref.Build(idx)
+	graph, err := xref.BuildWithOptions(idx, xref.Options{Dynamic: boolArg(args, "dynamic", false)})
 	if err != nil {
 		return nil, err
 	}
-	roots, err := graph.FindDefinitions(name, regexMode)
+	roots, err := graph.FindDefinitionsAny(patterns, regexMode)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +43,25 @@ func (s *Service) callCallgraph(args map[string]any) (any, error) {
 	for _, root := range roots {
 		rootIDs = append(rootIDs, root.ID)
 	}
-	walk := graph.Walk(rootIDs, depth, reverse)
+	filter := walkfilter.Options{
+		ExcludeTests:    boolArg(args, "exclude_tests", false),
+		OnlyProject:     boolArg(args, "only_project", false),
+		ExcludePackages: stringSliceArg(args, "exclude_package"),
+	}
+	keep, err := filter.Keep(idx)
+	if err != nil {
+		return nil, err
+	}
+	walk := graph.WalkFiltered(rootIDs, depth, reverse, keep)
+
+	if aggregate == "package" {
+		return map[string]any{
+			"roots":    walk.Roots,
+			"depth":    walk.Depth,
+			"reverse":  walk.Reverse,
+			"packages": walk.AggregatePackages(),
+		}, nil
+	}
 
 	// MaterializedEdges builds full Definition copies for JSON.
 	// The MCP framework serializes the return value, so we can't stream here.
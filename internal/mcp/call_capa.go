@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/odvcencio/gts-suite/pkg/capa"
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
 func (s *Service) callCapa(args map[string]any) (any, error) {
@@ -48,20 +49,9 @@ func (s *Service) callCapa(args map[string]any) (any, error) {
 		return matches[i].Rule.Name < matches[j].Rule.Name
 	})
 
-	type capaResult struct {
-		Name        string   `json:"name"`
-		AttackID    string   `json:"attack_id"`
-		Category    string   `json:"category"`
-		Confidence  string   `json:"confidence"`
-		Description string   `json:"description"`
-		MatchedAPIs []string `json:"matched_apis"`
-		Files       []string `json:"files"`
-		Functions   []string `json:"functions"`
-	}
-
-	results := make([]capaResult, 0, len(matches))
+	results := make([]report.Capability, 0, len(matches))
 	for _, m := range matches {
-		results = append(results, capaResult{
+		results = append(results, report.Capability{
 			Name:        m.Rule.Name,
 			AttackID:    m.Rule.AttackID,
 			Category:    m.Rule.Category,
@@ -10,7 +10,7 @@ import (
 func (s *Service) callChunk(args map[string]any) (any, error) {
 	target := s.stringArgOrDefault(args, "path", s.defaultRoot)
 	cachePath := s.stringArgOrDefault(args, "cache", s.defaultCache)
-	tokens := intArg(args, "tokens", 800)
+	tokens := intArg(args, "tokens", s.tokens)
 	if tokens <= 0 {
 		return nil, fmt.Errorf("tokens must be > 0")
 	}
@@ -24,12 +24,23 @@ func (s *Service) callChunk(args map[string]any) (any, error) {
 	if strings.TrimSpace(cachePath) != "" && strings.TrimSpace(target) != "" {
 		filterPath = target
 	}
-	report, err := chunk.Build(idx, chunk.Options{
+	opts := chunk.Options{
 		TokenBudget: tokens,
 		FilterPath:  filterPath,
-	})
+	}
+	if since := strings.TrimSpace(stringArg(args, "since_manifest")); since != "" {
+		previous, err := chunk.LoadManifest(since)
+		if err != nil {
+			return nil, err
+		}
+		if previous == nil {
+			previous = []chunk.Chunk{}
+		}
+		opts.Previous = previous
+	}
+	report, err := chunk.Build(idx, opts)
 	if err != nil {
 		return nil, err
 	}
-	return report, nil
+	return report.Changes(), nil
 }
@@ -13,9 +13,11 @@ func (s *Service) callContext(args map[string]any) (any, error) {
 	rootPath := s.stringArgOrDefault(args, "root", s.defaultRoot)
 	cachePath := s.stringArgOrDefault(args, "cache", s.defaultCache)
 	line := intArg(args, "line", 1)
-	tokens := intArg(args, "tokens", 800)
+	tokens := intArg(args, "tokens", s.tokens)
 	semantic := boolArg(args, "semantic", false)
 	semanticDepth := intArg(args, "semantic_depth", 1)
+	callers := intArg(args, "callers", 0)
+	compact := boolArg(args, "compact", false)
 
 	idx, err := s.loadOrBuild(cachePath, rootPath)
 	if err != nil {
@@ -29,6 +31,8 @@ func (s *Service) callContext(args map[string]any) (any, error) {
 		TokenBudget:   tokens,
 		Semantic:      semantic,
 		SemanticDepth: semanticDepth,
+		Callers:       callers,
+		Compact:       compact,
 	})
 	if err != nil {
 		return nil, err
@@ -5,19 +5,22 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/odvcencio/gts-suite/internal/deadroots"
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
 func (s *Service) callDead(args map[string]any) (any, error) {
 	mode := strings.ToLower(strings.TrimSpace(s.stringArgOrDefault(args, "kind", "callable")))
 	switch mode {
-	case "callable", "function", "method":
+	case "callable", "function", "method", "component", "hook":
 	default:
-		return nil, fmt.Errorf("unsupported kind %q (expected callable|function|method)", mode)
+		return nil, fmt.Errorf("unsupported kind %q (expected callable|function|method|component|hook)", mode)
 	}
 
 	includeEntrypoints := boolArg(args, "include_entrypoints", false)
 	includeTests := boolArg(args, "include_tests", false)
+	unexportedOnly := boolArg(args, "unexported_only", false)
 	target := s.stringArgOrDefault(args, "path", s.defaultRoot)
 	cachePath := s.stringArgOrDefault(args, "cache", s.defaultCache)
 
@@ -31,38 +34,41 @@ func (s *Service) callDead(args map[string]any) (any, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	type deadMatch struct {
-		File      string `json:"file"`
-		Package   string `json:"package"`
-		Kind      string `json:"kind"`
-		Name      string `json:"name"`
-		Signature string `json:"signature,omitempty"`
-		StartLine int    `json:"start_line"`
-		EndLine   int    `json:"end_line"`
-		Incoming  int    `json:"incoming"`
-		Outgoing  int    `json:"outgoing"`
+	var roots *deadroots.Roots
+	if boolArg(args, "dynamic_roots", true) {
+		decorators := append(append([]string(nil), deadroots.DefaultDecorators...), stringSliceArg(args, "root_decorators")...)
+		if roots, err = deadroots.Find(idx, deadroots.Options{Decorators: decorators}); err != nil {
+			return nil, err
+		}
 	}
 
-	matches := make([]deadMatch, 0, 64)
-	scanned := 0
+	matches := make([]report.DeadMatch, 0, 64)
+	scanned, dynamicRoots := 0, 0
+	topLevel := graph.TopLevelCallNames()
 	for _, definition := range graph.Definitions {
 		if !deadKindAllowed(definition, mode) {
 			continue
 		}
-		if !includeEntrypoints && isEntrypointDefinition(definition) {
+		if !includeEntrypoints && isEntrypointDefinition(definition, topLevel) {
 			continue
 		}
 		if !includeTests && isTestSourceFile(definition.File) {
 			continue
 		}
+		if unexportedOnly && definition.Exported {
+			continue
+		}
 
 		scanned++
 		incoming := graph.IncomingCount(definition.ID)
 		if incoming > 0 {
 			continue
 		}
-		matches = append(matches, deadMatch{
+		if _, ok := roots.Reason(definition.File, definition.Name, definition.StartLine); ok {
+			dynamicRoots++
+			continue
+		}
+		matches = append(matches, report.DeadMatch{
 			File:      definition.File,
 			Package:   definition.Package,
 			Kind:      definition.Kind,
@@ -70,6 +76,7 @@ func (s *Service) callDead(args map[string]any) (any, error) {
 			Signature: definition.Signature,
 			StartLine: definition.StartLine,
 			EndLine:   definition.EndLine,
+			Exported:  definition.Exported,
 			Incoming:  incoming,
 			Outgoing:  graph.OutgoingCount(definition.ID),
 		})
@@ -86,9 +93,10 @@ func (s *Service) callDead(args map[string]any) (any, error) {
 	})
 
 	return map[string]any{
-		"kind":    mode,
-		"scanned": scanned,
-		"count":   len(matches),
-		"matches": matches,
+		"kind":          mode,
+		"scanned":       scanned,
+		"dynamic_roots": dynamicRoots,
+		"count":         len(matches),
+		"matches":       matches,
 	}, nil
 }
@@ -3,11 +3,11 @@ package mcp
 import (
 	"sort"
 
-	"github.com/odvcencio/gts-suite/pkg/query"
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
 func (s *Service) callGrep(args map[string]any) (any, error) {
-	selectorRaw, err := requiredStringArg(args, "selector")
+	selector, err := selectorArg(args, "selector")
 	if err != nil {
 		return nil, err
 	}
@@ -20,27 +20,10 @@ func (s *Service) callGrep(args map[string]any) (any, error) {
 	}
 	idx = applyGeneratedFilter(idx, boolArg(args, "include_generated", false), stringArg(args, "generator"))
 
-	selector, err := query.ParseSelector(selectorRaw)
-	if err != nil {
-		return nil, err
-	}
-
-	type grepMatch struct {
-		File      string `json:"file"`
-		Kind      string `json:"kind"`
-		Name      string `json:"name"`
-		Signature string `json:"signature,omitempty"`
-		StartLine int    `json:"start_line"`
-		EndLine   int    `json:"end_line"`
-	}
-
-	matches := make([]grepMatch, 0, idx.SymbolCount())
+	matches := make([]report.SymbolMatch, 0, idx.SymbolCount())
 	for _, file := range idx.Files {
-		for _, symbol := range file.Symbols {
-			if !selector.Match(symbol) {
-				continue
-			}
-			matches = append(matches, grepMatch{
+		for _, symbol := range selector.MatchFile(file.Symbols) {
+			matches = append(matches, report.SymbolMatch{
 				File:      file.Path,
 				Kind:      symbol.Kind,
 				Name:      symbol.Name,
@@ -1,7 +1,10 @@
 package mcp
 
 import (
+	"fmt"
+
 	"github.com/odvcencio/gts-suite/pkg/impact"
+	"github.com/odvcencio/gts-suite/pkg/index"
 )
 
 func (s *Service) callImpact(args map[string]any) (any, error) {
@@ -11,6 +14,23 @@ func (s *Service) callImpact(args map[string]any) (any, error) {
 	maxDepth := intArg(args, "max_depth", 10)
 	changed := stringSliceArg(args, "changed")
 
+	beforeCache := stringArg(args, "before_cache")
+	afterCache := stringArg(args, "after_cache")
+	if (beforeCache == "") != (afterCache == "") {
+		return nil, fmt.Errorf("before_cache and after_cache must be used together")
+	}
+	if beforeCache != "" {
+		before, err := index.Load(beforeCache)
+		if err != nil {
+			return nil, fmt.Errorf("load before snapshot: %w", err)
+		}
+		after, err := index.Load(afterCache)
+		if err != nil {
+			return nil, fmt.Errorf("load after snapshot: %w", err)
+		}
+		return impact.AnalyzeSnapshots(before, after, maxDepth)
+	}
+
 	idx, err := s.loadOrBuild(cachePath, target)
 	if err != nil {
 		return nil, err
@@ -1,9 +1,13 @@
 package mcp
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 
+	"github.com/odvcencio/gts-suite/internal/outline"
 	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
 func (s *Service) callMap(args map[string]any) (any, error) {
@@ -15,28 +19,57 @@ func (s *Service) callMap(args map[string]any) (any, error) {
 		return nil, err
 	}
 	idx = applyGeneratedFilter(idx, boolArg(args, "include_generated", false), stringArg(args, "generator"))
+	idx = idx.FilterByGlobs(stringSliceArg(args, "file"))
 
-	type mapFileSummary struct {
-		Path           string            `json:"path"`
-		Language       string            `json:"language"`
-		Imports        []string          `json:"imports,omitempty"`
-		Symbols        []model.Symbol    `json:"symbols,omitempty"`
-		References     []model.Reference `json:"references,omitempty"`
-		SymbolCount    int               `json:"symbol_count"`
-		ReferenceCount int               `json:"reference_count"`
+	if depthArg := stringArg(args, "depth"); depthArg != "" {
+		depth, err := outline.ParseDepth(depthArg)
+		if err != nil {
+			return nil, err
+		}
+		outlines := make([]outline.File, 0, len(idx.Files))
+		for _, file := range idx.Files {
+			outlines = append(outlines, outline.Build(file, depth))
+		}
+		return map[string]any{
+			"root":       idx.Root,
+			"depth":      depth,
+			"file_count": len(outlines),
+			"files":      outlines,
+		}, nil
 	}
 
-	files := make([]mapFileSummary, 0, len(idx.Files))
+	detail := strings.ToLower(strings.TrimSpace(stringArg(args, "detail")))
+	if detail == "" {
+		detail = "summary"
+	}
+	if detail != "summary" && detail != "symbols" && detail != "full" {
+		return nil, fmt.Errorf("unsupported detail %q (expected summary|symbols|full)", detail)
+	}
+
+	files := make([]report.MapFile, 0, len(idx.Files))
 	for _, file := range idx.Files {
-		files = append(files, mapFileSummary{
+		summary := report.MapFile{
 			Path:           file.Path,
 			Language:       file.Language,
-			Imports:        append([]string(nil), file.Imports...),
-			Symbols:        append([]model.Symbol(nil), file.Symbols...),
-			References:     append([]model.Reference(nil), file.References...),
+			ImportCount:    len(file.Imports),
 			SymbolCount:    len(file.Symbols),
 			ReferenceCount: len(file.References),
-		})
+		}
+		switch detail {
+		case "summary":
+			for _, symbol := range file.Symbols {
+				if symbol.ContainerPath == "" {
+					summary.TopLevel = append(summary.TopLevel, report.MapSymbol{Kind: symbol.Kind, Name: symbol.Name, Line: symbol.StartLine})
+				}
+			}
+		case "full":
+			summary.References = append([]model.Reference(nil), file.References...)
+			fallthrough
+		case "symbols":
+			summary.Imports = append([]string(nil), file.Imports...)
+			summary.Symbols = append([]model.Symbol(nil), file.Symbols...)
+		}
+		files = append(files, summary)
 	}
 	sort.Slice(files, func(i, j int) bool {
 		return files[i].Path < files[j].Path
@@ -45,6 +78,7 @@ func (s *Service) callMap(args map[string]any) (any, error) {
 	return map[string]any{
 		"root":            idx.Root,
 		"generated_at":    idx.GeneratedAt,
+		"detail":          detail,
 		"file_count":      len(files),
 		"symbol_count":    idx.SymbolCount(),
 		"reference_count": idx.ReferenceCount(),
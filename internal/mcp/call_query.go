@@ -1,16 +1,18 @@
 package mcp
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync/atomic"
 
-	"github.com/odvcencio/gotreesitter"
-	"github.com/odvcencio/gotreesitter/grammars"
+	"github.com/odvcencio/gts-suite/internal/parsesession"
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
-func (s *Service) callQuery(args map[string]any) (any, error) {
+func (s *Service) callQuery(ctx context.Context, args map[string]any) (any, error) {
 	pattern, err := requiredStringArg(args, "pattern")
 	if err != nil {
 		return nil, err
@@ -25,70 +27,45 @@ func (s *Service) callQuery(args map[string]any) (any, error) {
 	}
 	idx = applyGeneratedFilter(idx, boolArg(args, "include_generated", false), stringArg(args, "generator"))
 
+	// Parsers poll this flag so a cancelled call stops mid-parse instead of
+	// finishing a pathological file first.
+	var cancelFlag uint32
+	stopCancel := context.AfterFunc(ctx, func() {
+		atomic.StoreUint32(&cancelFlag, 1)
+	})
+	defer stopCancel()
+
 	captureFilter := map[string]bool{}
 	for _, capture := range captures {
 		captureFilter[strings.TrimSpace(capture)] = true
 	}
 
-	entriesByLanguage := map[string]grammars.LangEntry{}
-	for _, entry := range grammars.AllLanguages() {
-		if strings.TrimSpace(entry.Name) == "" || entry.Language == nil {
-			continue
-		}
-		entriesByLanguage[entry.Name] = entry
-	}
-
-	queryByLanguage := map[string]*gotreesitter.Query{}
+	session := parsesession.New()
+	session.SetCancellationFlag(&cancelFlag)
 	queryErrorByLanguage := map[string]string{}
-	langByName := map[string]*gotreesitter.Language{}
-	parserByLanguage := map[string]*gotreesitter.Parser{}
-
-	type queryCaptureMatch struct {
-		File        string `json:"file"`
-		Language    string `json:"language"`
-		Pattern     int    `json:"pattern"`
-		Capture     string `json:"capture"`
-		NodeType    string `json:"node_type"`
-		Text        string `json:"text"`
-		StartLine   int    `json:"start_line"`
-		EndLine     int    `json:"end_line"`
-		StartColumn int    `json:"start_column"`
-		EndColumn   int    `json:"end_column"`
-	}
-	type queryLanguageError struct {
-		Language string `json:"language"`
-		Error    string `json:"error"`
-	}
 
-	results := make([]queryCaptureMatch, 0, idx.SymbolCount())
+	results := make([]report.QueryMatch, 0, idx.SymbolCount())
 	for _, file := range idx.Files {
-		entry, ok := entriesByLanguage[file.Language]
-		if !ok {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !session.Supported(file.Language) {
 			continue
 		}
 		if _, failed := queryErrorByLanguage[file.Language]; failed {
 			continue
 		}
 
-		lang, ok := langByName[file.Language]
+		lang, ok := session.Language(file.Language)
 		if !ok {
-			lang = entry.Language()
-			if lang == nil {
-				queryErrorByLanguage[file.Language] = "language loader returned nil"
-				continue
-			}
-			langByName[file.Language] = lang
+			queryErrorByLanguage[file.Language] = "language loader returned nil"
+			continue
 		}
 
-		queryForLanguage, ok := queryByLanguage[file.Language]
-		if !ok {
-			compiled, compileErr := gotreesitter.NewQuery(pattern, lang)
-			if compileErr != nil {
-				queryErrorByLanguage[file.Language] = compileErr.Error()
-				continue
-			}
-			queryByLanguage[file.Language] = compiled
-			queryForLanguage = compiled
+		queryForLanguage, compileErr := session.Query(file.Language, pattern)
+		if compileErr != nil {
+			queryErrorByLanguage[file.Language] = compileErr.Error()
+			continue
 		}
 
 		sourcePath := filepath.Join(idx.Root, filepath.FromSlash(file.Path))
@@ -97,32 +74,25 @@ func (s *Service) callQuery(args map[string]any) (any, error) {
 			return nil, readErr
 		}
 
-		parser, ok := parserByLanguage[file.Language]
-		if !ok {
-			parser = gotreesitter.NewParser(lang)
-			parserByLanguage[file.Language] = parser
-		}
-
-		var tree *gotreesitter.Tree
-		var parseErr error
-		if entry.TokenSourceFactory != nil {
-			tokenSource := entry.TokenSourceFactory(source, lang)
-			if tokenSource != nil {
-				tree, parseErr = parser.ParseWithTokenSource(source, tokenSource)
-			}
-		}
-		if tree == nil && parseErr == nil {
-			tree, parseErr = parser.Parse(source)
+		tree, parseErr := session.Parse(file.Language, source)
+		if err := ctx.Err(); err != nil {
+			tree.Release()
+			return nil, err
 		}
 		if parseErr != nil {
 			continue
 		}
-		if tree == nil || tree.RootNode() == nil {
-			continue
-		}
 
-		matches := queryForLanguage.Execute(tree)
-		for _, match := range matches {
+		cursor := queryForLanguage.Exec(tree.RootNode(), lang, source)
+		for {
+			if err := ctx.Err(); err != nil {
+				tree.Release()
+				return nil, err
+			}
+			match, ok := cursor.NextMatch()
+			if !ok {
+				break
+			}
 			for _, capture := range match.Captures {
 				if len(captureFilter) > 0 && !captureFilter[capture.Name] {
 					continue
@@ -141,7 +111,7 @@ func (s *Service) callQuery(args map[string]any) (any, error) {
 				if endColumn < startColumn {
 					endColumn = startColumn
 				}
-				results = append(results, queryCaptureMatch{
+				results = append(results, report.QueryMatch{
 					File:        file.Path,
 					Language:    file.Language,
 					Pattern:     match.PatternIndex,
@@ -171,9 +141,9 @@ func (s *Service) callQuery(args map[string]any) (any, error) {
 		return results[i].File < results[j].File
 	})
 
-	languageErrors := make([]queryLanguageError, 0, len(queryErrorByLanguage))
+	languageErrors := make([]report.QueryLanguageError, 0, len(queryErrorByLanguage))
 	for language, value := range queryErrorByLanguage {
-		languageErrors = append(languageErrors, queryLanguageError{
+		languageErrors = append(languageErrors, report.QueryLanguageError{
 			Language: language,
 			Error:    value,
 		})
@@ -1,14 +1,14 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/odvcencio/gts-suite/pkg/query"
 	"github.com/odvcencio/gts-suite/pkg/refactor"
 )
 
-func (s *Service) callRefactor(args map[string]any) (any, error) {
-	selectorRaw, err := requiredStringArg(args, "selector")
+func (s *Service) callRefactor(ctx context.Context, args map[string]any) (any, error) {
+	selector, err := selectorArg(args, "selector")
 	if err != nil {
 		return nil, err
 	}
@@ -35,15 +35,13 @@ func (s *Service) callRefactor(args map[string]any) (any, error) {
 	}
 	idx = applyGeneratedFilter(idx, boolArg(args, "include_generated", false), stringArg(args, "generator"))
 
-	selector, err := query.ParseSelector(selectorRaw)
-	if err != nil {
-		return nil, err
-	}
-	report, err := refactor.RenameDeclarations(idx, selector, newName, refactor.Options{
+	report, err := refactor.RenameDeclarationsContext(ctx, idx, selector, newName, refactor.Options{
 		Write:                 writeChanges,
 		UpdateCallsites:       updateCallsites,
 		CrossPackageCallsites: crossPackage,
 		Engine:                engine,
+		UpdateComments:        boolArg(args, "update_comments", false),
+		UpdateStrings:         boolArg(args, "update_strings", false),
 	})
 	if err != nil {
 		return nil, err
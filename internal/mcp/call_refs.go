@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
 func (s *Service) callRefs(args map[string]any) (any, error) {
@@ -29,23 +31,25 @@ func (s *Service) callRefs(args map[string]any) (any, error) {
 		}
 		matchReference = compiled.MatchString
 	}
-
-	type referenceMatch struct {
-		File        string `json:"file"`
-		Kind        string `json:"kind"`
-		Name        string `json:"name"`
-		StartLine   int    `json:"start_line"`
-		EndLine     int    `json:"end_line"`
-		StartColumn int    `json:"start_column"`
-		EndColumn   int    `json:"end_column"`
+	var qualifierRE *regexp.Regexp
+	if raw := stringArg(args, "qualifier"); raw != "" {
+		compiled, compileErr := regexp.Compile(raw)
+		if compileErr != nil {
+			return nil, fmt.Errorf("compile qualifier: %w", compileErr)
+		}
+		qualifierRE = compiled
 	}
-	matches := make([]referenceMatch, 0, idx.ReferenceCount())
+
+	matches := make([]report.ReferenceMatch, 0, idx.ReferenceCount())
 	for _, file := range idx.Files {
 		for _, reference := range file.References {
 			if !matchReference(reference.Name) {
 				continue
 			}
-			matches = append(matches, referenceMatch{
+			if qualifierRE != nil && !qualifierRE.MatchString(reference.Qualifier) {
+				continue
+			}
+			matches = append(matches, report.ReferenceMatch{
 				File:        file.Path,
 				Kind:        reference.Kind,
 				Name:        reference.Name,
@@ -53,6 +57,7 @@ func (s *Service) callRefs(args map[string]any) (any, error) {
 				EndLine:     reference.EndLine,
 				StartColumn: reference.StartColumn,
 				EndColumn:   reference.EndColumn,
+				Qualifier:   reference.Qualifier,
 			})
 		}
 	}
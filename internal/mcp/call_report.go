@@ -6,36 +6,10 @@ import (
 	"github.com/odvcencio/gts-suite/pkg/capa"
 	"github.com/odvcencio/gts-suite/pkg/complexity"
 	"github.com/odvcencio/gts-suite/pkg/hotspot"
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
-type mcpReportResult struct {
-	Files        int            `json:"files"`
-	Languages    map[string]int `json:"languages"`
-	TotalSymbols int            `json:"total_symbols"`
-	GeneratedPct int            `json:"generated_pct"`
-
-	FunctionCount int `json:"function_count"`
-	CyclomaticMax int `json:"cyclomatic_max"`
-	CyclomaticP90 int `json:"cyclomatic_p90"`
-	CognitiveMax  int `json:"cognitive_max"`
-
-	BoundaryViolations int `json:"boundary_violations"`
-	ImportCycles       int `json:"import_cycles"`
-
-	Capabilities  int `json:"capabilities"`
-	DeadFunctions int `json:"dead_functions"`
-
-	Hotspots []mcpHotspotEntry `json:"hotspots,omitempty"`
-}
-
-type mcpHotspotEntry struct {
-	File       string  `json:"file"`
-	Name       string  `json:"name"`
-	Cyclomatic int     `json:"cyclomatic"`
-	Score      float64 `json:"score"`
-}
-
 func (s *Service) callReport(args map[string]any) (any, error) {
 	target := s.stringArgOrDefault(args, "path", s.defaultRoot)
 	cachePath := s.stringArgOrDefault(args, "cache", s.defaultCache)
@@ -46,7 +20,7 @@ func (s *Service) callReport(args map[string]any) (any, error) {
 	}
 	analysisIdx := applyGeneratedFilter(idx, boolArg(args, "include_generated", false), stringArg(args, "generator"))
 
-	rpt := mcpReportResult{
+	rpt := report.ExecutiveReport{
 		Languages: make(map[string]int),
 	}
 
@@ -116,11 +90,12 @@ func (s *Service) callReport(args map[string]any) (any, error) {
 	xrefGraph, xrefErr := xref.Build(analysisIdx)
 	if xrefErr == nil {
 		deadCount := 0
+		topLevel := xrefGraph.TopLevelCallNames()
 		for _, definition := range xrefGraph.Definitions {
 			if !definition.Callable {
 				continue
 			}
-			if isEntrypointDefinition(definition) {
+			if isEntrypointDefinition(definition, topLevel) {
 				continue
 			}
 			if isTestSourceFile(definition.File) {
@@ -141,7 +116,7 @@ func (s *Service) callReport(args map[string]any) (any, error) {
 	})
 	if hotspotErr == nil {
 		for _, h := range hotspotReport.Functions {
-			rpt.Hotspots = append(rpt.Hotspots, mcpHotspotEntry{
+			rpt.Hotspots = append(rpt.Hotspots, report.ExecutiveHotspot{
 				File:       h.File,
 				Name:       h.Name,
 				Cyclomatic: h.Cyclomatic,
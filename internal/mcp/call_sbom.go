@@ -67,6 +67,8 @@ func (s *Service) callSBOM(args map[string]any) (any, error) {
 	}
 	capaByAPI := sbomBuildCapaIndex(capaMatches)
 
+	// The BOM follows the CycloneDX 1.5 JSON format rather than a pkg/report
+	// document, so these types stay local.
 	type bomProperty struct {
 		Name  string `json:"name"`
 		Value string `json:"value"`
@@ -13,6 +13,7 @@ func (s *Service) callScope(args map[string]any) (any, error) {
 	rootPath := s.stringArgOrDefault(args, "root", s.defaultRoot)
 	cachePath := s.stringArgOrDefault(args, "cache", s.defaultCache)
 	line := intArg(args, "line", 1)
+	column := intArg(args, "column", 0)
 
 	idx, err := s.loadOrBuild(cachePath, rootPath)
 	if err != nil {
@@ -23,6 +24,7 @@ func (s *Service) callScope(args map[string]any) (any, error) {
 	report, err := gtsscope.Build(idx, gtsscope.Options{
 		FilePath: filePath,
 		Line:     line,
+		Column:   column,
 	})
 	if err != nil {
 		return nil, err
@@ -3,6 +3,7 @@ package mcp
 import (
 	"fmt"
 
+	"github.com/odvcencio/gts-suite/pkg/report"
 	"github.com/odvcencio/gts-suite/pkg/similarity"
 )
 
@@ -40,20 +41,9 @@ func (s *Service) callSimilarity(args map[string]any) (any, error) {
 		return nil, err
 	}
 
-	type pairResult struct {
-		FileA     string  `json:"file_a"`
-		NameA     string  `json:"name_a"`
-		LinesA    string  `json:"lines_a"`
-		FileB     string  `json:"file_b"`
-		NameB     string  `json:"name_b"`
-		LinesB    string  `json:"lines_b"`
-		Score     float64 `json:"score"`
-		Method    string  `json:"method"`
-	}
-
-	results := make([]pairResult, 0, len(pairs))
+	results := make([]report.SimilarPair, 0, len(pairs))
 	for _, p := range pairs {
-		results = append(results, pairResult{
+		results = append(results, report.SimilarPair{
 			FileA:  p.A.File,
 			NameA:  p.A.Name,
 			LinesA: formatLines(p.A.StartLine, p.A.EndLine),
@@ -3,15 +3,26 @@ package mcp
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/odvcencio/gts-suite/pkg/index"
 	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/query"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
 func (s *Service) loadOrBuild(cachePath string, target string) (*model.Index, error) {
+	idx, err := s.loadOrBuildUnchecked(cachePath, target)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkIndexRoot(idx.Root); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+func (s *Service) loadOrBuildUnchecked(cachePath string, target string) (*model.Index, error) {
 	if strings.TrimSpace(cachePath) != "" {
 		return index.Load(cachePath)
 	}
@@ -20,7 +31,7 @@ func (s *Service) loadOrBuild(cachePath string, target string) (*model.Index, er
 		target = s.defaultRoot
 	}
 	// Auto-discover cached index
-	autoPath := filepath.Join(target, ".gts", "index.json")
+	autoPath := s.config.CachePath(target)
 	if _, err := os.Stat(autoPath); err == nil {
 		if idx, loadErr := index.Load(autoPath); loadErr == nil {
 			if idx.ConfigHashes == nil {
@@ -41,6 +52,17 @@ func (s *Service) loadOrBuild(cachePath string, target string) (*model.Index, er
 }
 
 func (s *Service) loadIndexFromSource(pathArg, cacheArg string) (*model.Index, error) {
+	idx, err := s.loadIndexFromSourceUnchecked(pathArg, cacheArg)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkIndexRoot(idx.Root); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+func (s *Service) loadIndexFromSourceUnchecked(pathArg, cacheArg string) (*model.Index, error) {
 	cachePath := strings.TrimSpace(cacheArg)
 	if cachePath != "" {
 		return index.Load(cachePath)
@@ -50,7 +72,7 @@ func (s *Service) loadIndexFromSource(pathArg, cacheArg string) (*model.Index, e
 	if target == "" {
 		target = s.defaultRoot
 	}
-	autoPath := filepath.Join(target, ".gts", "index.json")
+	autoPath := s.config.CachePath(target)
 	if _, err := os.Stat(autoPath); err == nil {
 		if idx, loadErr := index.Load(autoPath); loadErr == nil {
 			if idx.ConfigHashes == nil {
@@ -78,6 +100,16 @@ func requiredStringArg(args map[string]any, key string) (string, error) {
 	return value, nil
 }
 
+// selectorArg parses a selector argument given as one string or an array of
+// alternatives.
+func selectorArg(args map[string]any, key string) (query.Selector, error) {
+	raws := stringSliceArg(args, key)
+	if len(raws) == 0 {
+		return query.Selector{}, fmt.Errorf("missing required argument %q", key)
+	}
+	return query.ParseSelectors(raws)
+}
+
 func stringArg(args map[string]any, key string) string {
 	raw, ok := args[key]
 	if !ok || raw == nil {
@@ -198,19 +230,29 @@ func deadKindAllowed(definition xref.Definition, mode string) bool {
 	case "callable":
 		return definition.Callable
 	case "function":
-		return definition.Kind == "function_definition"
+		return model.IsFunctionKind(definition.Kind)
 	case "method":
 		return definition.Kind == "method_definition"
+	case "component":
+		return definition.Kind == "component_definition"
+	case "hook":
+		return definition.Kind == "hook_definition"
 	default:
 		return false
 	}
 }
 
-func isEntrypointDefinition(definition xref.Definition) bool {
-	if definition.Kind != "function_definition" {
-		return false
+// isEntrypointDefinition reports whether definition is a program entry point:
+// main or init, or a component rendered outside any function, as a React
+// app's root is.
+func isEntrypointDefinition(definition xref.Definition, topLevel map[string]bool) bool {
+	switch definition.Kind {
+	case "function_definition":
+		return definition.Name == "main" || definition.Name == "init"
+	case "component_definition":
+		return topLevel[definition.Name]
 	}
-	return definition.Name == "main" || definition.Name == "init"
+	return false
 }
 
 func isTestSourceFile(path string) bool {
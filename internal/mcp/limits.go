@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// callLimiter bounds how many tool calls run at once and how quickly new
+// calls may start. A zero-value limiter imposes no limits.
+type callLimiter struct {
+	slots chan struct{}
+
+	mu     sync.Mutex
+	rate   float64 // tokens added per second; <= 0 disables rate limiting
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newCallLimiter(maxConcurrent int, ratePerSecond float64, burst int) *callLimiter {
+	limiter := &callLimiter{
+		rate: ratePerSecond,
+		now:  time.Now,
+	}
+	if maxConcurrent > 0 {
+		limiter.slots = make(chan struct{}, maxConcurrent)
+	}
+	if ratePerSecond > 0 {
+		if burst <= 0 {
+			burst = int(ratePerSecond)
+			if burst < 1 {
+				burst = 1
+			}
+		}
+		limiter.burst = float64(burst)
+		limiter.tokens = limiter.burst
+	}
+	return limiter
+}
+
+// acquire reserves a rate-limit token and a concurrency slot. Rate-limited
+// calls are rejected immediately; concurrency waits until a slot frees up or
+// ctx is done. Callers must call release after a successful acquire.
+func (l *callLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if !l.allow() {
+		return fmt.Errorf("rate limit exceeded: at most %g tool calls per second", l.rate)
+	}
+	if l.slots == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *callLimiter) release() {
+	if l == nil || l.slots == nil {
+		return
+	}
+	<-l.slots
+}
+
+func (l *callLimiter) allow() bool {
+	if l.rate <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// toolTimeout returns the effective timeout for the named tool, preferring a
+// per-tool override over the service-wide default.
+func (s *Service) toolTimeout(name string) time.Duration {
+	if timeout, ok := s.toolTimeouts[strings.TrimSpace(name)]; ok {
+		return timeout
+	}
+	return s.callTimeout
+}
+
+// ParseToolTimeouts parses "tool=duration" pairs (e.g. "gts_query=30s") into a
+// per-tool timeout map.
+func ParseToolTimeouts(values []string) (map[string]time.Duration, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	timeouts := make(map[string]time.Duration, len(values))
+	for _, value := range values {
+		name, raw, ok := strings.Cut(strings.TrimSpace(value), "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid tool timeout %q (expected tool=duration)", value)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool timeout %q: %w", value, err)
+		}
+		if timeout < 0 {
+			return nil, fmt.Errorf("invalid tool timeout %q: must be >= 0", value)
+		}
+		timeouts[name] = timeout
+	}
+	return timeouts, nil
+}
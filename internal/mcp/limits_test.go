@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCallLimiterRateLimit(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := newCallLimiter(0, 2, 2)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := limiter.acquire(ctx); err != nil {
+			t.Fatalf("acquire %d within burst failed: %v", i, err)
+		}
+		limiter.release()
+	}
+	if err := limiter.acquire(ctx); err == nil {
+		t.Fatal("expected rate limit error after burst exhausted")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if err := limiter.acquire(ctx); err != nil {
+		t.Fatalf("expected token refill after 500ms, got %v", err)
+	}
+}
+
+func TestCallLimiterConcurrencyWaitsForContext(t *testing.T) {
+	limiter := newCallLimiter(1, 0, 0)
+	if err := limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded while slot held, got %v", err)
+	}
+
+	limiter.release()
+	if err := limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire after release failed: %v", err)
+	}
+}
+
+func TestParseToolTimeouts(t *testing.T) {
+	timeouts, err := ParseToolTimeouts([]string{"gts_query=30s", " gts_map = 1m "})
+	if err != nil {
+		t.Fatalf("ParseToolTimeouts returned error: %v", err)
+	}
+	if timeouts["gts_query"] != 30*time.Second {
+		t.Fatalf("expected gts_query=30s, got %s", timeouts["gts_query"])
+	}
+	if timeouts["gts_map"] != time.Minute {
+		t.Fatalf("expected gts_map=1m, got %s", timeouts["gts_map"])
+	}
+
+	for _, bad := range []string{"gts_query", "=5s", "gts_query=soon", "gts_query=-1s"} {
+		if _, err := ParseToolTimeouts([]string{bad}); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestServiceCallContextCancelsQuery(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package sample\n\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	service := NewService(tmpDir, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := service.CallContext(ctx, "gts_query", map[string]any{
+		"pattern": "(function_declaration) @fn",
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled from cancelled query, got %v", err)
+	}
+}
+
+func TestServiceToolTimeoutOverride(t *testing.T) {
+	service := NewServiceWithOptions(".", "", ServiceOptions{
+		CallTimeout:  time.Minute,
+		ToolTimeouts: map[string]time.Duration{"gts_query": time.Nanosecond},
+	})
+	if got := service.toolTimeout("gts_map"); got != time.Minute {
+		t.Fatalf("expected default timeout for gts_map, got %s", got)
+	}
+	if got := service.toolTimeout("gts_query"); got != time.Nanosecond {
+		t.Fatalf("expected override timeout for gts_query, got %s", got)
+	}
+
+	_, err := service.Call("gts_query", map[string]any{"pattern": "(identifier) @id"})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestServiceTimedOutRefactorDoesNotWrite(t *testing.T) {
+	dir := t.TempDir()
+	source := "package sample\n\nfunc Old() {}\n"
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	service := NewServiceWithOptions(dir, "", ServiceOptions{
+		AllowWrites:        true,
+		MaxConcurrentCalls: 1,
+		ToolTimeouts:       map[string]time.Duration{"gts_refactor": time.Nanosecond},
+	})
+
+	_, err := service.Call("gts_refactor", map[string]any{
+		"selector": "function_definition[name=/^Old$/]",
+		"new_name": "New",
+		"write":    true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	// With one slot, this call waits for any refactor still running.
+	if _, err := service.Call("gts_map", map[string]any{}); err != nil {
+		t.Fatalf("gts_map returned error: %v", err)
+	}
+	data, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatalf("ReadFile failed: %v", readErr)
+	}
+	if string(data) != source {
+		t.Fatalf("expected a timed-out refactor to leave files alone, got:\n%s", data)
+	}
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// sandboxedPathArgs lists tool arguments that name filesystem locations and
+// must therefore resolve inside the service's allowed roots.
+var sandboxedPathArgs = []string{
+	"path",
+	"cache",
+	"root",
+	"path_a",
+	"path_b",
+	"cache_a",
+	"cache_b",
+	"before_path",
+	"before_cache",
+	"after_path",
+	"after_cache",
+	"federation",
+	"since_manifest",
+}
+
+// checkSandbox rejects tool arguments that resolve outside the allowed roots.
+// The "file" argument is interpreted relative to the call's root, matching how
+// gts_context and gts_scope resolve it.
+func (s *Service) checkSandbox(args map[string]any) error {
+	if !s.roots.Enabled() {
+		return nil
+	}
+	for _, key := range sandboxedPathArgs {
+		value := stringArg(args, key)
+		if value == "" {
+			continue
+		}
+		if err := s.roots.Check(value); err != nil {
+			return fmt.Errorf("argument %q: %w", key, err)
+		}
+	}
+	if file := stringArg(args, "file"); file != "" {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(s.stringArgOrDefault(args, "root", s.defaultRoot), file)
+		}
+		if err := s.roots.Check(file); err != nil {
+			return fmt.Errorf("argument %q: %w", "file", err)
+		}
+	}
+	return nil
+}
+
+// checkIndexRoot rejects indexes whose root lies outside the allowed roots.
+// A cache file inside the sandbox could otherwise point tools at arbitrary
+// directories through its recorded root.
+func (s *Service) checkIndexRoot(root string) error {
+	if !s.roots.Enabled() {
+		return nil
+	}
+	if err := s.roots.Check(root); err != nil {
+		return fmt.Errorf("index root: %w", err)
+	}
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/report"
 )
 
 const serverName = "gts-suite"
@@ -155,11 +157,13 @@ func (s *Server) handleRequest(request rpcRequest) (any, *rpcError) {
 		result, err := s.service.Call(params.Name, params.Arguments)
 		durationMs := time.Since(started).Milliseconds()
 		meta := map[string]any{
-			"tool":        params.Name,
-			"duration_ms": durationMs,
+			"tool":           params.Name,
+			"duration_ms":    durationMs,
+			"schema_version": report.SchemaVersion,
 		}
 		if err != nil {
 			meta["ok"] = false
+			s.audit(params, started, err.Error(), err)
 			return toolCallResult{
 				IsError: true,
 				Content: []toolContent{
@@ -173,10 +177,11 @@ func (s *Server) handleRequest(request rpcRequest) (any, *rpcError) {
 		}
 
 		meta["ok"] = true
-		encoded, encodeErr := json.MarshalIndent(result, "", "  ")
-		if encodeErr != nil {
-			encoded = []byte(`{"error":"failed to encode result"}`)
+		result, encoded, truncated := s.service.limitOutput(params.Name, params.Arguments, result)
+		if truncated {
+			meta["truncated"] = true
 		}
+		s.audit(params, started, string(encoded), nil)
 		return toolCallResult{
 			Content: []toolContent{
 				{
@@ -192,6 +197,14 @@ func (s *Server) handleRequest(request rpcRequest) (any, *rpcError) {
 	}
 }
 
+// audit records a tool call on the service's audit log, reporting a failure
+// to write it on the server log.
+func (s *Server) audit(params toolsCallParams, started time.Time, resultText string, callErr error) {
+	if err := s.service.auditToolCall(params.Name, params.Arguments, started, resultText, callErr); err != nil && s.log != nil {
+		fmt.Fprintf(s.log, "gts mcp: %v\n", err)
+	}
+}
+
 func decodeParams(raw json.RawMessage, out any) error {
 	if len(bytes.TrimSpace(raw)) == 0 {
 		return nil
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -152,6 +153,117 @@ func B() { A() }
 	}
 }
 
+func TestServerToolsCallAuditLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package sample\n\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	audit := bytes.NewBuffer(nil)
+	service := NewServiceWithOptions(tmpDir, "", ServiceOptions{AuditLog: audit})
+
+	requests := bytes.NewBuffer(nil)
+	appendFramedJSON(t, requests, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name": "gts_refs",
+			"arguments": map[string]any{
+				"name":    strings.Repeat("A", 300),
+				"api_key": "hunter2",
+			},
+		},
+	})
+	appendFramedJSON(t, requests, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "missing_tool"},
+	})
+
+	output := bytes.NewBuffer(nil)
+	if err := RunStdio(service, requests, output, bytes.NewBuffer(nil)); err != nil {
+		t.Fatalf("RunStdio returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected an audit line per tool call, got %d:\n%s", len(lines), audit.String())
+	}
+	var first, second AuditEntry
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("decode first audit line: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("decode second audit line: %v", err)
+	}
+
+	if first.Tool != "gts_refs" || first.Error != "" || first.ResultBytes == 0 || first.ResultTokens != (first.ResultBytes+3)/4 {
+		t.Fatalf("unexpected first audit entry %+v", first)
+	}
+	if first.Args["api_key"] != "[redacted]" {
+		t.Fatalf("expected api_key to be redacted, got %#v", first.Args["api_key"])
+	}
+	if name, _ := first.Args["name"].(string); !strings.HasSuffix(name, "…[44 more bytes]") {
+		t.Fatalf("expected a long argument to be cut, got %q", name)
+	}
+	if second.Tool != "missing_tool" || second.Error == "" || second.ResultBytes != len(second.Error) {
+		t.Fatalf("unexpected second audit entry %+v", second)
+	}
+}
+
+func TestServerToolsCallTruncatesOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	var source strings.Builder
+	source.WriteString("package sample\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&source, "\nfunc Function%03d() {}\n", i)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source.String()), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	service := NewServiceWithOptions(tmpDir, "", ServiceOptions{MaxOutputTokens: 1000})
+	requests := bytes.NewBuffer(nil)
+	appendFramedJSON(t, requests, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "gts_map"},
+	})
+
+	output := bytes.NewBuffer(nil)
+	if err := RunStdio(service, requests, output, bytes.NewBuffer(nil)); err != nil {
+		t.Fatalf("RunStdio returned error: %v", err)
+	}
+
+	response, _ := decodeFramedJSON(t, output.Bytes())
+	result, ok := response["result"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected result map, got %T", response["result"])
+	}
+	meta, _ := result["_meta"].(map[string]any)
+	if truncated, _ := meta["truncated"].(bool); !truncated {
+		t.Fatalf("expected _meta.truncated=true, got %#v", meta)
+	}
+	content, _ := result["content"].([]any)
+	if len(content) != 1 {
+		t.Fatalf("expected one content item, got %#v", result["content"])
+	}
+	text, _ := content[0].(map[string]any)["text"].(string)
+	if len(text) > 4000 {
+		t.Fatalf("expected at most 4000 bytes of output, got %d", len(text))
+	}
+	structured, _ := result["structuredContent"].(map[string]any)
+	if truncated, _ := structured["truncated"].(bool); !truncated {
+		t.Fatalf("expected structuredContent.truncated=true")
+	}
+	if _, ok := structured["truncation"].(map[string]any); !ok {
+		t.Fatalf("expected a truncation report, got %#v", structured["truncation"])
+	}
+}
+
 func appendFramedJSON(t *testing.T, buffer *bytes.Buffer, value any) {
 	t.Helper()
 	payload, err := json.Marshal(value)
@@ -2,9 +2,15 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/odvcencio/gts-suite/internal/config"
+	"github.com/odvcencio/gts-suite/pkg/sandbox"
 )
 
 type Tool struct {
@@ -16,11 +22,52 @@ type Tool struct {
 type Service struct {
 	defaultRoot  string
 	defaultCache string
+	tokens       int
+	config       *config.Config
 	allowWrites  bool
+	callTimeout  time.Duration
+	toolTimeouts map[string]time.Duration
+	limiter      *callLimiter
+	roots        *sandbox.Roots
+	audit        *auditLog
+	// maxOutputBytes is the default output budget of a call; 0 is unlimited.
+	maxOutputBytes int
 }
 
 type ServiceOptions struct {
 	AllowWrites bool
+	// CallTimeout bounds the wall-clock time of a single tool call. Zero
+	// disables the timeout.
+	CallTimeout time.Duration
+	// ToolTimeouts overrides CallTimeout for specific tools (keyed by tool name).
+	ToolTimeouts map[string]time.Duration
+	// MaxConcurrentCalls caps the number of tool calls executing at once.
+	// Zero means unlimited.
+	MaxConcurrentCalls int
+	// RateLimit is the sustained number of tool calls allowed per second.
+	// Zero disables rate limiting.
+	RateLimit float64
+	// RateBurst is the number of calls allowed in a burst above RateLimit.
+	// Defaults to RateLimit rounded down (minimum 1).
+	RateBurst int
+	// AllowedRoots restricts every path-like tool argument (path, cache, file,
+	// root, ...) to these directories after symlink resolution. Empty allows
+	// any path.
+	AllowedRoots []string
+	// Tokens is the token budget of gts_chunk and gts_context calls that do
+	// not pass one. Zero means 800.
+	Tokens int
+	// Config is the project's .gts/config.yaml, if any. An index cached
+	// where its cache setting points is used for its root.
+	Config *config.Config
+	// AuditLog, if set, receives a JSON line per tool call served over
+	// stdio: the tool, its sanitized arguments, duration, result size, and
+	// error. See AuditEntry.
+	AuditLog io.Writer
+	// MaxOutputTokens truncates tool results served over stdio to about this
+	// many tokens unless a call passes max_output_tokens or max_bytes. Zero
+	// means unlimited.
+	MaxOutputTokens int
 }
 
 func NewService(defaultRoot, defaultCache string) *Service {
@@ -32,10 +79,26 @@ func NewServiceWithOptions(defaultRoot, defaultCache string, opts ServiceOptions
 	if root == "" {
 		root = "."
 	}
+	tokens := opts.Tokens
+	if tokens <= 0 {
+		tokens = 800
+	}
+	toolTimeouts := make(map[string]time.Duration, len(opts.ToolTimeouts))
+	for name, timeout := range opts.ToolTimeouts {
+		toolTimeouts[strings.TrimSpace(name)] = timeout
+	}
 	return &Service{
-		defaultRoot:  root,
-		defaultCache: strings.TrimSpace(defaultCache),
-		allowWrites:  opts.AllowWrites,
+		defaultRoot:    root,
+		defaultCache:   strings.TrimSpace(defaultCache),
+		tokens:         tokens,
+		config:         opts.Config,
+		allowWrites:    opts.AllowWrites,
+		callTimeout:    opts.CallTimeout,
+		toolTimeouts:   toolTimeouts,
+		limiter:        newCallLimiter(opts.MaxConcurrentCalls, opts.RateLimit, opts.RateBurst),
+		roots:          sandbox.New(opts.AllowedRoots),
+		audit:          newAuditLog(opts.AuditLog),
+		maxOutputBytes: max(opts.MaxOutputTokens, 0) * bytesPerToken,
 	}
 }
 
@@ -65,7 +128,7 @@ func searchTools() []Tool {
 			Description: "Run structural selector matches across indexed symbols",
 			InputSchema: Schema{
 				Properties: map[string]Property{
-					"selector":          {Type: "string"},
+					"selector":          {OneOf: stringOrArray, Description: "selector, or alternatives whose matches are combined"},
 					"path":              {Type: "string"},
 					"cache":             {Type: "string"},
 					"include_generated": {Type: "boolean", Description: "include generated files (default: false)"},
@@ -83,6 +146,9 @@ func searchTools() []Tool {
 					"cache":             {Type: "string"},
 					"include_generated": {Type: "boolean", Description: "include generated files (default: false)"},
 					"generator":          {Type: "string", Description: "filter to specific generator (e.g. protobuf, mockgen, human)"},
+					"file":              {OneOf: stringOrArray, Description: "only include files matching glob(s); supports **"},
+					"detail":            {Type: "string", Enum: []string{"summary", "symbols", "full"}, Description: "per-file detail: summary (counts and top-level symbols, the default), symbols (imports and every symbol), or full (also references)"},
+					"depth":             {Type: "string", Description: "return nested outlines at depth full, symbols, or types-only instead of flat symbols"},
 				},
 			}.ToMap(),
 		},
@@ -108,6 +174,7 @@ func searchTools() []Tool {
 				Properties: map[string]Property{
 					"name":              {Type: "string"},
 					"regex":             {Type: "boolean"},
+					"qualifier":         {Type: "string", Description: "regex matched against the reference qualifier, e.g. ^os$"},
 					"path":              {Type: "string"},
 					"cache":             {Type: "string"},
 					"include_generated": {Type: "boolean", Description: "include generated files (default: false)"},
@@ -126,6 +193,8 @@ func searchTools() []Tool {
 					"tokens":            {Type: "integer"},
 					"semantic":          {Type: "boolean"},
 					"semantic_depth":    {Type: "integer"},
+					"callers":           {Type: "integer", Description: "include up to N caller snippets of the focus symbol"},
+					"compact":           {Type: "boolean", Description: "strip comments, fold imports, and use shorter signatures to fit more symbols"},
 					"root":              {Type: "string"},
 					"cache":             {Type: "string"},
 					"include_generated": {Type: "boolean", Description: "include generated files (default: false)"},
@@ -136,11 +205,12 @@ func searchTools() []Tool {
 		},
 		{
 			Name:        "gts_scope",
-			Description: "Resolve symbols in scope for a file and line",
+			Description: "Resolve symbols in scope for a file, line, and optional column",
 			InputSchema: Schema{
 				Properties: map[string]Property{
 					"file":              {Type: "string"},
 					"line":              {Type: "integer"},
+					"column":            {Type: "integer", Description: "1-based cursor column (default: end of line)"},
 					"root":              {Type: "string"},
 					"cache":             {Type: "string"},
 					"include_generated": {Type: "boolean", Description: "include generated files (default: false)"},
@@ -173,6 +243,7 @@ func searchTools() []Tool {
 					"path":              {Type: "string"},
 					"cache":             {Type: "string"},
 					"tokens":            {Type: "integer"},
+					"since_manifest":    {Type: "string", Description: "previous chunk manifest; return only added, modified, and moved chunks plus removed ids"},
 					"include_generated": {Type: "boolean", Description: "include generated files (default: false)"},
 					"generator":          {Type: "string", Description: "filter to specific generator (e.g. protobuf, mockgen, human)"},
 				},
@@ -182,6 +253,10 @@ func searchTools() []Tool {
 }
 
 func graphTools() []Tool {
+	stringOrArray := []Property{
+		{Type: "string"},
+		{Type: "array", Items: &Property{Type: "string"}},
+	}
 	return []Tool{
 		{
 			Name:        "gts_services",
@@ -217,12 +292,17 @@ func graphTools() []Tool {
 			Description: "Traverse resolved call graph from matching callable roots",
 			InputSchema: Schema{
 				Properties: map[string]Property{
-					"name":              {Type: "string"},
+					"name":              {OneOf: stringOrArray, Description: "root name or regex; an array walks from several roots"},
 					"regex":             {Type: "boolean"},
 					"path":              {Type: "string"},
 					"cache":             {Type: "string"},
 					"depth":             {Type: "integer"},
 					"reverse":           {Type: "boolean"},
+					"aggregate":         {Type: "string", Enum: []string{"package"}, Description: "collapse the walk to package-level edges"},
+					"dynamic":           {Type: "boolean", Description: "add resolution=interface edges from method calls to other implementations"},
+					"exclude_tests":     {Type: "boolean", Description: "skip definitions in test files"},
+					"only_project":      {Type: "boolean", Description: "skip generated files and dependency directories such as vendor"},
+					"exclude_package":   {OneOf: stringOrArray, Description: "package globs to skip, e.g. internal/testutil/..."},
 					"include_generated": {Type: "boolean", Description: "include generated files (default: false)"},
 					"generator":          {Type: "string", Description: "filter to specific generator (e.g. protobuf, mockgen, human)"},
 				},
@@ -239,7 +319,10 @@ func graphTools() []Tool {
 					"kind":                {Type: "string"},
 					"include_entrypoints": {Type: "boolean"},
 					"include_tests":       {Type: "boolean"},
+					"unexported_only":     {Type: "boolean", Description: "skip exported definitions (default: false)"},
 					"include_generated":   {Type: "boolean", Description: "include generated files (default: false)"},
+					"dynamic_roots":       {Type: "boolean", Description: "leave out Python definitions reached through getattr, __all__, registration calls, or framework decorators (default: true)"},
+					"root_decorators":     {Type: "array", Items: &Property{Type: "string"}, Description: "extra regexes matched against decorators; matching definitions count as used"},
 				},
 			}.ToMap(),
 		},
@@ -390,6 +473,8 @@ func analyzeTools() []Tool {
 					"changed":           {OneOf: stringOrArray},
 					"diff_ref":          {Type: "string", Description: "git ref for diff-based change detection (e.g. HEAD~1)"},
 					"max_depth":         {Type: "integer", Description: "maximum traversal depth (default: 10)"},
+					"before_cache":      {Type: "string", Description: "index snapshot before the change; requires after_cache"},
+					"after_cache":       {Type: "string", Description: "index snapshot after the change; changed symbols come from the structural diff"},
 					"include_generated": {Type: "boolean", Description: "include generated files (default: false)"},
 					"generator":          {Type: "string", Description: "filter to specific generator (e.g. protobuf, mockgen, human)"},
 				},
@@ -507,13 +592,17 @@ func analyzeTools() []Tool {
 }
 
 func transformTools() []Tool {
+	stringOrArray := []Property{
+		{Type: "string"},
+		{Type: "array", Items: &Property{Type: "string"}},
+	}
 	return []Tool{
 		{
 			Name:        "gts_refactor",
 			Description: "Apply structural declaration renames (dry-run by default)",
 			InputSchema: Schema{
 				Properties: map[string]Property{
-					"selector":          {Type: "string"},
+					"selector":          {OneOf: stringOrArray, Description: "selector, or alternatives whose matches are renamed together"},
 					"new_name":          {Type: "string"},
 					"path":              {Type: "string"},
 					"cache":             {Type: "string"},
@@ -521,6 +610,8 @@ func transformTools() []Tool {
 					"callsites":         {Type: "boolean"},
 					"cross_package":     {Type: "boolean"},
 					"write":             {Type: "boolean"},
+					"update_comments":   {Type: "boolean", Description: "also rename whole-word occurrences in comments and docstrings (low confidence)"},
+					"update_strings":    {Type: "boolean", Description: "also rename whole-word occurrences in string literals (low confidence)"},
 					"include_generated": {Type: "boolean", Description: "include generated files (default: false)"},
 					"generator":          {Type: "string", Description: "filter to specific generator (e.g. protobuf, mockgen, human)"},
 				},
@@ -569,6 +660,11 @@ func finalizeToolSchema(tool *Tool) {
 	schema["type"] = "object"
 
 	properties := normalizeSchemaProperties(schema["properties"])
+	for name, property := range outputBudgetProperties {
+		if _, ok := properties[name]; !ok {
+			properties[name] = propertyToMap(property)
+		}
+	}
 	schema["properties"] = properties
 
 	required := normalizeRequiredKeys(schema["required"], properties)
@@ -635,13 +731,80 @@ func normalizeRequiredKeys(raw any, properties map[string]any) []string {
 }
 
 func (s *Service) Call(name string, args map[string]any) (any, error) {
-	switch strings.TrimSpace(name) {
+	return s.CallContext(context.Background(), name, args)
+}
+
+// CallContext runs the named tool subject to the service's concurrency, rate,
+// and timeout limits. When a timeout applies, the call is abandoned once the
+// deadline passes; tools that honor ctx (e.g. gts_query) stop work promptly,
+// while the others run on in the background, holding their concurrency slot
+// and CPU until they finish. A call that writes files is never abandoned: it
+// checks ctx before committing, and the caller waits to learn whether the
+// write happened.
+func (s *Service) CallContext(ctx context.Context, name string, args map[string]any) (any, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	name = strings.TrimSpace(name)
+	if err := s.checkSandbox(args); err != nil {
+		return nil, err
+	}
+	if err := s.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	timeout := s.toolTimeout(name)
+	if timeout <= 0 {
+		defer s.limiter.release()
+		return s.dispatch(ctx, name, args)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		result any
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		// Hold the concurrency slot until the tool actually returns so an
+		// abandoned call still counts against MaxConcurrentCalls.
+		defer s.limiter.release()
+		result, err := s.dispatch(ctx, name, args)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.result, out.err
+	case <-ctx.Done():
+		if writesFiles(name, args) {
+			if out := <-done; out.err == nil {
+				return out.result, nil
+			}
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("tool %q timed out after %s", name, timeout)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// writesFiles reports whether a call of the named tool with args may change
+// files.
+func writesFiles(name string, args map[string]any) bool {
+	return name == "gts_refactor" && boolArg(args, "write", false)
+}
+
+func (s *Service) dispatch(ctx context.Context, name string, args map[string]any) (any, error) {
+	switch name {
 	case "gts_grep":
 		return s.callGrep(args)
 	case "gts_map":
 		return s.callMap(args)
 	case "gts_query":
-		return s.callQuery(args)
+		return s.callQuery(ctx, args)
 	case "gts_refs":
 		return s.callRefs(args)
 	case "gts_context":
@@ -659,7 +822,7 @@ func (s *Service) Call(name string, args map[string]any) (any, error) {
 	case "gts_lint":
 		return s.callLint(args)
 	case "gts_refactor":
-		return s.callRefactor(args)
+		return s.callRefactor(ctx, args)
 	case "gts_diff":
 		return s.callDiff(args)
 	case "gts_stats":
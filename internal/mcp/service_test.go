@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -187,6 +188,96 @@ func B() {
 	}
 }
 
+func TestServiceMapDetailLevels(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package sample
+
+import "fmt"
+
+type Server struct{}
+
+func (s *Server) Run() { fmt.Println("run") }
+
+func Start() { (&Server{}).Run() }
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	service := NewService(tmpDir, "")
+
+	type mapFile struct {
+		TopLevel []struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+			Line int    `json:"line"`
+		} `json:"top_level"`
+		Imports        []string          `json:"imports"`
+		Symbols        []json.RawMessage `json:"symbols"`
+		References     []json.RawMessage `json:"references"`
+		ImportCount    int               `json:"import_count"`
+		SymbolCount    int               `json:"symbol_count"`
+		ReferenceCount int               `json:"reference_count"`
+	}
+	callMap := func(detail string) (string, mapFile) {
+		t.Helper()
+		args := map[string]any{}
+		if detail != "" {
+			args["detail"] = detail
+		}
+		raw, err := service.Call("gts_map", args)
+		if err != nil {
+			t.Fatalf("gts_map detail=%q failed: %v", detail, err)
+		}
+		data, err := json.Marshal(raw)
+		if err != nil {
+			t.Fatalf("marshal gts_map result: %v", err)
+		}
+		var result struct {
+			Detail string    `json:"detail"`
+			Files  []mapFile `json:"files"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			t.Fatalf("decode gts_map result: %v", err)
+		}
+		if len(result.Files) != 1 {
+			t.Fatalf("expected one file, got %d", len(result.Files))
+		}
+		return result.Detail, result.Files[0]
+	}
+
+	detail, summary := callMap("")
+	if detail != "summary" {
+		t.Fatalf("expected default detail summary, got %q", detail)
+	}
+	if len(summary.Symbols) != 0 || len(summary.References) != 0 || len(summary.Imports) != 0 {
+		t.Fatalf("expected summary to omit symbols, references, and imports, got %+v", summary)
+	}
+	var names []string
+	for _, symbol := range summary.TopLevel {
+		names = append(names, symbol.Name)
+	}
+	if strings.Join(names, ",") != "Server,Start" {
+		t.Fatalf("expected top-level symbols Server,Start, got %v", names)
+	}
+	if summary.ImportCount != 1 || summary.SymbolCount != 3 || summary.ReferenceCount == 0 {
+		t.Fatalf("unexpected summary counts %+v", summary)
+	}
+
+	_, symbols := callMap("symbols")
+	if len(symbols.Symbols) != 3 || len(symbols.Imports) != 1 || len(symbols.References) != 0 || len(symbols.TopLevel) != 0 {
+		t.Fatalf("unexpected symbols detail %+v", symbols)
+	}
+
+	_, full := callMap("full")
+	if len(full.Symbols) != 3 || len(full.References) != full.ReferenceCount {
+		t.Fatalf("unexpected full detail %+v", full)
+	}
+
+	if _, err := service.Call("gts_map", map[string]any{"detail": "everything"}); err == nil {
+		t.Fatalf("expected an unsupported detail to fail")
+	}
+}
+
 func TestServiceCallContextAndDeps(t *testing.T) {
 	tmpDir := t.TempDir()
 	sourcePath := filepath.Join(tmpDir, "main.go")
@@ -521,3 +612,43 @@ func Value() {}
 		t.Fatalf("expected non-empty bridge report, got %+v", bridgeReport)
 	}
 }
+
+func TestServiceRejectsPathsOutsideAllowedRoots(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	source := "package sample\n\nfunc A() {}\n"
+	for _, dir := range []string{root, outside} {
+		if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	service := NewServiceWithOptions(root, "", ServiceOptions{AllowedRoots: []string{root}})
+	if _, err := service.Call("gts_map", map[string]any{}); err != nil {
+		t.Fatalf("gts_map inside root failed: %v", err)
+	}
+
+	cases := []map[string]any{
+		{"pattern": "(identifier) @id", "path": outside},
+		{"pattern": "(identifier) @id", "path": filepath.Join(root, "escape")},
+		{"pattern": "(identifier) @id", "cache": filepath.Join(outside, "index.json")},
+	}
+	for _, args := range cases {
+		if _, err := service.Call("gts_query", args); err == nil || !strings.Contains(err.Error(), "outside the allowed roots") {
+			t.Fatalf("expected sandbox rejection for %#v, got %v", args, err)
+		}
+	}
+	if _, err := service.Call("gts_context", map[string]any{"file": "../" + filepath.Base(outside) + "/main.go"}); err == nil || !strings.Contains(err.Error(), "outside the allowed roots") {
+		t.Fatalf("expected sandbox rejection for escaping file, got %v", err)
+	}
+	manifest := filepath.Join(outside, "manifest.json")
+	if err := os.WriteFile(manifest, []byte("[]"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := service.Call("gts_chunk", map[string]any{"since_manifest": manifest}); err == nil || !strings.Contains(err.Error(), "outside the allowed roots") {
+		t.Fatalf("expected sandbox rejection for an outside manifest, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+// Package membudget keeps large-repository analyses within a memory budget:
+// it caps the Go heap, sizes parse concurrency to the budget, and spools
+// results to disk once they outgrow their share of it.
+package membudget
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+// WorkerBytes is the memory set aside for each concurrent parse: the
+// source, its syntax tree, and the parser's scratch space. Typical files
+// need far less, but files near the size limit come close.
+const WorkerBytes int64 = 64 << 20
+
+// Apply sets the Go runtime's soft memory limit to limit bytes, so the
+// collector works harder as the heap approaches it, and returns a function
+// restoring the previous limit. A limit of zero or less leaves the runtime
+// untouched.
+func Apply(limit int64) (restore func()) {
+	if limit <= 0 {
+		return func() {}
+	}
+	previous := debug.SetMemoryLimit(limit)
+	return func() { debug.SetMemoryLimit(previous) }
+}
+
+// Workers returns how many files may be parsed at once within limit bytes,
+// reserving half the budget for the results. It is at least one and at most
+// GOMAXPROCS; a limit of zero or less means no budget, i.e. GOMAXPROCS.
+func Workers(limit int64) int {
+	procs := runtime.GOMAXPROCS(0)
+	if limit <= 0 {
+		return procs
+	}
+	n := int(limit / 2 / WorkerBytes)
+	if n < 1 {
+		return 1
+	}
+	if n > procs {
+		return procs
+	}
+	return n
+}
+
+// HeapInUse returns the bytes currently held by live and unswept heap
+// objects.
+func HeapInUse() int64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapInuse)
+}
+
+// Release returns freed memory to the operating system after a batch of
+// work whose allocations are no longer referenced.
+func Release() {
+	debug.FreeOSMemory()
+}
+
+// Spool is an append-only sequence of values that stays in memory up to a
+// byte threshold and moves to a temporary file of JSON lines beyond it.
+// Values read back from disk are JSON round-tripped, so T must encode all
+// the fields that matter. A Spool is not safe for concurrent use.
+type Spool[T any] struct {
+	threshold int64
+	size      int64
+	count     int
+	items     []T
+	file      *os.File
+	writer    *bufio.Writer
+	encoder   *json.Encoder
+}
+
+// NewSpool returns an empty Spool keeping up to threshold bytes in memory. A
+// threshold of zero or less keeps everything in memory.
+func NewSpool[T any](threshold int64) *Spool[T] {
+	return &Spool[T]{threshold: threshold}
+}
+
+// Add appends item, whose in-memory size the caller estimates as size bytes.
+// The first Add that crosses the threshold moves every value to disk.
+func (s *Spool[T]) Add(item T, size int64) error {
+	s.count++
+	if s.file == nil {
+		s.items = append(s.items, item)
+		s.size += size
+		if s.threshold <= 0 || s.size <= s.threshold {
+			return nil
+		}
+		return s.spill()
+	}
+	return s.encoder.Encode(item)
+}
+
+// Len returns the number of values added.
+func (s *Spool[T]) Len() int {
+	return s.count
+}
+
+// Spilled reports whether the values have moved to disk.
+func (s *Spool[T]) Spilled() bool {
+	return s.file != nil
+}
+
+// Each calls fn with every value in the order added, stopping at the first
+// error.
+func (s *Spool[T]) Each(fn func(T) error) error {
+	if s.file == nil {
+		for _, item := range s.items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	defer s.file.Seek(0, io.SeekEnd)
+
+	decoder := json.NewDecoder(bufio.NewReader(s.file))
+	for {
+		var item T
+		err := decoder.Decode(&item)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+}
+
+// Items returns every value as a slice, reading spilled values back into
+// memory.
+func (s *Spool[T]) Items() ([]T, error) {
+	if s.file == nil {
+		return s.items, nil
+	}
+	items := make([]T, 0, s.count)
+	err := s.Each(func(item T) error {
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
+
+// Close removes the spool file, if any. The Spool must not be used
+// afterwards.
+func (s *Spool[T]) Close() error {
+	s.items = nil
+	if s.file == nil {
+		return nil
+	}
+	name := s.file.Name()
+	err := s.file.Close()
+	if removeErr := os.Remove(name); err == nil {
+		err = removeErr
+	}
+	s.file = nil
+	return err
+}
+
+func (s *Spool[T]) spill() error {
+	file, err := os.CreateTemp("", "gts-spool-*.jsonl")
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, item := range s.items {
+		if err := encoder.Encode(item); err != nil {
+			file.Close()
+			os.Remove(file.Name())
+			return err
+		}
+	}
+	s.file, s.writer, s.encoder = file, writer, encoder
+	s.items = nil
+	return nil
+}
@@ -0,0 +1,60 @@
+package membudget
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestWorkers(t *testing.T) {
+	procs := runtime.GOMAXPROCS(0)
+	if got := Workers(0); got != procs {
+		t.Fatalf("expected GOMAXPROCS=%d without a budget, got %d", procs, got)
+	}
+	if got := Workers(1 << 20); got != 1 {
+		t.Fatalf("expected one worker for a tiny budget, got %d", got)
+	}
+	if got := Workers(1 << 50); got != procs {
+		t.Fatalf("expected at most GOMAXPROCS=%d workers, got %d", procs, got)
+	}
+}
+
+func TestSpoolSpillsPastThreshold(t *testing.T) {
+	type item struct {
+		N int `json:"n"`
+	}
+	spool := NewSpool[item](20)
+	defer spool.Close()
+
+	for i := 0; i < 5; i++ {
+		if err := spool.Add(item{N: i}, 8); err != nil {
+			t.Fatalf("Add returned error: %v", err)
+		}
+		if i == 1 && spool.Spilled() {
+			t.Fatal("expected the spool to stay in memory below the threshold")
+		}
+	}
+	if !spool.Spilled() {
+		t.Fatal("expected the spool to spill past the threshold")
+	}
+	if spool.Len() != 5 {
+		t.Fatalf("expected 5 items, got %d", spool.Len())
+	}
+
+	// Reading twice must see the same values, and adding afterwards appends.
+	for round := 0; round < 2; round++ {
+		items, err := spool.Items()
+		if err != nil {
+			t.Fatalf("Items returned error: %v", err)
+		}
+		if len(items) != 5 || items[0].N != 0 || items[4].N != 4 {
+			t.Fatalf("unexpected items %+v", items)
+		}
+	}
+	if err := spool.Add(item{N: 5}, 8); err != nil {
+		t.Fatalf("Add after Each returned error: %v", err)
+	}
+	items, err := spool.Items()
+	if err != nil || len(items) != 6 || items[5].N != 5 {
+		t.Fatalf("expected an appended sixth item, got %+v (%v)", items, err)
+	}
+}
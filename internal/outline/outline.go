@@ -0,0 +1,119 @@
+// Package outline renders a file's symbols as a nested outline at a chosen
+// depth, for gtsmap and the gts_map MCP tool.
+package outline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Depth selects how much of each file an outline keeps.
+type Depth string
+
+const (
+	// DepthFull keeps imports and every symbol.
+	DepthFull Depth = "full"
+	// DepthSymbols keeps every symbol but drops imports.
+	DepthSymbols Depth = "symbols"
+	// DepthTypes keeps only type-level declarations, without their members.
+	DepthTypes Depth = "types-only"
+)
+
+// ParseDepth validates a --depth value. An empty value means DepthFull.
+func ParseDepth(value string) (Depth, error) {
+	switch Depth(strings.ToLower(strings.TrimSpace(value))) {
+	case "", DepthFull:
+		return DepthFull, nil
+	case DepthSymbols:
+		return DepthSymbols, nil
+	case DepthTypes, "types":
+		return DepthTypes, nil
+	default:
+		return "", fmt.Errorf("unsupported depth %q (expected full, symbols, or types-only)", value)
+	}
+}
+
+// Node is one symbol with the symbols nested inside it.
+type Node struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Signature string `json:"signature,omitempty"`
+	Receiver  string `json:"receiver,omitempty"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Children  []Node `json:"children,omitempty"`
+}
+
+// File is the outline of one indexed file.
+type File struct {
+	Path     string   `json:"path"`
+	Language string   `json:"language"`
+	Imports  []string `json:"imports,omitempty"`
+	Symbols  []Node   `json:"symbols,omitempty"`
+}
+
+// Build returns the outline of file at the given depth.
+func Build(file model.FileSummary, depth Depth) File {
+	out := File{Path: file.Path, Language: file.Language}
+	if depth == DepthFull || depth == "" {
+		out.Imports = append([]string(nil), file.Imports...)
+	}
+	out.Symbols = Nest(file.Symbols)
+	if depth == DepthTypes {
+		out.Symbols = typesOnly(out.Symbols)
+	}
+	return out
+}
+
+// Nest builds the symbol tree for one file from symbol line ranges and
+// receivers, so symbols indexed before container paths were recorded nest the
+// same way.
+// Closures are left out; they are call graph nodes, not declarations.
+func Nest(symbols []model.Symbol) []Node {
+	declared := make([]model.Symbol, 0, len(symbols))
+	for _, symbol := range symbols {
+		if symbol.Kind != "closure_definition" {
+			declared = append(declared, symbol)
+		}
+	}
+	return toNodes(model.NestSymbols(declared))
+}
+
+func toNodes(tree []model.SymbolNode) []Node {
+	if len(tree) == 0 {
+		return nil
+	}
+	nodes := make([]Node, 0, len(tree))
+	for _, item := range tree {
+		nodes = append(nodes, Node{
+			Kind:      item.Kind,
+			Name:      item.Name,
+			Signature: item.Signature,
+			Receiver:  item.Receiver,
+			StartLine: item.StartLine,
+			EndLine:   item.EndLine,
+			Children:  toNodes(item.Children),
+		})
+	}
+	return nodes
+}
+
+func typesOnly(nodes []Node) []Node {
+	kept := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !model.IsContainerKind(node.Kind) {
+			continue
+		}
+		node.Children = typesOnly(node.Children)
+		if len(node.Children) == 0 {
+			node.Children = nil
+		}
+		kept = append(kept, node)
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
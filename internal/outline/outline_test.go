@@ -0,0 +1,73 @@
+package outline
+
+import (
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func TestNest_AttachesGoMethodsAndNestedDeclarations(t *testing.T) {
+	symbols := []model.Symbol{
+		{Kind: "function_definition", Name: "helper", StartLine: 20, EndLine: 22},
+		{Kind: "method_definition", Name: "Run", Receiver: "s *Server", StartLine: 10, EndLine: 18},
+		{Kind: "type_definition", Name: "Server", StartLine: 3, EndLine: 6},
+		{Kind: "function_definition", Name: "inner", StartLine: 12, EndLine: 14},
+	}
+
+	nodes := Nest(symbols)
+	if len(nodes) != 2 || nodes[0].Name != "Server" || nodes[1].Name != "helper" {
+		t.Fatalf("unexpected roots %+v", nodes)
+	}
+	server := nodes[0]
+	if len(server.Children) != 1 || server.Children[0].Name != "Run" {
+		t.Fatalf("expected Run under Server, got %+v", server.Children)
+	}
+	run := server.Children[0]
+	if len(run.Children) != 1 || run.Children[0].Name != "inner" {
+		t.Fatalf("expected inner under Run, got %+v", run.Children)
+	}
+}
+
+func TestBuild_Depths(t *testing.T) {
+	file := model.FileSummary{
+		Path:     "a.py",
+		Language: "python",
+		Imports:  []string{"os"},
+		Symbols: []model.Symbol{
+			{Kind: "class_definition", Name: "A", StartLine: 1, EndLine: 10},
+			{Kind: "function_definition", Name: "m", StartLine: 2, EndLine: 4},
+			{Kind: "class_definition", Name: "Inner", StartLine: 5, EndLine: 9},
+			{Kind: "function_definition", Name: "top", StartLine: 12, EndLine: 13},
+		},
+	}
+
+	full := Build(file, DepthFull)
+	if len(full.Imports) != 1 || len(full.Symbols) != 2 || len(full.Symbols[0].Children) != 2 {
+		t.Fatalf("unexpected full outline %+v", full)
+	}
+
+	symbols := Build(file, DepthSymbols)
+	if len(symbols.Imports) != 0 || len(symbols.Symbols) != 2 {
+		t.Fatalf("unexpected symbols outline %+v", symbols)
+	}
+
+	types := Build(file, DepthTypes)
+	if len(types.Symbols) != 1 || types.Symbols[0].Name != "A" {
+		t.Fatalf("unexpected types-only roots %+v", types.Symbols)
+	}
+	if children := types.Symbols[0].Children; len(children) != 1 || children[0].Name != "Inner" {
+		t.Fatalf("expected only nested type under A, got %+v", children)
+	}
+}
+
+func TestParseDepth(t *testing.T) {
+	for input, want := range map[string]Depth{"": DepthFull, "full": DepthFull, "symbols": DepthSymbols, "types-only": DepthTypes, "Types": DepthTypes} {
+		got, err := ParseDepth(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseDepth(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseDepth("bogus"); err == nil {
+		t.Fatal("expected error for unknown depth")
+	}
+}
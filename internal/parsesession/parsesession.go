@@ -0,0 +1,154 @@
+// Package parsesession shares tree-sitter languages, parsers, and compiled
+// queries across the files of one command run, so loops over an index pay
+// for each once per language instead of once per file.
+package parsesession
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+	"github.com/odvcencio/gotreesitter/grammars"
+)
+
+// ErrNoTree is returned by Parse when the parser produced no syntax tree.
+var ErrNoTree = errors.New("parser produced no syntax tree")
+
+// Session caches, per language name, the grammar, one parser, and every
+// query compiled for it. Trees come from the parser's arena pool; releasing
+// each tree once done with it lets the next parse reuse the arena. A Session
+// is not safe for concurrent use.
+type Session struct {
+	entries map[string]grammars.LangEntry
+	langs   map[string]*gotreesitter.Language
+	parsers map[string]*gotreesitter.Parser
+	queries map[queryKey]compiledQuery
+	cancel  *uint32
+}
+
+type queryKey struct {
+	language string
+	text     string
+}
+
+type compiledQuery struct {
+	query *gotreesitter.Query
+	err   error
+}
+
+// New returns an empty Session.
+func New() *Session {
+	return &Session{
+		langs:   map[string]*gotreesitter.Language{},
+		parsers: map[string]*gotreesitter.Parser{},
+		queries: map[queryKey]compiledQuery{},
+	}
+}
+
+// Supported reports whether a grammar is registered under name, without
+// loading it.
+func (s *Session) Supported(name string) bool {
+	_, ok := s.entry(name)
+	return ok
+}
+
+// Language returns the grammar registered under name, such as "go" or
+// "python", loading it on first use. It reports false for unknown names and
+// grammars that fail to load.
+func (s *Session) Language(name string) (*gotreesitter.Language, bool) {
+	if lang, ok := s.langs[name]; ok {
+		return lang, lang != nil
+	}
+	entry, ok := s.entry(name)
+	if !ok {
+		return nil, false
+	}
+	lang := entry.Language()
+	s.langs[name] = lang
+	return lang, lang != nil
+}
+
+// Parse parses source as language with the session's parser for it, using
+// the grammar's token source when it has one. The caller must Release the
+// returned tree.
+func (s *Session) Parse(language string, source []byte) (*gotreesitter.Tree, error) {
+	lang, ok := s.Language(language)
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q", language)
+	}
+	parser, ok := s.parsers[language]
+	if !ok {
+		parser = gotreesitter.NewParser(lang)
+		if s.cancel != nil {
+			parser.SetCancellationFlag(s.cancel)
+		}
+		s.parsers[language] = parser
+	}
+
+	entry, _ := s.entry(language)
+	var tree *gotreesitter.Tree
+	var err error
+	if entry.TokenSourceFactory != nil {
+		if tokenSource := entry.TokenSourceFactory(source, lang); tokenSource != nil {
+			tree, err = parser.ParseWithTokenSource(source, tokenSource)
+		}
+	}
+	if tree == nil && err == nil {
+		tree, err = parser.Parse(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tree == nil || tree.RootNode() == nil {
+		tree.Release()
+		return nil, ErrNoTree
+	}
+	return tree, nil
+}
+
+// Query returns text compiled for language, compiling it on first use. A
+// compile error is remembered and returned again on later calls.
+func (s *Session) Query(language, text string) (*gotreesitter.Query, error) {
+	key := queryKey{language: language, text: text}
+	if compiled, ok := s.queries[key]; ok {
+		return compiled.query, compiled.err
+	}
+	lang, ok := s.Language(language)
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q", language)
+	}
+	query, err := gotreesitter.NewQuery(text, lang)
+	s.queries[key] = compiledQuery{query: query, err: err}
+	return query, err
+}
+
+// SetCancellationFlag makes every parse of the session poll flag and stop
+// early once it is set to a non-zero value.
+func (s *Session) SetCancellationFlag(flag *uint32) {
+	s.cancel = flag
+	for _, parser := range s.parsers {
+		parser.SetCancellationFlag(flag)
+	}
+}
+
+// ReleaseParsers drops the cached parsers, and with them their arena pools,
+// so a long run can hand memory back between batches of files. Grammars and
+// compiled queries are kept.
+func (s *Session) ReleaseParsers() {
+	s.parsers = map[string]*gotreesitter.Parser{}
+}
+
+func (s *Session) entry(name string) (grammars.LangEntry, bool) {
+	if s.entries == nil {
+		s.entries = map[string]grammars.LangEntry{}
+		for _, entry := range grammars.AllLanguages() {
+			if strings.TrimSpace(entry.Name) == "" || entry.Language == nil {
+				continue
+			}
+			s.entries[entry.Name] = entry
+		}
+	}
+	entry, ok := s.entries[name]
+	return entry, ok
+}
@@ -0,0 +1,49 @@
+package parsesession
+
+import "testing"
+
+func TestSessionReusesParserAndQueries(t *testing.T) {
+	session := New()
+	if !session.Supported("go") {
+		t.Fatal("expected the go grammar to be registered")
+	}
+	if session.Supported("no-such-language") {
+		t.Fatal("expected an unknown language to be unsupported")
+	}
+
+	query, err := session.Query("go", "(function_declaration name: (identifier) @name)")
+	if err != nil {
+		t.Fatalf("Query returned error: %v", err)
+	}
+	again, err := session.Query("go", "(function_declaration name: (identifier) @name)")
+	if err != nil || again != query {
+		t.Fatalf("expected the compiled query to be cached, got %p and %p (%v)", query, again, err)
+	}
+
+	for i, source := range []string{"package a\n\nfunc A() {}\n", "package b\n\nfunc B() {}\nfunc C() {}\n"} {
+		tree, err := session.Parse("go", []byte(source))
+		if err != nil {
+			t.Fatalf("Parse returned error: %v", err)
+		}
+		if got := len(query.Execute(tree)); got != i+1 {
+			t.Fatalf("file %d: expected %d matches, got %d", i, i+1, got)
+		}
+		tree.Release()
+	}
+	if len(session.parsers) != 1 {
+		t.Fatalf("expected one parser shared across files, got %d", len(session.parsers))
+	}
+}
+
+func TestSessionRemembersErrors(t *testing.T) {
+	session := New()
+	if _, err := session.Query("go", "(not_a_node"); err == nil {
+		t.Fatal("expected a compile error")
+	}
+	if _, err := session.Query("go", "(not_a_node"); err == nil {
+		t.Fatal("expected the compile error to be returned again")
+	}
+	if _, err := session.Parse("no-such-language", []byte("x")); err == nil {
+		t.Fatal("expected an error for an unknown language")
+	}
+}
@@ -0,0 +1,160 @@
+// Package queries loads named grep patterns from a .gts/queries.yaml file so
+// common structural searches can be shared and run as "gts grep @name".
+//
+// The file is a small YAML subset: each top-level key names a query whose
+// value is either the pattern itself or a block of fields:
+//
+//	# Exported HTTP handlers.
+//	handlers:
+//	  pattern: method_definition[name=/^Serve/,exported=true]
+//	  description: HTTP handler methods
+//	errors: 'func $NAME($$$) error'
+//	tests:
+//	  pattern: func $NAME($$$)
+//	  mode: structural
+//	  lang: go
+//	  where: matches($NAME, "^Test")
+//
+// The file is written in the YAML subset of package yamlsubset, so values may
+// be plain, 'single-quoted', or "double-quoted".
+package queries
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/yamlsubset"
+)
+
+// FileName is the queries file path relative to a project root.
+const FileName = ".gts/queries.yaml"
+
+// Query is a named grep pattern.
+type Query struct {
+	Name        string `json:"name"`
+	Pattern     string `json:"pattern"`
+	Description string `json:"description,omitempty"`
+	Mode        string `json:"mode,omitempty"` // "selector", "structural", or empty for auto-detection
+	Lang        string `json:"lang,omitempty"`
+	Where       string `json:"where,omitempty"`
+}
+
+// File is a parsed queries file.
+type File struct {
+	Path    string  `json:"path"`
+	Queries []Query `json:"queries"`
+}
+
+// Lookup returns the query called name.
+func (f *File) Lookup(name string) (Query, bool) {
+	if f == nil {
+		return Query{}, false
+	}
+	for _, q := range f.Queries {
+		if q.Name == name {
+			return q, true
+		}
+	}
+	return Query{}, false
+}
+
+// Names returns the query names in file order.
+func (f *File) Names() []string {
+	if f == nil {
+		return nil
+	}
+	names := make([]string, 0, len(f.Queries))
+	for _, q := range f.Queries {
+		names = append(names, q.Name)
+	}
+	return names
+}
+
+// Parse parses the content of a queries file.
+func Parse(content string) ([]Query, error) {
+	doc, err := yamlsubset.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Query, 0, len(doc.Keys))
+	for _, name := range doc.Keys {
+		value := doc.Fields[name]
+		q := Query{Name: name}
+		if !value.IsMap() {
+			if q.Pattern, err = value.Value(name); err != nil {
+				return nil, err
+			}
+			out = append(out, q)
+			continue
+		}
+		for _, key := range value.Keys {
+			field, err := value.Fields[key].Value(key)
+			if err != nil {
+				return nil, err
+			}
+			switch key {
+			case "pattern":
+				q.Pattern = field
+			case "description":
+				q.Description = field
+			case "mode":
+				if field != "selector" && field != "structural" && field != "auto" {
+					return nil, fmt.Errorf("line %d: mode must be selector, structural, or auto, got %q", value.Fields[key].Line, field)
+				}
+				if field != "auto" {
+					q.Mode = field
+				}
+			case "lang":
+				q.Lang = field
+			case "where":
+				q.Where = field
+			default:
+				return nil, fmt.Errorf("line %d: unknown field %q", value.Fields[key].Line, key)
+			}
+		}
+		out = append(out, q)
+	}
+
+	for _, q := range out {
+		if strings.TrimSpace(q.Pattern) == "" {
+			return nil, fmt.Errorf("query %q has no pattern", q.Name)
+		}
+	}
+	return out, nil
+}
+
+// Load searches for .gts/queries.yaml starting in dir and walking up parent
+// directories. It returns a nil File with no error when none is found.
+func Load(dir string) (*File, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving directory: %w", err)
+	}
+	if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+		abs = filepath.Dir(abs)
+	}
+
+	for {
+		candidate := filepath.Join(abs, filepath.FromSlash(FileName))
+		data, err := os.ReadFile(candidate)
+		if err == nil {
+			parsed, parseErr := Parse(string(data))
+			if parseErr != nil {
+				return nil, fmt.Errorf("parsing %s: %w", candidate, parseErr)
+			}
+			return &File{Path: candidate, Queries: parsed}, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading %s: %w", candidate, err)
+		}
+
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return nil, nil
+		}
+		abs = parent
+	}
+}
@@ -0,0 +1,80 @@
+package queries
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `# shared searches
+handlers:
+  pattern: method_definition[name=/^Serve/] # HTTP
+  description: "HTTP handler \"methods\""
+
+errors: 'func $NAME($$$) error'
+tests:
+  pattern: func $NAME($$$)
+  mode: structural
+  lang: go
+  where: matches($NAME, "^Test")
+`
+	got, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []Query{
+		{Name: "handlers", Pattern: "method_definition[name=/^Serve/]", Description: `HTTP handler "methods"`},
+		{Name: "errors", Pattern: "func $NAME($$$) error"},
+		{Name: "tests", Pattern: "func $NAME($$$)", Mode: "structural", Lang: "go", Where: `matches($NAME, "^Test")`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Parse mismatch\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"no pattern":    "empty:\n  description: nothing\n",
+		"unknown field": "a:\n  pattern: x\n  colour: red\n",
+		"bad mode":      "a:\n  pattern: x\n  mode: fuzzy\n",
+		"duplicate":     "a: x\na: y\n",
+		"orphan field":  "  pattern: x\n",
+		"unterminated":  "a: 'x\n",
+	}
+	for name, content := range cases {
+		if _, err := Parse(content); err == nil {
+			t.Errorf("%s: expected error for %q", name, content)
+		}
+	}
+}
+
+func TestLoadWalksUp(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "pkg", "api")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".gts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gts", "queries.yaml"), []byte("funcs: function_definition\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := Load(nested)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if file == nil || !strings.HasSuffix(file.Path, filepath.Join(".gts", "queries.yaml")) {
+		t.Fatalf("unexpected file %+v", file)
+	}
+	if q, ok := file.Lookup("funcs"); !ok || q.Pattern != "function_definition" {
+		t.Fatalf("Lookup(funcs) = %+v, %v", q, ok)
+	}
+	if _, ok := file.Lookup("missing"); ok {
+		t.Fatal("Lookup(missing) should fail")
+	}
+}
@@ -0,0 +1,8 @@
+; Go has no catch: an empty "if err != nil {}" block plays the same role.
+(if_statement
+  condition: (binary_expression
+    left: (identifier) @err
+    right: (nil))
+  consequence: (block) @body
+  (#eq? @err "err")
+  (#match? @body "^\\{\\s*\\}$")) @match
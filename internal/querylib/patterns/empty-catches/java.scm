@@ -0,0 +1,3 @@
+(catch_clause
+  body: (block) @body
+  (#match? @body "^\\{\\s*\\}$")) @match
@@ -0,0 +1,3 @@
+(catch_clause
+  body: (statement_block) @body
+  (#match? @body "^\\{\\s*\\}$")) @match
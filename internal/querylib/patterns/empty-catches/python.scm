@@ -0,0 +1,2 @@
+(except_clause
+  (block . (pass_statement) .)) @match
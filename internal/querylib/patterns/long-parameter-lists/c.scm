@@ -0,0 +1,3 @@
+(parameter_list
+  (parameter_declaration) (parameter_declaration) (parameter_declaration)
+  (parameter_declaration) (parameter_declaration) (parameter_declaration)) @match
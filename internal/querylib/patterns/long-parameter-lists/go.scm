@@ -0,0 +1,13 @@
+; Go groups names sharing a type ("a, b int"), so parameters are counted by
+; the commas separating them.
+(function_declaration
+  parameters: (parameter_list) @match
+  (#match? @match "^\\((?:[^,]*,){5}"))
+
+(method_declaration
+  parameters: (parameter_list) @match
+  (#match? @match "^\\((?:[^,]*,){5}"))
+
+(func_literal
+  parameters: (parameter_list) @match
+  (#match? @match "^\\((?:[^,]*,){5}"))
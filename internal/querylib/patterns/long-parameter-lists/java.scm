@@ -0,0 +1,3 @@
+(formal_parameters
+  (formal_parameter) (formal_parameter) (formal_parameter)
+  (formal_parameter) (formal_parameter) (formal_parameter)) @match
@@ -0,0 +1 @@
+(formal_parameters (_) (_) (_) (_) (_) (_)) @match
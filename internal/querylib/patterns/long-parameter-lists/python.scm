@@ -0,0 +1 @@
+(parameters (_) (_) (_) (_) (_) (_)) @match
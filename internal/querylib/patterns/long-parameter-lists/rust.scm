@@ -0,0 +1,3 @@
+(parameters
+  (parameter) (parameter) (parameter)
+  (parameter) (parameter) (parameter)) @match
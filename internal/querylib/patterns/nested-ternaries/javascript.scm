@@ -0,0 +1,3 @@
+(ternary_expression
+  [(ternary_expression)
+   (parenthesized_expression (ternary_expression))]) @match
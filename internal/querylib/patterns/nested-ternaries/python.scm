@@ -0,0 +1,3 @@
+(conditional_expression
+  [(conditional_expression)
+   (parenthesized_expression (conditional_expression))]) @match
@@ -0,0 +1,2 @@
+((comment) @match
+  (#match? @match "\\b(TODO|FIXME|XXX|HACK)\\b"))
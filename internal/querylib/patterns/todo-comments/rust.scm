@@ -0,0 +1,2 @@
+([(line_comment) (block_comment)] @match
+  (#match? @match "\\b(TODO|FIXME|XXX|HACK)\\b"))
@@ -0,0 +1,124 @@
+// Package querylib bundles named tree-sitter query patterns for common
+// searches, such as TODO comments and empty catch blocks, so they can be run
+// as "gts search query @name" without writing S-expressions.
+//
+// Each pattern is a directory under patterns/ holding one <language>.scm file
+// per supported language. Every query captures the reported node as @match;
+// other captures only serve predicates. TypeScript and TSX share the
+// JavaScript queries, and C++ shares the C queries.
+package querylib
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// MatchCapture is the capture every bundled query reports.
+const MatchCapture = "match"
+
+//go:embed patterns
+var patternFS embed.FS
+
+var descriptions = map[string]string{
+	"todo-comments":        "TODO, FIXME, XXX, and HACK comments",
+	"empty-catches":        "catch and except blocks that do nothing, and empty if err != nil blocks in Go",
+	"long-parameter-lists": "functions taking six or more parameters",
+	"nested-ternaries":     "conditional expressions nested inside another conditional expression",
+}
+
+// languageAliases maps languages without their own query files to the
+// language whose queries they share.
+var languageAliases = map[string]string{
+	"typescript": "javascript",
+	"tsx":        "javascript",
+	"cpp":        "c",
+}
+
+// Pattern is a named query with one variant per language.
+type Pattern struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Languages   []string `json:"languages"`
+
+	queries map[string]string
+}
+
+// Query returns the pattern's query for language.
+func (p Pattern) Query(language string) (string, bool) {
+	if query, ok := p.queries[language]; ok {
+		return query, true
+	}
+	if alias, ok := languageAliases[language]; ok {
+		query, ok := p.queries[alias]
+		return query, ok
+	}
+	return "", false
+}
+
+// Lookup returns the bundled pattern called name. A leading "@" is ignored.
+func Lookup(name string) (Pattern, bool) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+	for _, pattern := range All() {
+		if pattern.Name == name {
+			return pattern, true
+		}
+	}
+	return Pattern{}, false
+}
+
+// All returns the bundled patterns ordered by name.
+func All() []Pattern {
+	dirs, err := fs.ReadDir(patternFS, "patterns")
+	if err != nil {
+		return nil
+	}
+	patterns := make([]Pattern, 0, len(dirs))
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		pattern := Pattern{
+			Name:        dir.Name(),
+			Description: descriptions[dir.Name()],
+			queries:     map[string]string{},
+		}
+		files, err := fs.ReadDir(patternFS, path.Join("patterns", dir.Name()))
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			language, ok := strings.CutSuffix(file.Name(), ".scm")
+			if !ok {
+				continue
+			}
+			data, err := patternFS.ReadFile(path.Join("patterns", dir.Name(), file.Name()))
+			if err != nil {
+				continue
+			}
+			pattern.queries[language] = string(data)
+			pattern.Languages = append(pattern.Languages, language)
+		}
+		for alias, language := range languageAliases {
+			if _, ok := pattern.queries[language]; ok {
+				pattern.Languages = append(pattern.Languages, alias)
+			}
+		}
+		sort.Strings(pattern.Languages)
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool { return patterns[i].Name < patterns[j].Name })
+	return patterns
+}
+
+// Names returns the names of the bundled patterns.
+func Names() []string {
+	patterns := All()
+	names := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		names = append(names, pattern.Name)
+	}
+	return names
+}
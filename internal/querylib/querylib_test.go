@@ -0,0 +1,209 @@
+package querylib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gotreesitter"
+	"github.com/odvcencio/gotreesitter/grammars"
+)
+
+func TestPatternsCompile(t *testing.T) {
+	patterns := All()
+	if len(patterns) == 0 {
+		t.Fatal("expected bundled patterns")
+	}
+	for _, pattern := range patterns {
+		if pattern.Description == "" {
+			t.Errorf("pattern %s has no description", pattern.Name)
+		}
+		for _, language := range pattern.Languages {
+			query, ok := pattern.Query(language)
+			if !ok {
+				t.Errorf("pattern %s lists %s but has no query for it", pattern.Name, language)
+				continue
+			}
+			if !strings.Contains(query, "@"+MatchCapture) {
+				t.Errorf("pattern %s/%s does not capture @%s", pattern.Name, language, MatchCapture)
+			}
+			entry := languageEntry(t, language)
+			if _, err := gotreesitter.NewQuery(query, entry.Language()); err != nil {
+				t.Errorf("pattern %s/%s does not compile: %v", pattern.Name, language, err)
+			}
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	pattern, ok := Lookup("@todo-comments")
+	if !ok || pattern.Name != "todo-comments" {
+		t.Fatalf("Lookup(@todo-comments) = %+v, %v", pattern, ok)
+	}
+	if _, ok := pattern.Query("tsx"); !ok {
+		t.Fatal("expected tsx to share the javascript query")
+	}
+	if _, ok := pattern.Query("haskell"); ok {
+		t.Fatal("expected no haskell query")
+	}
+	if _, ok := Lookup("@missing"); ok {
+		t.Fatal("expected unknown pattern lookup to fail")
+	}
+}
+
+func TestPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		language string
+		source   string
+		want     []string
+	}{
+		{
+			pattern:  "todo-comments",
+			language: "go",
+			source: `package p
+
+// TODO: remove
+// a todomvc reference
+func f() {} // FIXME later
+`,
+			want: []string{"// TODO: remove", "// FIXME later"},
+		},
+		{
+			pattern:  "todo-comments",
+			language: "rust",
+			source:   "// XXX: unsafe\n/* HACK */\nfn f() {}\n",
+			want:     []string{"// XXX: unsafe", "/* HACK */"},
+		},
+		{
+			pattern:  "empty-catches",
+			language: "go",
+			source: `package p
+
+func f() {
+	if err := g(); err != nil {
+	}
+	if err := g(); err != nil {
+		return
+	}
+}
+`,
+			want: []string{"if err := g(); err != nil {\n\t}"},
+		},
+		{
+			pattern:  "empty-catches",
+			language: "javascript",
+			source:   "try { f() } catch (e) {}\ntry { f() } catch (e) { log(e) }\n",
+			want:     []string{"catch (e) {}"},
+		},
+		{
+			pattern:  "empty-catches",
+			language: "python",
+			source:   "try:\n    f()\nexcept ValueError:\n    pass\nexcept KeyError:\n    log()\n",
+			want:     []string{"except ValueError:\n    pass"},
+		},
+		{
+			pattern:  "empty-catches",
+			language: "java",
+			source:   "class A { void f() { try { g(); } catch (Exception e) { } } }\n",
+			want:     []string{"catch (Exception e) { }"},
+		},
+		{
+			pattern:  "long-parameter-lists",
+			language: "go",
+			source: `package p
+
+func many(a, b, c int, d, e string, f bool) {}
+func few(a, b int, c string) {}
+`,
+			want: []string{"(a, b, c int, d, e string, f bool)"},
+		},
+		{
+			pattern:  "long-parameter-lists",
+			language: "javascript",
+			source:   "function many(a, b, c, d, e, f, g) {}\nfunction few(a, b) {}\n",
+			want:     []string{"(a, b, c, d, e, f, g)"},
+		},
+		{
+			pattern:  "long-parameter-lists",
+			language: "python",
+			source:   "def many(a, b, c, d, e, f):\n    pass\n\ndef few(a):\n    pass\n",
+			want:     []string{"(a, b, c, d, e, f)"},
+		},
+		{
+			pattern:  "nested-ternaries",
+			language: "javascript",
+			source:   "const a = x ? 1 : (y ? 2 : 3);\nconst b = x ? 1 : 2;\n",
+			want:     []string{"x ? 1 : (y ? 2 : 3)"},
+		},
+		{
+			pattern:  "nested-ternaries",
+			language: "python",
+			source:   "a = 1 if x else 2 if y else 3\nb = 1 if x else 2\n",
+			want:     []string{"1 if x else 2 if y else 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.language, func(t *testing.T) {
+			got := matchTexts(t, tt.pattern, tt.language, tt.source)
+			if strings.Join(got, "\n--\n") != strings.Join(tt.want, "\n--\n") {
+				t.Fatalf("matches = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func languageEntry(t *testing.T, language string) grammars.LangEntry {
+	t.Helper()
+	for _, entry := range grammars.AllLanguages() {
+		if entry.Name == language && entry.Language != nil {
+			return entry
+		}
+	}
+	t.Fatalf("no grammar for %s", language)
+	return grammars.LangEntry{}
+}
+
+func matchTexts(t *testing.T, name, language, source string) []string {
+	t.Helper()
+	pattern, ok := Lookup(name)
+	if !ok {
+		t.Fatalf("unknown pattern %s", name)
+	}
+	queryText, ok := pattern.Query(language)
+	if !ok {
+		t.Fatalf("pattern %s has no %s query", name, language)
+	}
+	entry := languageEntry(t, language)
+	lang := entry.Language()
+	query, err := gotreesitter.NewQuery(queryText, lang)
+	if err != nil {
+		t.Fatalf("NewQuery returned error: %v", err)
+	}
+
+	src := []byte(source)
+	parser := gotreesitter.NewParser(lang)
+	var tree *gotreesitter.Tree
+	if entry.TokenSourceFactory != nil {
+		if tokenSource := entry.TokenSourceFactory(src, lang); tokenSource != nil {
+			tree, err = parser.ParseWithTokenSource(src, tokenSource)
+		}
+	}
+	if tree == nil && err == nil {
+		tree, err = parser.Parse(src)
+	}
+	if err != nil || tree == nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	defer tree.Release()
+
+	var texts []string
+	for _, match := range query.Execute(tree) {
+		for _, capture := range match.Captures {
+			if capture.Name == MatchCapture {
+				texts = append(texts, capture.Node.Text(src))
+			}
+		}
+	}
+	return texts
+}
@@ -0,0 +1,102 @@
+// Package resultcache stores the results of expensive analyses on disk, keyed by the digest of the index they were computed from and the parameters they were run with.
+package resultcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Dir is where ForIndex keeps results, relative to the index root.
+const Dir = ".gts/results"
+
+// Cache is a directory of cached results, one JSON file per key.
+type Cache struct {
+	dir string
+}
+
+// New returns a Cache storing results in dir, which is created on first
+// Store.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// ForIndex returns the Cache under idx's root.
+func ForIndex(idx *model.Index) *Cache {
+	return New(filepath.Join(idx.Root, filepath.FromSlash(Dir)))
+}
+
+// Key derives the cache key for running operation with params over idx. The
+// key covers the index root and the path and content hash of every file, so
+// any edit, addition, or removal yields a new key. It reports false when
+// some file has no content hash, since its edits would go unnoticed.
+func Key(idx *model.Index, operation string, params any) (string, bool) {
+	if idx == nil {
+		return "", false
+	}
+	for _, file := range idx.Files {
+		if file.ContentHash == "" {
+			return "", false
+		}
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return "", false
+	}
+
+	h := sha256.New()
+	for _, part := range []string{idx.Root, index.Digest(idx), operation, string(encoded)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// Load decodes the result stored under key into v and reports whether there
+// was one. Unreadable entries count as missing.
+func (c *Cache) Load(key string, v any) bool {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// Store saves v under key, replacing any earlier result atomically so
+// concurrent readers never see a partial entry.
+func (c *Cache) Store(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
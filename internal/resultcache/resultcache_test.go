@@ -0,0 +1,55 @@
+package resultcache
+
+import (
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func TestKey(t *testing.T) {
+	idx := &model.Index{
+		Root:  "/repo",
+		Files: []model.FileSummary{{Path: "a.go", ContentHash: "h1"}},
+	}
+	base, ok := Key(idx, "dead", map[string]bool{"tests": false})
+	if !ok || base == "" {
+		t.Fatal("expected a key for an index with content hashes")
+	}
+	if again, _ := Key(idx, "dead", map[string]bool{"tests": false}); again != base {
+		t.Fatal("expected the same key for the same index and parameters")
+	}
+
+	if other, _ := Key(idx, "dead", map[string]bool{"tests": true}); other == base {
+		t.Fatal("expected different parameters to change the key")
+	}
+	if other, _ := Key(idx, "bridge", map[string]bool{"tests": false}); other == base {
+		t.Fatal("expected a different operation to change the key")
+	}
+	edited := &model.Index{Root: "/repo", Files: []model.FileSummary{{Path: "a.go", ContentHash: "h2"}}}
+	if other, _ := Key(edited, "dead", map[string]bool{"tests": false}); other == base {
+		t.Fatal("expected a content change to change the key")
+	}
+
+	unhashed := &model.Index{Root: "/repo", Files: []model.FileSummary{{Path: "a.go"}}}
+	if _, ok := Key(unhashed, "dead", nil); ok {
+		t.Fatal("expected no key for files without content hashes")
+	}
+}
+
+func TestCacheStoreAndLoad(t *testing.T) {
+	cache := New(t.TempDir())
+	type result struct {
+		Names []string `json:"names"`
+	}
+
+	var got result
+	if cache.Load("k", &got) {
+		t.Fatal("expected a miss on an empty cache")
+	}
+	if err := cache.Store("k", result{Names: []string{"A", "B"}}); err != nil {
+		t.Fatalf("Store returned error: %v", err)
+	}
+	if !cache.Load("k", &got) || len(got.Names) != 2 || got.Names[1] != "B" {
+		t.Fatalf("expected the stored result, got %+v", got)
+	}
+}
@@ -0,0 +1,481 @@
+package routes
+
+import (
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// inlineHandler stands in for a handler defined at its registration.
+const inlineHandler = "func literal"
+
+// routeSyntax pairs a language's route query with the function turning its
+// captures into routes. Every pattern captures the registration as @route
+// and the registering method or annotation name as @verb.
+type routeSyntax struct {
+	query     string
+	interpret func(m *matcher, captures map[string]*gotreesitter.Node) []Route
+}
+
+var syntaxByLanguage = map[string]routeSyntax{
+	"go":         {query: goRouteQuery, interpret: goRoutes},
+	"javascript": {query: jsRouteQuery, interpret: jsRoutes},
+	"typescript": {query: jsRouteQuery, interpret: jsRoutes},
+	"tsx":        {query: jsRouteQuery, interpret: jsRoutes},
+	"python":     {query: pythonRouteQuery, interpret: pythonRoutes},
+	"java":       {query: javaRouteQuery, interpret: javaRoutes},
+}
+
+// goRouteQuery matches router method calls: mux.HandleFunc("GET /x", h),
+// r.GET("/x", h) (gin, echo), r.Get("/x", h) (chi), and r.Method("GET", "/x", h).
+const goRouteQuery = `
+(call_expression
+  function: (selector_expression
+    field: (field_identifier) @verb)
+  arguments: (argument_list) @args
+  (#match? @verb "^(Handle|HandleFunc|Method|MethodFunc|Any|GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|Get|Post|Put|Patch|Delete|Head|Options)$")
+) @route
+`
+
+// jsRouteQuery matches Express-style app.get("/x", handler) calls.
+const jsRouteQuery = `
+(call_expression
+  function: (member_expression
+    property: (property_identifier) @verb)
+  arguments: (arguments) @args
+  (#match? @verb "^(get|post|put|patch|delete|head|options|all)$")
+) @route
+`
+
+// pythonRouteQuery matches FastAPI @app.get("/x") and Flask @app.route("/x")
+// decorators on functions.
+const pythonRouteQuery = `
+(decorated_definition
+  (decorator
+    (call
+      function: (attribute
+        attribute: (identifier) @verb)
+      arguments: (argument_list) @args))
+  definition: (function_definition
+    name: (identifier) @handler)
+  (#match? @verb "^(get|post|put|patch|delete|head|options|route|api_route)$")
+) @route
+`
+
+// javaRouteQuery matches Spring @GetMapping("/x") and @RequestMapping
+// annotations on methods, with or without arguments.
+const javaRouteQuery = `
+(method_declaration
+  (modifiers
+    (annotation
+      name: (identifier) @verb
+      arguments: (annotation_argument_list) @args))
+  name: (identifier) @handler
+  (#match? @verb "^(Get|Post|Put|Patch|Delete|Request)Mapping$")
+) @route
+
+(method_declaration
+  (modifiers
+    (marker_annotation
+      name: (identifier) @verb))
+  name: (identifier) @handler
+  (#match? @verb "^(Get|Post|Put|Patch|Delete|Request)Mapping$")
+) @route
+`
+
+var httpMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+	"HEAD": true, "OPTIONS": true, "CONNECT": true, "TRACE": true,
+}
+
+// matcher carries the file being matched.
+type matcher struct {
+	lang *gotreesitter.Language
+	src  []byte
+	file model.FileSummary
+}
+
+func (m *matcher) text(node *gotreesitter.Node) string {
+	return strings.TrimSpace(node.Text(m.src))
+}
+
+// arguments returns the named children of an argument list, without comments.
+func (m *matcher) arguments(list *gotreesitter.Node) []*gotreesitter.Node {
+	if list == nil {
+		return nil
+	}
+	var args []*gotreesitter.Node
+	for i := 0; i < list.ChildCount(); i++ {
+		child := list.Child(i)
+		if child != nil && child.IsNamed() && child.Type(m.lang) != "comment" {
+			args = append(args, child)
+		}
+	}
+	return args
+}
+
+// stringValue returns the value of a string literal without interpolation.
+func (m *matcher) stringValue(node *gotreesitter.Node) (string, bool) {
+	if node == nil {
+		return "", false
+	}
+	switch node.Type(m.lang) {
+	case "interpreted_string_literal", "raw_string_literal", "string", "string_literal":
+	default:
+		return "", false
+	}
+	text := strings.TrimLeft(m.text(node), "rRbBuU")
+	for _, quote := range []string{`"""`, `'''`, `"`, `'`, "`"} {
+		if len(text) >= 2*len(quote) && strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) {
+			return text[len(quote) : len(text)-len(quote)], true
+		}
+	}
+	return "", false
+}
+
+// imports reports whether the file imports a path containing any of parts.
+func (m *matcher) imports(parts ...string) bool {
+	for _, imp := range m.file.Imports {
+		for _, part := range parts {
+			if strings.Contains(imp, part) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handlerName returns the handler passed as node, unwrapping middleware and
+// adapters such as auth(h.list) or http.HandlerFunc(list).
+func (m *matcher) handlerName(node *gotreesitter.Node) string {
+	for node != nil {
+		switch node.Type(m.lang) {
+		case "func_literal", "function_expression", "arrow_function", "function", "lambda":
+			return inlineHandler
+		case "call_expression", "call":
+			args := m.arguments(node.ChildByFieldName("arguments", m.lang))
+			if len(args) == 0 {
+				return m.text(node.ChildByFieldName("function", m.lang))
+			}
+			node = args[len(args)-1]
+		case "parenthesized_expression":
+			node = node.NamedChild(0)
+		default:
+			return m.text(node)
+		}
+	}
+	return ""
+}
+
+func goRoutes(m *matcher, captures map[string]*gotreesitter.Node) []Route {
+	verb := m.text(captures["verb"])
+	args := m.arguments(captures["args"])
+	if len(args) < 2 {
+		return nil
+	}
+	first, firstOK := m.stringValue(args[0])
+	second, secondOK := m.stringValue(args[1])
+
+	method, path := "", ""
+	switch {
+	case len(args) >= 3 && firstOK && secondOK && httpMethods[strings.ToUpper(first)]:
+		// gin Handle("GET", "/x", h) and chi Method("GET", "/x", h).
+		method, path = strings.ToUpper(first), second
+	case verb == "Handle" || verb == "HandleFunc":
+		if !firstOK {
+			return nil
+		}
+		// Go 1.22 patterns may lead with a method: "GET /users/{id}".
+		method, path = AnyMethod, first
+		if space := strings.IndexByte(first, ' '); space > 0 && httpMethods[first[:space]] {
+			method, path = first[:space], strings.TrimSpace(first[space+1:])
+		}
+	case verb == "Method" || verb == "MethodFunc":
+		return nil
+	case verb == "Any":
+		method, path = AnyMethod, first
+	default:
+		method, path = strings.ToUpper(verb), first
+	}
+	if !firstOK || path == "" || (!strings.HasPrefix(path, "/") && verb != "Handle" && verb != "HandleFunc") {
+		return nil
+	}
+
+	route := Route{
+		Method:    method,
+		Path:      joinPath(m.chiPrefix(captures["route"]), path),
+		Framework: m.goFramework(verb),
+		Handler:   m.handlerName(args[len(args)-1]),
+	}
+	methods := m.gorillaMethods(captures["route"])
+	if len(methods) == 0 {
+		return []Route{route}
+	}
+	routes := make([]Route, 0, len(methods))
+	for _, method := range methods {
+		route.Method = method
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+func (m *matcher) goFramework(verb string) string {
+	switch {
+	case m.imports("gin-gonic/gin"):
+		return "gin"
+	case m.imports("go-chi/chi"):
+		return "chi"
+	case m.imports("labstack/echo"):
+		return "echo"
+	case m.imports("gorilla/mux"):
+		return "gorilla"
+	case verb == "Handle" || verb == "HandleFunc":
+		return "net/http"
+	}
+	return "go"
+}
+
+// chiPrefix joins the paths of the chi r.Route("/prefix", func(r chi.Router)
+// {...}) blocks enclosing a registration.
+func (m *matcher) chiPrefix(call *gotreesitter.Node) string {
+	prefix := ""
+	for node := call.Parent(); node != nil; node = node.Parent() {
+		if node.Type(m.lang) != "func_literal" {
+			continue
+		}
+		list := node.Parent()
+		if list == nil || list.Type(m.lang) != "argument_list" {
+			continue
+		}
+		outer := list.Parent()
+		if outer == nil || outer.Type(m.lang) != "call_expression" {
+			continue
+		}
+		function := outer.ChildByFieldName("function", m.lang)
+		if function == nil || function.Type(m.lang) != "selector_expression" {
+			continue
+		}
+		if m.text(function.ChildByFieldName("field", m.lang)) != "Route" {
+			continue
+		}
+		args := m.arguments(list)
+		if len(args) == 0 {
+			continue
+		}
+		if value, ok := m.stringValue(args[0]); ok {
+			prefix = joinPath(value, prefix)
+		}
+	}
+	return prefix
+}
+
+// gorillaMethods returns the methods of a gorilla/mux registration chained
+// with .Methods("GET", ...).
+func (m *matcher) gorillaMethods(call *gotreesitter.Node) []string {
+	selector := call.Parent()
+	if selector == nil || selector.Type(m.lang) != "selector_expression" {
+		return nil
+	}
+	if m.text(selector.ChildByFieldName("field", m.lang)) != "Methods" {
+		return nil
+	}
+	outer := selector.Parent()
+	if outer == nil || outer.Type(m.lang) != "call_expression" {
+		return nil
+	}
+	var methods []string
+	for _, arg := range m.arguments(outer.ChildByFieldName("arguments", m.lang)) {
+		if value, ok := m.stringValue(arg); ok {
+			methods = append(methods, strings.ToUpper(value))
+		}
+	}
+	return methods
+}
+
+func jsRoutes(m *matcher, captures map[string]*gotreesitter.Node) []Route {
+	args := m.arguments(captures["args"])
+	if len(args) < 2 {
+		return nil
+	}
+	path, ok := m.stringValue(args[0])
+	if !ok || !strings.HasPrefix(path, "/") {
+		return nil
+	}
+	method := strings.ToUpper(m.text(captures["verb"]))
+	if method == "ALL" {
+		method = AnyMethod
+	}
+	framework := "express"
+	switch {
+	case m.imports("fastify"):
+		framework = "fastify"
+	case m.imports("koa"):
+		framework = "koa"
+	}
+	return []Route{{
+		Method:    method,
+		Path:      path,
+		Framework: framework,
+		Handler:   m.handlerName(args[len(args)-1]),
+	}}
+}
+
+func pythonRoutes(m *matcher, captures map[string]*gotreesitter.Node) []Route {
+	verb := m.text(captures["verb"])
+	path := ""
+	var methods []string
+	for _, arg := range m.arguments(captures["args"]) {
+		if arg.Type(m.lang) == "keyword_argument" {
+			name := m.text(arg.ChildByFieldName("name", m.lang))
+			value := arg.ChildByFieldName("value", m.lang)
+			switch name {
+			case "path", "rule":
+				if text, ok := m.stringValue(value); ok {
+					path = text
+				}
+			case "methods":
+				for _, item := range m.arguments(value) {
+					if text, ok := m.stringValue(item); ok {
+						methods = append(methods, strings.ToUpper(text))
+					}
+				}
+			}
+			continue
+		}
+		if text, ok := m.stringValue(arg); ok && path == "" {
+			path = text
+		}
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil
+	}
+
+	framework := "fastapi"
+	if m.imports("flask") || (verb == "route" && !m.imports("fastapi")) {
+		framework = "flask"
+	}
+	switch verb {
+	case "route", "api_route":
+		if len(methods) == 0 {
+			methods = []string{"GET"}
+			if verb == "api_route" {
+				methods = []string{AnyMethod}
+			}
+		}
+	default:
+		methods = []string{strings.ToUpper(verb)}
+	}
+
+	handler := m.text(captures["handler"])
+	routes := make([]Route, 0, len(methods))
+	for _, method := range methods {
+		routes = append(routes, Route{Method: method, Path: path, Framework: framework, Handler: handler})
+	}
+	return routes
+}
+
+func javaRoutes(m *matcher, captures map[string]*gotreesitter.Node) []Route {
+	verb := m.text(captures["verb"])
+	paths, methods := m.springMapping(captures["args"])
+	if verb != "RequestMapping" {
+		methods = []string{strings.ToUpper(strings.TrimSuffix(verb, "Mapping"))}
+	} else if len(methods) == 0 {
+		methods = []string{AnyMethod}
+	}
+	if len(paths) == 0 {
+		paths = []string{""}
+	}
+
+	prefix := ""
+	for node := captures["route"].Parent(); node != nil; node = node.Parent() {
+		if node.Type(m.lang) == "class_declaration" {
+			prefix = m.classMapping(node)
+			break
+		}
+	}
+
+	handler := m.text(captures["handler"])
+	var routes []Route
+	for _, path := range paths {
+		for _, method := range methods {
+			routes = append(routes, Route{
+				Method:    method,
+				Path:      joinPath(prefix, path),
+				Framework: "spring",
+				Handler:   handler,
+			})
+		}
+	}
+	return routes
+}
+
+// springMapping reads the paths and RequestMethods of a mapping annotation's
+// arguments: ("/x"), (value = "/x"), (path = {"/a", "/b"}), and
+// (method = RequestMethod.GET).
+func (m *matcher) springMapping(args *gotreesitter.Node) (paths, methods []string) {
+	var collect func(node *gotreesitter.Node, key string)
+	collect = func(node *gotreesitter.Node, key string) {
+		if node == nil {
+			return
+		}
+		switch node.Type(m.lang) {
+		case "string_literal":
+			if key == "value" || key == "path" {
+				if value, ok := m.stringValue(node); ok {
+					paths = append(paths, value)
+				}
+			}
+		case "field_access", "identifier":
+			if key == "method" {
+				text := m.text(node)
+				methods = append(methods, text[strings.LastIndex(text, ".")+1:])
+			}
+		case "element_value_array_initializer":
+			for _, item := range m.arguments(node) {
+				collect(item, key)
+			}
+		case "element_value_pair":
+			collect(node.ChildByFieldName("value", m.lang), m.text(node.ChildByFieldName("key", m.lang)))
+		}
+	}
+	for _, arg := range m.arguments(args) {
+		collect(arg, "value")
+	}
+	return paths, methods
+}
+
+// classMapping returns the path of a class-level @RequestMapping.
+func (m *matcher) classMapping(class *gotreesitter.Node) string {
+	for i := 0; i < class.ChildCount(); i++ {
+		modifiers := class.Child(i)
+		if modifiers == nil || modifiers.Type(m.lang) != "modifiers" {
+			continue
+		}
+		for j := 0; j < modifiers.ChildCount(); j++ {
+			annotation := modifiers.Child(j)
+			if annotation == nil || annotation.Type(m.lang) != "annotation" {
+				continue
+			}
+			if m.text(annotation.ChildByFieldName("name", m.lang)) != "RequestMapping" {
+				continue
+			}
+			if paths, _ := m.springMapping(annotation.ChildByFieldName("arguments", m.lang)); len(paths) > 0 {
+				return paths[0]
+			}
+		}
+	}
+	return ""
+}
+
+// joinPath joins a route prefix and path with a single slash.
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
@@ -0,0 +1,243 @@
+// Package routes finds HTTP route registrations in indexed sources and maps
+// each method and path to the handler symbol serving it.
+//
+// Registrations are matched with tree-sitter queries for Go (net/http,
+// gorilla/mux, gin, chi, echo), JavaScript and TypeScript (Express-style
+// routers), Python (FastAPI and Flask decorators), and Java (Spring mapping
+// annotations). Paths are taken as written at the registration, joined with
+// chi Route blocks and class-level Spring @RequestMapping prefixes; prefixes
+// applied through router variables, such as gin groups, are not resolved.
+package routes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/internal/parsesession"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// AnyMethod is the method of a route registered for every HTTP method.
+const AnyMethod = "ANY"
+
+// Route is one registered method and path.
+type Route struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Framework string `json:"framework"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	// Handler is the handler as written at the registration, or "func
+	// literal" for an inline handler.
+	Handler string `json:"handler"`
+	// Definition is the handler's definition, when it resolves to one.
+	Definition *xref.Definition `json:"definition,omitempty"`
+}
+
+// Extract returns the routes registered in the indexed files, resolving
+// handlers against graph. Files whose language has no route queries, or that
+// can no longer be read, are skipped.
+func Extract(idx *model.Index, graph *xref.Graph) ([]Route, error) {
+	if idx == nil {
+		return nil, fmt.Errorf("index is nil")
+	}
+
+	session := parsesession.New()
+
+	var routes []Route
+	for _, file := range idx.Files {
+		syntax, ok := syntaxByLanguage[file.Language]
+		if !ok {
+			continue
+		}
+		lang, ok := session.Language(file.Language)
+		if !ok {
+			continue
+		}
+		query, err := session.Query(file.Language, syntax.query)
+		if err != nil {
+			return nil, fmt.Errorf("compile %s route query: %w", file.Language, err)
+		}
+
+		source, err := os.ReadFile(filepath.Join(idx.Root, filepath.FromSlash(file.Path)))
+		if err != nil {
+			continue
+		}
+
+		tree, parseErr := session.Parse(file.Language, source)
+		if parseErr != nil {
+			continue
+		}
+
+		m := matcher{lang: lang, src: source, file: file}
+		for _, match := range query.Execute(tree) {
+			captures := map[string]*gotreesitter.Node{}
+			for _, capture := range match.Captures {
+				if capture.Node != nil {
+					captures[capture.Name] = capture.Node
+				}
+			}
+			if captures["route"] == nil || captures["verb"] == nil {
+				continue
+			}
+			for _, route := range syntax.interpret(&m, captures) {
+				route.File = file.Path
+				route.Line = int(captures["route"].StartPoint().Row) + 1
+				if route.Path == "" {
+					route.Path = "/"
+				}
+				routes = append(routes, route)
+			}
+		}
+		tree.Release()
+	}
+
+	if graph != nil {
+		for i := range routes {
+			routes[i].Definition = resolveHandler(graph, routes[i])
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		if routes[i].Method != routes[j].Method {
+			return routes[i].Method < routes[j].Method
+		}
+		if routes[i].File != routes[j].File {
+			return routes[i].File < routes[j].File
+		}
+		return routes[i].Line < routes[j].Line
+	})
+	return routes, nil
+}
+
+// ParseSpec splits a route spec such as "GET /users/42" into its method and
+// path. A spec without a method matches every method.
+func ParseSpec(spec string) (method, path string, err error) {
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 1:
+		path = fields[0]
+	case 2:
+		method, path = strings.ToUpper(fields[0]), fields[1]
+	default:
+		return "", "", fmt.Errorf("invalid route %q (expected \"[METHOD] /path\")", spec)
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "", "", fmt.Errorf("invalid route %q: path must start with /", spec)
+	}
+	return method, path, nil
+}
+
+// Matches reports whether a request with the given method and path would hit
+// the route. Path parameters in the route ({id}, :id, <id>) match any single
+// segment, and a trailing wildcard (*, {path...}) matches the rest of the
+// path. An empty method matches every route.
+func (r Route) Matches(method, path string) bool {
+	if method != "" && r.Method != AnyMethod && !strings.EqualFold(method, r.Method) {
+		return false
+	}
+	pattern := splitPath(r.Path)
+	segments := splitPath(path)
+	for i, part := range pattern {
+		if isWildcard(part) {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if isParam(part) || isParam(segments[i]) {
+			continue
+		}
+		if part != segments[i] {
+			return false
+		}
+	}
+	return len(pattern) == len(segments)
+}
+
+// Find returns the routes a request with the given method and path would hit.
+func Find(routes []Route, method, path string) []Route {
+	var matches []Route
+	for _, route := range routes {
+		if route.Matches(method, path) {
+			matches = append(matches, route)
+		}
+	}
+	return matches
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, ":") ||
+		(strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")) ||
+		(strings.HasPrefix(segment, "<") && strings.HasSuffix(segment, ">"))
+}
+
+func isWildcard(segment string) bool {
+	return segment == "*" || strings.HasPrefix(segment, "*") || strings.HasSuffix(segment, "...}") || segment == "{$}"
+}
+
+// resolveHandler finds the callable definition a route's handler names,
+// preferring one in the registering file, then its package, then a package
+// named by the handler's qualifier.
+func resolveHandler(graph *xref.Graph, route Route) *xref.Definition {
+	qualifier, name := splitHandler(route.Handler)
+	if name == "" {
+		return nil
+	}
+	candidates, err := graph.FindDefinitions(name, false)
+	if err != nil || len(candidates) == 0 {
+		return nil
+	}
+	pkg := xref.PackageOf(route.File)
+	for _, prefer := range []func(xref.Definition) bool{
+		func(d xref.Definition) bool { return d.File == route.File },
+		func(d xref.Definition) bool { return d.Package == pkg },
+		func(d xref.Definition) bool { return qualifier != "" && filepath.Base(d.Package) == qualifier },
+	} {
+		var found []xref.Definition
+		for _, candidate := range candidates {
+			if prefer(candidate) {
+				found = append(found, candidate)
+			}
+		}
+		if len(found) > 0 {
+			return &found[0]
+		}
+	}
+	if len(candidates) == 1 {
+		return &candidates[0]
+	}
+	return nil
+}
+
+// splitHandler splits "h.listUsers" into its qualifier and name.
+func splitHandler(handler string) (qualifier, name string) {
+	if handler == "" || handler == inlineHandler {
+		return "", ""
+	}
+	if dot := strings.LastIndex(handler, "."); dot >= 0 {
+		qualifier = handler[:dot]
+		if inner := strings.LastIndex(qualifier, "."); inner >= 0 {
+			qualifier = qualifier[inner+1:]
+		}
+		return qualifier, handler[dot+1:]
+	}
+	return "", handler
+}
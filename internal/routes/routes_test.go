@@ -0,0 +1,176 @@
+package routes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+func TestExtract(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeFile(t, root, "server/server.go", `package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"example.com/app/handlers"
+)
+
+func Routes(h *Handler) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", h.getUser)
+	mux.Handle("/static/", http.StripPrefix("/static/", files()))
+
+	r := chi.NewRouter()
+	r.Route("/api", func(r chi.Router) {
+		r.Get("/items", handlers.ListItems)
+		r.Post("/items", func(w http.ResponseWriter, req *http.Request) {})
+	})
+	r.Method("DELETE", "/items/{id}", auth(handlers.DeleteItem))
+	resp, _ := http.Get("https://example.com")
+	_ = resp
+}
+
+type Handler struct{}
+
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {}
+
+func files() http.Handler { return nil }
+
+func auth(next http.HandlerFunc) http.HandlerFunc { return next }
+`)
+	writeFile(t, root, "handlers/items.go", `package handlers
+
+import "net/http"
+
+func ListItems(w http.ResponseWriter, r *http.Request) {}
+
+func DeleteItem(w http.ResponseWriter, r *http.Request) {}
+`)
+	writeFile(t, root, "web/app.js", `const express = require("express");
+const app = express();
+
+function show(req, res) {}
+
+app.get("/users/:id", auth, show);
+app.post('/users', (req, res) => {});
+app.get("env");
+`)
+	writeFile(t, root, "py/api.py", `from fastapi import FastAPI
+
+app = FastAPI()
+
+@app.get("/health")
+async def health():
+    return {}
+
+@app.api_route("/echo", methods=["GET", "POST"])
+def echo():
+    pass
+`)
+	writeFile(t, root, "java/UserController.java", `@RestController
+@RequestMapping("/v1")
+class UserController {
+    @GetMapping("/users/{id}")
+    public User get(long id) { return null; }
+
+    @RequestMapping(value = {"/a", "/b"}, method = RequestMethod.PUT)
+    public void update() {}
+
+    @DeleteMapping
+    public void clear() {}
+}
+`)
+
+	idx, err := index.NewBuilder().BuildPath(root)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	graph, err := xref.Build(idx)
+	if err != nil {
+		t.Fatalf("xref.Build returned error: %v", err)
+	}
+	routes, err := Extract(idx, &graph)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+
+	got := make([]string, 0, len(routes))
+	for _, route := range routes {
+		line := route.Method + " " + route.Path + " " + route.Framework + " " + route.Handler
+		if route.Definition != nil {
+			line += " -> " + route.Definition.File + ":" + route.Definition.Name
+		}
+		got = append(got, line)
+	}
+	want := []string{
+		"GET /api/items chi handlers.ListItems -> handlers/items.go:ListItems",
+		"POST /api/items chi func literal",
+		"GET /echo fastapi echo -> py/api.py:echo",
+		"POST /echo fastapi echo -> py/api.py:echo",
+		"GET /health fastapi health -> py/api.py:health",
+		"DELETE /items/{id} chi handlers.DeleteItem -> handlers/items.go:DeleteItem",
+		"ANY /static/ chi files -> server/server.go:files",
+		"POST /users express func literal",
+		"GET /users/:id express show -> web/app.js:show",
+		"GET /users/{id} chi h.getUser -> server/server.go:getUser",
+		"DELETE /v1 spring clear -> java/UserController.java:clear",
+		"PUT /v1/a spring update -> java/UserController.java:update",
+		"PUT /v1/b spring update -> java/UserController.java:update",
+		"GET /v1/users/{id} spring get -> java/UserController.java:get",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected routes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRouteMatches(t *testing.T) {
+	tests := []struct {
+		route  Route
+		method string
+		path   string
+		want   bool
+	}{
+		{Route{Method: "GET", Path: "/users/{id}"}, "GET", "/users/42", true},
+		{Route{Method: "GET", Path: "/users/:id"}, "GET", "/users/{id}", true},
+		{Route{Method: "GET", Path: "/users/<int:id>"}, "get", "/users/7/", true},
+		{Route{Method: "GET", Path: "/users/{id}"}, "POST", "/users/42", false},
+		{Route{Method: "GET", Path: "/users/{id}"}, "", "/users/42", true},
+		{Route{Method: AnyMethod, Path: "/users"}, "DELETE", "/users", true},
+		{Route{Method: "GET", Path: "/users"}, "GET", "/users/42", false},
+		{Route{Method: "GET", Path: "/files/{path...}"}, "GET", "/files/a/b.txt", true},
+		{Route{Method: "GET", Path: "/"}, "GET", "/", true},
+	}
+	for _, tt := range tests {
+		if got := tt.route.Matches(tt.method, tt.path); got != tt.want {
+			t.Errorf("%s %s matches %s %s = %v, want %v", tt.route.Method, tt.route.Path, tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	method, path, err := ParseSpec("get /users/42")
+	if err != nil || method != "GET" || path != "/users/42" {
+		t.Fatalf("ParseSpec = %q, %q, %v", method, path, err)
+	}
+	if _, _, err := ParseSpec("GET users"); err == nil {
+		t.Fatal("expected error for a path without a leading slash")
+	}
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package scope
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// typedKinds lists the scope symbol kinds whose Detail is filled from go/types.
+var typedKinds = map[string]bool{
+	"param":       true,
+	"receiver":    true,
+	"local_var":   true,
+	"local_const": true,
+}
+
+// addGoTypeDetails type-checks the package containing relPath and fills in
+// the Detail of local symbols that tree-sitter could not type. Type-checking
+// is best effort: parse failures or type errors leave symbols untouched.
+func addGoTypeDetails(collector *symbolCollector, idx *model.Index, relPath string) {
+	if !strings.HasSuffix(relPath, ".go") {
+		return
+	}
+	defTypes, ok := goDefTypes(idx, relPath)
+	if !ok {
+		return
+	}
+	for i, symbol := range collector.items {
+		if symbol.Detail != "" || symbol.DeclLine <= 0 || !typedKinds[symbol.Kind] {
+			continue
+		}
+		if typ, ok := defTypes[defKey{name: symbol.Name, line: symbol.DeclLine}]; ok {
+			collector.items[i].Detail = typ
+		}
+	}
+}
+
+type defKey struct {
+	name string
+	line int
+}
+
+// goDefTypes returns the type of every identifier defined in relPath, keyed by
+// name and declaration line. Types are qualified relative to the file's own
+// package, so local types print unqualified.
+func goDefTypes(idx *model.Index, relPath string) (map[defKey]string, bool) {
+	dir := filepath.ToSlash(filepath.Dir(filepath.Clean(relPath)))
+	fset := token.NewFileSet()
+
+	var target *ast.File
+	byPackage := map[string][]*ast.File{}
+	for _, fileSummary := range idx.Files {
+		if !strings.HasSuffix(fileSummary.Path, ".go") {
+			continue
+		}
+		if filepath.ToSlash(filepath.Dir(filepath.Clean(fileSummary.Path))) != dir {
+			continue
+		}
+		absPath := filepath.Join(idx.Root, filepath.FromSlash(fileSummary.Path))
+		source, err := os.ReadFile(absPath)
+		if err != nil {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, absPath, source, parser.SkipObjectResolution)
+		if err != nil && parsed == nil {
+			continue
+		}
+		byPackage[parsed.Name.Name] = append(byPackage[parsed.Name.Name], parsed)
+		if filepath.ToSlash(fileSummary.Path) == filepath.ToSlash(relPath) {
+			target = parsed
+		}
+	}
+	if target == nil {
+		return nil, false
+	}
+
+	files := byPackage[target.Name.Name]
+	sort.Slice(files, func(i, j int) bool {
+		return fset.Position(files[i].Pos()).Filename < fset.Position(files[j].Pos()).Filename
+	})
+
+	info := &types.Info{
+		Defs: map[*ast.Ident]types.Object{},
+	}
+	config := &types.Config{
+		Importer: importer.Default(),
+		Error:    func(error) {},
+	}
+	pkg, _ := config.Check(target.Name.Name, fset, files, info)
+	qualifier := types.RelativeTo(pkg)
+
+	out := map[defKey]string{}
+	for ident, obj := range info.Defs {
+		if obj == nil || obj.Type() == nil {
+			continue
+		}
+		if _, isPkg := obj.(*types.PkgName); isPkg {
+			continue
+		}
+		position := fset.Position(ident.Pos())
+		if position.Filename != fset.Position(target.Pos()).Filename {
+			continue
+		}
+		typ := obj.Type()
+		if basic, ok := typ.(*types.Basic); ok && basic.Kind() == types.Invalid {
+			continue
+		}
+		out[defKey{name: ident.Name, line: position.Line}] = types.TypeString(typ, qualifier)
+	}
+	return out, true
+}
@@ -17,6 +17,9 @@ import (
 type Options struct {
 	FilePath string
 	Line     int
+	// Column is the 1-based cursor column. Zero resolves scope as if the
+	// cursor sat at the end of Line.
+	Column int
 }
 
 type Symbol struct {
@@ -29,6 +32,7 @@ type Symbol struct {
 type Report struct {
 	File    string        `json:"file"`
 	Line    int           `json:"line"`
+	Column  int           `json:"column,omitempty"`
 	Package string        `json:"package"`
 	Focus   *model.Symbol `json:"focus,omitempty"`
 	Symbols []Symbol      `json:"symbols,omitempty"`
@@ -44,6 +48,9 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 	if opts.Line <= 0 {
 		opts.Line = 1
 	}
+	if opts.Column < 0 {
+		opts.Column = 0
+	}
 
 	relPath, absPath, err := resolvePaths(idx.Root, opts.FilePath)
 	if err != nil {
@@ -79,6 +86,7 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 	report := Report{
 		File:    fileSummary.Path,
 		Line:    opts.Line,
+		Column:  opts.Column,
 		Package: inferPackageName(bound, root, fileSummary),
 	}
 
@@ -91,7 +99,8 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 	collector := newSymbolCollector()
 	addImportsFromIndex(collector, fileSummary)
 	addIndexedPackageSymbols(collector, idx, fileSummary)
-	addLocalScope(collector, bound, root, source, opts.Line)
+	addLocalScope(collector, bound, root, source, newCursor(opts.Line, opts.Column))
+	addGoTypeDetails(collector, idx, fileSummary.Path)
 
 	report.Symbols = collector.symbols()
 	return report, nil
@@ -205,38 +214,84 @@ func addIndexedPackageSymbols(collector *symbolCollector, idx *model.Index, file
 	}
 }
 
-// addLocalScope walks the tree-sitter AST to find declarations visible at the target line.
-// It finds the innermost scope containing the line and collects all declarations
-// visible from that point: function parameters, local variables, and block-scoped names.
-func addLocalScope(collector *symbolCollector, bound *gotreesitter.BoundTree, root *gotreesitter.Node, _ []byte, line int) {
-	// Find the innermost function/method containing the target line
-	funcNode := findContainingFunction(bound, root, line)
+// cursor is a 0-based source position. A line-only lookup uses the maximum
+// column so every node ending on the cursor line counts as already complete.
+type cursor struct {
+	row uint32
+	col uint32
+}
+
+func newCursor(line, column int) cursor {
+	c := cursor{row: uint32(line - 1), col: ^uint32(0)}
+	if column > 0 {
+		c.col = uint32(column - 1)
+	}
+	return c
+}
+
+func pointBefore(a gotreesitter.Point, c cursor) bool {
+	return a.Row < c.row || (a.Row == c.row && a.Column <= c.col)
+}
+
+// endsBefore reports whether node finishes at or before the cursor.
+func (c cursor) endsBefore(node *gotreesitter.Node) bool {
+	return pointBefore(node.EndPoint(), c)
+}
+
+// startsAfter reports whether node begins strictly after the cursor.
+func (c cursor) startsAfter(node *gotreesitter.Node) bool {
+	return !pointBefore(node.StartPoint(), c)
+}
+
+// within reports whether the cursor lies inside node's span.
+func (c cursor) within(node *gotreesitter.Node) bool {
+	return !c.startsAfter(node) && !c.endsBefore(node)
+}
+
+// addLocalScope walks the tree-sitter AST to find declarations visible at the cursor.
+// It resolves the full chain of enclosing functions and blocks, outermost first,
+// so closures see their own parameters plus every enclosing local, and inner
+// declarations shadow outer ones.
+func addLocalScope(collector *symbolCollector, bound *gotreesitter.BoundTree, root *gotreesitter.Node, _ []byte, pos cursor) {
+	// Find the outermost function/method containing the cursor; nested
+	// closures are picked up while descending through its body.
+	funcNode := findContainingFunction(bound, root, pos)
 	if funcNode == nil {
 		return
 	}
+	collectFunctionScope(collector, bound, funcNode, pos)
+}
 
-	// Collect function parameters
+// collectFunctionScope collects a function's parameters and the locals of its
+// body visible at the cursor.
+func collectFunctionScope(collector *symbolCollector, bound *gotreesitter.BoundTree, funcNode *gotreesitter.Node, pos cursor) {
 	collectFunctionParams(collector, bound, funcNode)
 
-	// Find the function body and walk it for local declarations
 	body := findFunctionBody(bound, funcNode)
-	if body != nil {
-		collectBlockScope(collector, bound, body, line)
+	if body == nil {
+		// Expression-bodied functions (arrow functions, lambdas) have no
+		// block but may still contain nested closures.
+		recurseIntoContainingBlock(collector, bound, funcNode, pos)
+		return
+	}
+	if pos.within(body) {
+		collectBlockScope(collector, bound, body, pos)
 	}
 }
 
-// findContainingFunction finds the innermost function/method declaration containing the line.
-func findContainingFunction(bound *gotreesitter.BoundTree, root *gotreesitter.Node, line int) *gotreesitter.Node {
+// findContainingFunction finds the outermost function/method declaration containing the cursor.
+func findContainingFunction(bound *gotreesitter.BoundTree, root *gotreesitter.Node, pos cursor) *gotreesitter.Node {
 	var best *gotreesitter.Node
 	gotreesitter.Walk(root, func(node *gotreesitter.Node, depth int) gotreesitter.WalkAction {
-		nodeType := bound.NodeType(node)
-		if !isFunctionDecl(nodeType) {
-			return gotreesitter.WalkContinue
+		if best != nil {
+			return gotreesitter.WalkSkipChildren
 		}
-		start := int(node.StartPoint().Row) + 1
-		end := int(node.EndPoint().Row) + 1
-		if line >= start && line <= end {
+		if !pos.within(node) {
+			return gotreesitter.WalkSkipChildren
+		}
+		if isFunctionDecl(bound.NodeType(node)) {
 			best = node
+			return gotreesitter.WalkSkipChildren
 		}
 		return gotreesitter.WalkContinue
 	})
@@ -251,7 +306,8 @@ func isFunctionDecl(nodeType string) bool {
 		"function_definition", "function_item",
 		"constructor_declaration",
 		"method",
-		"function_definition_statement":
+		"function_definition_statement",
+		"lambda", "closure_expression":
 		return true
 	}
 	return false
@@ -264,6 +320,23 @@ func collectFunctionParams(collector *symbolCollector, bound *gotreesitter.Bound
 	funcType := bound.NodeType(funcNode)
 	isGoMethod := funcType == "method_declaration"
 
+	if funcType == "arrow_function" {
+		// Single bare parameter: `x => x + 1`.
+		for i := 0; i < funcNode.ChildCount(); i++ {
+			child := funcNode.Child(i)
+			if !child.IsNamed() {
+				if strings.TrimSpace(bound.NodeText(child)) == "=>" {
+					break
+				}
+				continue
+			}
+			if bound.NodeType(child) == "identifier" {
+				collector.add(bound.NodeText(child), "param", "", int(child.StartPoint().Row)+1)
+			}
+			break
+		}
+	}
+
 	paramListIndex := 0
 	for i := 0; i < funcNode.ChildCount(); i++ {
 		child := funcNode.Child(i)
@@ -280,7 +353,7 @@ func collectFunctionParams(collector *symbolCollector, bound *gotreesitter.Bound
 			}
 			paramListIndex++
 		case "parameters", "formal_parameters",
-			"function_params", "lambda_parameters":
+			"function_params", "lambda_parameters", "closure_parameters":
 			collectParamList(collector, bound, child)
 		}
 	}
@@ -373,26 +446,24 @@ func isBlockNode(nodeType string) bool {
 	return false
 }
 
-// collectBlockScope walks a block node collecting declarations visible at the target line.
+// collectBlockScope walks a block node collecting declarations visible at the cursor.
 // It handles both direct statement children and statement_list wrappers.
-func collectBlockScope(collector *symbolCollector, bound *gotreesitter.BoundTree, block *gotreesitter.Node, line int) {
+func collectBlockScope(collector *symbolCollector, bound *gotreesitter.BoundTree, block *gotreesitter.Node, pos cursor) {
 	stmts := statementsOf(bound, block)
 	for _, child := range stmts {
-		start := int(child.StartPoint().Row) + 1
-		end := int(child.EndPoint().Row) + 1
-
-		if start > line {
+		if pos.startsAfter(child) {
 			break
 		}
 
-		if line > end {
+		if pos.endsBefore(child) {
 			collectDeclsFromStmt(collector, bound, child)
 			continue
 		}
 
-		// We're inside this statement — collect its init-clause decls and recurse
-		collectDeclsFromStmt(collector, bound, child)
-		recurseIntoContainingBlock(collector, bound, child, line)
+		// We're inside this statement: its header declarations (loop
+		// variables, if/switch initializers) are visible, then descend.
+		collectHeaderDecls(collector, bound, child, pos)
+		recurseIntoContainingBlock(collector, bound, child, pos)
 		return
 	}
 }
@@ -444,11 +515,14 @@ func collectDeclsFromStmt(collector *symbolCollector, bound *gotreesitter.BoundT
 	// Rust let bindings
 	case "let_declaration":
 		collectRustLetDecl(collector, bound, stmt)
-	// Go range statements
-	case "for_statement":
-		collectGoForDecls(collector, bound, stmt)
-	case "range_clause":
-		collectRangeClauseDecls(collector, bound, stmt)
+	// Python wraps assignments in expression statements
+	case "expression_statement":
+		for i := 0; i < stmt.ChildCount(); i++ {
+			inner := stmt.Child(i)
+			if inner.IsNamed() && bound.NodeType(inner) == "assignment" {
+				collectPythonAssignment(collector, bound, inner)
+			}
+		}
 	// Labeled statements — recurse to inner stmt
 	case "labeled_statement":
 		for i := 0; i < stmt.ChildCount(); i++ {
@@ -461,6 +535,31 @@ func collectDeclsFromStmt(collector *symbolCollector, bound *gotreesitter.BoundT
 	}
 }
 
+// collectHeaderDecls extracts declarations scoped to a compound statement's
+// own body, such as Go loop variables and if/switch initializers. They are only
+// visible while the cursor is inside the statement.
+func collectHeaderDecls(collector *symbolCollector, bound *gotreesitter.BoundTree, stmt *gotreesitter.Node, pos cursor) {
+	switch bound.NodeType(stmt) {
+	case "for_statement":
+		collectGoForDecls(collector, bound, stmt, pos)
+	case "if_statement", "expression_switch_statement", "type_switch_statement":
+		for i := 0; i < stmt.ChildCount(); i++ {
+			child := stmt.Child(i)
+			if bound.NodeType(child) == "short_var_declaration" && pos.endsBefore(child) {
+				collectShortVarDecl(collector, bound, child)
+			}
+		}
+	case "labeled_statement":
+		for i := 0; i < stmt.ChildCount(); i++ {
+			inner := stmt.Child(i)
+			if inner.IsNamed() && bound.NodeType(inner) != "label_name" && bound.NodeType(inner) != "identifier" {
+				collectHeaderDecls(collector, bound, inner, pos)
+				break
+			}
+		}
+	}
+}
+
 func collectShortVarDecl(collector *symbolCollector, bound *gotreesitter.BoundTree, node *gotreesitter.Node) {
 	// In Go short var decl, the LHS identifiers come before `:=`
 	for i := 0; i < node.ChildCount(); i++ {
@@ -573,13 +672,20 @@ func collectRustLetDecl(collector *symbolCollector, bound *gotreesitter.BoundTre
 	}
 }
 
-func collectGoForDecls(collector *symbolCollector, bound *gotreesitter.BoundTree, node *gotreesitter.Node) {
+func collectGoForDecls(collector *symbolCollector, bound *gotreesitter.BoundTree, node *gotreesitter.Node, pos cursor) {
 	for i := 0; i < node.ChildCount(); i++ {
 		child := node.Child(i)
 		nodeType := bound.NodeType(child)
 		switch nodeType {
 		case "range_clause":
 			collectRangeClauseDecls(collector, bound, child)
+		case "for_clause":
+			for j := 0; j < child.ChildCount(); j++ {
+				gc := child.Child(j)
+				if bound.NodeType(gc) == "short_var_declaration" && pos.endsBefore(gc) {
+					collectShortVarDecl(collector, bound, gc)
+				}
+			}
 		case "short_var_declaration":
 			collectShortVarDecl(collector, bound, child)
 		}
@@ -605,27 +711,32 @@ func collectRangeClauseDecls(collector *symbolCollector, bound *gotreesitter.Bou
 	}
 }
 
-// recurseIntoContainingBlock finds inner blocks within a statement and recurses.
-func recurseIntoContainingBlock(collector *symbolCollector, bound *gotreesitter.BoundTree, node *gotreesitter.Node, line int) {
+// recurseIntoContainingBlock descends into the child of node that contains the
+// cursor. Blocks continue statement-level resolution; function literals and
+// closures (e.g. callbacks passed as call arguments) open a new scope whose
+// parameters and locals shadow the enclosing ones.
+func recurseIntoContainingBlock(collector *symbolCollector, bound *gotreesitter.BoundTree, node *gotreesitter.Node, pos cursor) {
 	for i := 0; i < node.ChildCount(); i++ {
 		child := node.Child(i)
 		if !child.IsNamed() {
 			continue
 		}
-
-		start := int(child.StartPoint().Row) + 1
-		end := int(child.EndPoint().Row) + 1
-		if line < start || line > end {
+		if !pos.within(child) {
 			continue
 		}
 
 		nodeType := bound.NodeType(child)
+		if isFunctionDecl(nodeType) {
+			collectFunctionScope(collector, bound, child, pos)
+			return
+		}
 		if isBlockNode(nodeType) {
-			collectBlockScope(collector, bound, child, line)
+			collectBlockScope(collector, bound, child, pos)
 			return
 		}
 		// Recurse deeper for compound statements (if, for, switch, etc.)
-		recurseIntoContainingBlock(collector, bound, child, line)
+		recurseIntoContainingBlock(collector, bound, child, pos)
+		return
 	}
 }
 
@@ -3,6 +3,7 @@ package scope
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/odvcencio/gts-suite/pkg/index"
@@ -61,6 +62,55 @@ func (s *Service) Work(input string) (out int) {
 	}
 }
 
+func TestBuild_AddsGoTypeDetails(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "sample.go")
+	source := `package sample
+
+type Service struct{}
+
+func NewService() *Service { return &Service{} }
+
+func Work(input string) {
+	value := input + "!"
+	count := len(value)
+	svc := NewService()
+	_, _ = count, svc
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+
+	report, err := Build(idx, Options{FilePath: sourcePath, Line: 11})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	want := map[string]string{
+		"input": "string",
+		"value": "string",
+		"count": "int",
+		"svc":   "*Service",
+	}
+	for _, symbol := range report.Symbols {
+		if expected, ok := want[symbol.Name]; ok {
+			if symbol.Detail != expected {
+				t.Fatalf("expected %s detail %q, got %q", symbol.Name, expected, symbol.Detail)
+			}
+			delete(want, symbol.Name)
+		}
+	}
+	if len(want) > 0 {
+		t.Fatalf("missing symbols in scope: %v", want)
+	}
+}
+
 func TestBuild_RejectsUnsupportedLanguage(t *testing.T) {
 	tmpDir := t.TempDir()
 	mdPath := filepath.Join(tmpDir, "notes.md")
@@ -151,6 +201,76 @@ func TestOnly() {}
 	}
 }
 
+func TestBuild_ColumnResolvesClosureInCallArgs(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "sample.go")
+	source := `package sample
+
+func each(items []int, fn func(int)) {}
+
+func Run(items []int) {
+	total := 0
+	for i := 0; i < len(items); i++ {
+		total += i
+	}
+	each(items, func(item int) { seen := item; _ = seen }); after := total
+	_ = after
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+
+	line := 10
+	lineText := "\teach(items, func(item int) { seen := item; _ = seen }); after := total"
+	insideClosure := strings.Index(lineText, "_ = seen") + 1
+	beforeClosure := strings.Index(lineText, "items") + 1
+
+	inside, err := Build(idx, Options{FilePath: sourcePath, Line: line, Column: insideClosure})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if inside.Column != insideClosure {
+		t.Fatalf("expected report column %d, got %d", insideClosure, inside.Column)
+	}
+	for _, name := range []string{"items", "total", "item", "seen"} {
+		if !hasSymbol(inside, name) {
+			t.Fatalf("expected %q in scope inside closure, got %#v", name, inside.Symbols)
+		}
+	}
+	for _, name := range []string{"i", "after"} {
+		if hasSymbol(inside, name) {
+			t.Fatalf("did not expect %q in scope inside closure", name)
+		}
+	}
+
+	outside, err := Build(idx, Options{FilePath: sourcePath, Line: line, Column: beforeClosure})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	for _, name := range []string{"item", "seen", "i"} {
+		if hasSymbol(outside, name) {
+			t.Fatalf("did not expect %q in scope before closure", name)
+		}
+	}
+
+	endOfLine, err := Build(idx, Options{FilePath: sourcePath, Line: line + 1})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if !hasSymbol(endOfLine, "after") {
+		t.Fatal("expected after in scope on the following line")
+	}
+	if hasSymbol(endOfLine, "item") {
+		t.Fatal("did not expect closure parameter to leak after the call")
+	}
+}
+
 func hasSymbol(report Report, name string) bool {
 	for _, symbol := range report.Symbols {
 		if symbol.Name == name {
@@ -0,0 +1,286 @@
+// Package snapshot keeps timestamped, optionally labeled index snapshots
+// under .gts/snapshots, so structural diffs between releases or points in
+// time can name a snapshot instead of a cache file path. A manifest lists
+// the snapshots; each one's index is stored next to it as <id>.json.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Dir is where ForRoot keeps snapshots, relative to the project root.
+const Dir = ".gts/snapshots"
+
+const manifestName = "manifest.json"
+
+// idLayout formats snapshot IDs from their UTC creation time.
+const idLayout = "20060102T150405Z"
+
+// Snapshot describes a stored index.
+type Snapshot struct {
+	// ID is unique within the store and derived from Created.
+	ID string `json:"id"`
+	// Label names the snapshot, such as a release tag. Labels are unique:
+	// saving under a label replaces the snapshot that had it.
+	Label    string    `json:"label,omitempty"`
+	Created  time.Time `json:"created"`
+	Revision string    `json:"revision,omitempty"`
+	Files    int       `json:"files"`
+	Symbols  int       `json:"symbols"`
+}
+
+// Name returns the label of s, or its ID when it has none.
+func (s Snapshot) Name() string {
+	if s.Label != "" {
+		return s.Label
+	}
+	return s.ID
+}
+
+type manifest struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// Store is a directory of snapshots.
+type Store struct {
+	dir string
+}
+
+// New returns a Store keeping snapshots in dir, which is created on first
+// Save.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// ForRoot returns the Store under the project root.
+func ForRoot(root string) *Store {
+	return New(filepath.Join(root, filepath.FromSlash(Dir)))
+}
+
+// Dir returns the directory the store keeps snapshots in.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// List returns the stored snapshots, oldest first.
+func (s *Store) List() ([]Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, manifestName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Join(s.dir, manifestName), err)
+	}
+	sortSnapshots(m.Snapshots)
+	return m.Snapshots, nil
+}
+
+// Save stores idx as a snapshot created at now under label, which may be
+// empty, replacing any snapshot that already has the label.
+func (s *Store) Save(label string, idx *model.Index, now time.Time) (Snapshot, error) {
+	if idx == nil {
+		return Snapshot{}, errors.New("no index to save")
+	}
+	label = strings.TrimSpace(label)
+	snapshots, err := s.List()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	now = now.UTC()
+	ids := make(map[string]bool, len(snapshots))
+	for _, snap := range snapshots {
+		ids[snap.ID] = true
+	}
+	id := now.Format(idLayout)
+	for n := 2; ids[id]; n++ {
+		id = fmt.Sprintf("%s-%d", now.Format(idLayout), n)
+	}
+
+	snap := Snapshot{
+		ID:       id,
+		Label:    label,
+		Created:  now,
+		Revision: idx.Revision,
+		Files:    idx.FileCount(),
+		Symbols:  idx.SymbolCount(),
+	}
+	if err := index.Save(s.indexPath(id), idx); err != nil {
+		return Snapshot{}, err
+	}
+
+	kept := make([]Snapshot, 0, len(snapshots)+1)
+	var replaced []Snapshot
+	for _, existing := range snapshots {
+		if label != "" && existing.Label == label {
+			replaced = append(replaced, existing)
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	if err := s.writeManifest(append(kept, snap)); err != nil {
+		_ = os.Remove(s.indexPath(id))
+		return Snapshot{}, err
+	}
+	for _, old := range replaced {
+		_ = os.Remove(s.indexPath(old.ID))
+	}
+	return snap, nil
+}
+
+// Find returns the snapshot ref names: the one labeled ref, else the one
+// with ID ref, else, for "latest", the newest. It reports false when none
+// matches.
+func (s *Store) Find(ref string) (Snapshot, bool, error) {
+	snapshots, err := s.List()
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	for _, snap := range snapshots {
+		if snap.Label == ref {
+			return snap, true, nil
+		}
+	}
+	for _, snap := range snapshots {
+		if snap.ID == ref {
+			return snap, true, nil
+		}
+	}
+	if ref == "latest" && len(snapshots) > 0 {
+		return snapshots[len(snapshots)-1], true, nil
+	}
+	return Snapshot{}, false, nil
+}
+
+// Load reads the index of snap.
+func (s *Store) Load(snap Snapshot) (*model.Index, error) {
+	return index.Load(s.indexPath(snap.ID))
+}
+
+// Policy says which snapshots Prune removes.
+type Policy struct {
+	// Keep is how many of the newest snapshots to keep; 0 keeps them all.
+	Keep int
+	// MaxAge removes snapshots created longer ago than this; 0 disables it.
+	MaxAge time.Duration
+	// Labeled lets the policy remove labeled snapshots, which it otherwise
+	// leaves alone and does not count toward Keep.
+	Labeled bool
+	// DryRun reports what would be removed without removing it.
+	DryRun bool
+}
+
+// Prune removes the snapshots policy selects, judging age against now, and
+// returns them oldest first.
+func (s *Store) Prune(policy Policy, now time.Time) ([]Snapshot, error) {
+	snapshots, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Snapshot
+	for _, snap := range snapshots {
+		if snap.Label == "" || policy.Labeled {
+			candidates = append(candidates, snap)
+		}
+	}
+	remove := map[string]bool{}
+	if policy.Keep > 0 && len(candidates) > policy.Keep {
+		for _, snap := range candidates[:len(candidates)-policy.Keep] {
+			remove[snap.ID] = true
+		}
+	}
+	if policy.MaxAge > 0 {
+		for _, snap := range candidates {
+			if now.Sub(snap.Created) > policy.MaxAge {
+				remove[snap.ID] = true
+			}
+		}
+	}
+	if len(remove) == 0 {
+		return nil, nil
+	}
+
+	var removed, kept []Snapshot
+	for _, snap := range snapshots {
+		if remove[snap.ID] {
+			removed = append(removed, snap)
+		} else {
+			kept = append(kept, snap)
+		}
+	}
+	if policy.DryRun {
+		return removed, nil
+	}
+	if err := s.writeManifest(kept); err != nil {
+		return nil, err
+	}
+	for _, snap := range removed {
+		if err := os.Remove(s.indexPath(snap.ID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+func (s *Store) indexPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// writeManifest replaces the manifest atomically, so a concurrent List never
+// sees a partial one.
+func (s *Store) writeManifest(snapshots []Snapshot) error {
+	sortSnapshots(snapshots)
+	if snapshots == nil {
+		snapshots = []Snapshot{}
+	}
+	data, err := json.MarshalIndent(manifest{Snapshots: snapshots}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, manifestName+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, manifestName)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func sortSnapshots(snapshots []Snapshot) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if snapshots[i].Created.Equal(snapshots[j].Created) {
+			return snapshots[i].ID < snapshots[j].ID
+		}
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+}
@@ -0,0 +1,132 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func testIndex(symbols ...string) *model.Index {
+	file := model.FileSummary{Path: "a.go", Language: "go"}
+	for _, name := range symbols {
+		file.Symbols = append(file.Symbols, model.Symbol{File: "a.go", Kind: "function_definition", Name: name})
+	}
+	return &model.Index{Root: "/repo", Files: []model.FileSummary{file}}
+}
+
+func TestStoreSaveFindLoad(t *testing.T) {
+	store := New(t.TempDir())
+	if snapshots, err := store.List(); err != nil || len(snapshots) != 0 {
+		t.Fatalf("expected an empty store, got %v, %v", snapshots, err)
+	}
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	release, err := store.Save("v1.2.0", testIndex("A"), now)
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if release.ID != "20260102T030405Z" || release.Files != 1 || release.Symbols != 1 {
+		t.Fatalf("unexpected snapshot %+v", release)
+	}
+	unlabeled, err := store.Save("", testIndex("A", "B"), now)
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if unlabeled.ID != "20260102T030405Z-2" {
+		t.Fatalf("expected a distinct ID for a snapshot saved in the same second, got %q", unlabeled.ID)
+	}
+
+	snap, ok, err := store.Find("v1.2.0")
+	if err != nil || !ok || snap.ID != release.ID {
+		t.Fatalf("Find(label) = %+v, %v, %v", snap, ok, err)
+	}
+	if snap, ok, _ := store.Find(unlabeled.ID); !ok || snap.ID != unlabeled.ID {
+		t.Fatalf("Find(id) = %+v, %v", snap, ok)
+	}
+	if snap, ok, _ := store.Find("latest"); !ok || snap.ID != unlabeled.ID {
+		t.Fatalf("Find(latest) = %+v, %v", snap, ok)
+	}
+	if _, ok, _ := store.Find("v9"); ok {
+		t.Fatal("expected no snapshot for an unknown label")
+	}
+
+	idx, err := store.Load(unlabeled)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if idx.SymbolCount() != 2 {
+		t.Fatalf("expected the saved index back, got %d symbols", idx.SymbolCount())
+	}
+}
+
+func TestStoreSaveReplacesLabel(t *testing.T) {
+	store := New(t.TempDir())
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	first, err := store.Save("v1", testIndex("A"), now)
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	second, err := store.Save("v1", testIndex("A", "B"), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	snapshots, err := store.List()
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].ID != second.ID {
+		t.Fatalf("expected only the newer v1 snapshot, got %+v", snapshots)
+	}
+	if _, err := os.Stat(filepath.Join(store.Dir(), first.ID+".json")); !os.IsNotExist(err) {
+		t.Fatalf("expected the replaced snapshot's index to be deleted, stat error: %v", err)
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	store := New(t.TempDir())
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := store.Save("release", testIndex("A"), start); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	for day := 1; day <= 4; day++ {
+		if _, err := store.Save("", testIndex("A"), start.AddDate(0, 0, day)); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+	}
+	now := start.AddDate(0, 0, 5)
+
+	removed, err := store.Prune(Policy{Keep: 2, DryRun: true}, now)
+	if err != nil {
+		t.Fatalf("Prune returned error: %v", err)
+	}
+	if len(removed) != 2 || removed[0].ID != "20260102T000000Z" || removed[1].ID != "20260103T000000Z" {
+		t.Fatalf("expected the two oldest unlabeled snapshots, got %+v", removed)
+	}
+	if snapshots, _ := store.List(); len(snapshots) != 5 {
+		t.Fatalf("expected a dry run to keep all snapshots, got %d", len(snapshots))
+	}
+
+	if _, err := store.Prune(Policy{Keep: 2}, now); err != nil {
+		t.Fatalf("Prune returned error: %v", err)
+	}
+	snapshots, _ := store.List()
+	if len(snapshots) != 3 || snapshots[0].Label != "release" {
+		t.Fatalf("expected the labeled snapshot and two newest to remain, got %+v", snapshots)
+	}
+
+	removed, err = store.Prune(Policy{MaxAge: 36 * time.Hour, Labeled: true}, now)
+	if err != nil {
+		t.Fatalf("Prune returned error: %v", err)
+	}
+	if len(removed) != 2 || removed[0].Label != "release" {
+		t.Fatalf("expected --labeled to prune the old release too, got %+v", removed)
+	}
+	entries, _ := os.ReadDir(store.Dir())
+	if len(entries) != 2 {
+		t.Fatalf("expected the manifest and one index left, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,234 @@
+package sqlusage
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Each query captures a database call as @call with its method name as
+// @verb and its argument list as @args, and a string constant as @const with
+// its value as @value.
+var queryByLanguageName = map[string]string{
+	"go":         goSQLQuery,
+	"javascript": jsSQLQuery,
+	"typescript": jsSQLQuery,
+	"tsx":        jsSQLQuery,
+	"python":     pythonSQLQuery,
+	"java":       javaSQLQuery,
+}
+
+const goSQLQuery = `
+(call_expression
+  function: (selector_expression
+    field: (field_identifier) @verb)
+  arguments: (argument_list) @args
+  (#match? @verb "^(Exec|ExecContext|Query|QueryContext|QueryRow|QueryRowContext|Queryx|QueryxContext|QueryRowx|QueryRowxContext|Prepare|PrepareContext|Preparex|PreparexContext|Get|GetContext|Select|SelectContext|MustExec|MustExecContext|NamedExec|NamedExecContext|NamedQuery|NamedQueryContext|Raw)$")
+) @call
+
+(const_spec
+  name: (identifier) @const
+  value: (expression_list) @value)
+
+(var_spec
+  name: (identifier) @const
+  value: (expression_list) @value)
+`
+
+const jsSQLQuery = `
+(call_expression
+  function: (member_expression
+    property: (property_identifier) @verb)
+  arguments: (arguments) @args
+  (#match? @verb "^(query|execute|prepare|raw|all|get|run|exec|each|none|one|oneOrNone|many|manyOrNone|any)$")
+) @call
+
+(variable_declarator
+  name: (identifier) @const
+  value: (_) @value)
+`
+
+const pythonSQLQuery = `
+(call
+  function: (attribute
+    attribute: (identifier) @verb)
+  arguments: (argument_list) @args
+  (#match? @verb "^(execute|executemany|executescript|exec_driver_sql|raw|fetch|fetchrow|fetchval|text)$")
+) @call
+
+(call
+  function: (identifier) @verb
+  arguments: (argument_list) @args
+  (#eq? @verb "text")
+) @call
+
+(assignment
+  left: (identifier) @const
+  right: (_) @value)
+`
+
+const javaSQLQuery = `
+(method_invocation
+  name: (identifier) @verb
+  arguments: (argument_list) @args
+  (#match? @verb "^(prepareStatement|prepareCall|executeQuery|executeUpdate|executeLargeUpdate|execute|addBatch|createQuery|createNativeQuery|query|queryForObject|queryForList|queryForMap|queryForRowSet|update|batchUpdate)$")
+) @call
+
+(annotation
+  name: (identifier) @verb
+  arguments: (annotation_argument_list) @args
+  (#eq? @verb "Query")
+) @call
+
+(variable_declarator
+  name: (identifier) @const
+  value: (_) @value)
+`
+
+var interpolation = regexp.MustCompile(`\$\{[^}]*\}|\{[^}]*\}`)
+
+type fileMatcher struct {
+	lang      *gotreesitter.Language
+	src       []byte
+	constants map[string]*gotreesitter.Node
+}
+
+func extractFile(file model.FileSummary, lang *gotreesitter.Language, src []byte, matches []gotreesitter.QueryMatch) []Usage {
+	m := fileMatcher{lang: lang, src: src, constants: map[string]*gotreesitter.Node{}}
+	type call struct{ node, args *gotreesitter.Node }
+	var calls []call
+	for _, match := range matches {
+		captures := map[string]*gotreesitter.Node{}
+		for _, capture := range match.Captures {
+			if capture.Node != nil {
+				captures[capture.Name] = capture.Node
+			}
+		}
+		switch {
+		case captures["const"] != nil && captures["value"] != nil:
+			value := captures["value"]
+			if value.Type(lang) == "expression_list" {
+				value = value.NamedChild(0)
+			}
+			m.constants[m.text(captures["const"])] = value
+		case captures["call"] != nil && captures["args"] != nil:
+			calls = append(calls, call{node: captures["call"], args: captures["args"]})
+		}
+	}
+
+	var usages []Usage
+	for _, c := range calls {
+		for _, arg := range m.arguments(c.args) {
+			if arg.Type(lang) == "element_value_pair" {
+				arg = arg.ChildByFieldName("value", lang)
+			}
+			text, ok := m.stringValue(arg, 0)
+			if !ok {
+				continue
+			}
+			operation, tables, ok := parseStatement(text)
+			if !ok {
+				continue
+			}
+			usages = append(usages, Usage{
+				File:      file.Path,
+				Line:      int(c.node.StartPoint().Row) + 1,
+				Language:  file.Language,
+				Call:      compact(string(src[c.node.StartByte():c.args.StartByte()])),
+				Operation: operation,
+				Tables:    tables,
+				Query:     compact(text),
+			})
+			break
+		}
+	}
+	return usages
+}
+
+func (m *fileMatcher) text(node *gotreesitter.Node) string {
+	return strings.TrimSpace(node.Text(m.src))
+}
+
+func (m *fileMatcher) arguments(list *gotreesitter.Node) []*gotreesitter.Node {
+	var args []*gotreesitter.Node
+	for i := 0; i < list.ChildCount(); i++ {
+		child := list.Child(i)
+		if child != nil && child.IsNamed() && child.Type(m.lang) != "comment" {
+			args = append(args, child)
+		}
+	}
+	return args
+}
+
+// stringValue evaluates a string literal, a concatenation of literals, or a
+// constant naming one. Interpolations and other operands become "?".
+func (m *fileMatcher) stringValue(node *gotreesitter.Node, depth int) (string, bool) {
+	if node == nil || depth > 8 {
+		return "", false
+	}
+	switch node.Type(m.lang) {
+	case "interpreted_string_literal":
+		text := m.text(node)
+		if value, err := strconv.Unquote(text); err == nil {
+			return value, true
+		}
+		return strings.Trim(text, `"`), true
+	case "raw_string_literal", "string_literal", "text_block":
+		return trimQuotes(m.text(node)), true
+	case "string":
+		text := m.text(node)
+		prefix := strings.ToLower(text[:len(text)-len(strings.TrimLeft(text, "rRbBuUfF"))])
+		value := trimQuotes(text[len(prefix):])
+		if strings.Contains(prefix, "f") {
+			value = interpolation.ReplaceAllString(value, "?")
+		}
+		return value, true
+	case "template_string":
+		return interpolation.ReplaceAllString(trimQuotes(m.text(node)), "?"), true
+	case "concatenated_string":
+		var parts []string
+		for _, part := range m.arguments(node) {
+			value, ok := m.stringValue(part, depth+1)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, value)
+		}
+		return strings.Join(parts, ""), true
+	case "binary_expression", "binary_operator":
+		left, leftOK := m.stringValue(node.ChildByFieldName("left", m.lang), depth+1)
+		right, rightOK := m.stringValue(node.ChildByFieldName("right", m.lang), depth+1)
+		if !leftOK && !rightOK {
+			return "", false
+		}
+		if !leftOK {
+			left = "?"
+		}
+		if !rightOK {
+			right = "?"
+		}
+		return left + right, true
+	case "identifier":
+		return m.stringValue(m.constants[m.text(node)], depth+1)
+	case "parenthesized_expression":
+		return m.stringValue(node.NamedChild(0), depth+1)
+	}
+	return "", false
+}
+
+func trimQuotes(text string) string {
+	for _, quote := range []string{`"""`, `'''`, `"`, `'`, "`"} {
+		if len(text) >= 2*len(quote) && strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) {
+			return text[len(quote) : len(text)-len(quote)]
+		}
+	}
+	return text
+}
+
+func compact(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,261 @@
+// Package sqlusage finds SQL passed to database call sites in indexed
+// sources and maps each query and table to the functions issuing it.
+//
+// Call sites are matched with tree-sitter queries for Go (database/sql, sqlx,
+// gorm), JavaScript and TypeScript (node-postgres, mysql, sqlite drivers,
+// knex), Python (DB-API cursors, SQLAlchemy text, asyncpg), and Java (JDBC,
+// JPA, Spring JdbcTemplate, and @Query annotations). A query is recognized
+// when an argument is a string literal, a concatenation of literals, or a
+// string constant declared in the same file, and its text starts with a SQL
+// statement keyword. Tables are read from the statement text, so dynamically
+// built queries are only partially covered.
+package sqlusage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/parsesession"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// Usage is one SQL statement passed to a database call.
+type Usage struct {
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	Language  string   `json:"language"`
+	Call      string   `json:"call"`
+	Operation string   `json:"operation"`
+	Tables    []string `json:"tables,omitempty"`
+	Query     string   `json:"query"`
+	// Caller is the function containing the call, when there is one.
+	Caller *xref.Definition `json:"caller,omitempty"`
+}
+
+// TableUsage summarizes the statements touching one table.
+type TableUsage struct {
+	Table      string   `json:"table"`
+	Operations []string `json:"operations"`
+	Queries    int      `json:"queries"`
+	Callers    []string `json:"callers,omitempty"`
+}
+
+type Report struct {
+	Usages []Usage      `json:"usages"`
+	Tables []TableUsage `json:"tables"`
+}
+
+// Extract returns the SQL usages in the indexed files. Callers are resolved
+// against graph when it is not nil.
+func Extract(idx *model.Index, graph *xref.Graph) ([]Usage, error) {
+	if idx == nil {
+		return nil, fmt.Errorf("index is nil")
+	}
+
+	session := parsesession.New()
+
+	var usages []Usage
+	for _, file := range idx.Files {
+		queryText, ok := queryByLanguageName[file.Language]
+		if !ok {
+			continue
+		}
+		lang, ok := session.Language(file.Language)
+		if !ok {
+			continue
+		}
+		query, err := session.Query(file.Language, queryText)
+		if err != nil {
+			return nil, fmt.Errorf("compile %s SQL query: %w", file.Language, err)
+		}
+
+		source, err := os.ReadFile(filepath.Join(idx.Root, filepath.FromSlash(file.Path)))
+		if err != nil {
+			continue
+		}
+
+		tree, parseErr := session.Parse(file.Language, source)
+		if parseErr != nil {
+			continue
+		}
+
+		usages = append(usages, extractFile(file, lang, source, query.Execute(tree))...)
+		tree.Release()
+	}
+
+	if graph != nil {
+		resolveCallers(graph, usages)
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].File == usages[j].File {
+			return usages[i].Line < usages[j].Line
+		}
+		return usages[i].File < usages[j].File
+	})
+	return usages, nil
+}
+
+// Build extracts the SQL usages of the index and summarizes them per table.
+func Build(idx *model.Index, graph *xref.Graph) (Report, error) {
+	usages, err := Extract(idx, graph)
+	if err != nil {
+		return Report{}, err
+	}
+	return Report{Usages: usages, Tables: Tables(usages)}, nil
+}
+
+// Tables summarizes usages per table, ordered by table name.
+func Tables(usages []Usage) []TableUsage {
+	type tableSet struct {
+		operations map[string]bool
+		callers    map[string]bool
+		queries    int
+	}
+	sets := map[string]*tableSet{}
+	for _, usage := range usages {
+		for _, table := range usage.Tables {
+			set, ok := sets[table]
+			if !ok {
+				set = &tableSet{operations: map[string]bool{}, callers: map[string]bool{}}
+				sets[table] = set
+			}
+			set.operations[usage.Operation] = true
+			set.queries++
+			if usage.Caller != nil {
+				set.callers[CallerLabel(*usage.Caller)] = true
+			}
+		}
+	}
+
+	tables := make([]TableUsage, 0, len(sets))
+	for table, set := range sets {
+		tables = append(tables, TableUsage{
+			Table:      table,
+			Operations: sortedKeys(set.operations),
+			Queries:    set.queries,
+			Callers:    sortedKeys(set.callers),
+		})
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Table < tables[j].Table })
+	return tables
+}
+
+// CallerLabel names a caller as "pkg.Func" or "pkg.Type.Method", without
+// the package for the root directory.
+func CallerLabel(definition xref.Definition) string {
+	name := definition.Name
+	if receiver := strings.Fields(definition.Receiver); len(receiver) > 0 {
+		name = strings.TrimLeft(receiver[len(receiver)-1], "*&") + "." + name
+	}
+	if definition.Package == "" || definition.Package == "." {
+		return name
+	}
+	return definition.Package + "." + name
+}
+
+// Touches reports whether the usage reads or writes table, compared without
+// case or schema qualification.
+func (u Usage) Touches(table string) bool {
+	want := strings.ToLower(table)
+	for _, name := range u.Tables {
+		name = strings.ToLower(name)
+		if name == want || strings.HasSuffix(name, "."+want) {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveCallers(graph *xref.Graph, usages []Usage) {
+	callablesByFile := map[string][]int{}
+	for i, definition := range graph.Definitions {
+		if definition.Callable {
+			callablesByFile[definition.File] = append(callablesByFile[definition.File], i)
+		}
+	}
+	for i := range usages {
+		best := -1
+		for _, candidate := range callablesByFile[usages[i].File] {
+			definition := graph.Definitions[candidate]
+			if usages[i].Line < definition.StartLine || usages[i].Line > definition.EndLine {
+				continue
+			}
+			// The innermost definition is the one starting last.
+			if best < 0 || definition.StartLine > graph.Definitions[best].StartLine {
+				best = candidate
+			}
+		}
+		if best >= 0 {
+			caller := graph.Definitions[best]
+			usages[i].Caller = &caller
+		}
+	}
+}
+
+var statementKeywords = map[string]bool{
+	"SELECT": true, "INSERT": true, "UPDATE": true, "DELETE": true, "WITH": true,
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "REPLACE": true,
+	"MERGE": true, "UPSERT": true,
+}
+
+var (
+	tablePattern = regexp.MustCompile("(?i)\\b(?:from|join|into|update|table(?:\\s+if\\s+(?:not\\s+)?exists)?)\\s+(?:only\\s+)?([`\"\\[]?[A-Za-z_][\\w$]*[`\"\\]]?(?:\\.[`\"\\[]?[A-Za-z_][\\w$]*[`\"\\]]?)?)")
+	ctePattern   = regexp.MustCompile(`(?i)(?:\bwith(?:\s+recursive)?|,)\s+([A-Za-z_]\w*)\s+as\s*\(`)
+)
+
+// tableKeywords follow a table keyword without naming a table, as in
+// "ON CONFLICT DO UPDATE SET".
+var tableKeywords = map[string]bool{
+	"SET": true, "SELECT": true, "LATERAL": true, "UNNEST": true, "VALUES": true,
+}
+
+// parseStatement returns the statement keyword of query, upper-cased, and
+// the tables it names. ok is false when query is not a SQL statement.
+func parseStatement(query string) (operation string, tables []string, ok bool) {
+	trimmed := strings.TrimLeft(query, " \t\r\n(")
+	end := strings.IndexFunc(trimmed, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z')
+	})
+	if end < 0 {
+		end = len(trimmed)
+	}
+	operation = strings.ToUpper(trimmed[:end])
+	if !statementKeywords[operation] {
+		return "", nil, false
+	}
+	// A statement keyword alone, such as a "delete" confirmation prompt, is
+	// not a query.
+	if end == len(trimmed) {
+		return "", nil, false
+	}
+
+	ctes := map[string]bool{}
+	for _, match := range ctePattern.FindAllStringSubmatch(query, -1) {
+		ctes[strings.ToLower(match[1])] = true
+	}
+	seen := map[string]bool{}
+	for _, match := range tablePattern.FindAllStringSubmatch(query, -1) {
+		table := strings.NewReplacer("`", "", `"`, "", "[", "", "]", "").Replace(match[1])
+		if tableKeywords[strings.ToUpper(table)] || ctes[strings.ToLower(table)] || seen[table] {
+			continue
+		}
+		seen[table] = true
+		tables = append(tables, table)
+	}
+	return operation, tables, true
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
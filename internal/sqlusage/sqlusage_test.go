@@ -0,0 +1,141 @@
+package sqlusage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+func TestBuild(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "store/users.go", `package store
+
+const listUsers = `+"`"+`
+	SELECT u.id, o.total
+	FROM users u
+	JOIN orders o ON o.user_id = u.id`+"`"+`
+
+type Store struct{}
+
+func (s *Store) List(ctx context.Context) {
+	s.db.QueryContext(ctx, listUsers)
+}
+
+func Rename(id int, name string) {
+	db.Exec("UPDATE users SET name = $1 "+"WHERE id = $2", name, id)
+	r.Get("/users", handler)
+}
+`)
+	writeFile(t, root, "web/db.js", `async function recent(pool) {
+  return pool.query(`+"`SELECT * FROM events WHERE day = ${day}`"+`);
+}
+`)
+	writeFile(t, root, "jobs/cleanup.py", `from sqlalchemy import text
+
+STALE = "DELETE FROM sessions WHERE expires < now()"
+
+def cleanup(cur):
+    cur.execute(STALE)
+    cur.execute(text("WITH old AS (SELECT id FROM audit) INSERT INTO archive SELECT * FROM old"))
+`)
+	writeFile(t, root, "java/Repo.java", `interface Repo {
+    @Query("SELECT u FROM User u")
+    List<User> all();
+
+    @Query(value = "UPDATE accounts SET active = false", nativeQuery = true)
+    void deactivate();
+}
+`)
+
+	idx, err := index.NewBuilder().BuildPath(root)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	graph, err := xref.Build(idx)
+	if err != nil {
+		t.Fatalf("xref.Build returned error: %v", err)
+	}
+	report, err := Build(idx, &graph)
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	got := make([]string, 0, len(report.Usages))
+	for _, usage := range report.Usages {
+		line := usage.File + " " + usage.Call + " " + usage.Operation + " " + strings.Join(usage.Tables, ",")
+		if usage.Caller != nil {
+			line += " <- " + CallerLabel(*usage.Caller)
+		}
+		got = append(got, line)
+	}
+	want := []string{
+		"java/Repo.java @Query SELECT User <- java.all",
+		"java/Repo.java @Query UPDATE accounts <- java.deactivate",
+		"jobs/cleanup.py cur.execute DELETE sessions <- jobs.cleanup",
+		"jobs/cleanup.py text WITH audit,archive <- jobs.cleanup",
+		"store/users.go s.db.QueryContext SELECT users,orders <- store.Store.List",
+		"store/users.go db.Exec UPDATE users <- store.Rename",
+		"web/db.js pool.query SELECT events <- web.recent",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected usages:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if query := report.Usages[5].Query; query != "UPDATE users SET name = $1 WHERE id = $2" {
+		t.Fatalf("unexpected concatenated query %q", query)
+	}
+	if query := report.Usages[6].Query; query != "SELECT * FROM events WHERE day = ?" {
+		t.Fatalf("unexpected template query %q", query)
+	}
+
+	var users *TableUsage
+	for i := range report.Tables {
+		if report.Tables[i].Table == "users" {
+			users = &report.Tables[i]
+		}
+	}
+	if users == nil {
+		t.Fatalf("expected a users table in %+v", report.Tables)
+	}
+	if !reflect.DeepEqual(users.Operations, []string{"SELECT", "UPDATE"}) || users.Queries != 2 ||
+		!reflect.DeepEqual(users.Callers, []string{"store.Rename", "store.Store.List"}) {
+		t.Fatalf("unexpected users summary %+v", *users)
+	}
+}
+
+func TestParseStatement(t *testing.T) {
+	tests := []struct {
+		query     string
+		operation string
+		tables    []string
+		ok        bool
+	}{
+		{"select * from public.users where id = 1", "SELECT", []string{"public.users"}, true},
+		{`INSERT INTO "order_items" (id) VALUES (1) ON CONFLICT (id) DO UPDATE SET id = 2`, "INSERT", []string{"order_items"}, true},
+		{"CREATE TABLE IF NOT EXISTS [logs] (id int)", "CREATE", []string{"logs"}, true},
+		{"  (SELECT a FROM x) UNION (SELECT a FROM y)", "SELECT", []string{"x", "y"}, true},
+		{"delete", "", nil, false},
+		{"/users/{id}", "", nil, false},
+	}
+	for _, tt := range tests {
+		operation, tables, ok := parseStatement(tt.query)
+		if operation != tt.operation || ok != tt.ok || !reflect.DeepEqual(tables, tt.tables) {
+			t.Errorf("parseStatement(%q) = %q, %v, %v; want %q, %v, %v", tt.query, operation, tables, ok, tt.operation, tt.tables, tt.ok)
+		}
+	}
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
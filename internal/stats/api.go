@@ -0,0 +1,201 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/testmap"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// DefaultMaxExported is the public surface above which a package is flagged.
+const DefaultMaxExported = 50
+
+type APIOptions struct {
+	MaxExported int // flag packages exporting more symbols than this
+}
+
+// PackageAPI counts the exported functions, types, and methods of a package.
+type PackageAPI struct {
+	Package   string `json:"package"`
+	Functions int    `json:"functions"`
+	Types     int    `json:"types"`
+	Methods   int    `json:"methods"`
+	Exported  int    `json:"exported"`
+	Large     bool   `json:"large,omitempty"`
+}
+
+// InternalSymbol is an exported symbol referenced only from its own
+// package, a candidate for unexporting.
+type InternalSymbol struct {
+	File       string `json:"file"`
+	Package    string `json:"package"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	StartLine  int    `json:"start_line"`
+	References int    `json:"references"`
+}
+
+type APIReport struct {
+	Root         string           `json:"root"`
+	MaxExported  int              `json:"max_exported"`
+	Packages     []PackageAPI     `json:"packages"`
+	LargeCount   int              `json:"large_count"`
+	InternalOnly []InternalSymbol `json:"internal_only,omitempty"`
+}
+
+// BuildAPI reports the public API surface of each package, ignoring symbols
+// declared in test files. Calls to exported
+// functions and methods are resolved through the cross-reference graph;
+// references to exported types are matched by name, so a type is only listed
+// as internal when no file outside its package mentions that name.
+func BuildAPI(idx *model.Index, opts APIOptions) (APIReport, error) {
+	if idx == nil {
+		return APIReport{}, fmt.Errorf("index is nil")
+	}
+	if opts.MaxExported <= 0 {
+		opts.MaxExported = DefaultMaxExported
+	}
+
+	graph, err := xref.Build(idx)
+	if err != nil {
+		return APIReport{}, err
+	}
+
+	// Packages referring to each name through anything but a field access.
+	type nameUse struct {
+		packages map[string]bool
+		count    int
+	}
+	uses := map[string]*nameUse{}
+	testFiles := map[string]bool{}
+	for _, file := range idx.Files {
+		testFiles[file.Path] = testmap.IsTestFile(file.Path, file.Language)
+		pkg := xref.PackageOf(file.Path)
+		for _, reference := range file.References {
+			if reference.Kind == "reference.field" {
+				continue
+			}
+			use, ok := uses[reference.Name]
+			if !ok {
+				use = &nameUse{packages: map[string]bool{}}
+				uses[reference.Name] = use
+			}
+			use.packages[pkg] = true
+			use.count++
+		}
+	}
+
+	packages := map[string]*PackageAPI{}
+	var internal []InternalSymbol
+	declared := declaredCallables(graph.Definitions)
+	for _, definition := range graph.Definitions {
+		if !definition.Exported || testFiles[definition.File] || (definition.Callable && !declared[definition.ID]) {
+			continue
+		}
+		api, ok := packages[definition.Package]
+		if !ok {
+			api = &PackageAPI{Package: definition.Package}
+		}
+		switch {
+		case model.IsFunctionKind(definition.Kind):
+			api.Functions++
+		case definition.Kind == "method_definition":
+			api.Methods++
+		case model.IsContainerKind(definition.Kind) && definition.Kind != "module_definition":
+			api.Types++
+		default:
+			continue
+		}
+		packages[definition.Package] = api
+		api.Exported++
+
+		references := 0
+		ownOnly := true
+		if definition.Callable {
+			for _, edge := range graph.IncomingEdges(definition.ID) {
+				references += edge.Count
+				if graph.EdgeCaller(edge).Package != definition.Package {
+					ownOnly = false
+				}
+			}
+		} else if use := uses[definition.Name]; use != nil {
+			references = use.count
+			for pkg := range use.packages {
+				if pkg != definition.Package {
+					ownOnly = false
+				}
+			}
+		}
+		if references > 0 && ownOnly {
+			internal = append(internal, InternalSymbol{
+				File:       definition.File,
+				Package:    definition.Package,
+				Kind:       definition.Kind,
+				Name:       definition.Name,
+				StartLine:  definition.StartLine,
+				References: references,
+			})
+		}
+	}
+
+	report := APIReport{
+		Root:         idx.Root,
+		MaxExported:  opts.MaxExported,
+		Packages:     make([]PackageAPI, 0, len(packages)),
+		InternalOnly: internal,
+	}
+	for _, api := range packages {
+		api.Large = api.Exported > opts.MaxExported
+		if api.Large {
+			report.LargeCount++
+		}
+		report.Packages = append(report.Packages, *api)
+	}
+	sort.Slice(report.Packages, func(i, j int) bool {
+		if report.Packages[i].Exported == report.Packages[j].Exported {
+			return report.Packages[i].Package < report.Packages[j].Package
+		}
+		return report.Packages[i].Exported > report.Packages[j].Exported
+	})
+	sort.Slice(report.InternalOnly, func(i, j int) bool {
+		if report.InternalOnly[i].File == report.InternalOnly[j].File {
+			return report.InternalOnly[i].StartLine < report.InternalOnly[j].StartLine
+		}
+		return report.InternalOnly[i].File < report.InternalOnly[j].File
+	})
+	return report, nil
+}
+
+// declaredCallables returns the IDs of callables that name their declaration.
+// Tags queries can also capture a Go function's result type as a callable
+// with the same signature, as in "func Make() Server"; of the callables
+// sharing a line and signature, the one named first in it is the real one.
+func declaredCallables(definitions []xref.Definition) map[string]bool {
+	best := map[string]xref.Definition{}
+	for _, definition := range definitions {
+		if !definition.Callable {
+			continue
+		}
+		key := definition.File + "\x00" + strconv.Itoa(definition.StartLine) + "\x00" + definition.Signature
+		current, ok := best[key]
+		if !ok || namePosition(definition) < namePosition(current) {
+			best[key] = definition
+		}
+	}
+	declared := make(map[string]bool, len(best))
+	for _, definition := range best {
+		declared[definition.ID] = true
+	}
+	return declared
+}
+
+func namePosition(definition xref.Definition) int {
+	if i := strings.Index(definition.Signature, definition.Name); i >= 0 {
+		return i
+	}
+	return len(definition.Signature)
+}
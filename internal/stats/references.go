@@ -0,0 +1,140 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// ReferenceStats counts the references of an index by kind.
+type ReferenceStats struct {
+	Total int         `json:"total"`
+	Kinds []KindCount `json:"kinds,omitempty"`
+}
+
+// CallStats measures how much of the call graph can be trusted: how many
+// call references xref resolved to a definition, how, and why the rest were
+// not.
+type CallStats struct {
+	// Total counts call references; Resolved and Unresolved split it.
+	Total         int     `json:"total"`
+	Resolved      int     `json:"resolved"`
+	Unresolved    int     `json:"unresolved"`
+	ResolvedRatio float64 `json:"resolved_ratio"`
+	// External counts the unresolved calls into packages outside the index,
+	// which no index could resolve. LocalResolvedRatio leaves them out.
+	External           int     `json:"external"`
+	LocalResolvedRatio float64 `json:"local_resolved_ratio"`
+	// Resolutions counts call graph edges by how their callee was found,
+	// such as "package" or "import". A call edge counts each of its call
+	// sites, and a polymorphic call counts once per candidate.
+	Resolutions []ReasonCount `json:"resolutions,omitempty"`
+	// Reasons counts unresolved calls by why they were not resolved, such as
+	// "not_found" or "ambiguous_package".
+	Reasons []ReasonCount `json:"reasons,omitempty"`
+}
+
+// ReasonCount is the number of calls resolved, or left unresolved, for one
+// reason. Candidates totals the definitions ambiguous calls matched.
+type ReasonCount struct {
+	Reason     string `json:"reason"`
+	Count      int    `json:"count"`
+	Candidates int    `json:"candidates,omitempty"`
+}
+
+// buildReferenceStats counts the references of idx by kind.
+func buildReferenceStats(idx *model.Index) ReferenceStats {
+	kinds := map[string]int{}
+	stats := ReferenceStats{}
+	for _, file := range idx.Files {
+		for _, ref := range file.References {
+			kinds[ref.Kind]++
+			stats.Total++
+		}
+	}
+	stats.Kinds = sortedKindCounts(kinds)
+	return stats
+}
+
+// buildCallStats resolves the calls of idx as xref does and tallies the
+// outcome.
+func buildCallStats(idx *model.Index) (CallStats, error) {
+	graph, err := xref.Build(idx)
+	if err != nil {
+		return CallStats{}, err
+	}
+	stats := CallStats{}
+	for _, file := range idx.Files {
+		for _, ref := range file.References {
+			if strings.HasPrefix(strings.TrimSpace(ref.Kind), "reference.call") {
+				stats.Total++
+			}
+		}
+	}
+
+	resolutions := map[string]*ReasonCount{}
+	for _, edge := range graph.Edges {
+		tally(resolutions, edge.Resolution, edge.Count, 0)
+	}
+	reasons := map[string]*ReasonCount{}
+	for _, call := range graph.Unresolved {
+		tally(reasons, call.Reason, 1, call.CandidateCount)
+		if call.Reason == "external_package" {
+			stats.External++
+		}
+	}
+	stats.Unresolved = len(graph.Unresolved)
+	stats.Resolved = max(stats.Total-stats.Unresolved, 0)
+	if stats.Total > 0 {
+		stats.ResolvedRatio = float64(stats.Resolved) / float64(stats.Total)
+	}
+	if local := stats.Total - stats.External; local > 0 {
+		stats.LocalResolvedRatio = float64(stats.Resolved) / float64(local)
+	}
+	stats.Resolutions = sortedReasonCounts(resolutions)
+	stats.Reasons = sortedReasonCounts(reasons)
+	return stats, nil
+}
+
+func tally(counts map[string]*ReasonCount, reason string, count, candidates int) {
+	if reason == "" {
+		reason = "unknown"
+	}
+	entry, ok := counts[reason]
+	if !ok {
+		entry = &ReasonCount{Reason: reason}
+		counts[reason] = entry
+	}
+	entry.Count += count
+	entry.Candidates += candidates
+}
+
+func sortedReasonCounts(counts map[string]*ReasonCount) []ReasonCount {
+	list := make([]ReasonCount, 0, len(counts))
+	for _, entry := range counts {
+		list = append(list, *entry)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count == list[j].Count {
+			return list[i].Reason < list[j].Reason
+		}
+		return list[i].Count > list[j].Count
+	})
+	return list
+}
+
+func sortedKindCounts(counts map[string]int) []KindCount {
+	list := make([]KindCount, 0, len(counts))
+	for kind, count := range counts {
+		list = append(list, KindCount{Kind: kind, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count == list[j].Count {
+			return list[i].Kind < list[j].Kind
+		}
+		return list[i].Count > list[j].Count
+	})
+	return list
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/odvcencio/gts-suite/pkg/model"
 )
 
 type Options struct {
 	TopFiles int
+	// Calls resolves the index's calls with xref to report CallStats.
+	Calls bool
 }
 
 type KindCount struct {
@@ -48,6 +51,12 @@ type Report struct {
 	Languages          []LanguageCount  `json:"languages,omitempty"`
 	Generators         []GeneratorCount `json:"generators,omitempty"`
 	TopFiles           []FileMetric     `json:"top_files,omitempty"`
+	References         ReferenceStats   `json:"references"`
+	GeneratedAt        time.Time        `json:"generated_at"`
+	// Calls is set when Options.Calls asks for it.
+	Calls *CallStats `json:"calls,omitempty"`
+	// Provenance is how the index was built, when it records that.
+	Provenance *model.Provenance `json:"provenance,omitempty"`
 }
 
 func Build(idx *model.Index, opts Options) (Report, error) {
@@ -107,16 +116,7 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 		})
 	}
 
-	kindList := make([]KindCount, 0, len(kindCounts))
-	for kind, count := range kindCounts {
-		kindList = append(kindList, KindCount{Kind: kind, Count: count})
-	}
-	sort.Slice(kindList, func(i, j int) bool {
-		if kindList[i].Count == kindList[j].Count {
-			return kindList[i].Kind < kindList[j].Kind
-		}
-		return kindList[i].Count > kindList[j].Count
-	})
+	kindList := sortedKindCounts(kindCounts)
 
 	languageList := make([]LanguageCount, 0, len(languages))
 	for lang, aggregate := range languages {
@@ -164,6 +164,16 @@ func Build(idx *model.Index, opts Options) (Report, error) {
 		Languages:          languageList,
 		Generators:         generatorList,
 		TopFiles:           fileMetrics,
+		References:         buildReferenceStats(idx),
+		GeneratedAt:        idx.GeneratedAt,
+		Provenance:         idx.Provenance,
+	}
+	if opts.Calls {
+		calls, err := buildCallStats(idx)
+		if err != nil {
+			return Report{}, err
+		}
+		report.Calls = &calls
 	}
 	return report, nil
 }
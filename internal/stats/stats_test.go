@@ -1,8 +1,11 @@
 package stats
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/odvcencio/gts-suite/pkg/index"
 	"github.com/odvcencio/gts-suite/pkg/model"
 )
 
@@ -135,3 +138,121 @@ func TestBuildNilIndex(t *testing.T) {
 		t.Fatal("expected nil index to fail")
 	}
 }
+
+func TestBuildAPI(t *testing.T) {
+	idx := &model.Index{
+		Files: []model.FileSummary{
+			{
+				Path:     "lib/lib.go",
+				Language: "go",
+				Symbols: []model.Symbol{
+					{Kind: "type_definition", Name: "Config", Exported: true, StartLine: 3, EndLine: 3},
+					{Kind: "type_definition", Name: "Shared", Exported: true, StartLine: 5, EndLine: 5},
+					{Kind: "function_definition", Name: "Public", Signature: "func Public() Shared", Exported: true, StartLine: 7, EndLine: 9},
+					{Kind: "function_definition", Name: "Shared", Signature: "func Public() Shared", Exported: true, StartLine: 7, EndLine: 9},
+					{Kind: "function_definition", Name: "Helper", Signature: "func Helper()", Exported: true, StartLine: 11, EndLine: 13},
+					{Kind: "function_definition", Name: "private", Signature: "func private()", StartLine: 15, EndLine: 15},
+				},
+				References: []model.Reference{
+					{Kind: "reference.call", Name: "Helper", StartLine: 8},
+					{Kind: "reference.type", Name: "Config", StartLine: 12},
+				},
+			},
+			{
+				Path:     "lib/lib_test.go",
+				Language: "go",
+				Symbols: []model.Symbol{
+					{Kind: "function_definition", Name: "TestHelper", Signature: "func TestHelper(t *testing.T)", Exported: true, StartLine: 3, EndLine: 3},
+				},
+			},
+			{
+				Path:     "app/main.go",
+				Language: "go",
+				Imports:  []string{"example.com/lib"},
+				Symbols: []model.Symbol{
+					{Kind: "function_definition", Name: "main", Signature: "func main()", StartLine: 3, EndLine: 6},
+				},
+				References: []model.Reference{
+					{Kind: "reference.call", Name: "Public", Qualifier: "lib", StartLine: 4},
+					{Kind: "reference.type", Name: "Shared", Qualifier: "lib", StartLine: 5},
+				},
+			},
+		},
+	}
+
+	report, err := BuildAPI(idx, APIOptions{MaxExported: 3})
+	if err != nil {
+		t.Fatalf("BuildAPI returned error: %v", err)
+	}
+	if len(report.Packages) != 1 {
+		t.Fatalf("expected only lib to export symbols, got %+v", report.Packages)
+	}
+	lib := report.Packages[0]
+	if lib.Package != "lib" || lib.Functions != 2 || lib.Types != 2 || lib.Exported != 4 || !lib.Large || report.LargeCount != 1 {
+		t.Fatalf("unexpected lib surface: %+v (large=%d)", lib, report.LargeCount)
+	}
+
+	var internal []string
+	for _, symbol := range report.InternalOnly {
+		internal = append(internal, symbol.Name)
+	}
+	if len(internal) != 2 || internal[0] != "Config" || internal[1] != "Helper" {
+		t.Fatalf("unexpected internal-only symbols %v", internal)
+	}
+}
+
+func TestBuildReferenceAndCallStats(t *testing.T) {
+	dir := t.TempDir()
+	source := `package sample
+
+import "fmt"
+
+func A() {}
+
+func Use() {
+	A()
+	missing()
+	fmt.Println()
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(dir)
+	if err != nil {
+		t.Fatalf("BuildPath failed: %v", err)
+	}
+
+	report, err := Build(idx, Options{})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if report.Calls != nil {
+		t.Fatalf("expected no call stats without Options.Calls, got %+v", report.Calls)
+	}
+	if report.References.Total != 3 || len(report.References.Kinds) != 1 || report.References.Kinds[0] != (KindCount{Kind: "reference.call", Count: 3}) {
+		t.Fatalf("unexpected reference stats: %+v", report.References)
+	}
+
+	report, err = Build(idx, Options{Calls: true})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	calls := report.Calls
+	if calls == nil || calls.Total != 3 || calls.Resolved != 1 || calls.Unresolved != 2 || calls.External != 1 {
+		t.Fatalf("unexpected call stats: %+v", calls)
+	}
+	if calls.LocalResolvedRatio != 0.5 {
+		t.Fatalf("local resolved ratio = %v, want 0.5", calls.LocalResolvedRatio)
+	}
+	reasons := map[string]int{}
+	for _, reason := range calls.Reasons {
+		reasons[reason.Reason] = reason.Count
+	}
+	if reasons["not_found"] != 1 || reasons["external_package"] != 1 {
+		t.Fatalf("unexpected unresolved reasons: %+v", calls.Reasons)
+	}
+	if len(calls.Resolutions) != 1 || calls.Resolutions[0].Count != 1 {
+		t.Fatalf("unexpected resolutions: %+v", calls.Resolutions)
+	}
+}
@@ -0,0 +1,108 @@
+// Package walkfilter narrows call graph walks to the definitions worth
+// reading: it drops test code, code outside the project such as vendored
+// dependencies and generated files, and packages matching exclusion globs,
+// so helpers and third-party code do not swamp the callees of a real root.
+package walkfilter
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/testmap"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// Options selects the definitions a walk skips.
+type Options struct {
+	// ExcludeTests skips definitions in test files.
+	ExcludeTests bool
+	// OnlyProject skips definitions in generated files and under dependency
+	// directories such as vendor and node_modules.
+	OnlyProject bool
+	// ExcludePackages are globs matched against definition packages, the
+	// slash-separated directory of their file. A trailing "/..." also
+	// matches every package below, as in "internal/testutil/...".
+	ExcludePackages []string
+}
+
+// Keep returns the filter for xref.Graph.WalkFiltered over a graph built
+// from idx, or nil when opts skip nothing.
+func (opts Options) Keep(idx *model.Index) (func(xref.Definition) bool, error) {
+	if !opts.ExcludeTests && !opts.OnlyProject && len(opts.ExcludePackages) == 0 {
+		return nil, nil
+	}
+	var patterns []string
+	for _, raw := range opts.ExcludePackages {
+		pattern := strings.TrimPrefix(strings.TrimSpace(raw), "./")
+		if dir, ok := strings.CutSuffix(pattern, "/..."); ok {
+			patterns = append(patterns, dir)
+			pattern = dir + "/**"
+		}
+		if pattern == "" {
+			return nil, fmt.Errorf("empty package pattern %q", raw)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid package pattern %q: %w", raw, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	files := make(map[string]*model.FileSummary, len(idx.Files))
+	for i := range idx.Files {
+		files[idx.Files[i].Path] = &idx.Files[i]
+	}
+	return func(def xref.Definition) bool {
+		file := files[def.File]
+		if opts.ExcludeTests {
+			language := ""
+			if file != nil {
+				language = file.Language
+			}
+			if testmap.IsTestFile(def.File, language) {
+				return false
+			}
+		}
+		if opts.OnlyProject {
+			if file != nil && file.Generated != nil {
+				return false
+			}
+			if inDependencyDir(def.File) {
+				return false
+			}
+		}
+		for _, pattern := range patterns {
+			if matchPackage(pattern, def.Package) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// inDependencyDir reports whether p lies under a directory the indexer skips
+// by default, or under third_party, where dependencies are conventionally
+// copied in.
+func inDependencyDir(p string) bool {
+	skip := index.DefaultSkipDirs()
+	segments := strings.Split(p, "/")
+	for _, dir := range segments[:len(segments)-1] {
+		if skip[dir] || dir == "third_party" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPackage matches a package path against pattern. Unlike
+// model.MatchGlob, a pattern without a slash matches the whole package path,
+// so "testutil" does not match "internal/testutil".
+func matchPackage(pattern, pkg string) bool {
+	if strings.Contains(pattern, "**") {
+		return model.MatchGlob(pattern, pkg)
+	}
+	matched, _ := path.Match(pattern, pkg)
+	return matched
+}
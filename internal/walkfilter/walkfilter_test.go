@@ -0,0 +1,69 @@
+package walkfilter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+func TestKeepFiltersWalk(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"app/app.go":             "package app\n\nfunc Serve() {\n\thelper()\n\tMustSetup()\n\tVendored()\n\tShared()\n}\n\nfunc helper() {\n\tdeep()\n}\n\nfunc deep() {}\n",
+		"app/app_test.go":        "package app\n\nfunc MustSetup() {}\n",
+		"internal/testutil/u.go": "package testutil\n\nfunc Shared() {}\n",
+		"third_party/lib/lib.go": "package lib\n\nfunc Vendored() {}\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx, err := index.NewBuilder().BuildPath(root)
+	if err != nil {
+		t.Fatalf("BuildPath: %v", err)
+	}
+	graph, err := xref.Build(idx)
+	if err != nil {
+		t.Fatalf("xref.Build: %v", err)
+	}
+	roots, err := graph.FindDefinitions("Serve", false)
+	if err != nil || len(roots) != 1 {
+		t.Fatalf("FindDefinitions: %v %v", roots, err)
+	}
+
+	walked := func(opts Options) []string {
+		keep, err := opts.Keep(idx)
+		if err != nil {
+			t.Fatalf("Keep: %v", err)
+		}
+		walk := graph.WalkFiltered([]string{roots[0].ID}, 3, false, keep)
+		var names []string
+		for _, node := range walk.Nodes {
+			names = append(names, node.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	if got := walked(Options{}); !reflect.DeepEqual(got, []string{"MustSetup", "Serve", "Shared", "Vendored", "deep", "helper"}) {
+		t.Fatalf("unfiltered walk = %v", got)
+	}
+	got := walked(Options{ExcludeTests: true, OnlyProject: true, ExcludePackages: []string{"internal/..."}})
+	if !reflect.DeepEqual(got, []string{"Serve", "deep", "helper"}) {
+		t.Fatalf("filtered walk = %v", got)
+	}
+
+	if _, err := (Options{ExcludePackages: []string{"["}}).Keep(idx); err == nil {
+		t.Fatal("expected an invalid pattern error")
+	}
+}
@@ -0,0 +1,266 @@
+// Package yamlsubset parses the small subset of YAML that gts project files
+// use: .gts/config.yaml, .gts/queries.yaml, and the lint.yaml of rule packs.
+//
+// A document is a mapping of keys to values. A value is a scalar, a list of
+// scalars written as "- item" lines or as a "[a, b]" flow list, or a nested
+// mapping indented under its key:
+//
+//	# Comments start with "#".
+//	name: plain value # trailing comment
+//	quoted: "double \"quoted\"" or 'single ''quoted'''
+//	list:
+//	  - one
+//	  - two
+//	flow: [one, "two"]
+//	nested:
+//	  key: value
+//
+// Indentation uses spaces; tabs are rejected.
+package yamlsubset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is a parsed value: a scalar, a list of scalars, or a mapping.
+type Node struct {
+	// Line is the 1-based line the value starts on.
+	Line   int
+	Scalar string
+	List   []string
+	IsList bool
+	// Keys are the mapping's keys in file order.
+	Keys   []string
+	Fields map[string]*Node
+}
+
+// IsMap reports whether n is a mapping.
+func (n *Node) IsMap() bool {
+	return n.Fields != nil
+}
+
+// Value returns the scalar n holds, reporting an error naming key when n is
+// a list or a mapping.
+func (n *Node) Value(key string) (string, error) {
+	if n.IsList || n.IsMap() {
+		return "", fmt.Errorf("line %d: %s must be a single value", n.Line, key)
+	}
+	return n.Scalar, nil
+}
+
+// Values returns the list n holds, or its scalar as a one-item list,
+// reporting an error naming key when n is a mapping.
+func (n *Node) Values(key string) ([]string, error) {
+	switch {
+	case n.IsMap():
+		return nil, fmt.Errorf("line %d: %s must be a value or a list", n.Line, key)
+	case n.IsList:
+		return n.List, nil
+	}
+	return []string{n.Scalar}, nil
+}
+
+// line is a non-blank, non-comment line of a document.
+type line struct {
+	no     int
+	indent int
+	text   string
+}
+
+// Parse parses content into its top-level mapping, which is empty when
+// content has no entries.
+func Parse(content string) (*Node, error) {
+	lines, err := splitLines(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return &Node{Line: 1, Fields: map[string]*Node{}}, nil
+	}
+	if lines[0].indent != 0 {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[0].no)
+	}
+	doc, next, err := parseBlock(lines, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if next < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[next].no)
+	}
+	if !doc.IsMap() {
+		return nil, fmt.Errorf("line %d: expected \"key: value\" entries", lines[0].no)
+	}
+	return doc, nil
+}
+
+func splitLines(content string) ([]line, error) {
+	var out []line
+	for i, raw := range strings.Split(content, "\n") {
+		raw = strings.TrimRight(raw, " \t\r")
+		trimmed := strings.TrimLeft(raw, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: indent with spaces, not tabs", i+1)
+		}
+		out = append(out, line{no: i + 1, indent: len(raw) - len(trimmed), text: trimmed})
+	}
+	return out, nil
+}
+
+// parseBlock parses the lines at indent starting at lines[i] as one list or
+// mapping, returning it and the index of the first line after it.
+func parseBlock(lines []line, i, indent int) (*Node, int, error) {
+	block := &Node{Line: lines[i].no}
+	if isListItem(lines[i].text) {
+		block.IsList = true
+		for ; i < len(lines) && lines[i].indent >= indent; i++ {
+			if lines[i].indent > indent || !isListItem(lines[i].text) {
+				return nil, 0, fmt.Errorf("line %d: expected a \"- item\" list entry", lines[i].no)
+			}
+			value, err := scalar(strings.TrimSpace(strings.TrimPrefix(lines[i].text, "-")))
+			if err != nil {
+				return nil, 0, fmt.Errorf("line %d: %w", lines[i].no, err)
+			}
+			block.List = append(block.List, value)
+		}
+		return block, i, nil
+	}
+
+	block.Fields = map[string]*Node{}
+	for i < len(lines) && lines[i].indent >= indent {
+		l := lines[i]
+		if l.indent > indent {
+			return nil, 0, fmt.Errorf("line %d: unexpected indentation", l.no)
+		}
+		key, rest, err := splitEntry(l.text)
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", l.no, err)
+		}
+		if _, dup := block.Fields[key]; dup {
+			return nil, 0, fmt.Errorf("line %d: duplicate key %q", l.no, key)
+		}
+		i++
+
+		var value *Node
+		if rest == "" && i < len(lines) && lines[i].indent > indent {
+			value, i, err = parseBlock(lines, i, lines[i].indent)
+		} else {
+			value, err = inlineValue(l.no, rest)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		block.Keys = append(block.Keys, key)
+		block.Fields[key] = value
+	}
+	return block, i, nil
+}
+
+func isListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitEntry splits "key: value" into the key and the raw value, which is
+// empty when only a comment follows the colon.
+func splitEntry(text string) (string, string, error) {
+	colon := strings.Index(text, ":")
+	if colon <= 0 {
+		return "", "", fmt.Errorf("expected \"key: value\", got %q", text)
+	}
+	key := strings.TrimSpace(text[:colon])
+	rest := text[colon+1:]
+	if rest != "" && rest[0] != ' ' {
+		return "", "", fmt.Errorf("expected a space after %q", key+":")
+	}
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "#") {
+		rest = ""
+	}
+	return key, rest, nil
+}
+
+// inlineValue decodes a value written on its key's line: a scalar or a
+// "[a, b]" flow list.
+func inlineValue(no int, raw string) (*Node, error) {
+	if !strings.HasPrefix(raw, "[") {
+		value, err := scalar(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", no, err)
+		}
+		return &Node{Line: no, Scalar: value}, nil
+	}
+
+	list := &Node{Line: no, IsList: true}
+	rest := strings.TrimSpace(raw[1:])
+	for !strings.HasPrefix(rest, "]") {
+		item := rest
+		if strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "'") {
+			end := closingQuote(rest, rest[0])
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated string %s", no, rest)
+			}
+			item = rest[:end+1]
+		} else if end := strings.IndexAny(rest, ",]"); end >= 0 {
+			item = rest[:end]
+		}
+		value, err := scalar(strings.TrimSpace(item))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", no, err)
+		}
+		list.List = append(list.List, value)
+		rest = strings.TrimSpace(rest[len(item):])
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+		} else if !strings.HasPrefix(rest, "]") {
+			return nil, fmt.Errorf("line %d: unterminated list %s", no, raw)
+		}
+	}
+	if tail := strings.TrimSpace(rest[1:]); tail != "" && !strings.HasPrefix(tail, "#") {
+		return nil, fmt.Errorf("line %d: unexpected %q after list", no, tail)
+	}
+	return list, nil
+}
+
+// scalar decodes a plain, 'single-quoted', or "double-quoted" scalar. Plain
+// scalars end at a " #" comment.
+func scalar(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		end := closingQuote(raw, '"')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", raw)
+		}
+		return strconv.Unquote(raw[:end+1])
+	case strings.HasPrefix(raw, "'"):
+		end := closingQuote(raw, '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", raw)
+		}
+		return strings.ReplaceAll(raw[1:end], "''", "'"), nil
+	}
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = raw[:i]
+	}
+	return strings.TrimSpace(raw), nil
+}
+
+// closingQuote returns the index of the quote that ends the string starting
+// at raw[0], honoring \" escapes in double-quoted strings and doubled quotes
+// in single-quoted ones.
+func closingQuote(raw string, quote byte) int {
+	for i := 1; i < len(raw); i++ {
+		switch {
+		case quote == '"' && raw[i] == '\\':
+			i++
+		case raw[i] == quote && quote == '\'' && i+1 < len(raw) && raw[i+1] == '\'':
+			i++
+		case raw[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
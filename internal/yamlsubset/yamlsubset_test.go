@@ -0,0 +1,105 @@
+package yamlsubset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `# project settings
+name: plain value # trailing comment
+double: "say \"hi\""
+single: 'it''s'
+empty: # nothing here
+list:
+  - one
+  - 'two # not a comment'
+flow: [one, "two, three", 'four']
+nested:
+  key: value
+  inner:
+    - x
+`
+	doc, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	wantKeys := []string{"name", "double", "single", "empty", "list", "flow", "nested"}
+	if !reflect.DeepEqual(doc.Keys, wantKeys) {
+		t.Fatalf("Keys = %v, want %v", doc.Keys, wantKeys)
+	}
+
+	scalars := map[string]string{
+		"name":   "plain value",
+		"double": `say "hi"`,
+		"single": "it's",
+		"empty":  "",
+	}
+	for key, want := range scalars {
+		got, err := doc.Fields[key].Value(key)
+		if err != nil || got != want {
+			t.Errorf("Value(%s) = %q, %v; want %q", key, got, err, want)
+		}
+	}
+
+	lists := map[string][]string{
+		"list": {"one", "two # not a comment"},
+		"flow": {"one", "two, three", "four"},
+	}
+	for key, want := range lists {
+		got, err := doc.Fields[key].Values(key)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Values(%s) = %q, %v; want %q", key, got, err, want)
+		}
+	}
+	if got, err := doc.Fields["name"].Values("name"); err != nil || !reflect.DeepEqual(got, []string{"plain value"}) {
+		t.Errorf("Values(name) = %q, %v", got, err)
+	}
+
+	nested := doc.Fields["nested"]
+	if !nested.IsMap() || nested.Line != 11 {
+		t.Fatalf("nested = %+v, want a mapping on line 11", nested)
+	}
+	if got, _ := nested.Fields["key"].Value("key"); got != "value" {
+		t.Errorf("nested.key = %q", got)
+	}
+	if got, _ := nested.Fields["inner"].Values("inner"); !reflect.DeepEqual(got, []string{"x"}) {
+		t.Errorf("nested.inner = %q", got)
+	}
+	if _, err := nested.Value("nested"); err == nil {
+		t.Error("Value on a mapping should fail")
+	}
+	if _, err := doc.Fields["list"].Value("list"); err == nil {
+		t.Error("Value on a list should fail")
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	doc, err := Parse("# only comments\n\n")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !doc.IsMap() || len(doc.Keys) != 0 {
+		t.Fatalf("doc = %+v, want an empty mapping", doc)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"tab indent":        "a:\n\tb: c\n",
+		"indented start":    "  a: b\n",
+		"stray indentation": "a: b\n  c: d\n",
+		"duplicate":         "a: x\na: y\n",
+		"unterminated":      "a: 'x\n",
+		"unterminated flow": "a: [x, y\n",
+		"top-level list":    "- a\n- b\n",
+		"missing colon":     "just text\n",
+		"no space":          "a:b\n",
+		"mixed list":        "a:\n  - x\n  y: z\n",
+	}
+	for name, content := range cases {
+		if _, err := Parse(content); err == nil {
+			t.Errorf("%s: expected error for %q", name, content)
+		}
+	}
+}
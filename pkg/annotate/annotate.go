@@ -0,0 +1,149 @@
+// Package annotate renders gts-suite findings as CI annotations: GitHub
+// Actions workflow commands and GitLab Code Quality reports, so violations
+// appear inline on pull and merge request diffs.
+package annotate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Annotation is one finding attached to a file location.
+type Annotation struct {
+	RuleID    string
+	Severity  string // gts severity: error, warn, info
+	Title     string
+	Message   string
+	File      string
+	StartLine int
+	EndLine   int
+}
+
+// WriteGitHub writes one "::error file=...,line=...::message" workflow
+// command per annotation. Annotations without a line are attached to the
+// file as a whole.
+func WriteGitHub(w io.Writer, annotations []Annotation) error {
+	for _, a := range annotations {
+		props := make([]string, 0, 4)
+		if a.File != "" {
+			props = append(props, "file="+escapeProperty(a.File))
+		}
+		if a.StartLine > 0 {
+			props = append(props, fmt.Sprintf("line=%d", a.StartLine))
+			if a.EndLine > a.StartLine {
+				props = append(props, fmt.Sprintf("endLine=%d", a.EndLine))
+			}
+		}
+		title := a.Title
+		if title == "" {
+			title = a.RuleID
+		}
+		if title != "" {
+			props = append(props, "title="+escapeProperty(title))
+		}
+
+		line := "::" + githubLevel(a.Severity)
+		if len(props) > 0 {
+			line += " " + strings.Join(props, ",")
+		}
+		if _, err := fmt.Fprintf(w, "%s::%s\n", line, escapeData(a.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// codeQualityIssue is one entry of a GitLab Code Quality report.
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
+}
+
+// WriteGitLab writes annotations as a GitLab Code Quality JSON report.
+// Fingerprints leave out line numbers so a finding keeps its identity when
+// code above it moves, which is how GitLab tells new issues from old ones.
+func WriteGitLab(w io.Writer, annotations []Annotation) error {
+	issues := make([]codeQualityIssue, 0, len(annotations))
+	for _, a := range annotations {
+		begin := a.StartLine
+		if begin <= 0 {
+			begin = 1
+		}
+		end := 0
+		if a.EndLine > begin {
+			end = a.EndLine
+		}
+		sum := sha256.Sum256([]byte(a.RuleID + "\x00" + a.File + "\x00" + a.Message))
+		issues = append(issues, codeQualityIssue{
+			Description: a.Message,
+			CheckName:   a.RuleID,
+			Fingerprint: hex.EncodeToString(sum[:16]),
+			Severity:    gitlabSeverity(a.Severity),
+			Location: codeQualityLocation{
+				Path:  a.File,
+				Lines: codeQualityLines{Begin: begin, End: end},
+			},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+// githubLevel converts gts severity strings to workflow command names.
+// Unknown severities default to "warning".
+func githubLevel(severity string) string {
+	switch severity {
+	case "error":
+		return "error"
+	case "note", "info":
+		return "notice"
+	default:
+		return "warning"
+	}
+}
+
+// gitlabSeverity converts gts severity strings to Code Quality severities.
+// Unknown severities default to "minor".
+func gitlabSeverity(severity string) string {
+	switch severity {
+	case "error":
+		return "major"
+	case "note", "info":
+		return "info"
+	default:
+		return "minor"
+	}
+}
+
+// escapeData escapes a workflow command message.
+func escapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeProperty escapes a workflow command property value, which
+// additionally may not contain the ":" and "," separators.
+func escapeProperty(s string) string {
+	s = escapeData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
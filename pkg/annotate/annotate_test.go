@@ -0,0 +1,80 @@
+package annotate
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteGitHub(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteGitHub(&buf, []Annotation{
+		{RuleID: "cyclomatic", Severity: "error", Message: "complexity 55 exceeds 50\nsplit it", File: "pkg/a,b.go", StartLine: 10, EndLine: 42},
+		{RuleID: "dead-code", Severity: "warn", Message: "100% unused", File: "main.go", StartLine: 3, EndLine: 3},
+		{RuleID: "boundary-violation", Message: "ui -> db", File: "internal/ui"},
+	})
+	if err != nil {
+		t.Fatalf("WriteGitHub: %v", err)
+	}
+
+	want := "::error file=pkg/a%2Cb.go,line=10,endLine=42,title=cyclomatic::complexity 55 exceeds 50%0Asplit it\n" +
+		"::warning file=main.go,line=3,title=dead-code::100%25 unused\n" +
+		"::warning file=internal/ui,title=boundary-violation::ui -> db\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteGitLab(t *testing.T) {
+	var buf bytes.Buffer
+	annotations := []Annotation{
+		{RuleID: "cyclomatic", Severity: "error", Message: "too complex", File: "a.go", StartLine: 10, EndLine: 42},
+		{RuleID: "boundary-violation", Severity: "error", Message: "ui -> db", File: "internal/ui"},
+	}
+	if err := WriteGitLab(&buf, annotations); err != nil {
+		t.Fatalf("WriteGitLab: %v", err)
+	}
+
+	var issues []codeQualityIssue
+	if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(issues) != 2 {
+		t.Fatalf("issues = %d, want 2", len(issues))
+	}
+	first := issues[0]
+	if first.CheckName != "cyclomatic" || first.Severity != "major" || first.Location.Path != "a.go" {
+		t.Errorf("unexpected issue %+v", first)
+	}
+	if first.Location.Lines.Begin != 10 || first.Location.Lines.End != 42 {
+		t.Errorf("lines = %+v, want 10-42", first.Location.Lines)
+	}
+	if issues[1].Location.Lines.Begin != 1 {
+		t.Errorf("line-less issue should start at line 1, got %d", issues[1].Location.Lines.Begin)
+	}
+
+	// Moving a finding must not change its fingerprint.
+	moved := annotations[0]
+	moved.StartLine, moved.EndLine = 20, 52
+	buf.Reset()
+	if err := WriteGitLab(&buf, []Annotation{moved}); err != nil {
+		t.Fatalf("WriteGitLab: %v", err)
+	}
+	var movedIssues []codeQualityIssue
+	if err := json.Unmarshal(buf.Bytes(), &movedIssues); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if movedIssues[0].Fingerprint != first.Fingerprint {
+		t.Errorf("fingerprint changed after move: %s != %s", movedIssues[0].Fingerprint, first.Fingerprint)
+	}
+}
+
+func TestWriteGitLabEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGitLab(&buf, nil); err != nil {
+		t.Fatalf("WriteGitLab: %v", err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("empty report = %q, want %q", got, "[]\n")
+	}
+}
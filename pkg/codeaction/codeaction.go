@@ -0,0 +1,147 @@
+// Package codeaction computes the source changes behind editor code actions
+// and gts fix: removing unused imports, sorting imports, and extracting a
+// literal to a named constant. Each works on the tree-sitter syntax tree of
+// one Go, Python, JavaScript, or TypeScript file and returns the new source;
+// Edit turns a change into a refactor.Edit.
+package codeaction
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/internal/parsesession"
+	"github.com/odvcencio/gts-suite/pkg/refactor"
+)
+
+// Code action kinds, as LSP names them.
+const (
+	KindOrganizeImports     = "source.organizeImports"
+	KindRemoveUnusedImports = "source.removeUnusedImports"
+	KindSortImports         = "source.sortImports"
+	KindExtractConstant     = "refactor.extract.constant"
+)
+
+// Action is a code action on one file.
+type Action struct {
+	Kind  string        `json:"kind"`
+	Title string        `json:"title"`
+	Edit  refactor.Edit `json:"edit"`
+}
+
+// Supported reports whether code actions are available for language, an
+// index language name such as "go" or "typescript".
+func Supported(language string) bool {
+	_, ok := syntaxes[language]
+	return ok
+}
+
+// Actions returns the code actions available in source, the contents of
+// file, for the selection [start, end) of byte offsets: each import action
+// that would change the file, and extracting the literal at the selection.
+func Actions(file, language string, source []byte, start, end int) ([]Action, error) {
+	if !Supported(language) {
+		return nil, nil
+	}
+	var actions []Action
+	importActions := []struct {
+		kind, title string
+		apply       func(string, []byte) ([]byte, error)
+	}{
+		{KindOrganizeImports, "Organize imports", OrganizeImports},
+		{KindRemoveUnusedImports, "Remove unused imports", RemoveUnusedImports},
+		{KindSortImports, "Sort imports", SortImports},
+	}
+	for _, candidate := range importActions {
+		updated, err := candidate.apply(language, source)
+		if err != nil {
+			return nil, err
+		}
+		if edit, ok := Edit(file, source, updated); ok {
+			edit.Category = candidate.kind
+			actions = append(actions, Action{Kind: candidate.kind, Title: candidate.title, Edit: edit})
+		}
+	}
+	if updated, name, err := ExtractConstant(language, source, start, end, ""); err == nil {
+		if edit, ok := Edit(file, source, updated); ok {
+			edit.Category = KindExtractConstant
+			actions = append(actions, Action{Kind: KindExtractConstant, Title: "Extract constant " + name, Edit: edit})
+		}
+	}
+	return actions, nil
+}
+
+// Edit returns the edit that turns before into after, the contents of file,
+// replacing the whole lines that differ. It reports false when they are
+// equal.
+func Edit(file string, before, after []byte) (refactor.Edit, bool) {
+	if bytes.Equal(before, after) {
+		return refactor.Edit{}, false
+	}
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	prefix = bytes.LastIndexByte(before[:prefix], '\n') + 1
+
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix && before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	// Keep whole lines: shrink the shared suffix to start after a newline.
+	for suffix > 0 && len(before)-suffix > prefix && before[len(before)-suffix-1] != '\n' {
+		suffix--
+	}
+
+	return refactor.Edit{
+		File:    file,
+		Kind:    "code_action",
+		OldName: string(before[prefix : len(before)-suffix]),
+		NewName: string(after[prefix : len(after)-suffix]),
+		Line:    bytes.Count(before[:prefix], []byte("\n")) + 1,
+		Column:  1,
+		Offset:  prefix,
+	}, true
+}
+
+// parse returns the syntax tree of source, which the caller must release,
+// together with the language's grammar and syntax rules.
+func parse(language string, source []byte) (*gotreesitter.Tree, *gotreesitter.Language, *syntax, error) {
+	rules, ok := syntaxes[language]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("code actions do not support %q", language)
+	}
+	session := parsesession.New()
+	tree, err := session.Parse(language, source)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	lang, _ := session.Language(language)
+	return tree, lang, rules, nil
+}
+
+// walk calls visit on node and its descendants, skipping the children of
+// nodes for which visit returns false.
+func walk(node *gotreesitter.Node, visit func(*gotreesitter.Node) bool) {
+	if node == nil || !visit(node) {
+		return
+	}
+	for i := 0; i < node.ChildCount(); i++ {
+		walk(node.Child(i), visit)
+	}
+}
+
+// lineStart returns the offset of the start of the line holding offset.
+func lineStart(source []byte, offset int) int {
+	return bytes.LastIndexByte(source[:offset], '\n') + 1
+}
+
+// lineEnd returns the offset just past the newline ending the line holding
+// offset, or len(source).
+func lineEnd(source []byte, offset int) int {
+	if newline := bytes.IndexByte(source[offset:], '\n'); newline >= 0 {
+		return offset + newline + 1
+	}
+	return len(source)
+}
@@ -0,0 +1,308 @@
+package codeaction
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRemoveUnusedImports(t *testing.T) {
+	tests := []struct {
+		name, language, source, want string
+	}{
+		{
+			name:     "go",
+			language: "go",
+			source: `package p
+
+import (
+	"fmt"
+	"os"
+	yaml "gopkg.in/yaml.v3"
+	_ "embed"
+)
+
+import "strings"
+
+import (
+	"bytes"
+)
+
+func f() { fmt.Println(yaml.Node{}) }
+`,
+			want: `package p
+
+import (
+	"fmt"
+	yaml "gopkg.in/yaml.v3"
+	_ "embed"
+)
+
+func f() { fmt.Println(yaml.Node{}) }
+`,
+		},
+		{
+			name:     "go keeps unaliased imports when a package name is unknown",
+			language: "go",
+			source: `package p
+
+import (
+	"fmt"
+	"github.com/example/go-thing"
+)
+
+func f() { widget.Do() }
+`,
+			want: `package p
+
+import (
+	"fmt"
+	"github.com/example/go-thing"
+)
+
+func f() { widget.Do() }
+`,
+		},
+		{
+			name:     "python",
+			language: "python",
+			source: `import os, sys as system
+from a.b import (c, d as e)
+from x import *
+import json
+
+__all__ = ["c"]
+print(system.argv)
+`,
+			want: `import sys as system
+from a.b import c
+from x import *
+
+__all__ = ["c"]
+print(system.argv)
+`,
+		},
+		{
+			name:     "typescript",
+			language: "typescript",
+			source: `import React, { useState as useS, useEffect } from "react";
+import * as path from 'path'
+import './styles.css';
+import type { Unused } from "./types";
+
+export function App() { return useEffect(useS(0)); }
+`,
+			want: `import { useState as useS, useEffect } from "react";
+import './styles.css';
+
+export function App() { return useEffect(useS(0)); }
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RemoveUnusedImports(tt.language, []byte(tt.source))
+			if err != nil {
+				t.Fatalf("RemoveUnusedImports returned error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("RemoveUnusedImports =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortImports(t *testing.T) {
+	tests := []struct {
+		name, language, source, want string
+	}{
+		{
+			name:     "go keeps groups",
+			language: "go",
+			source: `package p
+
+import (
+	"os"
+	"fmt"
+
+	"github.com/b/y"
+	"github.com/a/x"
+)
+`,
+			want: `package p
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/a/x"
+	"github.com/b/y"
+)
+`,
+		},
+		{
+			name:     "python future first",
+			language: "python",
+			source: `import sys
+from __future__ import annotations
+import os
+`,
+			want: `from __future__ import annotations
+import os
+import sys
+`,
+		},
+		{
+			name:     "javascript side-effect imports stay put",
+			language: "javascript",
+			source: `import b from "b";
+import a from "a";
+import "polyfill";
+import d from "d";
+import c from "c";
+`,
+			want: `import a from "a";
+import b from "b";
+import "polyfill";
+import c from "c";
+import d from "d";
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SortImports(tt.language, []byte(tt.source))
+			if err != nil {
+				t.Fatalf("SortImports returned error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("SortImports =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractConstant(t *testing.T) {
+	tests := []struct {
+		name, language, source, selection, constName, want, wantName string
+	}{
+		{
+			name:     "go string",
+			language: "go",
+			source: `package p
+
+// f greets.
+func f() string { return "hello world" }
+`,
+			selection: `"hello world"`,
+			want: `package p
+
+const helloWorld = "hello world"
+
+// f greets.
+func f() string { return helloWorld }
+`,
+			wantName: "helloWorld",
+		},
+		{
+			name:     "go number with name",
+			language: "go",
+			source: `package p
+
+func f() int { return 3 }
+`,
+			selection: "3",
+			constName: "maxRetries",
+			want: `package p
+
+const maxRetries = 3
+
+func f() int { return maxRetries }
+`,
+			wantName: "maxRetries",
+		},
+		{
+			name:     "python",
+			language: "python",
+			source: `import os
+
+
+def f():
+    return os.getenv("API_HOST")
+`,
+			selection: `API_HOST`,
+			want: `import os
+
+
+API_HOST = "API_HOST"
+
+
+def f():
+    return os.getenv(API_HOST)
+`,
+			wantName: "API_HOST",
+		},
+		{
+			name:     "typescript collision",
+			language: "typescript",
+			source: `const TIMEOUT = 1;
+export function f() { return wait(250, "timeout"); }
+`,
+			selection: `"timeout"`,
+			want: `const TIMEOUT = 1;
+const TIMEOUT2 = "timeout";
+
+export function f() { return wait(250, TIMEOUT2); }
+`,
+			wantName: "TIMEOUT2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := strings.Index(tt.source, tt.selection)
+			got, name, err := ExtractConstant(tt.language, []byte(tt.source), start, start+len(tt.selection), tt.constName)
+			if err != nil {
+				t.Fatalf("ExtractConstant returned error: %v", err)
+			}
+			if name != tt.wantName || string(got) != tt.want {
+				t.Fatalf("ExtractConstant = %q,\n%s\nwant %q,\n%s", name, got, tt.wantName, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractConstantRejects(t *testing.T) {
+	source := "const x = `a${b}`;\nimport y from \"y\";\n"
+	for _, selection := range []string{"`a${b}`", `"y"`, "const"} {
+		start := strings.Index(source, selection)
+		if _, _, err := ExtractConstant("javascript", []byte(source), start, start+len(selection), ""); err == nil {
+			t.Fatalf("ExtractConstant(%q) succeeded, want error", selection)
+		}
+	}
+	if _, _, err := ExtractConstant("go", []byte("package p\n\nvar v = 1\n"), 19, 20, "1bad"); err == nil {
+		t.Fatal("ExtractConstant accepted an invalid name")
+	}
+}
+
+func TestActions(t *testing.T) {
+	source := []byte("package p\n\nimport (\n\t\"os\"\n\t\"fmt\"\n)\n\nfunc f() { fmt.Println(\"hi\") }\n")
+	offset := strings.Index(string(source), `"hi"`) + 1
+	actions, err := Actions("p.go", "go", source, offset, offset)
+	if err != nil {
+		t.Fatalf("Actions returned error: %v", err)
+	}
+	kinds := make([]string, 0, len(actions))
+	for _, action := range actions {
+		kinds = append(kinds, action.Kind)
+	}
+	want := []string{KindOrganizeImports, KindRemoveUnusedImports, KindSortImports, KindExtractConstant}
+	if strings.Join(kinds, ",") != strings.Join(want, ",") {
+		t.Fatalf("action kinds = %v, want %v", kinds, want)
+	}
+	edit := actions[0].Edit
+	if edit.File != "p.go" || edit.Line != 4 || edit.OldName != "\t\"os\"\n" || edit.NewName != "" {
+		t.Fatalf("organize imports edit = %+v", edit)
+	}
+	if actions, _ := Actions("p.rb", "ruby", source, 0, 0); len(actions) != 0 {
+		t.Fatalf("Actions for an unsupported language = %v, want none", actions)
+	}
+}
@@ -0,0 +1,140 @@
+package codeaction
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/pkg/refactor"
+)
+
+var (
+	constantNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	literalWord         = regexp.MustCompile(`\pL[\pL\pN]*`)
+)
+
+// maxNameWords bounds how many words of a literal name its constant.
+const maxNameWords = 4
+
+// ExtractConstant returns source, in language, with the literal at the
+// selection [start, end) of byte offsets replaced by a constant declared
+// before the top-level declaration holding it, and the constant's name. An
+// empty selection picks the literal at start. When name is empty, one is
+// derived from the literal and made unique in the file.
+func ExtractConstant(language string, source []byte, start, end int, name string) ([]byte, string, error) {
+	tree, lang, rules, err := parse(language, source)
+	if err != nil {
+		return nil, "", err
+	}
+	defer tree.Release()
+	c := &fileContext{root: tree.RootNode(), lang: lang, source: source}
+
+	start, end = max(min(start, len(source)), 0), max(min(end, len(source)), 0)
+	if end < start {
+		start, end = end, start
+	}
+	literal := c.root.NamedDescendantForByteRange(uint32(start), uint32(end))
+	for literal != nil && !rules.literals[c.kind(literal)] {
+		literal = literal.Parent()
+	}
+	if literal == nil {
+		return nil, "", fmt.Errorf("no literal at the selection")
+	}
+	if rules.interpolations != nil {
+		interpolated := false
+		walk(literal, func(node *gotreesitter.Node) bool {
+			interpolated = interpolated || rules.interpolations[c.kind(node)]
+			return !interpolated
+		})
+		if interpolated {
+			return nil, "", fmt.Errorf("literal %s interpolates values", c.text(literal))
+		}
+	}
+
+	top := literal
+	for top.Parent() != nil && top.Parent() != c.root {
+		top = top.Parent()
+		if strings.Contains(c.kind(top), "import") {
+			return nil, "", fmt.Errorf("cannot extract a literal from an import")
+		}
+	}
+	if top == literal {
+		return nil, "", fmt.Errorf("literal %s is not inside a declaration", c.text(literal))
+	}
+
+	taken := leafNames(c, nil, func(kind string) bool { return strings.HasSuffix(kind, "identifier") })
+	if name == "" {
+		text := c.text(literal)
+		// Skip string prefixes such as Python's r and b.
+		if quote := strings.IndexAny(text, "\"'`"); quote >= 0 {
+			text = text[quote:]
+		}
+		name = rules.constantName(literalWord.FindAllString(text, maxNameWords))
+		for base, n := name, 2; taken[name]; n++ {
+			name = base + strconv.Itoa(n)
+		}
+	} else if !constantNamePattern.MatchString(name) {
+		return nil, "", fmt.Errorf("invalid constant name %q", name)
+	} else if taken[name] {
+		return nil, "", fmt.Errorf("name %q is already used in the file", name)
+	}
+
+	// Keep the comments documenting the declaration attached to it.
+	insertAt := lineStart(source, int(top.StartByte()))
+	for prev := top.PrevSibling(); prev != nil && c.kind(prev) == "comment" && lineEnd(source, int(prev.EndByte())) == insertAt; prev = prev.PrevSibling() {
+		insertAt = lineStart(source, int(prev.StartByte()))
+	}
+
+	edits := []refactor.Edit{
+		{NewName: rules.declare(name, c.text(literal), c.kind(top)), Offset: insertAt},
+		{OldName: c.text(literal), NewName: name, Offset: int(literal.StartByte())},
+	}
+	updated, _, err := refactor.ApplyEdits(source, edits)
+	if err != nil {
+		return nil, "", err
+	}
+	return updated, name, nil
+}
+
+// lowerCamel joins words as a Go unexported name, such as maxRetries.
+func lowerCamel(words []string) string {
+	if len(words) == 0 {
+		return "value"
+	}
+	var b strings.Builder
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			first, size := utf8.DecodeRuneInString(word)
+			word = string(unicode.ToUpper(first)) + word[size:]
+		}
+		b.WriteString(word)
+	}
+	name := b.String()
+	if goKeywords[name] {
+		name += "Value"
+	}
+	return name
+}
+
+// upperSnake joins words as a Python or JavaScript constant, such as
+// MAX_RETRIES.
+func upperSnake(words []string) string {
+	if len(words) == 0 {
+		return "VALUE"
+	}
+	return strings.ToUpper(strings.Join(words, "_"))
+}
+
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+}
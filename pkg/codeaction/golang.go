@@ -0,0 +1,173 @@
+package codeaction
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+)
+
+var goSyntax = &syntax{
+	imports: goImports,
+	used:    goUsed,
+	literals: map[string]bool{
+		"interpreted_string_literal": true,
+		"raw_string_literal":         true,
+		"int_literal":                true,
+		"float_literal":              true,
+		"imaginary_literal":          true,
+		"rune_literal":               true,
+	},
+	constantName: lowerCamel,
+	declare: func(name, literal, _ string) string {
+		return "const " + name + " = " + literal + "\n\n"
+	},
+}
+
+// goImports returns the specs of the file's import declarations. The specs
+// of a parenthesized declaration may be sorted among themselves; a
+// single-spec declaration stands alone.
+func goImports(c *fileContext) []importStmt {
+	var imports []importStmt
+	for i := 0; i < c.root.NamedChildCount(); i++ {
+		decl := c.root.NamedChild(i)
+		if c.kind(decl) != "import_declaration" {
+			continue
+		}
+		for j := 0; j < decl.NamedChildCount(); j++ {
+			child := decl.NamedChild(j)
+			switch c.kind(child) {
+			case "import_spec":
+				stmt := goImportSpec(c, child)
+				stmt.node = decl
+				stmt.movable = false
+				imports = append(imports, stmt)
+			case "import_spec_list":
+				for k := 0; k < child.NamedChildCount(); k++ {
+					spec := child.NamedChild(k)
+					if c.kind(spec) != "import_spec" {
+						continue
+					}
+					stmt := goImportSpec(c, spec)
+					stmt.container = decl
+					imports = append(imports, stmt)
+				}
+			}
+		}
+	}
+	return imports
+}
+
+func goImportSpec(c *fileContext, spec *gotreesitter.Node) importStmt {
+	stmt := importStmt{node: spec, removable: true, movable: true}
+	if path := spec.ChildByFieldName("path", c.lang); path != nil {
+		stmt.key = unquote(c.text(path))
+	}
+	name := spec.ChildByFieldName("name", c.lang)
+	switch {
+	case name != nil && (c.text(name) == "_" || c.text(name) == "."):
+		stmt.removable = false
+	case name != nil:
+		stmt.bindings = []binding{{names: []string{c.text(name)}, aliased: true}}
+	case stmt.key == "C":
+		stmt.removable = false
+	default:
+		stmt.bindings = []binding{{names: goPackageNames(stmt.key)}}
+	}
+	return stmt
+}
+
+// goUsed reports an import used when its name is the operand of a selector
+// or the package of a qualified type. The package name of an unaliased
+// import is only guessed from its path, so when some operand is neither a
+// guessed name nor declared in the file, unaliased imports are all kept.
+func goUsed(c *fileContext, imports []importStmt) func(binding) bool {
+	skip := importNodes(imports)
+	operands := map[string]bool{}
+	var operandNodes []*gotreesitter.Node
+	walk(c.root, func(node *gotreesitter.Node) bool {
+		if skip[node] {
+			return false
+		}
+		var operand *gotreesitter.Node
+		switch c.kind(node) {
+		case "selector_expression":
+			operand = node.ChildByFieldName("operand", c.lang)
+		case "qualified_type":
+			operand = node.ChildByFieldName("package", c.lang)
+		}
+		if operand != nil && operand.NamedChildCount() == 0 {
+			operands[c.text(operand)] = true
+			operandNodes = append(operandNodes, operand)
+		}
+		return true
+	})
+
+	declared := map[string]bool{}
+	isOperand := make(map[*gotreesitter.Node]bool, len(operandNodes))
+	for _, node := range operandNodes {
+		isOperand[node] = true
+	}
+	walk(c.root, func(node *gotreesitter.Node) bool {
+		if skip[node] {
+			return false
+		}
+		if c.kind(node) == "identifier" && !isOperand[node] {
+			declared[c.text(node)] = true
+		}
+		return true
+	})
+	guessed := map[string]bool{}
+	for _, stmt := range imports {
+		for _, b := range stmt.bindings {
+			for _, name := range b.names {
+				guessed[name] = true
+			}
+		}
+	}
+	unresolved := false
+	for operand := range operands {
+		if !guessed[operand] && !declared[operand] {
+			unresolved = true
+			break
+		}
+	}
+
+	usedName := usedByName(operands)
+	return func(b binding) bool {
+		if unresolved && !b.aliased {
+			return true
+		}
+		return usedName(b)
+	}
+}
+
+// goPackageNames returns the names a package imported from path is likely
+// declared as, as the unused-imports lint rule guesses them: the last path
+// element without a major version, and that element stripped of go- and -go
+// affixes, gopkg.in version suffixes, and dashes.
+func goPackageNames(path string) []string {
+	parts := strings.Split(path, "/")
+	last := parts[len(parts)-1]
+	if len(parts) > 1 && len(last) > 1 && last[0] == 'v' {
+		if _, err := strconv.Atoi(last[1:]); err == nil {
+			last = parts[len(parts)-2]
+		}
+	}
+
+	names := []string{last}
+	trimmed := strings.TrimPrefix(last, "go-")
+	trimmed = strings.TrimSuffix(trimmed, "-go")
+	trimmed = strings.TrimSuffix(trimmed, ".go")
+	if dot := strings.Index(trimmed, ".v"); dot > 0 {
+		trimmed = trimmed[:dot]
+	}
+	trimmed = strings.ReplaceAll(trimmed, "-", "")
+	if trimmed != last && trimmed != "" {
+		names = append(names, trimmed)
+	}
+	if dot := strings.LastIndex(trimmed, "."); dot >= 0 && dot < len(trimmed)-1 {
+		names = append(names, trimmed[dot+1:])
+	}
+	return names
+}
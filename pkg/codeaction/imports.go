@@ -0,0 +1,311 @@
+package codeaction
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/pkg/refactor"
+)
+
+// fileContext is a parsed file.
+type fileContext struct {
+	root   *gotreesitter.Node
+	lang   *gotreesitter.Language
+	source []byte
+}
+
+func (c *fileContext) kind(node *gotreesitter.Node) string {
+	return node.Type(c.lang)
+}
+
+func (c *fileContext) text(node *gotreesitter.Node) string {
+	return node.Text(c.source)
+}
+
+// importStmt is one import of a file: an import statement, or one spec of a
+// grouped Go import declaration.
+type importStmt struct {
+	node *gotreesitter.Node
+	// key orders imports when sorting: the imported module or path.
+	key string
+	// rank sorts before key; Python's __future__ imports rank lowest.
+	rank     int
+	bindings []binding
+	// removable is false for imports kept for their side effects, such as
+	// blank, dot, and wildcard imports.
+	removable bool
+	// movable is false for imports whose position matters, which end a run
+	// of imports being sorted.
+	movable bool
+	// rewrite renders the import keeping only the bindings kept says. When
+	// nil, an import is removed whole or not at all.
+	rewrite func(kept []bool) string
+	// container is the Go import declaration grouping a spec, removed with
+	// its last spec.
+	container *gotreesitter.Node
+}
+
+// binding is a name an import introduces. It is used if any of names is.
+type binding struct {
+	names []string
+	// aliased bindings were named in the source rather than guessed from
+	// the import path.
+	aliased bool
+}
+
+// syntax is what the code actions need to know about a language.
+type syntax struct {
+	imports func(c *fileContext) []importStmt
+	// used returns a function reporting whether a binding is used outside
+	// the imports.
+	used func(c *fileContext, imports []importStmt) func(binding) bool
+	// literals are the node types ExtractConstant extracts.
+	literals map[string]bool
+	// interpolations are node types that make a literal depend on its
+	// surroundings, such as f-string replacement fields.
+	interpolations map[string]bool
+	// constantName renders the words describing a literal as a constant
+	// name in the language's style.
+	constantName func(words []string) string
+	// declare renders the declaration of a constant inserted before a
+	// top-level node of type before.
+	declare func(name, literal, before string) string
+}
+
+var syntaxes = map[string]*syntax{
+	"go":         goSyntax,
+	"python":     pythonSyntax,
+	"javascript": jsSyntax,
+	"typescript": jsSyntax,
+	"tsx":        jsSyntax,
+}
+
+// RemoveUnusedImports returns source, in language, without the imports
+// whose names it never uses. Imports that may be kept for their side
+// effects are left alone, and a Go import is only removed by its guessed
+// package name when every package the file refers to is accounted for.
+func RemoveUnusedImports(language string, source []byte) ([]byte, error) {
+	tree, lang, rules, err := parse(language, source)
+	if err != nil {
+		return nil, err
+	}
+	defer tree.Release()
+	c := &fileContext{root: tree.RootNode(), lang: lang, source: source}
+	imports := rules.imports(c)
+	used := rules.used(c, imports)
+
+	type change struct {
+		stmt   importStmt
+		remove bool
+		text   string
+	}
+	var changes []change
+	members := map[*gotreesitter.Node]int{}
+	removed := map[*gotreesitter.Node]int{}
+	for _, stmt := range imports {
+		if stmt.container != nil {
+			members[stmt.container]++
+		}
+		if !stmt.removable || len(stmt.bindings) == 0 {
+			continue
+		}
+		kept := make([]bool, len(stmt.bindings))
+		keptCount := 0
+		for i, b := range stmt.bindings {
+			if used(b) {
+				kept[i] = true
+				keptCount++
+			}
+		}
+		switch {
+		case keptCount == len(kept):
+		case keptCount == 0:
+			changes = append(changes, change{stmt: stmt, remove: true})
+			if stmt.container != nil {
+				removed[stmt.container]++
+			}
+		case stmt.rewrite != nil:
+			changes = append(changes, change{stmt: stmt, text: stmt.rewrite(kept)})
+		}
+	}
+
+	var edits []refactor.Edit
+	var spans [][2]int
+	containersRemoved := map[*gotreesitter.Node]bool{}
+	for _, ch := range changes {
+		node := ch.stmt.node
+		if container := ch.stmt.container; container != nil && removed[container] == members[container] {
+			if containersRemoved[container] {
+				continue
+			}
+			containersRemoved[container] = true
+			node = container
+		}
+		start, end := int(node.StartByte()), int(node.EndByte())
+		if !ch.remove {
+			edits = append(edits, refactor.Edit{OldName: string(source[start:end]), NewName: ch.text, Offset: start})
+			continue
+		}
+		if first, last, ok := ownLines(source, start, end); ok {
+			spans = append(spans, [2]int{first, last})
+		}
+	}
+	for _, span := range mergeSpans(spans) {
+		first, last := span[0], span[1]
+		// Removing lines between blank lines leaves one blank line.
+		if first > 1 && source[first-2] == '\n' && last < len(source) && source[last] == '\n' {
+			last++
+		}
+		edits = append(edits, refactor.Edit{OldName: string(source[first:last]), Offset: first})
+	}
+	updated, _, err := refactor.ApplyEdits(source, edits)
+	return updated, err
+}
+
+// mergeSpans sorts spans and joins the ones that touch.
+func mergeSpans(spans [][2]int) [][2]int {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	var merged [][2]int
+	for _, span := range spans {
+		if n := len(merged); n > 0 && merged[n-1][1] >= span[0] {
+			merged[n-1][1] = max(merged[n-1][1], span[1])
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+// SortImports returns source, in language, with each run of imports on
+// consecutive lines sorted by module. Blank lines, comments, and imports
+// whose position matters end a run, so deliberate grouping is kept.
+func SortImports(language string, source []byte) ([]byte, error) {
+	tree, lang, rules, err := parse(language, source)
+	if err != nil {
+		return nil, err
+	}
+	defer tree.Release()
+	c := &fileContext{root: tree.RootNode(), lang: lang, source: source}
+
+	type unit struct {
+		stmt        importStmt
+		first, last int
+	}
+	var runs [][]unit
+	var run []unit
+	flush := func() {
+		if len(run) > 1 {
+			runs = append(runs, run)
+		}
+		run = nil
+	}
+	for _, stmt := range rules.imports(c) {
+		first, last, ok := ownLines(source, int(stmt.node.StartByte()), int(stmt.node.EndByte()))
+		if !ok || !stmt.movable || last == len(source) && !bytes.HasSuffix(source, []byte("\n")) {
+			flush()
+			continue
+		}
+		if n := len(run); n > 0 && (run[n-1].last != first || run[n-1].stmt.container != stmt.container) {
+			flush()
+		}
+		run = append(run, unit{stmt: stmt, first: first, last: last})
+	}
+	flush()
+
+	var edits []refactor.Edit
+	for _, run := range runs {
+		sorted := append([]unit(nil), run...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if sorted[i].stmt.rank != sorted[j].stmt.rank {
+				return sorted[i].stmt.rank < sorted[j].stmt.rank
+			}
+			return sorted[i].stmt.key < sorted[j].stmt.key
+		})
+		var text strings.Builder
+		for _, u := range sorted {
+			text.Write(source[u.first:u.last])
+		}
+		start, end := run[0].first, run[len(run)-1].last
+		if text.String() != string(source[start:end]) {
+			edits = append(edits, refactor.Edit{OldName: string(source[start:end]), NewName: text.String(), Offset: start})
+		}
+	}
+	updated, _, err := refactor.ApplyEdits(source, edits)
+	return updated, err
+}
+
+// OrganizeImports removes unused imports from source, then sorts the rest.
+func OrganizeImports(language string, source []byte) ([]byte, error) {
+	pruned, err := RemoveUnusedImports(language, source)
+	if err != nil {
+		return nil, err
+	}
+	return SortImports(language, pruned)
+}
+
+// ownLines returns the span of the whole lines holding source[start:end],
+// provided nothing but whitespace precedes it on its first line and nothing
+// but whitespace or a comment follows it on its last.
+func ownLines(source []byte, start, end int) (int, int, bool) {
+	first := lineStart(source, start)
+	if len(bytes.TrimSpace(source[first:start])) > 0 {
+		return 0, 0, false
+	}
+	last := lineEnd(source, end)
+	trailing := bytes.TrimSpace(source[end:last])
+	if len(trailing) > 0 && !bytes.HasPrefix(trailing, []byte("//")) && !bytes.HasPrefix(trailing, []byte("#")) {
+		return 0, 0, false
+	}
+	return first, last, true
+}
+
+// leafNames collects the text of the identifier leaves of root whose type
+// passes keep, skipping the subtrees of skip.
+func leafNames(c *fileContext, skip map[*gotreesitter.Node]bool, keep func(kind string) bool) map[string]bool {
+	names := map[string]bool{}
+	walk(c.root, func(node *gotreesitter.Node) bool {
+		if skip[node] {
+			return false
+		}
+		if node.IsNamed() && node.NamedChildCount() == 0 && keep(c.kind(node)) {
+			names[c.text(node)] = true
+		}
+		return true
+	})
+	return names
+}
+
+// importNodes returns the nodes of imports, to skip when looking for uses.
+func importNodes(imports []importStmt) map[*gotreesitter.Node]bool {
+	nodes := make(map[*gotreesitter.Node]bool, len(imports))
+	for _, stmt := range imports {
+		nodes[stmt.node] = true
+		if stmt.container != nil {
+			nodes[stmt.container] = true
+		}
+	}
+	return nodes
+}
+
+// usedByName reports a binding used when any of its names is in names.
+func usedByName(names map[string]bool) func(binding) bool {
+	return func(b binding) bool {
+		for _, name := range b.names {
+			if names[name] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func unquote(text string) string {
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		return unquoted
+	}
+	return strings.Trim(text, "'\"`")
+}
@@ -0,0 +1,131 @@
+package codeaction
+
+import (
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+)
+
+var jsSyntax = &syntax{
+	imports: jsImports,
+	used:    jsUsed,
+	literals: map[string]bool{
+		"string":          true,
+		"number":          true,
+		"template_string": true,
+	},
+	interpolations: map[string]bool{"template_substitution": true},
+	constantName:   upperSnake,
+	declare: func(name, literal, _ string) string {
+		return "const " + name + " = " + literal + ";\n\n"
+	},
+}
+
+// jsImports returns the file's import statements. Side-effect imports, and
+// TypeScript's import = require, are kept and keep their place.
+func jsImports(c *fileContext) []importStmt {
+	var imports []importStmt
+	for i := 0; i < c.root.NamedChildCount(); i++ {
+		node := c.root.NamedChild(i)
+		if c.kind(node) != "import_statement" {
+			continue
+		}
+		stmt := importStmt{node: node}
+		if source := node.ChildByFieldName("source", c.lang); source != nil {
+			stmt.key = unquote(c.text(source))
+		}
+		var clause *gotreesitter.Node
+		for j := 0; j < node.NamedChildCount(); j++ {
+			if child := node.NamedChild(j); c.kind(child) == "import_clause" {
+				clause = child
+			}
+		}
+		if clause == nil || stmt.key == "" {
+			imports = append(imports, stmt)
+			continue
+		}
+		stmt.removable, stmt.movable = true, true
+
+		// parts renders each binding as written, for rewriting.
+		var parts []string
+		var named []bool
+		for j := 0; j < clause.NamedChildCount(); j++ {
+			child := clause.NamedChild(j)
+			switch c.kind(child) {
+			case "identifier":
+				stmt.bindings = append(stmt.bindings, binding{names: []string{c.text(child)}})
+				parts, named = append(parts, c.text(child)), append(named, false)
+			case "namespace_import":
+				stmt.bindings = append(stmt.bindings, binding{names: []string{lastIdentifier(c, child)}})
+				parts, named = append(parts, c.text(child)), append(named, false)
+			case "named_imports":
+				for k := 0; k < child.NamedChildCount(); k++ {
+					specifier := child.NamedChild(k)
+					if c.kind(specifier) != "import_specifier" {
+						continue
+					}
+					bound := specifier.ChildByFieldName("alias", c.lang)
+					if bound == nil {
+						bound = specifier.ChildByFieldName("name", c.lang)
+					}
+					if bound == nil {
+						continue
+					}
+					stmt.bindings = append(stmt.bindings, binding{names: []string{c.text(bound)}})
+					parts, named = append(parts, c.text(specifier)), append(named, true)
+				}
+			}
+		}
+		if len(parts) != len(stmt.bindings) {
+			stmt.removable = false
+			imports = append(imports, stmt)
+			continue
+		}
+
+		text := c.text(node)
+		prefix := "import "
+		if strings.HasPrefix(text, "import type ") {
+			prefix = "import type "
+		}
+		source := c.text(node.ChildByFieldName("source", c.lang))
+		semicolon := ""
+		if strings.HasSuffix(text, ";") {
+			semicolon = ";"
+		}
+		stmt.rewrite = func(kept []bool) string {
+			var clauses, specifiers []string
+			for i, part := range parts {
+				switch {
+				case !kept[i]:
+				case named[i]:
+					specifiers = append(specifiers, part)
+				default:
+					clauses = append(clauses, part)
+				}
+			}
+			if len(specifiers) > 0 {
+				clauses = append(clauses, "{ "+strings.Join(specifiers, ", ")+" }")
+			}
+			return prefix + strings.Join(clauses, ", ") + " from " + source + semicolon
+		}
+		imports = append(imports, stmt)
+	}
+	return imports
+}
+
+func lastIdentifier(c *fileContext, node *gotreesitter.Node) string {
+	for i := node.NamedChildCount() - 1; i >= 0; i-- {
+		if child := node.NamedChild(i); c.kind(child) == "identifier" {
+			return c.text(child)
+		}
+	}
+	return ""
+}
+
+// jsUsed reports an import used when its name appears anywhere outside the
+// imports, including as a JSX tag or a property name.
+func jsUsed(c *fileContext, imports []importStmt) func(binding) bool {
+	return usedByName(leafNames(c, importNodes(imports), func(kind string) bool {
+		return strings.HasSuffix(kind, "identifier")
+	}))
+}
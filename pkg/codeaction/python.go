@@ -0,0 +1,113 @@
+package codeaction
+
+import (
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+)
+
+var pythonSyntax = &syntax{
+	imports: pythonImports,
+	used:    pythonUsed,
+	literals: map[string]bool{
+		"string":  true,
+		"integer": true,
+		"float":   true,
+	},
+	interpolations: map[string]bool{"interpolation": true},
+	constantName:   upperSnake,
+	declare: func(name, literal, before string) string {
+		switch before {
+		case "function_definition", "class_definition", "decorated_definition":
+			return name + " = " + literal + "\n\n\n"
+		}
+		return name + " = " + literal + "\n"
+	},
+}
+
+// pythonImports returns the file's top-level import statements. A
+// statement importing several names can lose some of them.
+func pythonImports(c *fileContext) []importStmt {
+	var imports []importStmt
+	for i := 0; i < c.root.NamedChildCount(); i++ {
+		node := c.root.NamedChild(i)
+		switch c.kind(node) {
+		case "future_import_statement":
+			imports = append(imports, importStmt{node: node, key: "__future__", rank: -1, movable: true})
+		case "import_statement":
+			stmt := importStmt{node: node, removable: true, movable: true}
+			names := pythonImportNames(c, node)
+			for _, name := range names {
+				module, bound := pythonBinding(c, name)
+				if stmt.key == "" {
+					stmt.key = module
+				}
+				// "import a.b" binds a.
+				bound, _, _ = strings.Cut(bound, ".")
+				stmt.bindings = append(stmt.bindings, binding{names: []string{bound}})
+			}
+			stmt.rewrite = pythonRewrite(c, "import ", names)
+			imports = append(imports, stmt)
+		case "import_from_statement":
+			module := node.ChildByFieldName("module_name", c.lang)
+			if module == nil {
+				continue
+			}
+			stmt := importStmt{node: node, key: c.text(module), removable: true, movable: true}
+			names := pythonImportNames(c, node)
+			for _, name := range names {
+				_, bound := pythonBinding(c, name)
+				stmt.bindings = append(stmt.bindings, binding{names: []string{bound}})
+			}
+			for j := 0; j < node.NamedChildCount(); j++ {
+				if c.kind(node.NamedChild(j)) == "wildcard_import" {
+					stmt.removable = false
+				}
+			}
+			stmt.rewrite = pythonRewrite(c, "from "+stmt.key+" import ", names)
+			imports = append(imports, stmt)
+		}
+	}
+	return imports
+}
+
+// pythonImportNames returns the imported names of an import statement.
+func pythonImportNames(c *fileContext, node *gotreesitter.Node) []*gotreesitter.Node {
+	var names []*gotreesitter.Node
+	for i := 0; i < node.ChildCount(); i++ {
+		if node.FieldNameForChild(i, c.lang) == "name" {
+			names = append(names, node.Child(i))
+		}
+	}
+	return names
+}
+
+// pythonBinding returns the module an imported name refers to and the name
+// it is bound to.
+func pythonBinding(c *fileContext, name *gotreesitter.Node) (module, bound string) {
+	if c.kind(name) == "aliased_import" {
+		module = c.text(name.ChildByFieldName("name", c.lang))
+		return module, c.text(name.ChildByFieldName("alias", c.lang))
+	}
+	return c.text(name), c.text(name)
+}
+
+func pythonRewrite(c *fileContext, prefix string, names []*gotreesitter.Node) func([]bool) string {
+	return func(kept []bool) string {
+		var parts []string
+		for i, name := range names {
+			if kept[i] {
+				parts = append(parts, c.text(name))
+			}
+		}
+		return prefix + strings.Join(parts, ", ")
+	}
+}
+
+// pythonUsed reports an import used when its name appears anywhere outside
+// the imports, or as a string, which keeps names listed in __all__.
+func pythonUsed(c *fileContext, imports []importStmt) func(binding) bool {
+	return usedByName(leafNames(c, importNodes(imports), func(kind string) bool {
+		return kind == "identifier" || kind == "string_content"
+	}))
+}
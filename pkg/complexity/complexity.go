@@ -10,6 +10,7 @@ import (
 
 	"github.com/odvcencio/gotreesitter"
 	"github.com/odvcencio/gotreesitter/grammars"
+	"github.com/odvcencio/gts-suite/internal/parsesession"
 	"github.com/odvcencio/gts-suite/pkg/model"
 	"github.com/odvcencio/gts-suite/pkg/xref"
 )
@@ -67,6 +68,9 @@ func Analyze(idx *model.Index, root string, opts Options) (*Report, error) {
 
 	// Cache file contents to avoid re-reading the same file for multiple functions.
 	fileCache := map[string][]byte{}
+	// Function bodies are parsed one at a time, so one parser per language
+	// serves them all.
+	session := parsesession.New()
 
 	for _, file := range idx.Files {
 		for _, sym := range file.Symbols {
@@ -99,28 +103,16 @@ func Analyze(idx *model.Index, root string, opts Options) (*Report, error) {
 				continue
 			}
 
-			lang := entry.Language()
-			parser := gotreesitter.NewParser(lang)
-
-			var tree *gotreesitter.Tree
-			var parseErr error
-			if entry.TokenSourceFactory != nil {
-				ts := entry.TokenSourceFactory(body, lang)
-				tree, parseErr = parser.ParseWithTokenSource(body, ts)
-			} else {
-				tree, parseErr = parser.Parse(body)
-			}
-			if parseErr != nil || tree == nil {
+			lang, ok := session.Language(entry.Name)
+			if !ok {
 				continue
 			}
-
-			rootNode := tree.RootNode()
-			if rootNode == nil {
-				tree.Release()
+			tree, parseErr := session.Parse(entry.Name, body)
+			if parseErr != nil {
 				continue
 			}
 
-			cyc, cog, maxNest := computeComplexity(rootNode, lang, body)
+			cyc, cog, maxNest := computeComplexity(tree.RootNode(), lang, body)
 			tree.Release()
 
 			metrics := FunctionMetrics{
@@ -229,14 +221,10 @@ func countParameters(signature string) int {
 	return strings.Count(inner, ",") + 1
 }
 
-// isCallableSymbol returns true for function and method definition kinds.
+// isCallableSymbol returns true for function and method definition kinds,
+// including React components and hooks.
 func isCallableSymbol(kind string) bool {
-	switch kind {
-	case "function_definition", "method_definition":
-		return true
-	default:
-		return false
-	}
+	return model.IsFunctionKind(kind) || kind == "method_definition"
 }
 
 // isBranchingNode returns true for node types that represent control flow branching.
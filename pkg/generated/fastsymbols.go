@@ -85,12 +85,17 @@ func FastExtractSymbols(path string, source []byte, language string) model.FileS
 			line := lineFromOffset(lineOffsets, match[0])
 			kind := inferKind(re)
 
+			endLine := lineFromOffset(lineOffsets, match[1]-1)
 			summary.Symbols = append(summary.Symbols, model.Symbol{
-				File:      path,
-				Kind:      kind,
-				Name:      name,
-				StartLine: line,
-				EndLine:   line,
+				File:        path,
+				Kind:        kind,
+				Name:        name,
+				StartLine:   line,
+				EndLine:     endLine,
+				StartColumn: match[0] - lineOffsets[line-1] + 1,
+				EndColumn:   match[1] - lineOffsets[endLine-1] + 1,
+				StartByte:   match[0],
+				EndByte:     match[1],
 			})
 		}
 	}
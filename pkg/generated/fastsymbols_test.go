@@ -115,5 +115,8 @@ func Bar() {}
 		if s.Name == "Bar" && s.StartLine != 5 {
 			t.Errorf("Bar StartLine = %d, want 5", s.StartLine)
 		}
+		if s.StartColumn != 1 || s.EndByte <= s.StartByte || string(src[s.StartByte:s.EndByte]) == "" {
+			t.Errorf("%s: columns [%d,%d) bytes [%d,%d)", s.Name, s.StartColumn, s.EndColumn, s.StartByte, s.EndByte)
+		}
 	}
 }
@@ -0,0 +1,220 @@
+// Package gts is the embeddable Go API for gts-suite. A Client wraps a
+// structural index of one workspace and answers the same map, reference,
+// scope, and context queries as the gts CLI, without shelling out.
+//
+//	client, err := gts.Open(".")
+//	if err != nil {
+//		return err
+//	}
+//	refs, err := client.Refs("ParseConfig", gts.RefsOptions{})
+package gts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/contextpack"
+	"github.com/odvcencio/gts-suite/internal/scope"
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// ScopeOptions selects the cursor position for Client.Scope.
+type ScopeOptions = scope.Options
+
+// ScopeReport lists the symbols visible at a cursor position.
+type ScopeReport = scope.Report
+
+// ScopeSymbol is one entry of a ScopeReport.
+type ScopeSymbol = scope.Symbol
+
+// ContextOptions selects the focus position and token budget for Client.Context.
+type ContextOptions = contextpack.Options
+
+// ContextReport is a focused, token-budgeted source snippet with related symbols.
+type ContextReport = contextpack.Report
+
+// Options configures how Open obtains the index.
+type Options struct {
+	// CachePath loads a saved index instead of parsing the workspace.
+	CachePath string
+	// NoCache skips auto-discovery of <root>/.gts/index.json.
+	NoCache bool
+}
+
+// RefsOptions filters Client.Refs results.
+type RefsOptions struct {
+	// Regex treats the name as a regular expression.
+	Regex bool
+	// Language restricts matches to files of one language (e.g. "go").
+	Language string
+	// Limit caps the number of matches; zero means unlimited.
+	Limit int
+}
+
+// Client answers structural queries against one workspace index. It is safe
+// for concurrent use; Refresh swaps the index atomically for readers.
+type Client struct {
+	root  string
+	store *index.Store
+}
+
+// Open indexes root, reusing a cached index under root/.gts when its config
+// hashes still match the workspace.
+func Open(root string) (*Client, error) {
+	return OpenWithOptions(root, Options{})
+}
+
+// OpenWithOptions is Open with explicit cache control.
+func OpenWithOptions(root string, opts Options) (*Client, error) {
+	if strings.TrimSpace(root) == "" {
+		root = "."
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := loadIndex(absRoot, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{root: absRoot, store: index.NewStore(idx)}, nil
+}
+
+// NewClient wraps an index that was built or loaded elsewhere.
+func NewClient(idx *model.Index) (*Client, error) {
+	if idx == nil {
+		return nil, errors.New("index is nil")
+	}
+	return &Client{root: idx.Root, store: index.NewStore(idx)}, nil
+}
+
+func loadIndex(root string, opts Options) (*model.Index, error) {
+	if strings.TrimSpace(opts.CachePath) != "" {
+		return index.Load(opts.CachePath)
+	}
+	if !opts.NoCache {
+		cachePath := filepath.Join(root, ".gts", "index.json")
+		if _, err := os.Stat(cachePath); err == nil {
+			if idx, loadErr := index.Load(cachePath); loadErr == nil && cacheFresh(root, idx) {
+				return idx, nil
+			}
+		}
+	}
+	builder, err := index.NewBuilderWithWorkspaceIgnores(root)
+	if err != nil {
+		return nil, err
+	}
+	return builder.BuildPath(root)
+}
+
+func cacheFresh(root string, idx *model.Index) bool {
+	if idx.ConfigHashes == nil {
+		return true
+	}
+	current, err := index.ComputeConfigHashes(root)
+	if err != nil || len(current) != len(idx.ConfigHashes) {
+		return false
+	}
+	for key, value := range idx.ConfigHashes {
+		if current[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Root returns the absolute workspace root.
+func (c *Client) Root() string {
+	return c.root
+}
+
+// Index returns the current index. Callers must not mutate it.
+func (c *Client) Index() *model.Index {
+	return c.store.Snapshot()
+}
+
+// Refresh incrementally re-indexes the workspace, reparsing only files whose
+// size or modification time changed.
+func (c *Client) Refresh(ctx context.Context) error {
+	builder, err := index.NewBuilderWithWorkspaceIgnores(c.root)
+	if err != nil {
+		return err
+	}
+	_, err = c.store.Update(func(current *model.Index) (*model.Index, error) {
+		next, _, err := builder.BuildPathIncremental(ctx, c.root, current)
+		return next, err
+	})
+	return err
+}
+
+// Map returns the structural summary of every indexed file: its language,
+// imports, and symbols, ordered by path.
+func (c *Client) Map() []model.FileSummary {
+	return append([]model.FileSummary(nil), c.Index().Files...)
+}
+
+// Refs returns indexed references whose name matches name, ordered by file,
+// line, and column.
+func (c *Client) Refs(name string, opts RefsOptions) ([]model.Reference, error) {
+	pattern := strings.TrimSpace(name)
+	if pattern == "" {
+		return nil, errors.New("reference matcher cannot be empty")
+	}
+	match := func(candidate string) bool { return candidate == pattern }
+	if opts.Regex {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile regex: %w", err)
+		}
+		match = compiled.MatchString
+	}
+
+	matches := make([]model.Reference, 0, 64)
+	for _, file := range c.Index().Files {
+		if opts.Language != "" && !strings.EqualFold(file.Language, opts.Language) {
+			continue
+		}
+		for _, reference := range file.References {
+			if !match(reference.Name) {
+				continue
+			}
+			reference.File = file.Path
+			matches = append(matches, reference)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].File != matches[j].File {
+			return matches[i].File < matches[j].File
+		}
+		if matches[i].StartLine != matches[j].StartLine {
+			return matches[i].StartLine < matches[j].StartLine
+		}
+		if matches[i].StartColumn != matches[j].StartColumn {
+			return matches[i].StartColumn < matches[j].StartColumn
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+	return matches, nil
+}
+
+// Scope resolves the symbols visible at a file position.
+func (c *Client) Scope(opts ScopeOptions) (ScopeReport, error) {
+	return scope.Build(c.Index(), opts)
+}
+
+// Context packs focused source context around a file position.
+func (c *Client) Context(opts ContextOptions) (ContextReport, error) {
+	return contextpack.Build(c.Index(), opts)
+}
@@ -0,0 +1,105 @@
+package gts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSample(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "main.go")
+	source := `package sample
+
+func helper() int { return 1 }
+
+func Run() int {
+	value := helper()
+	return value
+}
+`
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return path
+}
+
+func TestClientQueries(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := writeSample(t, tmpDir)
+
+	client, err := OpenWithOptions(tmpDir, Options{NoCache: true})
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+
+	files := client.Map()
+	if len(files) != 1 || files[0].Path != "main.go" {
+		t.Fatalf("expected map with main.go, got %#v", files)
+	}
+
+	refs, err := client.Refs("helper", RefsOptions{})
+	if err != nil {
+		t.Fatalf("Refs returned error: %v", err)
+	}
+	if len(refs) == 0 || refs[0].File != "main.go" {
+		t.Fatalf("expected helper references in main.go, got %#v", refs)
+	}
+	if _, err := client.Refs("(", RefsOptions{Regex: true}); err == nil {
+		t.Fatal("expected invalid regex to fail")
+	}
+
+	scopeReport, err := client.Scope(ScopeOptions{FilePath: sourcePath, Line: 7})
+	if err != nil {
+		t.Fatalf("Scope returned error: %v", err)
+	}
+	found := false
+	for _, symbol := range scopeReport.Symbols {
+		if symbol.Name == "value" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected value in scope, got %#v", scopeReport.Symbols)
+	}
+
+	contextReport, err := client.Context(ContextOptions{FilePath: "main.go", Line: 6})
+	if err != nil {
+		t.Fatalf("Context returned error: %v", err)
+	}
+	if contextReport.Focus == nil || contextReport.Focus.Name != "Run" {
+		t.Fatalf("expected focus Run, got %#v", contextReport.Focus)
+	}
+}
+
+func TestClientRefresh(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeSample(t, tmpDir)
+
+	client, err := OpenWithOptions(tmpDir, Options{NoCache: true})
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+
+	extra := filepath.Join(tmpDir, "extra.go")
+	if err := os.WriteFile(extra, []byte("package sample\n\nfunc Extra() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	future := time.Now().Add(time.Minute)
+	_ = os.Chtimes(extra, future, future)
+
+	if err := client.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh returned error: %v", err)
+	}
+	if got := len(client.Map()); got != 2 {
+		t.Fatalf("expected 2 files after refresh, got %d", got)
+	}
+}
+
+func TestNewClientRejectsNilIndex(t *testing.T) {
+	if _, err := NewClient(nil); err == nil {
+		t.Fatal("expected nil index to fail")
+	}
+}
@@ -0,0 +1,19 @@
+package gts
+
+import "github.com/odvcencio/gts-suite/internal/lint"
+
+// LintRuleFunc implements a custom structural lint rule.
+type LintRuleFunc = lint.RuleFunc
+
+// LintRuleContext gives a LintRuleFunc access to the index and syntax trees.
+type LintRuleContext = lint.RuleContext
+
+// LintViolation is a single lint finding.
+type LintViolation = lint.Violation
+
+// RegisterLintRule registers a custom rule that every gts lint run evaluates,
+// alongside the built-in rules. Call it from init in a program that embeds
+// gts-suite, e.g. to require that handlers call audit.Log.
+func RegisterLintRule(id string, fn LintRuleFunc) error {
+	return lint.RegisterRule(id, fn)
+}
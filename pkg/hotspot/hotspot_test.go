@@ -2,7 +2,11 @@ package hotspot
 
 import (
 	"math"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
 )
 
 func TestParseGitLogEmpty(t *testing.T) {
@@ -194,3 +198,45 @@ func TestRankCentrality(t *testing.T) {
 		t.Errorf("centrality rank: %f should be < %f", hotspots[0].Centrality, hotspots[2].Centrality)
 	}
 }
+
+func TestUsageRanksByCallersAndPackages(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module sample\n\ngo 1.22\n",
+		"core/core.go": "package core\n\nfunc Shared() {}\n\nfunc Local() {}\n\nfunc use() {\n\tShared()\n\tLocal()\n}\n",
+		"api/api.go":   "package api\n\nimport \"sample/core\"\n\nfunc Handle() {\n\tcore.Shared()\n}\n",
+		"web/web.go":   "package web\n\nimport \"sample/core\"\n\nfunc Serve() {\n\tcore.Shared()\n}\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx, err := index.NewBuilder().BuildPath(root)
+	if err != nil {
+		t.Fatalf("BuildPath: %v", err)
+	}
+
+	report, err := Usage(idx, UsageOptions{NoGit: true})
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	if report.Churn || report.Count != 2 {
+		t.Fatalf("expected 2 definitions without churn, got %+v", report)
+	}
+	top := report.Definitions[0]
+	if top.Name != "Shared" || top.References != 3 || top.Callers != 3 || len(top.CallerPackages) != 3 {
+		t.Fatalf("expected Shared called from 3 packages first, got %+v", top)
+	}
+	if report.Definitions[1].Name != "Local" || report.Definitions[1].Score >= top.Score {
+		t.Fatalf("expected Local ranked below Shared, got %+v", report.Definitions[1])
+	}
+
+	if _, err := Usage(idx, UsageOptions{NoGit: true, Sort: "bogus"}); err == nil {
+		t.Fatal("expected an unsupported sort error")
+	}
+}
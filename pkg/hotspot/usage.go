@@ -0,0 +1,180 @@
+package hotspot
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// UsageEntry ranks one callable definition by how heavily the rest of the
+// code depends on it.
+type UsageEntry struct {
+	Name           string   `json:"name"`
+	Signature      string   `json:"signature,omitempty"`
+	File           string   `json:"file"`
+	Package        string   `json:"package"`
+	Kind           string   `json:"kind"`
+	StartLine      int      `json:"start_line"`
+	EndLine        int      `json:"end_line"`
+	References     int      `json:"references"`      // raw incoming calls
+	Callers        int      `json:"callers"`         // distinct calling definitions
+	CallerPackages []string `json:"caller_packages"` // distinct calling packages
+	Commits        int      `json:"commits,omitempty"`
+	Authors        int      `json:"authors,omitempty"`
+	Score          float64  `json:"score"` // geometric mean of percentile ranks
+}
+
+// UsageReport is the result of Usage.
+type UsageReport struct {
+	Definitions []UsageEntry `json:"definitions"`
+	Count       int          `json:"count"`
+	// Churn reports whether git history contributed to the scores.
+	Churn bool `json:"churn"`
+}
+
+// UsageOptions controls Usage.
+type UsageOptions struct {
+	Root          string // git repo root for churn data
+	Since         string // git log --since period
+	NoGit         bool   // rank by references and packages only
+	MinReferences int    // drop definitions with fewer incoming calls
+	Top           int    // limit to top N results
+	Sort          string // score (default), refs, packages, or churn
+}
+
+// Usage ranks callable definitions by incoming calls, the number of distinct
+// packages calling them, and, unless opts.NoGit is set, the git churn of
+// their files. The top of the ranking is the load-bearing code where a
+// change reaches the most callers.
+func Usage(idx *model.Index, opts UsageOptions) (*UsageReport, error) {
+	report := &UsageReport{Definitions: []UsageEntry{}}
+	if idx == nil {
+		return report, nil
+	}
+	switch opts.Sort {
+	case "", "score", "refs", "packages", "churn":
+	default:
+		return nil, fmt.Errorf("unsupported sort %q (expected score|refs|packages|churn)", opts.Sort)
+	}
+
+	graph, err := xref.Build(idx)
+	if err != nil {
+		return nil, fmt.Errorf("xref build: %w", err)
+	}
+
+	churnMap := map[string]FileChurn{}
+	if !opts.NoGit {
+		root := opts.Root
+		if root == "" {
+			root = idx.Root
+		}
+		if rawChurn, err := GitChurn(root, opts.Since); err == nil {
+			churnMap = normalizeChurnMap(rawChurn, root, resolveGitRoot(root))
+			report.Churn = true
+		}
+	}
+
+	entries := make([]UsageEntry, 0, 128)
+	for _, def := range graph.Definitions {
+		if !def.Callable {
+			continue
+		}
+		refs := graph.IncomingCount(def.ID)
+		if refs == 0 || refs < opts.MinReferences {
+			continue
+		}
+		callers := map[string]bool{}
+		packages := map[string]bool{}
+		for _, edge := range graph.IncomingEdges(def.ID) {
+			caller := graph.EdgeCaller(edge)
+			callers[caller.ID] = true
+			packages[caller.Package] = true
+		}
+		callerPackages := make([]string, 0, len(packages))
+		for pkg := range packages {
+			callerPackages = append(callerPackages, pkg)
+		}
+		sort.Strings(callerPackages)
+
+		fileChurn := churnMap[def.File]
+		entries = append(entries, UsageEntry{
+			Name:           def.Name,
+			Signature:      def.Signature,
+			File:           def.File,
+			Package:        def.Package,
+			Kind:           def.Kind,
+			StartLine:      def.StartLine,
+			EndLine:        def.EndLine,
+			References:     refs,
+			Callers:        len(callers),
+			CallerPackages: callerPackages,
+			Commits:        fileChurn.Commits,
+			Authors:        fileChurn.Authors,
+		})
+	}
+	if len(entries) == 0 {
+		return report, nil
+	}
+
+	refRanks := make([]float64, len(entries))
+	pkgRanks := make([]float64, len(entries))
+	churnRanks := make([]float64, len(entries))
+	for i, e := range entries {
+		refRanks[i] = float64(e.References)
+		pkgRanks[i] = float64(len(e.CallerPackages))
+		churnRanks[i] = churnValue(e)
+	}
+	refRanks = percentileRank(refRanks)
+	pkgRanks = percentileRank(pkgRanks)
+	churnRanks = percentileRank(churnRanks)
+	for i := range entries {
+		if report.Churn {
+			entries[i].Score = geometricMean(refRanks[i], pkgRanks[i], churnRanks[i])
+		} else {
+			// With churn pinned at 1, the mean covers the other two dimensions.
+			entries[i].Score = geometricMean(refRanks[i], pkgRanks[i], 1)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch opts.Sort {
+		case "refs":
+			if a.References != b.References {
+				return a.References > b.References
+			}
+		case "packages":
+			if len(a.CallerPackages) != len(b.CallerPackages) {
+				return len(a.CallerPackages) > len(b.CallerPackages)
+			}
+		case "churn":
+			if churnValue(a) != churnValue(b) {
+				return churnValue(a) > churnValue(b)
+			}
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.References != b.References {
+			return a.References > b.References
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.StartLine < b.StartLine
+	})
+
+	if opts.Top > 0 && opts.Top < len(entries) {
+		entries = entries[:opts.Top]
+	}
+	report.Definitions = entries
+	report.Count = len(entries)
+	return report, nil
+}
+
+// churnValue weighs commits and authors as rankChurn does.
+func churnValue(e UsageEntry) float64 {
+	return float64(e.Commits) + float64(e.Authors)*0.5
+}
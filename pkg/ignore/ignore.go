@@ -9,10 +9,12 @@ import (
 )
 
 type pattern struct {
-	raw     string
-	negated bool
-	dirOnly bool
-	glob    string
+	raw      string
+	negated  bool
+	dirOnly  bool
+	anchored bool
+	glob     string
+	base     string // directory the pattern is scoped to, "" for the root
 }
 
 // Matcher evaluates file paths against a set of gitignore-style patterns.
@@ -41,6 +43,18 @@ func Load(path string) (*Matcher, error) {
 
 // ParsePatterns builds a Matcher from raw pattern lines.
 func ParsePatterns(lines []string) *Matcher {
+	return ParsePatternsAt("", lines)
+}
+
+// ParsePatternsAt builds a Matcher from the pattern lines of an ignore file in
+// directory base (slash-separated, relative to the project root), such as a
+// nested .gitignore. Its patterns only apply to paths under base and are
+// matched relative to it.
+func ParsePatternsAt(base string, lines []string) *Matcher {
+	base = strings.Trim(filepath.ToSlash(base), "/")
+	if base == "." {
+		base = ""
+	}
 	m := &Matcher{}
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -48,7 +62,7 @@ func ParsePatterns(lines []string) *Matcher {
 			continue
 		}
 
-		p := pattern{raw: line}
+		p := pattern{raw: line, base: base}
 
 		if strings.HasPrefix(line, "!") {
 			p.negated = true
@@ -60,12 +74,37 @@ func ParsePatterns(lines []string) *Matcher {
 			line = strings.TrimSuffix(line, "/")
 		}
 
+		// A leading slash anchors the pattern to the ignore file's directory.
+		if strings.HasPrefix(line, "/") {
+			p.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
 		p.glob = line
 		m.patterns = append(m.patterns, p)
 	}
 	return m
 }
 
+// Merge returns a Matcher that evaluates the patterns of each matcher in
+// order, so later matchers can override earlier ones with negations. Nil
+// matchers are skipped; the result is nil when no matcher has patterns.
+func Merge(matchers ...*Matcher) *Matcher {
+	merged := &Matcher{}
+	for _, m := range matchers {
+		if m != nil {
+			merged.patterns = append(merged.patterns, m.patterns...)
+		}
+	}
+	if len(merged.patterns) == 0 {
+		return nil
+	}
+	return merged
+}
+
 // Match returns true if the given path should be ignored.
 // The path should be slash-separated and relative to the project root.
 // isDir indicates whether the path refers to a directory.
@@ -86,25 +125,48 @@ func (m *Matcher) Match(path string, isDir bool) bool {
 }
 
 func matchesPattern(p pattern, path string, isDir bool) bool {
+	if p.base != "" {
+		rest, ok := strings.CutPrefix(path, p.base+"/")
+		if !ok {
+			return false
+		}
+		path = rest
+	}
 	if p.dirOnly {
-		return matchDirectoryPattern(p.glob, path, isDir)
+		return matchDirectoryPattern(p, path, isDir)
 	}
-	return matchPattern(p.glob, path)
+	if matchPatternAt(p, path) {
+		return true
+	}
+	// Anything inside a matched directory is ignored too.
+	for _, dir := range ancestorDirectories(path) {
+		if matchPatternAt(p, dir) {
+			return true
+		}
+	}
+	return false
 }
 
-func matchDirectoryPattern(glob, path string, isDir bool) bool {
+func matchDirectoryPattern(p pattern, path string, isDir bool) bool {
 	if isDir {
-		return matchPattern(glob, path)
+		return matchPatternAt(p, path)
 	}
 
 	for _, dir := range ancestorDirectories(path) {
-		if matchPattern(glob, dir) {
+		if matchPatternAt(p, dir) {
 			return true
 		}
 	}
 	return false
 }
 
+func matchPatternAt(p pattern, path string) bool {
+	if p.anchored {
+		return matchPathGlob(p.glob, path)
+	}
+	return matchPattern(p.glob, path)
+}
+
 func ancestorDirectories(path string) []string {
 	path = filepath.ToSlash(strings.TrimSpace(path))
 	if path == "" || path == "." {
@@ -128,8 +190,7 @@ func ancestorDirectories(path string) []string {
 // Patterns with a slash match against the full path.
 func matchPattern(glob, path string) bool {
 	if strings.Contains(glob, "/") {
-		matched, _ := filepath.Match(glob, path)
-		return matched
+		return matchPathGlob(glob, path)
 	}
 
 	// Match against basename
@@ -147,3 +208,30 @@ func matchPattern(glob, path string) bool {
 	}
 	return false
 }
+
+// matchPathGlob matches glob against the whole path, one segment at a time,
+// where a "**" segment matches any number of directories.
+func matchPathGlob(glob, path string) bool {
+	return matchSegments(strings.Split(glob, "/"), strings.Split(path, "/"))
+}
+
+func matchSegments(glob, path []string) bool {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for skip := 0; skip <= len(path); skip++ {
+				if matchSegments(glob[1:], path[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 {
+			return false
+		}
+		if matched, _ := filepath.Match(glob[0], path[0]); !matched {
+			return false
+		}
+		glob, path = glob[1:], path[1:]
+	}
+	return len(path) == 0
+}
@@ -132,3 +132,48 @@ func TestLoad_NotFound(t *testing.T) {
 		t.Error("expected error for missing file")
 	}
 }
+
+func TestMatch_AnchoredPattern(t *testing.T) {
+	m := ParsePatterns([]string{"/dist"})
+	if !m.Match("dist/app.js", false) {
+		t.Error("expected anchored pattern to match at the root")
+	}
+	if m.Match("web/dist/app.js", false) {
+		t.Error("unexpected match of anchored pattern below the root")
+	}
+}
+
+func TestMatch_DoubleStar(t *testing.T) {
+	m := ParsePatterns([]string{"**/gen/*.go", "docs/**/*.md"})
+	for _, path := range []string{"gen/a.go", "pkg/api/gen/a.go", "docs/a.md", "docs/x/y/a.md"} {
+		if !m.Match(path, false) {
+			t.Errorf("expected match on %s", path)
+		}
+	}
+	if m.Match("pkg/gen.go", false) {
+		t.Error("unexpected match on pkg/gen.go")
+	}
+}
+
+func TestParsePatternsAt_ScopesToBase(t *testing.T) {
+	m := ParsePatternsAt("web", []string{"dist/", "/local.js"})
+	if !m.Match("web/dist/app.js", false) || !m.Match("web/pkg/dist/app.js", false) {
+		t.Error("expected nested pattern to match under its directory")
+	}
+	if m.Match("dist/app.js", false) {
+		t.Error("unexpected match outside the pattern's directory")
+	}
+	if !m.Match("web/local.js", false) || m.Match("web/pkg/local.js", false) {
+		t.Error("expected anchored nested pattern to match only at its directory")
+	}
+}
+
+func TestMerge_LaterNegationWins(t *testing.T) {
+	m := Merge(nil, ParsePatterns([]string{"dist/"}), ParsePatterns([]string{"!dist/"}))
+	if m.Match("dist/app.js", false) {
+		t.Error("expected later negation to re-include dist/")
+	}
+	if Merge(nil, ParsePatterns(nil)) != nil {
+		t.Error("expected nil when no matcher has patterns")
+	}
+}
@@ -17,6 +17,7 @@ import (
 type AffectedSymbol struct {
 	Name      string  `json:"name"`
 	File      string  `json:"file"`
+	Package   string  `json:"package,omitempty"`
 	Kind      string  `json:"kind"`
 	StartLine int     `json:"start_line"`
 	EndLine   int     `json:"end_line"`
@@ -26,10 +27,12 @@ type AffectedSymbol struct {
 
 // Result contains the full impact analysis output.
 type Result struct {
-	Changed       []string         `json:"changed"`
-	Affected      []AffectedSymbol `json:"affected"`
-	AffectedFiles []string         `json:"affected_files"`
-	TotalAffected int              `json:"total_affected"`
+	Changed          []string         `json:"changed"`
+	Changes          []Change         `json:"changes,omitempty"`
+	Affected         []AffectedSymbol `json:"affected"`
+	AffectedFiles    []string         `json:"affected_files"`
+	AffectedPackages []string         `json:"affected_packages,omitempty"`
+	TotalAffected    int              `json:"total_affected"`
 }
 
 // Options configures the impact analysis.
@@ -71,10 +74,17 @@ func Analyze(idx *model.Index, opts Options) (*Result, error) {
 		}, nil
 	}
 
+	result := &Result{Changed: changedNames}
+	result.setAffected(callersOf(&graph, changedDefs, maxDepth))
+	return result, nil
+}
+
+// callersOf returns the transitive callers of defs, excluding defs themselves.
+func callersOf(graph *xref.Graph, defs []xref.Definition, maxDepth int) []AffectedSymbol {
 	// Collect root IDs and build a set for exclusion.
-	rootIDs := make([]string, 0, len(changedDefs))
+	rootIDs := make([]string, 0, len(defs))
 	rootSet := map[string]bool{}
-	for _, def := range changedDefs {
+	for _, def := range defs {
 		rootIDs = append(rootIDs, def.ID)
 		rootSet[def.ID] = true
 	}
@@ -83,11 +93,9 @@ func Analyze(idx *model.Index, opts Options) (*Result, error) {
 	walk := graph.Walk(rootIDs, maxDepth, true)
 
 	// BFS to compute distances from changed symbols.
-	distances := bfsDistances(&graph, rootIDs, maxDepth)
+	distances := bfsDistances(graph, rootIDs, maxDepth)
 
-	// Build affected list excluding the changed symbols themselves.
 	affected := make([]AffectedSymbol, 0, len(walk.Nodes))
-	fileSet := map[string]bool{}
 	for _, node := range walk.Nodes {
 		if rootSet[node.ID] {
 			continue
@@ -99,15 +107,19 @@ func Analyze(idx *model.Index, opts Options) (*Result, error) {
 		affected = append(affected, AffectedSymbol{
 			Name:      node.Name,
 			File:      node.File,
+			Package:   node.Package,
 			Kind:      node.Kind,
 			StartLine: node.StartLine,
 			EndLine:   node.EndLine,
 			Distance:  dist,
 			Risk:      1.0 / float64(dist+1),
 		})
-		fileSet[node.File] = true
 	}
+	return affected
+}
 
+// setAffected sorts affected and fills the derived file and package lists.
+func (r *Result) setAffected(affected []AffectedSymbol) {
 	sort.Slice(affected, func(i, j int) bool {
 		if affected[i].Distance != affected[j].Distance {
 			return affected[i].Distance < affected[j].Distance
@@ -118,18 +130,28 @@ func Analyze(idx *model.Index, opts Options) (*Result, error) {
 		return affected[i].StartLine < affected[j].StartLine
 	})
 
-	affectedFiles := make([]string, 0, len(fileSet))
-	for file := range fileSet {
-		affectedFiles = append(affectedFiles, file)
+	fileSet := map[string]bool{}
+	packageSet := map[string]bool{}
+	for _, symbol := range affected {
+		fileSet[symbol.File] = true
+		if symbol.Package != "" {
+			packageSet[symbol.Package] = true
+		}
 	}
-	sort.Strings(affectedFiles)
-
-	return &Result{
-		Changed:       changedNames,
-		Affected:      affected,
-		AffectedFiles: affectedFiles,
-		TotalAffected: len(affected),
-	}, nil
+
+	r.Affected = affected
+	r.AffectedFiles = sortedKeys(fileSet)
+	r.AffectedPackages = sortedKeys(packageSet)
+	r.TotalAffected = len(affected)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // resolveChanged resolves changed symbol names or diff refs into xref definitions.
@@ -325,10 +347,5 @@ func MatchDiffToSymbols(idx *model.Index, diffText string) []string {
 }
 
 func isCallableKind(kind string) bool {
-	switch kind {
-	case "function_definition", "method_definition":
-		return true
-	default:
-		return false
-	}
+	return model.IsFunctionKind(kind) || kind == "method_definition"
 }
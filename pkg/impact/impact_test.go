@@ -202,3 +202,53 @@ index abc1234..def5678 100644
 		t.Errorf("did not expect parseArgs (out of range) in changed symbols, got %v", names)
 	}
 }
+
+func TestAnalyzeSnapshots(t *testing.T) {
+	before := &model.Index{
+		Files: []model.FileSummary{
+			{
+				Path: "api/handler.go",
+				Symbols: []model.Symbol{
+					{File: "api/handler.go", Kind: "function_definition", Name: "Handle", StartLine: 1, EndLine: 5},
+					{File: "api/handler.go", Kind: "function_definition", Name: "Legacy", StartLine: 7, EndLine: 9},
+				},
+				References: []model.Reference{
+					{File: "api/handler.go", Kind: "reference.call", Name: "Validate", StartLine: 2},
+					{File: "api/handler.go", Kind: "reference.call", Name: "oldHelper", StartLine: 8},
+				},
+			},
+			{
+				Path: "core/validate.go",
+				Symbols: []model.Symbol{
+					{File: "core/validate.go", Kind: "function_definition", Name: "Validate", Signature: "func Validate(x int) error", StartLine: 1, EndLine: 3},
+					{File: "core/validate.go", Kind: "function_definition", Name: "oldHelper", StartLine: 5, EndLine: 6},
+				},
+			},
+		},
+	}
+	after := &model.Index{
+		Files: []model.FileSummary{
+			before.Files[0],
+			{
+				Path: "core/validate.go",
+				Symbols: []model.Symbol{
+					{File: "core/validate.go", Kind: "function_definition", Name: "Validate", Signature: "func Validate(x, y int) error", StartLine: 1, EndLine: 3},
+				},
+			},
+		},
+	}
+
+	result, err := AnalyzeSnapshots(before, after, 3)
+	if err != nil {
+		t.Fatalf("AnalyzeSnapshots returned error: %v", err)
+	}
+	if len(result.Changes) != 2 || result.Changes[0].Change != "modified" || result.Changes[1].Change != "removed" {
+		t.Fatalf("unexpected changes %+v", result.Changes)
+	}
+	if result.TotalAffected != 2 {
+		t.Fatalf("expected Handle and Legacy affected, got %+v", result.Affected)
+	}
+	if len(result.AffectedPackages) != 1 || result.AffectedPackages[0] != "api" {
+		t.Fatalf("unexpected affected packages %v", result.AffectedPackages)
+	}
+}
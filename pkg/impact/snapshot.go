@@ -0,0 +1,159 @@
+package impact
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/structdiff"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// Change is one definition that differs between two snapshots.
+type Change struct {
+	Change    string   `json:"change"` // "added", "removed", or "modified"
+	Name      string   `json:"name"`
+	File      string   `json:"file"`
+	Kind      string   `json:"kind"`
+	StartLine int      `json:"start_line"`
+	EndLine   int      `json:"end_line"`
+	Fields    []string `json:"fields,omitempty"`
+}
+
+// AnalyzeSnapshots computes the blast radius of the structural differences
+// between two indexes. Callers of added and modified definitions are found in
+// after; callers of removed definitions are found in before, since they are
+// the code that loses its callee.
+func AnalyzeSnapshots(before, after *model.Index, maxDepth int) (*Result, error) {
+	if before == nil || after == nil {
+		return nil, fmt.Errorf("both before and after indexes are required")
+	}
+	if maxDepth <= 0 {
+		maxDepth = 10
+	}
+
+	beforeGraph, err := xref.Build(before)
+	if err != nil {
+		return nil, fmt.Errorf("build before xref graph: %w", err)
+	}
+	afterGraph, err := xref.Build(after)
+	if err != nil {
+		return nil, fmt.Errorf("build after xref graph: %w", err)
+	}
+
+	report := structdiff.Compare(before, after)
+	changes := make([]Change, 0, report.Stats.AddedSymbols+report.Stats.RemovedSymbols+report.Stats.ModifiedSymbols)
+	afterRefs := make([]structdiff.SymbolRef, 0, report.Stats.AddedSymbols+report.Stats.ModifiedSymbols)
+	for _, symbol := range report.AddedSymbols {
+		changes = append(changes, changeFromRef("added", symbol, nil))
+		afterRefs = append(afterRefs, symbol)
+	}
+	for _, modified := range report.ModifiedSymbols {
+		if onlyShifted(modified) {
+			continue
+		}
+		changes = append(changes, changeFromRef("modified", modified.After, modified.Fields))
+		afterRefs = append(afterRefs, modified.After)
+	}
+	for _, symbol := range report.RemovedSymbols {
+		changes = append(changes, changeFromRef("removed", symbol, nil))
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].File != changes[j].File {
+			return changes[i].File < changes[j].File
+		}
+		return changes[i].StartLine < changes[j].StartLine
+	})
+
+	affected := mergeAffected(
+		callersOf(&afterGraph, definitionsFor(afterGraph, afterRefs), maxDepth),
+		callersOf(&beforeGraph, definitionsFor(beforeGraph, report.RemovedSymbols), maxDepth),
+		changes,
+	)
+
+	result := &Result{Changes: changes}
+	seen := map[string]bool{}
+	for _, change := range changes {
+		if !seen[change.Name] {
+			seen[change.Name] = true
+			result.Changed = append(result.Changed, change.Name)
+		}
+	}
+	if result.Changed == nil {
+		result.Changed = []string{}
+	}
+	result.setAffected(affected)
+	return result, nil
+}
+
+// onlyShifted reports whether a modification is just the definition moving
+// because lines above it changed.
+func onlyShifted(modified structdiff.ModifiedSymbol) bool {
+	if len(modified.Fields) != 1 || modified.Fields[0] != "span" {
+		return false
+	}
+	return modified.Before.EndLine-modified.Before.StartLine == modified.After.EndLine-modified.After.StartLine
+}
+
+func changeFromRef(kind string, symbol structdiff.SymbolRef, fields []string) Change {
+	return Change{
+		Change:    kind,
+		Name:      symbol.Name,
+		File:      symbol.File,
+		Kind:      symbol.Kind,
+		StartLine: symbol.StartLine,
+		EndLine:   symbol.EndLine,
+		Fields:    fields,
+	}
+}
+
+// definitionsFor maps diffed symbols back to call graph definitions.
+func definitionsFor(graph xref.Graph, symbols []structdiff.SymbolRef) []xref.Definition {
+	if len(symbols) == 0 {
+		return nil
+	}
+	wanted := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		wanted[symbolKey(symbol.File, symbol.Kind, symbol.Name, symbol.StartLine)] = true
+	}
+	defs := make([]xref.Definition, 0, len(symbols))
+	for _, def := range graph.Definitions {
+		if wanted[symbolKey(def.File, def.Kind, def.Name, def.StartLine)] {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+// mergeAffected combines caller sets, keeping the shortest distance for a
+// symbol reached from both snapshots and dropping symbols that are
+// themselves changed.
+func mergeAffected(afterSide, beforeSide []AffectedSymbol, changes []Change) []AffectedSymbol {
+	changed := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		changed[change.File+"|"+change.Kind+"|"+change.Name] = true
+	}
+
+	byKey := map[string]int{}
+	merged := make([]AffectedSymbol, 0, len(afterSide)+len(beforeSide))
+	for _, symbol := range append(afterSide, beforeSide...) {
+		key := symbol.File + "|" + symbol.Kind + "|" + symbol.Name
+		if changed[key] {
+			continue
+		}
+		if i, ok := byKey[key]; ok {
+			if symbol.Distance < merged[i].Distance {
+				merged[i] = symbol
+			}
+			continue
+		}
+		byKey[key] = len(merged)
+		merged = append(merged, symbol)
+	}
+	return merged
+}
+
+func symbolKey(file, kind, name string, line int) string {
+	return file + "|" + kind + "|" + name + "|" + strconv.Itoa(line)
+}
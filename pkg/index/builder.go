@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/odvcencio/gotreesitter"
@@ -17,31 +18,47 @@ import (
 	"github.com/odvcencio/gts-suite/pkg/generated"
 	"github.com/odvcencio/gts-suite/pkg/ignore"
 	"github.com/odvcencio/gts-suite/pkg/lang"
+	"github.com/odvcencio/gts-suite/pkg/lang/notebook"
 	"github.com/odvcencio/gts-suite/pkg/lang/treesitter"
+	"github.com/odvcencio/gts-suite/pkg/lang/vue"
 	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
 )
 
 const schemaVersion = "0.2.0"
 
+// SchemaVersion returns the index format version that builds write and Load
+// accepts.
+func SchemaVersion() string { return schemaVersion }
+
 type Builder struct {
-	parsers      map[string]lang.Parser
-	ignore       *ignore.Matcher
-	detector     *generated.Detector
-	configHashes map[string]string
+	parsers        map[string]lang.Parser
+	ignore         *ignore.Matcher // gitignore then user patterns; see Ignore
+	userIgnore     *ignore.Matcher
+	gitignore      *ignore.Matcher
+	detector       *generated.Detector
+	configHashes   map[string]string
+	followSymlinks bool
+	skipGenerated  bool
+	maxFileSize    int64
+	maxConcurrent  int
 }
 
 // SetConfigHashes stores pre-computed config file hashes to embed in built indexes.
 func (b *Builder) SetConfigHashes(h map[string]string) { b.configHashes = h }
 
 type BuildStats struct {
-	CandidateFiles int `json:"candidate_files"`
-	ParsedFiles    int `json:"parsed_files"`
-	ReusedFiles    int `json:"reused_files"`
+	CandidateFiles   int `json:"candidate_files"`
+	ParsedFiles      int `json:"parsed_files"`
+	ReusedFiles      int `json:"reused_files"`
+	SkippedGenerated int `json:"skipped_generated,omitempty"`
+	SkippedFiles     int `json:"skipped_files,omitempty"`
 }
 
 func NewBuilder() *Builder {
 	builder := &Builder{
-		parsers: make(map[string]lang.Parser),
+		parsers:     make(map[string]lang.Parser),
+		maxFileSize: DefaultMaxFileSize,
 	}
 	builder.registerTreesitterParsers()
 	return builder
@@ -75,6 +92,32 @@ func (b *Builder) registerTreesitterParsers() {
 			b.parsers[normalized] = lp
 		}
 	}
+	for ext, alias := range extensionAliases {
+		if parser, ok := b.parsers[alias]; ok {
+			b.parsers[ext] = parser
+		}
+	}
+	b.registerWrappedParsers()
+}
+
+// extensionAliases maps extensions the grammars do not list to one of the
+// language they are written in.
+var extensionAliases = map[string]string{
+	".jsx": ".js",
+}
+
+// registerWrappedParsers registers the parsers of file types indexed with
+// another language's parser: notebooks as Python, and Vue components as
+// JavaScript or TypeScript.
+func (b *Builder) registerWrappedParsers() {
+	if python, ok := b.parsers[".py"]; ok {
+		b.parsers[notebook.Extension] = notebook.NewParser(python)
+	}
+	javascript, hasJS := b.parsers[".js"]
+	typescript, hasTS := b.parsers[".ts"]
+	if hasJS && hasTS {
+		b.parsers[vue.Extension] = vue.NewParser(javascript, typescript)
+	}
 }
 
 // fallbackTagsQueries provides custom tags queries for languages where
@@ -93,6 +136,7 @@ var fallbackTagsQueries = map[string]string{
 // grammars at NewBuilder time (the root cause of OOM on large repos).
 type lazyParser struct {
 	entry  grammars.LangEntry
+	extra  []string // tags query patterns added by AddTagsQuery
 	parser *treesitter.Parser
 	once   sync.Once
 	err    error
@@ -105,10 +149,13 @@ func newLazyParser(entry grammars.LangEntry) *lazyParser {
 func (lp *lazyParser) init() {
 	// Infer the tags query on demand (loads the grammar for this one language).
 	entry := lp.entry
-	entry.TagsQuery = grammars.ResolveTagsQuery(entry)
+	entry.TagsQuery = treesitter.ResolveTagsQuery(entry)
 	if strings.TrimSpace(entry.TagsQuery) == "" {
 		entry.TagsQuery = fallbackTagsQueries[entry.Name]
 	}
+	if len(lp.extra) > 0 {
+		entry.TagsQuery = strings.Join(append([]string{entry.TagsQuery}, lp.extra...), "\n")
+	}
 	if strings.TrimSpace(entry.TagsQuery) == "" {
 		lp.err = fmt.Errorf("no tags query available for %q", entry.Name)
 		return
@@ -116,6 +163,38 @@ func (lp *lazyParser) init() {
 	lp.parser, lp.err = treesitter.NewParser(entry)
 }
 
+// AddTagsQuery adds the patterns of query to the built-in tags query of
+// language, so files in it also yield the definitions and references query
+// captures with @definition.<kind> and @reference.<kind> tags. It loads the
+// grammar and returns an error when language is unknown or the combined
+// query does not compile.
+func (b *Builder) AddTagsQuery(language, query string) error {
+	language = strings.ToLower(strings.TrimSpace(language))
+	var base *lazyParser
+	for _, parser := range b.parsers {
+		if lp, ok := parser.(*lazyParser); ok && lp.entry.Name == language {
+			base = lp
+			break
+		}
+	}
+	if base == nil {
+		return fmt.Errorf("unknown language %q", language)
+	}
+
+	lp := newLazyParser(base.entry)
+	lp.extra = append(append([]string(nil), base.extra...), query)
+	if _, err := lp.TreesitterParser(); err != nil {
+		return err
+	}
+	for ext, parser := range b.parsers {
+		if parser == base {
+			b.parsers[ext] = lp
+		}
+	}
+	b.registerWrappedParsers()
+	return nil
+}
+
 func (lp *lazyParser) Language() string {
 	return lp.entry.Name
 }
@@ -138,10 +217,21 @@ func (lp *lazyParser) Parse(path string, src []byte) (model.FileSummary, error)
 
 // SetIgnore configures a .gtsignore-style matcher to skip paths during indexing.
 func (b *Builder) SetIgnore(m *ignore.Matcher) {
-	b.ignore = m
+	b.userIgnore = m
+	b.ignore = ignore.Merge(b.gitignore, b.userIgnore)
+}
+
+// SetGitignore configures the .gitignore patterns to skip during indexing.
+// They are evaluated before the SetIgnore patterns, so a .gtsignore negation
+// such as "!dist/" can bring a git-ignored path back. Pass nil to index
+// git-ignored files.
+func (b *Builder) SetGitignore(m *ignore.Matcher) {
+	b.gitignore = m
+	b.ignore = ignore.Merge(b.gitignore, b.userIgnore)
 }
 
-// Ignore returns the current ignore matcher, or nil if none is set.
+// Ignore returns the effective ignore matcher, combining .gitignore and
+// SetIgnore patterns, or nil if none is set.
 func (b *Builder) Ignore() *ignore.Matcher {
 	return b.ignore
 }
@@ -151,6 +241,29 @@ func (b *Builder) SetDetector(d *generated.Detector) {
 	b.detector = d
 }
 
+// SetFollowSymlinks makes directory builds index files reached through
+// symbolic links, under the link's path. Links are skipped by default.
+func (b *Builder) SetFollowSymlinks(follow bool) {
+	b.followSymlinks = follow
+}
+
+// SetSkipGenerated drops files the generated-file detector matches instead of
+// indexing them with a Generated annotation. A default detector is installed
+// if none is configured.
+func (b *Builder) SetSkipGenerated(skip bool) {
+	b.skipGenerated = skip
+	if skip && b.detector == nil {
+		b.detector = generated.NewDetector(nil)
+	}
+}
+
+// SetMaxConcurrent caps how many files are read and parsed at once, bounding
+// the syntax trees held in memory. Zero or a negative value keeps the gateway
+// default of GOMAXPROCS, or GTS_MAX_CONCURRENT when set.
+func (b *Builder) SetMaxConcurrent(n int) {
+	b.maxConcurrent = n
+}
+
 func (b *Builder) Register(extension string, parser lang.Parser) {
 	if parser == nil {
 		return
@@ -174,7 +287,13 @@ func normalizeExtension(extension string) string {
 }
 
 func (b *Builder) BuildPath(path string) (*model.Index, error) {
-	idx, _, err := b.BuildPathIncrementalWithOptions(context.Background(), path, nil, BuildOptions{})
+	return b.BuildPathContext(context.Background(), path)
+}
+
+// BuildPathContext is BuildPath with cancellation. When ctx is cancelled the
+// walk stops and the files indexed so far are returned with ctx's error.
+func (b *Builder) BuildPathContext(ctx context.Context, path string) (*model.Index, error) {
+	idx, _, err := b.BuildPathIncrementalWithOptions(ctx, path, nil, BuildOptions{})
 	return idx, err
 }
 
@@ -184,6 +303,7 @@ func (b *Builder) BuildPathIncremental(ctx context.Context, path string, previou
 
 func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path string, previous *model.Index, opts BuildOptions) (*model.Index, BuildStats, error) {
 	stats := BuildStats{}
+	start := time.Now()
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -204,15 +324,33 @@ func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path stri
 	}
 
 	// Single-file mode: parse one file directly without the gateway walk.
-	if !info.IsDir() {
-		return b.buildSingleFileWithOptions(ctx, target, info, previous, opts)
-	}
+	var idx *model.Index
+	if info.IsDir() {
+		idx, stats, err = b.buildTree(ctx, target, "", previous, opts)
+	} else {
+		idx, stats, err = b.buildSingleFileWithOptions(ctx, target, info, previous, opts)
+	}
+	b.stampProvenance(ctx, idx, start, stats, "")
+	return idx, stats, err
+}
 
-	root := filepath.Clean(target)
+// buildTree indexes the directory tree at root, or only its subdirectory
+// subtree when that is not empty. Paths stay relative to root either way.
+func (b *Builder) buildTree(ctx context.Context, root, subtree string, previous *model.Index, opts BuildOptions) (*model.Index, BuildStats, error) {
+	stats := BuildStats{}
+	walkRoot := slashpath.Join(root, subtree)
 
 	previousByPath := previousFilesByPath(previous, root)
+	for relPath := range previousByPath {
+		if !inSubtree(relPath, subtree) {
+			delete(previousByPath, relPath)
+		}
+	}
 	filesByPath := make(map[string]model.FileSummary, len(previousByPath))
 	errorsByPath := map[string]model.ParseError{}
+	// Written only from the walk goroutine via ShouldParse; read after the
+	// results channel closes.
+	skippedByPath := map[string]model.SkippedFile{}
 
 	// Build the gateway policy.
 	policy := grammars.DefaultPolicy()
@@ -221,15 +359,19 @@ func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path stri
 	}
 	// DefaultPolicy uses GOMAXPROCS for concurrency. Lazy grammar loading
 	// (sync.Once per language) prevents the OOM spikes that originally
-	// motivated serial parsing. GTS_MAX_CONCURRENT env var still overrides.
+	// motivated serial parsing. GTS_MAX_CONCURRENT env var still overrides,
+	// and SetMaxConcurrent overrides both.
+	if b.maxConcurrent > 0 {
+		policy.MaxConcurrent = b.maxConcurrent
+		policy.ChannelBuffer = b.maxConcurrent + 1
+	}
 	policy.ShouldParse = func(absPath string, size int64, modTime time.Time) bool {
 		// Skip files inside hidden directories (dot-prefixed), matching
 		// the old collectCandidates behaviour.
-		relPath, relErr := filepath.Rel(root, absPath)
-		if relErr != nil {
+		relPath, ok := slashpath.Rel(root, absPath)
+		if !ok {
 			return false
 		}
-		relPath = filepath.ToSlash(relPath)
 		for _, seg := range strings.Split(relPath, "/") {
 			if strings.HasPrefix(seg, ".") && seg != "." {
 				return false
@@ -248,6 +390,16 @@ func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path stri
 			return false
 		}
 
+		// Generated files recognisable by name alone need not be read.
+		if b.skipGenerated && b.detector != nil && b.detector.Detect(relPath, nil) != nil {
+			return false
+		}
+
+		if b.oversized(size) {
+			skippedByPath[relPath] = model.SkippedFile{Path: relPath, Reason: SkipReasonMaxSize, SizeBytes: size}
+			return false
+		}
+
 		// Incremental reuse: skip files that haven't changed.
 		if prev, ok := previousByPath[relPath]; ok {
 			parser, _ := b.parserForPath(absPath)
@@ -259,6 +411,13 @@ func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path stri
 				return false
 			}
 		}
+
+		// Reused files passed this check when first parsed, so only files
+		// about to be read are sniffed.
+		if isBinaryFile(absPath) {
+			skippedByPath[relPath] = model.SkippedFile{Path: relPath, Reason: SkipReasonBinary, SizeBytes: size}
+			return false
+		}
 		return true
 	}
 
@@ -270,11 +429,10 @@ func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path stri
 			if size < generated.FastExtractThreshold {
 				return false
 			}
-			relPath, relErr := filepath.Rel(root, absPath)
-			if relErr != nil {
+			relPath, ok := slashpath.Rel(root, absPath)
+			if !ok {
 				return false
 			}
-			relPath = filepath.ToSlash(relPath)
 			// Detect by filename only (nil source). Returns non-nil for
 			// filename-pattern matches without needing file contents.
 			info := b.detector.Detect(relPath, nil)
@@ -292,7 +450,7 @@ func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path stri
 	// gateway's ShouldParse=false means they won't appear in the channel.
 	// We pre-collect reused entries before the walk.
 	for relPath, prev := range previousByPath {
-		absPath := filepath.Join(root, filepath.FromSlash(relPath))
+		absPath := slashpath.Join(root, relPath)
 		fi, statErr := os.Stat(absPath)
 		if statErr != nil {
 			// File removed or inaccessible — don't reuse.
@@ -319,6 +477,12 @@ func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path stri
 		if b.ignore != nil && b.ignore.Match(relPath, false) {
 			continue
 		}
+		if b.skipGenerated && prev.Generated != nil {
+			continue
+		}
+		if b.oversized(fi.Size()) {
+			continue
+		}
 		entry := prev
 		entry.Path = relPath
 		entry.Language = parser.Language()
@@ -341,11 +505,53 @@ func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path stri
 		})
 	}
 
-	results, statsFn := grammars.WalkAndParse(ctx, root, policy)
+	// The gateway reports queued files from its walk goroutine; progress is
+	// delivered from this one.
+	var queued atomic.Int64
+	var walkDone atomic.Bool
+	if opts.Progress != nil {
+		policy.OnProgress = func(event grammars.ProgressEvent) {
+			switch event.Phase {
+			case "walking":
+				queued.Add(1)
+			case "walk_complete":
+				walkDone.Store(true)
+			}
+		}
+	}
+
+	results, statsFn := grammars.WalkAndParse(ctx, walkRoot, policy)
+	done := 0
 	for file := range results {
+		path := file.Path
 		b.processWalkedFile(file, root, filesByPath, errorsByPath, &stats, opts)
+		if opts.Progress != nil {
+			done++
+			if rel, ok := slashpath.Rel(root, path); ok {
+				path = rel
+			}
+			opts.Progress(BuildProgress{
+				Done:     stats.ReusedFiles + done,
+				Total:    stats.ReusedFiles + int(queued.Load()),
+				Path:     path,
+				WalkDone: walkDone.Load(),
+			})
+		}
 	}
 	_ = statsFn()
+	if opts.Progress != nil && ctx.Err() == nil {
+		opts.Progress(BuildProgress{
+			Done:     stats.ReusedFiles + done,
+			Total:    stats.ReusedFiles + done,
+			WalkDone: true,
+		})
+	}
+
+	b.indexNotebooks(ctx, walkRoot, subtree, filesByPath, errorsByPath, skippedByPath, &stats, opts)
+	if b.followSymlinks {
+		b.indexSymlinks(ctx, walkRoot, subtree, filesByPath, errorsByPath, skippedByPath, &stats, opts)
+	}
+	stats.SkippedFiles = len(skippedByPath)
 
 	if langCount := countDistinctLanguages(filesByPath); langCount > 20 {
 		fmt.Fprintf(os.Stderr, "warning: %d distinct languages detected — this may cause high memory usage\n", langCount)
@@ -353,6 +559,7 @@ func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path stri
 
 	index := snapshotIndex(root, filesByPath, errorsByPath)
 	index.ConfigHashes = b.configHashes
+	index.Skipped = skippedFiles(skippedByPath)
 	if ctxErr := ctx.Err(); ctxErr != nil {
 		return index, stats, ctxErr
 	}
@@ -360,11 +567,10 @@ func (b *Builder) BuildPathIncrementalWithOptions(ctx context.Context, path stri
 }
 
 func (b *Builder) processWalkedFile(file grammars.ParsedFile, root string, filesByPath map[string]model.FileSummary, errorsByPath map[string]model.ParseError, stats *BuildStats, opts BuildOptions) {
-	relPath, relErr := filepath.Rel(root, file.Path)
-	if relErr != nil {
-		relPath = file.Path
+	relPath, ok := slashpath.Rel(root, file.Path)
+	if !ok {
+		relPath = slashpath.Clean(file.Path)
 	}
-	relPath = filepath.ToSlash(relPath)
 
 	stats.CandidateFiles++
 
@@ -397,6 +603,7 @@ func (b *Builder) processWalkedFile(file grammars.ParsedFile, root string, files
 		summary.Path = relPath
 		summary.SizeBytes = file.Size
 		summary.Language = parser.Language()
+		summary.ContentHash = ContentHash(file.Source)
 		if b.detector != nil {
 			summary.Generated = b.detector.Detect(relPath, file.Source)
 		}
@@ -407,6 +614,10 @@ func (b *Builder) processWalkedFile(file grammars.ParsedFile, root string, files
 			summary.Symbols[i].File = relPath
 		}
 		file.Close()
+		if b.skipGenerated && summary.Generated != nil {
+			stats.SkippedGenerated++
+			return
+		}
 		filesByPath[relPath] = summary
 		stats.ParsedFiles++
 		emitBuildEvent(opts, BuildEvent{
@@ -420,13 +631,19 @@ func (b *Builder) processWalkedFile(file grammars.ParsedFile, root string, files
 
 	summary, parseErr := parseIndexedFile(parser, file.Path, file.Source, file.Tree)
 
-	// Run generated-file detection before Close(), which nils Source.
+	// Run generated-file detection and hashing before Close(), which nils Source.
 	var genInfo *model.GeneratedInfo
 	if b.detector != nil {
 		genInfo = b.detector.Detect(relPath, file.Source)
 	}
+	contentHash := ContentHash(file.Source)
 	file.Close()
 
+	if b.skipGenerated && genInfo != nil {
+		stats.SkippedGenerated++
+		return
+	}
+
 	if parseErr != nil {
 		parseFailure := model.ParseError{
 			Path:  relPath,
@@ -460,6 +677,7 @@ func (b *Builder) processWalkedFile(file grammars.ParsedFile, root string, files
 	}
 
 	summary.Generated = genInfo
+	summary.ContentHash = contentHash
 
 	delete(errorsByPath, relPath)
 	filesByPath[relPath] = summary
@@ -503,14 +721,20 @@ func (b *Builder) buildSingleFileWithOptions(ctx context.Context, target string,
 		return snapshotIndex(root, filesByPath, errorsByPath), stats, nil
 	}
 
-	relPath, relErr := filepath.Rel(root, target)
-	if relErr != nil {
+	relPath, ok := slashpath.Rel(root, target)
+	if !ok {
 		relPath = filepath.Base(target)
 	}
-	relPath = filepath.ToSlash(relPath)
 
 	stats.CandidateFiles = 1
 
+	if reason := b.guardReason(target, info.Size()); reason != "" {
+		index := snapshotIndex(root, filesByPath, errorsByPath)
+		index.Skipped = []model.SkippedFile{{Path: relPath, Reason: reason, SizeBytes: info.Size()}}
+		stats.SkippedFiles = 1
+		return index, stats, nil
+	}
+
 	previousByPath := previousFilesByPath(previous, root)
 	if prev, ok := previousByPath[relPath]; ok && canReuseSummary(prev, info.Size(), info.ModTime().UnixNano(), parser.Language()) {
 		reused := prev
@@ -575,6 +799,7 @@ func (b *Builder) buildSingleFileWithOptions(ctx context.Context, target string,
 	summary.SizeBytes = info.Size()
 	summary.ModTimeUnixNano = info.ModTime().UnixNano()
 	summary.Language = parser.Language()
+	summary.ContentHash = ContentHash(source)
 	for i := range summary.Symbols {
 		summary.Symbols[i].File = relPath
 	}
@@ -584,6 +809,10 @@ func (b *Builder) buildSingleFileWithOptions(ctx context.Context, target string,
 	if b.detector != nil {
 		summary.Generated = b.detector.Detect(relPath, source)
 	}
+	if b.skipGenerated && summary.Generated != nil {
+		stats.SkippedGenerated = 1
+		return snapshotIndex(root, filesByPath, errorsByPath), stats, nil
+	}
 	filesByPath[relPath] = summary
 	stats.ParsedFiles = 1
 	emitBuildEvent(opts, BuildEvent{
@@ -328,3 +328,293 @@ func Func%03d() int { return %d }
 		base = next
 	}
 }
+
+func TestBuildPath_ContentHashAndDigest(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainPath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(mainPath, []byte("package sample\n\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile main.go failed: %v", err)
+	}
+
+	builder := NewBuilder()
+	first, err := builder.BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	if got, want := first.Files[0].ContentHash, ContentHash([]byte("package sample\n\nfunc A() {}\n")); got != want {
+		t.Fatalf("content hash = %q, want %q", got, want)
+	}
+	if first.Digest == "" {
+		t.Fatal("expected index digest")
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(mainPath, later, later); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	second, err := builder.BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	if second.Digest != first.Digest {
+		t.Fatalf("digest changed after touch: %s -> %s", first.Digest, second.Digest)
+	}
+}
+
+func TestVerify_DetectsStaleCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainPath := filepath.Join(tmpDir, "main.go")
+	oldPath := filepath.Join(tmpDir, "old.go")
+	if err := os.WriteFile(mainPath, []byte("package sample\n\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile main.go failed: %v", err)
+	}
+	if err := os.WriteFile(oldPath, []byte("package sample\n\nfunc Old() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile old.go failed: %v", err)
+	}
+
+	builder := NewBuilder()
+	cached, err := builder.BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+
+	report, err := builder.Verify(context.Background(), cached)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if report.Stale() || report.Checked != 2 || report.Rehashed != 0 {
+		t.Fatalf("expected fresh cache with no rehashing, got %+v", report)
+	}
+
+	// Touching a file without changing it is not staleness.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(mainPath, later, later); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	report, err = builder.Verify(context.Background(), cached)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if report.Stale() || report.Rehashed != 1 {
+		t.Fatalf("expected touched file to be rehashed but fresh, got %+v", report)
+	}
+
+	if err := os.WriteFile(mainPath, []byte("package sample\n\nfunc B() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile main.go failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "new.go"), []byte("package sample\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile new.go failed: %v", err)
+	}
+	if err := os.Remove(oldPath); err != nil {
+		t.Fatalf("Remove old.go failed: %v", err)
+	}
+
+	report, err = builder.Verify(context.Background(), cached)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if !report.Stale() {
+		t.Fatal("expected stale report")
+	}
+	if !reflect.DeepEqual(report.Changed, []string{"main.go"}) {
+		t.Fatalf("changed = %v", report.Changed)
+	}
+	if !reflect.DeepEqual(report.Added, []string{"new.go"}) {
+		t.Fatalf("added = %v", report.Added)
+	}
+	if !reflect.DeepEqual(report.Removed, []string{"old.go"}) {
+		t.Fatalf("removed = %v", report.Removed)
+	}
+}
+
+func TestBuildPath_FollowSymlinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := filepath.Join(tmpDir, "repo")
+	shared := filepath.Join(tmpDir, "shared")
+	for _, dir := range []string{repo, shared} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package sample\n\nfunc Main() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile main.go failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(shared, "util.go"), []byte("package shared\n\nfunc Util() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile util.go failed: %v", err)
+	}
+	if err := os.Symlink(shared, filepath.Join(repo, "shared")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+	// A link back to the repo root must not loop.
+	if err := os.Symlink(repo, filepath.Join(shared, "loop")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+
+	builder := NewBuilder()
+	idx, err := builder.BuildPath(repo)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	if idx.FileCount() != 1 {
+		t.Fatalf("expected symlinks to be skipped by default, got %d files", idx.FileCount())
+	}
+
+	builder.SetFollowSymlinks(true)
+	idx, err = builder.BuildPath(repo)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	paths := make([]string, 0, len(idx.Files))
+	for _, file := range idx.Files {
+		paths = append(paths, file.Path)
+	}
+	if !reflect.DeepEqual(paths, []string{"main.go", "shared/util.go"}) {
+		t.Fatalf("unexpected files %v", paths)
+	}
+
+	report, err := builder.Verify(context.Background(), idx)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if report.Stale() {
+		t.Fatalf("expected symlinked files to verify, got %+v", report)
+	}
+}
+
+func TestBuildPath_Notebooks(t *testing.T) {
+	tmpDir := t.TempDir()
+	notebook := `{
+ "cells": [
+  {"cell_type": "markdown", "metadata": {}, "source": ["# Notes\n"]},
+  {"cell_type": "code", "metadata": {}, "outputs": [], "source": ["import os\n", "\n", "def load(path):\n", "    return open(path)\n"]},
+  {"cell_type": "code", "metadata": {}, "outputs": [], "source": ["data = load('x.csv')\n"]}
+ ],
+ "metadata": {"kernelspec": {"language": "python", "name": "python3"}},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}`
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".ipynb_checkpoints"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	for _, name := range []string{"analysis.ipynb", ".ipynb_checkpoints/analysis-checkpoint.ipynb"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(notebook), 0o644); err != nil {
+			t.Fatalf("WriteFile %s failed: %v", name, err)
+		}
+	}
+
+	builder := NewBuilder()
+	idx, err := builder.BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	if idx.FileCount() != 1 {
+		t.Fatalf("expected only the notebook outside checkpoints, got %d files", idx.FileCount())
+	}
+	file := idx.Files[0]
+	if file.Path != "analysis.ipynb" || file.Language != "python" {
+		t.Fatalf("unexpected file %s (%s)", file.Path, file.Language)
+	}
+	var load *int
+	for _, symbol := range file.Symbols {
+		if symbol.Name == "load" {
+			load = &symbol.StartLine
+		}
+	}
+	if load == nil {
+		t.Fatalf("expected function load, got %+v", file.Symbols)
+	}
+	if cell, line, ok := file.CellLine(*load); !ok || cell != 2 || line != 3 {
+		t.Fatalf("load is at cell %d line %d (%t), want cell 2 line 3", cell, line, ok)
+	}
+
+	next, stats, err := builder.BuildPathIncremental(context.Background(), tmpDir, idx)
+	if err != nil {
+		t.Fatalf("BuildPathIncremental returned error: %v", err)
+	}
+	if stats.ReusedFiles != 1 || stats.ParsedFiles != 0 || next.FileCount() != 1 {
+		t.Fatalf("expected the unchanged notebook to be reused, got %+v", stats)
+	}
+}
+
+func TestBuildPath_SkipGenerated(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package sample\n\nfunc Main() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile main.go failed: %v", err)
+	}
+	generatedSource := "// Code generated by stringer; DO NOT EDIT.\n\npackage sample\n\nfunc Gen() {}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "gen.go"), []byte(generatedSource), 0o644); err != nil {
+		t.Fatalf("WriteFile gen.go failed: %v", err)
+	}
+
+	builder := NewBuilder()
+	builder.SetSkipGenerated(true)
+	idx, stats, err := builder.BuildPathIncremental(context.Background(), tmpDir, nil)
+	if err != nil {
+		t.Fatalf("BuildPathIncremental returned error: %v", err)
+	}
+	if idx.FileCount() != 1 || idx.Files[0].Path != "main.go" {
+		t.Fatalf("expected only main.go, got %+v", idx.Files)
+	}
+	if stats.SkippedGenerated != 1 {
+		t.Fatalf("expected 1 skipped generated file, got %d", stats.SkippedGenerated)
+	}
+
+	report, err := builder.Verify(context.Background(), idx)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if report.Stale() {
+		t.Fatalf("skipped generated file should not count as added: %+v", report)
+	}
+}
+
+func TestBuildPath_SkipsOversizedAndBinaryFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package sample\n\nfunc Main() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile main.go failed: %v", err)
+	}
+	bundle := "var x = 1;\n"
+	for len(bundle) < 4096 {
+		bundle += "var y = 2;\n"
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "bundle.js"), []byte(bundle), 0o644); err != nil {
+		t.Fatalf("WriteFile bundle.js failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "blob.go"), []byte("package sample\x00\x01\x02"), 0o644); err != nil {
+		t.Fatalf("WriteFile blob.go failed: %v", err)
+	}
+
+	builder := NewBuilder()
+	if builder.MaxFileSize() != DefaultMaxFileSize {
+		t.Fatalf("expected default max file size %d, got %d", DefaultMaxFileSize, builder.MaxFileSize())
+	}
+	builder.SetMaxFileSize(1024)
+	idx, stats, err := builder.BuildPathIncremental(context.Background(), tmpDir, nil)
+	if err != nil {
+		t.Fatalf("BuildPathIncremental returned error: %v", err)
+	}
+	if idx.FileCount() != 1 || idx.Files[0].Path != "main.go" {
+		t.Fatalf("expected only main.go to be indexed, got %+v", idx.Files)
+	}
+	if stats.SkippedFiles != 2 {
+		t.Fatalf("expected 2 skipped files, got %d", stats.SkippedFiles)
+	}
+	want := []string{"blob.go:binary", "bundle.js:max_size"}
+	got := make([]string, 0, len(idx.Skipped))
+	for _, skipped := range idx.Skipped {
+		got = append(got, skipped.Path+":"+skipped.Reason)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("skipped = %v, want %v", got, want)
+	}
+
+	// Raising the limit indexes the bundle on the next incremental build.
+	builder.SetMaxFileSize(0)
+	idx, _, err = builder.BuildPathIncremental(context.Background(), tmpDir, idx)
+	if err != nil {
+		t.Fatalf("BuildPathIncremental returned error: %v", err)
+	}
+	if idx.FileCount() != 2 || len(idx.Skipped) != 1 {
+		t.Fatalf("expected bundle.js indexed and blob.go skipped, got files=%d skipped=%+v", idx.FileCount(), idx.Skipped)
+	}
+}
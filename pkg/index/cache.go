@@ -2,11 +2,12 @@ package index
 
 import (
 	"encoding/json"
-	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
 )
 
 func Save(path string, idx *model.Index) error {
@@ -60,10 +61,47 @@ func Load(path string) (*model.Index, error) {
 
 	var idx model.Index
 	if err := json.NewDecoder(file).Decode(&idx); err != nil {
+		// Another format may not decode at all; say so rather than report
+		// where decoding broke.
+		if incompatible := checkCompatible(path); incompatible != nil {
+			return nil, incompatible
+		}
 		return nil, err
 	}
 	if idx.Version != "" && idx.Version != schemaVersion {
-		return nil, fmt.Errorf("index schema version mismatch: cache has %q, expected %q", idx.Version, schemaVersion)
+		incompatible := &IncompatibleError{Path: path, SchemaVersion: idx.Version}
+		if idx.Provenance != nil {
+			incompatible.ToolVersion = idx.Provenance.ToolVersion
+		}
+		return nil, incompatible
 	}
+	normalizePaths(&idx)
 	return &idx, nil
 }
+
+// normalizePaths rewrites the stored paths of idx in slash form. Indexes
+// written by builds that kept Windows separators load the same as current
+// ones; paths already in slash form are left alone.
+func normalizePaths(idx *model.Index) {
+	clean := func(p *string) {
+		if strings.ContainsRune(*p, '\\') {
+			*p = slashpath.Clean(*p)
+		}
+	}
+	for i := range idx.Files {
+		file := &idx.Files[i]
+		clean(&file.Path)
+		for j := range file.Symbols {
+			clean(&file.Symbols[j].File)
+		}
+		for j := range file.References {
+			clean(&file.References[j].File)
+		}
+	}
+	for i := range idx.Errors {
+		clean(&idx.Errors[i].Path)
+	}
+	for i := range idx.Skipped {
+		clean(&idx.Skipped[i].Path)
+	}
+}
@@ -0,0 +1,41 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadNormalizesWindowsSeparators(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "index.json")
+	cache := `{
+  "version": "` + schemaVersion + `",
+  "root": "C:\\work\\repo",
+  "files": [{
+    "path": "pkg\\api\\server.go",
+    "language": "go",
+    "symbols": [{"file": "pkg\\api\\server.go", "kind": "function_definition", "name": "Serve", "start_line": 3, "end_line": 5}],
+    "references": [{"file": "pkg\\api\\server.go", "kind": "reference.call", "name": "listen", "start_line": 4, "end_line": 4}]
+  }],
+  "errors": [{"path": "cmd\\main.go", "error": "syntax error"}],
+  "skipped": [{"path": "assets\\logo.png", "reason": "binary"}]
+}`
+	if err := os.WriteFile(cachePath, []byte(cache), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := Load(cachePath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	file := idx.Files[0]
+	if file.Path != "pkg/api/server.go" || file.Symbols[0].File != file.Path || file.References[0].File != file.Path {
+		t.Fatalf("expected slash-form file paths, got %q, %q, %q", file.Path, file.Symbols[0].File, file.References[0].File)
+	}
+	if idx.Errors[0].Path != "cmd/main.go" || idx.Skipped[0].Path != "assets/logo.png" {
+		t.Fatalf("expected slash-form error and skipped paths, got %q and %q", idx.Errors[0].Path, idx.Skipped[0].Path)
+	}
+	if idx.Root != `C:\work\repo` {
+		t.Fatalf("expected the root to keep its OS form, got %q", idx.Root)
+	}
+}
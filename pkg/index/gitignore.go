@@ -0,0 +1,161 @@
+package index
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/ignore"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
+)
+
+// LoadGitignoreMatcher collects the .gitignore rules that apply to target:
+// every .gitignore at or below it, plus those in parent directories up to the
+// enclosing git repository and the repository's .git/info/exclude. Nested
+// files are scoped to their own directory, as git does. Returns nil (no
+// error) when there are no rules.
+func LoadGitignoreMatcher(target string) (*ignore.Matcher, error) {
+	root, err := filepath.Abs(target)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		root = filepath.Dir(root)
+	}
+
+	var matchers []*ignore.Matcher
+
+	// Rules above root only reach into it through unanchored patterns or
+	// anchored ones whose path leads into root.
+	if gitRoot := enclosingGitRoot(root); gitRoot != "" {
+		lines, err := readIgnoreLines(filepath.Join(gitRoot, ".git", "info", "exclude"))
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, ignore.ParsePatterns(rebaseIgnoreLines(lines, gitRoot, root)))
+
+		var parents []string
+		for dir := root; dir != gitRoot; {
+			dir = filepath.Dir(dir)
+			parents = append([]string{dir}, parents...)
+		}
+		for _, dir := range parents {
+			lines, err := readIgnoreLines(filepath.Join(dir, ".gitignore"))
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, ignore.ParsePatterns(rebaseIgnoreLines(lines, dir, root)))
+		}
+	}
+
+	// Walk root parent-first so deeper files override shallower ones, and
+	// skip directories already ignored so build output is never descended.
+	current := ignore.Merge(matchers...)
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if path == root {
+				return walkErr
+			}
+			return nil
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		rel, ok := slashpath.Rel(root, path)
+		if !ok {
+			return nil
+		}
+		if rel != "." {
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || defaultSkipDirs[name] {
+				return filepath.SkipDir
+			}
+			if current.Match(rel, true) {
+				return filepath.SkipDir
+			}
+		}
+		lines, err := readIgnoreLines(filepath.Join(path, ".gitignore"))
+		if err != nil {
+			return err
+		}
+		if len(lines) > 0 {
+			matchers = append(matchers, ignore.ParsePatternsAt(rel, lines))
+			current = ignore.Merge(matchers...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// enclosingGitRoot returns the nearest directory at or above dir that
+// contains .git, or "" when dir is not inside a git work tree.
+func enclosingGitRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func readIgnoreLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return splitLines(string(data)), nil
+}
+
+// rebaseIgnoreLines rewrites the lines of an ignore file in dir so they can
+// be matched against paths relative to root, a subdirectory of dir. Lines
+// that cannot match anything under root are dropped.
+func rebaseIgnoreLines(lines []string, dir, root string) []string {
+	prefix, err := filepath.Rel(dir, root)
+	if err != nil {
+		return nil
+	}
+	prefix = filepath.ToSlash(prefix)
+	if prefix == "." {
+		return lines
+	}
+
+	var out []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		negation := ""
+		if strings.HasPrefix(line, "!") {
+			negation = "!"
+			line = line[1:]
+		}
+		glob := strings.TrimSuffix(line, "/")
+		switch {
+		case !strings.Contains(glob, "/"), strings.HasPrefix(glob, "**/"):
+			out = append(out, negation+line)
+		default:
+			rest, ok := strings.CutPrefix(strings.TrimPrefix(line, "/"), prefix+"/")
+			if ok && rest != "" {
+				out = append(out, negation+"/"+rest)
+			}
+		}
+	}
+	return out
+}
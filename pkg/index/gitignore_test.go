@@ -0,0 +1,99 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func indexedPaths(idx *model.Index) []string {
+	paths := make([]string, 0, len(idx.Files))
+	for _, file := range idx.Files {
+		paths = append(paths, file.Path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestNewBuilderWithWorkspaceIgnores_RespectsGitignore(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":       "dist/\n*.gen.go\n",
+		"main.go":          "package main\n\nfunc main() {}\n",
+		"dist/bundle.go":   "package dist\n\nfunc Bundle() {}\n",
+		"api/.gitignore":   "/local.go\n!keep.gen.go\n",
+		"api/api.go":       "package api\n\nfunc Serve() {}\n",
+		"api/local.go":     "package api\n\nfunc Local() {}\n",
+		"api/x.gen.go":     "package api\n\nfunc X() {}\n",
+		"api/keep.gen.go":  "package api\n\nfunc Keep() {}\n",
+		"api/sub/local.go": "package sub\n\nfunc Local() {}\n",
+	})
+
+	builder, err := NewBuilderWithWorkspaceIgnores(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err := builder.BuildPath(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := indexedPaths(idx)
+	want := []string{"api/api.go", "api/keep.gen.go", "api/sub/local.go", "main.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("indexed %v, want %v", got, want)
+	}
+
+	builder.SetGitignore(nil)
+	idx, err = builder.BuildPath(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Files) != 7 {
+		t.Fatalf("without gitignore indexed %v, want all 7 files", indexedPaths(idx))
+	}
+}
+
+func TestLoadGitignoreMatcher_ParentRulesInSubdirectory(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTree(t, repo, map[string]string{
+		".gitignore":     "*.tmp.go\n/svc/out/\n/other/\n",
+		"svc/main.go":    "package svc\n",
+		"svc/a.tmp.go":   "package svc\n",
+		"svc/out/gen.go": "package out\n",
+	})
+
+	m, err := LoadGitignoreMatcher(filepath.Join(repo, "svc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]bool{
+		"main.go":    false,
+		"a.tmp.go":   true,
+		"out/gen.go": true,
+		"other/x.go": false,
+	} {
+		if got := m.Match(path, false); got != want {
+			t.Errorf("Match(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package index
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// DefaultMaxFileSize is the largest file a new Builder will parse. Bigger
+// files are usually bundles, fixtures, or vendored blobs that stall indexing
+// without adding useful structure.
+const DefaultMaxFileSize int64 = 4 << 20
+
+// Reasons recorded in model.SkippedFile.
+const (
+	SkipReasonMaxSize = "max_size"
+	SkipReasonBinary  = "binary"
+)
+
+// binarySniffLen is how much of a file is inspected for NUL bytes.
+const binarySniffLen = 8 * 1024
+
+// SetMaxFileSize sets the largest file, in bytes, the builder will parse.
+// Zero or a negative value removes the limit.
+func (b *Builder) SetMaxFileSize(n int64) {
+	b.maxFileSize = n
+}
+
+// MaxFileSize returns the configured file size limit; zero means unlimited.
+func (b *Builder) MaxFileSize() int64 {
+	if b.maxFileSize < 0 {
+		return 0
+	}
+	return b.maxFileSize
+}
+
+func (b *Builder) oversized(size int64) bool {
+	return b.maxFileSize > 0 && size > b.maxFileSize
+}
+
+// guardReason returns why a file must not be parsed, or "" if it may be.
+func (b *Builder) guardReason(absPath string, size int64) string {
+	if b.oversized(size) {
+		return SkipReasonMaxSize
+	}
+	if isBinaryFile(absPath) {
+		return SkipReasonBinary
+	}
+	return ""
+}
+
+// isBinaryFile reports whether the start of the file contains a NUL byte.
+func isBinaryFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	buf := make([]byte, binarySniffLen)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false
+	}
+	return bytes.IndexByte(buf[:n], 0) >= 0
+}
+
+func skippedFiles(byPath map[string]model.SkippedFile) []model.SkippedFile {
+	if len(byPath) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(byPath))
+	for path := range byPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	skipped := make([]model.SkippedFile, 0, len(paths))
+	for _, path := range paths {
+		skipped = append(skipped, byPath[path])
+	}
+	return skipped
+}
@@ -0,0 +1,46 @@
+package index
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/lang/notebook"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
+)
+
+// indexNotebooks indexes the Jupyter notebooks under dir, whose files appear
+// in the index under prefix. The gateway walk only yields files with a
+// grammar, so notebooks are found by a walk of their own.
+func (b *Builder) indexNotebooks(ctx context.Context, dir, prefix string, filesByPath map[string]model.FileSummary, errorsByPath map[string]model.ParseError, skippedByPath map[string]model.SkippedFile, stats *BuildStats, opts BuildOptions) {
+	if _, ok := b.parsers[notebook.Extension]; !ok {
+		return
+	}
+	_ = filepath.WalkDir(dir, func(absPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if absPath != dir && (strings.HasPrefix(name, ".") || defaultSkipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !strings.EqualFold(filepath.Ext(name), notebook.Extension) {
+			return nil
+		}
+		rel, ok := slashpath.Rel(dir, absPath)
+		if !ok {
+			return nil
+		}
+		b.indexExtraFile(absPath, path.Join(prefix, rel), filesByPath, errorsByPath, skippedByPath, stats, opts)
+		return nil
+	})
+}
@@ -0,0 +1,145 @@
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odvcencio/gotreesitter/grammars"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// ToolVersion is the gts version recorded in the provenance of built
+// indexes. It defaults to the main module's version; the gts command sets
+// its release version.
+var ToolVersion = moduleVersion("")
+
+const grammarsModule = "github.com/odvcencio/gotreesitter"
+
+// moduleVersion returns the version of module in the running binary's
+// build info, or of the main module when module is "".
+func moduleVersion(module string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if module == "" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == module {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return ""
+}
+
+// stampProvenance records on idx how b built it, starting at start, with
+// stats. commit is the revision built from, or "" for the working tree,
+// whose HEAD is looked up.
+func (b *Builder) stampProvenance(ctx context.Context, idx *model.Index, start time.Time, stats BuildStats, commit string) {
+	if idx == nil {
+		return
+	}
+	if commit == "" {
+		if out, err := gitOutput(ctx, idx.Root, "rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+			commit = strings.TrimSpace(out)
+		}
+	}
+
+	counts := map[string]int{}
+	for _, file := range idx.Files {
+		if file.Language != "" {
+			counts[file.Language]++
+		}
+	}
+	languages := make([]model.LanguageSource, 0, len(counts))
+	for language, files := range counts {
+		source := model.LanguageSource{Language: language, Files: files}
+		if entry := grammars.DetectLanguageByName(language); entry != nil {
+			source.Grammar = string(entry.GrammarSource)
+		}
+		languages = append(languages, source)
+	}
+	sort.Slice(languages, func(i, j int) bool { return languages[i].Language < languages[j].Language })
+
+	idx.Provenance = &model.Provenance{
+		ToolVersion: ToolVersion,
+		GoVersion:   runtime.Version(),
+		Grammars:    moduleVersion(grammarsModule),
+		GitCommit:   commit,
+		Options: model.BuildSettings{
+			FollowSymlinks: b.followSymlinks,
+			SkipGenerated:  b.skipGenerated,
+			MaxFileSize:    b.MaxFileSize(),
+		},
+		DurationMS:  time.Since(start).Milliseconds(),
+		ParsedFiles: stats.ParsedFiles,
+		ReusedFiles: stats.ReusedFiles,
+		Languages:   languages,
+	}
+}
+
+// IncompatibleError reports a cache written in an index format this build
+// of gts cannot read. Callers that can rebuild should warn and do so.
+type IncompatibleError struct {
+	Path          string
+	SchemaVersion string
+	// ToolVersion is the gts that wrote the cache, when it recorded one.
+	ToolVersion string
+}
+
+// Writer names the gts that wrote the cache.
+func (e *IncompatibleError) Writer() string {
+	if e.ToolVersion != "" {
+		return "gts " + e.ToolVersion
+	}
+	return "an older gts"
+}
+
+func (e *IncompatibleError) Error() string {
+	current := "this gts"
+	if ToolVersion != "" {
+		current = "gts " + ToolVersion
+	}
+	return fmt.Sprintf("%s was written by %s in index format %s, but %s reads format %s; rebuild it with gts index build",
+		e.Path, e.Writer(), e.SchemaVersion, current, schemaVersion)
+}
+
+// cacheHeader is the part of a cache checked for compatibility. It decodes
+// whatever the rest of the document looks like.
+type cacheHeader struct {
+	Version    string `json:"version"`
+	Provenance struct {
+		ToolVersion string `json:"tool_version"`
+	} `json:"provenance"`
+}
+
+// checkCompatible returns an IncompatibleError when the cache at path is in
+// another index format. A cache that cannot be read at all is left for the
+// caller's own error.
+func checkCompatible(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	var header cacheHeader
+	if err := json.NewDecoder(file).Decode(&header); err != nil {
+		return nil
+	}
+	if header.Version == "" || header.Version == schemaVersion {
+		return nil
+	}
+	return &IncompatibleError{Path: path, SchemaVersion: header.Version, ToolVersion: header.Provenance.ToolVersion}
+}
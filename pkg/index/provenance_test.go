@@ -0,0 +1,83 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestBuildPath_RecordsProvenance(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "tool.py"), []byte("def run():\n    pass\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	builder := NewBuilder()
+	builder.SetFollowSymlinks(true)
+	idx, err := builder.BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	p := idx.Provenance
+	if p == nil {
+		t.Fatal("expected a fresh build to record provenance")
+	}
+	if p.GoVersion != runtime.Version() || p.ParsedFiles != 2 || p.ReusedFiles != 0 {
+		t.Fatalf("unexpected provenance %+v", p)
+	}
+	if !p.Options.FollowSymlinks || p.Options.MaxFileSize != DefaultMaxFileSize {
+		t.Fatalf("expected the builder options to be recorded, got %+v", p.Options)
+	}
+	if len(p.Languages) != 2 || p.Languages[0].Language != "go" || p.Languages[0].Files != 1 || p.Languages[1].Language != "python" {
+		t.Fatalf("expected go and python to be recorded, got %+v", p.Languages)
+	}
+	if p.Languages[0].Grammar == "" {
+		t.Fatalf("expected the go grammar source to be recorded, got %+v", p.Languages[0])
+	}
+
+	next, _, err := builder.BuildPathIncremental(context.Background(), tmpDir, idx)
+	if err != nil {
+		t.Fatalf("BuildPathIncremental returned error: %v", err)
+	}
+	if next.Provenance == nil || next.Provenance.ReusedFiles != 2 || next.Provenance.ParsedFiles != 0 {
+		t.Fatalf("expected an incremental build to record its reuse, got %+v", next.Provenance)
+	}
+}
+
+func TestLoad_IncompatibleVersion(t *testing.T) {
+	dir := t.TempDir()
+	for name, cache := range map[string]string{
+		// An older format that still decodes.
+		"decodes.json": `{"version": "0.1.0", "root": "/repo", "files": [], "provenance": {"tool_version": "0.9.0"}}`,
+		// An older format whose files no longer decode into the model.
+		"broken.json": `{"version": "0.0.1", "root": "/repo", "files": {"a.go": 1}}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(cache), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Load(path)
+		var incompatible *IncompatibleError
+		if !errors.As(err, &incompatible) {
+			t.Fatalf("%s: expected an IncompatibleError, got %v", name, err)
+		}
+		if incompatible.Path != path || incompatible.SchemaVersion == schemaVersion {
+			t.Fatalf("%s: unexpected error fields %+v", name, incompatible)
+		}
+		if !strings.Contains(err.Error(), "gts index build") {
+			t.Fatalf("%s: expected the error to say how to rebuild, got %q", name, err)
+		}
+	}
+
+	_, err := Load(filepath.Join(dir, "decodes.json"))
+	if !strings.Contains(err.Error(), "written by gts 0.9.0") {
+		t.Fatalf("expected the writing gts version in the error, got %q", err)
+	}
+}
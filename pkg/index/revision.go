@@ -0,0 +1,265 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// revisionFile is one blob listed by git ls-tree.
+type revisionFile struct {
+	relPath string // relative to the indexed directory
+	object  string
+	size    int64
+}
+
+// BuildRevision indexes the directory at path as it exists in a git
+// revision (a commit, tag, branch, or stash such as "stash@{0}"), reading
+// file contents from the object store instead of the working tree. Ignore
+// rules and generated-file settings still come from the builder. The
+// returned index records the resolved commit in Revision.
+func (b *Builder) BuildRevision(ctx context.Context, path, rev string) (*model.Index, BuildStats, error) {
+	stats := BuildStats{}
+	start := time.Now()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if strings.TrimSpace(path) == "" {
+		path = "."
+	}
+	if strings.TrimSpace(rev) == "" {
+		return nil, stats, fmt.Errorf("revision is required")
+	}
+	if strings.HasPrefix(rev, "-") {
+		return nil, stats, fmt.Errorf("invalid revision %q", rev)
+	}
+
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return nil, stats, err
+	}
+	root = filepath.Clean(root)
+
+	commit, err := gitOutput(ctx, root, "rev-parse", "--verify", rev+"^{commit}")
+	if err != nil {
+		return nil, stats, fmt.Errorf("resolve revision %q: %w", rev, err)
+	}
+	commit = strings.TrimSpace(commit)
+	prefix, err := gitOutput(ctx, root, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, stats, err
+	}
+	prefix = strings.TrimSpace(prefix)
+
+	skippedByPath := map[string]model.SkippedFile{}
+	files, err := b.listRevisionFiles(ctx, root, commit, prefix, skippedByPath, &stats)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	filesByPath := make(map[string]model.FileSummary, len(files))
+	errorsByPath := map[string]model.ParseError{}
+	err = readRevisionBlobs(ctx, root, files, func(file revisionFile, source []byte) {
+		b.indexRevisionFile(file, source, filesByPath, errorsByPath, skippedByPath, &stats)
+	})
+	if err != nil {
+		return nil, stats, err
+	}
+	stats.SkippedFiles = len(skippedByPath)
+
+	idx := snapshotIndex(root, filesByPath, errorsByPath)
+	idx.ConfigHashes = b.configHashes
+	idx.Skipped = skippedFiles(skippedByPath)
+	idx.Revision = commit
+	b.stampProvenance(ctx, idx, start, stats, commit)
+	return idx, stats, ctx.Err()
+}
+
+// listRevisionFiles lists the parseable blobs under prefix at commit,
+// applying the same hidden-directory, ignore, and size rules as a
+// working-tree build.
+func (b *Builder) listRevisionFiles(ctx context.Context, root, commit, prefix string, skippedByPath map[string]model.SkippedFile, stats *BuildStats) ([]revisionFile, error) {
+	args := []string{"ls-tree", "-r", "-z", "--long", "--full-tree", commit}
+	if prefix != "" {
+		args = append(args, "--", prefix)
+	}
+	out, err := gitOutput(ctx, root, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list revision %s: %w", commit, err)
+	}
+
+	files := make([]revisionFile, 0, 256)
+	for _, entry := range strings.Split(out, "\x00") {
+		// <mode> SP <type> SP <object> SP+ <size> TAB <path>
+		meta, fullPath, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 4 || fields[1] != "blob" || fields[0] == "120000" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		relPath := strings.TrimPrefix(fullPath, prefix)
+		if hiddenPath(relPath) {
+			continue
+		}
+		if b.ignore != nil && b.ignore.Match(relPath, false) {
+			continue
+		}
+		if _, ok := b.parserForPath(relPath); !ok {
+			continue
+		}
+		if b.skipGenerated && b.detector != nil && b.detector.Detect(relPath, nil) != nil {
+			stats.SkippedGenerated++
+			continue
+		}
+		if b.oversized(size) {
+			skippedByPath[relPath] = model.SkippedFile{Path: relPath, Reason: SkipReasonMaxSize, SizeBytes: size}
+			continue
+		}
+		stats.CandidateFiles++
+		files = append(files, revisionFile{relPath: relPath, object: fields[2], size: size})
+	}
+	return files, nil
+}
+
+func (b *Builder) indexRevisionFile(file revisionFile, source []byte, filesByPath map[string]model.FileSummary, errorsByPath map[string]model.ParseError, skippedByPath map[string]model.SkippedFile, stats *BuildStats) {
+	relPath := file.relPath
+	if bytes.IndexByte(source[:min(len(source), binarySniffLen)], 0) >= 0 {
+		skippedByPath[relPath] = model.SkippedFile{Path: relPath, Reason: SkipReasonBinary, SizeBytes: file.size}
+		return
+	}
+
+	parser, _ := b.parserForPath(relPath)
+	var genInfo *model.GeneratedInfo
+	if b.detector != nil {
+		genInfo = b.detector.Detect(relPath, source)
+	}
+	if b.skipGenerated && genInfo != nil {
+		stats.SkippedGenerated++
+		return
+	}
+
+	summary, err := parser.Parse(relPath, source)
+	if err != nil {
+		errorsByPath[relPath] = model.ParseError{Path: relPath, Error: err.Error()}
+		return
+	}
+	summary.Path = relPath
+	summary.Language = parser.Language()
+	summary.SizeBytes = file.size
+	summary.ContentHash = ContentHash(source)
+	summary.Generated = genInfo
+	for i := range summary.Symbols {
+		summary.Symbols[i].File = relPath
+	}
+	for i := range summary.References {
+		summary.References[i].File = relPath
+	}
+	filesByPath[relPath] = summary
+	stats.ParsedFiles++
+}
+
+// readRevisionBlobs streams blob contents through a single
+// "git cat-file --batch" process.
+func readRevisionBlobs(ctx context.Context, root string, files []revisionFile, fn func(revisionFile, []byte)) error {
+	if len(files) == 0 {
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, "git", "-C", root, "cat-file", "--batch")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		writer := bufio.NewWriter(stdin)
+		for _, file := range files {
+			writer.WriteString(file.object + "\n")
+		}
+		writer.Flush()
+		stdin.Close()
+	}()
+
+	reader := bufio.NewReader(stdout)
+	var readErr error
+	for _, file := range files {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			readErr = err
+			break
+		}
+		// <object> SP blob SP <size> LF <contents> LF
+		fields := strings.Fields(header)
+		if len(fields) != 3 || fields[1] != "blob" {
+			readErr = fmt.Errorf("unexpected cat-file header %q for %s", strings.TrimSpace(header), file.relPath)
+			break
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			readErr = err
+			break
+		}
+		source := make([]byte, size+1)
+		if _, err := io.ReadFull(reader, source); err != nil {
+			readErr = err
+			break
+		}
+		fn(file, source[:size])
+	}
+
+	if readErr != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return fmt.Errorf("read revision blobs: %w", readErr)
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git cat-file: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+func hiddenPath(relPath string) bool {
+	for _, seg := range strings.Split(relPath, "/") {
+		if strings.HasPrefix(seg, ".") && seg != "." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package index
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func TestBuildRevisionReadsObjectStore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	pkgDir := filepath.Join(repo, "pkg")
+	if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pkgDir, "a.go"), []byte("package pkg\n\nfunc Committed() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "root.go"), []byte("package root\n\nfunc Root() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "init", "-q")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "init")
+
+	// Dirty the worktree: the revision index must not see this.
+	if err := os.WriteFile(filepath.Join(pkgDir, "a.go"), []byte("package pkg\n\nfunc Dirty() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, stats, err := NewBuilder().BuildRevision(context.Background(), pkgDir, "HEAD")
+	if err != nil {
+		t.Fatalf("BuildRevision returned error: %v", err)
+	}
+	if idx.Revision == "" {
+		t.Fatal("expected resolved revision to be recorded")
+	}
+	if stats.ParsedFiles != 1 || len(idx.Files) != 1 {
+		t.Fatalf("expected only pkg/a.go to be indexed, got %+v", idx.Files)
+	}
+	file := idx.Files[0]
+	if file.Path != "a.go" || file.ContentHash == "" {
+		t.Fatalf("unexpected file summary %+v", file)
+	}
+	if len(file.Symbols) != 1 || file.Symbols[0].Name != "Committed" || file.Symbols[0].File != "a.go" {
+		t.Fatalf("expected committed symbol, got %+v", file.Symbols)
+	}
+
+	if _, _, err := NewBuilder().BuildRevision(context.Background(), repo, "no-such-rev"); err == nil {
+		t.Fatal("expected error for unknown revision")
+	}
+}
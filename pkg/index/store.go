@@ -0,0 +1,160 @@
+package index
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Store holds the current index of a long-running process such as the watch
+// loop, the daemon, or the LSP server. Readers take a Snapshot, which is never
+// modified afterwards; writers publish a new index rather than mutating the
+// old one, so a query always sees either the previous or the next index in
+// full. Writers are serialized.
+type Store struct {
+	mu      sync.Mutex
+	current atomic.Pointer[model.Index]
+}
+
+// NewStore returns a Store holding idx, which may be nil.
+func NewStore(idx *model.Index) *Store {
+	s := &Store{}
+	s.current.Store(idx)
+	return s
+}
+
+// Snapshot returns the current index, or nil if none has been stored. The
+// returned index must be treated as read-only.
+func (s *Store) Snapshot() *model.Index {
+	return s.current.Load()
+}
+
+// Replace publishes idx as the current index.
+func (s *Store) Replace(idx *model.Index) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current.Store(idx)
+}
+
+// Update calls fn with the current index and publishes the index it returns.
+// Other writers wait until fn returns, so an incremental rebuild cannot
+// overwrite a newer index with one derived from an older snapshot. If fn
+// returns an error, the current index is kept.
+func (s *Store) Update(fn func(current *model.Index) (*model.Index, error)) (*model.Index, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next, err := fn(s.current.Load())
+	if err != nil {
+		return s.current.Load(), err
+	}
+	s.current.Store(next)
+	return next, nil
+}
+
+// ApplyFileSummary publishes a copy of the current index in which summary
+// replaces the file at summary.Path, or is added if the file is new. Any
+// parse error or skip recorded for that path is dropped. It returns the new
+// index.
+func (s *Store) ApplyFileSummary(summary model.FileSummary) *model.Index {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.copyCurrent(summary.Path)
+	files := make([]model.FileSummary, 0, len(next.Files)+1)
+	for _, file := range next.Files {
+		if file.Path != summary.Path {
+			files = append(files, file)
+		}
+	}
+	files = append(files, cloneFileSummary(summary))
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	next.Files = files
+	next.Digest = Digest(next)
+
+	s.current.Store(next)
+	return next
+}
+
+// RemoveFile publishes a copy of the current index without the file, parse
+// error, or skip recorded for path, and returns it.
+func (s *Store) RemoveFile(path string) *model.Index {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.copyCurrent(path)
+	files := make([]model.FileSummary, 0, len(next.Files))
+	for _, file := range next.Files {
+		if file.Path != path {
+			files = append(files, file)
+		}
+	}
+	next.Files = files
+	next.Digest = Digest(next)
+
+	s.current.Store(next)
+	return next
+}
+
+// ApplyWatchChanges publishes the index after the files at changedAbsPaths
+// changed on disk. A single changed file is re-read on its own and published
+// with ApplyFileSummary or RemoveFile, so the rest of the index is neither
+// walked nor rebuilt. Several paths, or a file that now fails to parse or is
+// skipped, go through Builder.ApplyWatchChanges instead.
+func (s *Store) ApplyWatchChanges(b *Builder, changedAbsPaths []string, state *WatchState, opts WatchUpdateOptions) (*model.Index, BuildStats, error) {
+	if current := s.Snapshot(); current != nil && len(changedAbsPaths) == 1 {
+		for relPath := range normalizeChangedPaths(current.Root, changedAbsPaths) {
+			change, ok, err := b.readWatchChange(filepath.Clean(current.Root), relPath, state, opts)
+			if err != nil {
+				return current, BuildStats{}, err
+			}
+			switch {
+			case !ok:
+				return current, BuildStats{}, nil
+			case change.summary != nil:
+				return s.ApplyFileSummary(*change.summary), BuildStats{CandidateFiles: 1, ParsedFiles: 1}, nil
+			case change.parseErr == nil && change.skipped == nil:
+				return s.RemoveFile(relPath), BuildStats{CandidateFiles: 1}, nil
+			}
+		}
+	}
+
+	var stats BuildStats
+	next, err := s.Update(func(current *model.Index) (*model.Index, error) {
+		next, nextStats, err := b.ApplyWatchChanges(current, changedAbsPaths, state, opts)
+		stats = nextStats
+		return next, err
+	})
+	return next, stats, err
+}
+
+// copyCurrent returns a shallow copy of the current index with fresh Errors
+// and Skipped slices that omit path. Files still shares the current backing
+// array and must be replaced, not written to.
+func (s *Store) copyCurrent(path string) *model.Index {
+	next := &model.Index{Version: schemaVersion}
+	if current := s.current.Load(); current != nil {
+		*next = *current
+	}
+	next.GeneratedAt = time.Now().UTC()
+
+	parseErrors := make([]model.ParseError, 0, len(next.Errors))
+	for _, parseErr := range next.Errors {
+		if parseErr.Path != path {
+			parseErrors = append(parseErrors, parseErr)
+		}
+	}
+	next.Errors = parseErrors
+
+	var skipped []model.SkippedFile
+	for _, file := range next.Skipped {
+		if file.Path != path {
+			skipped = append(skipped, file)
+		}
+	}
+	next.Skipped = skipped
+	return next
+}
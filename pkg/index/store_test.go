@@ -0,0 +1,171 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func TestStore_ApplyFileSummaryIsCopyOnWrite(t *testing.T) {
+	initial := &model.Index{
+		Root: "/repo",
+		Files: []model.FileSummary{
+			{Path: "a.go", Symbols: []model.Symbol{{Name: "A"}}},
+			{Path: "c.go"},
+		},
+		Errors: []model.ParseError{{Path: "b.go", Error: "syntax error"}},
+	}
+	store := NewStore(initial)
+
+	next := store.ApplyFileSummary(model.FileSummary{Path: "b.go", Symbols: []model.Symbol{{Name: "B"}}})
+	if store.Snapshot() != next {
+		t.Fatal("expected ApplyFileSummary to publish the returned index")
+	}
+	if got := filePaths(next); fmt.Sprint(got) != "[a.go b.go c.go]" {
+		t.Fatalf("unexpected files after apply: %v", got)
+	}
+	if len(next.Errors) != 0 {
+		t.Fatalf("expected the parse error for b.go to be dropped, got %+v", next.Errors)
+	}
+	if next.Root != "/repo" || next.Digest == "" {
+		t.Fatalf("expected root kept and digest set, got root=%q digest=%q", next.Root, next.Digest)
+	}
+
+	if got := filePaths(initial); fmt.Sprint(got) != "[a.go c.go]" || len(initial.Errors) != 1 {
+		t.Fatalf("expected the previous snapshot to be unchanged, got files=%v errors=%+v", got, initial.Errors)
+	}
+
+	replaced := store.ApplyFileSummary(model.FileSummary{Path: "a.go", Symbols: []model.Symbol{{Name: "A2"}}})
+	if replaced.SymbolCount() != 2 || replaced.Files[0].Symbols[0].Name != "A2" {
+		t.Fatalf("expected a.go to be replaced, got %+v", replaced.Files)
+	}
+	if next.Files[0].Symbols[0].Name != "A" {
+		t.Fatalf("expected the earlier snapshot to keep the old a.go, got %+v", next.Files[0])
+	}
+}
+
+func TestStore_RemoveFile(t *testing.T) {
+	store := NewStore(&model.Index{
+		Files:   []model.FileSummary{{Path: "a.go"}, {Path: "b.go"}},
+		Skipped: []model.SkippedFile{{Path: "big.go", Reason: "too large"}},
+	})
+	before := store.Snapshot()
+
+	after := store.RemoveFile("a.go")
+	if got := filePaths(after); fmt.Sprint(got) != "[b.go]" {
+		t.Fatalf("unexpected files after remove: %v", got)
+	}
+	after = store.RemoveFile("big.go")
+	if len(after.Skipped) != 0 {
+		t.Fatalf("expected the skip for big.go to be dropped, got %+v", after.Skipped)
+	}
+	if len(before.Files) != 2 || len(before.Skipped) != 1 {
+		t.Fatalf("expected the previous snapshot to be unchanged, got %+v", before)
+	}
+}
+
+func TestStore_ConcurrentWritersAndReaders(t *testing.T) {
+	store := NewStore(nil)
+
+	const writers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path := fmt.Sprintf("f%d.go", i)
+			store.ApplyFileSummary(model.FileSummary{Path: path})
+			_, _ = store.Update(func(current *model.Index) (*model.Index, error) {
+				return current, nil
+			})
+		}(i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if idx := store.Snapshot(); idx != nil {
+				for i := 1; i < len(idx.Files); i++ {
+					if idx.Files[i-1].Path >= idx.Files[i].Path {
+						t.Errorf("snapshot files out of order: %v", filePaths(idx))
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := store.Snapshot().FileCount(); got != writers {
+		t.Fatalf("expected %d files after concurrent applies, got %d", writers, got)
+	}
+}
+
+func TestStore_UpdateKeepsIndexOnError(t *testing.T) {
+	initial := &model.Index{Files: []model.FileSummary{{Path: "a.go"}}}
+	store := NewStore(initial)
+
+	got, err := store.Update(func(current *model.Index) (*model.Index, error) {
+		return nil, fmt.Errorf("build failed")
+	})
+	if err == nil {
+		t.Fatal("expected Update to return the error")
+	}
+	if got != initial || store.Snapshot() != initial {
+		t.Fatal("expected the current index to be kept after a failed update")
+	}
+}
+
+func TestStore_ApplyWatchChangesSingleFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("WriteFile %s failed: %v", name, err)
+		}
+	}
+	write("a.go", "package sample\n\nfunc A() {}\n")
+	write("b.go", "package sample\n\nfunc B() {}\n")
+
+	builder := NewBuilder()
+	initial, _, err := builder.BuildPathIncremental(context.Background(), tmpDir, nil)
+	if err != nil {
+		t.Fatalf("BuildPathIncremental returned error: %v", err)
+	}
+	store := NewStore(initial)
+
+	write("a.go", "package sample\n\nfunc A() {}\n\nfunc A2() {}\n")
+	next, stats, err := store.ApplyWatchChanges(builder, []string{filepath.Join(tmpDir, "a.go")}, nil, WatchUpdateOptions{})
+	if err != nil {
+		t.Fatalf("ApplyWatchChanges returned error: %v", err)
+	}
+	if store.Snapshot() != next || next.SymbolCount() != 3 || stats.ParsedFiles != 1 {
+		t.Fatalf("expected a.go to be re-parsed into 3 symbols, got %d (stats %+v)", next.SymbolCount(), stats)
+	}
+	// b.go did not change, so its summary is carried over rather than rebuilt.
+	if &next.Files[1].Symbols[0] != &initial.Files[1].Symbols[0] {
+		t.Fatal("expected the unchanged b.go summary to be shared with the previous index")
+	}
+
+	if err := os.Remove(filepath.Join(tmpDir, "b.go")); err != nil {
+		t.Fatalf("Remove b.go failed: %v", err)
+	}
+	next, _, err = store.ApplyWatchChanges(builder, []string{filepath.Join(tmpDir, "b.go")}, nil, WatchUpdateOptions{})
+	if err != nil {
+		t.Fatalf("ApplyWatchChanges returned error: %v", err)
+	}
+	if got := filePaths(next); len(got) != 1 || got[0] != "a.go" {
+		t.Fatalf("expected b.go to be removed, got %v", got)
+	}
+}
+
+func filePaths(idx *model.Index) []string {
+	paths := make([]string, 0, len(idx.Files))
+	for _, file := range idx.Files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
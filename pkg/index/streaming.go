@@ -26,6 +26,20 @@ type BuildEvent struct {
 
 type BuildOptions struct {
 	Observer func(BuildEvent)
+	// Progress, if set, is called after each parsed file and once more with
+	// an empty Path when the build completes. It runs on the goroutine that
+	// called the build.
+	Progress func(BuildProgress)
+}
+
+// BuildProgress reports how far a build has got. Total counts the files
+// found so far, reused ones included, and only stops growing once WalkDone
+// is set.
+type BuildProgress struct {
+	Done     int
+	Total    int
+	Path     string
+	WalkDone bool
 }
 
 func emitBuildEvent(opts BuildOptions, event BuildEvent) {
@@ -131,6 +145,7 @@ func snapshotIndex(root string, filesByPath map[string]model.FileSummary, errors
 	for _, path := range errorPaths {
 		idx.Errors = append(idx.Errors, errorsByPath[path])
 	}
+	idx.Digest = Digest(idx)
 
 	return idx
 }
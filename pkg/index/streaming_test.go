@@ -96,6 +96,63 @@ func TestBuildPathIncrementalWithOptions_ObserverReceivesStreamedEvents(t *testi
 	}
 }
 
+func TestBuildPathIncrementalWithOptions_ReportsProgress(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("package sample\n"), 0o644); err != nil {
+			t.Fatalf("WriteFile %s failed: %v", name, err)
+		}
+	}
+
+	builder := NewBuilder()
+	builder.Register(".go", stubStreamingParser{})
+
+	baseline, _, err := builder.BuildPathIncremental(context.Background(), tmpDir, nil)
+	if err != nil {
+		t.Fatalf("BuildPathIncremental baseline returned error: %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte("package sample\n\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	var updates []BuildProgress
+	_, _, err = builder.BuildPathIncrementalWithOptions(context.Background(), tmpDir, baseline, BuildOptions{
+		Progress: func(progress BuildProgress) {
+			updates = append(updates, progress)
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildPathIncrementalWithOptions returned error: %v", err)
+	}
+
+	if len(updates) != 2 {
+		t.Fatalf("expected one file update and a final update, got %+v", updates)
+	}
+	if first := updates[0]; first.Path != "a.go" || first.Done != 3 || first.Total != 3 {
+		t.Fatalf("unexpected file update %+v", first)
+	}
+	if last := updates[1]; last.Path != "" || !last.WalkDone || last.Done != 3 || last.Total != 3 {
+		t.Fatalf("unexpected final update %+v", last)
+	}
+}
+
+func TestBuildPathContext_Cancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte("package sample\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	builder := NewBuilder()
+	builder.Register(".go", stubStreamingParser{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := builder.BuildPathContext(ctx, tmpDir); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
 func TestPartialIndexSnapshotTracksFilesAndErrors(t *testing.T) {
 	base := &model.Index{
 		Version: "0.2.0",
@@ -0,0 +1,113 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
+)
+
+// BuildSubtree re-indexes only the directory subdir of idx's workspace and
+// returns a copy of idx with the files, parse errors, and skips under subdir
+// replaced by the result. subdir is relative to idx.Root or absolute within
+// it. Unchanged files under subdir are reused from idx as in
+// BuildPathIncremental; files outside it are carried over without being
+// looked at, so they may be stale. The returned stats cover subdir only.
+func (b *Builder) BuildSubtree(ctx context.Context, idx *model.Index, subdir string) (*model.Index, BuildStats, error) {
+	stats := BuildStats{}
+	start := time.Now()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if idx == nil {
+		return nil, stats, fmt.Errorf("index is nil")
+	}
+	if idx.Revision != "" {
+		return nil, stats, fmt.Errorf("cannot rebuild part of an index built from revision %s", idx.Revision)
+	}
+
+	root := filepath.Clean(idx.Root)
+	subtree, err := subtreePath(root, subdir)
+	if err != nil {
+		return nil, stats, err
+	}
+	info, err := os.Stat(slashpath.Join(root, subtree))
+	if err != nil {
+		return nil, stats, err
+	}
+	if !info.IsDir() {
+		return nil, stats, fmt.Errorf("%s is not a directory", subdir)
+	}
+
+	partial, stats, err := b.buildTree(ctx, root, subtree, idx, BuildOptions{})
+	if err != nil {
+		return nil, stats, err
+	}
+
+	filesByPath := map[string]model.FileSummary{}
+	errorsByPath := map[string]model.ParseError{}
+	skippedByPath := map[string]model.SkippedFile{}
+	for _, file := range idx.Files {
+		if !inSubtree(file.Path, subtree) {
+			filesByPath[file.Path] = file
+		}
+	}
+	for _, parseErr := range idx.Errors {
+		if !inSubtree(parseErr.Path, subtree) {
+			errorsByPath[parseErr.Path] = parseErr
+		}
+	}
+	for _, skipped := range idx.Skipped {
+		if !inSubtree(skipped.Path, subtree) {
+			skippedByPath[skipped.Path] = skipped
+		}
+	}
+	for _, file := range partial.Files {
+		filesByPath[file.Path] = file
+	}
+	for _, parseErr := range partial.Errors {
+		errorsByPath[parseErr.Path] = parseErr
+	}
+	for _, skipped := range partial.Skipped {
+		skippedByPath[skipped.Path] = skipped
+	}
+
+	merged := snapshotIndex(root, filesByPath, errorsByPath)
+	merged.Skipped = skippedFiles(skippedByPath)
+	merged.ConfigHashes = idx.ConfigHashes
+	b.stampProvenance(ctx, merged, start, stats, "")
+	return merged, stats, nil
+}
+
+// subtreePath returns subdir as a slash-separated path relative to root,
+// rejecting paths outside it.
+func subtreePath(root, subdir string) (string, error) {
+	if strings.TrimSpace(subdir) == "" {
+		return "", fmt.Errorf("subtree path is required")
+	}
+	rel := slashpath.Clean(subdir)
+	if filepath.IsAbs(subdir) {
+		var ok bool
+		if rel, ok = slashpath.Rel(root, filepath.Clean(subdir)); !ok {
+			return "", fmt.Errorf("%s is outside %s", subdir, root)
+		}
+	}
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%s is outside %s", subdir, root)
+	}
+	if rel == "." {
+		return "", nil
+	}
+	return rel, nil
+}
+
+// inSubtree reports whether relPath lies under subtree; every path lies under
+// the empty subtree.
+func inSubtree(relPath, subtree string) bool {
+	return subtree == "" || relPath == subtree || strings.HasPrefix(relPath, subtree+"/")
+}
@@ -0,0 +1,83 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func TestBuildSubtree(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(relPath, body string) {
+		t.Helper()
+		absPath := filepath.Join(tmpDir, filepath.FromSlash(relPath))
+		if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(absPath, []byte(body), 0o644); err != nil {
+			t.Fatalf("WriteFile %s failed: %v", relPath, err)
+		}
+	}
+	write("a/a.go", "package a\n\nfunc A() {}\n")
+	write("a/gone.go", "package a\n\nfunc Gone() {}\n")
+	write("ab/ab.go", "package ab\n\nfunc AB() {}\n")
+	write("b/b.go", "package b\n\nfunc B() {}\n")
+
+	builder := NewBuilder()
+	idx, err := builder.BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+
+	write("a/a.go", "package a\n\nfunc A() {}\n\nfunc A2() {}\n")
+	write("a/new.go", "package a\n\nfunc New() {}\n")
+	if err := os.Remove(filepath.Join(tmpDir, "a", "gone.go")); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	write("ab/ab.go", "package ab\n\nfunc AB() {}\n\nfunc AB2() {}\n")
+	write("b/b.go", "package b\n\nfunc B() {}\n\nfunc B2() {}\n")
+
+	next, stats, err := builder.BuildSubtree(context.Background(), idx, "a")
+	if err != nil {
+		t.Fatalf("BuildSubtree returned error: %v", err)
+	}
+
+	symbols := map[string]bool{}
+	for _, file := range next.Files {
+		for _, symbol := range file.Symbols {
+			symbols[symbol.Name] = true
+		}
+	}
+	for _, name := range []string{"A", "A2", "New", "AB", "B"} {
+		if !symbols[name] {
+			t.Fatalf("expected symbol %s in merged index, got %v", name, symbols)
+		}
+	}
+	for _, name := range []string{"Gone", "AB2", "B2"} {
+		if symbols[name] {
+			t.Fatalf("expected symbol %s to be absent from merged index, got %v", name, symbols)
+		}
+	}
+	if stats.ParsedFiles != 2 {
+		t.Fatalf("expected only the two changed files under a/ to be parsed, got %+v", stats)
+	}
+	if next.Root != idx.Root || next.Digest == idx.Digest {
+		t.Fatalf("expected same root and a new digest, got root=%q digest=%q", next.Root, next.Digest)
+	}
+	if got := filePaths(idx); len(got) != 4 {
+		t.Fatalf("expected the input index to be unchanged, got %v", got)
+	}
+}
+
+func TestBuildSubtree_RejectsPathsOutsideRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	idx := &model.Index{Root: tmpDir}
+	for _, subdir := range []string{"../elsewhere", filepath.Dir(tmpDir), ""} {
+		if _, _, err := NewBuilder().BuildSubtree(context.Background(), idx, subdir); err == nil {
+			t.Fatalf("expected BuildSubtree(%q) to fail", subdir)
+		}
+	}
+}
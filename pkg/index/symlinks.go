@@ -0,0 +1,164 @@
+package index
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
+)
+
+// indexSymlinks indexes the files the gateway walk skips because they are
+// reached through a symbolic link under dir, whose files appear in the index
+// under prefix. Linked files keep the link's path. Each real directory is walked at most once, so link cycles and
+// links back into the tree terminate.
+func (b *Builder) indexSymlinks(ctx context.Context, dir, prefix string, filesByPath map[string]model.FileSummary, errorsByPath map[string]model.ParseError, skippedByPath map[string]model.SkippedFile, stats *BuildStats, opts BuildOptions) {
+	visited := map[string]bool{}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		visited[real] = true
+	}
+	b.walkLinked(ctx, dir, prefix, false, visited, filesByPath, errorsByPath, skippedByPath, stats, opts)
+}
+
+// walkLinked walks dir, whose files appear in the index under prefix. Regular
+// files are only indexed when linked is true; otherwise the gateway walk has
+// already seen them and only symlinks are followed.
+func (b *Builder) walkLinked(ctx context.Context, dir, prefix string, linked bool, visited map[string]bool, filesByPath map[string]model.FileSummary, errorsByPath map[string]model.ParseError, skippedByPath map[string]model.SkippedFile, stats *BuildStats, opts BuildOptions) {
+	_ = filepath.WalkDir(dir, func(absPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if absPath == dir {
+			return nil
+		}
+		rel, ok := slashpath.Rel(dir, absPath)
+		if !ok {
+			return nil
+		}
+		relPath := path.Join(prefix, rel)
+		name := entry.Name()
+
+		if entry.IsDir() {
+			if strings.HasPrefix(name, ".") || defaultSkipDirs[name] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			if linked {
+				b.indexExtraFile(absPath, relPath, filesByPath, errorsByPath, skippedByPath, stats, opts)
+			}
+			return nil
+		}
+		if entry.Type()&fs.ModeSymlink == 0 {
+			return nil
+		}
+
+		target, evalErr := filepath.EvalSymlinks(absPath)
+		if evalErr != nil {
+			return nil
+		}
+		info, statErr := os.Stat(target)
+		if statErr != nil {
+			return nil
+		}
+		if !info.IsDir() {
+			b.indexExtraFile(absPath, relPath, filesByPath, errorsByPath, skippedByPath, stats, opts)
+			return nil
+		}
+		if strings.HasPrefix(name, ".") || defaultSkipDirs[name] || visited[target] {
+			return nil
+		}
+		visited[target] = true
+		b.walkLinked(ctx, target, relPath, true, visited, filesByPath, errorsByPath, skippedByPath, stats, opts)
+		return nil
+	})
+}
+
+// indexExtraFile indexes a file the gateway walk does not yield: one reached
+// through a symbolic link, or a notebook, at relPath.
+func (b *Builder) indexExtraFile(absPath, relPath string, filesByPath map[string]model.FileSummary, errorsByPath map[string]model.ParseError, skippedByPath map[string]model.SkippedFile, stats *BuildStats, opts BuildOptions) {
+	for _, seg := range strings.Split(relPath, "/") {
+		if strings.HasPrefix(seg, ".") {
+			return
+		}
+	}
+	if b.ignore != nil && b.ignore.Match(relPath, false) {
+		return
+	}
+	parser, ok := b.parserForPath(absPath)
+	if !ok {
+		return
+	}
+	if _, reused := filesByPath[relPath]; reused {
+		return
+	}
+
+	stats.CandidateFiles++
+	fail := func(err error) {
+		parseErr := model.ParseError{Path: relPath, Error: err.Error()}
+		errorsByPath[relPath] = parseErr
+		emitBuildEvent(opts, BuildEvent{
+			Kind:       BuildEventError,
+			Path:       relPath,
+			ParseError: parseErr,
+			Stats:      *stats,
+		})
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		fail(err)
+		return
+	}
+	if reason := b.guardReason(absPath, info.Size()); reason != "" {
+		skippedByPath[relPath] = model.SkippedFile{Path: relPath, Reason: reason, SizeBytes: info.Size()}
+		return
+	}
+	source, err := os.ReadFile(absPath)
+	if err != nil {
+		fail(err)
+		return
+	}
+	summary, err := parser.Parse(absPath, source)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	summary.Path = relPath
+	summary.Language = parser.Language()
+	summary.SizeBytes = info.Size()
+	summary.ModTimeUnixNano = info.ModTime().UnixNano()
+	summary.ContentHash = ContentHash(source)
+	for i := range summary.Symbols {
+		summary.Symbols[i].File = relPath
+	}
+	for i := range summary.References {
+		summary.References[i].File = relPath
+	}
+	if b.detector != nil {
+		summary.Generated = b.detector.Detect(relPath, source)
+	}
+	if b.skipGenerated && summary.Generated != nil {
+		stats.SkippedGenerated++
+		return
+	}
+
+	delete(errorsByPath, relPath)
+	filesByPath[relPath] = summary
+	stats.ParsedFiles++
+	emitBuildEvent(opts, BuildEvent{
+		Kind:    BuildEventParsed,
+		Path:    relPath,
+		Summary: summary,
+		Stats:   *stats,
+	})
+}
@@ -0,0 +1,210 @@
+package index
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
+)
+
+// ContentHash returns the hex SHA-256 of a file's contents as stored in
+// model.FileSummary.ContentHash.
+func ContentHash(source []byte) string {
+	sum := sha256.Sum256(source)
+	return hex.EncodeToString(sum[:])
+}
+
+// Digest hashes the schema version, every file path with its content hash,
+// and every parse-error path. It does not depend on build time or file
+// modification times, so rebuilding an unchanged tree yields the same digest.
+func Digest(idx *model.Index) string {
+	if idx == nil {
+		return ""
+	}
+	files := make([]string, 0, len(idx.Files))
+	for _, file := range idx.Files {
+		files = append(files, file.Path+"\x00"+file.ContentHash)
+	}
+	sort.Strings(files)
+	failed := make([]string, 0, len(idx.Errors))
+	for _, parseErr := range idx.Errors {
+		failed = append(failed, parseErr.Path)
+	}
+	sort.Strings(failed)
+
+	h := sha256.New()
+	h.Write([]byte(idx.Version + "\n"))
+	for _, entry := range files {
+		h.Write([]byte("file\x00" + entry + "\n"))
+	}
+	for _, path := range failed {
+		h.Write([]byte("error\x00" + path + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyReport describes how a cached index differs from the working tree.
+type VerifyReport struct {
+	Root          string   `json:"root"`
+	Digest        string   `json:"digest,omitempty"`
+	Checked       int      `json:"checked"`
+	Rehashed      int      `json:"rehashed"`
+	Changed       []string `json:"changed,omitempty"`
+	Added         []string `json:"added,omitempty"`
+	Removed       []string `json:"removed,omitempty"`
+	ConfigChanged bool     `json:"config_changed,omitempty"`
+}
+
+// Stale reports whether the cached index no longer matches the working tree.
+func (r VerifyReport) Stale() bool {
+	return len(r.Changed) > 0 || len(r.Added) > 0 || len(r.Removed) > 0 || r.ConfigChanged
+}
+
+// Verify checks a cached index against the working tree under its root
+// without parsing anything. Files whose size and modification time match the
+// cache are trusted; the rest are rehashed and compared to their recorded
+// content hash, so a touched but unmodified file is not reported. The walk
+// applies the same hidden-directory, skip-dir, ignore, and language filters as
+// a build, which lets new files be detected too. New files behind symlinks
+// are not detected.
+func (b *Builder) Verify(ctx context.Context, cached *model.Index) (VerifyReport, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	report := VerifyReport{}
+	if cached == nil {
+		return report, nil
+	}
+	root := filepath.Clean(cached.Root)
+	report.Root = root
+	report.Digest = cached.Digest
+
+	if cached.ConfigHashes != nil {
+		current, err := ComputeConfigHashes(root)
+		if err != nil {
+			return report, err
+		}
+		report.ConfigChanged = !maps.Equal(cached.ConfigHashes, current)
+	}
+
+	known := make(map[string]model.FileSummary, len(cached.Files))
+	for _, file := range cached.Files {
+		known[file.Path] = file
+	}
+	unindexed := make(map[string]bool, len(cached.Errors)+len(cached.Skipped))
+	for _, parseErr := range cached.Errors {
+		unindexed[parseErr.Path] = true
+	}
+	for _, skipped := range cached.Skipped {
+		unindexed[skipped.Path] = true
+	}
+	seen := make(map[string]bool, len(known))
+
+	check := func(relPath, absPath string, info os.FileInfo) error {
+		summary := known[relPath]
+		report.Checked++
+		if info.Size() == summary.SizeBytes && info.ModTime().UnixNano() == summary.ModTimeUnixNano {
+			return nil
+		}
+		source, err := os.ReadFile(absPath)
+		if err != nil {
+			return err
+		}
+		report.Rehashed++
+		if summary.ContentHash == "" || ContentHash(source) != summary.ContentHash {
+			report.Changed = append(report.Changed, relPath)
+		}
+		return nil
+	}
+
+	err := filepath.WalkDir(root, func(absPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if absPath == root {
+			return nil
+		}
+		relPath, ok := slashpath.Rel(root, absPath)
+		if !ok {
+			return fmt.Errorf("%s is outside %s", absPath, root)
+		}
+		name := entry.Name()
+
+		if entry.IsDir() {
+			if strings.HasPrefix(name, ".") || defaultSkipDirs[name] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || strings.HasPrefix(name, ".") {
+			return nil
+		}
+		if b.ignore != nil && b.ignore.Match(relPath, false) {
+			return nil
+		}
+		if _, ok := b.parserForPath(absPath); !ok {
+			return nil
+		}
+
+		seen[relPath] = true
+		if _, ok := known[relPath]; !ok {
+			if !unindexed[relPath] && !b.skippedAsGenerated(relPath, absPath) {
+				report.Added = append(report.Added, relPath)
+			}
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		return check(relPath, absPath, info)
+	})
+	if err != nil {
+		return report, err
+	}
+
+	for relPath := range known {
+		if seen[relPath] {
+			continue
+		}
+		// The walk does not descend through symlinks; files a symlink-following
+		// build reached that way are checked individually.
+		absPath := slashpath.Join(root, relPath)
+		if info, statErr := os.Stat(absPath); b.followSymlinks && statErr == nil && info.Mode().IsRegular() {
+			if err := check(relPath, absPath, info); err != nil {
+				return report, err
+			}
+			continue
+		}
+		report.Removed = append(report.Removed, relPath)
+	}
+	sort.Strings(report.Changed)
+	sort.Strings(report.Added)
+	sort.Strings(report.Removed)
+	return report, nil
+}
+
+// skippedAsGenerated reports whether a build with SetSkipGenerated would have
+// left the file out of the index.
+func (b *Builder) skippedAsGenerated(relPath, absPath string) bool {
+	if !b.skipGenerated || b.detector == nil {
+		return false
+	}
+	source, err := os.ReadFile(absPath)
+	if err != nil {
+		return false
+	}
+	return b.detector.Detect(relPath, source) != nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/odvcencio/gts-suite/pkg/lang"
 	"github.com/odvcencio/gts-suite/pkg/lang/treesitter"
 	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
 )
 
 type WatchUpdateOptions struct {
@@ -87,6 +88,7 @@ func (s *WatchState) get(path string) (watchTreeState, bool) {
 
 func (b *Builder) ApplyWatchChanges(current *model.Index, changedAbsPaths []string, state *WatchState, opts WatchUpdateOptions) (*model.Index, BuildStats, error) {
 	stats := BuildStats{}
+	start := time.Now()
 	if current == nil {
 		return b.BuildPathIncremental(context.Background(), ".", nil)
 	}
@@ -117,6 +119,10 @@ func (b *Builder) ApplyWatchChanges(current *model.Index, changedAbsPaths []stri
 	for _, parseErr := range current.Errors {
 		errorsByPath[parseErr.Path] = parseErr
 	}
+	skippedByPath := make(map[string]model.SkippedFile, len(current.Skipped))
+	for _, skipped := range current.Skipped {
+		skippedByPath[skipped.Path] = skipped
+	}
 
 	changed := make([]string, 0, len(changedRel))
 	for relPath := range changedRel {
@@ -125,62 +131,25 @@ func (b *Builder) ApplyWatchChanges(current *model.Index, changedAbsPaths []stri
 	sort.Strings(changed)
 
 	for _, relPath := range changed {
-		absPath := filepath.Join(root, filepath.FromSlash(relPath))
-		info, err := os.Stat(absPath)
+		delete(skippedByPath, relPath)
+		change, ok, err := b.readWatchChange(root, relPath, state, opts)
 		if err != nil {
-			if os.IsNotExist(err) {
-				delete(filesByPath, relPath)
-				delete(errorsByPath, relPath)
-				if state != nil {
-					state.drop(relPath)
-				}
-				continue
-			}
 			return nil, stats, err
 		}
-		if info.IsDir() {
-			continue
-		}
-
-		parser, ok := b.ParserForPath(absPath)
 		if !ok {
-			delete(filesByPath, relPath)
-			delete(errorsByPath, relPath)
-			if state != nil {
-				state.drop(relPath)
-			}
 			continue
 		}
-
-		source, readErr := os.ReadFile(absPath)
-		if readErr != nil {
-			delete(filesByPath, relPath)
-			errorsByPath[relPath] = model.ParseError{
-				Path:  relPath,
-				Error: readErr.Error(),
-			}
-			if state != nil {
-				state.drop(relPath)
-			}
-			continue
-		}
-
-		summary, parseErr := parseWatchFile(relPath, absPath, source, info, parser, state, opts.SubfileIncremental)
-		if parseErr != nil {
-			delete(filesByPath, relPath)
-			errorsByPath[relPath] = model.ParseError{
-				Path:  relPath,
-				Error: parseErr.Error(),
-			}
-			if state != nil {
-				state.drop(relPath)
-			}
-			continue
-		}
-
+		delete(filesByPath, relPath)
 		delete(errorsByPath, relPath)
-		filesByPath[relPath] = summary
-		stats.ParsedFiles++
+		switch {
+		case change.summary != nil:
+			filesByPath[relPath] = *change.summary
+			stats.ParsedFiles++
+		case change.parseErr != nil:
+			errorsByPath[relPath] = *change.parseErr
+		case change.skipped != nil:
+			skippedByPath[relPath] = *change.skipped
+		}
 	}
 
 	next := &model.Index{
@@ -209,10 +178,63 @@ func (b *Builder) ApplyWatchChanges(current *model.Index, changedAbsPaths []stri
 	for _, relPath := range errorPaths {
 		next.Errors = append(next.Errors, errorsByPath[relPath])
 	}
+	next.Skipped = skippedFiles(skippedByPath)
+	next.Digest = Digest(next)
+	b.stampProvenance(context.Background(), next, start, stats, "")
 
 	return next, stats, nil
 }
 
+// watchChange is what re-reading one changed path found: its summary, the
+// error reading or parsing it, or why it was skipped. All are nil when the
+// path no longer holds an indexed file.
+type watchChange struct {
+	summary  *model.FileSummary
+	parseErr *model.ParseError
+	skipped  *model.SkippedFile
+}
+
+// readWatchChange re-reads relPath under root. It reports false for a
+// directory, which changes nothing by itself.
+func (b *Builder) readWatchChange(root, relPath string, state *WatchState, opts WatchUpdateOptions) (watchChange, bool, error) {
+	absPath := slashpath.Join(root, relPath)
+	info, err := os.Stat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			state.drop(relPath)
+			return watchChange{}, true, nil
+		}
+		return watchChange{}, false, err
+	}
+	if info.IsDir() {
+		return watchChange{}, false, nil
+	}
+
+	parser, ok := b.ParserForPath(absPath)
+	if !ok {
+		state.drop(relPath)
+		return watchChange{}, true, nil
+	}
+
+	if reason := b.guardReason(absPath, info.Size()); reason != "" {
+		state.drop(relPath)
+		return watchChange{skipped: &model.SkippedFile{Path: relPath, Reason: reason, SizeBytes: info.Size()}}, true, nil
+	}
+
+	source, readErr := os.ReadFile(absPath)
+	if readErr != nil {
+		state.drop(relPath)
+		return watchChange{parseErr: &model.ParseError{Path: relPath, Error: readErr.Error()}}, true, nil
+	}
+
+	summary, parseErr := parseWatchFile(relPath, absPath, source, info, parser, state, opts.SubfileIncremental)
+	if parseErr != nil {
+		state.drop(relPath)
+		return watchChange{parseErr: &model.ParseError{Path: relPath, Error: parseErr.Error()}}, true, nil
+	}
+	return watchChange{summary: &summary}, true, nil
+}
+
 type watchTreesitterParser interface {
 	TreesitterParser() (*treesitter.Parser, error)
 }
@@ -247,6 +269,7 @@ func parseWatchFile(relPath, absPath string, source []byte, info os.FileInfo, pa
 		fileSummary.Language = parser.Language()
 		fileSummary.SizeBytes = info.Size()
 		fileSummary.ModTimeUnixNano = info.ModTime().UnixNano()
+		fileSummary.ContentHash = ContentHash(source)
 		for i := range fileSummary.Symbols {
 			fileSummary.Symbols[i].File = relPath
 		}
@@ -271,6 +294,7 @@ func parseWatchFile(relPath, absPath string, source []byte, info os.FileInfo, pa
 	fileSummary.Language = parser.Language()
 	fileSummary.SizeBytes = info.Size()
 	fileSummary.ModTimeUnixNano = info.ModTime().UnixNano()
+	fileSummary.ContentHash = ContentHash(source)
 	for i := range fileSummary.Symbols {
 		fileSummary.Symbols[i].File = relPath
 	}
@@ -312,12 +336,8 @@ func normalizeChangedPaths(root string, changedAbsPaths []string) map[string]boo
 			}
 		}
 
-		relPath, err := filepath.Rel(root, absPath)
-		if err != nil || strings.HasPrefix(relPath, "..") {
-			continue
-		}
-		relPath = filepath.ToSlash(filepath.Clean(relPath))
-		if relPath == "." || relPath == "" {
+		relPath, ok := slashpath.Rel(root, absPath)
+		if !ok || relPath == "." {
 			continue
 		}
 		normalized[relPath] = true
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
+	"github.com/odvcencio/gts-suite/internal/config"
 	"github.com/odvcencio/gts-suite/pkg/generated"
 	"github.com/odvcencio/gts-suite/pkg/ignore"
 )
@@ -51,7 +53,8 @@ func workspaceIgnoreRoot(target string) (string, error) {
 }
 
 // LoadWorkspaceIgnoreMatcher finds the workspace root and loads ignore patterns
-// from .graftignore and .gtsignore files found there.
+// from .graftignore and .gtsignore files found there, and the directories the
+// exclude setting of .gts/config.yaml lists.
 func LoadWorkspaceIgnoreMatcher(target string) (*ignore.Matcher, error) {
 	root, err := workspaceIgnoreRoot(target)
 	if err != nil {
@@ -70,6 +73,11 @@ func LoadWorkspaceIgnoreMatcher(target string) (*ignore.Matcher, error) {
 		}
 		allPatterns = append(allPatterns, splitLines(string(data))...)
 	}
+	cfg, err := config.Load(target)
+	if err != nil {
+		return nil, err
+	}
+	allPatterns = append(allPatterns, cfg.ExcludePatterns()...)
 
 	if len(allPatterns) == 0 {
 		return nil, nil
@@ -105,7 +113,8 @@ func ComputeConfigHashes(target string) (map[string]string, error) {
 		return nil, err
 	}
 	hashes := make(map[string]string)
-	for _, name := range workspaceIgnoreFiles {
+	// .gitignore does not anchor a workspace, but its rules shape the index.
+	for _, name := range append(workspaceIgnoreFiles, ".gitignore") {
 		data, readErr := os.ReadFile(filepath.Join(root, name))
 		if readErr != nil {
 			continue
@@ -113,6 +122,26 @@ func ComputeConfigHashes(target string) (map[string]string, error) {
 		h := sha256.Sum256(data)
 		hashes[name] = fmt.Sprintf("%x", h)
 	}
+	// Its exclude setting shapes the index too, as do the tags queries it
+	// names, which the config file hash alone misses when they are edited.
+	if cfg, err := config.Load(target); err == nil && cfg != nil {
+		if data, readErr := os.ReadFile(cfg.Path); readErr == nil {
+			hashes[config.FileName] = fmt.Sprintf("%x", sha256.Sum256(data))
+		}
+		for _, files := range cfg.Tags {
+			for _, file := range files {
+				data, readErr := os.ReadFile(file)
+				if readErr != nil {
+					continue
+				}
+				name := file
+				if rel, relErr := filepath.Rel(cfg.Dir, file); relErr == nil {
+					name = filepath.ToSlash(rel)
+				}
+				hashes[name] = fmt.Sprintf("%x", sha256.Sum256(data))
+			}
+		}
+	}
 	if len(hashes) == 0 {
 		return nil, nil
 	}
@@ -130,9 +159,38 @@ func LoadWorkspaceGeneratedConfig(target string) ([]generated.ConfigEntry, int,
 	return generated.LoadConfigFileWithOptions(filepath.Join(root, ".gtsgenerated"))
 }
 
+// LoadWorkspaceTagsQueries adds the tags queries the tags setting of the
+// .gts/config.yaml at or above target names to builder's built-in queries.
+// A missing file, an unknown language, or a query that does not compile is
+// an error, so a broken override fails before anything is indexed.
+func LoadWorkspaceTagsQueries(builder *Builder, target string) error {
+	cfg, err := config.Load(target)
+	if err != nil || cfg == nil {
+		return err
+	}
+	languages := make([]string, 0, len(cfg.Tags))
+	for language := range cfg.Tags {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	for _, language := range languages {
+		for _, file := range cfg.Tags[language] {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("%s: tags query for %s: %w", cfg.Path, language, err)
+			}
+			if err := builder.AddTagsQuery(language, string(data)); err != nil {
+				return fmt.Errorf("%s: tags query %s for %s: %w", cfg.Path, file, language, err)
+			}
+		}
+	}
+	return nil
+}
+
 // NewBuilderWithWorkspaceIgnores creates a Builder pre-configured with ignore
 // patterns and generated-file detection from the workspace config files found
-// at or above target.
+// at or above target, with the .gitignore rules that apply to target, and
+// with the tags queries .gts/config.yaml adds.
 func NewBuilderWithWorkspaceIgnores(target string) (*Builder, error) {
 	builder := NewBuilder()
 	matcher, err := LoadWorkspaceIgnoreMatcher(target)
@@ -142,11 +200,19 @@ func NewBuilderWithWorkspaceIgnores(target string) (*Builder, error) {
 	if matcher != nil {
 		builder.SetIgnore(matcher)
 	}
+	gitignore, err := LoadGitignoreMatcher(target)
+	if err != nil {
+		return nil, err
+	}
+	builder.SetGitignore(gitignore)
 	configs, scanDepth, err := LoadWorkspaceGeneratedConfig(target)
 	if err != nil {
 		return nil, err
 	}
 	builder.SetDetector(generated.NewDetector(configs, scanDepth))
+	if err := LoadWorkspaceTagsQueries(builder, target); err != nil {
+		return nil, err
+	}
 
 	hashes, err := ComputeConfigHashes(target)
 	if err != nil {
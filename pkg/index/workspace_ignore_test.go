@@ -31,3 +31,53 @@ func TestLoadWorkspaceGeneratedConfig_NoFile(t *testing.T) {
 		t.Fatalf("expected 0 entries, got %d", len(entries))
 	}
 }
+
+func TestNewBuilderWithWorkspaceIgnoresAddsTagsQueries(t *testing.T) {
+	root := t.TempDir()
+	writeFile := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(".gts/config.yaml", "tags:\n  python: .gts/tags/constants.scm\n")
+	writeFile(".gts/tags/constants.scm", "(assignment left: (identifier) @name) @definition.constant\n")
+	writeFile("app.py", "TIMEOUT = 30\n\ndef run():\n    return TIMEOUT\n")
+
+	builder, err := NewBuilderWithWorkspaceIgnores(root)
+	if err != nil {
+		t.Fatalf("NewBuilderWithWorkspaceIgnores: %v", err)
+	}
+	idx, err := builder.BuildPath(root)
+	if err != nil {
+		t.Fatalf("BuildPath: %v", err)
+	}
+	kinds := map[string]string{}
+	for _, file := range idx.Files {
+		for _, sym := range file.Symbols {
+			kinds[sym.Name] = sym.Kind
+		}
+	}
+	if kinds["TIMEOUT"] != "constant_definition" {
+		t.Fatalf("expected TIMEOUT from the added query, got symbols %v", kinds)
+	}
+	if kinds["run"] != "function_definition" {
+		t.Fatalf("expected run from the built-in query, got symbols %v", kinds)
+	}
+	if _, ok := idx.ConfigHashes[".gts/tags/constants.scm"]; !ok {
+		t.Fatalf("expected the tags query in config hashes, got %v", idx.ConfigHashes)
+	}
+
+	writeFile(".gts/tags/constants.scm", "(module (no_such_node) @definition.constant)\n")
+	if _, err := NewBuilderWithWorkspaceIgnores(root); err == nil {
+		t.Fatal("expected an invalid tags query to fail")
+	}
+	writeFile(".gts/config.yaml", "tags:\n  klingon: .gts/tags/constants.scm\n")
+	if _, err := NewBuilderWithWorkspaceIgnores(root); err == nil {
+		t.Fatal("expected an unknown language to fail")
+	}
+}
@@ -0,0 +1,159 @@
+// Package notebook indexes Jupyter notebooks (.ipynb) as Python. A notebook
+// is indexed as the source of its code cells joined in order, and the
+// summary's Cells map each line of that source back to its cell.
+package notebook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/lang"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Extension is the file extension of Jupyter notebooks.
+const Extension = ".ipynb"
+
+// pythonCellMagics are the cell magics whose body is still Python.
+var pythonCellMagics = map[string]bool{
+	"capture": true,
+	"prun":    true,
+	"time":    true,
+	"timeit":  true,
+}
+
+type document struct {
+	Cells    []cell `json:"cells"`
+	Metadata struct {
+		Kernelspec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+type cell struct {
+	CellType string          `json:"cell_type"`
+	Source   json.RawMessage `json:"source"`
+}
+
+// Parser parses notebooks with the parser of their kernel's language, which
+// must be Python.
+type Parser struct {
+	python lang.Parser
+}
+
+// NewParser returns a Parser that parses the code of notebooks with python.
+func NewParser(python lang.Parser) *Parser {
+	return &Parser{python: python}
+}
+
+// Language returns the language notebooks are indexed as.
+func (p *Parser) Language() string {
+	return p.python.Language()
+}
+
+// Parse summarizes the code cells of the notebook src. Line numbers in the
+// summary count lines of the joined cell source; Cells maps them back to
+// cells. A notebook whose kernel is not Python has no symbols.
+func (p *Parser) Parse(path string, src []byte) (model.FileSummary, error) {
+	source, cells, language, err := Extract(src)
+	if err != nil {
+		return model.FileSummary{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if language != "" && language != "python" {
+		return model.FileSummary{Path: path, Language: p.Language()}, nil
+	}
+	summary, err := p.python.Parse(path, source)
+	if err != nil {
+		return model.FileSummary{}, err
+	}
+	summary.Cells = cells
+	return summary, nil
+}
+
+// Extract joins the source of the notebook src's code cells, in order and
+// each ending in a newline, and returns it with the lines each cell occupies
+// and the kernel's language, "" when the notebook does not say. IPython
+// magics and shell escapes are commented out, keeping line numbers intact,
+// as are whole cells run by a non-Python cell magic such as %%bash.
+func Extract(src []byte) ([]byte, []model.NotebookCell, string, error) {
+	var doc document
+	if err := json.Unmarshal(src, &doc); err != nil {
+		return nil, nil, "", fmt.Errorf("decode notebook: %w", err)
+	}
+	language := strings.ToLower(doc.Metadata.LanguageInfo.Name)
+	if language == "" {
+		language = strings.ToLower(doc.Metadata.Kernelspec.Language)
+	}
+
+	var out strings.Builder
+	var cells []model.NotebookCell
+	line := 1
+	for i, c := range doc.Cells {
+		if c.CellType != "code" {
+			continue
+		}
+		text, err := cellSource(c.Source)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("cell %d: %w", i+1, err)
+		}
+		if text == "" {
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+		commentAll := false
+		if magic, ok := strings.CutPrefix(strings.TrimSpace(lines[0]), "%%"); ok {
+			name, _, _ := strings.Cut(magic, " ")
+			commentAll = !pythonCellMagics[name]
+			lines[0] = "# " + lines[0]
+		}
+		for j, code := range lines {
+			trimmed := strings.TrimSpace(code)
+			if commentAll && j > 0 || strings.HasPrefix(trimmed, "%") || strings.HasPrefix(trimmed, "!") {
+				code = "# " + code
+			}
+			out.WriteString(code)
+			out.WriteByte('\n')
+		}
+		cells = append(cells, model.NotebookCell{Cell: i + 1, StartLine: line, EndLine: line + len(lines) - 1})
+		line += len(lines)
+	}
+	return []byte(out.String()), cells, language, nil
+}
+
+// cellSource decodes a cell's source, which nbformat writes either as one
+// string or as a list of lines.
+func cellSource(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+	var lines []string
+	if err := json.Unmarshal(raw, &lines); err != nil {
+		return "", fmt.Errorf("decode source: %w", err)
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// ReadSource reads the file at path as it is indexed: the joined code cells
+// of a notebook, or the contents of any other file.
+func ReadSource(path string) ([]byte, error) {
+	src, err := os.ReadFile(path)
+	if err != nil || !strings.EqualFold(filepath.Ext(path), Extension) {
+		return src, err
+	}
+	source, _, _, err := Extract(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return source, nil
+}
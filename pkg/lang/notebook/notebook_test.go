@@ -0,0 +1,67 @@
+package notebook
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+const sample = `{
+ "cells": [
+  {"cell_type": "markdown", "metadata": {}, "source": ["# Analysis\n"]},
+  {"cell_type": "code", "metadata": {}, "outputs": [], "source": ["%matplotlib inline\n", "import pandas as pd\n", "\n", "def load(path):\n", "    return pd.read_csv(path)\n"]},
+  {"cell_type": "code", "metadata": {}, "outputs": [], "source": "%%bash\nls -la"},
+  {"cell_type": "code", "metadata": {}, "outputs": [], "source": []},
+  {"cell_type": "code", "metadata": {}, "outputs": [], "source": "%%time\n!pip install pandas\nframe = load('x.csv')\n"}
+ ],
+ "metadata": {"kernelspec": {"language": "python", "name": "python3"}},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}`
+
+func TestExtract(t *testing.T) {
+	source, cells, language, err := Extract([]byte(sample))
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+	const want = `# %matplotlib inline
+import pandas as pd
+
+def load(path):
+    return pd.read_csv(path)
+# %%bash
+# ls -la
+# %%time
+# !pip install pandas
+frame = load('x.csv')
+`
+	if string(source) != want {
+		t.Fatalf("source = %q, want %q", source, want)
+	}
+	wantCells := []model.NotebookCell{
+		{Cell: 2, StartLine: 1, EndLine: 5},
+		{Cell: 3, StartLine: 6, EndLine: 7},
+		{Cell: 5, StartLine: 8, EndLine: 10},
+	}
+	if !reflect.DeepEqual(cells, wantCells) {
+		t.Fatalf("cells = %+v, want %+v", cells, wantCells)
+	}
+	if language != "python" {
+		t.Fatalf("language = %q, want python", language)
+	}
+
+	summary := model.FileSummary{Cells: cells}
+	if cell, line, ok := summary.CellLine(4); !ok || cell != 2 || line != 4 {
+		t.Fatalf("CellLine(4) = %d, %d, %t, want 2, 4, true", cell, line, ok)
+	}
+	if cell, line, ok := summary.CellLine(10); !ok || cell != 5 || line != 3 {
+		t.Fatalf("CellLine(10) = %d, %d, %t, want 5, 3, true", cell, line, ok)
+	}
+}
+
+func TestExtractRejectsInvalidNotebook(t *testing.T) {
+	if _, _, _, err := Extract([]byte("not json")); err == nil {
+		t.Fatal("expected an error for a file that is not a notebook")
+	}
+}
@@ -0,0 +1,182 @@
+package treesitter
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// applyAttributes records on symbol the decorators, annotations, and
+// attributes written on its declaration, in source order and as written
+// with whitespace collapsed: "@app.route('/users')" in Python,
+// "@Override" in Java, "[HttpGet]" in C#, "#[test]" in Rust. Attributes
+// are read from the lines directly above the declaration, where Python,
+// TypeScript, and Rust put them, and from the head of the declaration
+// itself, where Java and C# include them in the node.
+func applyAttributes(symbol *model.Symbol, language string, src []byte, startByte uint32) {
+	if language == "go" {
+		return
+	}
+	attributes := precedingAttributes(src, startByte, language)
+	if int(startByte) < len(src) {
+		attributes = append(attributes, leadingAttributes(src[startByte:], language)...)
+	}
+	symbol.Attributes = attributes
+}
+
+// precedingAttributes returns the attribute lines directly above the line
+// containing offset, top to bottom.
+func precedingAttributes(src []byte, offset uint32, language string) []string {
+	end := int(offset)
+	if end > len(src) {
+		end = len(src)
+	}
+	for end > 0 && src[end-1] != '\n' {
+		end--
+	}
+
+	var lines []string
+	for end > 0 {
+		start := end - 1
+		for start > 0 && src[start-1] != '\n' {
+			start--
+		}
+		line := strings.TrimSpace(string(src[start : end-1]))
+		if attributeLength(line, language) != len(line) {
+			break
+		}
+		lines = append(lines, collapseSpace(line))
+		end = start
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// leadingAttributes returns the attributes at the start of a declaration's
+// text, stopping at the first token that is not one.
+func leadingAttributes(text []byte, language string) []string {
+	var attributes []string
+	rest := string(text)
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		n := attributeLength(rest, language)
+		if n == 0 {
+			return attributes
+		}
+		attributes = append(attributes, collapseSpace(rest[:n]))
+		rest = rest[n:]
+	}
+}
+
+// attributeLength returns the length of the attribute that text starts
+// with, or 0 if it starts with none: "@name" with an optional
+// parenthesized argument list, "#[...]", or, in C#, "[...]".
+func attributeLength(text, language string) int {
+	switch {
+	case strings.HasPrefix(text, "#["):
+		return bracketedLength(text, 1)
+	case strings.HasPrefix(text, "[") && language == "c_sharp":
+		return bracketedLength(text, 0)
+	case strings.HasPrefix(text, "@"):
+		n := 1
+		for n < len(text) && (text[n] == '_' || text[n] == '.' || isWordByte(text[n])) {
+			n++
+		}
+		if n == 1 || text[1] == '.' {
+			return 0
+		}
+		if n < len(text) && text[n] == '(' {
+			if closed := bracketedLength(text, n); closed > 0 {
+				return closed
+			}
+		}
+		return n
+	}
+	return 0
+}
+
+// bracketedLength returns the length of text up to and including the
+// bracket closing the one at open, or 0 if it is unclosed. Brackets in
+// string literals are skipped.
+func bracketedLength(text string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'' || ch == '`':
+			quote = ch
+		case ch == '(' || ch == '[' || ch == '{':
+			depth++
+		case ch == ')' || ch == ']' || ch == '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+func isWordByte(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// goStructTagAttributes splits a Go struct tag literal such as
+// `json:"name,omitempty" db:"name"` into its key:"value" pairs.
+func goStructTagAttributes(literal string) []string {
+	tag, err := strconv.Unquote(strings.TrimSpace(literal))
+	if err != nil {
+		return nil
+	}
+	var pairs []string
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		colon := strings.Index(tag, `:"`)
+		if colon <= 0 || strings.ContainsAny(tag[:colon], " \"") {
+			return pairs
+		}
+		end := colon + 2
+		for end < len(tag) && tag[end] != '"' {
+			if tag[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(tag) {
+			return pairs
+		}
+		pairs = append(pairs, tag[:end+1])
+		tag = tag[end+1:]
+	}
+}
+
+// goBuildConstraints returns the //go:build and // +build lines of a Go
+// file's header, before its package clause.
+func goBuildConstraints(src []byte) []string {
+	var constraints []string
+	for _, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "//go:build "), strings.HasPrefix(line, "// +build "):
+			constraints = append(constraints, collapseSpace(line))
+		case strings.HasPrefix(line, "package "):
+			return constraints
+		}
+	}
+	return constraints
+}
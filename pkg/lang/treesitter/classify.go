@@ -62,6 +62,12 @@ var ImportNodeTypesByLanguage = map[string]map[string]bool{
 		"use_declaration":           true,
 		"namespace_use_declaration": true,
 	},
+	"swift": {
+		"import_declaration": true,
+	},
+	"scala": {
+		"import_declaration": true,
+	},
 }
 
 func isImportNodeType(language, nodeType string) bool {
@@ -0,0 +1,91 @@
+package treesitter
+
+import (
+	"strconv"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// closureNodeTypes lists, per language, the node types of anonymous
+// functions.
+var closureNodeTypes = map[string]map[string]bool{
+	"go":         {"func_literal": true},
+	"javascript": {"arrow_function": true, "function_expression": true, "function": true},
+	"typescript": {"arrow_function": true, "function_expression": true, "function": true},
+	"tsx":        {"arrow_function": true, "function_expression": true, "function": true},
+	"python":     {"lambda": true},
+	"rust":       {"closure_expression": true},
+	"java":       {"lambda_expression": true},
+}
+
+// extractClosures returns a closure_definition symbol for each anonymous
+// function nested in a function, method, or another closure of symbols, so
+// calls made in goroutine bodies, callbacks, and inline handlers can be told
+// apart from those of the enclosing function. Closures are named as the Go
+// compiler names them: the enclosing function's name, then ".func" and a
+// 1-based ordinal among that function's closures, as in "Serve.func1" and
+// "Serve.func1.func1". A closure that is the value of a named declaration,
+// such as "const f = () => {}", is already the symbol f, and one outside any
+// function is left out.
+func (p *Parser) extractClosures(root *gotreesitter.Node, src []byte, symbols []model.Symbol) []model.Symbol {
+	nodeTypes, ok := closureNodeTypes[p.entry.Name]
+	if !ok || root == nil {
+		return nil
+	}
+
+	// Enclosing candidates: the named callables, then each closure as it is
+	// found. The walk is in source order, so outer closures come first.
+	var parents []model.Symbol
+	for _, symbol := range symbols {
+		if model.IsFunctionKind(symbol.Kind) || symbol.Kind == "method_definition" {
+			parents = append(parents, symbol)
+		}
+	}
+	if len(parents) == 0 {
+		return nil
+	}
+
+	var closures []model.Symbol
+	ordinals := map[string]int{}
+	gotreesitter.Walk(root, func(node *gotreesitter.Node, depth int) gotreesitter.WalkAction {
+		if node == nil || !node.IsNamed() || !nodeTypes[node.Type(p.lang)] {
+			return gotreesitter.WalkContinue
+		}
+		start, end := int(node.StartByte()), int(node.EndByte())
+		parent := -1
+		for i, candidate := range parents {
+			if candidate.EndByte == end && candidate.StartByte <= start {
+				// The closure is the body of a named declaration.
+				return gotreesitter.WalkContinue
+			}
+			if candidate.StartByte > start || candidate.EndByte < end {
+				continue
+			}
+			if parent < 0 || candidate.EndByte-candidate.StartByte < parents[parent].EndByte-parents[parent].StartByte {
+				parent = i
+			}
+		}
+		if parent < 0 {
+			return gotreesitter.WalkContinue
+		}
+
+		owner := parents[parent]
+		key := owner.Name + "\x00" + strconv.Itoa(owner.StartByte)
+		ordinals[key]++
+		symbol := model.Symbol{
+			Kind:          "closure_definition",
+			Name:          owner.Name + ".func" + strconv.Itoa(ordinals[key]),
+			Signature:     summarizeSignature(node.Text(src)),
+			StartLine:     int(node.StartPoint().Row) + 1,
+			EndLine:       int(node.EndPoint().Row) + 1,
+			ContainerPath: owner.ContainerPath,
+		}
+		setSymbolRange(&symbol, node.Range())
+		closures = append(closures, symbol)
+		parents = append(parents, symbol)
+		return gotreesitter.WalkContinue
+	})
+	return closures
+}
@@ -0,0 +1,241 @@
+package treesitter
+
+import (
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// memberSyntax describes how a language declares struct fields or class
+// members and how it accesses them. Only languages whose member accesses are
+// always explicit (a receiver, self, or this) are listed, so an access the
+// walk does not see is genuinely absent.
+type memberSyntax struct {
+	declarations map[string]bool // node types declaring fields by name
+	containers   map[string]bool // node types owning those declarations
+	access       string          // member access node type
+	member       string          // field of access holding the member name
+}
+
+var memberSyntaxByLanguage = map[string]memberSyntax{
+	"go": {
+		declarations: map[string]bool{"field_declaration": true},
+		containers:   map[string]bool{"type_spec": true},
+		access:       "selector_expression",
+		member:       "field",
+	},
+	"rust": {
+		declarations: map[string]bool{"field_declaration": true},
+		containers:   map[string]bool{"struct_item": true},
+		access:       "field_expression",
+		member:       "field",
+	},
+	"javascript": {
+		declarations: map[string]bool{"field_definition": true},
+		containers:   map[string]bool{"class_declaration": true, "class": true},
+		access:       "member_expression",
+		member:       "property",
+	},
+	"typescript": {
+		declarations: map[string]bool{"public_field_definition": true},
+		containers:   map[string]bool{"class_declaration": true, "abstract_class_declaration": true, "class": true},
+		access:       "member_expression",
+		member:       "property",
+	},
+	"tsx": {
+		declarations: map[string]bool{"public_field_definition": true},
+		containers:   map[string]bool{"class_declaration": true, "abstract_class_declaration": true, "class": true},
+		access:       "member_expression",
+		member:       "property",
+	},
+	"python": {
+		containers: map[string]bool{"class_definition": true},
+		access:     "attribute",
+		member:     "attribute",
+	},
+}
+
+// memberNameTypes are the node types that name a field.
+var memberNameTypes = map[string]bool{
+	"field_identifier":            true,
+	"property_identifier":         true,
+	"private_property_identifier": true,
+	"identifier":                  true,
+}
+
+// extractFields returns a field_definition symbol for each struct field or
+// class member declared in the file. Python has no field declarations, so a
+// class-level assignment or the first "self.name = ..." in a class declares
+// the member.
+func (p *Parser) extractFields(root *gotreesitter.Node, src []byte) []model.Symbol {
+	syntax, ok := memberSyntaxByLanguage[p.entry.Name]
+	if !ok || root == nil {
+		return nil
+	}
+
+	var fields []model.Symbol
+	seen := map[string]bool{}
+	add := func(decl, name *gotreesitter.Node, container string) {
+		fieldName := strings.TrimSpace(name.Text(src))
+		if fieldName == "" || container == "" {
+			return
+		}
+		key := container + "." + fieldName
+		if p.entry.Name == "python" {
+			if seen[key] {
+				return
+			}
+			seen[key] = true
+		}
+		symbol := model.Symbol{
+			Kind:      "field_definition",
+			Name:      fieldName,
+			Signature: summarizeSignature(decl.Text(src)),
+			Receiver:  container,
+			StartLine: int(decl.StartPoint().Row) + 1,
+			EndLine:   int(decl.EndPoint().Row) + 1,
+		}
+		setSymbolRange(&symbol, decl.Range())
+		applyModifiers(&symbol, p.entry.Name, src, decl.StartByte())
+		applyAttributes(&symbol, p.entry.Name, src, decl.StartByte())
+		if tag := decl.ChildByFieldName("tag", p.lang); tag != nil && p.entry.Name == "go" {
+			symbol.Attributes = goStructTagAttributes(tag.Text(src))
+		}
+		fields = append(fields, symbol)
+	}
+
+	gotreesitter.Walk(root, func(node *gotreesitter.Node, depth int) gotreesitter.WalkAction {
+		if node == nil {
+			return gotreesitter.WalkContinue
+		}
+		nodeType := node.Type(p.lang)
+		switch {
+		case syntax.declarations[nodeType]:
+			container := p.memberContainer(node, src, syntax)
+			for i := 0; i < node.ChildCount(); i++ {
+				child := node.Child(i)
+				if child != nil && child.IsNamed() && memberNameTypes[child.Type(p.lang)] && child.Type(p.lang) != "identifier" {
+					add(node, child, container)
+				}
+			}
+		case p.entry.Name == "python" && nodeType == "assignment":
+			left := node.ChildByFieldName("left", p.lang)
+			if left == nil {
+				break
+			}
+			switch left.Type(p.lang) {
+			case "identifier":
+				// A class attribute: an assignment directly in the class body.
+				block := node.Parent()
+				if block != nil && block.Type(p.lang) == "expression_statement" {
+					block = block.Parent()
+				}
+				if block != nil && block.Parent() != nil && block.Parent().Type(p.lang) == "class_definition" {
+					add(node, left, firstIdentifierText(block.Parent(), p.lang, src))
+				}
+			case "attribute":
+				object := left.ChildByFieldName("object", p.lang)
+				attribute := left.ChildByFieldName("attribute", p.lang)
+				if object != nil && attribute != nil && object.Text(src) == "self" {
+					add(node, attribute, p.memberContainer(node, src, syntax))
+				}
+			}
+		}
+		return gotreesitter.WalkContinue
+	})
+	return fields
+}
+
+// extractMemberReferences returns a reference.field reference for each
+// member access that is not the callee of a call, marked as a read or a
+// write depending on whether it is the target of an assignment.
+func (p *Parser) extractMemberReferences(root *gotreesitter.Node, src []byte) []model.Reference {
+	syntax, ok := memberSyntaxByLanguage[p.entry.Name]
+	if !ok || root == nil {
+		return nil
+	}
+
+	var references []model.Reference
+	gotreesitter.Walk(root, func(node *gotreesitter.Node, depth int) gotreesitter.WalkAction {
+		if node == nil || node.Type(p.lang) != syntax.access {
+			return gotreesitter.WalkContinue
+		}
+		name := node.ChildByFieldName(syntax.member, p.lang)
+		if name == nil || !memberNameTypes[name.Type(p.lang)] || p.isCallee(node) {
+			return gotreesitter.WalkContinue
+		}
+		access := "read"
+		if p.isAssignmentTarget(node) {
+			access = "write"
+		}
+
+		nameRange := name.Range()
+		startLine := int(nameRange.StartPoint.Row) + 1
+		startCol := int(nameRange.StartPoint.Column) + 1
+		endCol := int(nameRange.EndPoint.Column) + 1
+		references = append(references, model.Reference{
+			Kind:        "reference.field",
+			Name:        strings.TrimSpace(name.Text(src)),
+			StartLine:   startLine,
+			EndLine:     int(nameRange.EndPoint.Row) + 1,
+			StartColumn: startCol,
+			EndColumn:   endCol,
+			Qualifier:   referenceQualifier(src, gotreesitter.Tag{Range: node.Range(), NameRange: nameRange}),
+			Access:      access,
+		})
+		return gotreesitter.WalkContinue
+	})
+	return references
+}
+
+// memberContainer returns the name of the type or class enclosing node.
+func (p *Parser) memberContainer(node *gotreesitter.Node, src []byte, syntax memberSyntax) string {
+	for current := node.Parent(); current != nil; current = current.Parent() {
+		if syntax.containers[current.Type(p.lang)] {
+			return firstIdentifierText(current, p.lang, src)
+		}
+	}
+	return ""
+}
+
+// isCallee reports whether access is the function being called, as in
+// s.Close() or this.run(), which is a method call rather than a field read.
+func (p *Parser) isCallee(access *gotreesitter.Node) bool {
+	parent := access.Parent()
+	if parent == nil {
+		return false
+	}
+	switch parent.Type(p.lang) {
+	case "call_expression", "call":
+		return sameNode(parent.ChildByFieldName("function", p.lang), access)
+	}
+	return false
+}
+
+// isAssignmentTarget reports whether access is assigned to, including
+// compound assignments and Go's x.n++, which never use the value elsewhere.
+func (p *Parser) isAssignmentTarget(access *gotreesitter.Node) bool {
+	target := access
+	parent := access.Parent()
+	// Go wraps both sides of an assignment in expression lists.
+	if parent != nil && parent.Type(p.lang) == "expression_list" {
+		target, parent = parent, parent.Parent()
+	}
+	if parent == nil {
+		return false
+	}
+	switch parent.Type(p.lang) {
+	case "assignment_statement", "assignment", "augmented_assignment", "assignment_expression",
+		"augmented_assignment_expression", "compound_assignment_expr":
+		return sameNode(parent.ChildByFieldName("left", p.lang), target)
+	case "inc_statement", "dec_statement":
+		return true
+	}
+	return false
+}
+
+func sameNode(a, b *gotreesitter.Node) bool {
+	return a != nil && b != nil && a.StartByte() == b.StartByte() && a.EndByte() == b.EndByte()
+}
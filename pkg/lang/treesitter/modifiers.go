@@ -0,0 +1,178 @@
+package treesitter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// applyModifiers fills the visibility and modifier flags of symbol from its
+// declaration head: the text on the symbol's first line up to its name, plus
+// any decorator or annotation lines directly above it.
+func applyModifiers(symbol *model.Symbol, language string, src []byte, startByte uint32) {
+	head := linePrefix(src, startByte) + " " + symbol.Signature
+	words := modifierWords(head, symbol.Name)
+	decorators := precedingDecorators(src, startByte)
+
+	symbol.Static = words["static"]
+	symbol.Async = words["async"]
+	symbol.Abstract = words["abstract"]
+	symbol.Visibility = explicitVisibility(words)
+
+	switch language {
+	case "go":
+		symbol.Visibility = goVisibility(symbol.Name)
+	case "python":
+		symbol.Visibility = underscoreVisibility(symbol.Name)
+		symbol.Static = decorators["staticmethod"]
+		symbol.Abstract = decorators["abstractmethod"]
+	case "javascript", "typescript", "tsx":
+		switch {
+		case symbol.Visibility != "":
+		case strings.HasPrefix(symbol.Name, "#"):
+			symbol.Visibility = "private"
+		case symbol.Receiver != "" || words["export"]:
+			symbol.Visibility = "public"
+		default:
+			symbol.Visibility = "private"
+		}
+	case "java":
+		if symbol.Visibility == "" {
+			symbol.Visibility = "package"
+		}
+	case "c_sharp":
+		if symbol.Visibility == "" {
+			symbol.Visibility = "internal"
+			if symbol.Receiver != "" {
+				symbol.Visibility = "private"
+			}
+		}
+	case "rust":
+		switch {
+		case strings.Contains(head, "pub("):
+			symbol.Visibility = "internal"
+		case words["pub"]:
+			symbol.Visibility = "public"
+		default:
+			symbol.Visibility = "private"
+		}
+	case "kotlin", "scala", "php":
+		if symbol.Visibility == "" {
+			symbol.Visibility = "public"
+		}
+	case "swift":
+		if words["open"] {
+			symbol.Visibility = "public"
+		}
+		if symbol.Visibility == "" {
+			symbol.Visibility = "internal"
+		}
+	case "c":
+		if symbol.Static {
+			symbol.Visibility = "private"
+		} else {
+			symbol.Visibility = "public"
+		}
+	}
+	symbol.Exported = symbol.Visibility == "public"
+}
+
+func explicitVisibility(words map[string]bool) string {
+	for _, keyword := range []string{"private", "protected", "internal", "public"} {
+		if words[keyword] {
+			return keyword
+		}
+	}
+	if words["fileprivate"] {
+		return "private"
+	}
+	return ""
+}
+
+func goVisibility(name string) string {
+	r, _ := utf8.DecodeRuneInString(name)
+	if unicode.IsUpper(r) {
+		return "public"
+	}
+	return "private"
+}
+
+// underscoreVisibility applies the Python convention: a leading underscore
+// marks an internal name, dunder methods are public.
+func underscoreVisibility(name string) string {
+	if strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__") {
+		return "public"
+	}
+	if strings.HasPrefix(name, "_") {
+		return "private"
+	}
+	return "public"
+}
+
+// modifierWords returns the identifier-like words of head that precede name.
+func modifierWords(head, name string) map[string]bool {
+	words := map[string]bool{}
+	fields := strings.FieldsFunc(head, func(r rune) bool {
+		return !(r == '_' || r == '#' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+	for _, field := range fields {
+		if field == name || strings.TrimPrefix(field, "#") == strings.TrimPrefix(name, "#") {
+			break
+		}
+		words[field] = true
+	}
+	return words
+}
+
+// linePrefix returns the text between the start of the line containing
+// offset and offset itself, e.g. "export " before a JavaScript function.
+func linePrefix(src []byte, offset uint32) string {
+	end := int(offset)
+	if end > len(src) {
+		end = len(src)
+	}
+	start := end
+	for start > 0 && src[start-1] != '\n' {
+		start--
+	}
+	return string(src[start:end])
+}
+
+// precedingDecorators returns the names of the "@name" lines directly above
+// the line containing offset, lowercased and without arguments.
+func precedingDecorators(src []byte, offset uint32) map[string]bool {
+	end := int(offset)
+	if end > len(src) {
+		end = len(src)
+	}
+	for end > 0 && src[end-1] != '\n' {
+		end--
+	}
+
+	var decorators map[string]bool
+	for end > 0 {
+		start := end - 1
+		for start > 0 && src[start-1] != '\n' {
+			start--
+		}
+		line := strings.TrimSpace(string(src[start : end-1]))
+		if !strings.HasPrefix(line, "@") {
+			break
+		}
+		name := strings.TrimPrefix(line, "@")
+		if idx := strings.IndexAny(name, "( "); idx >= 0 {
+			name = name[:idx]
+		}
+		if idx := strings.LastIndex(name, "."); idx >= 0 {
+			name = name[idx+1:]
+		}
+		if decorators == nil {
+			decorators = map[string]bool{}
+		}
+		decorators[strings.ToLower(name)] = true
+		end = start
+	}
+	return decorators
+}
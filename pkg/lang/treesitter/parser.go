@@ -192,7 +192,10 @@ func (p *Parser) buildSummaryFromRoot(path string, src []byte, root *gotreesitte
 	tags := p.extractTags(root, src)
 	summary.Imports = p.extractImports(root, src)
 	summary.Symbols = p.extractSymbols(src, root, tags)
-	summary.References = p.extractReferences(tags)
+	summary.References = p.extractReferences(src, root, tags)
+	if p.entry.Name == "go" {
+		summary.Attributes = goBuildConstraints(src)
+	}
 	return summary
 }
 
@@ -383,10 +386,15 @@ func (p *Parser) extractImports(root *gotreesitter.Node, src []byte) []string {
 			return gotreesitter.WalkContinue
 		}
 
-		if p.entry.Name == "ruby" && nodeType == "call" {
+		switch {
+		case p.entry.Name == "ruby" && nodeType == "call":
 			if imp := extractRubyRequireImport(strings.TrimSpace(node.Text(src))); imp != "" {
 				add(imp)
 			}
+		case p.entry.Name == "elixir" && nodeType == "call":
+			add(extractElixirImport(strings.TrimSpace(node.Text(src))))
+		case p.entry.Name == "zig" && nodeType == "SuffixExpr":
+			add(extractZigImport(strings.TrimSpace(node.Text(src))))
 		}
 
 		return gotreesitter.WalkContinue
@@ -404,18 +412,25 @@ func (p *Parser) extractImports(root *gotreesitter.Node, src []byte) []string {
 }
 
 func (p *Parser) extractSymbols(src []byte, root *gotreesitter.Node, tags []gotreesitter.Tag) []model.Symbol {
-	if len(tags) == 0 {
+	fields := p.extractFields(root, src)
+	if len(tags) == 0 && len(fields) == 0 && !reactLanguages[p.entry.Name] {
 		return nil
 	}
 
-	symbols := make([]model.Symbol, 0, len(tags))
+	symbols := make([]model.Symbol, 0, len(tags)+len(fields))
 	seen := map[string]struct{}{}
+	candidates := make([]model.Symbol, 0, len(tags)+len(fields))
 	for _, tag := range tags {
 		symbol, ok := symbolFromTag(src, root, p.lang, p.entry.Name, tag)
-		if !ok {
-			continue
+		if ok {
+			candidates = append(candidates, symbol)
 		}
-
+	}
+	candidates = append(candidates, fields...)
+	if reactLanguages[p.entry.Name] {
+		candidates = p.classifyReact(root, src, candidates)
+	}
+	for _, symbol := range candidates {
 		key := symbol.Kind + "|" + symbol.Name + "|" + strconv.Itoa(symbol.StartLine) + "|" + strconv.Itoa(symbol.EndLine)
 		if _, exists := seen[key]; exists {
 			continue
@@ -424,6 +439,23 @@ func (p *Parser) extractSymbols(src []byte, root *gotreesitter.Node, tags []gotr
 		symbols = append(symbols, symbol)
 	}
 
+	sortSymbols(symbols)
+	model.AssignContainerPaths(symbols)
+	for i := range symbols {
+		if symbols[i].Kind == "field_definition" {
+			symbols[i].ContainerPath = fieldContainerPath(symbols[i])
+		}
+	}
+	// Closures are added once container paths are set, so they do not
+	// become the containers of the named symbols declared inside them.
+	if closures := p.extractClosures(root, src, symbols); len(closures) > 0 {
+		symbols = append(symbols, closures...)
+		sortSymbols(symbols)
+	}
+	return symbols
+}
+
+func sortSymbols(symbols []model.Symbol) {
 	sort.Slice(symbols, func(i, j int) bool {
 		if symbols[i].StartLine == symbols[j].StartLine {
 			if symbols[i].EndLine == symbols[j].EndLine {
@@ -436,22 +468,62 @@ func (p *Parser) extractSymbols(src []byte, root *gotreesitter.Node, tags []gotr
 		}
 		return symbols[i].StartLine < symbols[j].StartLine
 	})
-	return symbols
 }
 
-func (p *Parser) extractReferences(tags []gotreesitter.Tag) []model.Reference {
-	if len(tags) == 0 {
+// fieldContainerPath trims a field's container path back to its owning type,
+// so a Python member first assigned in __init__ belongs to the class rather
+// than the method.
+func fieldContainerPath(field model.Symbol) string {
+	path := field.ContainerPath
+	if field.Receiver == "" || path == field.Receiver || strings.HasSuffix(path, "."+field.Receiver) {
+		return path
+	}
+	if i := strings.LastIndex(path, "."+field.Receiver+"."); i >= 0 {
+		return path[:i+len(field.Receiver)+1]
+	}
+	if strings.HasPrefix(path, field.Receiver+".") {
+		return field.Receiver
+	}
+	return path
+}
+
+func (p *Parser) extractReferences(src []byte, root *gotreesitter.Node, tags []gotreesitter.Tag) []model.Reference {
+	members := p.extractMemberReferences(root, src)
+	if reactLanguages[p.entry.Name] {
+		// Rendering a component is calling it.
+		members = append(members, p.extractJSXReferences(root, src)...)
+	}
+	if p.entry.Name == "python" {
+		members = append(members, p.extractPythonDynamicReferences(root, src)...)
+	}
+	if len(tags) == 0 && len(members) == 0 {
 		return nil
 	}
 
-	references := make([]model.Reference, 0, len(tags))
-	seen := map[string]struct{}{}
+	// A definition's name is not a reference to it, though a query matching
+	// calls generically captures it as one where declarations parse as
+	// calls, as Elixir's "def name(...)" does.
+	defined := map[uint32]bool{}
+	for _, tag := range tags {
+		if strings.HasPrefix(tag.Kind, "definition.") {
+			defined[tag.NameRange.StartByte] = true
+		}
+	}
+
+	candidates := make([]model.Reference, 0, len(tags)+len(members))
 	for _, tag := range tags {
-		reference, ok := referenceFromTag(tag)
-		if !ok {
+		if defined[tag.NameRange.StartByte] {
 			continue
 		}
+		if reference, ok := referenceFromTag(src, tag); ok {
+			candidates = append(candidates, reference)
+		}
+	}
+	candidates = append(candidates, members...)
 
+	references := make([]model.Reference, 0, len(candidates))
+	seen := map[string]struct{}{}
+	for _, reference := range candidates {
 		key := reference.Kind + "|" + reference.Name + "|" + strconv.Itoa(reference.StartLine) + "|" + strconv.Itoa(reference.StartColumn)
 		if _, exists := seen[key]; exists {
 			continue
@@ -493,19 +565,61 @@ func symbolFromTag(src []byte, root *gotreesitter.Node, lang *gotreesitter.Langu
 	}
 
 	signature := summarizeSignature(rawRangeText(src, tag.Range))
+	if language == "go" && (kind == "function_definition" || kind == "method_definition") && !declaresGoFunc(signature, name) {
+		// The inferred Go tags query also captures a bare result type, as in
+		// "func Make() Server"; only the declared name is a symbol.
+		return model.Symbol{}, false
+	}
 	receiver := inferReceiver(language, kind, signature, root, lang, src, tag.Range)
 
-	return model.Symbol{
+	symbol := model.Symbol{
 		Kind:      kind,
 		Name:      name,
 		Signature: signature,
 		Receiver:  receiver,
 		StartLine: start,
 		EndLine:   end,
-	}, true
+	}
+	setSymbolRange(&symbol, tag.Range)
+	applyModifiers(&symbol, language, src, tag.Range.StartByte)
+	applyAttributes(&symbol, language, src, tag.Range.StartByte)
+	return symbol, true
+}
+
+// setSymbolRange records the columns and byte offsets of r on symbol.
+func setSymbolRange(symbol *model.Symbol, r gotreesitter.Range) {
+	symbol.StartColumn = int(r.StartPoint.Column) + 1
+	symbol.EndColumn = int(r.EndPoint.Column) + 1
+	if symbol.EndLine == symbol.StartLine && symbol.EndColumn < symbol.StartColumn {
+		symbol.EndColumn = symbol.StartColumn
+	}
+	symbol.StartByte = int(r.StartByte)
+	symbol.EndByte = int(r.EndByte)
 }
 
-func referenceFromTag(tag gotreesitter.Tag) (model.Reference, bool) {
+// declaresGoFunc reports whether name is followed by its parameter or type
+// parameter list somewhere in a Go function signature.
+func declaresGoFunc(signature, name string) bool {
+	for offset := 0; ; {
+		i := strings.Index(signature[offset:], name)
+		if i < 0 {
+			return signature == ""
+		}
+		start := offset + i
+		end := start + len(name)
+		boundary := start == 0 || !isIdentifierByte(signature[start-1])
+		if boundary && end < len(signature) && (signature[end] == '(' || signature[end] == '[') {
+			return true
+		}
+		offset = end
+	}
+}
+
+func isIdentifierByte(b byte) bool {
+	return b == '_' || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
+
+func referenceFromTag(src []byte, tag gotreesitter.Tag) (model.Reference, bool) {
 	if !strings.HasPrefix(tag.Kind, "reference.") {
 		return model.Reference{}, false
 	}
@@ -533,9 +647,38 @@ func referenceFromTag(tag gotreesitter.Tag) (model.Reference, bool) {
 		EndLine:     endLine,
 		StartColumn: startCol,
 		EndColumn:   endCol,
+		Qualifier:   referenceQualifier(src, tag),
 	}, true
 }
 
+// referenceQualifier returns the simple expression a referenced name is
+// selected from, or "" when the tag is not a member access or the receiver
+// is a call, index, or other complex expression.
+func referenceQualifier(src []byte, tag gotreesitter.Tag) string {
+	if tag.NameRange.StartByte <= tag.Range.StartByte {
+		return ""
+	}
+	text := strings.TrimSpace(rawRangeText(src, gotreesitter.Range{
+		StartByte: tag.Range.StartByte,
+		EndByte:   tag.NameRange.StartByte,
+	}))
+	trimmed := false
+	for _, separator := range []string{"?.", "->", "::", "."} {
+		if strings.HasSuffix(text, separator) {
+			text = strings.TrimSpace(strings.TrimSuffix(text, separator))
+			trimmed = true
+			break
+		}
+	}
+	if !trimmed || text == "" || len(text) > 128 {
+		return ""
+	}
+	if strings.ContainsAny(strings.ReplaceAll(text, "->", "."), "()[]{}<>\"'`,;+*/=!&|^%~ \t\r\n") {
+		return ""
+	}
+	return text
+}
+
 func mapTagKind(tagKind string) (string, bool) {
 	if !strings.HasPrefix(tagKind, "definition.") {
 		return "", false
@@ -597,8 +740,12 @@ func summarizeSignature(text string) string {
 		return ""
 	}
 
-	if idx := strings.Index(trimmed, "\n"); idx >= 0 {
+	if idx := signatureEnd(trimmed); idx >= 0 {
+		joined := strings.Contains(trimmed[:idx], "\n")
 		trimmed = strings.TrimSpace(trimmed[:idx])
+		if joined {
+			trimmed = tightenBrackets(trimmed)
+		}
 	}
 	if idx := strings.Index(trimmed, "{"); idx > 0 {
 		trimmed = strings.TrimSpace(trimmed[:idx])
@@ -607,6 +754,44 @@ func summarizeSignature(text string) string {
 	return strings.Join(strings.Fields(trimmed), " ")
 }
 
+// maxSignatureBytes bounds how far a signature is followed across lines.
+const maxSignatureBytes = 1024
+
+// signatureEnd returns the offset of the first line break outside
+// parentheses and brackets, so a type-parameter or parameter list split
+// across lines, as in "func Map[\n\tT any,\n](xs []T)", stays whole. It
+// returns -1 when text is a single line.
+func signatureEnd(text string) int {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '{':
+			if depth <= 0 && i > 0 {
+				return i
+			}
+		case '\n':
+			if depth <= 0 || i >= maxSignatureBytes {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// tightenBrackets removes the whitespace and trailing commas that a list
+// split across lines leaves inside its brackets.
+func tightenBrackets(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	for _, pair := range [][2]string{{"( ", "("}, {"[ ", "["}, {", )", ")"}, {", ]", "]"}, {" )", ")"}, {" ]", "]"}} {
+		text = strings.ReplaceAll(text, pair[0], pair[1])
+	}
+	return text
+}
+
 func inferGoReceiver(signature string) string {
 	const prefix = "func ("
 	if !strings.HasPrefix(signature, prefix) {
@@ -632,6 +817,19 @@ func inferReceiver(language, kind, signature string, root *gotreesitter.Node, la
 		return findEnclosingContainerName(root, lang, src, rng, map[string]bool{"class_declaration": true, "class": true})
 	case "java", "c_sharp":
 		return findEnclosingContainerName(root, lang, src, rng, map[string]bool{"class_declaration": true})
+	case "swift":
+		return findEnclosingContainerName(root, lang, src, rng, map[string]bool{"class_declaration": true, "protocol_declaration": true})
+	case "scala":
+		return findEnclosingContainerName(root, lang, src, rng, map[string]bool{"class_definition": true, "object_definition": true, "trait_definition": true})
+	case "zig":
+		// A Zig type is a container literal bound by a const declaration.
+		declNode := findEnclosingContainerNode(root, lang, rng, map[string]bool{"VarDecl": true})
+		if declNode == nil || declNode.NamedChildCount() == 0 || declNode.NamedChild(0).Type(lang) != "IDENTIFIER" {
+			return ""
+		}
+		return strings.TrimSpace(declNode.NamedChild(0).Text(src))
+	case "elixir":
+		return findEnclosingElixirModule(root, lang, src, rng)
 	case "rust":
 		implNode := findEnclosingContainerNode(root, lang, rng, map[string]bool{"impl_item": true})
 		if implNode == nil {
@@ -701,6 +899,33 @@ func firstIdentifierText(node *gotreesitter.Node, lang *gotreesitter.Language, s
 	return ""
 }
 
+// findEnclosingElixirModule returns the name of the innermost defmodule
+// containing rng. Elixir declarations parse as calls, so the module is the
+// call whose target is the defmodule identifier.
+func findEnclosingElixirModule(root *gotreesitter.Node, lang *gotreesitter.Language, src []byte, rng gotreesitter.Range) string {
+	if root == nil || lang == nil {
+		return ""
+	}
+	name := ""
+	var bestSpan uint32
+	gotreesitter.Walk(root, func(node *gotreesitter.Node, depth int) gotreesitter.WalkAction {
+		if node == nil || node.Type(lang) != "call" || !nodeContainsRange(node, rng) || node.NamedChildCount() < 2 {
+			return gotreesitter.WalkContinue
+		}
+		target, args := node.NamedChild(0), node.NamedChild(1)
+		if target.Type(lang) != "identifier" || target.Text(src) != "defmodule" || args.NamedChildCount() == 0 {
+			return gotreesitter.WalkContinue
+		}
+		span := node.EndByte() - node.StartByte()
+		if name == "" || span < bestSpan {
+			name = strings.TrimSpace(args.NamedChild(0).Text(src))
+			bestSpan = span
+		}
+		return gotreesitter.WalkContinue
+	})
+	return name
+}
+
 func extractRustImplReceiver(text string) string {
 	text = strings.TrimSpace(text)
 	if !strings.HasPrefix(text, "impl") {
@@ -839,6 +1064,32 @@ func extractRubyRequireImport(raw string) string {
 	return quoted[0]
 }
 
+// extractElixirImport returns an alias, import, require, or use directive
+// as written, or "" for any other call.
+func extractElixirImport(raw string) string {
+	fields := strings.Fields(raw)
+	if len(fields) < 2 {
+		return ""
+	}
+	switch fields[0] {
+	case "alias", "import", "require", "use":
+		return raw
+	}
+	return ""
+}
+
+// extractZigImport returns the path of an @import("...") builtin call.
+func extractZigImport(raw string) string {
+	if !strings.HasPrefix(raw, "@import(") {
+		return ""
+	}
+	quoted := extractQuotedStrings(raw)
+	if len(quoted) == 0 {
+		return ""
+	}
+	return quoted[0]
+}
+
 func singleEdit(oldSrc, newSrc []byte) (gotreesitter.InputEdit, bool) {
 	if bytes.Equal(oldSrc, newSrc) {
 		return gotreesitter.InputEdit{}, false
@@ -96,6 +96,8 @@ func TestService() error {
 }
 
 func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func NewService() Service { return Service{} }
 `
 
 	summary, err := parser.Parse("main.go", []byte(source))
@@ -119,6 +121,9 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
 	if !hasSymbol(summary, "function_definition", "TestService") {
 		t.Fatal("expected function_definition TestService")
 	}
+	if !hasSymbol(summary, "function_definition", "NewService") || hasSymbol(summary, "function_definition", "Service") {
+		t.Fatal("expected function_definition NewService and none named after its result type")
+	}
 	method := findSymbol(summary, "method_definition", "ServeHTTP")
 	if method == nil {
 		t.Fatal("expected method_definition ServeHTTP")
@@ -200,6 +205,163 @@ class Worker:
 	}
 }
 
+func TestParseReferenceQualifiers(t *testing.T) {
+	parser, err := NewParser(findEntryByExtension(t, ".go"))
+	if err != nil {
+		t.Fatalf("NewParser returned error: %v", err)
+	}
+
+	const source = `package main
+
+import "os"
+
+func (s *Server) Close() {
+	s.db.Close()
+	helper()
+	os.Exit(1)
+	open().Close()
+}
+`
+	summary, err := parser.Parse("main.go", []byte(source))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	got := map[string][]string{}
+	for _, reference := range summary.References {
+		got[reference.Name] = append(got[reference.Name], reference.Qualifier)
+	}
+	if !reflect.DeepEqual(got["Close"], []string{"s.db", ""}) {
+		t.Fatalf("unexpected Close qualifiers %q", got["Close"])
+	}
+	if !reflect.DeepEqual(got["Exit"], []string{"os"}) || !reflect.DeepEqual(got["helper"], []string{""}) {
+		t.Fatalf("unexpected qualifiers %v", got)
+	}
+}
+
+func TestParseFieldsAndMemberAccess(t *testing.T) {
+	cases := []struct {
+		ext, file, source string
+		fields            []string // container.name
+		accesses          []string // name:access
+	}{
+		{".go", "main.go", "package main\n\ntype Server struct {\n\tName string\n\taddr, port string\n\tcount int\n}\n\nfunc (s *Server) Run() string {\n\ts.count++\n\ts.addr = s.Name\n\ts.db.Close()\n\treturn s.port\n}\n",
+			[]string{"Server.Name", "Server.addr", "Server.port", "Server.count"},
+			[]string{"count:write", "addr:write", "Name:read", "db:read", "port:read"}},
+		{".py", "main.py", "class Foo:\n    limit = 3\n\n    def __init__(self):\n        self.a = 1\n        self.a = 2\n\n    def get(self):\n        self.b += 1\n        return self.a\n",
+			[]string{"Foo.limit", "Foo.a"},
+			[]string{"a:write", "a:write", "b:write", "a:read"}},
+		{".js", "main.js", "class Q {\n  count = 0;\n  #secret;\n  inc() { this.count += 1; this.run(); return this.#secret; }\n}\n",
+			[]string{"Q.count", "Q.#secret"},
+			[]string{"count:write", "#secret:read"}},
+		{".rs", "lib.rs", "struct P {\n    x: i32,\n    pub y: i32,\n}\n\nimpl P {\n    fn get(&mut self) -> i32 {\n        self.x = 2;\n        self.y\n    }\n}\n",
+			[]string{"P.x", "P.y"},
+			[]string{"x:write", "y:read"}},
+	}
+
+	for _, tc := range cases {
+		parser, err := NewParser(findEntryByExtension(t, tc.ext))
+		if err != nil {
+			t.Fatalf("NewParser(%s) returned error: %v", tc.ext, err)
+		}
+		summary, err := parser.Parse(tc.file, []byte(tc.source))
+		if err != nil {
+			t.Fatalf("Parse(%s) returned error: %v", tc.file, err)
+		}
+		var fields, accesses []string
+		for _, symbol := range summary.Symbols {
+			if symbol.Kind == "field_definition" {
+				fields = append(fields, symbol.ContainerPath+"."+symbol.Name)
+			}
+		}
+		for _, reference := range summary.References {
+			if reference.Kind == "reference.field" {
+				accesses = append(accesses, reference.Name+":"+reference.Access)
+			}
+		}
+		if !reflect.DeepEqual(fields, tc.fields) {
+			t.Errorf("%s: fields = %q, want %q", tc.file, fields, tc.fields)
+		}
+		if !reflect.DeepEqual(accesses, tc.accesses) {
+			t.Errorf("%s: accesses = %q, want %q", tc.file, accesses, tc.accesses)
+		}
+	}
+}
+
+func TestParseSymbolModifiers(t *testing.T) {
+	cases := []struct {
+		ext, file, source string
+		kind, name        string
+		want              model.Symbol
+	}{
+		{".go", "main.go", "package main\n\nfunc Public() {}\n\nfunc private() {}\n", "function_definition", "private", model.Symbol{Visibility: "private"}},
+		{".go", "main.go", "package main\n\nfunc Public() {}\n", "function_definition", "Public", model.Symbol{Visibility: "public", Exported: true}},
+		{".py", "main.py", "class W:\n    @staticmethod\n    def make():\n        pass\n\n    async def _load(self):\n        pass\n", "", "make", model.Symbol{Visibility: "public", Exported: true, Static: true}},
+		{".py", "main.py", "class W:\n    async def _load(self):\n        pass\n", "", "_load", model.Symbol{Visibility: "private", Async: true}},
+		{".ts", "main.ts", "export async function load() {}\nfunction helper() {}\n", "function_definition", "load", model.Symbol{Visibility: "public", Exported: true, Async: true}},
+		{".ts", "main.ts", "export async function load() {}\nfunction helper() {}\n", "function_definition", "helper", model.Symbol{Visibility: "private"}},
+		{".java", "Main.java", "public abstract class Main {\n  public static void run() {}\n  void pkg() {}\n}\n", "", "run", model.Symbol{Visibility: "public", Exported: true, Static: true}},
+		{".java", "Main.java", "public abstract class Main {\n  void pkg() {}\n}\n", "", "Main", model.Symbol{Visibility: "public", Exported: true, Abstract: true}},
+		{".rs", "lib.rs", "pub fn api() {}\npub(crate) fn shared() {}\nfn local() {}\n", "function_definition", "shared", model.Symbol{Visibility: "internal"}},
+		{".rs", "lib.rs", "pub async fn api() {}\n", "function_definition", "api", model.Symbol{Visibility: "public", Exported: true, Async: true}},
+	}
+
+	for _, tc := range cases {
+		parser, err := NewParser(findEntryByExtension(t, tc.ext))
+		if err != nil {
+			t.Fatalf("NewParser(%s) returned error: %v", tc.ext, err)
+		}
+		summary, err := parser.Parse(tc.file, []byte(tc.source))
+		if err != nil {
+			t.Fatalf("Parse(%s) returned error: %v", tc.file, err)
+		}
+		var got *model.Symbol
+		for i := range summary.Symbols {
+			if summary.Symbols[i].Name == tc.name && (tc.kind == "" || summary.Symbols[i].Kind == tc.kind) {
+				got = &summary.Symbols[i]
+				break
+			}
+		}
+		if got == nil {
+			t.Fatalf("%s: expected symbol %s in %+v", tc.file, tc.name, summary.Symbols)
+		}
+		if got.Visibility != tc.want.Visibility || got.Exported != tc.want.Exported || got.Static != tc.want.Static || got.Async != tc.want.Async || got.Abstract != tc.want.Abstract {
+			t.Errorf("%s %s: got visibility=%q exported=%v static=%v async=%v abstract=%v, want %+v",
+				tc.file, tc.name, got.Visibility, got.Exported, got.Static, got.Async, got.Abstract, tc.want)
+		}
+	}
+}
+
+func TestParseSymbolColumnsOnSharedLine(t *testing.T) {
+	parser, err := NewParser(findEntryByExtension(t, ".js"))
+	if err != nil {
+		t.Fatalf("NewParser returned error: %v", err)
+	}
+	const source = "function a(){return 1}function bb(){return 2}\n"
+	summary, err := parser.Parse("min.js", []byte(source))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	for _, name := range []string{"a", "bb"} {
+		symbol := findSymbol(summary, "function_definition", name)
+		if symbol == nil {
+			t.Fatalf("expected function_definition %s in %+v", name, summary.Symbols)
+		}
+		start := strings.Index(source, "function "+name)
+		end := start + strings.Index(source[start:], "}") + 1
+		if symbol.StartByte != start || symbol.EndByte != end {
+			t.Errorf("%s: bytes = [%d,%d), want [%d,%d)", name, symbol.StartByte, symbol.EndByte, start, end)
+		}
+		if symbol.StartLine != 1 || symbol.StartColumn != start+1 || symbol.EndColumn != end+1 {
+			t.Errorf("%s: line %d columns [%d,%d), want line 1 columns [%d,%d)", name, symbol.StartLine, symbol.StartColumn, symbol.EndColumn, start+1, end+1)
+		}
+		if got := source[symbol.StartByte:symbol.EndByte]; !strings.HasPrefix(got, "function "+name) {
+			t.Errorf("%s: byte range covers %q", name, got)
+		}
+	}
+}
+
 func TestParseJavaScriptAndTypeScriptImports(t *testing.T) {
 	jsEntry := findEntryByExtension(t, ".js")
 	tsEntry := findEntryByExtension(t, ".ts")
@@ -882,7 +1044,7 @@ func findEntryByExtension(tb testing.TB, extension string) grammars.LangEntry {
 	for _, entry := range grammars.AllLanguages() {
 		for _, ext := range entry.Extensions {
 			if ext == extension {
-				entry.TagsQuery = grammars.ResolveTagsQuery(entry)
+				entry.TagsQuery = ResolveTagsQuery(entry)
 				if strings.TrimSpace(entry.TagsQuery) == "" {
 					entry.TagsQuery = testFallbackTagsQueries[entry.Name]
 				}
@@ -928,3 +1090,401 @@ func hasImport(summary model.FileSummary, importPath string) bool {
 	}
 	return false
 }
+
+func TestParseClosures(t *testing.T) {
+	parser, err := NewParser(findEntryByExtension(t, ".go"))
+	if err != nil {
+		t.Fatalf("NewParser returned error: %v", err)
+	}
+	const goSource = `package demo
+
+var global = func() {}
+
+func Serve() {
+	go func() {
+		defer func() { recover() }()
+	}()
+	handler := func() {}
+	handler()
+}
+`
+	summary, err := parser.Parse("main.go", []byte(goSource))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	var names []string
+	for _, symbol := range summary.Symbols {
+		if symbol.Kind == "closure_definition" {
+			names = append(names, symbol.Name)
+		}
+	}
+	want := []string{"Serve.func1", "Serve.func1.func1", "Serve.func2"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("go closures = %v, want %v", names, want)
+	}
+	inner := findSymbol(summary, "closure_definition", "Serve.func1.func1")
+	if inner.StartLine != 7 || inner.StartColumn == 0 {
+		t.Fatalf("unexpected closure range %+v", inner)
+	}
+
+	parser, err = NewParser(findEntryByExtension(t, ".js"))
+	if err != nil {
+		t.Fatalf("NewParser returned error: %v", err)
+	}
+	const jsSource = `const f = () => {}
+
+function run(items) {
+  items.forEach((item) => f(item))
+}
+`
+	summary, err = parser.Parse("main.js", []byte(jsSource))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	names = names[:0]
+	for _, symbol := range summary.Symbols {
+		if symbol.Kind == "closure_definition" {
+			names = append(names, symbol.Name)
+		}
+	}
+	if !reflect.DeepEqual(names, []string{"run.func1"}) {
+		t.Fatalf("js closures = %v, want [run.func1]", names)
+	}
+}
+
+func TestParseReactComponentsAndHooks(t *testing.T) {
+	parser, err := NewParser(findEntryByExtension(t, ".tsx"))
+	if err != nil {
+		t.Fatalf("NewParser returned error: %v", err)
+	}
+	const source = `export function App() {
+  const [n] = useCounter(0);
+  return <Layout><UI.Button onClick={() => n} /></Layout>;
+}
+
+export const Layout = ({ children }) => <main>{children}</main>;
+const Card = memo(function Card() { return <section />; });
+
+export function useCounter(start: number) {
+  return [start];
+}
+
+class Legacy extends React.Component {
+  render() { return <div />; }
+}
+
+function helper() { return 1; }
+`
+	summary, err := parser.Parse("app.tsx", []byte(source))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	for _, name := range []string{"App", "Layout", "Card", "Legacy"} {
+		if !hasSymbol(summary, "component_definition", name) {
+			t.Errorf("expected component %s, got %+v", name, summary.Symbols)
+		}
+	}
+	if !hasSymbol(summary, "hook_definition", "useCounter") {
+		t.Errorf("expected hook useCounter, got %+v", summary.Symbols)
+	}
+	if !hasSymbol(summary, "function_definition", "helper") {
+		t.Errorf("expected helper to stay a function, got %+v", summary.Symbols)
+	}
+	if !hasSymbol(summary, "closure_definition", "App.func1") {
+		t.Errorf("expected the onClick handler as a closure of App, got %+v", summary.Symbols)
+	}
+	if layout := findSymbol(summary, "component_definition", "Layout"); layout != nil && layout.Visibility != "public" {
+		t.Errorf("expected exported Layout to be public, got %q", layout.Visibility)
+	}
+
+	var rendered []string
+	for _, reference := range summary.References {
+		if reference.Kind == "reference.call" && reference.StartLine == 3 {
+			rendered = append(rendered, reference.Qualifier+"."+reference.Name)
+		}
+	}
+	if !reflect.DeepEqual(rendered, []string{".Layout", "UI.Button"}) {
+		t.Fatalf("JSX call references on line 3 = %v, want [.Layout UI.Button]", rendered)
+	}
+}
+func TestParsePythonDynamicReferences(t *testing.T) {
+	parser, err := NewParser(findEntryByExtension(t, ".py"))
+	if err != nil {
+		t.Fatalf("NewParser returned error: %v", err)
+	}
+	const source = `__all__ = ["public_helper", "Widget"]
+
+def dispatch(obj, kind):
+    getattr(obj, "render")()
+    return getattr(obj, "handle_" + kind)()
+
+urlpatterns = [path("about/", views.about, name="about"), path("", view=index)]
+`
+	summary, err := parser.Parse("views.py", []byte(source))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	var got []string
+	for _, reference := range summary.References {
+		if reference.Kind == "reference.dynamic" {
+			got = append(got, reference.Qualifier+":"+reference.Name)
+		}
+	}
+	want := []string{
+		"__all__:public_helper", "__all__:Widget",
+		"getattr:render", "getattr:handle_*",
+		"path:about", "path:index",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("dynamic references = %v, want %v", got, want)
+	}
+}
+
+func TestParseAttributes(t *testing.T) {
+	cases := []struct {
+		ext    string
+		source string
+		kind   string
+		name   string
+		want   []string
+	}{
+		{".py", "@app.route('/users', methods=['GET'])\n@login_required\ndef users():\n    pass\n", "function_definition", "users", []string{"@app.route('/users', methods=['GET'])", "@login_required"}},
+		{".ts", "class Users {\n  @Get(':id')\n  find() {}\n}\n", "method_definition", "find", []string{"@Get(':id')"}},
+		{".java", "class A {\n  @Override\n  @GetMapping(\"/x\") public void run() {}\n}\n", "method_definition", "run", []string{"@Override", `@GetMapping("/x")`}},
+		{".cs", "class A {\n  [HttpGet(\"x\")]\n  [Authorize]\n  public void Run() {}\n}\n", "method_definition", "Run", []string{`[HttpGet("x")]`, "[Authorize]"}},
+		{".rs", "#[cfg(test)]\n#[test]\nfn t() {}\n", "function_definition", "t", []string{"#[cfg(test)]", "#[test]"}},
+		{".go", "//go:build linux\n\npackage demo\n\ntype User struct {\n\tName string `json:\"name,omitempty\" db:\"name\"`\n}\n", "field_definition", "Name", []string{`json:"name,omitempty"`, `db:"name"`}},
+	}
+	for _, tc := range cases {
+		parser, err := NewParser(findEntryByExtension(t, tc.ext))
+		if err != nil {
+			t.Fatalf("NewParser(%s) returned error: %v", tc.ext, err)
+		}
+		summary, err := parser.Parse("main"+tc.ext, []byte(tc.source))
+		if err != nil {
+			t.Fatalf("Parse(%s) returned error: %v", tc.ext, err)
+		}
+		symbol := findSymbol(summary, tc.kind, tc.name)
+		if symbol == nil {
+			t.Fatalf("%s: expected %s %s", tc.ext, tc.kind, tc.name)
+		}
+		if !reflect.DeepEqual(symbol.Attributes, tc.want) {
+			t.Fatalf("%s: attributes = %q, want %q", tc.ext, symbol.Attributes, tc.want)
+		}
+		if tc.ext == ".go" && !reflect.DeepEqual(summary.Attributes, []string{"//go:build linux"}) {
+			t.Fatalf("go file attributes = %q", summary.Attributes)
+		}
+	}
+}
+
+func TestParseGoGenericSignatures(t *testing.T) {
+	parser, err := NewParser(findEntryByExtension(t, ".go"))
+	if err != nil {
+		t.Fatalf("NewParser returned error: %v", err)
+	}
+	const source = `package demo
+
+func Reduce[
+	T any,
+	A any,
+](xs []T, init A, f func(A, T) A) A {
+	return Reduce[T, A](xs[1:], f(init, xs[0]), f)
+}
+
+type Cache[
+	K comparable,
+	V any,
+] struct{}
+
+func (c *Cache[K, V]) Get(k K) (V, bool) { var v V; return v, false }
+`
+	summary, err := parser.Parse("generic.go", []byte(source))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	want := map[string]string{
+		"Reduce": "func Reduce[T any, A any](xs []T, init A, f func(A, T) A) A",
+		"Cache":  "type Cache[K comparable, V any] struct",
+		"Get":    "func (c *Cache[K, V]) Get(k K) (V, bool)",
+	}
+	for _, symbol := range summary.Symbols {
+		if signature, ok := want[symbol.Name]; ok && symbol.Signature != signature {
+			t.Fatalf("%s signature = %q, want %q", symbol.Name, symbol.Signature, signature)
+		}
+	}
+	if !hasReference(summary, "reference.call", "Reduce") {
+		t.Fatal("expected the instantiated call to reference Reduce")
+	}
+}
+
+func TestParseSwiftScalaElixirAndZig(t *testing.T) {
+	type symbolWant struct {
+		kind, name, receiver string
+	}
+	tests := []struct {
+		extension  string
+		source     string
+		imports    []string
+		symbols    []symbolWant
+		references []string
+	}{
+		{
+			extension: ".swift",
+			source: `import Foundation
+
+protocol Repo {
+    func find(id: Int) -> User?
+}
+
+class UserService {
+    init() {}
+    func fetch(id: Int) -> User {
+        return loadUser(id)
+    }
+}
+
+struct User {
+    var name: String
+}
+
+func loadUser(_ id: Int) -> User {
+    return User(name: "x")
+}
+
+extension UserService {
+    func refresh() { cache.clear() }
+}
+`,
+			imports: []string{"import Foundation"},
+			symbols: []symbolWant{
+				{"interface_definition", "Repo", "Repo"},
+				{"method_definition", "find", "Repo"},
+				{"class_definition", "UserService", "UserService"},
+				{"constructor_definition", "init", "UserService"},
+				{"method_definition", "fetch", "UserService"},
+				{"struct_definition", "User", "User"},
+				{"function_definition", "loadUser", ""},
+				{"method_definition", "refresh", "UserService"},
+			},
+			references: []string{"loadUser", "User", "clear"},
+		},
+		{
+			extension: ".scala",
+			source: `package com.example
+
+import scala.collection.mutable
+
+class UserService {
+  def fetch(id: Int): User = loadUser(id)
+}
+
+object UserService {
+  def apply(): UserService = new UserService()
+}
+
+trait Repo {
+  def find(id: Int): Option[User]
+}
+
+def loadUser(id: Int): User = repo.get(id)
+`,
+			imports: []string{"import scala.collection.mutable"},
+			symbols: []symbolWant{
+				{"class_definition", "UserService", "UserService"},
+				{"method_definition", "fetch", "UserService"},
+				{"method_definition", "apply", "UserService"},
+				{"interface_definition", "Repo", "Repo"},
+				{"method_definition", "find", "Repo"},
+				{"function_definition", "loadUser", ""},
+			},
+			references: []string{"loadUser", "get"},
+		},
+		{
+			extension: ".ex",
+			source: `defmodule MyApp.UserService do
+  alias MyApp.Repo
+  import Ecto.Query
+
+  def fetch(id) when id > 0 do
+    load_user(id)
+    Repo.get(User, id)
+  end
+
+  defp load_user(id), do: id
+end
+`,
+			imports: []string{"alias MyApp.Repo", "import Ecto.Query"},
+			symbols: []symbolWant{
+				{"module_definition", "MyApp.UserService", "MyApp.UserService"},
+				{"function_definition", "fetch", "MyApp.UserService"},
+				{"function_definition", "load_user", "MyApp.UserService"},
+			},
+			references: []string{"load_user", "get"},
+		},
+		{
+			extension: ".zig",
+			source: `const std = @import("std");
+
+const Point = struct {
+    x: i32,
+    pub fn norm(self: Point) i32 {
+        return abs(self.x);
+    }
+};
+
+fn abs(v: i32) i32 {
+    return if (v < 0) -v else v;
+}
+
+pub fn main() void {
+    const p = Point{ .x = 1 };
+    std.debug.print("{}", .{p.norm()});
+}
+`,
+			imports: []string{"std"},
+			symbols: []symbolWant{
+				{"struct_definition", "Point", "Point"},
+				{"method_definition", "norm", "Point"},
+				{"function_definition", "abs", ""},
+				{"function_definition", "main", ""},
+			},
+			references: []string{"abs", "norm", "print"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.extension, func(t *testing.T) {
+			parser, err := NewParser(findEntryByExtension(t, tt.extension))
+			if err != nil {
+				t.Fatalf("NewParser returned error: %v", err)
+			}
+			summary, err := parser.Parse("main"+tt.extension, []byte(tt.source))
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+			if !reflect.DeepEqual(summary.Imports, tt.imports) {
+				t.Fatalf("imports = %v, want %v", summary.Imports, tt.imports)
+			}
+			for _, want := range tt.symbols {
+				symbol := findSymbol(summary, want.kind, want.name)
+				if symbol == nil {
+					t.Fatalf("expected %s %s, got %+v", want.kind, want.name, summary.Symbols)
+				}
+				if symbol.Receiver != want.receiver {
+					t.Fatalf("%s receiver = %q, want %q", want.name, symbol.Receiver, want.receiver)
+				}
+			}
+			for _, name := range tt.references {
+				if !hasReference(summary, "reference.call", name) {
+					t.Fatalf("expected call reference %s, got %+v", name, summary.References)
+				}
+			}
+			for _, reference := range summary.References {
+				switch reference.Name {
+				case "def", "defp", "defmodule", "fetch":
+					t.Fatalf("declaration captured as a reference: %+v", reference)
+				}
+			}
+		})
+	}
+}
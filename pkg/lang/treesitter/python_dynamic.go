@@ -0,0 +1,156 @@
+package treesitter
+
+import (
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// pythonRegistrationCalls are the calls that hand a function or class to a
+// framework, which calls it later: Django's path(), re_path(), and url()
+// and admin.site.register(), Flask's add_url_rule(), FastAPI's
+// add_api_route(), Celery's register_task(), and signal connect().
+var pythonRegistrationCalls = map[string]bool{
+	"path":          true,
+	"re_path":       true,
+	"url":           true,
+	"register":      true,
+	"add_url_rule":  true,
+	"add_api_route": true,
+	"register_task": true,
+	"connect":       true,
+}
+
+// extractPythonDynamicReferences returns a reference.dynamic reference for
+// each name Python code reaches without calling it by name, qualified by
+// how it is reached:
+//
+//   - "getattr": the attribute name of getattr(obj, "name"). A name built as
+//     "prefix" + x or f"prefix{x}" is the prefix followed by "*".
+//   - "__all__": each name a module's __all__ exports.
+//   - a registration call such as "path": each function or class passed to
+//     one of pythonRegistrationCalls.
+//
+// Dead-code analysis treats the definitions these name as used.
+func (p *Parser) extractPythonDynamicReferences(root *gotreesitter.Node, src []byte) []model.Reference {
+	if root == nil {
+		return nil
+	}
+	var references []model.Reference
+	add := func(node *gotreesitter.Node, name, qualifier string) {
+		if name == "" || name == "*" {
+			return
+		}
+		r := node.Range()
+		references = append(references, model.Reference{
+			Kind:        "reference.dynamic",
+			Name:        name,
+			StartLine:   int(r.StartPoint.Row) + 1,
+			EndLine:     int(r.EndPoint.Row) + 1,
+			StartColumn: int(r.StartPoint.Column) + 1,
+			EndColumn:   int(r.EndPoint.Column) + 1,
+			Qualifier:   qualifier,
+		})
+	}
+
+	gotreesitter.Walk(root, func(node *gotreesitter.Node, depth int) gotreesitter.WalkAction {
+		if node == nil {
+			return gotreesitter.WalkContinue
+		}
+		switch node.Type(p.lang) {
+		case "assignment", "augmented_assignment":
+			left := node.ChildByFieldName("left", p.lang)
+			right := node.ChildByFieldName("right", p.lang)
+			if left == nil || right == nil || left.Text(src) != "__all__" {
+				break
+			}
+			for i := 0; i < right.NamedChildCount(); i++ {
+				if item := right.NamedChild(i); item.Type(p.lang) == "string" {
+					add(item, p.stringLiteralValue(item, src), "__all__")
+				}
+			}
+		case "call":
+			function := node.ChildByFieldName("function", p.lang)
+			arguments := node.ChildByFieldName("arguments", p.lang)
+			if function == nil || arguments == nil {
+				break
+			}
+			callee := function
+			if function.Type(p.lang) == "attribute" {
+				callee = function.ChildByFieldName("attribute", p.lang)
+			}
+			switch name := callee.Text(src); {
+			case function.Type(p.lang) == "identifier" && name == "getattr":
+				if arguments.NamedChildCount() >= 2 {
+					attr := arguments.NamedChild(1)
+					add(attr, p.attributeNamePattern(attr, src), "getattr")
+				}
+			case pythonRegistrationCalls[name]:
+				for i := 0; i < arguments.NamedChildCount(); i++ {
+					arg := arguments.NamedChild(i)
+					if arg.Type(p.lang) == "keyword_argument" {
+						arg = arg.ChildByFieldName("value", p.lang)
+					}
+					if target := p.referencedName(arg); target != nil {
+						add(target, target.Text(src), name)
+					}
+				}
+			}
+		}
+		return gotreesitter.WalkContinue
+	})
+	return references
+}
+
+// referencedName returns the name node of an argument naming a function or
+// class, as in handler or views.index, or nil.
+func (p *Parser) referencedName(arg *gotreesitter.Node) *gotreesitter.Node {
+	if arg == nil {
+		return nil
+	}
+	switch arg.Type(p.lang) {
+	case "identifier":
+		return arg
+	case "attribute":
+		return arg.ChildByFieldName("attribute", p.lang)
+	}
+	return nil
+}
+
+// attributeNamePattern returns the attribute name a getattr argument
+// evaluates to: a string literal's value, or the literal prefix of a
+// concatenation or f-string followed by "*". It is "" for any other
+// expression.
+func (p *Parser) attributeNamePattern(arg *gotreesitter.Node, src []byte) string {
+	switch arg.Type(p.lang) {
+	case "string":
+		value := ""
+		for i := 0; i < arg.NamedChildCount(); i++ {
+			switch part := arg.NamedChild(i); part.Type(p.lang) {
+			case "string_content":
+				value += part.Text(src)
+			case "interpolation":
+				return value + "*"
+			}
+		}
+		return value
+	case "binary_operator":
+		left := arg.ChildByFieldName("left", p.lang)
+		if left != nil && left.Type(p.lang) == "string" {
+			if prefix := p.stringLiteralValue(left, src); prefix != "" {
+				return prefix + "*"
+			}
+		}
+	}
+	return ""
+}
+
+// stringLiteralValue returns the contents of a Python string literal
+// without interpolations, or "" for an f-string that has some.
+func (p *Parser) stringLiteralValue(node *gotreesitter.Node, src []byte) string {
+	value := p.attributeNamePattern(node, src)
+	if len(value) > 0 && value[len(value)-1] == '*' {
+		return ""
+	}
+	return value
+}
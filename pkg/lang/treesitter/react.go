@@ -0,0 +1,212 @@
+package treesitter
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/odvcencio/gotreesitter"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// reactLanguages are the languages whose functions can be React components
+// and hooks.
+var reactLanguages = map[string]bool{"javascript": true, "typescript": true, "tsx": true}
+
+// jsxElementTypes are the node types that open a JSX element and name the
+// component or tag it renders.
+var jsxElementTypes = map[string]bool{"jsx_opening_element": true, "jsx_self_closing_element": true}
+
+// reactWrappers are the functions whose result is a component when called
+// with one, as in "const Card = memo(function Card() {...})".
+var reactWrappers = map[string]bool{
+	"memo": true, "forwardRef": true, "React.memo": true, "React.forwardRef": true,
+}
+
+var classComponentPattern = regexp.MustCompile(`\bextends\s+(?:React\.)?(?:Pure)?Component\b`)
+
+// classifyReact marks the React components and hooks among symbols: a
+// function with a PascalCase name whose body renders JSX, or a class
+// extending Component or PureComponent, becomes a component_definition, and
+// a function named like useState becomes a hook_definition. Components and
+// hooks declared as "const Name = () => ..." or through memo or forwardRef,
+// which the tags query does not capture, are added.
+func (p *Parser) classifyReact(root *gotreesitter.Node, src []byte, symbols []model.Symbol) []model.Symbol {
+	if root == nil {
+		return symbols
+	}
+	var jsx []int
+	var declarators []*gotreesitter.Node
+	gotreesitter.Walk(root, func(node *gotreesitter.Node, depth int) gotreesitter.WalkAction {
+		if node == nil {
+			return gotreesitter.WalkContinue
+		}
+		switch nodeType := node.Type(p.lang); {
+		case jsxElementTypes[nodeType]:
+			jsx = append(jsx, int(node.StartByte()))
+		case nodeType == "variable_declarator":
+			declarators = append(declarators, node)
+		}
+		return gotreesitter.WalkContinue
+	})
+	sort.Ints(jsx)
+	rendersJSX := func(start, end int) bool {
+		i := sort.SearchInts(jsx, start)
+		return i < len(jsx) && jsx[i] < end
+	}
+
+	declared := map[string]bool{}
+	for i := range symbols {
+		symbol := &symbols[i]
+		switch symbol.Kind {
+		case "function_definition":
+			symbol.Kind = reactKind(symbol.Name, rendersJSX(symbol.StartByte, symbol.EndByte), symbol.Kind)
+		case "class_definition":
+			if isPascalCase(symbol.Name) && classComponentPattern.MatchString(symbol.Signature) {
+				symbol.Kind = "component_definition"
+			}
+		}
+		declared[symbol.Name] = true
+	}
+
+	for _, declarator := range declarators {
+		name := declarator.ChildByFieldName("name", p.lang)
+		value := declarator.ChildByFieldName("value", p.lang)
+		if name == nil || value == nil || name.Type(p.lang) != "identifier" || !p.isTopLevelDeclarator(declarator) {
+			continue
+		}
+		symbolName := name.Text(src)
+		if declared[symbolName] || !p.isFunctionValue(value, src) {
+			continue
+		}
+		kind := reactKind(symbolName, rendersJSX(int(value.StartByte()), int(value.EndByte())), "")
+		if kind == "" {
+			continue
+		}
+		declaration := declarator.Parent()
+		symbol := model.Symbol{
+			Kind:      kind,
+			Name:      symbolName,
+			Signature: summarizeSignature(declaration.Text(src)),
+			StartLine: int(declarator.StartPoint().Row) + 1,
+			EndLine:   int(declarator.EndPoint().Row) + 1,
+		}
+		// The declarator, not its declaration, so the function it holds is
+		// the symbol's body rather than a closure inside it.
+		setSymbolRange(&symbol, declarator.Range())
+		applyModifiers(&symbol, p.entry.Name, src, declarator.StartByte())
+		symbols = append(symbols, symbol)
+		declared[symbolName] = true
+	}
+	return symbols
+}
+
+// reactKind returns the kind of a function named name: component_definition
+// for a PascalCase name when it renders JSX, hook_definition for a name such
+// as useState, and otherwise fallback.
+func reactKind(name string, rendersJSX bool, fallback string) string {
+	switch {
+	case isPascalCase(name) && rendersJSX:
+		return "component_definition"
+	case isHookName(name):
+		return "hook_definition"
+	}
+	return fallback
+}
+
+// isFunctionValue reports whether value, a declarator's initializer, is a
+// function or a call of memo or forwardRef.
+func (p *Parser) isFunctionValue(value *gotreesitter.Node, src []byte) bool {
+	switch value.Type(p.lang) {
+	case "arrow_function", "function_expression", "function":
+		return true
+	case "call_expression":
+		callee := value.ChildByFieldName("function", p.lang)
+		return callee != nil && reactWrappers[callee.Text(src)]
+	}
+	return false
+}
+
+// isTopLevelDeclarator reports whether declarator is part of a declaration
+// at the top of the module, possibly exported.
+func (p *Parser) isTopLevelDeclarator(declarator *gotreesitter.Node) bool {
+	declaration := declarator.Parent()
+	if declaration == nil {
+		return false
+	}
+	switch declaration.Type(p.lang) {
+	case "lexical_declaration", "variable_declaration":
+	default:
+		return false
+	}
+	parent := declaration.Parent()
+	if parent != nil && parent.Type(p.lang) == "export_statement" {
+		parent = parent.Parent()
+	}
+	return parent != nil && parent.Type(p.lang) == "program"
+}
+
+// extractJSXReferences returns a reference.call reference for each JSX
+// element rendering a component, such as <Button /> or <UI.Button>, so the
+// call graph sees where components are used. Lowercase elements are HTML
+// tags and are left out.
+func (p *Parser) extractJSXReferences(root *gotreesitter.Node, src []byte) []model.Reference {
+	if root == nil {
+		return nil
+	}
+	var references []model.Reference
+	gotreesitter.Walk(root, func(node *gotreesitter.Node, depth int) gotreesitter.WalkAction {
+		if node == nil || !jsxElementTypes[node.Type(p.lang)] {
+			return gotreesitter.WalkContinue
+		}
+		element := node.ChildByFieldName("name", p.lang)
+		if element == nil {
+			return gotreesitter.WalkContinue
+		}
+		name, qualifier := element, ""
+		if property := element.ChildByFieldName("property", p.lang); property != nil {
+			name = property
+			if object := element.ChildByFieldName("object", p.lang); object != nil {
+				qualifier = object.Text(src)
+			}
+		}
+		text := name.Text(src)
+		if !isPascalCase(text) {
+			return gotreesitter.WalkContinue
+		}
+		nameRange := name.Range()
+		references = append(references, model.Reference{
+			Kind:        "reference.call",
+			Name:        text,
+			StartLine:   int(nameRange.StartPoint.Row) + 1,
+			EndLine:     int(nameRange.EndPoint.Row) + 1,
+			StartColumn: int(nameRange.StartPoint.Column) + 1,
+			EndColumn:   int(nameRange.EndPoint.Column) + 1,
+			Qualifier:   qualifier,
+		})
+		return gotreesitter.WalkContinue
+	})
+	return references
+}
+
+// isPascalCase reports whether name starts with an uppercase letter, as
+// component names must for JSX to tell them from HTML tags.
+func isPascalCase(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+// isHookName reports whether name follows the React hook convention of
+// "use" followed by an uppercase letter or digit, as in useState.
+func isHookName(name string) bool {
+	rest, ok := strings.CutPrefix(name, "use")
+	if !ok || rest == "" {
+		return false
+	}
+	r := rune(rest[0])
+	return unicode.IsUpper(r) || unicode.IsDigit(r)
+}
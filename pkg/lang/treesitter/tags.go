@@ -0,0 +1,58 @@
+package treesitter
+
+import (
+	"strings"
+
+	"github.com/odvcencio/gotreesitter/grammars"
+)
+
+// builtinTagsQueries replaces the inferred tags query of languages whose
+// inference misses their declarations: Swift methods and top-level
+// functions, Scala objects and traits, Elixir's def and defmodule macros,
+// which parse as plain calls, and Zig, for which nothing is inferred.
+var builtinTagsQueries = map[string]string{
+	"swift": `(class_declaration "struct" (type_identifier) @name) @definition.struct
+(class_declaration "enum" (type_identifier) @name) @definition.enum
+(class_declaration ["class" "actor"] (type_identifier) @name) @definition.class
+(protocol_declaration (type_identifier) @name) @definition.interface
+(protocol_function_declaration (simple_identifier) @name) @definition.method
+(function_declaration (simple_identifier) @name (#has-ancestor? @name class_body enum_class_body)) @definition.method
+(init_declaration "init" @name) @definition.constructor
+(function_declaration (simple_identifier) @name (#not-has-ancestor? @name class_body enum_class_body)) @definition.function
+(call_expression . (simple_identifier) @name) @reference.call
+(call_expression (navigation_expression (navigation_suffix (simple_identifier) @name))) @reference.call`,
+
+	"scala": `(class_definition (identifier) @name) @definition.class
+(object_definition (identifier) @name) @definition.class
+(trait_definition (identifier) @name) @definition.interface
+(enum_definition (identifier) @name) @definition.enum
+(type_definition (type_identifier) @name) @definition.type
+(function_definition (identifier) @name (#has-ancestor? @name template_body)) @definition.method
+(function_declaration (identifier) @name (#has-ancestor? @name template_body)) @definition.method
+(function_definition (identifier) @name (#not-has-ancestor? @name template_body)) @definition.function
+(call_expression function: (identifier) @name) @reference.call
+(call_expression function: (field_expression field: (identifier) @name)) @reference.call`,
+
+	"elixir": `(call . (identifier) @_keyword . (arguments . (alias) @name) (#any-of? @_keyword "defmodule" "defprotocol" "defimpl")) @definition.module
+(call . (identifier) @_keyword . (arguments . [(identifier) @name (call . (identifier) @name) (binary_operator . (call . (identifier) @name))]) (#any-of? @_keyword "def" "defp" "defmacro" "defmacrop" "defguard" "defdelegate")) @definition.function
+(call . (identifier) @name (#not-any-of? @name "defmodule" "defprotocol" "defimpl" "def" "defp" "defmacro" "defmacrop" "defguard" "defdelegate" "defstruct" "alias" "import" "require" "use")) @reference.call
+(call . (dot . (_) . (identifier) @name)) @reference.call`,
+
+	"zig": `(Decl (FnProto . (IDENTIFIER) @name) (#not-has-ancestor? @name ContainerDecl)) @definition.function
+(Decl (FnProto . (IDENTIFIER) @name) (#has-ancestor? @name ContainerDecl)) @definition.method
+(VarDecl (IDENTIFIER) @name (ErrorUnionExpr (SuffixExpr (ContainerDecl (ContainerDeclType) @_container))) (#match? @_container "^((extern|packed) )?struct")) @definition.struct
+(VarDecl (IDENTIFIER) @name (ErrorUnionExpr (SuffixExpr (ContainerDecl (ContainerDeclType) @_container))) (#match? @_container "^(extern )?enum")) @definition.enum
+(VarDecl (IDENTIFIER) @name (ErrorUnionExpr (SuffixExpr (ContainerDecl (ContainerDeclType) @_container))) (#match? @_container "^((extern|packed) )?union")) @definition.union
+(SuffixExpr . (IDENTIFIER) @name . (FnCallArguments)) @reference.call
+(SuffixExpr (FieldOrFnCall . (IDENTIFIER) @name . (FnCallArguments))) @reference.call`,
+}
+
+// ResolveTagsQuery returns the tags query to parse entry's language with:
+// the built-in query where gts has one, otherwise the query gotreesitter
+// ships or infers. It returns "" when neither exists.
+func ResolveTagsQuery(entry grammars.LangEntry) string {
+	if query, ok := builtinTagsQueries[entry.Name]; ok {
+		return query
+	}
+	return strings.TrimSpace(grammars.ResolveTagsQuery(entry))
+}
@@ -0,0 +1,188 @@
+// Package vue indexes Vue single-file components (.vue). The <script> blocks
+// of a component are indexed as JavaScript, or TypeScript for lang="ts",
+// with the rest of the file blanked so lines and columns stay those of the
+// .vue file. The component itself is a component_definition symbol, and the
+// components its <template> renders are call references.
+package vue
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/odvcencio/gts-suite/pkg/lang"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Extension is the file extension of Vue single-file components.
+const Extension = ".vue"
+
+var (
+	scriptPattern   = regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script\s*>`)
+	langPattern     = regexp.MustCompile(`(?i)\blang\s*=\s*["']?(\w+)`)
+	templatePattern = regexp.MustCompile(`(?is)<template\b[^>]*>(.*)</template\s*>`)
+	tagPattern      = regexp.MustCompile(`<([A-Za-z][\w-]*(?:\.[A-Za-z][\w-]*)*)`)
+	namePattern     = regexp.MustCompile(`\bname\s*:\s*['"]([^'"]+)['"]`)
+	optionsPattern  = regexp.MustCompile(`export\s+default\s+(?:defineComponent\s*\(\s*)?\{`)
+)
+
+// Parser parses the scripts of Vue components with the JavaScript or
+// TypeScript parser.
+type Parser struct {
+	javascript lang.Parser
+	typescript lang.Parser
+}
+
+// NewParser returns a Parser that parses component scripts with javascript,
+// or typescript for scripts declaring lang="ts" or lang="tsx".
+func NewParser(javascript, typescript lang.Parser) *Parser {
+	return &Parser{javascript: javascript, typescript: typescript}
+}
+
+// Language returns the language Vue components are indexed as.
+func (p *Parser) Language() string {
+	return "vue"
+}
+
+// Parse summarizes the Vue component src: the symbols and references of its
+// scripts, a component_definition named by its name option or, failing
+// that, its file name in PascalCase, and a reference.call for each
+// component its template renders.
+func (p *Parser) Parse(path string, src []byte) (model.FileSummary, error) {
+	script, language := Extract(src)
+	parser := p.javascript
+	if language == "typescript" {
+		parser = p.typescript
+	}
+	summary := model.FileSummary{Path: path, Language: p.Language()}
+	if strings.TrimSpace(string(script)) != "" {
+		parsed, err := parser.Parse(path, script)
+		if err != nil {
+			return model.FileSummary{}, err
+		}
+		summary.Imports = parsed.Imports
+		summary.Symbols = parsed.Symbols
+		summary.References = parsed.References
+	}
+
+	// The component spans the file.
+	component := model.Symbol{
+		Kind:        "component_definition",
+		Name:        componentName(path, script),
+		Signature:   "<template>",
+		Visibility:  "public",
+		StartLine:   1,
+		EndLine:     strings.Count(string(src), "\n") + 1,
+		StartColumn: 1,
+		EndColumn:   len(src) - strings.LastIndexByte(string(src), '\n'),
+		EndByte:     len(src),
+	}
+	summary.Symbols = append([]model.Symbol{component}, summary.Symbols...)
+	summary.References = append(summary.References, templateReferences(src)...)
+	sort.SliceStable(summary.References, func(i, j int) bool {
+		a, b := summary.References[i], summary.References[j]
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		return a.StartColumn < b.StartColumn
+	})
+	return summary, nil
+}
+
+// Extract returns the <script> blocks of the Vue component src with every
+// other byte but line breaks replaced by a space, so offsets into it are
+// offsets into src, and the language they are written in: "typescript" or
+// "javascript".
+func Extract(src []byte) ([]byte, string) {
+	out := make([]byte, len(src))
+	for i, b := range src {
+		if b == '\n' || b == '\r' {
+			out[i] = b
+		} else {
+			out[i] = ' '
+		}
+	}
+	language := "javascript"
+	for _, match := range scriptPattern.FindAllSubmatchIndex(src, -1) {
+		copy(out[match[4]:match[5]], src[match[4]:match[5]])
+		if attr := langPattern.FindSubmatch(src[match[2]:match[3]]); attr != nil {
+			switch strings.ToLower(string(attr[1])) {
+			case "ts", "tsx", "typescript":
+				language = "typescript"
+			}
+		}
+	}
+	return out, language
+}
+
+// componentName returns the name option of the component's options object,
+// or the file name of path in PascalCase.
+func componentName(path string, script []byte) string {
+	if loc := optionsPattern.FindIndex(script); loc != nil {
+		if name := namePattern.FindSubmatch(script[loc[1]:]); name != nil {
+			return string(name[1])
+		}
+	}
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return pascalCase(base)
+}
+
+// templateReferences returns a reference.call for each component element in
+// the <template> of src: a PascalCase tag such as <TodoItem>, or a
+// kebab-case one such as <todo-item>, which Vue resolves to TodoItem.
+func templateReferences(src []byte) []model.Reference {
+	template := templatePattern.FindSubmatchIndex(src)
+	if template == nil {
+		return nil
+	}
+	start, end := template[2], template[3]
+	var references []model.Reference
+	for _, match := range tagPattern.FindAllSubmatchIndex(src[start:end], -1) {
+		tagStart, tagEnd := start+match[2], start+match[3]
+		tag := string(src[tagStart:tagEnd])
+		qualifier, name := "", tag
+		if dot := strings.LastIndexByte(tag, '.'); dot >= 0 {
+			qualifier, name = tag[:dot], tag[dot+1:]
+			tagStart += dot + 1
+		}
+		if strings.Contains(name, "-") {
+			name = pascalCase(name)
+		} else if !unicode.IsUpper(rune(name[0])) {
+			// An HTML element, or a component Vue cannot resolve by name.
+			continue
+		}
+		line := strings.Count(string(src[:tagStart]), "\n") + 1
+		column := tagStart - strings.LastIndexByte(string(src[:tagStart]), '\n')
+		references = append(references, model.Reference{
+			Kind:        "reference.call",
+			Name:        name,
+			StartLine:   line,
+			EndLine:     line,
+			StartColumn: column,
+			EndColumn:   column + tagEnd - tagStart,
+			Qualifier:   qualifier,
+		})
+	}
+	return references
+}
+
+// pascalCase converts a kebab-case or snake_case name such as todo-item to
+// TodoItem.
+func pascalCase(name string) string {
+	var out strings.Builder
+	upper := true
+	for _, r := range name {
+		switch {
+		case r == '-' || r == '_' || r == '.' || r == ' ':
+			upper = true
+		case upper:
+			out.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
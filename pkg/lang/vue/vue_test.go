@@ -0,0 +1,111 @@
+package vue
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/odvcencio/gotreesitter/grammars"
+
+	"github.com/odvcencio/gts-suite/pkg/lang/treesitter"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+const sample = `<template>
+  <ul>
+    <todo-item v-for="t in items" :key="t" />
+    <Icons.Check />
+  </ul>
+</template>
+
+<script lang="ts">
+export default defineComponent({
+  name: "TodoList",
+  methods: {
+    clear(): void { reset(); },
+  },
+});
+</script>
+`
+
+func newTestParser(t *testing.T) *Parser {
+	t.Helper()
+	parserFor := func(path string) *treesitter.Parser {
+		entry := grammars.DetectLanguage(path)
+		if entry == nil {
+			t.Fatalf("no grammar for %s", path)
+		}
+		entry.TagsQuery = treesitter.ResolveTagsQuery(*entry)
+		parser, err := treesitter.NewParser(*entry)
+		if err != nil {
+			t.Fatalf("NewParser(%s): %v", path, err)
+		}
+		return parser
+	}
+	return NewParser(parserFor("a.js"), parserFor("a.ts"))
+}
+
+func TestExtractKeepsScriptOffsets(t *testing.T) {
+	script, language := Extract([]byte(sample))
+	if language != "typescript" {
+		t.Fatalf("language = %q, want typescript", language)
+	}
+	if len(script) != len(sample) {
+		t.Fatalf("script is %d bytes, want %d", len(script), len(sample))
+	}
+	const decl = "export default defineComponent"
+	for i := 0; i+len(decl) <= len(sample); i++ {
+		if sample[i:i+len(decl)] == decl {
+			if got := string(script[i : i+len(decl)]); got != decl {
+				t.Fatalf("script at %d = %q, want %q", i, got, decl)
+			}
+			return
+		}
+	}
+	t.Fatal("declaration not found in sample")
+}
+
+func TestParse(t *testing.T) {
+	summary, err := newTestParser(t).Parse("src/todo-list.vue", []byte(sample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if summary.Language != "vue" {
+		t.Fatalf("Language = %q, want vue", summary.Language)
+	}
+	component := summary.Symbols[0]
+	if component.Kind != "component_definition" || component.Name != "TodoList" || component.StartLine != 1 || component.EndLine != 16 {
+		t.Fatalf("unexpected component symbol %+v", component)
+	}
+	var method *model.Symbol
+	for i, symbol := range summary.Symbols {
+		if symbol.Name == "clear" {
+			method = &summary.Symbols[i]
+		}
+	}
+	if method == nil || method.StartLine != 12 {
+		t.Fatalf("expected method clear on line 12, got %+v", summary.Symbols)
+	}
+
+	var calls []string
+	for _, reference := range summary.References {
+		if reference.Kind == "reference.call" {
+			calls = append(calls, reference.Name)
+		}
+	}
+	if want := []string{"TodoItem", "Check", "defineComponent", "reset"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("call references = %v, want %v", calls, want)
+	}
+	if first := summary.References[0]; first.StartLine != 3 || first.StartColumn != 6 {
+		t.Fatalf("todo-item reference at %d:%d, want 3:6", first.StartLine, first.StartColumn)
+	}
+}
+
+func TestComponentNameFallsBackToFileName(t *testing.T) {
+	summary, err := newTestParser(t).Parse("todo-item.vue", []byte("<template><li /></template>\n<script setup>\nconst done = false\n</script>\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if name := summary.Symbols[0].Name; name != "TodoItem" {
+		t.Fatalf("component name = %q, want TodoItem", name)
+	}
+}
@@ -0,0 +1,91 @@
+package lsp
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter/grammars"
+
+	"github.com/odvcencio/gts-suite/pkg/codeaction"
+	"github.com/odvcencio/gts-suite/pkg/textpos"
+)
+
+// codeActionKinds are the kinds of code action gtsls offers.
+var codeActionKinds = []string{
+	codeaction.KindOrganizeImports,
+	codeaction.KindRemoveUnusedImports,
+	codeaction.KindSortImports,
+	codeaction.KindExtractConstant,
+}
+
+// handleCodeAction offers the tree-sitter code actions for the requested
+// range, computed on the open document's text when the client has sent it.
+func (s *Service) handleCodeAction(params json.RawMessage) (any, error) {
+	var p CodeActionParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	if result, ok := s.proxyRequest("textDocument/codeAction", params, p.TextDocument.URI); ok {
+		return result, nil
+	}
+
+	path := uriToPath(p.TextDocument.URI)
+	entry := grammars.DetectLanguage(path)
+	if entry == nil || !codeaction.Supported(entry.Name) {
+		return []CodeAction{}, nil
+	}
+	source, err := s.documentSource(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := s.encoding
+	if enc == "" {
+		enc = textpos.UTF16
+	}
+	m := textpos.New(source)
+	start := m.Offset(p.Range.Start.Line, p.Range.Start.Character, enc)
+	end := m.Offset(p.Range.End.Line, p.Range.End.Character, enc)
+	actions, err := codeaction.Actions(relativeTo(path, s.rootPath), entry.Name, source, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []CodeAction{}
+	for _, action := range actions {
+		if !codeActionKindRequested(action.Kind, p.Context.Only) {
+			continue
+		}
+		startLine, startCol := m.Position(action.Edit.Offset, enc)
+		endLine, endCol := m.Position(action.Edit.Offset+len(action.Edit.OldName), enc)
+		result = append(result, CodeAction{
+			Title: action.Title,
+			Kind:  action.Kind,
+			Edit: &WorkspaceEdit{Changes: map[string][]TextEdit{
+				p.TextDocument.URI: {{
+					Range: Range{
+						Start: Position{Line: startLine, Character: startCol},
+						End:   Position{Line: endLine, Character: endCol},
+					},
+					NewText: action.Edit.NewName,
+				}},
+			}},
+		})
+	}
+	return result, nil
+}
+
+// codeActionKindRequested reports whether kind is among only, where a kind
+// also matches the kinds nested under it, such as "source" matching
+// "source.organizeImports". An empty only matches every kind.
+func codeActionKindRequested(kind string, only []string) bool {
+	if len(only) == 0 {
+		return true
+	}
+	for _, prefix := range only {
+		if kind == prefix || strings.HasPrefix(kind, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
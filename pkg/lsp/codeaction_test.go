@@ -0,0 +1,56 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServiceCodeAction(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644)
+	uri := "file://" + dir + "/main.go"
+	// The open document differs from the file on disk.
+	text := "package main\n\nimport (\n\t\"os\"\n\t\"fmt\"\n)\n\nfunc main() { fmt.Println(\"héllo\") }\n"
+
+	input := lspRequest(1, "initialize", map[string]any{"rootUri": "file://" + dir})
+	input += lspNotify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "go", "version": 1, "text": text},
+	})
+	input += lspRequest(2, "textDocument/codeAction", map[string]any{
+		"textDocument": map[string]string{"uri": uri},
+		"range":        map[string]any{"start": map[string]int{"line": 7, "character": 27}, "end": map[string]int{"line": 7, "character": 27}},
+		"context":      map[string]any{"only": []string{"source.organizeImports", "refactor.extract"}},
+	})
+	input += lspRequest(3, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	var actions []CodeAction
+	for _, msg := range splitLSPMessages(t, out.Bytes()) {
+		if string(msg.ID) == "2" {
+			if err := json.Unmarshal(msg.Result, &actions); err != nil {
+				t.Fatalf("decode code actions: %v", err)
+			}
+		}
+	}
+	if len(actions) != 2 || actions[0].Kind != "source.organizeImports" || actions[1].Kind != "refactor.extract.constant" {
+		t.Fatalf("unexpected code actions %+v", actions)
+	}
+
+	organize := actions[0].Edit.Changes[uri]
+	if len(organize) != 1 || organize[0].NewText != "" || organize[0].Range.Start != (Position{Line: 3}) || organize[0].Range.End != (Position{Line: 4}) {
+		t.Fatalf("unexpected organize imports edit %+v", organize)
+	}
+	extract := actions[1].Edit.Changes[uri]
+	if len(extract) != 1 || !strings.Contains(extract[0].NewText, "const héllo = \"héllo\"") || !strings.Contains(extract[0].NewText, "fmt.Println(héllo)") {
+		t.Fatalf("unexpected extract constant edit %+v", extract)
+	}
+}
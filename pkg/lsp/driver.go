@@ -3,11 +3,8 @@ package lsp
 import (
 	"encoding/json"
 	"os"
-	"path/filepath"
 	"runtime"
 	"strings"
-
-	"github.com/odvcencio/gts-suite/pkg/index"
 )
 
 // gopackagesdriver LoadMode flags
@@ -45,6 +42,15 @@ type DriverPackage struct {
 	CompiledGoFiles []string          `json:"CompiledGoFiles,omitempty"`
 	Imports         map[string]string `json:"Imports,omitempty"`
 	Errors          []DriverError     `json:"Errors,omitempty"`
+	Module          *DriverModule     `json:"Module,omitempty"`
+}
+
+type DriverModule struct {
+	Path    string `json:"Path"`
+	Version string `json:"Version,omitempty"`
+	Dir     string `json:"Dir,omitempty"`
+	GoMod   string `json:"GoMod,omitempty"`
+	Main    bool   `json:"Main,omitempty"`
 }
 
 type DriverError struct {
@@ -52,68 +58,47 @@ type DriverError struct {
 	Msg string `json:"Msg"`
 }
 
-// HandleDriverRequest processes a gopackagesdriver request.
+// HandleDriverRequest processes a gopackagesdriver request for the packages
+// patterns match. Files are listed from an index kept incrementally in
+// .gts/driver-index.json, and their package clauses, imports, and build
+// constraints are read as the go command reads them, with the request's
+// overlay in place of the files on disk. With NeedDeps, the dependencies
+// are loaded from the module, GOROOT, the vendor directory, or the module
+// cache.
+//
+// Responses to requests without an overlay are cached in .gts/driver, keyed
+// by go.mod and the request, and reused while the index digest is
+// unchanged. Requests gtsls cannot answer, such as ones whose dependencies
+// it cannot find, report NotHandled so go/packages falls back to go list.
 func HandleDriverRequest(rootDir string, req DriverRequest, patterns []string) (*DriverResponse, error) {
-	builder := index.NewBuilder()
-	idx, err := builder.BuildPath(rootDir)
+	idx, err := driverIndex(rootDir)
 	if err != nil {
 		return &DriverResponse{NotHandled: true}, nil
 	}
 
-	// Group Go files by directory (package)
-	pkgMap := make(map[string]*DriverPackage)
-	for _, f := range idx.Files {
-		if f.Language != "go" {
-			continue
-		}
-		dir := filepath.Dir(f.Path)
-		pkg, ok := pkgMap[dir]
-		if !ok {
-			pkg = &DriverPackage{
-				ID:      dir,
-				Imports: make(map[string]string),
-			}
-			pkgMap[dir] = pkg
-		}
-
-		absPath := filepath.Join(rootDir, f.Path)
-		pkg.GoFiles = append(pkg.GoFiles, absPath)
-		pkg.CompiledGoFiles = append(pkg.CompiledGoFiles, absPath)
-
-		// Extract package name from first symbol (package clause is in imports)
-		if pkg.Name == "" {
-			pkg.Name = packageNameFromFile(absPath)
-		}
-
-		for _, imp := range f.Imports {
-			imp = strings.Trim(imp, "\"")
-			pkg.Imports[imp] = imp
+	loader := newDriverLoader(rootDir, idx, req)
+	key := loader.cacheKey(patterns)
+	cacheable := len(req.Overlay) == 0
+	if cacheable {
+		if resp, ok := loadDriverResponse(rootDir, key, idx.Digest); ok {
+			return resp, nil
 		}
 	}
 
-	// Build module path from go.mod if present
-	modulePath := readModulePath(rootDir)
-
-	var packages []*DriverPackage
-	var roots []string
-	for dir, pkg := range pkgMap {
-		if modulePath != "" && dir == "." {
-			pkg.PkgPath = modulePath
-			pkg.ID = modulePath
-		} else if modulePath != "" {
-			pkg.PkgPath = modulePath + "/" + dir
-			pkg.ID = pkg.PkgPath
-		}
-		packages = append(packages, pkg)
-		roots = append(roots, pkg.ID)
+	roots, packages, err := loader.load(patterns)
+	if err != nil {
+		return &DriverResponse{NotHandled: true}, nil
 	}
-
-	return &DriverResponse{
+	resp := &DriverResponse{
 		Compiler: runtime.Compiler,
-		Arch:     runtime.GOARCH,
+		Arch:     loader.ctxt.GOARCH,
 		Roots:    roots,
 		Packages: packages,
-	}, nil
+	}
+	if cacheable {
+		saveDriverResponse(rootDir, key, idx.Digest, resp)
+	}
+	return resp, nil
 }
 
 // RunDriver reads a DriverRequest from stdin and writes a DriverResponse to stdout.
@@ -130,38 +115,75 @@ func RunDriver(rootDir string, patterns []string) error {
 	return json.NewEncoder(os.Stdout).Encode(resp)
 }
 
-func packageNameFromFile(path string) string {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return ""
-	}
-	// Quick extraction: find "package X" in first 1KB
-	s := string(data)
-	if len(s) > 1024 {
-		s = s[:1024]
-	}
-	for _, line := range strings.Split(s, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "package ") {
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				return parts[1]
-			}
-		}
-	}
-	return ""
+// goModFile is what the driver reads from go.mod.
+type goModFile struct {
+	module   string
+	requires map[string]string // module path -> version
+	replaces map[string]goModReplace
 }
 
-func readModulePath(rootDir string) string {
-	data, err := os.ReadFile(filepath.Join(rootDir, "go.mod"))
-	if err != nil {
-		return ""
-	}
+// goModReplace is the target of a replace directive: a local directory,
+// or a module path and version.
+type goModReplace struct {
+	path    string
+	version string
+}
+
+func (r goModReplace) local() bool {
+	return strings.HasPrefix(r.path, "./") || strings.HasPrefix(r.path, "../") || strings.HasPrefix(r.path, "/")
+}
+
+// parseGoMod reads the module path, requirements, and replacements of a
+// go.mod file, in single-line and block form.
+func parseGoMod(data []byte) goModFile {
+	mod := goModFile{requires: map[string]string{}, replaces: map[string]goModReplace{}}
+	block := ""
 	for _, line := range strings.Split(string(data), "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "module ") {
-			return strings.TrimPrefix(line, "module ")
+		if comment := strings.Index(line, "//"); comment >= 0 {
+			line = line[:comment]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if block != "" {
+			if fields[0] == ")" {
+				block = ""
+				continue
+			}
+			fields = append([]string{block}, fields...)
+		} else if len(fields) == 2 && fields[1] == "(" {
+			block = fields[0]
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.Trim(fields[i], `"`)
+		}
+		switch fields[0] {
+		case "module":
+			if len(fields) >= 2 {
+				mod.module = fields[1]
+			}
+		case "require":
+			if len(fields) >= 3 {
+				mod.requires[fields[1]] = fields[2]
+			}
+		case "replace":
+			arrow := -1
+			for i, field := range fields {
+				if field == "=>" {
+					arrow = i
+				}
+			}
+			if arrow < 2 || arrow+1 >= len(fields) {
+				continue
+			}
+			target := goModReplace{path: fields[arrow+1]}
+			if arrow+2 < len(fields) {
+				target.version = fields[arrow+2]
+			}
+			mod.replaces[fields[1]] = target
 		}
 	}
-	return ""
+	return mod
 }
@@ -0,0 +1,170 @@
+package lsp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/ignore"
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// driverCacheVersion invalidates cached responses when their format or the
+// way they are computed changes.
+const driverCacheVersion = "1"
+
+// maxDriverResponses bounds the responses kept in .gts/driver; the least
+// recently written are removed first.
+const maxDriverResponses = 64
+
+// driverEnv are the environment variables that change a driver response.
+var driverEnv = map[string]bool{
+	"GOOS":        true,
+	"GOARCH":      true,
+	"GOROOT":      true,
+	"GOPATH":      true,
+	"GOMODCACHE":  true,
+	"GOFLAGS":     true,
+	"CGO_ENABLED": true,
+}
+
+// driverIndex returns the index of the Go files of rootDir, built
+// incrementally from .gts/driver-index.json, which is rewritten when the
+// tree has changed.
+func driverIndex(rootDir string) (*model.Index, error) {
+	path := filepath.Join(rootDir, ".gts", "driver-index.json")
+	previous, err := index.Load(path)
+	if err != nil {
+		previous = nil
+	}
+	builder := index.NewBuilder()
+	builder.SetIgnore(ignore.ParsePatterns([]string{"*", "!*.go"}))
+	idx, _, err := builder.BuildPathIncremental(context.Background(), rootDir, previous)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil || previous.Digest != idx.Digest {
+		_ = index.Save(path, idx)
+	}
+	return idx, nil
+}
+
+// driverCacheEntry is a cached response with the digest of the index it was
+// computed from.
+type driverCacheEntry struct {
+	Digest   string          `json:"digest"`
+	Response *DriverResponse `json:"response"`
+}
+
+// cacheKey identifies the response to a request for patterns: it hashes
+// go.mod, the Go release in GOROOT, and every part of the request but the
+// overlay, which bypasses the cache.
+func (l *driverLoader) cacheKey(patterns []string) string {
+	h := sha256.New()
+	write := func(parts ...string) {
+		for _, part := range parts {
+			h.Write([]byte(part))
+			h.Write([]byte{0})
+		}
+	}
+	write("gtsls driver", driverCacheVersion)
+	goMod, _ := os.ReadFile(filepath.Join(l.rootDir, "go.mod"))
+	goModHash := sha256.Sum256(goMod)
+	write(hex.EncodeToString(goModHash[:]))
+	release, _ := os.ReadFile(filepath.Join(l.ctxt.GOROOT, "VERSION"))
+	write(l.ctxt.GOROOT, string(release))
+	write(patterns...)
+	write(strings.Join(l.ctxt.BuildTags, ","), l.ctxt.GOOS, l.ctxt.GOARCH)
+	write(strconv.Itoa(l.mode), strconv.FormatBool(l.tests))
+	var env []string
+	for _, kv := range l.env {
+		if key, _, _ := strings.Cut(kv, "="); driverEnv[key] {
+			env = append(env, kv)
+		}
+	}
+	sort.Strings(env)
+	write(env...)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func driverCacheDir(rootDir string) string {
+	return filepath.Join(rootDir, ".gts", "driver")
+}
+
+// loadDriverResponse returns the response cached under key, provided it was
+// computed from an index with digest.
+func loadDriverResponse(rootDir, key, digest string) (*DriverResponse, bool) {
+	data, err := os.ReadFile(filepath.Join(driverCacheDir(rootDir), key+".json"))
+	if err != nil {
+		return nil, false
+	}
+	var entry driverCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Response == nil || digest == "" || entry.Digest != digest {
+		return nil, false
+	}
+	return entry.Response, true
+}
+
+// saveDriverResponse caches resp under key and drops the oldest responses
+// beyond maxDriverResponses. Failing to write the cache is not an error;
+// the next request recomputes the response.
+func saveDriverResponse(rootDir, key, digest string, resp *DriverResponse) {
+	if digest == "" {
+		return
+	}
+	dir := driverCacheDir(rootDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	data, err := json.Marshal(driverCacheEntry{Digest: digest, Response: resp})
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), filepath.Join(dir, key+".json")) != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	pruneDriverResponses(dir)
+}
+
+func pruneDriverResponses(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	type cached struct {
+		path    string
+		modTime int64
+	}
+	var files []cached
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cached{filepath.Join(dir, entry.Name()), info.ModTime().UnixNano()})
+	}
+	if len(files) <= maxDriverResponses {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime < files[j].modTime })
+	for _, file := range files[:len(files)-maxDriverResponses] {
+		os.Remove(file.path)
+	}
+}
@@ -0,0 +1,622 @@
+package lsp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/build"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// driverLoader answers one driver request. It reads packages with go/build,
+// configured from the request's environment and build flags, and serves the
+// request's overlay in place of the files on disk.
+//
+// Packages are loaded as with CGO_ENABLED=0: go list reports cgo files as
+// the Go files cgo compiles them to, which gtsls cannot produce, and every
+// standard library package has a pure Go fallback.
+type driverLoader struct {
+	rootDir string
+	ctxt    build.Context
+	env     []string
+	mode    int
+	tests   bool
+	overlay map[string][]byte
+	mod     goModFile
+	// localDirs are the slash-separated directories of rootDir holding Go
+	// files of the main module.
+	localDirs map[string]bool
+	packages  map[string]*DriverPackage
+	// std holds the IDs of the loaded standard library packages.
+	std map[string]bool
+}
+
+func newDriverLoader(rootDir string, idx *model.Index, req DriverRequest) *driverLoader {
+	l := &driverLoader{
+		rootDir:   rootDir,
+		env:       req.Env,
+		mode:      req.Mode,
+		tests:     req.Tests,
+		overlay:   map[string][]byte{},
+		localDirs: map[string]bool{},
+		packages:  map[string]*DriverPackage{},
+		std:       map[string]bool{},
+	}
+	data, _ := os.ReadFile(filepath.Join(rootDir, "go.mod"))
+	l.mod = parseGoMod(data)
+
+	ctxt := build.Default
+	ctxt.CgoEnabled = false
+	for _, kv := range req.Env {
+		key, value, _ := strings.Cut(kv, "=")
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "GOOS":
+			ctxt.GOOS = value
+		case "GOARCH":
+			ctxt.GOARCH = value
+		case "GOROOT":
+			ctxt.GOROOT = value
+		case "GOPATH":
+			ctxt.GOPATH = value
+		}
+	}
+	ctxt.BuildTags = buildTags(req.BuildFlags)
+	ctxt.OpenFile = l.openFile
+	ctxt.ReadDir = l.readDir
+	ctxt.IsDir = l.isDir
+	l.ctxt = ctxt
+
+	for name, contents := range req.Overlay {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(rootDir, name)
+		}
+		l.overlay[filepath.Clean(name)] = contents
+	}
+
+	dirs := map[string]bool{}
+	for _, file := range idx.Files {
+		if file.Language == "go" {
+			dirs[path.Dir(filepath.ToSlash(file.Path))] = true
+		}
+	}
+	for name := range l.overlay {
+		if dir, ok := l.relative(filepath.Dir(name)); ok && strings.HasSuffix(name, ".go") {
+			dirs[dir] = true
+		}
+	}
+	// Directories of nested modules belong to those modules.
+	var nested []string
+	for dir := range dirs {
+		if dir == "." {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(rootDir, filepath.FromSlash(dir), "go.mod")); err == nil {
+			nested = append(nested, dir)
+		}
+	}
+	for dir := range dirs {
+		if !slices.ContainsFunc(nested, func(root string) bool { return dir == root || strings.HasPrefix(dir, root+"/") }) {
+			l.localDirs[dir] = true
+		}
+	}
+	return l
+}
+
+// load returns the IDs of the packages patterns match and, sorted by ID,
+// those packages and, with NeedDeps, their dependencies. It fails when a
+// pattern or dependency is beyond what the driver resolves.
+func (l *driverLoader) load(patterns []string) ([]string, []*DriverPackage, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	dirs, imports, err := l.match(patterns)
+	if err != nil {
+		return nil, nil, err
+	}
+	var roots []string
+	addRoot := func(id string) {
+		if !slices.Contains(roots, id) {
+			roots = append(roots, id)
+		}
+	}
+	for _, dir := range dirs {
+		for _, id := range l.loadLocal(dir, l.tests) {
+			addRoot(id)
+		}
+	}
+	for _, importPath := range imports {
+		pkg, err := l.resolve(importPath, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		addRoot(pkg.ID)
+	}
+	if l.mode&NeedDeps != 0 {
+		if err := l.loadDeps(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	packages := make([]*DriverPackage, 0, len(l.packages))
+	for _, pkg := range l.packages {
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].ID < packages[j].ID })
+	return roots, packages, nil
+}
+
+// match splits patterns into the local directories and the import paths
+// they name. Relative, absolute, and main module patterns, with or without
+// a trailing "/...", and file= queries are local; wildcards outside the main
+// module and meta-packages such as "std" are not supported.
+func (l *driverLoader) match(patterns []string) ([]string, []string, error) {
+	var dirs, imports []string
+	add := func(dir string) {
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	local := make([]string, 0, len(l.localDirs))
+	for dir := range l.localDirs {
+		local = append(local, dir)
+	}
+	sort.Strings(local)
+
+	for _, pattern := range patterns {
+		if file, ok := strings.CutPrefix(pattern, "file="); ok {
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(l.rootDir, file)
+			}
+			dir, ok := l.relative(filepath.Dir(file))
+			if !ok {
+				return nil, nil, fmt.Errorf("file %s is outside %s", file, l.rootDir)
+			}
+			add(dir)
+			continue
+		}
+		if module := l.mod.module; module != "" {
+			if pattern == module || pattern == module+"/..." {
+				pattern = "." + strings.TrimPrefix(pattern, module)
+			} else if rest, ok := strings.CutPrefix(pattern, module+"/"); ok {
+				pattern = "./" + rest
+			}
+		}
+		if pattern != "." && !strings.HasPrefix(pattern, "./") && !strings.HasPrefix(pattern, "../") && !filepath.IsAbs(pattern) {
+			if strings.Contains(pattern, "...") || pattern == "std" || pattern == "cmd" || pattern == "all" {
+				return nil, nil, fmt.Errorf("unsupported pattern %q", pattern)
+			}
+			imports = append(imports, pattern)
+			continue
+		}
+
+		base, recursive := strings.CutSuffix(pattern, "/...")
+		if !filepath.IsAbs(base) {
+			base = filepath.Join(l.rootDir, base)
+		}
+		baseDir, ok := l.relative(base)
+		if !ok {
+			return nil, nil, fmt.Errorf("pattern %q is outside %s", pattern, l.rootDir)
+		}
+		if !recursive {
+			add(baseDir)
+			continue
+		}
+		for _, dir := range local {
+			rest := dir
+			if baseDir != "." {
+				var ok bool
+				if rest, ok = strings.CutPrefix(dir, baseDir+"/"); !ok && dir != baseDir {
+					continue
+				}
+			}
+			if dir == baseDir || !wildcardSkips(rest) {
+				add(dir)
+			}
+		}
+	}
+	return dirs, imports, nil
+}
+
+// wildcardSkips reports whether "..." skips the directory at rel, as the go
+// command skips testdata, vendor, and names starting with "." or "_".
+func wildcardSkips(rel string) bool {
+	for _, elem := range strings.Split(rel, "/") {
+		if elem == "testdata" || elem == "vendor" || strings.HasPrefix(elem, ".") || strings.HasPrefix(elem, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+// loadLocal loads the main module package in dir, and its test variants
+// when tests is set, returning their IDs. A directory without Go files for
+// the build configuration yields none.
+func (l *driverLoader) loadLocal(dir string, tests bool) []string {
+	bp, err := l.ctxt.ImportDir(filepath.Join(l.rootDir, filepath.FromSlash(dir)), 0)
+	var noGo *build.NoGoError
+	if errors.As(err, &noGo) || bp == nil {
+		return nil
+	}
+	id := l.localImportPath(dir)
+	pkg := l.newPackage(id, id, bp, bp.GoFiles, bp.Imports, false)
+	if err != nil {
+		pkg.Errors = append(pkg.Errors, DriverError{Msg: err.Error()})
+	}
+	pkg.Module = l.mainModule()
+	l.packages[id] = pkg
+	ids := []string{id}
+	if !tests {
+		return ids
+	}
+
+	// Test variants are named as go list -test names them.
+	testID := ""
+	if len(bp.TestGoFiles) > 0 {
+		testID = id + " [" + id + ".test]"
+		files := append(slices.Clone(bp.GoFiles), bp.TestGoFiles...)
+		test := l.newPackage(testID, id, bp, files, mergeImports(bp.Imports, bp.TestImports), false)
+		test.Module = pkg.Module
+		l.packages[testID] = test
+		ids = append(ids, testID)
+	}
+	if len(bp.XTestGoFiles) > 0 {
+		xtestID := id + "_test [" + id + ".test]"
+		xtest := l.newPackage(xtestID, id+"_test", bp, bp.XTestGoFiles, bp.XTestImports, false)
+		xtest.Name = bp.Name + "_test"
+		if _, ok := xtest.Imports[id]; ok && testID != "" {
+			xtest.Imports[id] = testID
+		}
+		xtest.Module = pkg.Module
+		l.packages[xtestID] = xtest
+		ids = append(ids, xtestID)
+	}
+	return ids
+}
+
+// loadDeps loads every package imported by a loaded package, transitively.
+func (l *driverLoader) loadDeps() error {
+	queue := make([]*DriverPackage, 0, len(l.packages))
+	for _, pkg := range l.packages {
+		queue = append(queue, pkg)
+	}
+	sort.Slice(queue, func(i, j int) bool { return queue[i].ID < queue[j].ID })
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		std := l.std[pkg.ID]
+		for _, importPath := range sortedKeys(pkg.Imports) {
+			if l.packages[pkg.Imports[importPath]] != nil {
+				continue
+			}
+			dep, err := l.resolve(importPath, std)
+			if err != nil {
+				return err
+			}
+			pkg.Imports[importPath] = dep.ID
+			queue = append(queue, dep)
+		}
+	}
+	return nil
+}
+
+// resolve loads the package importPath names when imported from a standard
+// library package, if fromStd, or from the main module.
+func (l *driverLoader) resolve(importPath string, fromStd bool) (*DriverPackage, error) {
+	if dir, ok := l.localDir(importPath); ok {
+		if pkg := l.packages[importPath]; pkg != nil {
+			return pkg, nil
+		}
+		l.loadLocal(dir, false)
+		if pkg := l.packages[importPath]; pkg != nil {
+			return pkg, nil
+		}
+		return nil, fmt.Errorf("cannot find package %q in the main module", importPath)
+	}
+	id, dir, module, ok := l.locate(importPath, fromStd)
+	if !ok {
+		return nil, fmt.Errorf("cannot find package %q", importPath)
+	}
+	if pkg := l.packages[id]; pkg != nil {
+		return pkg, nil
+	}
+	bp, err := l.ctxt.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", importPath, err)
+	}
+	std := bp.Goroot
+	pkg := l.newPackage(id, id, bp, bp.GoFiles, bp.Imports, std)
+	if l.mode&NeedModule != 0 {
+		pkg.Module = module
+	}
+	l.packages[id] = pkg
+	l.std[id] = std
+	return pkg, nil
+}
+
+// locate finds the directory of a package outside the main module: in
+// GOROOT, the vendor directory, or the module cache. Standard library
+// packages vendored in GOROOT get go list's "vendor/" IDs.
+func (l *driverLoader) locate(importPath string, fromStd bool) (string, string, *DriverModule, bool) {
+	src := filepath.Join(l.ctxt.GOROOT, "src")
+	if fromStd {
+		if dir := filepath.Join(src, "vendor", filepath.FromSlash(importPath)); l.isDir(dir) {
+			return "vendor/" + importPath, dir, nil, true
+		}
+	}
+	if first, _, _ := strings.Cut(importPath, "/"); !strings.Contains(first, ".") {
+		if dir := filepath.Join(src, filepath.FromSlash(importPath)); l.isDir(dir) {
+			return importPath, dir, nil, true
+		}
+	}
+	if dir := filepath.Join(l.rootDir, "vendor", filepath.FromSlash(importPath)); l.isDir(dir) {
+		return importPath, dir, nil, true
+	}
+
+	modulePath, version, rest, ok := l.requirement(importPath)
+	if !ok {
+		return "", "", nil, false
+	}
+	module := &DriverModule{Path: modulePath, Version: version}
+	source, sourceVersion := modulePath, version
+	if replacement, ok := l.mod.replaces[modulePath]; ok {
+		if replacement.local() {
+			module.Dir = replacement.path
+			if !filepath.IsAbs(module.Dir) {
+				module.Dir = filepath.Join(l.rootDir, filepath.FromSlash(module.Dir))
+			}
+			module.Version = ""
+			module.GoMod = filepath.Join(module.Dir, "go.mod")
+			dir := filepath.Join(module.Dir, filepath.FromSlash(rest))
+			return importPath, dir, module, l.isDir(dir)
+		}
+		source, sourceVersion = replacement.path, replacement.version
+	}
+	module.Dir = filepath.Join(l.moduleCache(), escapeModulePath(source)+"@"+escapeModulePath(sourceVersion))
+	module.GoMod = filepath.Join(module.Dir, "go.mod")
+	dir := filepath.Join(module.Dir, filepath.FromSlash(rest))
+	return importPath, dir, module, l.isDir(dir)
+}
+
+// requirement returns the module go.mod requires that provides importPath:
+// the one with the longest matching path.
+func (l *driverLoader) requirement(importPath string) (string, string, string, bool) {
+	best := ""
+	for modulePath := range l.mod.requires {
+		if (importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")) && len(modulePath) > len(best) {
+			best = modulePath
+		}
+	}
+	if best == "" {
+		return "", "", "", false
+	}
+	rest := strings.TrimPrefix(strings.TrimPrefix(importPath, best), "/")
+	return best, l.mod.requires[best], rest, true
+}
+
+// moduleCache returns GOMODCACHE as the go command defaults it.
+func (l *driverLoader) moduleCache() string {
+	if cache := l.getenv("GOMODCACHE"); cache != "" {
+		return cache
+	}
+	gopath := filepath.SplitList(l.ctxt.GOPATH)
+	if len(gopath) == 0 {
+		return ""
+	}
+	return filepath.Join(gopath[0], "pkg", "mod")
+}
+
+// getenv returns a variable from the request's environment, else the
+// process's.
+func (l *driverLoader) getenv(key string) string {
+	for i := len(l.env) - 1; i >= 0; i-- {
+		if value, ok := strings.CutPrefix(l.env[i], key+"="); ok {
+			return value
+		}
+	}
+	return os.Getenv(key)
+}
+
+// newPackage returns the package bp describes, listing files and imports.
+// Imports map to the IDs go list gives them, which resolve may change.
+func (l *driverLoader) newPackage(id, pkgPath string, bp *build.Package, files, imports []string, std bool) *DriverPackage {
+	pkg := &DriverPackage{ID: id, Name: bp.Name, PkgPath: pkgPath, Imports: map[string]string{}}
+	for _, name := range files {
+		file := filepath.Join(bp.Dir, name)
+		pkg.GoFiles = append(pkg.GoFiles, file)
+		pkg.CompiledGoFiles = append(pkg.CompiledGoFiles, file)
+	}
+	for _, importPath := range imports {
+		if importPath == "C" {
+			continue
+		}
+		pkg.Imports[importPath] = importPath
+		if std && l.isDir(filepath.Join(l.ctxt.GOROOT, "src", "vendor", filepath.FromSlash(importPath))) {
+			pkg.Imports[importPath] = "vendor/" + importPath
+		}
+	}
+	return pkg
+}
+
+func (l *driverLoader) mainModule() *DriverModule {
+	if l.mode&NeedModule == 0 || l.mod.module == "" {
+		return nil
+	}
+	return &DriverModule{Path: l.mod.module, Dir: l.rootDir, GoMod: filepath.Join(l.rootDir, "go.mod"), Main: true}
+}
+
+// localImportPath returns the import path of the main module package in
+// dir. Outside a module it is the directory itself.
+func (l *driverLoader) localImportPath(dir string) string {
+	switch {
+	case l.mod.module == "":
+		return dir
+	case dir == ".":
+		return l.mod.module
+	default:
+		return l.mod.module + "/" + dir
+	}
+}
+
+// localDir returns the directory of the main module package importPath
+// names.
+func (l *driverLoader) localDir(importPath string) (string, bool) {
+	if l.mod.module == "" {
+		return "", false
+	}
+	if importPath == l.mod.module {
+		return ".", true
+	}
+	rest, ok := strings.CutPrefix(importPath, l.mod.module+"/")
+	if !ok || l.nestedModule(rest) {
+		return "", false
+	}
+	return rest, true
+}
+
+// nestedModule reports whether dir lies in a module nested in the main one.
+func (l *driverLoader) nestedModule(dir string) bool {
+	for ; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(l.rootDir, filepath.FromSlash(dir), "go.mod")); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// relative returns dir relative to the root, slash-separated, reporting
+// false when it lies outside.
+func (l *driverLoader) relative(dir string) (string, bool) {
+	rel, err := filepath.Rel(l.rootDir, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func (l *driverLoader) openFile(name string) (io.ReadCloser, error) {
+	if contents, ok := l.overlay[filepath.Clean(name)]; ok {
+		return io.NopCloser(bytes.NewReader(contents)), nil
+	}
+	return os.Open(name)
+}
+
+// readDir lists dir with the overlay's files added, so files that exist
+// only in the editor are part of their packages.
+func (l *driverLoader) readDir(dir string) ([]fs.FileInfo, error) {
+	dir = filepath.Clean(dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !l.overlayDir(dir) {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var infos []fs.FileInfo
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		infos = append(infos, info)
+		seen[entry.Name()] = true
+	}
+	for name, contents := range l.overlay {
+		if filepath.Dir(name) == dir && !seen[filepath.Base(name)] {
+			infos = append(infos, overlayFileInfo{name: filepath.Base(name), size: int64(len(contents))})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+func (l *driverLoader) isDir(name string) bool {
+	if info, err := os.Stat(name); err == nil {
+		return info.IsDir()
+	}
+	return l.overlayDir(filepath.Clean(name))
+}
+
+// overlayDir reports whether the overlay has files directly in dir.
+func (l *driverLoader) overlayDir(dir string) bool {
+	for name := range l.overlay {
+		if filepath.Dir(name) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// overlayFileInfo describes an overlay file missing from disk.
+type overlayFileInfo struct {
+	name string
+	size int64
+}
+
+func (fi overlayFileInfo) Name() string       { return fi.name }
+func (fi overlayFileInfo) Size() int64        { return fi.size }
+func (fi overlayFileInfo) Mode() fs.FileMode  { return 0o644 }
+func (fi overlayFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi overlayFileInfo) IsDir() bool        { return false }
+func (fi overlayFileInfo) Sys() any           { return nil }
+
+// buildTags returns the tags set by -tags in build flags.
+func buildTags(flags []string) []string {
+	var tags []string
+	for i := 0; i < len(flags); i++ {
+		flag := strings.TrimPrefix(flags[i], "-")
+		value, ok := strings.CutPrefix(flag, "-tags=")
+		if !ok {
+			value, ok = strings.CutPrefix(flag, "tags=")
+		}
+		if !ok && (flag == "tags" || flag == "-tags") && i+1 < len(flags) {
+			i++
+			value, ok = flags[i], true
+		}
+		if !ok {
+			continue
+		}
+		tags = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	return tags
+}
+
+// mergeImports returns the sorted union of two import lists.
+func mergeImports(a, b []string) []string {
+	merged := append(slices.Clone(a), b...)
+	sort.Strings(merged)
+	return slices.Compact(merged)
+}
+
+// escapeModulePath escapes a module path or version as the module cache
+// does, replacing each upper-case letter with "!" and its lower case.
+func escapeModulePath(p string) string {
+	var b strings.Builder
+	for _, r := range p {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -3,6 +3,8 @@ package lsp
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -39,3 +41,233 @@ func TestDriverResponse(t *testing.T) {
 		t.Error("expected 'fmt' in imports")
 	}
 }
+
+func writeDriverTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func driverPackages(resp *DriverResponse) map[string]*DriverPackage {
+	packages := map[string]*DriverPackage{}
+	for _, pkg := range resp.Packages {
+		packages[pkg.ID] = pkg
+	}
+	return packages
+}
+
+func TestDriverPatterns(t *testing.T) {
+	dir := writeDriverTree(t, map[string]string{
+		"go.mod":               "module example.com/app\n\ngo 1.21\n",
+		"main.go":              "package main\n\nimport \"example.com/app/sub\"\n\nfunc main() { sub.Run() }\n",
+		"sub/sub.go":           "package sub\n\nfunc Run() {}\n",
+		"sub/deep/deep.go":     "package deep\n",
+		"sub/testdata/data.go": "package data\n",
+		"nested/go.mod":        "module example.com/nested\n",
+		"nested/nested.go":     "package nested\n",
+	})
+	cases := []struct {
+		patterns []string
+		roots    []string
+	}{
+		{[]string{"./..."}, []string{"example.com/app", "example.com/app/sub", "example.com/app/sub/deep"}},
+		{nil, []string{"example.com/app"}},
+		{[]string{"./sub/..."}, []string{"example.com/app/sub", "example.com/app/sub/deep"}},
+		{[]string{"example.com/app/sub"}, []string{"example.com/app/sub"}},
+		{[]string{"file=" + filepath.Join(dir, "sub", "deep", "deep.go")}, []string{"example.com/app/sub/deep"}},
+	}
+	for _, tc := range cases {
+		resp, err := HandleDriverRequest(dir, DriverRequest{Mode: NeedName | NeedFiles | NeedImports}, tc.patterns)
+		if err != nil {
+			t.Fatalf("driver %v: %v", tc.patterns, err)
+		}
+		if resp.NotHandled || !slices.Equal(resp.Roots, tc.roots) {
+			t.Errorf("patterns %v: roots %v (not handled %v), want %v", tc.patterns, resp.Roots, resp.NotHandled, tc.roots)
+		}
+	}
+
+	resp, err := HandleDriverRequest(dir, DriverRequest{}, []string{"std"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.NotHandled {
+		t.Errorf("std pattern handled, want NotHandled")
+	}
+}
+
+func TestDriverOverlayAndBuildTags(t *testing.T) {
+	dir := writeDriverTree(t, map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.21\n",
+		"main.go": "package main\n\nfunc main() {}\n",
+		"tag.go":  "//go:build special\n\npackage main\n\nimport \"strings\"\n\nvar _ = strings.ToUpper\n",
+	})
+	req := DriverRequest{
+		Mode: NeedName | NeedFiles | NeedImports,
+		Overlay: map[string][]byte{
+			filepath.Join(dir, "main.go"):  []byte("package main\n\nimport \"os\"\n\nfunc main() { os.Exit(0) }\n"),
+			filepath.Join(dir, "extra.go"): []byte("package main\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n"),
+		},
+	}
+	resp, err := HandleDriverRequest(dir, req, []string{"."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := driverPackages(resp)["example.com/app"]
+	if pkg == nil {
+		t.Fatalf("package missing from %+v", resp)
+	}
+	want := []string{filepath.Join(dir, "extra.go"), filepath.Join(dir, "main.go")}
+	if !slices.Equal(pkg.GoFiles, want) {
+		t.Errorf("GoFiles = %v, want %v", pkg.GoFiles, want)
+	}
+	if len(pkg.Imports) != 2 || pkg.Imports["os"] == "" || pkg.Imports["fmt"] == "" {
+		t.Errorf("Imports = %v, want fmt and os from the overlay", pkg.Imports)
+	}
+
+	req = DriverRequest{Mode: NeedName | NeedFiles | NeedImports, BuildFlags: []string{"-tags=special"}}
+	resp, err = HandleDriverRequest(dir, req, []string{"."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg = driverPackages(resp)["example.com/app"]
+	if pkg == nil || len(pkg.GoFiles) != 2 || pkg.Imports["strings"] == "" {
+		t.Errorf("with -tags=special got %+v, want tag.go and its imports", pkg)
+	}
+}
+
+func TestDriverDepsAndTests(t *testing.T) {
+	dir := writeDriverTree(t, map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.21\n",
+		"lib/lib.go":     "package lib\n\nimport \"errors\"\n\nvar Err = errors.New(\"x\")\n",
+		"lib/in_test.go": "package lib\n\nimport \"testing\"\n\nfunc TestIn(t *testing.T) {}\n",
+		"lib/ex_test.go": "package lib_test\n\nimport \"example.com/app/lib\"\n\nvar _ = lib.Err\n",
+	})
+	req := DriverRequest{Mode: NeedName | NeedFiles | NeedImports | NeedDeps | NeedModule, Tests: true}
+	resp, err := HandleDriverRequest(dir, req, []string{"./lib"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRoots := []string{
+		"example.com/app/lib",
+		"example.com/app/lib [example.com/app/lib.test]",
+		"example.com/app/lib_test [example.com/app/lib.test]",
+	}
+	if !slices.Equal(resp.Roots, wantRoots) {
+		t.Errorf("roots = %v, want %v", resp.Roots, wantRoots)
+	}
+	packages := driverPackages(resp)
+	for _, pkg := range resp.Packages {
+		for importPath, id := range pkg.Imports {
+			if packages[id] == nil {
+				t.Errorf("%s imports %s as %s, which is not in the response", pkg.ID, importPath, id)
+			}
+		}
+	}
+	for _, id := range []string{"errors", "testing"} {
+		if packages[id] == nil || len(packages[id].GoFiles) == 0 {
+			t.Errorf("dependency %s missing or without files", id)
+		}
+	}
+	xtest := packages["example.com/app/lib_test [example.com/app/lib.test]"]
+	if xtest == nil || xtest.Name != "lib_test" || xtest.Imports["example.com/app/lib"] != "example.com/app/lib [example.com/app/lib.test]" {
+		t.Errorf("external test package = %+v, want it to import the test variant", xtest)
+	}
+	if lib := packages["example.com/app/lib"]; lib.Module == nil || !lib.Module.Main || lib.Module.Path != "example.com/app" {
+		t.Errorf("lib module = %+v, want the main module", lib.Module)
+	}
+}
+
+func TestDriverModuleCache(t *testing.T) {
+	modcache := writeDriverTree(t, map[string]string{
+		"example.com/!dep@v1.2.0/go.mod":    "module example.com/Dep\n",
+		"example.com/!dep@v1.2.0/util/u.go": "package util\n",
+	})
+	dir := writeDriverTree(t, map[string]string{
+		"go.mod":                     "module example.com/app\n\ngo 1.21\n\nrequire (\n\texample.com/Dep v1.2.0\n\texample.com/local v0.0.0 // indirect\n)\n\nreplace example.com/local => ./third_party/local\n",
+		"main.go":                    "package main\n\nimport (\n\t\"example.com/Dep/util\"\n\t\"example.com/local\"\n)\n\nvar _ = util.X\nvar _ = local.Y\n\nfunc main() {}\n",
+		"third_party/local/go.mod":   "module example.com/local\n",
+		"third_party/local/local.go": "package local\n",
+	})
+	req := DriverRequest{Mode: NeedName | NeedFiles | NeedImports | NeedDeps, Env: []string{"GOMODCACHE=" + modcache}}
+	resp, err := HandleDriverRequest(dir, req, []string{"."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	packages := driverPackages(resp)
+	if pkg := packages["example.com/Dep/util"]; pkg == nil || pkg.Name != "util" {
+		t.Errorf("module cache package = %+v, want util", pkg)
+	}
+	if pkg := packages["example.com/local"]; pkg == nil || pkg.Name != "local" {
+		t.Errorf("replaced package = %+v, want local", pkg)
+	}
+
+	req.Env = []string{"GOMODCACHE=" + t.TempDir()}
+	resp, err = HandleDriverRequest(dir, req, []string{"."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.NotHandled {
+		t.Errorf("missing dependency handled, want NotHandled")
+	}
+}
+
+func TestDriverResponseCache(t *testing.T) {
+	dir := writeDriverTree(t, map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.21\n",
+		"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n",
+	})
+	req := DriverRequest{Mode: NeedName | NeedFiles | NeedImports}
+	first, err := HandleDriverRequest(dir, req, []string{"./..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, ".gts", "driver"))
+	if len(entries) != 1 {
+		t.Fatalf("cached %d responses, want 1", len(entries))
+	}
+
+	// A cached response is served while the tree is unchanged.
+	cachePath := filepath.Join(dir, ".gts", "driver", entries[0].Name())
+	data, _ := os.ReadFile(cachePath)
+	marked := strings.Replace(string(data), `"Name":"main"`, `"Name":"cached"`, 1)
+	if err := os.WriteFile(cachePath, []byte(marked), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	second, err := HandleDriverRequest(dir, req, []string{"./..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Packages[0].Name != "cached" {
+		t.Errorf("second response not served from the cache: %+v", second.Packages[0])
+	}
+
+	// Overlays bypass the cache.
+	overlaid := req
+	overlaid.Overlay = map[string][]byte{filepath.Join(dir, "main.go"): []byte("package main\n\nfunc main() {}\n")}
+	third, err := HandleDriverRequest(dir, overlaid, []string{"./..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.Packages[0].Name != "main" || len(third.Packages[0].Imports) != 0 {
+		t.Errorf("overlay response = %+v, want it computed from the overlay", third.Packages[0])
+	}
+
+	// Editing a Go file invalidates it.
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nimport \"os\"\n\nfunc main() { os.Exit(0) }\n"), 0o644)
+	fourth, err := HandleDriverRequest(dir, req, []string{"./..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg := fourth.Packages[0]; pkg.Name != "main" || pkg.Imports["os"] == "" || len(first.Packages[0].Imports) != 1 {
+		t.Errorf("response after edit = %+v, want it recomputed", pkg)
+	}
+}
@@ -0,0 +1,168 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+)
+
+// Folding range kinds, as LSP names them.
+const (
+	foldComment = "comment"
+	foldImports = "imports"
+)
+
+// handleFoldingRange folds the multi-line blocks of the document's syntax
+// tree, for every language with a grammar: bracketed nodes such as bodies,
+// argument lists, and literals up to the line of their closing bracket, and
+// indented blocks such as Python's from the line of the statement they
+// belong to. Runs of comments and of imports fold as one range each.
+func (s *Service) handleFoldingRange(params json.RawMessage) (any, error) {
+	var p struct {
+		TextDocument TextDocumentIdentifier `json:"textDocument"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	doc, ok := s.parseDocument(p.TextDocument.URI)
+	if !ok {
+		return []FoldingRange{}, nil
+	}
+	defer doc.release()
+	return doc.foldingRanges(), nil
+}
+
+// foldingRanges returns the document's folding ranges sorted by start line,
+// keeping the largest of the ranges starting on the same line.
+func (d *syntaxDocument) foldingRanges() []FoldingRange {
+	byStart := map[int]FoldingRange{}
+	add := func(startLine, endLine int, kind string) {
+		if endLine <= startLine {
+			return
+		}
+		if prev, ok := byStart[startLine]; ok && prev.EndLine >= endLine {
+			return
+		}
+		byStart[startLine] = FoldingRange{StartLine: startLine, EndLine: endLine, Kind: kind}
+	}
+
+	gotreesitter.Walk(d.tree.RootNode(), func(node *gotreesitter.Node, depth int) gotreesitter.WalkAction {
+		startRow, endRow := int(node.StartPoint().Row), int(node.EndPoint().Row)
+		if endRow == startRow {
+			return gotreesitter.WalkSkipChildren
+		}
+		d.foldRuns(node, add)
+		if !node.IsNamed() {
+			return gotreesitter.WalkContinue
+		}
+		kind := node.Type(d.lang)
+		switch {
+		case isCommentKind(kind) || isImportKind(kind):
+			// Folded with their runs.
+		case d.bracketed(node):
+			closing := node.Child(node.ChildCount() - 1)
+			foldKind := ""
+			if isImportKind(kind) || node.Parent() != nil && isImportKind(node.Parent().Type(d.lang)) {
+				foldKind = foldImports
+			}
+			add(startRow, int(closing.StartPoint().Row)-1, foldKind)
+		case strings.Contains(kind, "block") || strings.Contains(kind, "body"):
+			// An indented block folds from the line of its statement.
+			if parent := node.Parent(); parent != nil && int(parent.StartPoint().Row) < startRow {
+				startRow = int(parent.StartPoint().Row)
+			}
+			add(startRow, endRow, "")
+		}
+		return gotreesitter.WalkContinue
+	})
+
+	ranges := make([]FoldingRange, 0, len(byStart))
+	for _, r := range byStart {
+		ranges = append(ranges, r)
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].StartLine < ranges[j].StartLine })
+	return ranges
+}
+
+// foldRuns folds each run of node's children that are comments on lines of
+// their own, or imports, on consecutive lines.
+func (d *syntaxDocument) foldRuns(node *gotreesitter.Node, add func(startLine, endLine int, kind string)) {
+	// The imports of a grouped import fold with their group.
+	insideImport := isImportKind(node.Type(d.lang))
+	runKind := ""
+	runStart, runEnd := 0, 0
+	flush := func() {
+		if runKind != "" {
+			add(runStart, runEnd, runKind)
+		}
+		runKind = ""
+	}
+	for i := 0; i < node.NamedChildCount(); i++ {
+		child := node.NamedChild(i)
+		kind := ""
+		switch childKind := child.Type(d.lang); {
+		case isCommentKind(childKind) && d.startsLine(child):
+			kind = foldComment
+		case isImportKind(childKind) && !insideImport:
+			kind = foldImports
+		}
+		startRow, endRow := int(child.StartPoint().Row), int(child.EndPoint().Row)
+		// Keep the closing bracket of a grouped import visible.
+		if last := child.Child(child.ChildCount() - 1); kind == foldImports && last != nil && d.bracketed(last) {
+			endRow = int(last.EndPoint().Row) - 1
+		}
+		if kind == "" || kind != runKind || startRow > runEnd+1 {
+			flush()
+		}
+		if kind == "" {
+			continue
+		}
+		if runKind == "" {
+			runKind, runStart = kind, startRow
+		}
+		runEnd = endRow
+	}
+	flush()
+}
+
+// bracketed reports whether node starts and ends with matching brackets.
+func (d *syntaxDocument) bracketed(node *gotreesitter.Node) bool {
+	n := node.ChildCount()
+	if n < 2 {
+		return false
+	}
+	first, last := node.Child(0), node.Child(n-1)
+	if first.IsNamed() || last.IsNamed() {
+		return false
+	}
+	switch first.Type(d.lang) + last.Type(d.lang) {
+	case "{}", "()", "[]":
+		return true
+	}
+	return false
+}
+
+// startsLine reports whether only indentation precedes node on its line.
+func (d *syntaxDocument) startsLine(node *gotreesitter.Node) bool {
+	start := int(node.StartByte())
+	lineStart := bytes.LastIndexByte(d.source[:start], '\n') + 1
+	return len(bytes.TrimSpace(d.source[lineStart:start])) == 0
+}
+
+func isCommentKind(kind string) bool {
+	return strings.Contains(kind, "comment")
+}
+
+// isImportKind reports whether kind is an import, such as Go's
+// import_declaration, Python's import_from_statement, Rust's
+// use_declaration, or C's preproc_include.
+func isImportKind(kind string) bool {
+	switch kind {
+	case "use_declaration", "using_directive", "preproc_include":
+		return true
+	}
+	return strings.HasPrefix(kind, "import_")
+}
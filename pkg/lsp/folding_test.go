@@ -0,0 +1,86 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServiceFoldingRange(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte(`package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// run prints its arguments
+// and exits.
+func run(args []string) {
+	for _, arg := range args {
+		fmt.Println(arg)
+	}
+	os.Exit(0)
+}
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "app.py"), []byte(`import os
+import sys
+
+
+class App:
+    def run(self):
+        print(os.name,
+              sys.argv)
+        return 0
+`), 0644)
+
+	input := lspRequest(1, "initialize", map[string]any{"rootUri": "file://" + dir})
+	for i, name := range []string{"main.go", "app.py"} {
+		input += lspRequest(i+2, "textDocument/foldingRange", map[string]any{
+			"textDocument": map[string]string{"uri": "file://" + filepath.Join(dir, name)},
+		})
+	}
+	input += lspRequest(4, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	got := map[string]string{}
+	for _, msg := range splitLSPMessages(t, out.Bytes()) {
+		var ranges []FoldingRange
+		if msg.Method != "" || json.Unmarshal(msg.Result, &ranges) != nil {
+			continue
+		}
+		var parts []string
+		for _, r := range ranges {
+			part := fmt.Sprintf("%d-%d", r.StartLine, r.EndLine)
+			if r.Kind != "" {
+				part += " " + r.Kind
+			}
+			parts = append(parts, part)
+		}
+		got[string(msg.ID)] = strings.Join(parts, ", ")
+	}
+	want := map[string]string{
+		// The import group, the doc comment, the function body, and the
+		// loop body, each keeping its closing line visible.
+		"2": "2-4 imports, 7-8 comment, 9-13, 10-11",
+		// The imports, the class, and the method. The call's arguments end
+		// on the line after the call, which stays visible, so there is
+		// nothing to fold.
+		"3": "0-1 imports, 4-8, 5-8",
+	}
+	for id, w := range want {
+		if got[id] != w {
+			t.Errorf("request %s folding ranges = %q, want %q", id, got[id], w)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/textpos"
+)
+
+// Document highlight kinds, as LSP numbers them.
+const (
+	highlightRead  = 2
+	highlightWrite = 3
+)
+
+// handleDocumentHighlight highlights the occurrences in the file of the
+// name under the cursor: the indexed references to it as reads and the
+// names of the symbols declaring it as writes. Occurrences the open
+// document has moved since it was indexed are left out. A backend's
+// highlights are merged in.
+func (s *Service) handleDocumentHighlight(params json.RawMessage) (any, error) {
+	var p struct {
+		TextDocument TextDocumentIdentifier `json:"textDocument"`
+		Position     Position               `json:"position"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	highlights := s.nativeHighlights(p.TextDocument.URI, p.Position)
+
+	if s.proxyMgr != nil {
+		if b := s.proxyMgr.BackendForFile(uriToPath(p.TextDocument.URI)); b != nil {
+			if raw, err := b.Request("textDocument/documentHighlight", params); err == nil {
+				var backend []DocumentHighlight
+				if json.Unmarshal(raw, &backend) == nil && len(backend) > 0 {
+					highlights = mergeHighlights(backend, highlights)
+				}
+			}
+		}
+	}
+	return highlights, nil
+}
+
+func (s *Service) nativeHighlights(uri string, pos Position) []DocumentHighlight {
+	highlights := []DocumentHighlight{}
+	idx := s.store.Snapshot()
+	if idx == nil {
+		return highlights
+	}
+	relPath := relativeTo(uriToPath(uri), s.rootPath)
+	var file *model.FileSummary
+	for i := range idx.Files {
+		if idx.Files[i].Path == relPath {
+			file = &idx.Files[i]
+			break
+		}
+	}
+	if file == nil {
+		return highlights
+	}
+	source, err := s.documentSource(uri)
+	if err != nil {
+		return highlights
+	}
+
+	enc := s.encoding
+	if enc == "" {
+		enc = textpos.UTF16
+	}
+	m := textpos.New(source)
+	offset := m.Offset(pos.Line, pos.Character, enc)
+	start, end := offset, offset
+	for start > 0 && isWordByte(source[start-1]) {
+		start--
+	}
+	for end < len(source) && isWordByte(source[end]) {
+		end++
+	}
+	name := source[start:end]
+	if len(name) == 0 {
+		return highlights
+	}
+
+	kinds := map[int]int{} // offset -> kind
+	for _, ref := range file.References {
+		if ref.Name != string(name) {
+			continue
+		}
+		at := m.Offset(ref.StartLine-1, max(ref.StartColumn-1, 0), textpos.UTF8)
+		if wordAt(source, at, name) {
+			kinds[at] = highlightRead
+		}
+	}
+	for _, sym := range file.Symbols {
+		if sym.Name != string(name) || sym.StartByte < 0 || sym.EndByte > len(source) || sym.EndByte <= sym.StartByte {
+			continue
+		}
+		// The name is its first whole-word occurrence in the declaration.
+		decl := source[sym.StartByte:sym.EndByte]
+		for from := 0; from < len(decl); {
+			i := bytes.Index(decl[from:], name)
+			if i < 0 {
+				break
+			}
+			if at := sym.StartByte + from + i; wordAt(source, at, name) {
+				kinds[at] = highlightWrite
+				break
+			}
+			from += i + len(name)
+		}
+	}
+	// The word under the cursor must be one of the occurrences, else it is
+	// some other name that happens to be spelled the same.
+	if _, ok := kinds[start]; !ok {
+		return highlights
+	}
+
+	offsets := make([]int, 0, len(kinds))
+	for at := range kinds {
+		offsets = append(offsets, at)
+	}
+	sort.Ints(offsets)
+	for _, at := range offsets {
+		startLine, startCol := m.Position(at, enc)
+		endLine, endCol := m.Position(at+len(name), enc)
+		highlights = append(highlights, DocumentHighlight{
+			Range: Range{
+				Start: Position{Line: startLine, Character: startCol},
+				End:   Position{Line: endLine, Character: endCol},
+			},
+			Kind: kinds[at],
+		})
+	}
+	return highlights
+}
+
+// wordAt reports whether source holds the whole word name at offset.
+func wordAt(source []byte, offset int, name []byte) bool {
+	if offset < 0 || offset > len(source) || !bytes.HasPrefix(source[offset:], name) {
+		return false
+	}
+	end := offset + len(name)
+	return (offset == 0 || !isWordByte(source[offset-1])) && (end == len(source) || !isWordByte(source[end]))
+}
+
+// mergeHighlights adds to backend the native highlights of ranges it does
+// not already cover.
+func mergeHighlights(backend, native []DocumentHighlight) []DocumentHighlight {
+	seen := make(map[Range]bool, len(backend))
+	for _, h := range backend {
+		seen[h.Range] = true
+	}
+	for _, h := range native {
+		if !seen[h.Range] {
+			backend = append(backend, h)
+		}
+	}
+	return backend
+}
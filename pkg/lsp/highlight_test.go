@@ -0,0 +1,69 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServiceDocumentHighlight(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte(`package main
+
+func helper(n int) int { return n }
+
+func helperTwo() {}
+
+func main() {
+	helper(1)
+	helperTwo()
+	helper(helper(2))
+}
+`), 0644)
+	uri := "file://" + dir + "/main.go"
+	highlight := func(id, line, character int) string {
+		return lspRequest(id, "textDocument/documentHighlight", map[string]any{
+			"textDocument": map[string]string{"uri": uri},
+			"position":     map[string]int{"line": line, "character": character},
+		})
+	}
+
+	input := lspRequest(1, "initialize", map[string]any{"rootUri": "file://" + dir})
+	input += lspNotify("initialized", map[string]any{})
+	input += highlight(2, 7, 3) // helper(1)
+	input += highlight(3, 2, 6) // the declaration of helper
+	input += highlight(4, 6, 0) // func keyword
+	input += lspRequest(5, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	got := map[string]string{}
+	for _, msg := range splitLSPMessages(t, out.Bytes()) {
+		var highlights []DocumentHighlight
+		if msg.Method != "" || json.Unmarshal(msg.Result, &highlights) != nil {
+			continue
+		}
+		var parts []string
+		for _, h := range highlights {
+			parts = append(parts, fmt.Sprintf("%d:%d-%d:%d/%d", h.Range.Start.Line, h.Range.Start.Character, h.Range.End.Line, h.Range.End.Character, h.Kind))
+		}
+		got[string(msg.ID)] = strings.Join(parts, " ")
+	}
+	want := "2:5-2:11/3 7:1-7:7/2 9:1-9:7/2 9:8-9:14/2"
+	for _, id := range []string{"2", "3"} {
+		if got[id] != want {
+			t.Errorf("request %s highlights = %q, want %q", id, got[id], want)
+		}
+	}
+	if got["4"] != "" {
+		t.Errorf("highlights on a keyword = %q, want none", got["4"])
+	}
+}
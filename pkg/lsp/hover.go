@@ -0,0 +1,248 @@
+package lsp
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/docgen"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// nativeHover describes the symbol at pos: the definition a reference
+// there resolves to, else the innermost symbol declared around it. The
+// description gives its signature, doc comment, and defining file, how
+// often it calls and is called according to the call graph, and where it
+// sits in the outline.
+func (s *Service) nativeHover(uri string, pos Position) *Hover {
+	relPath := relativeTo(uriToPath(uri), s.rootPath)
+	idx := s.store.Snapshot()
+	if idx == nil {
+		return nil
+	}
+	var file *model.FileSummary
+	for i := range idx.Files {
+		if idx.Files[i].Path == relPath {
+			file = &idx.Files[i]
+			break
+		}
+	}
+	if file == nil {
+		return nil
+	}
+
+	graph := s.callGraph(idx)
+	line := pos.Line + 1
+	col := s.positions().byteColumn(relPath, pos)
+	target, sym, ok := hoverReferenceTarget(idx, graph, file, line, col)
+	if !ok {
+		target, sym, ok = file, innermostSymbol(file.Symbols, line), true
+		if sym == nil {
+			return nil
+		}
+	}
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: s.describeSymbol(graph, target, *sym)},
+	}
+}
+
+// callGraph returns the call graph of idx, brought up to date on first use
+// after each change. Only the files that changed since the previous index are
+// re-resolved. It is nil when the graph cannot be built.
+func (s *Service) callGraph(idx *model.Index) *xref.Graph {
+	if s.xrefIndex == idx {
+		return s.xrefGraph
+	}
+	s.xrefGraph = nil
+	switch {
+	case idx == nil:
+		s.xrefInc = nil
+	case s.xrefInc == nil || s.xrefIndex == nil || s.xrefIndex.Root != idx.Root:
+		inc, err := xref.NewIncremental(idx)
+		if err != nil {
+			s.xrefInc = nil
+			break
+		}
+		s.xrefInc = inc
+		s.xrefGraph = inc.Graph()
+	default:
+		s.xrefInc.Sync(idx)
+		s.xrefGraph = s.xrefInc.Graph()
+	}
+	s.xrefIndex = idx
+	return s.xrefGraph
+}
+
+// describeSymbol renders the hover of sym, declared in file.
+func (s *Service) describeSymbol(graph *xref.Graph, file *model.FileSummary, sym model.Symbol) string {
+	content := sym.Kind + " " + sym.Name
+	if sym.Signature != "" {
+		content = sym.Signature
+	}
+	sections := []string{"```" + file.Language + "\n" + content + "\n```"}
+	if source, err := s.documentSource(pathToURI(file.Path, s.rootPath)); err == nil {
+		if doc := docgen.DocComment(strings.Split(string(source), "\n"), sym.StartLine); doc != "" {
+			sections = append(sections, doc)
+		}
+	}
+	sections = append(sections, fmt.Sprintf("Defined in `%s:%d`", file.Path, sym.StartLine))
+	if graph != nil {
+		if def, ok := graph.DefinitionOf(file.Path, sym); ok && def.Callable {
+			sections = append(sections, fmt.Sprintf("Called %s from %s · calls %s",
+				plural(graph.IncomingCount(def.ID), "time"),
+				plural(len(graph.IncomingEdges(def.ID)), "caller"),
+				plural(len(graph.OutgoingEdges(def.ID)), "function")))
+		}
+	}
+	sections = append(sections, breadcrumbs(file.Path, sym))
+	return strings.Join(sections, "\n\n")
+}
+
+// hoverReferenceTarget resolves the reference at the 1-based line and
+// 0-based byte column of file. A call resolves as the call graph resolved
+// it; anything else to the symbols of its name in the same file, else the
+// same directory, else anywhere.
+func hoverReferenceTarget(idx *model.Index, graph *xref.Graph, file *model.FileSummary, line, col int) (*model.FileSummary, *model.Symbol, bool) {
+	var ref *model.Reference
+	for i, candidate := range file.References {
+		if candidate.StartLine == line && candidate.StartColumn-1 <= col && (candidate.EndLine > line || col < candidate.EndColumn-1) {
+			ref = &file.References[i]
+			break
+		}
+	}
+	if ref == nil {
+		return nil, nil, false
+	}
+
+	if graph != nil && strings.HasPrefix(ref.Kind, "reference.call") {
+		if caller := innermostCallable(file.Symbols, line); caller != nil {
+			if def, ok := graph.DefinitionOf(file.Path, *caller); ok {
+				if callee := calleeAt(graph, def.ID, file.Path, *ref); callee != nil {
+					if target, sym, ok := symbolOf(idx, *callee); ok {
+						return target, sym, true
+					}
+				}
+			}
+		}
+	}
+
+	dir := path.Dir(file.Path)
+	for _, tier := range []func(f *model.FileSummary) bool{
+		func(f *model.FileSummary) bool { return f.Path == file.Path },
+		func(f *model.FileSummary) bool { return path.Dir(f.Path) == dir },
+		func(f *model.FileSummary) bool { return true },
+	} {
+		for i := range idx.Files {
+			f := &idx.Files[i]
+			if !tier(f) {
+				continue
+			}
+			for j := range f.Symbols {
+				if f.Symbols[j].Name == ref.Name {
+					return f, &f.Symbols[j], true
+				}
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// calleeAt returns the definition the call ref, made by callerID, resolved
+// to: the callee of the edge sampling it, else the only callee of its name.
+func calleeAt(graph *xref.Graph, callerID, file string, ref model.Reference) *xref.Definition {
+	var named *xref.Definition
+	count := 0
+	for _, edge := range graph.OutgoingEdges(callerID) {
+		callee := graph.EdgeCallee(edge)
+		if callee == nil || callee.Name != strings.SplitN(ref.Name, "[", 2)[0] {
+			continue
+		}
+		for _, sample := range edge.Samples {
+			if sample.File == file && sample.StartLine == ref.StartLine && sample.StartColumn == ref.StartColumn {
+				return callee
+			}
+		}
+		named = callee
+		count++
+	}
+	if count == 1 {
+		return named
+	}
+	return nil
+}
+
+// symbolOf returns the indexed symbol def was built from.
+func symbolOf(idx *model.Index, def xref.Definition) (*model.FileSummary, *model.Symbol, bool) {
+	for i := range idx.Files {
+		f := &idx.Files[i]
+		if f.Path != def.File {
+			continue
+		}
+		for j, sym := range f.Symbols {
+			if sym.Kind == def.Kind && sym.Name == def.Name && sym.StartLine == def.StartLine {
+				return f, &f.Symbols[j], true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// innermostSymbol returns the symbol with the smallest line range around
+// line, or nil.
+func innermostSymbol(symbols []model.Symbol, line int) *model.Symbol {
+	return innermost(symbols, line, func(model.Symbol) bool { return true })
+}
+
+// innermostCallable returns the innermost function or method around line.
+func innermostCallable(symbols []model.Symbol, line int) *model.Symbol {
+	return innermost(symbols, line, func(sym model.Symbol) bool {
+		switch sym.Kind {
+		case "method_definition", "closure_definition":
+			return true
+		}
+		return model.IsFunctionKind(sym.Kind)
+	})
+}
+
+func innermost(symbols []model.Symbol, line int, keep func(model.Symbol) bool) *model.Symbol {
+	var best *model.Symbol
+	for i, sym := range symbols {
+		if sym.StartLine > line || line > sym.EndLine || !keep(sym) {
+			continue
+		}
+		span, bestSpan := sym.EndLine-sym.StartLine, 0
+		if best != nil {
+			bestSpan = best.EndLine - best.StartLine
+		}
+		if best == nil || span < bestSpan || span == bestSpan && sym.StartLine > best.StartLine {
+			best = &symbols[i]
+		}
+	}
+	return best
+}
+
+// plural renders n and noun, adding an s unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// breadcrumbs renders the outline path to sym, such as "main.go › Server ›
+// Serve": its file, the containers the index nests it in, and its name. A
+// method declared apart from its type is placed under its receiver type.
+func breadcrumbs(relPath string, sym model.Symbol) string {
+	crumbs := []string{path.Base(relPath)}
+	switch {
+	case sym.ContainerPath != "":
+		crumbs = append(crumbs, strings.Split(sym.ContainerPath, ".")...)
+	case sym.Receiver != "":
+		if typ := model.ReceiverType(sym.Receiver); typ != "" {
+			crumbs = append(crumbs, typ)
+		}
+	}
+	crumbs = append(crumbs, sym.Name)
+	return strings.Join(crumbs, " › ")
+}
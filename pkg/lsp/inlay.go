@@ -0,0 +1,466 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/textpos"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// Inlay hint kinds, as LSP numbers them.
+const (
+	inlayHintType      = 1
+	inlayHintParameter = 2
+)
+
+// maxCallScan bounds how many bytes past a callee's name the argument list
+// of a call is read.
+const maxCallScan = 1 << 16
+
+var (
+	// namedArgument matches arguments passed by name, such as Python's
+	// timeout=5 or C#'s timeout: 5, after which positions no longer line up
+	// with parameters.
+	namedArgument = regexp.MustCompile(`^[A-Za-z_$][\w$]*\s*(=[^=>]|:[^:])`)
+	identifierRE  = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+)
+
+// receiverParams are the names of explicit receiver parameters, which calls
+// through the receiver do not pass.
+var receiverParams = map[string]bool{"self": true, "cls": true, "this": true}
+
+// hintParam is a parameter parsed from a signature.
+type hintParam struct {
+	// name is empty for parameters without one to show, such as a
+	// destructuring pattern.
+	name     string
+	variadic bool
+	// rest marks where positional arguments end, as at Python's bare * or
+	// **kwargs.
+	rest bool
+}
+
+// hintCallee is a callable definition a call may resolve to.
+type hintCallee struct {
+	sym      model.Symbol
+	language string
+	params   []hintParam
+}
+
+// handleInlayHint shows parameter names before the arguments of calls to
+// indexed functions, and the receiver type a method call resolves to after
+// its receiver. Parameters come from the indexed signatures, so hints work
+// for every indexed language, without a backend.
+func (s *Service) handleInlayHint(params json.RawMessage) (any, error) {
+	var p InlayHintParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	hints := []InlayHint{}
+	idx := s.store.Snapshot()
+	if idx == nil {
+		return hints, nil
+	}
+	filePath := uriToPath(p.TextDocument.URI)
+	relPath := relativeTo(filePath, s.rootPath)
+	var file *model.FileSummary
+	for i := range idx.Files {
+		if idx.Files[i].Path == relPath {
+			file = &idx.Files[i]
+			break
+		}
+	}
+	if file == nil {
+		return hints, nil
+	}
+	source, err := s.documentSource(p.TextDocument.URI)
+	if err != nil {
+		return hints, nil
+	}
+
+	enc := s.encoding
+	if enc == "" {
+		enc = textpos.UTF16
+	}
+	m := textpos.New(source)
+	position := func(offset int) Position {
+		line, col := m.Position(offset, enc)
+		return Position{Line: line, Character: col}
+	}
+	callables := callablesByName(idx)
+	for _, ref := range file.References {
+		if !strings.HasPrefix(ref.Kind, "reference.call") || ref.StartLine-1 < p.Range.Start.Line || ref.StartLine-1 > p.Range.End.Line {
+			continue
+		}
+		offset := m.Offset(ref.StartLine-1, max(ref.StartColumn-1, 0), textpos.UTF8)
+		// Skip calls the open document has moved since it was indexed.
+		if ref.Name == "" || !bytes.HasPrefix(source[offset:], []byte(ref.Name)) {
+			continue
+		}
+		name := ref.Name
+		// A call to Map[int] calls Map.
+		if open := strings.IndexByte(name, '['); open > 0 {
+			name = name[:open]
+		}
+		args := callArguments(source, offset+len(ref.Name))
+		packageCall := ref.Qualifier != "" && importsPackage(file.Imports, ref.Qualifier)
+		callees := resolveHintCallees(file, ref, name, packageCall, len(args), callables[name])
+		if len(callees) == 0 {
+			continue
+		}
+
+		if typ := callees[0].sym.ContainerPath; ref.Qualifier != "" && !packageCall && typ != "" && !receiverParams[ref.Qualifier] {
+			sameType := true
+			for _, callee := range callees[1:] {
+				sameType = sameType && callee.sym.ContainerPath == typ
+			}
+			qualifier := ref.Qualifier
+			end := offset
+			for end > 0 && strings.IndexByte(".:->?", source[end-1]) >= 0 {
+				end--
+			}
+			// A call through the type itself, such as Foo.new, needs no hint.
+			if sameType && !strings.HasSuffix(qualifier, typ) && end >= len(qualifier) && string(source[end-len(qualifier):end]) == qualifier {
+				hints = append(hints, InlayHint{Position: position(end), Label: ": " + typ, Kind: inlayHintType})
+			}
+		}
+
+		params := callees[0].params
+		for i, arg := range args {
+			if i >= len(params) || params[i].rest {
+				break
+			}
+			text := string(source[arg[0]:arg[1]])
+			if namedArgument.MatchString(text) || strings.HasPrefix(text, "...") || strings.HasPrefix(text, "*") {
+				break
+			}
+			param := params[i]
+			if param.name != "" && param.name != "_" && !argumentNamesParam(text, param.name) {
+				hints = append(hints, InlayHint{Position: position(arg[0]), Label: param.name + ":", Kind: inlayHintParameter, PaddingRight: true})
+			}
+			if param.variadic {
+				break
+			}
+		}
+	}
+	return hints, nil
+}
+
+// callablesByName groups the function and method definitions of idx that
+// have signatures by name.
+func callablesByName(idx *model.Index) map[string][]hintCallee {
+	callables := map[string][]hintCallee{}
+	for _, file := range idx.Files {
+		for _, sym := range file.Symbols {
+			if sym.Signature == "" || !model.IsFunctionKind(sym.Kind) && sym.Kind != "method_definition" {
+				continue
+			}
+			callables[sym.Name] = append(callables[sym.Name], hintCallee{sym: sym, language: file.Language})
+		}
+	}
+	return callables
+}
+
+// resolveHintCallees returns the definitions a call to name in file may
+// resolve to, nearest first: those in the same file, else the same
+// directory, else anywhere. It returns none unless they agree on parameter
+// names. A call qualified by an imported package resolves to functions of a
+// directory of that name, and one qualified otherwise to methods.
+func resolveHintCallees(file *model.FileSummary, ref model.Reference, name string, packageCall bool, argCount int, candidates []hintCallee) []hintCallee {
+	var pool []hintCallee
+	for _, candidate := range candidates {
+		if xref.LanguageFamily(candidate.language) != xref.LanguageFamily(file.Language) {
+			continue
+		}
+		switch {
+		case packageCall:
+			if candidate.sym.ContainerPath != "" || path.Base(path.Dir(candidate.sym.File)) != ref.Qualifier {
+				continue
+			}
+		case ref.Qualifier != "":
+			if candidate.sym.ContainerPath == "" {
+				continue
+			}
+		}
+		params, ok := signatureParameters(candidate.sym.Signature, name, candidate.language)
+		if !ok || !acceptsArguments(params, argCount) {
+			continue
+		}
+		candidate.params = params
+		pool = append(pool, candidate)
+	}
+
+	dir := path.Dir(file.Path)
+	tiers := []func(hintCallee) bool{
+		func(c hintCallee) bool { return c.sym.File == file.Path },
+		func(c hintCallee) bool { return path.Dir(c.sym.File) == dir },
+		func(hintCallee) bool { return true },
+	}
+	for _, near := range tiers {
+		var tier, functions []hintCallee
+		for _, candidate := range pool {
+			if near(candidate) {
+				tier = append(tier, candidate)
+				if candidate.sym.ContainerPath == "" {
+					functions = append(functions, candidate)
+				}
+			}
+		}
+		if len(tier) == 0 {
+			continue
+		}
+		// An unqualified call is to a function unless no function fits.
+		if ref.Qualifier == "" && len(functions) > 0 {
+			tier = functions
+		}
+		for _, candidate := range tier[1:] {
+			if !sameParameterNames(candidate.params, tier[0].params) {
+				return nil
+			}
+		}
+		return tier
+	}
+	return nil
+}
+
+// importsPackage reports whether qualifier names one of imports, by its
+// last path or module element.
+func importsPackage(imports []string, qualifier string) bool {
+	for _, imp := range imports {
+		imp = strings.Trim(imp, "\"'`")
+		if imp == qualifier || path.Base(imp) == qualifier || strings.HasSuffix(imp, "."+qualifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsArguments reports whether a call with count positional arguments
+// fits params. Calls passing fewer are accepted, as defaults and optional
+// parameters are not always visible in a signature.
+func acceptsArguments(params []hintParam, count int) bool {
+	positional := 0
+	for _, param := range params {
+		if param.variadic || param.rest {
+			return true
+		}
+		positional++
+	}
+	return count <= positional
+}
+
+func sameParameterNames(a, b []hintParam) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// argumentNamesParam reports whether an argument already says what it is,
+// being the parameter's name or a selector ending in it.
+func argumentNamesParam(arg, name string) bool {
+	arg = strings.TrimLeft(arg, "&*")
+	if dot := strings.LastIndexByte(arg, '.'); dot >= 0 {
+		arg = arg[dot+1:]
+	}
+	return strings.EqualFold(arg, name)
+}
+
+// signatureParameters parses the parameters of the list following name in
+// signature. A list cut short, as signatures spanning lines are, yields the
+// parameters before the cut. It reports false when signature has no list.
+func signatureParameters(signature, name, language string) ([]hintParam, bool) {
+	rest := ""
+	for from := 0; ; {
+		at := strings.Index(signature[from:], name)
+		if at < 0 {
+			return nil, false
+		}
+		rest = signature[from+at+len(name):]
+		if rest != "" && strings.IndexByte("([<", rest[0]) >= 0 {
+			break
+		}
+		from += at + len(name)
+	}
+	// Skip type parameters, as in Go's Map[T any](...) or TypeScript's
+	// map<T>(...).
+	if rest[0] == '[' || rest[0] == '<' {
+		closing := byte(']')
+		if rest[0] == '<' {
+			closing = '>'
+		}
+		_, closed, end := splitList(rest, 1, closing, true)
+		if !closed {
+			return nil, false
+		}
+		rest = rest[end:]
+	}
+	if !strings.HasPrefix(rest, "(") {
+		return nil, false
+	}
+	spans, closed, _ := splitList(rest, 1, ')', true)
+	if !closed && len(spans) > 0 {
+		spans = spans[:len(spans)-1]
+	}
+
+	parts := make([]string, 0, len(spans))
+	goNamed := false
+	for _, span := range spans {
+		part := strings.TrimSpace(rest[span[0]:span[1]])
+		parts = append(parts, part)
+		if fields := strings.Fields(part); language == "go" && len(fields) > 1 {
+			goNamed = true
+		}
+	}
+	var params []hintParam
+	for i, part := range parts {
+		switch part {
+		case "", "/":
+			// Python's / only marks the end of positional-only parameters.
+			continue
+		case "*":
+			params = append(params, hintParam{rest: true})
+			continue
+		}
+		param := parseParameter(part, language == "go", goNamed)
+		if i == 0 && receiverParams[param.name] {
+			continue
+		}
+		params = append(params, param)
+	}
+	return params, true
+}
+
+// parseParameter parses one parameter declaration. Names come before a
+// colon when there is one, as in Python, TypeScript, and Rust; first in Go,
+// where they are all omitted or all given; and last elsewhere, as in Java
+// and C.
+func parseParameter(text string, goLang, goNamed bool) hintParam {
+	var param hintParam
+	if eq := topLevelIndex(text, '='); eq >= 0 {
+		text = text[:eq]
+	}
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "**") {
+		return hintParam{rest: true}
+	}
+	param.variadic = strings.Contains(text, "...") || strings.HasPrefix(text, "*")
+
+	fields := strings.Fields(text)
+	switch colon := topLevelIndex(text, ':'); {
+	case colon >= 0:
+		fields = strings.Fields(text[:colon])
+	case goLang && !goNamed:
+		return param
+	case goLang:
+		fields = fields[:min(len(fields), 1)]
+	}
+	if len(fields) == 0 {
+		return param
+	}
+	name := fields[len(fields)-1]
+	name = strings.TrimLeft(name, ".*&$")
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "?"), "[]")
+	if identifierRE.MatchString(name) {
+		param.name = name
+	}
+	return param
+}
+
+// topLevelIndex returns the index of the first sep in text outside
+// brackets, skipping == and =>, and :: and :=, or -1.
+func topLevelIndex(text string, sep byte) int {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			if depth > 0 {
+				depth--
+			}
+		case sep:
+			if depth > 0 {
+				continue
+			}
+			var prev, next byte
+			if i > 0 {
+				prev = text[i-1]
+			}
+			if i+1 < len(text) {
+				next = text[i+1]
+			}
+			if next == '=' || next == '>' || next == ':' || prev != 0 && strings.IndexByte("=!<>:", prev) >= 0 {
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// callArguments returns the spans of the arguments of the call whose callee
+// ends at offset in source, trimmed of whitespace, or none when no argument
+// list follows.
+func callArguments(source []byte, offset int) [][2]int {
+	text := string(source[offset:min(len(source), offset+maxCallScan)])
+	trimmed := strings.TrimLeft(text, " \t")
+	start := len(text) - len(trimmed)
+	if !strings.HasPrefix(trimmed, "(") {
+		return nil
+	}
+	spans, _, _ := splitList(text, start+1, ')', false)
+	args := make([][2]int, 0, len(spans))
+	for _, span := range spans {
+		arg := text[span[0]:span[1]]
+		lead := len(arg) - len(strings.TrimLeft(arg, " \t\r\n"))
+		trail := len(strings.TrimRight(arg, " \t\r\n"))
+		if trail <= lead {
+			// A trailing comma leaves an empty last span.
+			continue
+		}
+		args = append(args, [2]int{offset + span[0] + lead, offset + span[0] + trail})
+	}
+	return args
+}
+
+// splitList splits the comma-separated list in text starting at from, just
+// past its opening bracket, into spans of text. It stops at the closing
+// bracket, reporting whether there was one and the offset past it. Commas
+// inside nested brackets and string literals do not split, nor, with angles,
+// commas inside angle brackets, as in the type Map<K, V>.
+func splitList(text string, from int, closing byte, angles bool) (spans [][2]int, closed bool, end int) {
+	depth := 0
+	start := from
+	for i := from; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			for i++; i < len(text) && text[i] != c; i++ {
+				if text[i] == '\\' {
+					i++
+				}
+			}
+		case depth == 0 && c == closing:
+			return append(spans, [2]int{start, i}), true, i + 1
+		case c == '(' || c == '[' || c == '{' || angles && c == '<':
+			depth++
+		case c == ')' || c == ']' || c == '}' || angles && c == '>' && text[i-1] != '=' && text[i-1] != '-':
+			depth = max(depth-1, 0)
+		case c == ',' && depth == 0:
+			spans = append(spans, [2]int{start, i})
+			start = i + 1
+		}
+	}
+	return append(spans, [2]int{start, len(text)}), false, len(text)
+}
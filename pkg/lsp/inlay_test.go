@@ -0,0 +1,93 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSignatureParameters(t *testing.T) {
+	tests := []struct {
+		signature, name, language string
+		want                      []hintParam
+	}{
+		{"func (s *Server) Serve(addr string, timeout int, opts ...string) error", "Serve", "go",
+			[]hintParam{{name: "addr"}, {name: "timeout"}, {name: "opts", variadic: true}}},
+		{"func Add(a, b int) int", "Add", "go", []hintParam{{name: "a"}, {name: "b"}}},
+		{"func Map[T any](xs []T, fn func(T) T) []T", "Map", "go", []hintParam{{name: "xs"}, {name: "fn"}}},
+		{"func(int, string)", "func", "go", []hintParam{{}, {}}},
+		{`def greet(self, name, punctuation="!"):`, "greet", "python", []hintParam{{name: "name"}, {name: "punctuation"}}},
+		{"def area(width: int, height: int = 2, /, *args, **kw) -> int:", "area", "python",
+			[]hintParam{{name: "width"}, {name: "height"}, {name: "args", variadic: true}, {rest: true}}},
+		{"function connect(host: string, port: number,", "connect", "typescript", []hintParam{{name: "host"}, {name: "port"}}},
+		{"send({ retries }: Opts, urgent?: boolean)", "send", "typescript", []hintParam{{}, {name: "urgent"}}},
+		{"fn scale(&mut self, value: f64, factor: f64) -> f64", "scale", "rust", []hintParam{{name: "value"}, {name: "factor"}}},
+		{"int put(Map<String, Integer> entries, String... keys)", "put", "java",
+			[]hintParam{{name: "entries"}, {name: "keys", variadic: true}}},
+	}
+	for _, tt := range tests {
+		got, ok := signatureParameters(tt.signature, tt.name, tt.language)
+		if !ok || !sameParameterNames(got, tt.want) {
+			t.Errorf("signatureParameters(%q) = %+v, %v; want %+v", tt.signature, got, ok, tt.want)
+		}
+	}
+	if _, ok := signatureParameters("class Greeter:", "Greeter", "python"); ok {
+		t.Error("expected a signature without a parameter list to be rejected")
+	}
+}
+
+func TestServiceInlayHints(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte(`package main
+
+type Server struct{}
+
+func (s *Server) Serve(addr string, timeout int) error { return nil }
+
+func Add(a, b int) int { return a + b }
+
+func main() {
+	srv := &Server{}
+	timeout := 3
+	srv.Serve(":80", timeout)
+	Add(1, Add(2, 3))
+}
+`), 0644)
+	uri := "file://" + dir + "/main.go"
+
+	input := lspRequest(1, "initialize", map[string]any{"rootUri": "file://" + dir})
+	input += lspNotify("initialized", map[string]any{})
+	input += lspRequest(2, "textDocument/inlayHint", map[string]any{
+		"textDocument": map[string]string{"uri": uri},
+		"range":        map[string]any{"start": map[string]int{"line": 0}, "end": map[string]int{"line": 20}},
+	})
+	input += lspRequest(3, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	var hints []InlayHint
+	for _, msg := range splitLSPMessages(t, out.Bytes()) {
+		if string(msg.ID) == "2" {
+			if err := json.Unmarshal(msg.Result, &hints); err != nil {
+				t.Fatalf("decode inlay hints: %v", err)
+			}
+		}
+	}
+	var got []string
+	for _, hint := range hints {
+		got = append(got, fmt.Sprintf("%d:%d %s", hint.Position.Line, hint.Position.Character, hint.Label))
+	}
+	// timeout is passed as timeout, so it needs no hint.
+	want := []string{"11:4 : Server", "11:11 addr:", "12:5 a:", "12:8 b:", "12:12 a:", "12:15 b:"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("inlay hints = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,184 @@
+package lsp
+
+import (
+	"os"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/slashpath"
+	"github.com/odvcencio/gts-suite/pkg/textpos"
+)
+
+// negotiateEncoding picks the position encoding for the session from the
+// ones the client offers, in its order of preference. Without an offer, or
+// when requests may be proxied to backends that assume the default, it is
+// UTF-16.
+func negotiateEncoding(offered []string, proxied bool) textpos.Encoding {
+	if proxied {
+		return textpos.UTF16
+	}
+	for _, name := range offered {
+		if enc, ok := textpos.ParseEncoding(name); ok {
+			return enc
+		}
+	}
+	return textpos.UTF16
+}
+
+// positions converts between the byte columns the index records and the
+// client's position encoding for the duration of one request, reading each
+// file it is asked about at most once. Files it cannot read keep byte
+// columns.
+type positions struct {
+	root    string
+	enc     textpos.Encoding
+	sources map[string][]byte
+	maps    map[string]*textpos.Map
+}
+
+func (s *Service) positions() *positions {
+	enc := s.encoding
+	if enc == "" {
+		enc = textpos.UTF16
+	}
+	return &positions{
+		root:    s.rootPath,
+		enc:     enc,
+		sources: map[string][]byte{},
+		maps:    map[string]*textpos.Map{},
+	}
+}
+
+func (p *positions) load(relPath string) *textpos.Map {
+	if m, ok := p.maps[relPath]; ok {
+		return m
+	}
+	var m *textpos.Map
+	src, err := os.ReadFile(slashpath.Join(p.root, relPath))
+	if err == nil {
+		m = textpos.New(src)
+		p.sources[relPath] = src
+	}
+	p.maps[relPath] = m
+	return m
+}
+
+// position returns the LSP position of the 1-based line and 0-based byte
+// column in relPath.
+func (p *positions) position(relPath string, line, byteCol int) Position {
+	pos := Position{Line: line - 1, Character: byteCol}
+	if p.enc == textpos.UTF8 || byteCol <= 0 {
+		return pos
+	}
+	if m := p.load(relPath); m != nil {
+		pos.Character = m.Column(pos.Line, byteCol, p.enc)
+	}
+	return pos
+}
+
+func (p *positions) span(relPath string, startLine, startCol, endLine, endCol int) Range {
+	return Range{
+		Start: p.position(relPath, startLine, startCol),
+		End:   p.position(relPath, endLine, endCol),
+	}
+}
+
+// byteColumn returns the 0-based byte column of pos in relPath.
+func (p *positions) byteColumn(relPath string, pos Position) int {
+	if p.enc == textpos.UTF8 {
+		return pos.Character
+	}
+	if m := p.load(relPath); m != nil {
+		return m.ByteColumn(pos.Line, pos.Character, p.enc)
+	}
+	return pos.Character
+}
+
+// referenceRange returns the range of ref, whose columns are 1-based.
+func (p *positions) referenceRange(relPath string, ref model.Reference) Range {
+	return p.span(relPath, ref.StartLine, max(ref.StartColumn-1, 0), ref.EndLine, max(ref.EndColumn-1, 0))
+}
+
+// symbolRange returns the range of sym's whole declaration. Indexes built
+// before symbol columns were recorded give whole lines.
+func (p *positions) symbolRange(relPath string, sym model.Symbol) Range {
+	if sym.StartColumn <= 0 || sym.EndColumn <= 0 {
+		return Range{
+			Start: Position{Line: sym.StartLine - 1, Character: 0},
+			End:   Position{Line: sym.EndLine - 1, Character: 0},
+		}
+	}
+	return p.span(relPath, sym.StartLine, sym.StartColumn-1, sym.EndLine, sym.EndColumn-1)
+}
+
+// symbolNameRange returns the range of sym's name, approximated with the
+// start of the declaration's line when nameRange cannot find it.
+func (p *positions) symbolNameRange(relPath string, sym model.Symbol) Range {
+	if r, ok := p.nameRange(relPath, sym); ok {
+		return r
+	}
+	return Range{
+		Start: Position{Line: sym.StartLine - 1, Character: 0},
+		End:   Position{Line: sym.StartLine - 1, Character: len(sym.Name)},
+	}
+}
+
+// nameRange returns the range of the first whole-word occurrence of sym's
+// name in its declaration. It reports false for symbols without byte
+// offsets.
+func (p *positions) nameRange(relPath string, sym model.Symbol) (Range, bool) {
+	m := p.load(relPath)
+	src := p.sources[relPath]
+	if m == nil || sym.Name == "" || sym.EndByte <= sym.StartByte || sym.EndByte > len(src) {
+		return Range{}, false
+	}
+	decl := string(src[sym.StartByte:sym.EndByte])
+	for from := 0; ; {
+		i := strings.Index(decl[from:], sym.Name)
+		if i < 0 {
+			return Range{}, false
+		}
+		at := from + i
+		end := at + len(sym.Name)
+		from = end
+		if at > 0 && isWordByte(decl[at-1]) || end < len(decl) && isWordByte(decl[end]) {
+			continue
+		}
+		startLine, startCol := m.Position(sym.StartByte+at, p.enc)
+		endLine, endCol := m.Position(sym.StartByte+end, p.enc)
+		return Range{
+			Start: Position{Line: startLine, Character: startCol},
+			End:   Position{Line: endLine, Character: endCol},
+		}, true
+	}
+}
+
+func isWordByte(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch >= 0x80
+}
+
+func (p *positions) documentSymbols(relPath string, syms []model.Symbol) []DocumentSymbol {
+	return p.documentSymbolNodes(relPath, model.NestSymbols(syms))
+}
+
+func (p *positions) documentSymbolNodes(relPath string, nodes []model.SymbolNode) []DocumentSymbol {
+	result := make([]DocumentSymbol, 0, len(nodes))
+	for _, node := range nodes {
+		r := p.symbolRange(relPath, node.Symbol)
+		selection, ok := p.nameRange(relPath, node.Symbol)
+		if !ok {
+			selection = r
+		}
+		symbol := DocumentSymbol{
+			Name:           node.Name,
+			Kind:           symbolKindFromModel(node.Kind),
+			Range:          r,
+			SelectionRange: selection,
+		}
+		if len(node.Children) > 0 {
+			symbol.Children = p.documentSymbolNodes(relPath, node.Children)
+		}
+		result = append(result, symbol)
+	}
+	return result
+}
@@ -24,8 +24,19 @@ type rpcError struct {
 
 // LSP types -- minimal set for initialize
 type InitializeParams struct {
-	RootURI  string `json:"rootUri"`
-	RootPath string `json:"rootPath"`
+	RootURI      string             `json:"rootUri"`
+	RootPath     string             `json:"rootPath"`
+	Capabilities ClientCapabilities `json:"capabilities"`
+	// InitializationOptions holds the initial Settings.
+	InitializationOptions json.RawMessage `json:"initializationOptions,omitempty"`
+}
+
+type ClientCapabilities struct {
+	General struct {
+		// PositionEncodings lists the encodings the client can count
+		// Position.Character in, most preferred first.
+		PositionEncodings []string `json:"positionEncodings,omitempty"`
+	} `json:"general"`
 }
 
 type InitializeResult struct {
@@ -39,15 +50,21 @@ type ServerInfo struct {
 }
 
 type ServerCapabilities struct {
-	TextDocumentSync        int  `json:"textDocumentSync,omitempty"`
-	DocumentSymbolProvider  bool `json:"documentSymbolProvider,omitempty"`
-	WorkspaceSymbolProvider bool `json:"workspaceSymbolProvider,omitempty"`
-	DefinitionProvider      bool `json:"definitionProvider,omitempty"`
-	ReferencesProvider      bool `json:"referencesProvider,omitempty"`
-	HoverProvider           bool `json:"hoverProvider,omitempty"`
-	CompletionProvider      any  `json:"completionProvider,omitempty"`
-	RenameProvider          bool `json:"renameProvider,omitempty"`
-	DiagnosticProvider      any  `json:"diagnosticProvider,omitempty"`
+	PositionEncoding          string `json:"positionEncoding,omitempty"`
+	TextDocumentSync          int    `json:"textDocumentSync,omitempty"`
+	DocumentSymbolProvider    bool   `json:"documentSymbolProvider,omitempty"`
+	WorkspaceSymbolProvider   bool   `json:"workspaceSymbolProvider,omitempty"`
+	DefinitionProvider        bool   `json:"definitionProvider,omitempty"`
+	ReferencesProvider        bool   `json:"referencesProvider,omitempty"`
+	HoverProvider             bool   `json:"hoverProvider,omitempty"`
+	CompletionProvider        any    `json:"completionProvider,omitempty"`
+	RenameProvider            bool   `json:"renameProvider,omitempty"`
+	DiagnosticProvider        any    `json:"diagnosticProvider,omitempty"`
+	CodeActionProvider        any    `json:"codeActionProvider,omitempty"`
+	InlayHintProvider         bool   `json:"inlayHintProvider,omitempty"`
+	DocumentHighlightProvider bool   `json:"documentHighlightProvider,omitempty"`
+	SelectionRangeProvider    bool   `json:"selectionRangeProvider,omitempty"`
+	FoldingRangeProvider      bool   `json:"foldingRangeProvider,omitempty"`
 }
 
 // Text document types
@@ -64,7 +81,7 @@ type TextDocumentItem struct {
 
 type Position struct {
 	Line      int `json:"line"`      // 0-based
-	Character int `json:"character"` // 0-based
+	Character int `json:"character"` // 0-based, in the negotiated position encoding
 }
 
 type Range struct {
@@ -134,6 +151,63 @@ type WorkspaceEdit struct {
 	Changes map[string][]TextEdit `json:"changes,omitempty"`
 }
 
+type CodeActionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Context      CodeActionContext      `json:"context"`
+}
+
+type CodeActionContext struct {
+	Only []string `json:"only,omitempty"`
+}
+
+type CodeActionOptions struct {
+	CodeActionKinds []string `json:"codeActionKinds,omitempty"`
+}
+
+type CodeAction struct {
+	Title string         `json:"title"`
+	Kind  string         `json:"kind,omitempty"`
+	Edit  *WorkspaceEdit `json:"edit,omitempty"`
+}
+
+type InlayHintParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+type InlayHint struct {
+	Position     Position `json:"position"`
+	Label        string   `json:"label"`
+	Kind         int      `json:"kind,omitempty"` // 1=Type, 2=Parameter
+	PaddingLeft  bool     `json:"paddingLeft,omitempty"`
+	PaddingRight bool     `json:"paddingRight,omitempty"`
+}
+
+type DocumentHighlight struct {
+	Range Range `json:"range"`
+	Kind  int   `json:"kind,omitempty"` // 1=Text, 2=Read, 3=Write
+}
+
+type SelectionRangeParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Positions    []Position             `json:"positions"`
+}
+
+// SelectionRange is a range to select, and the next larger one enclosing it.
+type SelectionRange struct {
+	Range  Range           `json:"range"`
+	Parent *SelectionRange `json:"parent,omitempty"`
+}
+
+// FoldingRange is a range of whole lines the client may fold, leaving the
+// start line visible.
+type FoldingRange struct {
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	Kind      string `json:"kind,omitempty"` // comment, imports, or region
+}
+
 type RenameParams struct {
 	TextDocument TextDocumentIdentifier `json:"textDocument"`
 	Position     Position               `json:"position"`
@@ -0,0 +1,55 @@
+package lsp
+
+import (
+	"encoding/json"
+)
+
+// handleSelectionRange answers, for each requested position, the chain of
+// syntax nodes enclosing it, from the token at the position out to the
+// whole file, so editors can expand a selection one node at a time. Nodes
+// spanning the same text as their child are skipped.
+func (s *Service) handleSelectionRange(params json.RawMessage) (any, error) {
+	var p SelectionRangeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	result := make([]SelectionRange, 0, len(p.Positions))
+	doc, ok := s.parseDocument(p.TextDocument.URI)
+	if !ok {
+		// Every position needs a range; without a tree it is empty.
+		for _, pos := range p.Positions {
+			result = append(result, SelectionRange{Range: Range{Start: pos, End: pos}})
+		}
+		return result, nil
+	}
+	defer doc.release()
+
+	root := doc.tree.RootNode()
+	for _, pos := range p.Positions {
+		offset := doc.offset(pos)
+		// At the end of a word, select the word rather than what follows.
+		if offset > 0 && isWordByte(doc.source[offset-1]) && (offset == len(doc.source) || !isWordByte(doc.source[offset])) {
+			offset--
+		}
+		var spans [][2]uint32
+		for node := root.DescendantForByteRange(uint32(offset), uint32(offset)); node != nil; node = node.Parent() {
+			span := [2]uint32{node.StartByte(), node.EndByte()}
+			if n := len(spans); n > 0 && spans[n-1] == span {
+				continue
+			}
+			spans = append(spans, span)
+		}
+		var selection *SelectionRange
+		for i := len(spans) - 1; i >= 0; i-- {
+			selection = &SelectionRange{
+				Range:  Range{Start: doc.position(int(spans[i][0])), End: doc.position(int(spans[i][1]))},
+				Parent: selection,
+			}
+		}
+		if selection == nil {
+			selection = &SelectionRange{Range: Range{Start: pos, End: pos}}
+		}
+		result = append(result, *selection)
+	}
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServiceSelectionRange(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n\tprintln(1 + total)\n}\n"), 0644)
+	uri := "file://" + dir + "/main.go"
+
+	input := lspRequest(1, "initialize", map[string]any{"rootUri": "file://" + dir})
+	input += lspRequest(2, "textDocument/selectionRange", map[string]any{
+		"textDocument": map[string]string{"uri": uri},
+		"positions": []map[string]int{
+			{"line": 3, "character": 14}, // inside total
+			{"line": 3, "character": 18}, // just past total
+		},
+	})
+	input += lspRequest(3, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	var ranges []SelectionRange
+	for _, msg := range splitLSPMessages(t, out.Bytes()) {
+		if string(msg.ID) == "2" {
+			if err := json.Unmarshal(msg.Result, &ranges); err != nil {
+				t.Fatalf("decode selection ranges: %v", err)
+			}
+		}
+	}
+	if len(ranges) != 2 {
+		t.Fatalf("got %d selection ranges, want 2", len(ranges))
+	}
+	for i, selection := range ranges {
+		var chain []string
+		for r := &selection; r != nil; r = r.Parent {
+			chain = append(chain, fmt.Sprintf("%d:%d-%d:%d", r.Range.Start.Line, r.Range.Start.Character, r.Range.End.Line, r.Range.End.Character))
+		}
+		// total, 1 + total, (1 + total), println(1 + total), the statements,
+		// the block, the function, the file.
+		want := []string{"3:13-3:18", "3:9-3:18", "3:8-3:19", "3:1-3:19", "3:1-4:0", "2:12-4:1", "2:0-4:1", "0:0-5:0"}
+		if strings.Join(chain, " ") != strings.Join(want, " ") {
+			t.Errorf("position %d: chain = %v, want %v", i, chain, want)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/odvcencio/gts-suite/internal/lint"
 	"github.com/odvcencio/gts-suite/pkg/feeds"
 	feedcompiler "github.com/odvcencio/gts-suite/pkg/feeds/compiler"
 	feedparser "github.com/odvcencio/gts-suite/pkg/feeds/parser"
@@ -18,36 +19,66 @@ import (
 	"github.com/odvcencio/gts-suite/pkg/index"
 	"github.com/odvcencio/gts-suite/pkg/model"
 	"github.com/odvcencio/gts-suite/pkg/proxy"
+	"github.com/odvcencio/gts-suite/pkg/sandbox"
 	"github.com/odvcencio/gts-suite/pkg/scope"
 	"github.com/odvcencio/gts-suite/pkg/socket"
+	"github.com/odvcencio/gts-suite/pkg/textpos"
+	"github.com/odvcencio/gts-suite/pkg/xref"
 )
 
 // Service holds workspace state and handles LSP requests.
 type Service struct {
-	mu               sync.RWMutex
+	mu               sync.RWMutex // guards scopeGraph
 	rootURI          string
 	rootPath         string
-	idx              *model.Index
+	store            *index.Store
 	builder          *index.Builder
 	scopeGraph       *scope.Graph
 	feedEngine       *feeds.Engine
 	proxyMgr         *proxy.Manager
 	socketSrv        *socket.Server
 	feedsInitialized bool
+	roots            *sandbox.Roots
+	encoding         textpos.Encoding // negotiated at initialize
+
+	// The fields below are only used from the message loop.
+	client    *Server
+	settings  Settings
+	lintRules []lint.Rule       // compiled settings.LintRules
+	diagnosed map[string]bool   // URIs with published lint diagnostics
+	documents map[string][]byte // text of open documents by URI
+	xrefIndex *model.Index      // the index xrefGraph was built from
+	xrefGraph *xref.Graph
+	xrefInc   *xref.Incremental // updated per changed file as the index moves on
+}
+
+// ServiceOptions configures optional Service behavior.
+type ServiceOptions struct {
+	// AllowedRoots restricts the workspace root accepted at initialize to
+	// these directories after symlink resolution. Empty allows any root.
+	AllowedRoots []string
 }
 
 func NewService(proxyMgr *proxy.Manager) *Service {
+	return NewServiceWithOptions(proxyMgr, ServiceOptions{})
+}
+
+// NewServiceWithOptions creates a Service with the given options.
+func NewServiceWithOptions(proxyMgr *proxy.Manager, opts ServiceOptions) *Service {
 	engine := feeds.NewEngine(slog.Default())
 	engine.Register(feedparser.New())
 	return &Service{
+		store:      index.NewStore(nil),
 		builder:    index.NewBuilder(),
 		feedEngine: engine,
 		proxyMgr:   proxyMgr,
+		roots:      sandbox.New(opts.AllowedRoots),
 	}
 }
 
 // Register wires all LSP handlers onto a Server.
 func (s *Service) Register(srv *Server) {
+	s.client = srv
 	srv.Handle("initialize", s.handleInitialize)
 	srv.Handle("shutdown", s.handleShutdown)
 	srv.Handle("textDocument/documentSymbol", s.handleDocumentSymbol)
@@ -56,6 +87,11 @@ func (s *Service) Register(srv *Server) {
 	srv.Handle("textDocument/references", s.handleReferences)
 	srv.Handle("textDocument/hover", s.handleHover)
 	srv.Handle("textDocument/rename", s.handleRename)
+	srv.Handle("textDocument/codeAction", s.handleCodeAction)
+	srv.Handle("textDocument/inlayHint", s.handleInlayHint)
+	srv.Handle("textDocument/documentHighlight", s.handleDocumentHighlight)
+	srv.Handle("textDocument/selectionRange", s.handleSelectionRange)
+	srv.Handle("textDocument/foldingRange", s.handleFoldingRange)
 
 	srv.OnNotify("initialized", func(params json.RawMessage) {
 		s.buildIndex()
@@ -64,6 +100,7 @@ func (s *Service) Register(srv *Server) {
 	srv.OnNotify("textDocument/didSave", s.handleDidSave)
 	srv.OnNotify("textDocument/didChange", s.handleDidChange)
 	srv.OnNotify("textDocument/didClose", s.handleDidClose)
+	srv.OnNotify("workspace/didChangeConfiguration", s.handleDidChangeConfiguration)
 	srv.OnNotify("exit", func(params json.RawMessage) {})
 }
 
@@ -72,21 +109,39 @@ func (s *Service) handleInitialize(params json.RawMessage) (any, error) {
 	if err := json.Unmarshal(params, &p); err != nil {
 		return nil, err
 	}
-	s.rootURI = p.RootURI
-	s.rootPath = uriToPath(p.RootURI)
-	if s.rootPath == "" {
-		s.rootPath = p.RootPath
+	rootPath := uriToPath(p.RootURI)
+	if rootPath == "" {
+		rootPath = p.RootPath
+	}
+	if rootPath != "" {
+		if err := s.roots.Check(rootPath); err != nil {
+			return nil, fmt.Errorf("workspace root: %w", err)
+		}
+	}
+	settings, err := parseSettings(p.InitializationOptions)
+	if err != nil {
+		return nil, fmt.Errorf("initializationOptions: %w", err)
 	}
+	s.rootURI = p.RootURI
+	s.rootPath = rootPath
+	s.applySettings(settings)
+	s.encoding = negotiateEncoding(p.Capabilities.General.PositionEncodings, s.proxyMgr != nil)
 
 	return InitializeResult{
 		Capabilities: ServerCapabilities{
-			TextDocumentSync:        SyncFull,
-			DocumentSymbolProvider:  true,
-			WorkspaceSymbolProvider: true,
-			DefinitionProvider:      true,
-			ReferencesProvider:      true,
-			HoverProvider:           true,
-			RenameProvider:          true,
+			PositionEncoding:          string(s.encoding),
+			TextDocumentSync:          SyncFull,
+			DocumentSymbolProvider:    true,
+			WorkspaceSymbolProvider:   true,
+			DefinitionProvider:        true,
+			ReferencesProvider:        true,
+			HoverProvider:             true,
+			RenameProvider:            true,
+			CodeActionProvider:        CodeActionOptions{CodeActionKinds: codeActionKinds},
+			InlayHintProvider:         true,
+			DocumentHighlightProvider: true,
+			SelectionRangeProvider:    true,
+			FoldingRangeProvider:      true,
 		},
 		ServerInfo: &ServerInfo{Name: "gtsls", Version: "0.1.0"},
 	}, nil
@@ -110,6 +165,9 @@ func (s *Service) proxyRequest(method string, params json.RawMessage, fileURI st
 		return nil, false
 	}
 	file := uriToPath(fileURI)
+	if s.roots.Check(file) != nil {
+		return nil, false
+	}
 	b := s.proxyMgr.BackendForFile(file)
 	if b == nil {
 		return nil, false
@@ -146,11 +204,57 @@ func (s *Service) buildIndex() {
 		s.socketSrv = s.StartSocket()
 	}
 
-	idx, err := s.builder.BuildPath(s.rootPath)
+	s.rebuild(func(*model.Index) (*model.Index, error) {
+		if idx, ok := s.buildFromCache(); ok {
+			return idx, nil
+		}
+		return s.builder.BuildPath(s.rootPath)
+	})
+}
+
+// rebuild publishes the index build derives from the current one, together
+// with its scope graph, then saves it to the configured cache and publishes
+// its lint diagnostics. The store serializes rebuilds, so a slow build cannot
+// replace the result of a later one.
+func (s *Service) rebuild(build func(prev *model.Index) (*model.Index, error)) {
+	idx, err := s.store.Update(func(prev *model.Index) (*model.Index, error) {
+		idx, err := build(prev)
+		if err != nil {
+			return nil, err
+		}
+		s.buildScopeGraph(idx)
+		return idx, nil
+	})
 	if err != nil {
 		return
 	}
+	s.saveCache(idx)
+	s.publishLintDiagnostics(idx)
+}
 
+// reindexFile re-parses the file at path alone and publishes the index with
+// it replaced, or removed if it is gone, then finishes like rebuild. Before
+// the first build there is nothing to patch, so it rebuilds instead.
+func (s *Service) reindexFile(path string) {
+	if s.store.Snapshot() == nil {
+		s.rebuild(func(prev *model.Index) (*model.Index, error) {
+			idx, _, err := s.builder.BuildPathIncremental(context.Background(), s.rootPath, prev)
+			return idx, err
+		})
+		return
+	}
+	idx, _, err := s.store.ApplyWatchChanges(s.builder, []string{path}, nil, index.WatchUpdateOptions{})
+	if err != nil {
+		return
+	}
+	s.buildScopeGraph(idx)
+	s.saveCache(idx)
+	s.publishLintDiagnostics(idx)
+}
+
+// buildScopeGraph runs the feeds over the files of idx and publishes the
+// resulting scope graph.
+func (s *Service) buildScopeGraph(idx *model.Index) {
 	graph := scope.NewGraph()
 	ctx := &feeds.FeedContext{
 		WorkspaceRoot: s.rootPath,
@@ -169,7 +273,6 @@ func (s *Service) buildIndex() {
 	}
 
 	s.mu.Lock()
-	s.idx = idx
 	s.scopeGraph = graph
 	s.mu.Unlock()
 }
@@ -214,13 +317,12 @@ func (s *Service) StartSocket() *socket.Server {
 		if err := json.Unmarshal(params, &p); err != nil || p.Symbol == "" {
 			return nil, fmt.Errorf("symbol required")
 		}
-		s.mu.RLock()
-		defer s.mu.RUnlock()
-		if s.idx == nil {
+		idx := s.store.Snapshot()
+		if idx == nil {
 			return []any{}, nil
 		}
 		var refs []map[string]any
-		for _, f := range s.idx.Files {
+		for _, f := range idx.Files {
 			for _, ref := range f.References {
 				if ref.Name == p.Symbol {
 					refs = append(refs, map[string]any{
@@ -276,13 +378,12 @@ func (s *Service) StartSocket() *socket.Server {
 		if err := json.Unmarshal(params, &p); err != nil || p.Symbol == "" {
 			return nil, fmt.Errorf("symbol required")
 		}
-		s.mu.RLock()
-		defer s.mu.RUnlock()
-		if s.idx == nil {
+		idx := s.store.Snapshot()
+		if idx == nil {
 			return map[string]any{"symbol": p.Symbol, "affected": []any{}}, nil
 		}
 		var affected []map[string]any
-		for _, f := range s.idx.Files {
+		for _, f := range idx.Files {
 			for _, ref := range f.References {
 				if ref.Name == p.Symbol {
 					affected = append(affected, map[string]any{
@@ -314,16 +415,14 @@ func (s *Service) handleDocumentSymbol(params json.RawMessage) (any, error) {
 	path := uriToPath(p.TextDocument.URI)
 	relPath := relativeTo(path, s.rootPath)
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if s.idx == nil {
+	idx := s.store.Snapshot()
+	if idx == nil {
 		return []DocumentSymbol{}, nil
 	}
 
-	for _, f := range s.idx.Files {
+	for _, f := range idx.Files {
 		if f.Path == relPath {
-			return symbolsToDocumentSymbols(f.Symbols), nil
+			return s.positions().documentSymbols(f.Path, f.Symbols), nil
 		}
 	}
 	return []DocumentSymbol{}, nil
@@ -337,16 +436,15 @@ func (s *Service) handleWorkspaceSymbol(params json.RawMessage) (any, error) {
 		return nil, err
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if s.idx == nil {
+	idx := s.store.Snapshot()
+	if idx == nil {
 		return []SymbolInformation{}, nil
 	}
 
 	query := strings.ToLower(p.Query)
+	pos := s.positions()
 	var results []SymbolInformation
-	for _, f := range s.idx.Files {
+	for _, f := range idx.Files {
 		for _, sym := range f.Symbols {
 			if query == "" || strings.Contains(strings.ToLower(sym.Name), query) {
 				results = append(results, SymbolInformation{
@@ -354,7 +452,7 @@ func (s *Service) handleWorkspaceSymbol(params json.RawMessage) (any, error) {
 					Kind: symbolKindFromModel(sym.Kind),
 					Location: LSPLocation{
 						URI:   pathToURI(f.Path, s.rootPath),
-						Range: symbolRange(sym),
+						Range: pos.symbolRange(f.Path, sym),
 					},
 				})
 			}
@@ -364,15 +462,17 @@ func (s *Service) handleWorkspaceSymbol(params json.RawMessage) (any, error) {
 }
 
 func (s *Service) handleDidOpen(params json.RawMessage) {
-	if s.proxyMgr == nil {
+	var p struct {
+		TextDocument TextDocumentItem `json:"textDocument"`
+	}
+	if json.Unmarshal(params, &p) != nil {
 		return
 	}
-	var p struct {
-		TextDocument struct {
-			URI string `json:"uri"`
-		} `json:"textDocument"`
+	if s.documents == nil {
+		s.documents = map[string][]byte{}
 	}
-	if json.Unmarshal(params, &p) == nil {
+	s.documents[p.TextDocument.URI] = []byte(p.TextDocument.Text)
+	if s.proxyMgr != nil {
 		file := uriToPath(p.TextDocument.URI)
 		if b := s.proxyMgr.BackendForFile(file); b != nil {
 			b.Notify("textDocument/didOpen", params)
@@ -381,67 +481,44 @@ func (s *Service) handleDidOpen(params json.RawMessage) {
 }
 
 func (s *Service) handleDidSave(params json.RawMessage) {
-	// Forward to backend
-	if s.proxyMgr != nil {
-		var p struct {
-			TextDocument struct {
-				URI string `json:"uri"`
-			} `json:"textDocument"`
-		}
-		if json.Unmarshal(params, &p) == nil {
-			file := uriToPath(p.TextDocument.URI)
-			if b := s.proxyMgr.BackendForFile(file); b != nil {
-				b.Notify("textDocument/didSave", params)
-			}
-		}
-	}
-
-	// Existing index rebuild code follows...
-	if s.rootPath == "" {
-		return
+	var p struct {
+		TextDocument struct {
+			URI string `json:"uri"`
+		} `json:"textDocument"`
 	}
-	s.mu.RLock()
-	prev := s.idx
-	s.mu.RUnlock()
-
-	newIdx, _, err := s.builder.BuildPathIncremental(context.Background(), s.rootPath, prev)
-	if err != nil {
+	if json.Unmarshal(params, &p) != nil {
 		return
 	}
-
-	graph := scope.NewGraph()
-	ctx := &feeds.FeedContext{
-		WorkspaceRoot: s.rootPath,
-		Logger:        slog.Default(),
-	}
-	for _, f := range newIdx.Files {
-		src, readErr := os.ReadFile(filepath.Join(s.rootPath, f.Path))
-		if readErr != nil {
-			continue
+	file := uriToPath(p.TextDocument.URI)
+	if s.proxyMgr != nil {
+		if b := s.proxyMgr.BackendForFile(file); b != nil {
+			b.Notify("textDocument/didSave", params)
 		}
-		s.feedEngine.RunFile(graph, f.Path, src, f.Language, ctx)
 	}
 
-	for _, fs := range graph.FileScopes {
-		scope.ResolveAllGraph(fs, graph)
+	if s.rootPath == "" {
+		return
 	}
-
-	s.mu.Lock()
-	s.idx = newIdx
-	s.scopeGraph = graph
-	s.mu.Unlock()
+	s.reindexFile(file)
 }
 
 func (s *Service) handleDidChange(params json.RawMessage) {
-	if s.proxyMgr == nil {
-		return
-	}
 	var p struct {
 		TextDocument struct {
 			URI string `json:"uri"`
 		} `json:"textDocument"`
+		// With full sync, each change holds the whole document.
+		ContentChanges []struct {
+			Text string `json:"text"`
+		} `json:"contentChanges"`
+	}
+	if json.Unmarshal(params, &p) != nil {
+		return
+	}
+	if n := len(p.ContentChanges); n > 0 && s.documents != nil {
+		s.documents[p.TextDocument.URI] = []byte(p.ContentChanges[n-1].Text)
 	}
-	if json.Unmarshal(params, &p) == nil {
+	if s.proxyMgr != nil {
 		file := uriToPath(p.TextDocument.URI)
 		if b := s.proxyMgr.BackendForFile(file); b != nil {
 			b.Notify("textDocument/didChange", params)
@@ -450,15 +527,16 @@ func (s *Service) handleDidChange(params json.RawMessage) {
 }
 
 func (s *Service) handleDidClose(params json.RawMessage) {
-	if s.proxyMgr == nil {
-		return
-	}
 	var p struct {
 		TextDocument struct {
 			URI string `json:"uri"`
 		} `json:"textDocument"`
 	}
-	if json.Unmarshal(params, &p) == nil {
+	if json.Unmarshal(params, &p) != nil {
+		return
+	}
+	delete(s.documents, p.TextDocument.URI)
+	if s.proxyMgr != nil {
 		file := uriToPath(p.TextDocument.URI)
 		if b := s.proxyMgr.BackendForFile(file); b != nil {
 			b.Notify("textDocument/didClose", params)
@@ -466,6 +544,20 @@ func (s *Service) handleDidClose(params json.RawMessage) {
 	}
 }
 
+// documentSource returns the text of the document at uri: the client's,
+// when it has the document open, else the file on disk, which must lie
+// inside the allowed roots.
+func (s *Service) documentSource(uri string) ([]byte, error) {
+	if source, ok := s.documents[uri]; ok {
+		return source, nil
+	}
+	path := uriToPath(uri)
+	if err := s.roots.Check(path); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
 func (s *Service) handleDefinition(params json.RawMessage) (any, error) {
 	var p struct {
 		TextDocument TextDocumentIdentifier `json:"textDocument"`
@@ -484,26 +576,26 @@ func (s *Service) handleDefinition(params json.RawMessage) (any, error) {
 	relPath := relativeTo(path, s.rootPath)
 	line := p.Position.Line + 1 // LSP is 0-based, model is 1-based
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if s.idx == nil {
+	idx := s.store.Snapshot()
+	if idx == nil {
 		return nil, nil
 	}
+	pos := s.positions()
 
 	// Try scope graph resolution first
-	if s.scopeGraph != nil {
-		fs := s.scopeGraph.FileScope(relPath)
+	s.mu.RLock()
+	graph := s.scopeGraph
+	s.mu.RUnlock()
+	if graph != nil {
+		fs := graph.FileScope(relPath)
 		if fs != nil {
 			for i := range fs.Refs {
 				ref := &fs.Refs[i]
 				if ref.Loc.StartLine == line && ref.Resolved != nil {
+					loc := ref.Resolved.Loc
 					return LSPLocation{
-						URI: pathToURI(ref.Resolved.Loc.File, s.rootPath),
-						Range: Range{
-							Start: Position{Line: ref.Resolved.Loc.StartLine - 1, Character: ref.Resolved.Loc.StartCol},
-							End:   Position{Line: ref.Resolved.Loc.EndLine - 1, Character: ref.Resolved.Loc.EndCol},
-						},
+						URI:   pathToURI(loc.File, s.rootPath),
+						Range: pos.span(loc.File, loc.StartLine, loc.StartCol, loc.EndLine, loc.EndCol),
 					}, nil
 				}
 			}
@@ -511,18 +603,18 @@ func (s *Service) handleDefinition(params json.RawMessage) (any, error) {
 	}
 
 	// Fall back to name-based resolution
-	symbolName := s.symbolNameAtPosition(relPath, line, p.Position.Character)
+	symbolName := symbolNameAtPosition(idx, relPath, line, pos.byteColumn(relPath, p.Position))
 	if symbolName == "" {
 		return nil, nil
 	}
 
 	// Search for matching definition across the index
-	for _, f := range s.idx.Files {
+	for _, f := range idx.Files {
 		for _, sym := range f.Symbols {
 			if sym.Name == symbolName {
 				return LSPLocation{
 					URI:   pathToURI(f.Path, s.rootPath),
-					Range: symbolRange(sym),
+					Range: pos.symbolRange(f.Path, sym),
 				}, nil
 			}
 		}
@@ -548,28 +640,24 @@ func (s *Service) handleReferences(params json.RawMessage) (any, error) {
 	relPath := relativeTo(path, s.rootPath)
 	line := p.Position.Line + 1
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if s.idx == nil {
+	idx := s.store.Snapshot()
+	if idx == nil {
 		return []LSPLocation{}, nil
 	}
+	pos := s.positions()
 
-	symbolName := s.symbolNameAtPosition(relPath, line, p.Position.Character)
+	symbolName := symbolNameAtPosition(idx, relPath, line, pos.byteColumn(relPath, p.Position))
 	if symbolName == "" {
 		return []LSPLocation{}, nil
 	}
 
 	var locs []LSPLocation
-	for _, f := range s.idx.Files {
+	for _, f := range idx.Files {
 		for _, ref := range f.References {
 			if ref.Name == symbolName {
 				locs = append(locs, LSPLocation{
-					URI: pathToURI(f.Path, s.rootPath),
-					Range: Range{
-						Start: Position{Line: ref.StartLine - 1, Character: ref.StartColumn},
-						End:   Position{Line: ref.EndLine - 1, Character: ref.EndColumn},
-					},
+					URI:   pathToURI(f.Path, s.rootPath),
+					Range: pos.referenceRange(f.Path, ref),
 				})
 			}
 		}
@@ -612,37 +700,6 @@ func (s *Service) handleHover(params json.RawMessage) (any, error) {
 	return nil, nil
 }
 
-func (s *Service) nativeHover(uri string, pos Position) *Hover {
-	path := uriToPath(uri)
-	relPath := relativeTo(path, s.rootPath)
-	line := pos.Line + 1
-
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if s.idx == nil {
-		return nil
-	}
-
-	for _, f := range s.idx.Files {
-		if f.Path != relPath {
-			continue
-		}
-		for _, sym := range f.Symbols {
-			if sym.StartLine <= line && line <= sym.EndLine {
-				content := sym.Kind + " " + sym.Name
-				if sym.Signature != "" {
-					content = sym.Name + sym.Signature
-				}
-				return &Hover{
-					Contents: MarkupContent{Kind: "markdown", Value: "```" + f.Language + "\n" + content + "\n```"},
-				}
-			}
-		}
-	}
-	return nil
-}
-
 func (s *Service) handleRename(params json.RawMessage) (any, error) {
 	var p RenameParams
 	if err := json.Unmarshal(params, &p); err != nil {
@@ -658,26 +715,25 @@ func (s *Service) handleRename(params json.RawMessage) (any, error) {
 	relPath := relativeTo(path, s.rootPath)
 	line := p.Position.Line + 1
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if s.idx == nil {
+	idx := s.store.Snapshot()
+	if idx == nil {
 		return nil, fmt.Errorf("index not ready")
 	}
+	pos := s.positions()
 
-	symbolName := s.symbolNameAtPosition(relPath, line, p.Position.Character)
+	symbolName := symbolNameAtPosition(idx, relPath, line, pos.byteColumn(relPath, p.Position))
 	if symbolName == "" {
 		return nil, fmt.Errorf("no symbol at position")
 	}
 
 	// Collect all edits: definitions + references
 	changes := make(map[string][]TextEdit)
-	for _, f := range s.idx.Files {
+	for _, f := range idx.Files {
 		uri := pathToURI(f.Path, s.rootPath)
 		for _, sym := range f.Symbols {
 			if sym.Name == symbolName {
 				changes[uri] = append(changes[uri], TextEdit{
-					Range:   symbolNameRange(sym),
+					Range:   pos.symbolNameRange(f.Path, sym),
 					NewText: p.NewName,
 				})
 			}
@@ -685,10 +741,7 @@ func (s *Service) handleRename(params json.RawMessage) (any, error) {
 		for _, ref := range f.References {
 			if ref.Name == symbolName {
 				changes[uri] = append(changes[uri], TextEdit{
-					Range: Range{
-						Start: Position{Line: ref.StartLine - 1, Character: ref.StartColumn},
-						End:   Position{Line: ref.EndLine - 1, Character: ref.EndColumn},
-					},
+					Range:   pos.referenceRange(f.Path, ref),
 					NewText: p.NewName,
 				})
 			}
@@ -698,17 +751,10 @@ func (s *Service) handleRename(params json.RawMessage) (any, error) {
 	return WorkspaceEdit{Changes: changes}, nil
 }
 
-func symbolNameRange(sym model.Symbol) Range {
-	// Approximate: use the start line, first column
-	return Range{
-		Start: Position{Line: sym.StartLine - 1, Character: 0},
-		End:   Position{Line: sym.StartLine - 1, Character: len(sym.Name)},
-	}
-}
-
-// symbolNameAtPosition finds the symbol/reference name at a given cursor position.
-func (s *Service) symbolNameAtPosition(relPath string, line, col int) string {
-	for _, f := range s.idx.Files {
+// symbolNameAtPosition finds the symbol/reference name at a given cursor
+// position; col is a 0-based byte column.
+func symbolNameAtPosition(idx *model.Index, relPath string, line, col int) string {
+	for _, f := range idx.Files {
 		if f.Path != relPath {
 			continue
 		}
@@ -752,34 +798,13 @@ func relativeTo(abs, root string) string {
 	return abs
 }
 
-func symbolRange(sym model.Symbol) Range {
-	return Range{
-		Start: Position{Line: sym.StartLine - 1, Character: 0},
-		End:   Position{Line: sym.EndLine - 1, Character: 0},
-	}
-}
-
-func symbolsToDocumentSymbols(syms []model.Symbol) []DocumentSymbol {
-	result := make([]DocumentSymbol, 0, len(syms))
-	for _, sym := range syms {
-		r := symbolRange(sym)
-		result = append(result, DocumentSymbol{
-			Name:           sym.Name,
-			Kind:           symbolKindFromModel(sym.Kind),
-			Range:          r,
-			SelectionRange: r,
-		})
-	}
-	return result
-}
-
 func symbolKindFromModel(kind string) int {
 	switch kind {
-	case "function_definition":
+	case "function_definition", "hook_definition":
 		return SKFunction
 	case "method_definition":
 		return SKMethod
-	case "class_definition":
+	case "class_definition", "component_definition":
 		return SKClass
 	case "interface_definition":
 		return SKInterface
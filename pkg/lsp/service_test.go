@@ -48,6 +48,53 @@ func TestServiceInitialize(t *testing.T) {
 	}
 }
 
+func TestServiceInitializeRejectsRootOutsideAllowlist(t *testing.T) {
+	allowed := t.TempDir()
+	other := t.TempDir()
+
+	input := lspRequest(1, "initialize", map[string]string{
+		"rootUri": "file://" + other,
+	})
+	input += lspRequest(2, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewServiceWithOptions(nil, ServiceOptions{AllowedRoots: []string{allowed}})
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	resp := out.String()
+	if !strings.Contains(resp, "outside the allowed roots") {
+		t.Errorf("expected initialize to be rejected, got: %s", resp)
+	}
+	if svc.rootPath != "" {
+		t.Errorf("expected rootPath to stay empty, got %q", svc.rootPath)
+	}
+}
+
+func TestServiceDocumentSourceRejectsFilesOutsideAllowlist(t *testing.T) {
+	allowed := t.TempDir()
+	other := t.TempDir()
+	inside := filepath.Join(allowed, "main.go")
+	outside := filepath.Join(other, "secret.go")
+	for _, path := range []string{inside, outside} {
+		if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	svc := NewServiceWithOptions(nil, ServiceOptions{AllowedRoots: []string{allowed}})
+	if _, err := svc.documentSource("file://" + inside); err != nil {
+		t.Fatalf("documentSource inside the allowed roots returned error: %v", err)
+	}
+	if _, err := svc.documentSource("file://" + outside); err == nil || !strings.Contains(err.Error(), "outside the allowed roots") {
+		t.Fatalf("expected documentSource to reject %s, got %v", outside, err)
+	}
+	if _, ok := svc.parseDocument("file://" + outside); ok {
+		t.Fatal("expected parseDocument to reject a file outside the allowed roots")
+	}
+}
+
 func TestServiceGoToDefinition(t *testing.T) {
 	dir := t.TempDir()
 	os.WriteFile(filepath.Join(dir, "main.go"), []byte(
@@ -74,6 +121,88 @@ func TestServiceGoToDefinition(t *testing.T) {
 	}
 }
 
+func TestServiceDidSaveReindexesSavedFile(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "main.go")
+	os.WriteFile(mainPath, []byte("package main\n\nfunc hello() {}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "util.go"), []byte("package main\n\nfunc util() {}\n"), 0644)
+
+	input := lspRequest(1, "initialize", map[string]string{"rootUri": "file://" + dir})
+	input += lspNotify("initialized", struct{}{})
+	input += lspRequest(2, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	before := svc.store.Snapshot()
+	if before == nil || before.SymbolCount() != 2 {
+		t.Fatalf("expected the initial index to hold 2 symbols, got %+v", before)
+	}
+
+	os.WriteFile(mainPath, []byte("package main\n\nfunc hello() {}\n\nfunc goodbye() {}\n"), 0644)
+	params, _ := json.Marshal(map[string]any{"textDocument": map[string]string{"uri": "file://" + mainPath}})
+	svc.handleDidSave(params)
+
+	after := svc.store.Snapshot()
+	if after.SymbolCount() != 3 {
+		t.Fatalf("expected the saved file to be re-indexed, got %d symbols", after.SymbolCount())
+	}
+	// Only main.go was re-parsed; util.go's summary is carried over.
+	if &after.Files[1].Symbols[0] != &before.Files[1].Symbols[0] {
+		t.Fatal("expected the unchanged util.go summary to be reused")
+	}
+}
+
+func TestServiceCallGraphFollowsSavedFile(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "main.go")
+	os.WriteFile(mainPath, []byte("package main\n\nfunc main() { util() }\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "util.go"), []byte("package main\n\nfunc util() {}\n"), 0644)
+
+	input := lspRequest(1, "initialize", map[string]string{"rootUri": "file://" + dir})
+	input += lspNotify("initialized", struct{}{})
+	input += lspRequest(2, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	callsToUtil := func() int {
+		t.Helper()
+		graph := svc.callGraph(svc.store.Snapshot())
+		if graph == nil {
+			t.Fatal("expected a call graph")
+		}
+		for _, def := range graph.Definitions {
+			if def.Name == "util" {
+				return graph.IncomingCount(def.ID)
+			}
+		}
+		t.Fatal("util is not in the call graph")
+		return 0
+	}
+	if got := callsToUtil(); got != 1 {
+		t.Fatalf("expected 1 call to util, got %d", got)
+	}
+	inc := svc.xrefInc
+
+	os.WriteFile(mainPath, []byte("package main\n\nfunc main() { util(); other() }\n\nfunc other() { util() }\n"), 0644)
+	params, _ := json.Marshal(map[string]any{"textDocument": map[string]string{"uri": "file://" + mainPath}})
+	svc.handleDidSave(params)
+
+	if got := callsToUtil(); got != 2 {
+		t.Fatalf("expected the saved calls to util to be counted, got %d", got)
+	}
+	if svc.xrefInc != inc {
+		t.Fatal("expected the call graph to be updated rather than rebuilt")
+	}
+}
+
 func TestServiceDocumentSymbols(t *testing.T) {
 	dir := t.TempDir()
 	goFile := filepath.Join(dir, "main.go")
@@ -105,6 +234,34 @@ func TestServiceDocumentSymbols(t *testing.T) {
 	}
 }
 
+func TestServiceDocumentSymbolsNested(t *testing.T) {
+	dir := t.TempDir()
+	goFile := filepath.Join(dir, "main.go")
+	os.WriteFile(goFile, []byte("package main\n\ntype Server struct{}\n\nfunc (s *Server) Start() {}\n"), 0644)
+
+	input := lspRequest(1, "initialize", map[string]string{
+		"rootUri": "file://" + dir,
+	})
+	input += lspNotify("initialized", struct{}{})
+	input += lspRequest(2, "textDocument/documentSymbol", map[string]any{
+		"textDocument": map[string]string{
+			"uri": "file://" + goFile,
+		},
+	})
+	input += lspRequest(3, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	resp := out.String()
+	if !strings.Contains(resp, `"children":[{"name":"Start"`) {
+		t.Errorf("expected Start nested under Server, got: %s", resp)
+	}
+}
+
 func TestServiceHover(t *testing.T) {
 	dir := t.TempDir()
 	os.WriteFile(filepath.Join(dir, "main.go"), []byte(
@@ -130,3 +287,153 @@ func TestServiceHover(t *testing.T) {
 		t.Errorf("expected hover with 'hello', got: %s", resp)
 	}
 }
+
+func TestServiceHoverBreadcrumbs(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "app.py"), []byte(
+		"class Server:\n    def start(self):\n        return 1\n",
+	), 0644)
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte(
+		"package main\n\nfunc (s *Server) Stop() {\n\treturn\n}\n",
+	), 0644)
+
+	input := lspRequest(1, "initialize", map[string]string{"rootUri": "file://" + dir})
+	input += lspNotify("initialized", struct{}{})
+	input += lspRequest(2, "textDocument/hover", map[string]any{
+		"textDocument": map[string]string{"uri": "file://" + filepath.Join(dir, "app.py")},
+		"position":     map[string]int{"line": 2, "character": 8},
+	})
+	input += lspRequest(3, "textDocument/hover", map[string]any{
+		"textDocument": map[string]string{"uri": "file://" + filepath.Join(dir, "main.go")},
+		"position":     map[string]int{"line": 3, "character": 1},
+	})
+	input += lspRequest(4, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	want := map[string]string{
+		"2": "app.py › Server › start",
+		// The receiver places a method whose type is declared elsewhere.
+		"3": "main.go › Server › Stop",
+	}
+	for _, msg := range splitLSPMessages(t, out.Bytes()) {
+		crumbs, ok := want[string(msg.ID)]
+		if !ok {
+			continue
+		}
+		var hover Hover
+		if err := json.Unmarshal(msg.Result, &hover); err != nil {
+			t.Fatalf("decode hover: %v", err)
+		}
+		if !strings.HasSuffix(hover.Contents.Value, "\n\n"+crumbs) {
+			t.Errorf("hover %s = %q, want breadcrumbs %q", msg.ID, hover.Contents.Value, crumbs)
+		}
+		delete(want, string(msg.ID))
+	}
+	if len(want) > 0 {
+		t.Errorf("missing hovers: %v", want)
+	}
+}
+
+func TestServiceHoverDescribesDefinition(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte(`package main
+
+type Greeter struct{}
+
+// Greet returns a greeting
+// for name.
+func (g *Greeter) Greet(name string) string { return helper(name) }
+
+func helper(s string) string { return s }
+
+func main() {
+	g := &Greeter{}
+	g.Greet("a")
+	g.Greet("b")
+}
+`), 0644)
+
+	input := lspRequest(1, "initialize", map[string]string{"rootUri": "file://" + dir})
+	input += lspNotify("initialized", struct{}{})
+	// On the call g.Greet("a"), inside main.
+	input += lspRequest(2, "textDocument/hover", map[string]any{
+		"textDocument": map[string]string{"uri": "file://" + filepath.Join(dir, "main.go")},
+		"position":     map[string]int{"line": 12, "character": 4},
+	})
+	input += lspRequest(3, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	for _, msg := range splitLSPMessages(t, out.Bytes()) {
+		if string(msg.ID) != "2" {
+			continue
+		}
+		var hover Hover
+		if err := json.Unmarshal(msg.Result, &hover); err != nil {
+			t.Fatalf("decode hover: %v", err)
+		}
+		for _, want := range []string{
+			"```go\nfunc (g *Greeter) Greet(name string) string\n```",
+			"\n\nGreet returns a greeting\nfor name.\n\n",
+			"Defined in `main.go:7`",
+			"Called 2 times from 1 caller · calls 1 function",
+			"main.go › Greeter › Greet",
+		} {
+			if !strings.Contains(hover.Contents.Value, want) {
+				t.Errorf("hover = %q, want it to contain %q", hover.Contents.Value, want)
+			}
+		}
+		return
+	}
+	t.Fatal("no hover response")
+}
+
+func TestServiceReferencesCountUTF16Units(t *testing.T) {
+	dir := t.TempDir()
+	goFile := filepath.Join(dir, "main.go")
+	// The call follows a 4-byte emoji, which is 2 UTF-16 code units: byte
+	// column 26, UTF-16 column 24.
+	os.WriteFile(goFile, []byte("package main\n\nfunc hello() {}\n\nfunc main() { _ = \"😀\"; hello() }\n"), 0644)
+
+	for _, tc := range []struct {
+		encodings []string
+		want      string
+	}{
+		{nil, `"range":{"start":{"line":4,"character":24},"end":{"line":4,"character":29}}`},
+		{[]string{"utf-8"}, `"range":{"start":{"line":4,"character":26},"end":{"line":4,"character":31}}`},
+	} {
+		input := lspRequest(1, "initialize", map[string]any{
+			"rootUri":      "file://" + dir,
+			"capabilities": map[string]any{"general": map[string]any{"positionEncodings": tc.encodings}},
+		})
+		input += lspNotify("initialized", struct{}{})
+		input += lspRequest(2, "textDocument/references", map[string]any{
+			"textDocument": map[string]string{"uri": "file://" + goFile},
+			"position":     map[string]int{"line": 2, "character": 5},
+		})
+		input += lspRequest(3, "shutdown", nil)
+
+		var out bytes.Buffer
+		svc := NewService(nil)
+		srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+		svc.Register(srv)
+		srv.Serve()
+
+		resp := out.String()
+		if !strings.Contains(resp, tc.want) {
+			t.Errorf("encodings %v: expected %s, got: %s", tc.encodings, tc.want, resp)
+		}
+		if tc.encodings != nil && !strings.Contains(resp, `"positionEncoding":"utf-8"`) {
+			t.Errorf("expected utf-8 to be negotiated, got: %s", resp)
+		}
+	}
+}
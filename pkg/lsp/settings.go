@@ -0,0 +1,235 @@
+package lsp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/internal/lint"
+	"github.com/odvcencio/gts-suite/pkg/ignore"
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// settingsSection is the key clients may nest gtsls settings under, as
+// editors do when they send the whole configuration.
+const settingsSection = "gtsls"
+
+// Settings are the options an editor sets through initializationOptions and
+// workspace/didChangeConfiguration, either at the top level or under a
+// "gtsls" key. Changing them takes effect without restarting the server.
+type Settings struct {
+	// Exclude lists gitignore-style patterns of paths left out of the index.
+	Exclude []string `json:"exclude,omitempty"`
+	// LintRules are lint rule expressions, as for gts analyze lint --rule,
+	// whose violations are published as diagnostics.
+	LintRules []string `json:"lintRules,omitempty"`
+	// CodeActionTokens is the token budget of context code actions gather.
+	// Zero means 800.
+	CodeActionTokens int `json:"codeActionTokens,omitempty"`
+	// Cache is an index cache file, relative to the workspace root, loaded
+	// at startup and rewritten after every rebuild.
+	Cache string `json:"cache,omitempty"`
+}
+
+// parseSettings decodes the settings in raw, an object holding them under
+// "gtsls" or directly. Empty or null input yields zero Settings.
+func parseSettings(raw json.RawMessage) (Settings, error) {
+	var settings Settings
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return settings, nil
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &sections); err != nil {
+		return settings, err
+	}
+	if nested, ok := sections[settingsSection]; ok {
+		raw = nested
+	}
+	if err := json.Unmarshal(raw, &settings); err != nil {
+		return settings, err
+	}
+	return settings, nil
+}
+
+func (s *Service) handleDidChangeConfiguration(params json.RawMessage) {
+	var p struct {
+		Settings json.RawMessage `json:"settings"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		slog.Warn("gtsls: invalid didChangeConfiguration params", "error", err)
+		return
+	}
+	settings, err := parseSettings(p.Settings)
+	if err != nil {
+		slog.Warn("gtsls: invalid settings", "error", err)
+		return
+	}
+	s.applySettings(settings)
+}
+
+// applySettings makes settings current. Once the index has been built, a
+// change to the excludes rebuilds it, a new cache path is written at once,
+// and a change to the lint rules republishes diagnostics.
+func (s *Service) applySettings(settings Settings) {
+	prev := s.settings
+	s.settings = settings
+	s.lintRules = compileLintRules(settings.LintRules)
+	excludeChanged := !slices.Equal(prev.Exclude, settings.Exclude)
+	if excludeChanged {
+		s.builder = newSettingsBuilder(settings)
+	}
+
+	idx := s.store.Snapshot()
+	if idx == nil {
+		return
+	}
+	if excludeChanged {
+		// rebuild saves the cache and publishes diagnostics itself.
+		s.rebuild(func(*model.Index) (*model.Index, error) {
+			return s.builder.BuildPath(s.rootPath)
+		})
+		return
+	}
+	if prev.Cache != settings.Cache {
+		s.saveCache(idx)
+	}
+	if !slices.Equal(prev.LintRules, settings.LintRules) {
+		s.publishLintDiagnostics(idx)
+	}
+}
+
+// newSettingsBuilder returns a Builder that leaves out the paths settings
+// excludes.
+func newSettingsBuilder(settings Settings) *index.Builder {
+	builder := index.NewBuilder()
+	if len(settings.Exclude) > 0 {
+		builder.SetIgnore(ignore.ParsePatterns(settings.Exclude))
+	}
+	return builder
+}
+
+// compileLintRules parses rule expressions, skipping and logging the ones
+// that do not parse so one typo does not silence the rest.
+func compileLintRules(raw []string) []lint.Rule {
+	var rules []lint.Rule
+	for _, expr := range raw {
+		if strings.TrimSpace(expr) == "" {
+			continue
+		}
+		rule, err := lint.ParseRule(expr)
+		if err != nil {
+			slog.Warn("gtsls: ignoring lint rule", "rule", expr, "error", err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// cachePath returns the absolute path of the configured index cache, or ""
+// when none is set.
+func (s *Service) cachePath() string {
+	cache := strings.TrimSpace(s.settings.Cache)
+	if cache == "" || s.rootPath == "" {
+		return ""
+	}
+	if !filepath.IsAbs(cache) {
+		cache = filepath.Join(s.rootPath, cache)
+	}
+	return cache
+}
+
+// buildFromCache builds the index incrementally from the configured cache,
+// reporting false when there is no usable cache.
+func (s *Service) buildFromCache() (*model.Index, bool) {
+	path := s.cachePath()
+	if path == "" {
+		return nil, false
+	}
+	cached, err := index.Load(path)
+	if err != nil {
+		slog.Info("gtsls: not using index cache", "cache", path, "error", err)
+		return nil, false
+	}
+	idx, _, err := s.builder.BuildPathIncremental(context.Background(), s.rootPath, cached)
+	if err != nil {
+		return nil, false
+	}
+	return idx, true
+}
+
+// saveCache writes idx to the configured cache, if any.
+func (s *Service) saveCache(idx *model.Index) {
+	path := s.cachePath()
+	if path == "" || idx == nil {
+		return
+	}
+	if err := index.Save(path, idx); err != nil {
+		slog.Warn("gtsls: failed to write index cache", "cache", path, "error", err)
+	}
+}
+
+// publishLintDiagnostics publishes the violations of the configured lint
+// rules in idx, and clears diagnostics of files that no longer have any.
+func (s *Service) publishLintDiagnostics(idx *model.Index) {
+	if s.client == nil || idx == nil {
+		return
+	}
+	byURI := map[string][]Diagnostic{}
+	for _, violation := range lint.Evaluate(idx, s.lintRules) {
+		uri := pathToURI(violation.File, s.rootPath)
+		// Violations carry lines only; cover them whole. Import violations
+		// have none and are shown on the first line.
+		startLine := max(violation.StartLine, 1)
+		endLine := max(violation.EndLine, startLine)
+		byURI[uri] = append(byURI[uri], Diagnostic{
+			Range: Range{
+				Start: Position{Line: startLine - 1},
+				End:   Position{Line: endLine},
+			},
+			Severity: lintDiagnosticSeverity(violation.Severity),
+			Source:   "gts lint " + violation.RuleID,
+			Message:  violation.Message,
+		})
+	}
+
+	for uri := range s.diagnosed {
+		if _, ok := byURI[uri]; !ok {
+			byURI[uri] = []Diagnostic{}
+		}
+	}
+	s.diagnosed = map[string]bool{}
+	uris := make([]string, 0, len(byURI))
+	for uri, diagnostics := range byURI {
+		uris = append(uris, uri)
+		if len(diagnostics) > 0 {
+			s.diagnosed[uri] = true
+		}
+	}
+	slices.Sort(uris)
+	for _, uri := range uris {
+		if err := s.client.Notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: uri, Diagnostics: byURI[uri]}); err != nil {
+			slog.Warn("gtsls: failed to publish diagnostics", "uri", uri, "error", err)
+			return
+		}
+	}
+}
+
+func lintDiagnosticSeverity(severity string) int {
+	switch strings.ToLower(severity) {
+	case "error":
+		return 1
+	case "info":
+		return 3
+	case "hint":
+		return 4
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,137 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSettings(t *testing.T) {
+	nested, err := parseSettings(json.RawMessage(`{"gtsls":{"exclude":["vendor/"],"codeActionTokens":400},"gopls":{"staticcheck":true}}`))
+	if err != nil {
+		t.Fatalf("parseSettings nested: %v", err)
+	}
+	if len(nested.Exclude) != 1 || nested.Exclude[0] != "vendor/" || nested.CodeActionTokens != 400 {
+		t.Fatalf("unexpected nested settings %+v", nested)
+	}
+
+	flat, err := parseSettings(json.RawMessage(`{"cache":".gts/index.json","lintRules":["no import unsafe"]}`))
+	if err != nil {
+		t.Fatalf("parseSettings flat: %v", err)
+	}
+	if flat.Cache != ".gts/index.json" || len(flat.LintRules) != 1 {
+		t.Fatalf("unexpected flat settings %+v", flat)
+	}
+
+	for _, raw := range []string{"", "null"} {
+		settings, err := parseSettings(json.RawMessage(raw))
+		if err != nil || settings.Cache != "" || settings.Exclude != nil {
+			t.Fatalf("parseSettings(%q) = %+v, %v; want zero settings", raw, settings, err)
+		}
+	}
+	if _, err := parseSettings(json.RawMessage(`["exclude"]`)); err == nil {
+		t.Fatalf("expected non-object settings to fail")
+	}
+}
+
+func TestServiceDidChangeConfiguration(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc hello() {\n\tprintln(\"hi\")\n}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "util.go"), []byte("package main\n\nfunc util() {}\n"), 0644)
+
+	input := lspRequest(1, "initialize", map[string]any{
+		"rootUri": "file://" + dir,
+		"initializationOptions": map[string]any{
+			"gtsls": map[string]any{"lintRules": []string{"no function longer than 2 lines"}},
+		},
+	})
+	input += lspNotify("initialized", map[string]any{})
+	input += lspNotify("workspace/didChangeConfiguration", map[string]any{
+		"settings": map[string]any{
+			"gtsls": map[string]any{
+				"exclude":   []string{"main.go"},
+				"cache":     ".gts/lsp-index.json",
+				"lintRules": []string{"no function longer than 2 lines"},
+			},
+		},
+	})
+	input += lspRequest(2, "workspace/symbol", map[string]string{"query": ""})
+	input += lspRequest(3, "shutdown", nil)
+
+	var out bytes.Buffer
+	svc := NewService(nil)
+	srv := NewServer(strings.NewReader(input), &out, os.Stderr)
+	svc.Register(srv)
+	srv.Serve()
+
+	var published []PublishDiagnosticsParams
+	var symbols []SymbolInformation
+	for _, msg := range splitLSPMessages(t, out.Bytes()) {
+		switch {
+		case msg.Method == "textDocument/publishDiagnostics":
+			var params PublishDiagnosticsParams
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				t.Fatalf("decode diagnostics: %v", err)
+			}
+			published = append(published, params)
+		case string(msg.ID) == "2":
+			if err := json.Unmarshal(msg.Result, &symbols); err != nil {
+				t.Fatalf("decode workspace symbols: %v", err)
+			}
+		}
+	}
+
+	mainURI := "file://" + dir + "/main.go"
+	if len(published) != 2 {
+		t.Fatalf("expected diagnostics to be published then cleared, got %+v", published)
+	}
+	if published[0].URI != mainURI || len(published[0].Diagnostics) != 1 {
+		t.Fatalf("expected one lint diagnostic on main.go, got %+v", published[0])
+	}
+	if diag := published[0].Diagnostics[0]; diag.Range.Start.Line != 2 || diag.Range.End.Line != 5 || diag.Severity != 2 {
+		t.Fatalf("unexpected diagnostic %+v", diag)
+	}
+	if published[1].URI != mainURI || len(published[1].Diagnostics) != 0 {
+		t.Fatalf("expected main.go diagnostics to be cleared once it is excluded, got %+v", published[1])
+	}
+
+	for _, symbol := range symbols {
+		if symbol.Name == "hello" {
+			t.Fatalf("expected excluded main.go to leave the index, got %+v", symbols)
+		}
+	}
+	if len(symbols) != 1 || symbols[0].Name != "util" {
+		t.Fatalf("expected only util in the index, got %+v", symbols)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".gts", "lsp-index.json")); err != nil {
+		t.Fatalf("expected the index cache to be written: %v", err)
+	}
+}
+
+// lspMessage is a response or notification read back from a server.
+type lspMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+}
+
+func splitLSPMessages(t *testing.T, data []byte) []lspMessage {
+	t.Helper()
+	var messages []lspMessage
+	for _, part := range strings.Split(string(data), "Content-Length: ")[1:] {
+		_, body, ok := strings.Cut(part, "\r\n\r\n")
+		if !ok {
+			t.Fatalf("malformed message %q", part)
+		}
+		var msg lspMessage
+		if err := json.Unmarshal([]byte(body), &msg); err != nil {
+			t.Fatalf("decode message %q: %v", body, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
@@ -0,0 +1,59 @@
+package lsp
+
+import (
+	"github.com/odvcencio/gotreesitter"
+	"github.com/odvcencio/gotreesitter/grammars"
+
+	"github.com/odvcencio/gts-suite/internal/parsesession"
+	"github.com/odvcencio/gts-suite/pkg/textpos"
+)
+
+// syntaxDocument is a document parsed with tree-sitter, for the requests
+// answered from its syntax tree rather than the index.
+type syntaxDocument struct {
+	tree   *gotreesitter.Tree
+	lang   *gotreesitter.Language
+	source []byte
+	m      *textpos.Map
+	enc    textpos.Encoding
+}
+
+// parseDocument parses the document at uri, reporting false when its
+// language has no grammar or it cannot be read or parsed. The caller must
+// release the document.
+func (s *Service) parseDocument(uri string) (*syntaxDocument, bool) {
+	entry := grammars.DetectLanguage(uriToPath(uri))
+	if entry == nil {
+		return nil, false
+	}
+	source, err := s.documentSource(uri)
+	if err != nil {
+		return nil, false
+	}
+	session := parsesession.New()
+	tree, err := session.Parse(entry.Name, source)
+	if err != nil {
+		return nil, false
+	}
+	lang, _ := session.Language(entry.Name)
+	enc := s.encoding
+	if enc == "" {
+		enc = textpos.UTF16
+	}
+	return &syntaxDocument{tree: tree, lang: lang, source: source, m: textpos.New(source), enc: enc}, true
+}
+
+func (d *syntaxDocument) release() {
+	d.tree.Release()
+}
+
+// offset returns the byte offset of pos.
+func (d *syntaxDocument) offset(pos Position) int {
+	return d.m.Offset(pos.Line, pos.Character, d.enc)
+}
+
+// position returns the position of a byte offset.
+func (d *syntaxDocument) position(offset int) Position {
+	line, col := d.m.Position(offset, d.enc)
+	return Position{Line: line, Character: col}
+}
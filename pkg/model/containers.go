@@ -0,0 +1,183 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// SymbolNode is a symbol together with the symbols declared inside it.
+type SymbolNode struct {
+	Symbol
+	Children []SymbolNode `json:"children,omitempty"`
+}
+
+// QualifiedName returns the symbol name prefixed by its container path,
+// e.g. "Server.Run".
+func (s Symbol) QualifiedName() string {
+	if s.ContainerPath == "" {
+		return s.Name
+	}
+	return s.ContainerPath + "." + s.Name
+}
+
+// IsContainerKind reports whether a symbol kind declares a type-level
+// container that methods can be attached to by receiver.
+func IsContainerKind(kind string) bool {
+	switch kind {
+	case "class_definition", "interface_definition", "struct_definition", "enum_definition", "type_definition", "module_definition":
+		return true
+	}
+	return false
+}
+
+// IsFunctionKind reports whether a symbol kind declares a function that is
+// not a method: a function_definition, or the component_definition or
+// hook_definition a React function is indexed as instead.
+func IsFunctionKind(kind string) bool {
+	switch kind {
+	case "function_definition", "component_definition", "hook_definition":
+		return true
+	}
+	return false
+}
+
+// AssignContainerPaths sets ContainerPath on each symbol of one file. A symbol
+// belongs to the smallest symbol whose line range strictly encloses it; a
+// top-level function or method whose receiver names a container type in the
+// same file (Go methods, Rust impls) belongs to that type.
+func AssignContainerPaths(symbols []Symbol) {
+	parents := containerParents(symbols)
+	var pathOf func(i int) string
+	memo := make(map[int]string, len(symbols))
+	pathOf = func(i int) string {
+		if path, ok := memo[i]; ok {
+			return path
+		}
+		path := ""
+		if p := parents[i]; p >= 0 {
+			path = symbols[p].Name
+			if outer := pathOf(p); outer != "" {
+				path = outer + "." + path
+			}
+		}
+		memo[i] = path
+		return path
+	}
+	for i := range symbols {
+		symbols[i].ContainerPath = pathOf(i)
+	}
+}
+
+// NestSymbols arranges one file's symbols into a tree. It does not read
+// ContainerPath: like AssignContainerPaths, it nests each symbol under the
+// smallest symbol whose line range strictly encloses it, or under the
+// same-file type its receiver names. Siblings are ordered by start line.
+func NestSymbols(symbols []Symbol) []SymbolNode {
+	parents := containerParents(symbols)
+	children := make(map[int][]int, len(symbols))
+	roots := make([]int, 0, len(symbols))
+	for i, p := range parents {
+		if p < 0 {
+			roots = append(roots, i)
+		} else {
+			children[p] = append(children[p], i)
+		}
+	}
+
+	var build func(indexes []int) []SymbolNode
+	build = func(indexes []int) []SymbolNode {
+		if len(indexes) == 0 {
+			return nil
+		}
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return symbols[indexes[a]].StartLine < symbols[indexes[b]].StartLine
+		})
+		nodes := make([]SymbolNode, 0, len(indexes))
+		for _, i := range indexes {
+			nodes = append(nodes, SymbolNode{Symbol: symbols[i], Children: build(children[i])})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+// ContainerParents returns, for each symbol of one file, the index of its
+// container under the rules of AssignContainerPaths, or -1 for a top-level
+// symbol.
+func ContainerParents(symbols []Symbol) []int {
+	return containerParents(symbols)
+}
+
+// containerParents returns, for each symbol, the index of its parent or -1.
+func containerParents(symbols []Symbol) []int {
+	order := make([]int, len(symbols))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		sa, sb := symbols[order[a]], symbols[order[b]]
+		if sa.StartLine != sb.StartLine {
+			return sa.StartLine < sb.StartLine
+		}
+		return sa.EndLine > sb.EndLine
+	})
+
+	parents := make([]int, len(symbols))
+	stack := make([]int, 0, 8)
+	for _, i := range order {
+		for len(stack) > 0 && !enclosesLines(symbols[stack[len(stack)-1]], symbols[i]) {
+			stack = stack[:len(stack)-1]
+		}
+		parents[i] = -1
+		if len(stack) > 0 {
+			parents[i] = stack[len(stack)-1]
+		}
+		stack = append(stack, i)
+	}
+
+	types := map[string]int{}
+	for _, i := range order {
+		if parents[i] < 0 && IsContainerKind(symbols[i].Kind) {
+			if _, exists := types[symbols[i].Name]; !exists {
+				types[symbols[i].Name] = i
+			}
+		}
+	}
+	for i, symbol := range symbols {
+		if parents[i] >= 0 || symbol.Receiver == "" || IsContainerKind(symbol.Kind) {
+			continue
+		}
+		if owner, ok := types[ReceiverType(symbol.Receiver)]; ok && owner != i {
+			parents[i] = owner
+		}
+	}
+	return parents
+}
+
+// enclosesLines reports whether outer's line range strictly contains inner's.
+func enclosesLines(outer, inner Symbol) bool {
+	if outer.StartLine > inner.StartLine || outer.EndLine < inner.EndLine {
+		return false
+	}
+	return outer.StartLine != inner.StartLine || outer.EndLine != inner.EndLine
+}
+
+// ReceiverType extracts the type name from a receiver such as "s *Server",
+// "*Server", "c *Cache[K, V]", or "Display for Point".
+func ReceiverType(receiver string) string {
+	receiver = strings.TrimSpace(receiver)
+	if _, after, ok := strings.Cut(receiver, " for "); ok {
+		receiver = after
+	}
+	if idx := strings.IndexAny(receiver, "[<("); idx >= 0 {
+		receiver = receiver[:idx]
+	}
+	if fields := strings.Fields(receiver); len(fields) > 0 {
+		receiver = fields[len(fields)-1]
+	}
+	receiver = strings.TrimLeft(receiver, "*&")
+	if idx := strings.LastIndexAny(receiver, ".:"); idx >= 0 {
+		receiver = receiver[idx+1:]
+	}
+	return receiver
+}
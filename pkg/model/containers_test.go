@@ -0,0 +1,65 @@
+package model
+
+import "testing"
+
+func TestAssignContainerPaths(t *testing.T) {
+	symbols := []Symbol{
+		{Kind: "type_definition", Name: "Server", StartLine: 3, EndLine: 6},
+		{Kind: "method_definition", Name: "Run", Receiver: "s *Server", StartLine: 10, EndLine: 18},
+		{Kind: "function_definition", Name: "inner", StartLine: 12, EndLine: 14},
+		{Kind: "function_definition", Name: "helper", StartLine: 20, EndLine: 22},
+		{Kind: "class_definition", Name: "Widget", Receiver: "Widget", StartLine: 24, EndLine: 30},
+		{Kind: "function_definition", Name: "draw", Receiver: "Widget", StartLine: 25, EndLine: 27},
+	}
+	AssignContainerPaths(symbols)
+
+	want := map[string]string{
+		"Server": "",
+		"Run":    "Server",
+		"inner":  "Server.Run",
+		"helper": "",
+		"Widget": "",
+		"draw":   "Widget",
+	}
+	for _, symbol := range symbols {
+		if symbol.ContainerPath != want[symbol.Name] {
+			t.Errorf("%s: container path = %q, want %q", symbol.Name, symbol.ContainerPath, want[symbol.Name])
+		}
+	}
+	if got := symbols[2].QualifiedName(); got != "Server.Run.inner" {
+		t.Fatalf("QualifiedName = %q", got)
+	}
+
+	tree := NestSymbols(symbols)
+	if len(tree) != 3 || tree[0].Name != "Server" || len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 1 {
+		t.Fatalf("unexpected tree %+v", tree)
+	}
+}
+
+func TestReceiverType(t *testing.T) {
+	for receiver, want := range map[string]string{
+		"s *Server":         "Server",
+		"*Server":           "Server",
+		"c *Cache[K, V]":    "Cache",
+		"Display for Point": "Point",
+		"A":                 "A",
+	} {
+		if got := ReceiverType(receiver); got != want {
+			t.Fatalf("ReceiverType(%q) = %q, want %q", receiver, got, want)
+		}
+	}
+}
+
+func TestIsFunctionKind(t *testing.T) {
+	for kind, want := range map[string]bool{
+		"function_definition":  true,
+		"component_definition": true,
+		"hook_definition":      true,
+		"method_definition":    false,
+		"type_definition":      false,
+	} {
+		if got := IsFunctionKind(kind); got != want {
+			t.Fatalf("IsFunctionKind(%q) = %v, want %v", kind, got, want)
+		}
+	}
+}
@@ -1,7 +1,12 @@
 // Package model defines the core data types for structural code indexing: Symbol, Reference, FileSummary, and Index.
 package model
 
-import "time"
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
 
 // Symbol represents a top-level declaration (function, method, type) in a source file.
 type Symbol struct {
@@ -12,6 +17,30 @@ type Symbol struct {
 	Receiver  string `json:"receiver,omitempty"`
 	StartLine int    `json:"start_line"`
 	EndLine   int    `json:"end_line"`
+	// StartColumn and EndColumn are 1-based byte columns on StartLine and
+	// EndLine, the end exclusive, as for Reference.
+	StartColumn int `json:"start_column,omitempty"`
+	EndColumn   int `json:"end_column,omitempty"`
+	// StartByte and EndByte are byte offsets into the file, the end
+	// exclusive. Both are zero in indexes built before they were recorded.
+	StartByte int `json:"start_byte,omitempty"`
+	EndByte   int `json:"end_byte,omitempty"`
+	// ContainerPath is the dot-separated chain of enclosing symbol names,
+	// e.g. "Server" for a method of Server; empty for top-level symbols.
+	ContainerPath string `json:"container_path,omitempty"`
+	// Visibility is the declared or conventional access level: "public",
+	// "private", "protected", "internal", or "package". Empty when the
+	// language gives no signal.
+	Visibility string `json:"visibility,omitempty"`
+	Exported   bool   `json:"exported,omitempty"` // part of the package's public API
+	Static     bool   `json:"static,omitempty"`
+	Async      bool   `json:"async,omitempty"`
+	Abstract   bool   `json:"abstract,omitempty"`
+	// Attributes are the decorators, annotations, and attributes on the
+	// declaration as written, e.g. "@app.route('/users')", "@Override",
+	// "[HttpGet]", "#[test]", and for Go struct fields the tag pairs, e.g.
+	// `json:"name,omitempty"`.
+	Attributes []string `json:"attributes,omitempty"`
 }
 
 // Reference represents a usage of a symbol at a specific source location.
@@ -23,6 +52,12 @@ type Reference struct {
 	EndLine     int    `json:"end_line"`
 	StartColumn int    `json:"start_column,omitempty"`
 	EndColumn   int    `json:"end_column,omitempty"`
+	// Qualifier is the expression the name is selected from, when the parser
+	// can see one: "os" for os.Exit, "s.db" for s.db.Close, "Foo" for Foo::new.
+	Qualifier string `json:"qualifier,omitempty"`
+	// Access is "read" or "write" for reference.field references to struct
+	// fields and class members; empty for other kinds.
+	Access string `json:"access,omitempty"`
 }
 
 // GeneratedInfo describes why a file is considered generated and what produced it.
@@ -38,10 +73,37 @@ type FileSummary struct {
 	Language        string         `json:"language"`
 	SizeBytes       int64          `json:"size_bytes,omitempty"`
 	ModTimeUnixNano int64          `json:"mod_time_unix_nano,omitempty"`
+	ContentHash     string         `json:"content_hash,omitempty"` // hex SHA-256 of the file contents
 	Imports         []string       `json:"imports,omitempty"`
 	Symbols         []Symbol       `json:"symbols,omitempty"`
 	References      []Reference    `json:"references,omitempty"`
 	Generated       *GeneratedInfo `json:"generated,omitempty"`
+	// Attributes are file-level attributes as written, e.g. the
+	// "//go:build linux" constraints of a Go file.
+	Attributes []string `json:"attributes,omitempty"`
+	// Cells maps the lines of a notebook, indexed as the source of its code
+	// cells joined in order, back to the cells they came from.
+	Cells []NotebookCell `json:"cells,omitempty"`
+}
+
+// NotebookCell places one code cell of a notebook in the source the notebook
+// is indexed as.
+type NotebookCell struct {
+	Cell      int `json:"cell"`       // 1-based position among all the notebook's cells
+	StartLine int `json:"start_line"` // first line of the cell's code in the indexed source
+	EndLine   int `json:"end_line"`
+}
+
+// CellLine maps line of a notebook's indexed source to the cell it came
+// from and the 1-based line within that cell. ok is false for files that
+// are not notebooks.
+func (f FileSummary) CellLine(line int) (cell, cellLine int, ok bool) {
+	for _, c := range f.Cells {
+		if line >= c.StartLine && line <= c.EndLine {
+			return c.Cell, line - c.StartLine + 1, true
+		}
+	}
+	return 0, 0, false
 }
 
 // ParseError records a file that failed to parse.
@@ -50,14 +112,61 @@ type ParseError struct {
 	Error string `json:"error"`
 }
 
+// SkippedFile records a source file the builder deliberately did not parse.
+type SkippedFile struct {
+	Path      string `json:"path"`
+	Reason    string `json:"reason"` // "max_size" or "binary"
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+}
+
 // Index is a structural snapshot of a codebase containing file summaries and parse errors.
 type Index struct {
 	Version      string            `json:"version"`
 	Root         string            `json:"root"`
+	Revision     string            `json:"revision,omitempty"` // git commit the index was built from, if any
 	GeneratedAt  time.Time         `json:"generated_at"`
 	Files        []FileSummary     `json:"files"`
 	Errors       []ParseError      `json:"errors,omitempty"`
+	Skipped      []SkippedFile     `json:"skipped,omitempty"`
 	ConfigHashes map[string]string `json:"config_hashes,omitempty"`
+	Digest       string            `json:"digest,omitempty"` // hash over file paths and content hashes
+	Provenance   *Provenance       `json:"provenance,omitempty"`
+}
+
+// Provenance records how an index was built: by which gts and Go, from
+// which commit, with which options, and how long it took. Indexes written
+// before provenance was recorded have none.
+type Provenance struct {
+	ToolVersion string `json:"tool_version,omitempty"`
+	GoVersion   string `json:"go_version,omitempty"`
+	// Grammars is the version of the gotreesitter module, which ships the
+	// grammars and tags queries every language is parsed with.
+	Grammars string `json:"grammars,omitempty"`
+	// GitCommit is the HEAD commit of the indexed tree when it was built, or
+	// the commit of a revision build.
+	GitCommit   string           `json:"git_commit,omitempty"`
+	Options     BuildSettings    `json:"options"`
+	DurationMS  int64            `json:"duration_ms"`
+	ParsedFiles int              `json:"parsed_files"`
+	ReusedFiles int              `json:"reused_files"`
+	Languages   []LanguageSource `json:"languages,omitempty"`
+}
+
+// BuildSettings are the builder options that change what an index holds.
+type BuildSettings struct {
+	FollowSymlinks bool  `json:"follow_symlinks,omitempty"`
+	SkipGenerated  bool  `json:"skip_generated,omitempty"`
+	MaxFileSize    int64 `json:"max_file_size,omitempty"` // 0 means no limit
+}
+
+// LanguageSource records a language present in an index and the grammar
+// it was parsed with.
+type LanguageSource struct {
+	Language string `json:"language"`
+	Files    int    `json:"files"`
+	// Grammar is how gotreesitter provides the grammar, such as
+	// "ts2go_blob" or "grammargen".
+	Grammar string `json:"grammar,omitempty"`
 }
 
 // FileCount returns the number of successfully parsed files in the index.
@@ -123,6 +232,147 @@ func (idx *Index) WithoutGenerated() *Index {
 	return &filtered
 }
 
+// FilterByPaths returns a shallow copy containing only files whose path is in paths.
+func (idx *Index) FilterByPaths(paths map[string]bool) *Index {
+	if idx == nil {
+		return nil
+	}
+	filtered := *idx
+	filtered.Files = make([]FileSummary, 0, len(paths))
+	for _, f := range idx.Files {
+		if paths[f.Path] {
+			filtered.Files = append(filtered.Files, f)
+		}
+	}
+	return &filtered
+}
+
+// FilterByGlobs returns a shallow copy containing only files matching at least
+// one pattern (see MatchGlob). An empty pattern list keeps every file.
+func (idx *Index) FilterByGlobs(patterns []string) *Index {
+	if idx == nil || len(patterns) == 0 {
+		return idx
+	}
+	filtered := *idx
+	filtered.Files = make([]FileSummary, 0)
+	for _, f := range idx.Files {
+		for _, pattern := range patterns {
+			if MatchGlob(pattern, f.Path) {
+				filtered.Files = append(filtered.Files, f)
+				break
+			}
+		}
+	}
+	return &filtered
+}
+
+// FilterByPathPatterns returns a shallow copy keeping the files, parse
+// errors, and skipped files selected by patterns. Patterns are globs (see
+// MatchGlob); a leading "!" makes one exclude, and a trailing "/..." selects
+// a directory and everything below it, as in "internal/...". A path is kept
+// when it matches some include, or when there are only excludes, and matches
+// no exclude. An empty pattern list keeps everything.
+func (idx *Index) FilterByPathPatterns(patterns []string) (*Index, error) {
+	if idx == nil || len(patterns) == 0 {
+		return idx, nil
+	}
+	var include, exclude []string
+	for _, raw := range patterns {
+		pattern := strings.TrimSpace(raw)
+		excluded := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(strings.TrimPrefix(pattern, "!"), "./")
+		if pattern == "..." {
+			pattern = "**"
+		} else if dir, ok := strings.CutSuffix(pattern, "/..."); ok {
+			pattern = dir + "/**"
+		}
+		if pattern == "" {
+			return nil, fmt.Errorf("empty path pattern %q", raw)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid path pattern %q: %w", raw, err)
+		}
+		if excluded {
+			exclude = append(exclude, pattern)
+		} else {
+			include = append(include, pattern)
+		}
+	}
+	keep := func(p string) bool {
+		for _, pattern := range exclude {
+			if MatchGlob(pattern, p) {
+				return false
+			}
+		}
+		if len(include) == 0 {
+			return true
+		}
+		for _, pattern := range include {
+			if MatchGlob(pattern, p) {
+				return true
+			}
+		}
+		return false
+	}
+
+	filtered := *idx
+	filtered.Files = make([]FileSummary, 0, len(idx.Files))
+	for _, f := range idx.Files {
+		if keep(f.Path) {
+			filtered.Files = append(filtered.Files, f)
+		}
+	}
+	filtered.Errors = nil
+	for _, e := range idx.Errors {
+		if keep(e.Path) {
+			filtered.Errors = append(filtered.Errors, e)
+		}
+	}
+	filtered.Skipped = nil
+	for _, s := range idx.Skipped {
+		if keep(s.Path) {
+			filtered.Skipped = append(filtered.Skipped, s)
+		}
+	}
+	return &filtered, nil
+}
+
+// MatchGlob matches a slash-separated path against a glob pattern. "**"
+// matches any number of directories, and a pattern without a slash is matched
+// against the base name, so "*.go" selects Go files at any depth.
+func MatchGlob(pattern, p string) bool {
+	pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "./")
+	if pattern == "" {
+		return false
+	}
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(p))
+		return matched
+	}
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+func matchGlobSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchGlobSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
 // FilterByGenerator returns a copy with only files matching the given generator.
 // "human" matches files with nil Generated.
 func (idx *Index) FilterByGenerator(name string) *Index {
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"reflect"
 	"testing"
 	"time"
 )
@@ -350,3 +351,91 @@ func TestIndexFields(t *testing.T) {
 		t.Errorf("len(Errors) = %d, want 1", len(idx.Errors))
 	}
 }
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "pkg/model/model.go", true},
+		{"*.go", "README.md", false},
+		{"pkg/*/model.go", "pkg/model/model.go", true},
+		{"pkg/*.go", "pkg/model/model.go", false},
+		{"pkg/**/*.go", "pkg/model/model.go", true},
+		{"pkg/**/*.go", "pkg/a/b/c.go", true},
+		{"pkg/**", "pkg/a/b/c.go", true},
+		{"**/model.go", "model.go", true},
+		{"./cmd/gts/map.go", "cmd/gts/map.go", true},
+		{"cmd/**/map.go", "pkg/map.go", false},
+	}
+	for _, tc := range cases {
+		if got := MatchGlob(tc.pattern, tc.path); got != tc.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tc.pattern, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestFilterByGlobs(t *testing.T) {
+	idx := &Index{Files: []FileSummary{{Path: "a.go"}, {Path: "web/app.ts"}, {Path: "web/util.go"}}}
+	filtered := idx.FilterByGlobs([]string{"web/**/*.ts", "a.go"})
+	if len(filtered.Files) != 2 || filtered.Files[0].Path != "a.go" || filtered.Files[1].Path != "web/app.ts" {
+		t.Fatalf("unexpected files %+v", filtered.Files)
+	}
+	if idx.FilterByGlobs(nil) != idx {
+		t.Fatal("expected empty pattern list to return the index unchanged")
+	}
+}
+
+func TestFilterByPathPatterns(t *testing.T) {
+	idx := &Index{
+		Files: []FileSummary{
+			{Path: "cmd/gts/main.go"},
+			{Path: "internal/api/server.go"},
+			{Path: "internal/api/server_test.go"},
+			{Path: "internal/db/db.go"},
+		},
+		Errors:  []ParseError{{Path: "cmd/gts/broken.go"}, {Path: "internal/db/broken.go"}},
+		Skipped: []SkippedFile{{Path: "internal/db/blob.bin"}},
+	}
+	paths := func(index *Index) []string {
+		var out []string
+		for _, f := range index.Files {
+			out = append(out, f.Path)
+		}
+		return out
+	}
+
+	cases := []struct {
+		patterns []string
+		want     []string
+	}{
+		{[]string{"internal/..."}, []string{"internal/api/server.go", "internal/api/server_test.go", "internal/db/db.go"}},
+		{[]string{"internal/...", "!*_test.go"}, []string{"internal/api/server.go", "internal/db/db.go"}},
+		{[]string{"!internal/db/..."}, []string{"cmd/gts/main.go", "internal/api/server.go", "internal/api/server_test.go"}},
+		{[]string{"./cmd/**", "internal/db/*.go"}, []string{"cmd/gts/main.go", "internal/db/db.go"}},
+	}
+	for _, tc := range cases {
+		filtered, err := idx.FilterByPathPatterns(tc.patterns)
+		if err != nil {
+			t.Fatalf("%v: %v", tc.patterns, err)
+		}
+		if got := paths(filtered); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v: got %v, want %v", tc.patterns, got, tc.want)
+		}
+	}
+
+	filtered, _ := idx.FilterByPathPatterns([]string{"internal/..."})
+	if len(filtered.Errors) != 1 || filtered.Errors[0].Path != "internal/db/broken.go" || len(filtered.Skipped) != 1 {
+		t.Fatalf("expected errors and skipped files to be filtered too, got %+v and %+v", filtered.Errors, filtered.Skipped)
+	}
+	if len(idx.Errors) != 2 {
+		t.Fatal("expected the original index to be left alone")
+	}
+	if _, err := idx.FilterByPathPatterns([]string{"internal/[api"}); err == nil {
+		t.Fatal("expected an error for a malformed pattern")
+	}
+	if same, _ := idx.FilterByPathPatterns(nil); same != idx {
+		t.Fatal("expected empty pattern list to return the index unchanged")
+	}
+}
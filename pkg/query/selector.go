@@ -12,27 +12,148 @@ import (
 
 var validKind = regexp.MustCompile(`^(?:\*|[a-z_][a-z0-9_]*)$`)
 var lineFilterPattern = regexp.MustCompile(`^(start|end|line)\s*(<=|>=|=)\s*(\d+)$`)
+var flagFilterPattern = regexp.MustCompile(`^(exported|static|async|abstract)\s*=\s*(true|false)$`)
 
 type Selector struct {
-	Kind        string
-	NameRE      *regexp.Regexp
-	SignatureRE *regexp.Regexp
-	ReceiverRE  *regexp.Regexp
-	FileRE      *regexp.Regexp
-	StartMin    *int
-	StartMax    *int
-	EndMin      *int
-	EndMax      *int
-	Line        *int
-	Raw         string
+	Kind         string
+	NameRE       *regexp.Regexp
+	SignatureRE  *regexp.Regexp
+	ReceiverRE   *regexp.Regexp
+	FileRE       *regexp.Regexp
+	VisibilityRE *regexp.Regexp
+	AttrRE       *regexp.Regexp // matches any one of the symbol's attributes
+	Exported     *bool
+	Static       *bool
+	Async        *bool
+	Abstract     *bool
+	StartMin     *int
+	StartMax     *int
+	EndMin       *int
+	EndMax       *int
+	Line         *int
+	// Not holds negated clauses such as !name=/^Test/; a symbol matching any
+	// of them is rejected.
+	Not []Selector
+	// Parent, set by "parent > child", must match the symbol's direct
+	// container. Only MatchFile checks it.
+	Parent *Selector
+	// Any, set by "a, b", holds alternatives; the selector matches what any
+	// of them matches, and its other fields are unused.
+	Any []Selector
+	Raw string
 }
 
+// ParseSelector parses kind[filter,...] selectors. Segments joined by ">"
+// select symbols directly contained in a symbol matching the previous
+// segment, as in "class_definition > method_definition[name=/^handle/]".
+// Selectors separated by commas outside brackets are alternatives, as in
+// "function_definition[name=/^Old/], type_definition[name=/^Old/]".
 func ParseSelector(raw string) (Selector, error) {
 	text := strings.TrimSpace(raw)
 	if text == "" {
 		return Selector{}, fmt.Errorf("selector cannot be empty")
 	}
 
+	alternatives, err := splitTopLevel(text, ',')
+	if err != nil {
+		return Selector{}, err
+	}
+	if len(alternatives) == 1 {
+		return parseChain(text)
+	}
+	return ParseSelectors(alternatives)
+}
+
+// ParseSelectors parses each raw selector and returns their union, so a
+// repeated --or flag and comma-separated alternatives behave alike.
+func ParseSelectors(raws []string) (Selector, error) {
+	if len(raws) == 1 {
+		return ParseSelector(raws[0])
+	}
+	union := Selector{Kind: "*"}
+	texts := make([]string, 0, len(raws))
+	for _, raw := range raws {
+		selector, err := ParseSelector(raw)
+		if err != nil {
+			return Selector{}, err
+		}
+		if len(selector.Any) > 0 {
+			union.Any = append(union.Any, selector.Any...)
+		} else {
+			union.Any = append(union.Any, selector)
+		}
+		texts = append(texts, selector.Raw)
+	}
+	if len(union.Any) == 0 {
+		return Selector{}, fmt.Errorf("selector cannot be empty")
+	}
+	union.Raw = strings.Join(texts, ", ")
+	return union, nil
+}
+
+func parseChain(text string) (Selector, error) {
+	segments, err := splitTopLevel(text, '>')
+	if err != nil {
+		return Selector{}, err
+	}
+	var parent *Selector
+	for _, segment := range segments[:len(segments)-1] {
+		outer, err := parseSegment(segment)
+		if err != nil {
+			return Selector{}, err
+		}
+		outer.Parent = parent
+		parent = &outer
+	}
+	selector, err := parseSegment(segments[len(segments)-1])
+	if err != nil {
+		return Selector{}, err
+	}
+	selector.Parent = parent
+	selector.Raw = text
+	return selector, nil
+}
+
+// splitTopLevel splits text at each sep outside brackets.
+func splitTopLevel(text string, sep byte) ([]string, error) {
+	segments := make([]string, 0, 2)
+	start := 0
+	inBracket := false
+	inRegex := false
+	escaped := false
+
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inRegex && ch == '\\':
+			escaped = true
+		case inBracket && ch == '/':
+			inRegex = !inRegex
+		case inRegex:
+		case ch == '[':
+			inBracket = true
+		case ch == ']':
+			inBracket = false
+		case ch == sep && !inBracket:
+			segment := strings.TrimSpace(text[start:i])
+			if segment == "" {
+				return nil, fmt.Errorf("invalid selector %q: empty segment around '%c'", text, sep)
+			}
+			segments = append(segments, segment)
+			start = i + 1
+		}
+	}
+
+	last := strings.TrimSpace(text[start:])
+	if last == "" {
+		return nil, fmt.Errorf("invalid selector %q: empty segment around '%c'", text, sep)
+	}
+	return append(segments, last), nil
+}
+
+func parseSegment(text string) (Selector, error) {
 	selector := Selector{
 		Kind: "*",
 		Raw:  text,
@@ -65,6 +186,17 @@ func ParseSelector(raw string) (Selector, error) {
 	}
 
 	for _, clause := range filters {
+		if negated, ok := strings.CutPrefix(clause, "!"); ok {
+			not := Selector{Kind: "*", Raw: clause}
+			if err := applyFilterClause(&not, strings.TrimSpace(negated)); err != nil {
+				return Selector{}, err
+			}
+			if err := validateNumericFilters(not); err != nil {
+				return Selector{}, err
+			}
+			selector.Not = append(selector.Not, not)
+			continue
+		}
 		if err := applyFilterClause(&selector, clause); err != nil {
 			return Selector{}, err
 		}
@@ -140,6 +272,18 @@ func applyFilterClause(selector *Selector, clause string) error {
 				selector.FileRE = value
 			},
 		},
+		{
+			prefix: "visibility=",
+			setter: func(value *regexp.Regexp) {
+				selector.VisibilityRE = value
+			},
+		},
+		{
+			prefix: "attr=",
+			setter: func(value *regexp.Regexp) {
+				selector.AttrRE = value
+			},
+		},
 	}
 
 	for _, filter := range regexFilters {
@@ -154,6 +298,21 @@ func applyFilterClause(selector *Selector, clause string) error {
 		return nil
 	}
 
+	if flag := flagFilterPattern.FindStringSubmatch(clause); flag != nil {
+		value := boolPtr(flag[2] == "true")
+		switch flag[1] {
+		case "exported":
+			selector.Exported = value
+		case "static":
+			selector.Static = value
+		case "async":
+			selector.Async = value
+		case "abstract":
+			selector.Abstract = value
+		}
+		return nil
+	}
+
 	matches := lineFilterPattern.FindStringSubmatch(clause)
 	if matches == nil {
 		return fmt.Errorf("unsupported selector filter %q", clause)
@@ -213,6 +372,10 @@ func intPtr(value int) *int {
 	return &copied
 }
 
+func boolPtr(value bool) *bool {
+	return &value
+}
+
 func validateNumericFilters(selector Selector) error {
 	if selector.StartMin != nil && selector.StartMax != nil && *selector.StartMin > *selector.StartMax {
 		return fmt.Errorf("invalid start range: min %d is greater than max %d", *selector.StartMin, *selector.StartMax)
@@ -223,8 +386,19 @@ func validateNumericFilters(selector Selector) error {
 	return nil
 }
 
+// Match reports whether symbol satisfies s, without regard to s.Parent. The
+// kind function_definition also matches the component_definition and
+// hook_definition kinds React functions are indexed as.
 func (s Selector) Match(symbol model.Symbol) bool {
-	if s.Kind != "*" && symbol.Kind != s.Kind {
+	if len(s.Any) > 0 {
+		for _, alternative := range s.Any {
+			if alternative.Match(symbol) {
+				return true
+			}
+		}
+		return false
+	}
+	if s.Kind != "*" && symbol.Kind != s.Kind && !(s.Kind == "function_definition" && model.IsFunctionKind(symbol.Kind)) {
 		return false
 	}
 	if s.NameRE != nil && !s.NameRE.MatchString(symbol.Name) {
@@ -239,6 +413,24 @@ func (s Selector) Match(symbol model.Symbol) bool {
 	if s.FileRE != nil && !s.FileRE.MatchString(symbol.File) {
 		return false
 	}
+	if s.VisibilityRE != nil && !s.VisibilityRE.MatchString(symbol.Visibility) {
+		return false
+	}
+	if s.AttrRE != nil && !matchAny(s.AttrRE, symbol.Attributes) {
+		return false
+	}
+	if s.Exported != nil && symbol.Exported != *s.Exported {
+		return false
+	}
+	if s.Static != nil && symbol.Static != *s.Static {
+		return false
+	}
+	if s.Async != nil && symbol.Async != *s.Async {
+		return false
+	}
+	if s.Abstract != nil && symbol.Abstract != *s.Abstract {
+		return false
+	}
 	if s.StartMin != nil && symbol.StartLine < *s.StartMin {
 		return false
 	}
@@ -254,5 +446,64 @@ func (s Selector) Match(symbol model.Symbol) bool {
 	if s.Line != nil && (*s.Line < symbol.StartLine || *s.Line > symbol.EndLine) {
 		return false
 	}
+	for _, not := range s.Not {
+		if not.Match(symbol) {
+			return false
+		}
+	}
 	return true
 }
+
+func matchAny(re *regexp.Regexp, values []string) bool {
+	for _, value := range values {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchFile returns the symbols of one file that s matches, in order,
+// checking each segment of a containment chain against the symbol's
+// container as nested by model.AssignContainerPaths.
+func (s Selector) MatchFile(symbols []model.Symbol) []model.Symbol {
+	var parents []int
+	if s.hasParent() {
+		parents = model.ContainerParents(symbols)
+	}
+	matched := make([]model.Symbol, 0, 8)
+	for i := range symbols {
+		if s.matchAt(symbols, parents, i) {
+			matched = append(matched, symbols[i])
+		}
+	}
+	return matched
+}
+
+func (s Selector) hasParent() bool {
+	for _, alternative := range s.Any {
+		if alternative.Parent != nil {
+			return true
+		}
+	}
+	return s.Parent != nil
+}
+
+func (s Selector) matchAt(symbols []model.Symbol, parents []int, i int) bool {
+	if len(s.Any) > 0 {
+		for _, alternative := range s.Any {
+			if alternative.matchAt(symbols, parents, i) {
+				return true
+			}
+		}
+		return false
+	}
+	if !s.Match(symbols[i]) {
+		return false
+	}
+	if s.Parent == nil {
+		return true
+	}
+	parent := parents[i]
+	return parent >= 0 && s.Parent.matchAt(symbols, parents, parent)
+}
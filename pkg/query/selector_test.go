@@ -89,3 +89,157 @@ func TestSelectorMatch(t *testing.T) {
 		t.Fatal("expected selector not to match symbol outside filtered line range")
 	}
 }
+
+func TestSelectorMatch_ModifierFilters(t *testing.T) {
+	selector, err := ParseSelector("function_definition[exported=false,async=true,visibility=/private/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+
+	internal := model.Symbol{Kind: "function_definition", Name: "load", Visibility: "private", Async: true}
+	if !selector.Match(internal) {
+		t.Fatal("expected selector to match unexported async function")
+	}
+	public := internal
+	public.Visibility = "public"
+	public.Exported = true
+	if selector.Match(public) {
+		t.Fatal("expected selector not to match exported function")
+	}
+
+	if _, err := ParseSelector("function_definition[static=maybe]"); err == nil {
+		t.Fatal("expected error for non-boolean flag filter")
+	}
+}
+
+func TestSelectorMatch_NegatedFilters(t *testing.T) {
+	selector, err := ParseSelector("function_definition[!name=/^Test/, !file=/_test\\.go$/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+	if len(selector.Not) != 2 {
+		t.Fatalf("expected two negated clauses, got %d", len(selector.Not))
+	}
+	if !selector.Match(model.Symbol{Kind: "function_definition", Name: "Load", File: "store.go"}) {
+		t.Fatal("expected selector to match Load")
+	}
+	if selector.Match(model.Symbol{Kind: "function_definition", Name: "TestLoad", File: "store.go"}) {
+		t.Fatal("expected selector to reject TestLoad")
+	}
+	if selector.Match(model.Symbol{Kind: "function_definition", Name: "helper", File: "store_test.go"}) {
+		t.Fatal("expected selector to reject symbols in test files")
+	}
+
+	if _, err := ParseSelector("function_definition[!bogus=1]"); err == nil {
+		t.Fatal("expected error for unsupported negated filter")
+	}
+}
+
+func TestSelectorMatchFile_Containment(t *testing.T) {
+	selector, err := ParseSelector("class_definition[name=/Handler$/] > method_definition[name=/^handle/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+	if selector.Parent == nil || selector.Parent.Kind != "class_definition" || selector.Kind != "method_definition" {
+		t.Fatalf("unexpected containment chain %+v", selector)
+	}
+
+	symbols := []model.Symbol{
+		{Kind: "class_definition", Name: "UserHandler", StartLine: 1, EndLine: 10},
+		{Kind: "method_definition", Name: "handleGet", StartLine: 2, EndLine: 4},
+		{Kind: "method_definition", Name: "render", StartLine: 5, EndLine: 6},
+		{Kind: "class_definition", Name: "Store", StartLine: 12, EndLine: 20},
+		{Kind: "method_definition", Name: "handleLoad", StartLine: 13, EndLine: 15},
+		{Kind: "method_definition", Name: "handleOrphan", StartLine: 22, EndLine: 23},
+	}
+	matched := selector.MatchFile(symbols)
+	if len(matched) != 1 || matched[0].Name != "handleGet" {
+		t.Fatalf("expected only handleGet, got %+v", matched)
+	}
+
+	nested, err := ParseSelector("* > class_definition > method_definition")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+	if got := nested.MatchFile(symbols); len(got) != 0 {
+		t.Fatalf("expected no methods of nested classes, got %+v", got)
+	}
+
+	for _, raw := range []string{"> method_definition", "class_definition >", "function_definition[start>=3] > "} {
+		if _, err := ParseSelector(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+	if _, err := ParseSelector("function_definition[start>=3,name=/a>b/]"); err != nil {
+		t.Fatalf("expected > inside brackets to be a filter, got %v", err)
+	}
+}
+
+func TestParseSelector_Alternatives(t *testing.T) {
+	selector, err := ParseSelector("function_definition[name=/^Old/,exported=true], type_definition[name=/^Old/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+	if len(selector.Any) != 2 {
+		t.Fatalf("expected two alternatives, got %d", len(selector.Any))
+	}
+
+	symbols := []model.Symbol{
+		{Kind: "function_definition", Name: "OldLoad", Exported: true, StartLine: 1, EndLine: 2},
+		{Kind: "type_definition", Name: "OldStore", StartLine: 4, EndLine: 6},
+		{Kind: "function_definition", Name: "oldHelper", StartLine: 8, EndLine: 9},
+		{Kind: "method_definition", Name: "OldGet", StartLine: 10, EndLine: 11},
+	}
+	matched := selector.MatchFile(symbols)
+	if len(matched) != 2 || matched[0].Name != "OldLoad" || matched[1].Name != "OldStore" {
+		t.Fatalf("unexpected matches %+v", matched)
+	}
+	if selector.Match(symbols[3]) {
+		t.Fatal("expected method OldGet not to match")
+	}
+
+	union, err := ParseSelectors([]string{"function_definition[name=/^OldLoad$/]", "*[name=/^OldLoad$/], type_definition[name=/^OldStore$/]"})
+	if err != nil {
+		t.Fatalf("ParseSelectors returned error: %v", err)
+	}
+	if len(union.Any) != 3 {
+		t.Fatalf("expected three alternatives, got %d", len(union.Any))
+	}
+	if matched := union.MatchFile(symbols); len(matched) != 2 {
+		t.Fatalf("expected each symbol once, got %+v", matched)
+	}
+
+	contained, err := ParseSelector("type_definition > method_definition, function_definition[name=/^old/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+	if matched := contained.MatchFile(symbols); len(matched) != 1 || matched[0].Name != "oldHelper" {
+		t.Fatalf("unexpected matches %+v", matched)
+	}
+
+	if _, err := ParseSelector("function_definition, "); err == nil {
+		t.Fatal("expected error for empty alternative")
+	}
+}
+
+func TestSelectorMatch_AttrFilter(t *testing.T) {
+	selector, err := ParseSelector("function_definition[attr=/^@app\\.route/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+	route := model.Symbol{Kind: "function_definition", Name: "users", Attributes: []string{"@login_required", "@app.route('/users')"}}
+	if !selector.Match(route) {
+		t.Fatal("expected selector to match a decorated route")
+	}
+	if selector.Match(model.Symbol{Kind: "function_definition", Name: "helper"}) {
+		t.Fatal("expected selector not to match an undecorated function")
+	}
+
+	negated, err := ParseSelector("function_definition[!attr=/@login_required/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+	if negated.Match(route) {
+		t.Fatal("expected negated attr filter to reject the route")
+	}
+}
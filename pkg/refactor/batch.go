@@ -0,0 +1,270 @@
+package refactor
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/query"
+)
+
+// Rename is one row of a rename map: declarations matching Selector are
+// renamed from Old to New.
+type Rename struct {
+	Old      string         `json:"old"`
+	New      string         `json:"new"`
+	Selector query.Selector `json:"-"`
+	// Line is the row's line in the map file.
+	Line int `json:"line"`
+}
+
+// RenameSummary is the planned outcome of one rename in a batch.
+type RenameSummary struct {
+	Old          string `json:"old"`
+	New          string `json:"new"`
+	Selector     string `json:"selector"`
+	Engine       string `json:"engine"`
+	MatchCount   int    `json:"match_count"`
+	PlannedEdits int    `json:"planned_edits"`
+	SkippedEdits int    `json:"skipped_edits"`
+}
+
+// BatchReport consolidates the renames of a map into one set of edits.
+type BatchReport struct {
+	Root                  string          `json:"root"`
+	Write                 bool            `json:"write"`
+	UpdateCallsites       bool            `json:"update_callsites"`
+	CrossPackageCallsites bool            `json:"cross_package_callsites"`
+	Renames               []RenameSummary `json:"renames"`
+	MatchCount            int             `json:"match_count"`
+	PlannedEdits          int             `json:"planned_edits"`
+	AppliedEdits          int             `json:"applied_edits"`
+	ChangedFiles          int             `json:"changed_files"`
+	// Transaction is "committed" or "rolled_back" for --write runs.
+	Transaction string `json:"transaction,omitempty"`
+	Edits       []Edit `json:"edits,omitempty"`
+}
+
+// ParseRenameMap reads a rename map: CSV rows of "old,new" or
+// "old,new,selector". Without a selector a row renames every declaration
+// named old; a selector without a name filter is narrowed to that name.
+// Blank lines, lines starting with #, and an "old,new" header are skipped.
+func ParseRenameMap(r io.Reader) ([]Rename, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var renames []Rename
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse rename map: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		if len(renames) == 0 && len(record) >= 2 && record[0] == "old" && record[1] == "new" {
+			continue
+		}
+		if len(record) < 2 || len(record) > 3 || record[0] == "" || record[1] == "" {
+			return nil, fmt.Errorf("rename map line %d: expected old,new[,selector]", line)
+		}
+
+		rename := Rename{Old: record[0], New: record[1], Line: line}
+		raw := "*"
+		if len(record) == 3 && record[2] != "" {
+			raw = record[2]
+		}
+		selector, err := query.ParseSelector(raw)
+		if err != nil {
+			return nil, fmt.Errorf("rename map line %d: %w", line, err)
+		}
+		oldName := regexp.MustCompile("^" + regexp.QuoteMeta(rename.Old) + "$")
+		if selector.NameRE == nil && len(selector.Any) == 0 {
+			selector.NameRE = oldName
+		}
+		for i := range selector.Any {
+			if selector.Any[i].NameRE == nil {
+				selector.Any[i].NameRE = oldName
+			}
+		}
+		rename.Selector = selector
+		renames = append(renames, rename)
+	}
+	if len(renames) == 0 {
+		return nil, errors.New("rename map has no renames")
+	}
+	return renames, nil
+}
+
+// RenameBatch plans every rename against the unmodified sources and merges
+// the edits. Names vacated by one rename are free for another, so renames may
+// swap or rotate names. Renames that edit the same or overlapping text
+// differently, or give two declarations of a package the same name, are
+// rejected. With opts.Write, files are written only after every edit has been
+// applied in memory, and a failed write is rolled back, so an error leaves the
+// tree unchanged.
+func RenameBatch(idx *model.Index, renames []Rename, opts Options) (BatchReport, error) {
+	if idx == nil {
+		return BatchReport{}, fmt.Errorf("index is nil")
+	}
+	report := BatchReport{
+		Root:                  idx.Root,
+		Write:                 opts.Write,
+		UpdateCallsites:       opts.UpdateCallsites,
+		CrossPackageCallsites: opts.CrossPackageCallsites,
+	}
+
+	planOpts := opts
+	planOpts.Write = false
+	planOpts.vacated = map[string]bool{}
+	for _, file := range idx.Files {
+		for _, rename := range renames {
+			for _, symbol := range rename.Selector.MatchFile(file.Symbols) {
+				if symbol.Name != rename.New && supportsDeclarationRename(symbol.Kind) {
+					planOpts.vacated[targetMatchKey(symbol)] = true
+				}
+			}
+		}
+	}
+	type owned struct {
+		edit   Edit
+		rename int
+	}
+	var planned []owned
+	for i, rename := range renames {
+		single, err := RenameDeclarations(idx, rename.Selector, rename.New, planOpts)
+		if err != nil {
+			return report, fmt.Errorf("rename %s -> %s (line %d): %w", rename.Old, rename.New, rename.Line, err)
+		}
+		summary := RenameSummary{
+			Old:        rename.Old,
+			New:        rename.New,
+			Selector:   rename.Selector.Raw,
+			Engine:     single.Engine,
+			MatchCount: single.MatchCount,
+		}
+		for _, edit := range single.Edits {
+			if edit.Skipped {
+				summary.SkippedEdits++
+				report.Edits = append(report.Edits, edit)
+				continue
+			}
+			summary.PlannedEdits++
+			planned = append(planned, owned{edit: edit, rename: i})
+		}
+		report.MatchCount += single.MatchCount
+		report.Renames = append(report.Renames, summary)
+	}
+
+	// Two renames giving package-level declarations of one package the same
+	// name would collide with each other rather than with existing code.
+	declared := map[string]owned{}
+	for _, current := range planned {
+		if current.edit.Category != "declaration" || current.edit.Kind == "method_definition" {
+			continue
+		}
+		key := packageFromFilePath(current.edit.File) + "|" + current.edit.NewName
+		previous, ok := declared[key]
+		if !ok {
+			declared[key] = current
+			continue
+		}
+		if previous.edit.File == current.edit.File && previous.edit.Offset == current.edit.Offset {
+			continue
+		}
+		first, second := renames[previous.rename], renames[current.rename]
+		return report, fmt.Errorf(
+			"renames %s -> %s (line %d) and %s -> %s (line %d) both declare %s in %s",
+			first.Old, first.New, first.Line, second.Old, second.New, second.Line,
+			current.edit.NewName, packageFromFilePath(current.edit.File),
+		)
+	}
+
+	sort.SliceStable(planned, func(i, j int) bool {
+		if planned[i].edit.File == planned[j].edit.File {
+			return planned[i].edit.Offset < planned[j].edit.Offset
+		}
+		return planned[i].edit.File < planned[j].edit.File
+	})
+	editsByFile := map[string][]Edit{}
+	var files []string
+	for i, current := range planned {
+		if i > 0 {
+			previous := planned[i-1]
+			if previous.edit.File == current.edit.File && current.edit.Offset < previous.edit.Offset+len(previous.edit.OldName) {
+				if current.edit.Offset == previous.edit.Offset && current.edit.OldName == previous.edit.OldName && current.edit.NewName == previous.edit.NewName {
+					continue
+				}
+				first, second := renames[previous.rename], renames[current.rename]
+				return report, fmt.Errorf(
+					"renames %s -> %s (line %d) and %s -> %s (line %d) conflict at %s:%d:%d",
+					first.Old, first.New, first.Line, second.Old, second.New, second.Line,
+					current.edit.File, current.edit.Line, current.edit.Column,
+				)
+			}
+		}
+		if _, ok := editsByFile[current.edit.File]; !ok {
+			files = append(files, current.edit.File)
+		}
+		editsByFile[current.edit.File] = append(editsByFile[current.edit.File], current.edit)
+	}
+
+	updatedByPath := map[string][]byte{}
+	for _, file := range files {
+		edits := editsByFile[file]
+		report.PlannedEdits += len(edits)
+		if !opts.Write {
+			continue
+		}
+		absPath := filepath.Join(idx.Root, filepath.FromSlash(file))
+		source, err := os.ReadFile(absPath)
+		if err != nil {
+			return report, err
+		}
+		updated, applied, err := ApplyEdits(source, edits)
+		if err != nil {
+			return report, err
+		}
+		updatedByPath[absPath] = updated
+		report.AppliedEdits += applied
+	}
+	if opts.Write {
+		if err := WriteFiles(updatedByPath); err != nil {
+			report.AppliedEdits = 0
+			report.Transaction = TransactionRolledBack
+			return report, err
+		}
+		report.ChangedFiles = len(updatedByPath)
+		report.Transaction = TransactionCommitted
+	}
+
+	for _, file := range files {
+		for _, edit := range editsByFile[file] {
+			edit.Applied = opts.Write
+			report.Edits = append(report.Edits, edit)
+		}
+	}
+	sort.SliceStable(report.Edits, func(i, j int) bool {
+		if report.Edits[i].File == report.Edits[j].File {
+			if report.Edits[i].Line == report.Edits[j].Line {
+				return report.Edits[i].Column < report.Edits[j].Column
+			}
+			return report.Edits[i].Line < report.Edits[j].Line
+		}
+		return report.Edits[i].File < report.Edits[j].File
+	})
+	return report, nil
+}
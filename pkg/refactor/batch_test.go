@@ -0,0 +1,156 @@
+package refactor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+)
+
+func TestParseRenameMap(t *testing.T) {
+	input := `old,new,selector
+# API migration
+FetchUser,LoadUser
+
+Client,APIClient,type_definition
+Get,Fetch,"method_definition[receiver=/Store/,name=/^Get$/]"
+`
+	renames, err := ParseRenameMap(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRenameMap returned error: %v", err)
+	}
+	if len(renames) != 3 {
+		t.Fatalf("expected 3 renames, got %d", len(renames))
+	}
+	if renames[0].Old != "FetchUser" || renames[0].New != "LoadUser" || renames[0].Line != 3 {
+		t.Fatalf("unexpected first rename: %+v", renames[0])
+	}
+	if renames[0].Selector.Kind != "*" || !renames[0].Selector.NameRE.MatchString("FetchUser") || renames[0].Selector.NameRE.MatchString("FetchUsers") {
+		t.Fatalf("expected exact-name selector, got %+v", renames[0].Selector)
+	}
+	if renames[1].Selector.Kind != "type_definition" || renames[1].Selector.NameRE.String() != "^Client$" {
+		t.Fatalf("expected kind selector narrowed to name, got %+v", renames[1].Selector)
+	}
+	if renames[2].Selector.ReceiverRE == nil || renames[2].Line != 6 {
+		t.Fatalf("expected quoted selector with receiver filter, got %+v", renames[2])
+	}
+
+	for _, bad := range []string{"", "# only comments\n", "OnlyOld\n", "A,B,function_definition[\n"} {
+		if _, err := ParseRenameMap(strings.NewReader(bad)); err == nil {
+			t.Fatalf("expected error for rename map %q", bad)
+		}
+	}
+}
+
+func TestParseRenameMapAlternativesKeepOldName(t *testing.T) {
+	renames, err := ParseRenameMap(strings.NewReader("Client,APIClient,\"type_definition, function_definition[exported=true]\"\n"))
+	if err != nil {
+		t.Fatalf("ParseRenameMap returned error: %v", err)
+	}
+	alternatives := renames[0].Selector.Any
+	if len(alternatives) != 2 {
+		t.Fatalf("expected two alternatives, got %+v", renames[0].Selector)
+	}
+	for _, alternative := range alternatives {
+		if alternative.NameRE == nil || alternative.NameRE.String() != "^Client$" {
+			t.Fatalf("expected each alternative narrowed to Client, got %+v", alternative)
+		}
+	}
+}
+
+func TestRenameBatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+type Old struct{}
+
+func First() {}
+
+func Second() {
+	First()
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	renames, err := ParseRenameMap(strings.NewReader("Old,New\nFirst,Primary\nSecond,First\n"))
+	if err != nil {
+		t.Fatalf("ParseRenameMap returned error: %v", err)
+	}
+
+	dryRun, err := RenameBatch(idx, renames, Options{UpdateCallsites: true})
+	if err != nil {
+		t.Fatalf("RenameBatch returned error: %v", err)
+	}
+	if len(dryRun.Renames) != 3 || dryRun.PlannedEdits != 4 || dryRun.AppliedEdits != 0 {
+		t.Fatalf("unexpected dry-run report: %+v", dryRun)
+	}
+	after, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(after) != source {
+		t.Fatalf("dry run should not mutate file, got:\n%s", after)
+	}
+
+	report, err := RenameBatch(idx, renames, Options{UpdateCallsites: true, Write: true})
+	if err != nil {
+		t.Fatalf("RenameBatch returned error: %v", err)
+	}
+	if report.AppliedEdits != 4 || report.ChangedFiles != 1 {
+		t.Fatalf("unexpected write report: %+v", report)
+	}
+	after, err = os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	want := `package sample
+
+type New struct{}
+
+func Primary() {}
+
+func First() {
+	Primary()
+}
+`
+	if string(after) != want {
+		t.Fatalf("unexpected source after batch rename:\n%s", after)
+	}
+}
+
+func TestRenameBatch_Conflict(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := "package sample\n\nfunc Old() {}\n"
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	renames, err := ParseRenameMap(strings.NewReader("Old,A\nOld,B,function_definition\n"))
+	if err != nil {
+		t.Fatalf("ParseRenameMap returned error: %v", err)
+	}
+
+	_, err = RenameBatch(idx, renames, Options{Write: true})
+	if err == nil || !strings.Contains(err.Error(), "conflict at main.go:3") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	after, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(after) != source {
+		t.Fatalf("conflicting batch should not mutate file, got:\n%s", after)
+	}
+}
@@ -0,0 +1,107 @@
+package refactor
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// collisionNote reports why renaming target to newName would clash with an
+// existing declaration, or "" when it would not. Functions and types must not
+// collide with other package-level symbols or, in Go, with imported package
+// names and predeclared identifiers. Methods must not collide with another
+// method or field of the same receiver. Symbols in vacated, keyed by
+// targetMatchKey, are being renamed away and do not count.
+func collisionNote(idx *model.Index, target model.Symbol, newName string, vacated map[string]bool) string {
+	dir := packageFromFilePath(target.File)
+	isGo := strings.HasSuffix(target.File, ".go")
+	if isGo && target.Kind != "method_definition" && types.Universe.Lookup(newName) != nil {
+		return fmt.Sprintf("new name shadows predeclared identifier %s", newName)
+	}
+
+	for _, file := range idx.Files {
+		if packageFromFilePath(file.Path) != dir {
+			continue
+		}
+		if isGo && !strings.HasSuffix(file.Path, ".go") {
+			continue
+		}
+		for _, symbol := range file.Symbols {
+			if symbol.Name != newName || vacated[targetMatchKey(symbol)] {
+				continue
+			}
+			if target.Kind == "method_definition" {
+				if symbol.Receiver == "" || receiverType(symbol.Receiver) != receiverType(target.Receiver) {
+					continue
+				}
+			} else if symbol.Receiver != "" || !isPackageLevelKind(symbol.Kind) {
+				continue
+			}
+			return fmt.Sprintf("new name collides with %s %s at %s:%d", symbolKindLabel(symbol.Kind), symbol.Name, symbol.File, symbol.StartLine)
+		}
+		if isGo && target.Kind != "method_definition" {
+			for _, importPath := range file.Imports {
+				if path.Base(importPath) == newName {
+					return fmt.Sprintf("new name collides with imported package %q in %s", importPath, file.Path)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// receiverType reduces an indexed receiver such as "s *Store[T]" to the
+// receiver's type name.
+func receiverType(receiver string) string {
+	if i := strings.IndexByte(receiver, '['); i >= 0 {
+		receiver = receiver[:i]
+	}
+	fields := strings.Fields(receiver)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimLeft(fields[len(fields)-1], "*")
+}
+
+func isPackageLevelKind(kind string) bool {
+	switch kind {
+	case "type_definition", "variable_definition", "constant_definition":
+		return true
+	default:
+		return model.IsFunctionKind(kind)
+	}
+}
+
+func symbolKindLabel(kind string) string {
+	return strings.TrimSuffix(strings.ReplaceAll(kind, "_definition", ""), "_")
+}
+
+// shadowNote reports the declaration that would capture a reference to
+// newName at ident: a local variable, parameter, or file-level import
+// declared between the reference and package scope. It returns "" when the
+// reference would still resolve to the renamed package-level declaration.
+func shadowNote(fset *token.FileSet, info *types.Info, file *ast.File, ident *ast.Ident, newName string) string {
+	fileScope := info.Scopes[file]
+	if fileScope == nil {
+		return ""
+	}
+	scope := fileScope.Innermost(ident.Pos())
+	if scope == nil {
+		return ""
+	}
+	owner, object := scope.LookupParent(newName, ident.Pos())
+	if object == nil || owner == fileScope.Parent() || owner == types.Universe {
+		return ""
+	}
+	kind := "local"
+	if _, ok := object.(*types.PkgName); ok {
+		kind = "import"
+	}
+	pos := fset.Position(object.Pos())
+	return fmt.Sprintf("new name is shadowed by %s %s declared at line %d", kind, newName, pos.Line)
+}
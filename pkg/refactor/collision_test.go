@@ -0,0 +1,179 @@
+package refactor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/query"
+)
+
+const collisionSource = `package sample
+
+import "strings"
+
+type Store struct {
+	Name string
+}
+
+func (s Store) Label() string { return s.Name }
+
+func Load() string { return strings.ToUpper("x") }
+
+func Save() {}
+
+func Run() {
+	Save := 1
+	_ = Save
+	Load()
+}
+`
+
+func buildCollisionIndex(t *testing.T) (*model.Index, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(sourcePath, []byte(collisionSource), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	return idx, sourcePath
+}
+
+func TestRenameDeclarations_SkipsCollisions(t *testing.T) {
+	idx, sourcePath := buildCollisionIndex(t)
+
+	tests := []struct {
+		selector string
+		newName  string
+		note     string
+	}{
+		{"function_definition[name=/^Load$/]", "Save", "collides with function Save at main.go:13"},
+		{"method_definition[name=/^Label$/]", "Name", "collides with field Name at main.go:6"},
+		{"type_definition[name=/^Store$/]", "strings", `collides with imported package "strings"`},
+		{"function_definition[name=/^Save$/]", "len", "shadows predeclared identifier len"},
+		{"function_definition[name=/^Load$/]", "Save", "shadowed"},
+	}
+	for i, tt := range tests {
+		selector, err := query.ParseSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("ParseSelector returned error: %v", err)
+		}
+		opts := Options{Write: true}
+		if i == len(tests)-1 {
+			// Without the package-level Save, the local in Run still captures
+			// the renamed callsite.
+			idx = withoutSymbol(idx, "Save", "function_definition")
+			opts.UpdateCallsites = true
+		}
+		report, err := RenameDeclarations(idx, selector, tt.newName, opts)
+		if err != nil {
+			t.Fatalf("%s: RenameDeclarations returned error: %v", tt.selector, err)
+		}
+		if report.PlannedEdits != 0 || report.AppliedEdits != 0 {
+			t.Fatalf("%s -> %s: expected no edits, got %+v", tt.selector, tt.newName, report)
+		}
+		if len(report.Edits) == 0 || !report.Edits[0].Skipped || !strings.Contains(report.Edits[0].SkipNote, tt.note) {
+			t.Fatalf("%s -> %s: expected skip note containing %q, got %+v", tt.selector, tt.newName, tt.note, report.Edits)
+		}
+	}
+
+	after, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(after) != collisionSource {
+		t.Fatalf("skipped renames should not mutate file, got:\n%s", after)
+	}
+}
+
+func TestRenameDeclarations_ShadowedCallsite(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+func Load() {}
+
+func Run() {
+	Fetch := 1
+	_ = Fetch
+	Load()
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	selector, err := query.ParseSelector("function_definition[name=/^Load$/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+
+	report, err := RenameDeclarations(idx, selector, "Fetch", Options{UpdateCallsites: true, Write: true})
+	if err != nil {
+		t.Fatalf("RenameDeclarations returned error: %v", err)
+	}
+	if report.PlannedEdits != 0 {
+		t.Fatalf("expected shadowed rename to plan no edits, got %+v", report)
+	}
+	var notes []string
+	for _, edit := range report.Edits {
+		if !edit.Skipped {
+			t.Fatalf("expected only skipped edits, got %+v", edit)
+		}
+		notes = append(notes, edit.Category+": "+edit.SkipNote)
+	}
+	want := []string{
+		"declaration: new name is shadowed at a callsite",
+		"callsite: new name is shadowed by local Fetch declared at line 6",
+	}
+	if strings.Join(notes, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected skip notes:\n%s", strings.Join(notes, "\n"))
+	}
+
+	// Without callsite updates the local does not matter.
+	report, err = RenameDeclarations(idx, selector, "Fetch", Options{})
+	if err != nil {
+		t.Fatalf("RenameDeclarations returned error: %v", err)
+	}
+	if report.PlannedEdits != 1 {
+		t.Fatalf("expected declaration-only rename to plan 1 edit, got %+v", report)
+	}
+}
+
+func TestRenameBatch_DuplicateTarget(t *testing.T) {
+	idx, _ := buildCollisionIndex(t)
+	renames, err := ParseRenameMap(strings.NewReader("Load,Fetch\nSave,Fetch\n"))
+	if err != nil {
+		t.Fatalf("ParseRenameMap returned error: %v", err)
+	}
+	if _, err := RenameBatch(idx, renames, Options{}); err == nil || !strings.Contains(err.Error(), "both declare Fetch") {
+		t.Fatalf("expected duplicate target error, got %v", err)
+	}
+}
+
+func withoutSymbol(idx *model.Index, name, kind string) *model.Index {
+	clone := *idx
+	clone.Files = make([]model.FileSummary, len(idx.Files))
+	for i, file := range idx.Files {
+		symbols := make([]model.Symbol, 0, len(file.Symbols))
+		for _, symbol := range file.Symbols {
+			if symbol.Name == name && symbol.Kind == kind {
+				continue
+			}
+			symbols = append(symbols, symbol)
+		}
+		file.Symbols = symbols
+		clone.Files[i] = file
+	}
+	return &clone
+}
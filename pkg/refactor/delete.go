@@ -0,0 +1,163 @@
+package refactor
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Range is a line-aligned span of a source file. EndByte is exclusive and
+// includes the newline ending EndLine.
+type Range struct {
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
+	StartByte int `json:"start_byte"`
+	EndByte   int `json:"end_byte"`
+}
+
+// commentStyle describes the lines of a language that document the
+// declaration below them.
+type commentStyle struct {
+	// line are the prefixes of line comments.
+	line []string
+	// decorators are the prefixes of decorators, annotations, and attributes.
+	decorators []string
+	// block reports whether the language has /* ... */ comments.
+	block bool
+}
+
+// styleOf returns the comment style of language. Languages it does not know,
+// C among them, take only // and /* */ comments, so preprocessor directives
+// are never mistaken for documentation.
+func styleOf(language string) commentStyle {
+	switch language {
+	case "java", "kotlin", "scala", "groovy", "dart", "swift", "javascript", "typescript", "tsx":
+		return commentStyle{line: []string{"//"}, decorators: []string{"@"}, block: true}
+	case "rust":
+		return commentStyle{line: []string{"//"}, decorators: []string{"#["}, block: true}
+	case "php":
+		return commentStyle{line: []string{"//", "#"}, decorators: []string{"#["}, block: true}
+	case "c_sharp":
+		return commentStyle{line: []string{"//"}, decorators: []string{"["}, block: true}
+	case "cpp":
+		return commentStyle{line: []string{"//"}, decorators: []string{"[["}, block: true}
+	case "python", "elixir":
+		return commentStyle{line: []string{"#"}, decorators: []string{"@"}}
+	case "ruby", "bash", "fish", "perl", "r", "julia", "nim", "crystal", "powershell", "starlark", "gdscript", "tcl", "cmake":
+		return commentStyle{line: []string{"#"}}
+	case "lua", "luau", "teal", "haskell", "purescript", "elm", "sql", "ada":
+		return commentStyle{line: []string{"--"}}
+	case "clojure", "commonlisp", "scheme", "racket", "elisp", "fennel", "janet":
+		return commentStyle{line: []string{";"}}
+	}
+	return commentStyle{line: []string{"//"}, block: true}
+}
+
+// DeletionRange returns the lines to delete to remove the declaration on
+// lines startLine..endLine (1-based, inclusive) of a file in language: the
+// declaration itself, the comment and decorator lines directly above it, and
+// the blank lines that follow it, or the blank lines before it when it ends
+// the file. It reports false when the lines are out of range. Callers
+// should check that no other declaration shares the range's lines.
+func DeletionRange(source []byte, language string, startLine, endLine int) (Range, bool) {
+	lines := bytes.SplitAfter(source, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	if startLine < 1 || endLine < startLine || endLine > len(lines) {
+		return Range{}, false
+	}
+	first, last := startLine-1, endLine-1
+
+	style := styleOf(language)
+	isBlank := func(i int) bool { return len(bytes.TrimSpace(lines[i])) == 0 }
+	isComment := func(i int) bool {
+		trimmed := bytes.TrimSpace(lines[i])
+		if bytes.HasPrefix(trimmed, []byte("#!")) {
+			return false
+		}
+		for _, prefixes := range [][]string{style.line, style.decorators} {
+			for _, prefix := range prefixes {
+				if bytes.HasPrefix(trimmed, []byte(prefix)) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	// blockStart returns the line opening the block comment that ends on
+	// line i, or -1.
+	blockStart := func(i int) int {
+		if !style.block || !bytes.HasSuffix(bytes.TrimSpace(lines[i]), []byte("*/")) {
+			return -1
+		}
+		for j := i; j >= 0; j-- {
+			if bytes.Contains(lines[j], []byte("/*")) {
+				return j
+			}
+		}
+		return -1
+	}
+
+	for first > 0 {
+		if isComment(first - 1) {
+			first--
+		} else if start := blockStart(first - 1); start >= 0 {
+			first = start
+		} else {
+			break
+		}
+	}
+	trailing := last
+	for trailing+1 < len(lines) && isBlank(trailing+1) {
+		trailing++
+	}
+	last = trailing
+	if trailing+1 == len(lines) {
+		// Nothing follows, so drop the separating blank lines above instead.
+		for first > 0 && isBlank(first-1) {
+			first--
+		}
+	}
+
+	start := 0
+	for i := 0; i < first; i++ {
+		start += len(lines[i])
+	}
+	end := start
+	for i := first; i <= last; i++ {
+		end += len(lines[i])
+	}
+	return Range{StartLine: first + 1, EndLine: last + 1, StartByte: start, EndByte: end}, true
+}
+
+// DeletionEdits turns ranges in one file into edits for ApplyEdits. Ranges
+// nested in or overlapping an earlier, larger range are dropped, so removing
+// a declaration also removes anything declared inside it exactly once.
+func DeletionEdits(source []byte, file string, ranges []Range) []Edit {
+	sorted := append([]Range(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].StartByte == sorted[j].StartByte {
+			return sorted[i].EndByte > sorted[j].EndByte
+		}
+		return sorted[i].StartByte < sorted[j].StartByte
+	})
+
+	edits := make([]Edit, 0, len(sorted))
+	covered := -1
+	for _, r := range sorted {
+		if r.StartByte < covered || r.StartByte < 0 || r.EndByte > len(source) || r.StartByte >= r.EndByte {
+			continue
+		}
+		covered = r.EndByte
+		edits = append(edits, Edit{
+			File:     file,
+			Kind:     "declaration",
+			Category: "delete",
+			OldName:  string(source[r.StartByte:r.EndByte]),
+			Line:     r.StartLine,
+			Column:   1,
+			Offset:   r.StartByte,
+		})
+	}
+	return edits
+}
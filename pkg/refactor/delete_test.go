@@ -0,0 +1,73 @@
+package refactor
+
+import "testing"
+
+func TestDeletionRange(t *testing.T) {
+	source := []byte("package p\n\n// A does a.\n@decorated\nfunc A() {\n}\n\nfunc B() {}\n\n\n// C is last.\nfunc C() {}\n")
+
+	cases := []struct {
+		name       string
+		start, end int
+		want       Range
+	}{
+		{"comment and trailing blank", 5, 6, Range{StartLine: 3, EndLine: 7, StartByte: 11, EndByte: 49}},
+		{"blank lines after", 8, 8, Range{StartLine: 8, EndLine: 10, StartByte: 49, EndByte: 63}},
+		{"end of file takes blanks above", 12, 12, Range{StartLine: 9, EndLine: 12, StartByte: 61, EndByte: len(source)}},
+	}
+	for _, tc := range cases {
+		got, ok := DeletionRange(source, "java", tc.start, tc.end)
+		if !ok {
+			t.Fatalf("%s: DeletionRange reported false", tc.name)
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+
+	if _, ok := DeletionRange(source, "java", 12, 13); ok {
+		t.Fatal("expected false for lines past the end of the file")
+	}
+}
+
+func TestDeletionEditsSkipsNestedRanges(t *testing.T) {
+	source := []byte("class A:\n    def f(self):\n        pass\n\nclass B:\n    pass\n")
+	outer, _ := DeletionRange(source, "python", 1, 3)
+	inner, _ := DeletionRange(source, "python", 2, 3)
+
+	edits := DeletionEdits(source, "a.py", []Range{inner, outer})
+	if len(edits) != 1 {
+		t.Fatalf("expected one edit, got %+v", edits)
+	}
+	updated, applied, err := ApplyEdits(source, edits)
+	if err != nil {
+		t.Fatalf("ApplyEdits: %v", err)
+	}
+	if applied != 1 || string(updated) != "class B:\n    pass\n" {
+		t.Fatalf("unexpected result (%d applied):\n%s", applied, updated)
+	}
+}
+
+func TestDeletionRangeKeepsPreprocessorDirectives(t *testing.T) {
+	source := []byte("#include <stdio.h>\n#define LIMIT 3\nstatic int unused(void) {\n\treturn LIMIT;\n}\n\n/*\n * used is documented.\n */\n// It is called from main.\nint used(void) { return 1; }\n")
+
+	got, ok := DeletionRange(source, "c", 3, 5)
+	if !ok {
+		t.Fatal("DeletionRange reported false")
+	}
+	if got.StartLine != 3 {
+		t.Fatalf("expected the range to start at the function, got %+v", got)
+	}
+	if updated := string(source[:got.StartByte]); updated != "#include <stdio.h>\n#define LIMIT 3\n" {
+		t.Fatalf("expected the directives to stay, got %q", updated)
+	}
+
+	got, ok = DeletionRange(source, "c", 11, 11)
+	if !ok || got.StartLine != 6 {
+		t.Fatalf("expected the block and line comments above used to go with it, got %+v", got)
+	}
+
+	got, ok = DeletionRange([]byte("# Helper docs.\ndef helper():\n    pass\n"), "python", 2, 3)
+	if !ok || got.StartLine != 1 {
+		t.Fatalf("expected the Python comment to go with the function, got %+v", got)
+	}
+}
@@ -3,6 +3,7 @@ package refactor
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"go/ast"
 	"go/importer"
@@ -23,8 +24,29 @@ type Options struct {
 	UpdateCallsites       bool
 	CrossPackageCallsites bool
 	Engine                string
+	// UpdateComments and UpdateStrings also rename exact-word occurrences in
+	// comments and docstrings, and in string literals.
+	UpdateComments bool
+	UpdateStrings  bool
+
+	// vacated holds the symbols a batch renames away, which free their names
+	// for other renames in the same batch.
+	vacated map[string]bool
+	// ctx, when set, cancels a write that has not yet started committing.
+	ctx context.Context
 }
 
+// cancelled returns the error of opts.ctx once it is done, or nil.
+func (opts Options) cancelled() error {
+	if opts.ctx == nil {
+		return nil
+	}
+	return opts.ctx.Err()
+}
+
+// Edit replaces OldName at byte Offset of File. Line and Column are 1-based
+// and Column counts bytes, as go/token does; editors counting UTF-16 units
+// convert it with package textpos.
 type Edit struct {
 	File     string `json:"file"`
 	Kind     string `json:"kind"`
@@ -37,6 +59,8 @@ type Edit struct {
 	Applied  bool   `json:"applied"`
 	Skipped  bool   `json:"skipped,omitempty"`
 	SkipNote string `json:"skip_note,omitempty"`
+	// LowConfidence marks comment and string edits matched by name alone.
+	LowConfidence bool `json:"low_confidence,omitempty"`
 }
 
 type Report struct {
@@ -51,12 +75,23 @@ type Report struct {
 	PlannedEdits          int    `json:"planned_edits"`
 	PlannedDeclEdits      int    `json:"planned_declaration_edits"`
 	PlannedUseEdits       int    `json:"planned_callsite_edits"`
+	PlannedTextEdits      int    `json:"planned_text_edits"`
 	AppliedEdits          int    `json:"applied_edits"`
 	ChangedFiles          int    `json:"changed_files"`
-	Edits                 []Edit `json:"edits,omitempty"`
+	// Transaction is "committed" or "rolled_back" for --write runs.
+	Transaction string `json:"transaction,omitempty"`
+	Edits       []Edit `json:"edits,omitempty"`
 }
 
 func RenameDeclarations(idx *model.Index, selector query.Selector, newName string, opts Options) (Report, error) {
+	return RenameDeclarationsContext(context.Background(), idx, selector, newName, opts)
+}
+
+// RenameDeclarationsContext is RenameDeclarations with cancellation. When ctx
+// is done before the edits are committed, no file is written and ctx's error
+// is returned; once committing has started it runs to completion.
+func RenameDeclarationsContext(ctx context.Context, idx *model.Index, selector query.Selector, newName string, opts Options) (Report, error) {
+	opts.ctx = ctx
 	if idx == nil {
 		return Report{}, fmt.Errorf("index is nil")
 	}
@@ -85,10 +120,7 @@ func RenameDeclarations(idx *model.Index, selector query.Selector, newName strin
 
 	targetsByFile := make(map[string][]model.Symbol)
 	for _, file := range idx.Files {
-		for _, symbol := range file.Symbols {
-			if !selector.Match(symbol) {
-				continue
-			}
+		for _, symbol := range selector.MatchFile(file.Symbols) {
 			report.MatchCount++
 			if !supportsDeclarationRename(symbol.Kind) {
 				report.Edits = append(report.Edits, Edit{
@@ -118,6 +150,20 @@ func RenameDeclarations(idx *model.Index, selector query.Selector, newName strin
 				})
 				continue
 			}
+			if note := collisionNote(idx, symbol, newName, opts.vacated); note != "" {
+				report.Edits = append(report.Edits, Edit{
+					File:     symbol.File,
+					Kind:     symbol.Kind,
+					Category: "declaration",
+					OldName:  symbol.Name,
+					NewName:  newName,
+					Line:     symbol.StartLine,
+					Column:   1,
+					Skipped:  true,
+					SkipNote: note,
+				})
+				continue
+			}
 			targetsByFile[symbol.File] = append(targetsByFile[symbol.File], symbol)
 		}
 	}
@@ -162,7 +208,10 @@ func RenameDeclarations(idx *model.Index, selector query.Selector, newName strin
 			report.PlannedUseEdits++
 		}
 	}
-	report.PlannedEdits = report.PlannedDeclEdits + report.PlannedUseEdits
+	if err := planTextEdits(idx, plannedByFile, absByFile, sourceByFile, opts, &report); err != nil {
+		return report, err
+	}
+	report.PlannedEdits = report.PlannedDeclEdits + report.PlannedUseEdits + report.PlannedTextEdits
 
 	fileKeys := make([]string, 0, len(plannedByFile))
 	for file := range plannedByFile {
@@ -170,40 +219,8 @@ func RenameDeclarations(idx *model.Index, selector query.Selector, newName strin
 	}
 	sort.Strings(fileKeys)
 
-	editIndexesByFile := map[string][]int{}
-	for _, relPath := range fileKeys {
-		edits := append([]Edit(nil), plannedByFile[relPath]...)
-		sort.Slice(edits, func(i, j int) bool {
-			if edits[i].Offset == edits[j].Offset {
-				return edits[i].Category < edits[j].Category
-			}
-			return edits[i].Offset < edits[j].Offset
-		})
-
-		for _, edit := range edits {
-			report.Edits = append(report.Edits, edit)
-			editIndexesByFile[relPath] = append(editIndexesByFile[relPath], len(report.Edits)-1)
-		}
-
-		if !opts.Write || len(edits) == 0 {
-			continue
-		}
-
-		updated, applied, err := applySourceEdits(sourceByFile[relPath], edits)
-		if err != nil {
-			return report, err
-		}
-		if applied == 0 {
-			continue
-		}
-		if err := os.WriteFile(absByFile[relPath], updated, 0o644); err != nil {
-			return report, err
-		}
-		report.ChangedFiles++
-		report.AppliedEdits += applied
-		for _, idx := range editIndexesByFile[relPath] {
-			report.Edits[idx].Applied = true
-		}
+	if err := commitPlannedEdits(fileKeys, plannedByFile, absByFile, sourceByFile, opts, &report); err != nil {
+		return report, err
 	}
 
 	sort.Slice(report.Edits, func(i, j int) bool {
@@ -261,10 +278,8 @@ func selectEngine(idx *model.Index, selector query.Selector, raw string) string
 		if file.Language == "go" {
 			continue
 		}
-		for _, symbol := range file.Symbols {
-			if selector.Match(symbol) {
-				return "treesitter"
-			}
+		if len(selector.MatchFile(file.Symbols)) > 0 {
+			return "treesitter"
 		}
 	}
 	return "go"
@@ -272,10 +287,10 @@ func selectEngine(idx *model.Index, selector query.Selector, raw string) string
 
 func supportsDeclarationRename(kind string) bool {
 	switch kind {
-	case "function_definition", "method_definition", "type_definition":
+	case "method_definition", "type_definition":
 		return true
 	default:
-		return false
+		return model.IsFunctionKind(kind)
 	}
 }
 
@@ -393,8 +408,9 @@ func typeCheckGroup(group *packageGroup) (*types.Info, error) {
 	})
 
 	info := &types.Info{
-		Defs: map[*ast.Ident]types.Object{},
-		Uses: map[*ast.Ident]types.Object{},
+		Defs:   map[*ast.Ident]types.Object{},
+		Uses:   map[*ast.Ident]types.Object{},
+		Scopes: map[ast.Node]*types.Scope{},
 	}
 	config := &types.Config{
 		Importer: importer.Default(),
@@ -453,13 +469,12 @@ func planGroupEdits(group *packageGroup, newName string, withCallsites bool) ([]
 			Column:   declPos.Column,
 			Offset:   declPos.Offset,
 		}
-		key := editKey(declEdit)
-		if !seen[key] {
-			planned = append(planned, declEdit)
-			seen[key] = true
-		}
-
 		if !withCallsites {
+			key := editKey(declEdit)
+			if !seen[key] {
+				planned = append(planned, declEdit)
+				seen[key] = true
+			}
 			continue
 		}
 		if group.info == nil {
@@ -493,6 +508,10 @@ func planGroupEdits(group *packageGroup, newName string, withCallsites bool) ([]
 			continue
 		}
 
+		// A callsite captured by a local of the new name would silently
+		// change meaning, so such a target is skipped as a whole.
+		targetEdits := []Edit{declEdit}
+		var shadowed []Edit
 		for ident, useObj := range group.info.Uses {
 			if useObj != object {
 				continue
@@ -515,11 +534,29 @@ func planGroupEdits(group *packageGroup, newName string, withCallsites bool) ([]
 			if callEdit.OldName == newName {
 				continue
 			}
-			key := editKey(callEdit)
+			if target.Kind != "method_definition" {
+				if note := shadowNote(group.fset, group.info, group.astByRel[relPath], ident, newName); note != "" {
+					callEdit.Skipped = true
+					callEdit.SkipNote = note
+					shadowed = append(shadowed, callEdit)
+					continue
+				}
+			}
+			targetEdits = append(targetEdits, callEdit)
+		}
+		if len(shadowed) > 0 {
+			declEdit.Skipped = true
+			declEdit.SkipNote = "new name is shadowed at a callsite"
+			skipped = append(skipped, declEdit)
+			skipped = append(skipped, shadowed...)
+			continue
+		}
+		for _, edit := range targetEdits {
+			key := editKey(edit)
 			if seen[key] {
 				continue
 			}
-			planned = append(planned, callEdit)
+			planned = append(planned, edit)
 			seen[key] = true
 		}
 	}
@@ -644,19 +681,20 @@ func planCrossPackageCallsiteEdits(
 	sourceByRel := map[string][]byte{}
 
 	for dir, packageFiles := range filesByDir {
-		if targetDirs[dir] {
-			continue
-		}
 		if !directoryImportsTargets(packageFiles, targetByImport) {
 			continue
 		}
 
+		// A directory may hold an external test package ("package foo_test")
+		// beside its primary package; each is type-checked on its own. In a
+		// target's directory only the external test package is planned here,
+		// since the primary package's uses are same-package callsites.
 		fset := token.NewFileSet()
-		astByRel := map[string]*ast.File{}
-		relByAbs := map[string]string{}
-		packageName := ""
-
+		buckets := map[string]*crossPackageBucket{}
 		for _, fileSummary := range packageFiles {
+			if !strings.HasSuffix(fileSummary.Path, ".go") {
+				continue
+			}
 			absPath := filepath.Join(idx.Root, filepath.FromSlash(fileSummary.Path))
 			source, err := os.ReadFile(absPath)
 			if err != nil {
@@ -666,85 +704,95 @@ func planCrossPackageCallsiteEdits(
 			if err != nil {
 				return nil, skips, nil, nil, err
 			}
+			packageName := parsed.Name.Name
+			if targetDirs[dir] && !strings.HasSuffix(packageName, "_test") {
+				continue
+			}
 
-			astByRel[fileSummary.Path] = parsed
+			bucket := buckets[packageName]
+			if bucket == nil {
+				bucket = &crossPackageBucket{astByRel: map[string]*ast.File{}}
+				buckets[packageName] = bucket
+			}
+			bucket.astByRel[fileSummary.Path] = parsed
+			bucket.files = append(bucket.files, fileSummary)
 			absByRel[fileSummary.Path] = absPath
 			sourceByRel[fileSummary.Path] = source
-			relByAbs[filepath.Clean(absPath)] = fileSummary.Path
-			if packageName == "" {
-				packageName = parsed.Name.Name
-			}
-		}
-		if len(astByRel) == 0 {
-			continue
-		}
-
-		parsedFiles := make([]*ast.File, 0, len(astByRel))
-		for _, file := range astByRel {
-			parsedFiles = append(parsedFiles, file)
 		}
-		sort.Slice(parsedFiles, func(i, j int) bool {
-			left := fset.Position(parsedFiles[i].Pos()).Filename
-			right := fset.Position(parsedFiles[j].Pos()).Filename
-			return left < right
-		})
 
-		info := &types.Info{
-			Uses: map[*ast.Ident]types.Object{},
-		}
-		config := &types.Config{
-			Importer: importer.Default(),
-			Error:    func(error) {},
-		}
-		_, _ = config.Check(packageName, fset, parsedFiles, info)
-
-		for relPath, fileAST := range astByRel {
-			ast.Inspect(fileAST, func(node ast.Node) bool {
-				selector, ok := node.(*ast.SelectorExpr)
-				if !ok {
-					return true
-				}
-
-				xIdent, ok := selector.X.(*ast.Ident)
-				if !ok {
-					return true
-				}
-				obj, ok := info.Uses[xIdent].(*types.PkgName)
-				if !ok || obj.Imported() == nil {
-					return true
-				}
+		for packageName, bucket := range buckets {
+			if !directoryImportsTargets(bucket.files, targetByImport) {
+				continue
+			}
+			astByRel := bucket.astByRel
 
-				byName := targetByImport[obj.Imported().Path()]
-				if byName == nil {
-					return true
-				}
-				kind, ok := byName[selector.Sel.Name]
-				if !ok {
-					return true
-				}
+			parsedFiles := make([]*ast.File, 0, len(astByRel))
+			for _, file := range astByRel {
+				parsedFiles = append(parsedFiles, file)
+			}
+			sort.Slice(parsedFiles, func(i, j int) bool {
+				left := fset.Position(parsedFiles[i].Pos()).Filename
+				right := fset.Position(parsedFiles[j].Pos()).Filename
+				return left < right
+			})
 
-				pos := fset.Position(selector.Sel.Pos())
-				edit := Edit{
-					File:     relPath,
-					Kind:     kind,
-					Category: "callsite_cross_package",
-					OldName:  selector.Sel.Name,
-					NewName:  newName,
-					Line:     pos.Line,
-					Column:   pos.Column,
-					Offset:   pos.Offset,
-				}
-				if edit.OldName == newName {
-					return true
-				}
-				key := editKey(edit)
-				if seen[key] {
+			info := &types.Info{
+				Uses: map[*ast.Ident]types.Object{},
+			}
+			config := &types.Config{
+				Importer: importer.Default(),
+				Error:    func(error) {},
+			}
+			_, _ = config.Check(packageName, fset, parsedFiles, info)
+
+			for relPath, fileAST := range astByRel {
+				ast.Inspect(fileAST, func(node ast.Node) bool {
+					selector, ok := node.(*ast.SelectorExpr)
+					if !ok {
+						return true
+					}
+
+					xIdent, ok := selector.X.(*ast.Ident)
+					if !ok {
+						return true
+					}
+					obj, ok := info.Uses[xIdent].(*types.PkgName)
+					if !ok || obj.Imported() == nil {
+						return true
+					}
+
+					byName := targetByImport[obj.Imported().Path()]
+					if byName == nil {
+						return true
+					}
+					kind, ok := byName[selector.Sel.Name]
+					if !ok {
+						return true
+					}
+
+					pos := fset.Position(selector.Sel.Pos())
+					edit := Edit{
+						File:     relPath,
+						Kind:     kind,
+						Category: "callsite_cross_package",
+						OldName:  selector.Sel.Name,
+						NewName:  newName,
+						Line:     pos.Line,
+						Column:   pos.Column,
+						Offset:   pos.Offset,
+					}
+					if edit.OldName == newName {
+						return true
+					}
+					key := editKey(edit)
+					if seen[key] {
+						return true
+					}
+					seen[key] = true
+					edits = append(edits, edit)
 					return true
-				}
-				seen[key] = true
-				edits = append(edits, edit)
-				return true
-			})
+				})
+			}
 		}
 	}
 
@@ -760,6 +808,12 @@ func planCrossPackageCallsiteEdits(
 	return edits, skips, absByRel, sourceByRel, nil
 }
 
+// crossPackageBucket holds the files of one package clause in a directory.
+type crossPackageBucket struct {
+	files    []model.FileSummary
+	astByRel map[string]*ast.File
+}
+
 func directoryImportsTargets(files []model.FileSummary, targets map[string]map[string]string) bool {
 	for _, file := range files {
 		for _, imp := range file.Imports {
@@ -821,6 +875,13 @@ func editKey(edit Edit) string {
 	return edit.File + ":" + fmt.Sprintf("%d", edit.Offset)
 }
 
+// ApplyEdits replaces each edit's OldName at its byte Offset with NewName and
+// returns the updated source and the number of edits applied. It fails without
+// partial results when the source no longer matches an edit.
+func ApplyEdits(source []byte, edits []Edit) ([]byte, int, error) {
+	return applySourceEdits(source, append([]Edit(nil), edits...))
+}
+
 func applySourceEdits(source []byte, edits []Edit) ([]byte, int, error) {
 	if len(edits) == 0 {
 		return source, 0, nil
@@ -317,3 +317,225 @@ function Use() {
 		t.Fatalf("expected callsite rename, got:\n%s", text)
 	}
 }
+
+func TestRenameDeclarations_TreeSitterEngine_ReactComponent(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "greeting.tsx")
+	source := `export function Greeting() {
+	return <p>hi</p>;
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile greeting.tsx failed: %v", err)
+	}
+
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	selector, err := query.ParseSelector("function_definition[name=/^Greeting$/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+
+	report, err := RenameDeclarations(idx, selector, "Welcome", Options{
+		Write:  true,
+		Engine: "treesitter",
+	})
+	if err != nil {
+		t.Fatalf("RenameDeclarations returned error: %v", err)
+	}
+	if report.AppliedEdits != 1 {
+		t.Fatalf("expected the component declaration to be renamed, got %+v", report)
+	}
+	updated, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile greeting.tsx failed: %v", err)
+	}
+	if !strings.Contains(string(updated), "function Welcome()") {
+		t.Fatalf("expected declaration rename, got:\n%s", updated)
+	}
+}
+
+func TestRenameDeclarations_UpdateCommentsAndStrings(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "main.go")
+	source := `package sample
+
+import "fmt"
+
+// Load reads the config; see LoadAll, not Loaded.
+func Load() error {
+	return fmt.Errorf("Load failed")
+}
+
+func Run() {
+	/* calls Load */
+	_ = Load()
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	selector, err := query.ParseSelector("function_definition[name=/^Load$/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+
+	commentsOnly, err := RenameDeclarations(idx, selector, "Read", Options{UpdateCallsites: true, UpdateComments: true})
+	if err != nil {
+		t.Fatalf("RenameDeclarations returned error: %v", err)
+	}
+	if commentsOnly.PlannedTextEdits != 2 {
+		t.Fatalf("expected 2 comment edits, got %+v", commentsOnly)
+	}
+	for _, edit := range commentsOnly.Edits {
+		if edit.Category == CategoryComment && !edit.LowConfidence {
+			t.Fatalf("expected comment edit to be low confidence: %+v", edit)
+		}
+		if edit.Category == CategoryString {
+			t.Fatalf("unexpected string edit without UpdateStrings: %+v", edit)
+		}
+	}
+
+	report, err := RenameDeclarations(idx, selector, "Read", Options{UpdateCallsites: true, UpdateComments: true, UpdateStrings: true, Write: true})
+	if err != nil {
+		t.Fatalf("RenameDeclarations returned error: %v", err)
+	}
+	if report.PlannedTextEdits != 3 || report.PlannedEdits != 5 || report.AppliedEdits != 5 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	after, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	want := strings.NewReplacer(
+		"// Load reads", "// Read reads",
+		"func Load()", "func Read()",
+		`"Load failed"`, `"Read failed"`,
+		"calls Load", "calls Read",
+		"_ = Load()", "_ = Read()",
+	).Replace(source)
+	if string(after) != want {
+		t.Fatalf("unexpected source after rename:\n%s", after)
+	}
+}
+
+func TestRenameDeclarations_UpdateDocstrings_TreeSitter(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourcePath := filepath.Join(tmpDir, "app.py")
+	source := "def fetch():\n    \"\"\"fetch returns data.\"\"\"\n    # fetch is cheap; prefetch is not\n    return \"fetch\"\n"
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	selector, err := query.ParseSelector("function_definition[name=/^fetch$/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+
+	report, err := RenameDeclarations(idx, selector, "load", Options{UpdateComments: true, Write: true})
+	if err != nil {
+		t.Fatalf("RenameDeclarations returned error: %v", err)
+	}
+	if report.Engine != "treesitter" || report.PlannedTextEdits != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	after, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	want := "def load():\n    \"\"\"load returns data.\"\"\"\n    # load is cheap; prefetch is not\n    return \"fetch\"\n"
+	if string(after) != want {
+		t.Fatalf("unexpected source after rename:\n%s", after)
+	}
+}
+
+func TestRenameDeclarations_CrossPackageExternalTests(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"lib", "app"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0o755); err != nil {
+			t.Fatalf("MkdirAll %s failed: %v", dir, err)
+		}
+	}
+	files := map[string]string{
+		"go.mod": "module sample\n",
+		"lib/lib.go": `package lib
+
+func OldName() {}
+`,
+		"lib/lib_test.go": `package lib_test
+
+import (
+	"testing"
+
+	"sample/lib"
+)
+
+func TestOldName(t *testing.T) {
+	lib.OldName()
+}
+`,
+		"app/app.go": `package app
+
+func Run() {}
+`,
+		"app/app_test.go": `package app_test
+
+import (
+	"testing"
+
+	"sample/app"
+	"sample/lib"
+)
+
+func TestRun(t *testing.T) {
+	app.Run()
+	lib.OldName()
+}
+`,
+	}
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, filepath.FromSlash(name)), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile %s failed: %v", name, err)
+		}
+	}
+
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	selector, err := query.ParseSelector("function_definition[name=/^OldName$/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+
+	report, err := RenameDeclarations(idx, selector, "NewName", Options{
+		Write:                 true,
+		UpdateCallsites:       true,
+		CrossPackageCallsites: true,
+	})
+	if err != nil {
+		t.Fatalf("RenameDeclarations returned error: %v", err)
+	}
+	if report.PlannedDeclEdits != 1 || report.PlannedUseEdits != 2 {
+		t.Fatalf("expected 1 declaration and 2 test callsite edits, got %+v", report)
+	}
+
+	for _, name := range []string{"lib/lib_test.go", "app/app_test.go"} {
+		after, err := os.ReadFile(filepath.Join(tmpDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("ReadFile %s failed: %v", name, err)
+		}
+		if strings.Contains(string(after), "lib.OldName") || !strings.Contains(string(after), "lib.NewName()") {
+			t.Fatalf("expected %s callsite to be renamed, got:\n%s", name, after)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package refactor
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/odvcencio/gotreesitter"
+	"github.com/odvcencio/gotreesitter/grammars"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+	"github.com/odvcencio/gts-suite/pkg/textpos"
+)
+
+// Text edit categories. They are matched by name only, so they are marked
+// LowConfidence.
+const (
+	CategoryComment = "comment"
+	CategoryString  = "string"
+)
+
+// planTextEdits adds edits renaming exact-word occurrences of each renamed
+// declaration inside comments (including Python docstrings) and string
+// literals, as requested by opts.UpdateComments and opts.UpdateStrings. It
+// scans the packages of the renamed declarations and every other file
+// already being edited.
+func planTextEdits(idx *model.Index, plannedByFile map[string][]Edit, absByFile map[string]string, sourceByFile map[string][]byte, opts Options, report *Report) error {
+	if !opts.UpdateComments && !opts.UpdateStrings {
+		return nil
+	}
+
+	renames := map[string]string{}
+	dirs := map[string]bool{}
+	for _, edits := range plannedByFile {
+		for _, edit := range edits {
+			if edit.Category == "declaration" {
+				renames[edit.OldName] = edit.NewName
+				dirs[packageFromFilePath(edit.File)] = true
+			}
+		}
+	}
+	if len(renames) == 0 {
+		return nil
+	}
+
+	var files []string
+	for _, file := range idx.Files {
+		relPath := filepath.ToSlash(filepath.Clean(file.Path))
+		if dirs[packageFromFilePath(relPath)] || len(plannedByFile[relPath]) > 0 {
+			files = append(files, relPath)
+		}
+	}
+	sort.Strings(files)
+
+	for _, relPath := range files {
+		source, ok := sourceByFile[relPath]
+		absPath := filepath.Join(idx.Root, filepath.FromSlash(relPath))
+		if !ok {
+			var err error
+			source, err = os.ReadFile(absPath)
+			if err != nil {
+				return err
+			}
+		}
+		edits := textEditsInFile(relPath, source, renames, opts)
+		if len(edits) == 0 {
+			continue
+		}
+		sourceByFile[relPath] = source
+		if _, ok := absByFile[relPath]; !ok {
+			absByFile[relPath] = absPath
+		}
+		plannedByFile[relPath] = append(plannedByFile[relPath], edits...)
+		report.PlannedTextEdits += len(edits)
+	}
+	return nil
+}
+
+func textEditsInFile(relPath string, source []byte, renames map[string]string, opts Options) []Edit {
+	if grammars.DetectLanguage(relPath) == nil {
+		return nil
+	}
+	bound, err := grammars.ParseFile(relPath, source)
+	if err != nil {
+		return nil
+	}
+	defer bound.Release()
+	root := bound.RootNode()
+	if root == nil {
+		return nil
+	}
+
+	lines := textpos.New(source)
+	var edits []Edit
+	var visit func(node *gotreesitter.Node)
+	visit = func(node *gotreesitter.Node) {
+		category := textCategory(bound, node)
+		if category == CategoryComment && !opts.UpdateComments || category == CategoryString && !opts.UpdateStrings {
+			return
+		}
+		if category != "" {
+			edits = append(edits, wordEdits(relPath, source, lines, int(node.StartByte()), int(node.EndByte()), category, renames)...)
+			return
+		}
+		for i := 0; i < node.ChildCount(); i++ {
+			if child := node.Child(i); child != nil {
+				visit(child)
+			}
+		}
+	}
+	visit(root)
+	return edits
+}
+
+// textCategory classifies node as a comment or string literal. A string
+// standing alone as a statement is a docstring and counts as a comment.
+func textCategory(bound *gotreesitter.BoundTree, node *gotreesitter.Node) string {
+	nodeType := bound.NodeType(node)
+	switch {
+	case strings.Contains(nodeType, "comment"):
+		return CategoryComment
+	case strings.Contains(nodeType, "string") || nodeType == "template_literal" || nodeType == "heredoc_body":
+		if parent := node.Parent(); parent != nil {
+			switch bound.NodeType(parent) {
+			case "block", "module":
+				return CategoryComment
+			case "expression_statement":
+				if parent.NamedChildCount() == 1 {
+					return CategoryComment
+				}
+			}
+		}
+		return CategoryString
+	}
+	return ""
+}
+
+func wordEdits(relPath string, source []byte, lines *textpos.Map, start, end int, category string, renames map[string]string) []Edit {
+	text := string(source[start:end])
+	var edits []Edit
+	for oldName, newName := range renames {
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], oldName)
+			if i < 0 {
+				break
+			}
+			at := offset + i
+			offset = at + len(oldName)
+			if at > 0 && isIdentifierByte(text[at-1]) || offset < len(text) && isIdentifierByte(text[offset]) {
+				continue
+			}
+			line, column := lines.Position(start+at, textpos.UTF8)
+			edits = append(edits, Edit{
+				File:          relPath,
+				Kind:          "text",
+				Category:      category,
+				OldName:       oldName,
+				NewName:       newName,
+				Line:          line + 1,
+				Column:        column + 1,
+				Offset:        start + at,
+				LowConfidence: true,
+			})
+		}
+	}
+	return edits
+}
+
+func isIdentifierByte(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
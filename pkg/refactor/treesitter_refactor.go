@@ -11,6 +11,7 @@ import (
 	"github.com/odvcencio/gotreesitter"
 	"github.com/odvcencio/gotreesitter/grammars"
 
+	"github.com/odvcencio/gts-suite/pkg/lang/treesitter"
 	"github.com/odvcencio/gts-suite/pkg/model"
 	"github.com/odvcencio/gts-suite/pkg/query"
 )
@@ -33,7 +34,7 @@ func renameDeclarationsTreeSitter(idx *model.Index, selector query.Selector, new
 		CrossPackageCallsites: opts.CrossPackageCallsites,
 	}
 
-	targets := collectRenameTargets(idx, selector, newName, &report)
+	targets := collectRenameTargets(idx, selector, newName, opts, &report)
 	if len(targets.byFile) == 0 {
 		return report, nil
 	}
@@ -45,6 +46,10 @@ func renameDeclarationsTreeSitter(idx *model.Index, selector query.Selector, new
 
 	appendUnmatchedTargets(targets, targetMatched, newName, &report)
 
+	if err := planTextEdits(idx, plannedByFile, absByFile, sourceByFile, opts, &report); err != nil {
+		return report, err
+	}
+
 	if err := applyPlannedEdits(plannedByFile, absByFile, sourceByFile, opts, &report); err != nil {
 		return report, err
 	}
@@ -53,7 +58,7 @@ func renameDeclarationsTreeSitter(idx *model.Index, selector query.Selector, new
 	return report, nil
 }
 
-func collectRenameTargets(idx *model.Index, selector query.Selector, newName string, report *Report) renameTargets {
+func collectRenameTargets(idx *model.Index, selector query.Selector, newName string, opts Options, report *Report) renameTargets {
 	targets := renameTargets{
 		byFile:      map[string][]model.Symbol{},
 		kindsByName: map[string]string{},
@@ -61,10 +66,7 @@ func collectRenameTargets(idx *model.Index, selector query.Selector, newName str
 	}
 
 	for _, file := range idx.Files {
-		for _, symbol := range file.Symbols {
-			if !selector.Match(symbol) {
-				continue
-			}
+		for _, symbol := range selector.MatchFile(file.Symbols) {
 			report.MatchCount++
 			if !supportsDeclarationRename(symbol.Kind) {
 				report.Edits = append(report.Edits, Edit{
@@ -94,6 +96,20 @@ func collectRenameTargets(idx *model.Index, selector query.Selector, newName str
 				})
 				continue
 			}
+			if note := collisionNote(idx, symbol, newName, opts.vacated); note != "" {
+				report.Edits = append(report.Edits, Edit{
+					File:     symbol.File,
+					Kind:     symbol.Kind,
+					Category: "declaration",
+					OldName:  symbol.Name,
+					NewName:  newName,
+					Line:     symbol.StartLine,
+					Column:   1,
+					Skipped:  true,
+					SkipNote: note,
+				})
+				continue
+			}
 			targets.byFile[symbol.File] = append(targets.byFile[symbol.File], symbol)
 			targets.kindsByName[symbol.Name] = symbol.Kind
 			targets.dirs[packageFromFilePath(symbol.File)] = true
@@ -129,7 +145,7 @@ func planRenameEdits(idx *model.Index, targets renameTargets, newName string, op
 		if !ok {
 			continue
 		}
-		entry.TagsQuery = grammars.ResolveTagsQuery(entry)
+		entry.TagsQuery = treesitter.ResolveTagsQuery(entry)
 		if strings.TrimSpace(entry.TagsQuery) == "" {
 			continue
 		}
@@ -171,7 +187,7 @@ func collectTagEdits(tags []gotreesitter.Tag, relPath string, hasTargets bool, t
 				continue
 			}
 			for _, target := range targets.byFile[relPath] {
-				if target.Kind != kind || target.Name != name {
+				if !sameDeclarationKind(target.Kind, kind) || target.Name != name {
 					continue
 				}
 				if line < target.StartLine || line > target.EndLine {
@@ -241,48 +257,13 @@ func appendUnmatchedTargets(targets renameTargets, targetMatched map[string]bool
 }
 
 func applyPlannedEdits(plannedByFile map[string][]Edit, absByFile map[string]string, sourceByFile map[string][]byte, opts Options, report *Report) error {
-	report.PlannedEdits = report.PlannedDeclEdits + report.PlannedUseEdits
+	report.PlannedEdits = report.PlannedDeclEdits + report.PlannedUseEdits + report.PlannedTextEdits
 	fileKeys := make([]string, 0, len(plannedByFile))
 	for file := range plannedByFile {
 		fileKeys = append(fileKeys, file)
 	}
 	sort.Strings(fileKeys)
-
-	editIndexesByFile := map[string][]int{}
-	for _, relPath := range fileKeys {
-		edits := append([]Edit(nil), plannedByFile[relPath]...)
-		sort.Slice(edits, func(i, j int) bool {
-			if edits[i].Offset == edits[j].Offset {
-				return edits[i].Category < edits[j].Category
-			}
-			return edits[i].Offset < edits[j].Offset
-		})
-
-		for _, edit := range edits {
-			report.Edits = append(report.Edits, edit)
-			editIndexesByFile[relPath] = append(editIndexesByFile[relPath], len(report.Edits)-1)
-		}
-
-		if !opts.Write || len(edits) == 0 {
-			continue
-		}
-		updated, applied, err := applySourceEdits(sourceByFile[relPath], edits)
-		if err != nil {
-			return err
-		}
-		if applied == 0 {
-			continue
-		}
-		if err := os.WriteFile(absByFile[relPath], updated, 0o644); err != nil {
-			return err
-		}
-		report.ChangedFiles++
-		report.AppliedEdits += applied
-		for _, idx := range editIndexesByFile[relPath] {
-			report.Edits[idx].Applied = true
-		}
-	}
-	return nil
+	return commitPlannedEdits(fileKeys, plannedByFile, absByFile, sourceByFile, opts, report)
 }
 
 func sortReportEdits(report *Report) {
@@ -352,6 +333,12 @@ func declarationKindFromTag(tagKind string) (string, bool) {
 	}
 }
 
+// sameDeclarationKind reports whether a symbol of kind is declared by a tag
+// of tagKind. Tags name React components and hooks function_definition.
+func sameDeclarationKind(kind, tagKind string) bool {
+	return kind == tagKind || model.IsFunctionKind(kind) && model.IsFunctionKind(tagKind)
+}
+
 func targetMatchKey(symbol model.Symbol) string {
 	return symbol.File + "|" + symbol.Kind + "|" + symbol.Name + "|" + strconv.Itoa(symbol.StartLine)
 }
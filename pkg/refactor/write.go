@@ -0,0 +1,162 @@
+package refactor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Transaction outcomes recorded on reports of --write runs.
+const (
+	TransactionCommitted  = "committed"
+	TransactionRolledBack = "rolled_back"
+)
+
+// renameFile is os.Rename, replaceable in tests to inject commit failures.
+var renameFile = os.Rename
+
+// WriteFiles replaces every file in contents or none of them. All new
+// contents are first staged in temporary siblings; each target is then moved
+// aside to a backup and its staged copy renamed into place. If any step fails,
+// files already replaced are restored from their backups before returning
+// the error.
+func WriteFiles(contents map[string][]byte) error {
+	paths := make([]string, 0, len(contents))
+	for path := range contents {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	staged := make(map[string]string, len(paths))
+	removeStaged := func() {
+		for _, tmp := range staged {
+			_ = os.Remove(tmp)
+		}
+	}
+	for _, path := range paths {
+		tmp, err := stageFile(path, contents[path])
+		if err != nil {
+			removeStaged()
+			return fmt.Errorf("stage %s: %w", path, err)
+		}
+		staged[path] = tmp
+	}
+
+	type committed struct{ path, backup string }
+	done := make([]committed, 0, len(paths))
+	rollback := func(cause error) error {
+		removeStaged()
+		var failed []error
+		for i := len(done) - 1; i >= 0; i-- {
+			if err := renameFile(done[i].backup, done[i].path); err != nil {
+				failed = append(failed, fmt.Errorf("restore %s from %s: %w", done[i].path, done[i].backup, err))
+			}
+		}
+		if len(failed) > 0 {
+			return errors.Join(append([]error{cause}, failed...)...)
+		}
+		return fmt.Errorf("%w (rolled back %d file(s); no files changed)", cause, len(done))
+	}
+	for _, path := range paths {
+		backup := backupPath(path)
+		if err := renameFile(path, backup); err != nil {
+			return rollback(fmt.Errorf("back up %s: %w", path, err))
+		}
+		if err := renameFile(staged[path], path); err != nil {
+			if restoreErr := renameFile(backup, path); restoreErr != nil {
+				return rollback(errors.Join(fmt.Errorf("write %s: %w", path, err), fmt.Errorf("restore %s from %s: %w", path, backup, restoreErr)))
+			}
+			return rollback(fmt.Errorf("write %s: %w", path, err))
+		}
+		delete(staged, path)
+		done = append(done, committed{path: path, backup: backup})
+	}
+	for _, entry := range done {
+		_ = os.Remove(entry.backup)
+	}
+	return nil
+}
+
+// stageFile writes data to a temporary file beside path with path's
+// permissions and returns the temporary file's name.
+func stageFile(path string, data []byte) (string, error) {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".gts-*")
+	if err != nil {
+		return "", err
+	}
+	_, writeErr := tmp.Write(data)
+	syncErr := tmp.Sync()
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, syncErr, closeErr, os.Chmod(tmp.Name(), mode)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+func backupPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".gts-orig")
+}
+
+// commitPlannedEdits records the planned edits of each file on report and,
+// with opts.Write, applies them all in memory before writing the changed
+// files in one transaction.
+func commitPlannedEdits(fileKeys []string, plannedByFile map[string][]Edit, absByFile map[string]string, sourceByFile map[string][]byte, opts Options, report *Report) error {
+	editIndexesByFile := map[string][]int{}
+	updatedByPath := map[string][]byte{}
+	applied := 0
+	for _, relPath := range fileKeys {
+		edits := append([]Edit(nil), plannedByFile[relPath]...)
+		sort.Slice(edits, func(i, j int) bool {
+			if edits[i].Offset == edits[j].Offset {
+				return edits[i].Category < edits[j].Category
+			}
+			return edits[i].Offset < edits[j].Offset
+		})
+
+		for _, edit := range edits {
+			report.Edits = append(report.Edits, edit)
+			editIndexesByFile[relPath] = append(editIndexesByFile[relPath], len(report.Edits)-1)
+		}
+
+		if !opts.Write || len(edits) == 0 {
+			continue
+		}
+		updated, count, err := applySourceEdits(sourceByFile[relPath], edits)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			delete(editIndexesByFile, relPath)
+			continue
+		}
+		updatedByPath[absByFile[relPath]] = updated
+		applied += count
+	}
+	if !opts.Write {
+		return nil
+	}
+	if err := opts.cancelled(); err != nil {
+		return err
+	}
+
+	if err := WriteFiles(updatedByPath); err != nil {
+		report.Transaction = TransactionRolledBack
+		return err
+	}
+	report.Transaction = TransactionCommitted
+	report.ChangedFiles = len(updatedByPath)
+	report.AppliedEdits = applied
+	for _, indexes := range editIndexesByFile {
+		for _, idx := range indexes {
+			report.Edits[idx].Applied = true
+		}
+	}
+	return nil
+}
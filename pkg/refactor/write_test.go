@@ -0,0 +1,151 @@
+package refactor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/index"
+	"github.com/odvcencio/gts-suite/pkg/query"
+)
+
+func TestWriteFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	first := filepath.Join(tmpDir, "a.go")
+	second := filepath.Join(tmpDir, "b.go")
+	for _, path := range []string{first, second} {
+		if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	if err := WriteFiles(map[string][]byte{first: []byte("new a"), second: []byte("new b")}); err != nil {
+		t.Fatalf("WriteFiles returned error: %v", err)
+	}
+	for path, want := range map[string]string{first: "new a", second: "new b"} {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s = %q, want %q", path, got, want)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Fatalf("%s mode = %v, want 0600", path, info.Mode().Perm())
+		}
+	}
+	assertOnlyFiles(t, tmpDir, "a.go", "b.go")
+}
+
+func TestWriteFiles_RollsBackOnFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	first := filepath.Join(tmpDir, "a.go")
+	second := filepath.Join(tmpDir, "b.go")
+	for _, path := range []string{first, second} {
+		if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	original := renameFile
+	defer func() { renameFile = original }()
+	renameFile = func(from, to string) error {
+		if to == second && strings.Contains(from, ".gts-") && !strings.HasSuffix(from, ".gts-orig") {
+			return errors.New("permission denied")
+		}
+		return original(from, to)
+	}
+
+	err := WriteFiles(map[string][]byte{first: []byte("new a"), second: []byte("new b")})
+	if err == nil || !strings.Contains(err.Error(), "rolled back 1 file(s)") {
+		t.Fatalf("expected rolled back error, got %v", err)
+	}
+	for _, path := range []string{first, second} {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if string(got) != "old" {
+			t.Fatalf("%s = %q, want original contents", path, got)
+		}
+	}
+	assertOnlyFiles(t, tmpDir, "a.go", "b.go")
+}
+
+func TestRenameDeclarations_WriteFailureLeavesTreeUnchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	sources := map[string]string{
+		"a.go": "package sample\n\nfunc Old() {}\n",
+		"b.go": "package sample\n\nfunc use() { Old() }\n",
+	}
+	for name, source := range sources {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(source), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	idx, err := index.NewBuilder().BuildPath(tmpDir)
+	if err != nil {
+		t.Fatalf("BuildPath returned error: %v", err)
+	}
+	selector, err := query.ParseSelector("function_definition[name=/^Old$/]")
+	if err != nil {
+		t.Fatalf("ParseSelector returned error: %v", err)
+	}
+
+	original := renameFile
+	defer func() { renameFile = original }()
+	renameFile = func(from, to string) error {
+		if filepath.Base(to) == "b.go" && !strings.HasSuffix(from, ".gts-orig") {
+			return errors.New("disk full")
+		}
+		return original(from, to)
+	}
+
+	report, err := RenameDeclarations(idx, selector, "New", Options{Write: true, UpdateCallsites: true})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected write failure, got %v", err)
+	}
+	if report.Transaction != TransactionRolledBack || report.AppliedEdits != 0 || report.ChangedFiles != 0 {
+		t.Fatalf("unexpected report after failed write: %+v", report)
+	}
+	for name, source := range sources {
+		got, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if string(got) != source {
+			t.Fatalf("%s changed after rollback:\n%s", name, got)
+		}
+	}
+	assertOnlyFiles(t, tmpDir, "a.go", "b.go")
+
+	renameFile = original
+	report, err = RenameDeclarations(idx, selector, "New", Options{Write: true, UpdateCallsites: true})
+	if err != nil {
+		t.Fatalf("RenameDeclarations returned error: %v", err)
+	}
+	if report.Transaction != TransactionCommitted || report.ChangedFiles != 2 || report.AppliedEdits != 2 {
+		t.Fatalf("unexpected report after write: %+v", report)
+	}
+}
+
+func assertOnlyFiles(t *testing.T, dir string, want ...string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("files in %s = %v, want %v", dir, got, want)
+	}
+}
@@ -0,0 +1,135 @@
+package report
+
+import (
+	"github.com/odvcencio/gts-suite/internal/lint"
+	"github.com/odvcencio/gts-suite/internal/snapshot"
+	"github.com/odvcencio/gts-suite/pkg/capa"
+	"github.com/odvcencio/gts-suite/pkg/similarity"
+	"github.com/odvcencio/gts-suite/pkg/yara"
+)
+
+// LintReport is printed by gts analyze lint --json.
+type LintReport struct {
+	Rules          []lint.Rule          `json:"rules,omitempty"`
+	Patterns       []lint.QueryPattern  `json:"patterns,omitempty"`
+	ThresholdRules []lint.ThresholdRule `json:"threshold_rules,omitempty"`
+	Violations     []lint.Violation     `json:"violations,omitempty"`
+	Count          int                  `json:"count"`
+	// Fix reports what --fix applied or, with --dry-run, would apply.
+	Fix *lint.FixReport `json:"fix,omitempty"`
+}
+
+// CapaReport is printed by gts analyze capa --json.
+type CapaReport struct {
+	Count   int          `json:"count"`
+	Matches []capa.Match `json:"matches,omitempty"`
+}
+
+// Capability is a capability rule that matched, flattened for MCP clients.
+type Capability struct {
+	Name        string   `json:"name"`
+	AttackID    string   `json:"attack_id"`
+	Category    string   `json:"category"`
+	Confidence  string   `json:"confidence"`
+	Description string   `json:"description"`
+	MatchedAPIs []string `json:"matched_apis"`
+	Files       []string `json:"files"`
+	Functions   []string `json:"functions"`
+}
+
+// SimilarityReport is printed by gts analyze similarity --json.
+type SimilarityReport struct {
+	Threshold float64           `json:"threshold"`
+	Method    string            `json:"method"`
+	Count     int               `json:"count"`
+	Pairs     []similarity.Pair `json:"pairs,omitempty"`
+}
+
+// SimilarPair is a pair of similar functions, flattened for MCP clients.
+// Lines are "start-end", or a single line number.
+type SimilarPair struct {
+	FileA  string  `json:"file_a"`
+	NameA  string  `json:"name_a"`
+	LinesA string  `json:"lines_a"`
+	FileB  string  `json:"file_b"`
+	NameB  string  `json:"name_b"`
+	LinesB string  `json:"lines_b"`
+	Score  float64 `json:"score"`
+	Method string  `json:"method"`
+}
+
+// DuplicationReport is printed by gts analyze duplication --json.
+type DuplicationReport struct {
+	Threshold float64           `json:"threshold"`
+	Limit     int               `json:"limit"`
+	Count     int               `json:"count"`
+	Pairs     []similarity.Pair `json:"pairs,omitempty"`
+}
+
+// ExecutiveReport is printed by gts analyze report --json.
+type ExecutiveReport struct {
+	// Codebase overview
+	Files        int            `json:"files"`
+	Languages    map[string]int `json:"languages"`
+	TotalSymbols int            `json:"total_symbols"`
+	GeneratedPct int            `json:"generated_pct"`
+
+	// Complexity
+	FunctionCount int `json:"function_count"`
+	CyclomaticMax int `json:"cyclomatic_max"`
+	CyclomaticP90 int `json:"cyclomatic_p90"`
+	CognitiveMax  int `json:"cognitive_max"`
+
+	// Architecture
+	BoundaryViolations int `json:"boundary_violations"`
+	ImportCycles       int `json:"import_cycles"`
+
+	// Security
+	Capabilities int `json:"capabilities"`
+
+	// Dead code
+	DeadFunctions int `json:"dead_functions"`
+
+	// Hotspots (top 5)
+	Hotspots []ExecutiveHotspot `json:"hotspots,omitempty"`
+
+	// Team breakdown (only when --by-team is set)
+	Teams map[string]*TeamMetrics `json:"teams,omitempty"`
+}
+
+// ExecutiveHotspot is a simplified hotspot record for the executive report.
+type ExecutiveHotspot struct {
+	File       string  `json:"file"`
+	Name       string  `json:"name"`
+	Cyclomatic int     `json:"cyclomatic"`
+	Score      float64 `json:"score"`
+}
+
+// TeamMetrics holds per-team breakdown of report metrics.
+type TeamMetrics struct {
+	Files              int `json:"files"`
+	Functions          int `json:"functions"`
+	CyclomaticMax      int `json:"cyclomatic_max"`
+	CognitiveMax       int `json:"cognitive_max"`
+	DeadFunctions      int `json:"dead_functions"`
+	BoundaryViolations int `json:"boundary_violations"`
+	Capabilities       int `json:"capabilities"`
+}
+
+// ExecutiveComparison is printed by gts analyze report --compare --json.
+type ExecutiveComparison struct {
+	Current  ExecutiveReport `json:"current"`
+	Baseline ExecutiveReport `json:"baseline"`
+}
+
+// YaraReport is printed by gts transform yara --json.
+type YaraReport struct {
+	Count int                  `json:"count"`
+	Rules []yara.GeneratedRule `json:"rules,omitempty"`
+}
+
+// SnapshotSaveReport is printed by gts snapshot save --json.
+type SnapshotSaveReport struct {
+	Snapshot snapshot.Snapshot   `json:"snapshot"`
+	Pruned   []snapshot.Snapshot `json:"pruned,omitempty"`
+}
@@ -0,0 +1,11 @@
+package report
+
+import "github.com/odvcencio/gts-suite/internal/exits"
+
+// ExitsReport is printed by gts audit exits --json. Violations counts the
+// findings no allow rule covers; Findings lists only those unless --all is
+// set.
+type ExitsReport struct {
+	Violations int             `json:"violations"`
+	Findings   []exits.Finding `json:"findings"`
+}
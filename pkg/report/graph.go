@@ -0,0 +1,123 @@
+package report
+
+import (
+	"github.com/odvcencio/gts-suite/pkg/testmap"
+	"github.com/odvcencio/gts-suite/pkg/xref"
+)
+
+// CallgraphReport is printed by gts graph calls --json. The command streams
+// it one edge at a time rather than encoding this type, so large walks are
+// never held in memory as a whole.
+type CallgraphReport struct {
+	Roots               []xref.Definition       `json:"roots"`
+	Nodes               []xref.Definition       `json:"nodes"`
+	Edges               []xref.MaterializedEdge `json:"edges"`
+	Depth               int                     `json:"depth"`
+	Reverse             bool                    `json:"reverse"`
+	UnresolvedCallCount int                     `json:"unresolved_call_count"`
+}
+
+// CallgraphCountReport is printed by gts graph calls --json --count.
+type CallgraphCountReport struct {
+	RootCount           int `json:"root_count"`
+	NodeCount           int `json:"node_count"`
+	EdgeCount           int `json:"edge_count"`
+	UnresolvedCallCount int `json:"unresolved_call_count"`
+}
+
+// PackageCallgraphReport is printed by gts graph calls --aggregate package
+// --json.
+type PackageCallgraphReport struct {
+	Roots    []xref.Definition `json:"roots,omitempty"`
+	Depth    int               `json:"depth"`
+	Reverse  bool              `json:"reverse"`
+	Packages xref.PackageGraph `json:"packages"`
+}
+
+// PackageCallgraphCountReport is printed by gts graph calls --aggregate
+// package --json --count.
+type PackageCallgraphCountReport struct {
+	PackageCount int `json:"package_count"`
+	EdgeCount    int `json:"edge_count"`
+}
+
+// FaninEntry is a definition ranked by gts graph fanin.
+type FaninEntry struct {
+	Name      string  `json:"name"`
+	Signature string  `json:"signature,omitempty"`
+	File      string  `json:"file"`
+	Kind      string  `json:"kind"`
+	StartLine int     `json:"start_line"`
+	EndLine   int     `json:"end_line"`
+	Incoming  int     `json:"incoming"`
+	Outgoing  int     `json:"outgoing"`
+	Ratio     float64 `json:"ratio"`
+	Generated string  `json:"generated,omitempty"`
+}
+
+// FaninReport is printed by gts graph fanin --json.
+type FaninReport struct {
+	Count   int          `json:"count"`
+	SortBy  string       `json:"sort_by"`
+	Entries []FaninEntry `json:"entries,omitempty"`
+}
+
+// UnresolvedReport is printed by gts graph unresolved --json. Total counts
+// every unresolved call before filters and limits.
+type UnresolvedReport struct {
+	Count   int                   `json:"count"`
+	Total   int                   `json:"total"`
+	Entries []xref.UnresolvedCall `json:"entries,omitempty"`
+}
+
+// UnresolvedCountReport is printed by gts graph unresolved --json --count.
+type UnresolvedCountReport struct {
+	Count int `json:"count"`
+	Total int `json:"total"`
+}
+
+// UnusedField is a field gts graph unused-fields found no reads of.
+type UnusedField struct {
+	File      string `json:"file"`
+	Package   string `json:"package"`
+	Container string `json:"container,omitempty"`
+	Name      string `json:"name"`
+	Signature string `json:"signature,omitempty"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Exported  bool   `json:"exported,omitempty"`
+	Writes    int    `json:"writes"`
+}
+
+// UnusedFieldsReport is printed by gts graph unused-fields --json.
+type UnusedFieldsReport struct {
+	Scanned   int           `json:"scanned"`
+	Count     int           `json:"count"`
+	Truncated bool          `json:"truncated,omitempty"`
+	Matches   []UnusedField `json:"matches,omitempty"`
+}
+
+// UnusedFieldsCountReport is printed by gts graph unused-fields --json
+// --count.
+type UnusedFieldsCountReport struct {
+	Count     int  `json:"count"`
+	Scanned   int  `json:"scanned"`
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// TestmapReport is printed by gts graph testmap --json.
+type TestmapReport struct {
+	Mappings      []testmap.TestMapping `json:"mappings"`
+	TestedCount   int                   `json:"tested_count"`
+	UntestedCount int                   `json:"untested_count"`
+	Coverage      float64               `json:"coverage"`
+	Truncated     bool                  `json:"truncated,omitempty"`
+}
+
+// TestmapCountReport is printed by gts graph testmap --json --count.
+type TestmapCountReport struct {
+	TestedCount   int     `json:"tested_count"`
+	UntestedCount int     `json:"untested_count"`
+	Coverage      float64 `json:"coverage"`
+	Truncated     bool    `json:"truncated,omitempty"`
+}
@@ -0,0 +1,26 @@
+package report
+
+import "github.com/odvcencio/gts-suite/pkg/model"
+
+// MapSymbol is the short form of a top-level symbol in the summary detail
+// level of the MCP gts_map tool.
+type MapSymbol struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Line int    `json:"line"`
+}
+
+// MapFile is one file of the MCP gts_map tool. TopLevel is set at the
+// summary detail level, Imports and Symbols at symbols and full, and
+// References at full.
+type MapFile struct {
+	Path           string            `json:"path"`
+	Language       string            `json:"language"`
+	Imports        []string          `json:"imports,omitempty"`
+	TopLevel       []MapSymbol       `json:"top_level,omitempty"`
+	Symbols        []model.Symbol    `json:"symbols,omitempty"`
+	References     []model.Reference `json:"references,omitempty"`
+	ImportCount    int               `json:"import_count"`
+	SymbolCount    int               `json:"symbol_count"`
+	ReferenceCount int               `json:"reference_count"`
+}
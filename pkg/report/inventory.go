@@ -0,0 +1,46 @@
+package report
+
+import (
+	"github.com/odvcencio/gts-suite/internal/concurrency"
+	"github.com/odvcencio/gts-suite/internal/configusage"
+	"github.com/odvcencio/gts-suite/internal/routes"
+	"github.com/odvcencio/gts-suite/internal/sqlusage"
+)
+
+// RoutesReport is printed by gts routes --json. Resolved counts the routes
+// whose handler resolved to a definition.
+type RoutesReport struct {
+	Count    int            `json:"count"`
+	Resolved int            `json:"resolved"`
+	Routes   []routes.Route `json:"routes,omitempty"`
+}
+
+// RoutesCountReport is printed by gts routes --json --count.
+type RoutesCountReport struct {
+	Count    int `json:"count"`
+	Resolved int `json:"resolved"`
+}
+
+// ConcurrencyReport is printed by gts concurrency --json. Count is the
+// number of concurrency sites across all packages.
+type ConcurrencyReport struct {
+	Count    int                          `json:"count"`
+	Packages []concurrency.PackageSummary `json:"packages"`
+}
+
+// ConfigUsageReport is printed by gts config-usage --json.
+type ConfigUsageReport struct {
+	Count int               `json:"count"`
+	Keys  []configusage.Key `json:"keys"`
+}
+
+// SQLCountReport is printed by gts sql --json --count.
+type SQLCountReport struct {
+	Queries int `json:"queries"`
+	Tables  int `json:"tables"`
+}
+
+// SQLTablesReport is printed by gts sql --tables --json.
+type SQLTablesReport struct {
+	Tables []sqlusage.TableUsage `json:"tables"`
+}
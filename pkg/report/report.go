@@ -0,0 +1,207 @@
+// Package report defines the JSON documents gts commands print with --json,
+// so downstream tools can decode them with shared Go types and validate them
+// against the JSON Schemas that Schema derives from those types.
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/odvcencio/gts-suite/pkg/refactor"
+)
+
+// SchemaVersion is the version of the JSON documents gts prints. It is bumped
+// when a field is removed, renamed, or changes type; adding a field does not
+// bump it. Every top-level object carries it as "schema_version".
+const SchemaVersion = 1
+
+// Marshal encodes v as indented JSON followed by a newline. When v encodes
+// as an object, "schema_version" is added as its first field; arrays and
+// scalars are encoded unchanged.
+func Marshal(v any) ([]byte, error) {
+	compact, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	compact = withSchemaVersion(compact)
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact, "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+func withSchemaVersion(data []byte) []byte {
+	if len(data) < 2 || data[0] != '{' {
+		return data
+	}
+	field := `"schema_version":` + strconv.Itoa(SchemaVersion)
+	out := make([]byte, 0, len(data)+len(field)+1)
+	out = append(out, '{')
+	out = append(out, field...)
+	if data[1] != '}' {
+		out = append(out, ',')
+	}
+	return append(out, data[1:]...)
+}
+
+// SymbolMatch is a symbol matched by gts search grep.
+type SymbolMatch struct {
+	File      string `json:"file"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Signature string `json:"signature,omitempty"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	// StartColumn is the 1-based byte column StartLine's match begins at.
+	StartColumn int `json:"start_column,omitempty"`
+	// Cell and CellLine place a match in a notebook: the 1-based code cell
+	// StartLine falls in and the line within that cell.
+	Cell     int `json:"cell,omitempty"`
+	CellLine int `json:"cell_line,omitempty"`
+}
+
+// GrepReport is printed by gts search grep --json.
+type GrepReport struct {
+	Mode      string        `json:"mode"`
+	Matches   []SymbolMatch `json:"matches"`
+	Count     int           `json:"count"`
+	Truncated bool          `json:"truncated,omitempty"`
+}
+
+// GrepCountReport is printed by gts search grep --json --count.
+type GrepCountReport struct {
+	Mode      string `json:"mode"`
+	Count     int    `json:"count"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// ReferenceMatch is a reference found by gts search refs.
+type ReferenceMatch struct {
+	File        string `json:"file"`
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	StartColumn int    `json:"start_column"`
+	EndColumn   int    `json:"end_column"`
+	Qualifier   string `json:"qualifier,omitempty"`
+	Generated   string `json:"generated,omitempty"`
+	// Repo names the repository the reference was found in when refs
+	// searches more than one index.
+	Repo string `json:"repo,omitempty"`
+	// Cell and CellLine place a reference in a notebook, as in SymbolMatch.
+	Cell     int `json:"cell,omitempty"`
+	CellLine int `json:"cell_line,omitempty"`
+}
+
+// RefsReport is printed by gts search refs --json.
+type RefsReport struct {
+	Matches   []ReferenceMatch `json:"matches"`
+	Count     int              `json:"count"`
+	Truncated bool             `json:"truncated,omitempty"`
+}
+
+// CountReport is printed by commands whose --json --count output is just a
+// count, such as gts search refs and gts analyze complexity.
+type CountReport struct {
+	Count     int  `json:"count"`
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// QueryMatch is one capture reported by gts search query.
+type QueryMatch struct {
+	File        string `json:"file"`
+	Language    string `json:"language"`
+	Pattern     int    `json:"pattern"`
+	Capture     string `json:"capture"`
+	NodeType    string `json:"node_type"`
+	Text        string `json:"text"`
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	StartColumn int    `json:"start_column"`
+	EndColumn   int    `json:"end_column"`
+}
+
+// QueryLanguageError records a language whose query could not be run.
+type QueryLanguageError struct {
+	Language string `json:"language"`
+	Error    string `json:"error"`
+}
+
+// QueryReport is printed by gts search query --json.
+type QueryReport struct {
+	Matches        []QueryMatch         `json:"matches,omitempty"`
+	Count          int                  `json:"count"`
+	Truncated      bool                 `json:"truncated,omitempty"`
+	LanguageErrors []QueryLanguageError `json:"language_errors,omitempty"`
+}
+
+// QueryCountReport is printed by gts search query --json --count.
+type QueryCountReport struct {
+	Count          int                  `json:"count"`
+	Truncated      bool                 `json:"truncated,omitempty"`
+	LanguageErrors []QueryLanguageError `json:"language_errors,omitempty"`
+}
+
+// QueryGroup is the number of captures sharing one combination of
+// --group-by keys.
+type QueryGroup struct {
+	Key   map[string]string `json:"key"`
+	Count int               `json:"count"`
+}
+
+// QueryGroupsReport is printed by gts search query --group-by --json.
+type QueryGroupsReport struct {
+	GroupBy        []string             `json:"group_by"`
+	Groups         []QueryGroup         `json:"groups"`
+	Count          int                  `json:"count"`
+	Truncated      bool                 `json:"truncated,omitempty"`
+	LanguageErrors []QueryLanguageError `json:"language_errors,omitempty"`
+}
+
+// QueryGroupCountReport is printed by gts search query --group-by --json
+// --count.
+type QueryGroupCountReport struct {
+	Groups int `json:"groups"`
+}
+
+// DeadMatch is a definition gts graph dead found no references to.
+type DeadMatch struct {
+	File      string `json:"file"`
+	Package   string `json:"package"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Signature string `json:"signature,omitempty"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Exported  bool   `json:"exported,omitempty"`
+	Incoming  int    `json:"incoming"`
+	Outgoing  int    `json:"outgoing"`
+
+	// Deletion is the span --write removes; nil when the definition shares
+	// lines with other code.
+	Deletion *refactor.Range `json:"deletion,omitempty"`
+	Removed  bool            `json:"removed,omitempty"`
+}
+
+// DeadReport is printed by gts graph dead --json.
+type DeadReport struct {
+	Kind    string `json:"kind"`
+	Scanned int    `json:"scanned"`
+	// DynamicRoots counts the scanned definitions with no incoming calls
+	// left out as dynamically used, such as Flask routes.
+	DynamicRoots int         `json:"dynamic_roots,omitempty"`
+	Count        int         `json:"count"`
+	Truncated    bool        `json:"truncated,omitempty"`
+	Matches      []DeadMatch `json:"matches,omitempty"`
+}
+
+// DeadCountReport is printed by gts graph dead --json --count.
+type DeadCountReport struct {
+	Count     int  `json:"count"`
+	Scanned   int  `json:"scanned"`
+	Truncated bool `json:"truncated,omitempty"`
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMarshalAddsSchemaVersion(t *testing.T) {
+	data, err := Marshal(CountReport{Count: 3})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	want := "{\n  \"schema_version\": 1,\n  \"count\": 3\n}\n"
+	if string(data) != want {
+		t.Fatalf("expected %q, got %q", want, data)
+	}
+
+	empty, err := Marshal(struct{}{})
+	if err != nil || string(empty) != "{\n  \"schema_version\": 1\n}\n" {
+		t.Fatalf("expected only schema_version for an empty object, got %q (%v)", empty, err)
+	}
+	array, err := Marshal([]ReferenceMatch{})
+	if err != nil || string(array) != "[]\n" {
+		t.Fatalf("expected arrays to be left alone, got %q (%v)", array, err)
+	}
+}
+
+func TestSchemaCoversMarshalledFields(t *testing.T) {
+	for _, name := range Documents() {
+		schema, ok := Schema(name)
+		if !ok {
+			t.Fatalf("Schema(%q) reported unknown", name)
+		}
+		if schema["$schema"] != schemaDialect || schema["title"] != "gts "+name {
+			t.Fatalf("%s: missing $schema or title: %v", name, schema)
+		}
+	}
+
+	schema, _ := Schema("dead")
+	properties := schema["properties"].(map[string]any)
+	data, err := Marshal(DeadReport{Kind: "callable", Matches: []DeadMatch{{Name: "unused"}}})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	for field := range decoded {
+		if _, ok := properties[field]; !ok {
+			t.Fatalf("field %q is missing from the dead schema", field)
+		}
+	}
+	required := strings.Join(schema["required"].([]string), ",")
+	if required != "schema_version,kind,scanned,count" {
+		t.Fatalf("unexpected required fields %q", required)
+	}
+
+	refs, _ := Schema("refs")
+	if required := strings.Join(refs["required"].([]string), ","); required != "schema_version,matches,count" {
+		t.Fatalf("unexpected refs required fields %q", required)
+	}
+
+	if _, ok := Schema("no-such-document"); ok {
+		t.Fatal("expected an unknown document to be rejected")
+	}
+}
@@ -0,0 +1,181 @@
+package report
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odvcencio/gts-suite/internal/configusage"
+	"github.com/odvcencio/gts-suite/internal/sqlusage"
+	"github.com/odvcencio/gts-suite/pkg/complexity"
+	"github.com/odvcencio/gts-suite/pkg/hotspot"
+)
+
+// schemaDialect is the JSON Schema draft the generated schemas declare.
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// documents maps the names accepted by Schema to the document they
+// describe. Commands with several --json shapes get one name per shape.
+var documents = map[string]any{
+	"calls":                    CallgraphReport{},
+	"calls-count":              CallgraphCountReport{},
+	"calls-packages":           PackageCallgraphReport{},
+	"calls-packages-count":     PackageCallgraphCountReport{},
+	"capa":                     CapaReport{},
+	"complexity":               complexity.Report{},
+	"complexity-count":         CountReport{},
+	"concurrency":              ConcurrencyReport{},
+	"concurrency-count":        CountReport{},
+	"config-usage":             ConfigUsageReport{},
+	"config-usage-count":       CountReport{},
+	"config-usage-drift":       configusage.Drift{},
+	"config-usage-drift-count": CountReport{},
+	"dead":                     DeadReport{},
+	"dead-count":               DeadCountReport{},
+	"duplication":              DuplicationReport{},
+	"duplication-count":        CountReport{},
+	"exits":                    ExitsReport{},
+	"exits-count":              CountReport{},
+	"fanin":                    FaninReport{},
+	"fanin-count":              CountReport{},
+	"grep":                     GrepReport{},
+	"grep-count":               GrepCountReport{},
+	"grep-structural":          StructuralGrepReport{},
+	"hotspot":                  hotspot.Report{},
+	"hotspot-count":            CountReport{},
+	"hotspots":                 hotspot.UsageReport{},
+	"hotspots-count":           CountReport{},
+	"imports":                  ImportsReport{},
+	"imports-count":            ImportsCountReport{},
+	"imports-reverse":          ImportersReport{},
+	"imports-reverse-count":    CountReport{},
+	"lint":                     LintReport{},
+	"query":                    QueryReport{},
+	"query-count":              QueryCountReport{},
+	"query-groups":             QueryGroupsReport{},
+	"query-groups-count":       QueryGroupCountReport{},
+	"query-list":               QueryPatternsReport{},
+	"refs":                     RefsReport{},
+	"refs-count":               CountReport{},
+	"report":                   ExecutiveReport{},
+	"report-compare":           ExecutiveComparison{},
+	"routes":                   RoutesReport{},
+	"routes-count":             RoutesCountReport{},
+	"similarity":               SimilarityReport{},
+	"snapshot-save":            SnapshotSaveReport{},
+	"sql":                      sqlusage.Report{},
+	"sql-count":                SQLCountReport{},
+	"sql-tables":               SQLTablesReport{},
+	"symbols":                  SymbolsReport{},
+	"symbols-count":            CountReport{},
+	"testmap":                  TestmapReport{},
+	"testmap-count":            TestmapCountReport{},
+	"unresolved":               UnresolvedReport{},
+	"unresolved-count":         UnresolvedCountReport{},
+	"unused-fields":            UnusedFieldsReport{},
+	"unused-fields-count":      UnusedFieldsCountReport{},
+	"yara":                     YaraReport{},
+}
+
+// Documents returns the names Schema accepts, sorted.
+func Documents() []string {
+	names := make([]string, 0, len(documents))
+	for name := range documents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Schema returns the JSON Schema of the document called name, as a value
+// ready to be encoded as JSON, and reports whether name is known. Object
+// documents require "schema_version" to equal SchemaVersion.
+func Schema(name string) (map[string]any, bool) {
+	document, ok := documents[name]
+	if !ok {
+		return nil, false
+	}
+	schema := typeSchema(reflect.TypeOf(document))
+	if schema["type"] == "object" {
+		schema["properties"].(map[string]any)["schema_version"] = map[string]any{
+			"type":  "integer",
+			"const": SchemaVersion,
+		}
+		schema["required"] = append([]string{"schema_version"}, schema["required"].([]string)...)
+	}
+	schema["$schema"] = schemaDialect
+	schema["title"] = "gts " + name
+	return schema, true
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func typeSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		// Go encodes nil slices and maps as null.
+		return map[string]any{"type": []string{"array", "null"}, "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": []string{"object", "null"}, "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]any{}
+		required := []string{}
+		addFields(t, properties, &required)
+		return map[string]any{"type": "object", "properties": properties, "required": required}
+	default:
+		return map[string]any{}
+	}
+}
+
+// addFields adds the JSON fields of struct type t, including those promoted
+// from untagged embedded structs, to properties. Fields without omitempty
+// are required.
+func addFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = typeSchema(field.Type)
+		if !strings.Contains(","+options+",", ",omitempty,") && field.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	tsgrep "github.com/odvcencio/gotreesitter/grep"
+
+	"github.com/odvcencio/gts-suite/internal/querylib"
+)
+
+// StructuralMatch is a code span matched by gts search grep in structural
+// mode.
+type StructuralMatch struct {
+	File        string            `json:"file"`
+	StartLine   int               `json:"start_line"`
+	EndLine     int               `json:"end_line"`
+	StartColumn int               `json:"start_column"`
+	EndColumn   int               `json:"end_column"`
+	Text        string            `json:"text"`
+	Captures    map[string]string `json:"captures,omitempty"`
+}
+
+// StructuralRewrite lists the edits a structural grep --rewrite made to one
+// file.
+type StructuralRewrite struct {
+	File  string        `json:"file"`
+	Edits []tsgrep.Edit `json:"edits"`
+}
+
+// StructuralGrepReport is printed by gts search grep --json in structural
+// mode.
+type StructuralGrepReport struct {
+	Mode      string              `json:"mode"`
+	Matches   []StructuralMatch   `json:"matches"`
+	Count     int                 `json:"count"`
+	Truncated bool                `json:"truncated,omitempty"`
+	Edits     []StructuralRewrite `json:"edits,omitempty"`
+}
+
+// QueryPatternsReport is printed by gts search query --list --json.
+type QueryPatternsReport struct {
+	Patterns []querylib.Pattern `json:"patterns"`
+}
+
+// SymbolEntry is a symbol listed by gts search symbols.
+type SymbolEntry struct {
+	File      string `json:"file"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Signature string `json:"signature,omitempty"`
+	Receiver  string `json:"receiver,omitempty"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Generated string `json:"generated,omitempty"`
+}
+
+// SymbolsReport is printed by gts search symbols --json.
+type SymbolsReport struct {
+	Symbols   []SymbolEntry `json:"symbols"`
+	Count     int           `json:"count"`
+	Truncated bool          `json:"truncated,omitempty"`
+}
+
+// ImportMatch is one import of one file listed by gts search imports.
+type ImportMatch struct {
+	File      string `json:"file"`
+	Import    string `json:"import"`
+	Generated string `json:"generated,omitempty"`
+}
+
+// ImportsReport is printed by gts search imports --json.
+type ImportsReport struct {
+	Imports       []ImportMatch `json:"imports"`
+	UniqueImports int           `json:"unique_imports"`
+	TotalImports  int           `json:"total_imports"`
+}
+
+// ImportsCountReport is printed by gts search imports --json --count.
+type ImportsCountReport struct {
+	UniqueImports int `json:"unique_imports"`
+	TotalImports  int `json:"total_imports"`
+}
+
+// ImportingFile is a file found by gts search imports --reverse, with the
+// imports that matched --pattern.
+type ImportingFile struct {
+	File      string   `json:"file"`
+	Imports   []string `json:"imports"`
+	Generated string   `json:"generated,omitempty"`
+}
+
+// ImportersReport is printed by gts search imports --reverse --json.
+type ImportersReport struct {
+	Files []ImportingFile `json:"files"`
+	Count int             `json:"count"`
+}
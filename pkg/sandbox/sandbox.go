@@ -0,0 +1,114 @@
+// Package sandbox restricts filesystem access to an allowlist of root directories, resolving symlinks before comparison.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrOutsideRoots is returned when a path resolves outside every allowed root.
+var ErrOutsideRoots = errors.New("path is outside the allowed roots")
+
+// Roots is an allowlist of directories. A nil or empty Roots allows every path.
+type Roots struct {
+	roots []string
+}
+
+// New resolves each root to an absolute, symlink-free path. Blank entries are
+// skipped. A root that cannot be resolved is kept in cleaned form, so it only
+// ever matches itself rather than widening access.
+func New(roots []string) *Roots {
+	resolved := make([]string, 0, len(roots))
+	for _, root := range roots {
+		root = strings.TrimSpace(root)
+		if root == "" {
+			continue
+		}
+		real, err := Resolve(root)
+		if err != nil {
+			real = filepath.Clean(root)
+		}
+		resolved = append(resolved, real)
+	}
+	return &Roots{roots: resolved}
+}
+
+// Enabled reports whether any roots are configured.
+func (r *Roots) Enabled() bool {
+	return r != nil && len(r.roots) > 0
+}
+
+// List returns the resolved roots.
+func (r *Roots) List() []string {
+	if r == nil {
+		return nil
+	}
+	return append([]string(nil), r.roots...)
+}
+
+// Contains reports whether path resolves inside one of the roots. It always
+// returns true when no roots are configured.
+func (r *Roots) Contains(path string) bool {
+	return r.Check(path) == nil
+}
+
+// Check returns an error wrapping ErrOutsideRoots when path resolves outside
+// every root. Relative paths are resolved against the working directory.
+func (r *Roots) Check(path string) error {
+	if !r.Enabled() {
+		return nil
+	}
+	real, err := Resolve(path)
+	if err != nil {
+		return err
+	}
+	for _, root := range r.roots {
+		if within(root, real) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrOutsideRoots, path)
+}
+
+// Resolve returns the absolute, symlink-free form of path. Trailing path
+// components that do not exist yet are kept verbatim after resolving the
+// deepest existing ancestor, so not-yet-created outputs can still be checked.
+func Resolve(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	abs = filepath.Clean(abs)
+
+	existing := abs
+	var missing []string
+	for {
+		real, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			for i := len(missing) - 1; i >= 0; i-- {
+				real = filepath.Join(real, missing[i])
+			}
+			return filepath.Clean(real), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return abs, nil
+		}
+		missing = append(missing, filepath.Base(existing))
+		existing = parent
+	}
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
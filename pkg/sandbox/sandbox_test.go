@@ -0,0 +1,62 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRootsEmptyAllowsEverything(t *testing.T) {
+	var nilRoots *Roots
+	if !nilRoots.Contains("/etc/passwd") {
+		t.Fatal("nil Roots should allow every path")
+	}
+	if !New(nil).Contains("/etc/passwd") {
+		t.Fatal("empty Roots should allow every path")
+	}
+}
+
+func TestRootsContains(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	roots := New([]string{root})
+
+	for _, path := range []string{root, filepath.Join(root, "sub"), filepath.Join(root, "sub", "missing.go")} {
+		if err := roots.Check(path); err != nil {
+			t.Fatalf("expected %s inside roots, got %v", path, err)
+		}
+	}
+	for _, path := range []string{filepath.Dir(root), filepath.Join(root, "..", "other"), root + "-sibling"} {
+		if err := roots.Check(path); !errors.Is(err, ErrOutsideRoots) {
+			t.Fatalf("expected ErrOutsideRoots for %s, got %v", path, err)
+		}
+	}
+}
+
+func TestRootsResolvesSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	link := filepath.Join(root, "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	roots := New([]string{root})
+	if roots.Contains(link) {
+		t.Fatal("symlink pointing outside the root must be rejected")
+	}
+	if roots.Contains(filepath.Join(link, "file.txt")) {
+		t.Fatal("path beneath an escaping symlink must be rejected")
+	}
+
+	linkedRoot := filepath.Join(outside, "root-link")
+	if err := os.Symlink(root, linkedRoot); err != nil {
+		t.Fatal(err)
+	}
+	if !New([]string{linkedRoot}).Contains(filepath.Join(root, "a.go")) {
+		t.Fatal("roots given through a symlink should match their target")
+	}
+}
@@ -4,21 +4,25 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/odvcencio/gotreesitter"
 	"github.com/odvcencio/gotreesitter/grammars"
+	"github.com/odvcencio/gts-suite/internal/parsesession"
 	"github.com/odvcencio/gts-suite/pkg/model"
 )
 
 // BuildFromIndex constructs a scope graph for all files in an index.
 func BuildFromIndex(idx *model.Index, rootPath string) (*Graph, error) {
 	graph := NewGraph()
+	session := parsesession.New()
 
 	for _, f := range idx.Files {
 		entry := grammars.DetectLanguage(f.Path)
 		if entry == nil {
 			continue
 		}
-		lang := entry.Language()
+		lang, ok := session.Language(entry.Name)
+		if !ok {
+			continue
+		}
 		rules, err := LoadRules(entry.Name, lang)
 		if err != nil {
 			// No scope rules for this language — skip
@@ -31,19 +35,13 @@ func BuildFromIndex(idx *model.Index, rootPath string) (*Graph, error) {
 			continue
 		}
 
-		parser := gotreesitter.NewParser(lang)
-		var tree *gotreesitter.Tree
-		if entry.TokenSourceFactory != nil {
-			ts := entry.TokenSourceFactory(src, lang)
-			tree, err = parser.ParseWithTokenSource(src, ts)
-		} else {
-			tree, err = parser.Parse(src)
-		}
+		tree, err := session.Parse(entry.Name, src)
 		if err != nil {
 			continue
 		}
 
 		fileScope := BuildFileScope(tree, lang, src, rules, f.Path)
+		tree.Release()
 		graph.AddFileScope(f.Path, fileScope)
 	}
 
@@ -110,7 +110,7 @@ func extractPrints(idx *model.Index, root string) []FunctionPrint {
 	var prints []FunctionPrint
 	for _, f := range idx.Files {
 		for _, sym := range f.Symbols {
-			if sym.Kind != "function_definition" && sym.Kind != "method_definition" {
+			if !model.IsFunctionKind(sym.Kind) && sym.Kind != "method_definition" {
 				continue
 			}
 			body, err := readFunctionBody(root, f.Path, sym.StartLine, sym.EndLine)
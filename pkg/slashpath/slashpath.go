@@ -0,0 +1,64 @@
+// Package slashpath is the path layer between the filesystem and the index.
+// Every path the index stores is relative to the index root, cleaned, and
+// separated by "/" whatever the OS, so an index built on Windows reads the
+// same on Linux and paths compare equal however they were spelled. OS paths
+// are only rebuilt, with Join, at the point a file is opened.
+package slashpath
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Clean returns p in slash form: backslashes become "/" on every OS, the
+// result is cleaned, and a leading "./" is dropped. An empty path stays
+// empty. gts treats backslashes as separators everywhere, so file names
+// containing them are not supported.
+func Clean(p string) string {
+	if p == "" {
+		return ""
+	}
+	p = strings.ReplaceAll(p, `\`, "/")
+	// Keep the leading "//" of a UNC path, which path.Clean would collapse.
+	if strings.HasPrefix(p, "//") && !strings.HasPrefix(p, "///") {
+		return "/" + path.Clean(p)
+	}
+	return path.Clean(p)
+}
+
+// Rel returns target relative to root in slash form. It reports false when
+// target is not inside root, including when the two are on different
+// volumes; root itself is ".".
+func Rel(root, target string) (string, bool) {
+	rel, err := filepath.Rel(root, target)
+	if err != nil {
+		return "", false
+	}
+	rel = Clean(filepath.ToSlash(rel))
+	if rel == ".." || strings.HasPrefix(rel, "../") || filepath.IsAbs(rel) {
+		return "", false
+	}
+	return rel, true
+}
+
+// Join returns the OS path of rel, a slash-form path relative to root.
+func Join(root, rel string) string {
+	return filepath.Join(root, filepath.FromSlash(Clean(rel)))
+}
+
+// IsUNC reports whether p is a Windows UNC path such as \\server\share\dir,
+// in either slash style. Such paths are on network shares. Device paths
+// (\\?\ and \\.\) are not UNC paths unless they name one (\\?\UNC\...).
+func IsUNC(p string) bool {
+	p = strings.ReplaceAll(p, `\`, "/")
+	if !strings.HasPrefix(p, "//") || strings.HasPrefix(p, "///") {
+		return false
+	}
+	rest := p[2:]
+	if strings.HasPrefix(rest, "?/") || strings.HasPrefix(rest, "./") {
+		return strings.HasPrefix(strings.ToUpper(rest[2:]), "UNC/")
+	}
+	server, share, _ := strings.Cut(rest, "/")
+	return server != "" && strings.Trim(share, "/") != ""
+}
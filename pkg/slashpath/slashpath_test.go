@@ -0,0 +1,86 @@
+package slashpath
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	cases := map[string]string{
+		"":                      "",
+		".":                     ".",
+		"./src/a.go":            "src/a.go",
+		`src\pkg\a.go`:          "src/pkg/a.go",
+		`src/pkg\..\b.go`:       "src/b.go",
+		`C:\work\repo\a.go`:     "C:/work/repo/a.go",
+		`\\nas\share\repo`:      "//nas/share/repo",
+		"//nas/share/repo/../x": "//nas/share/x",
+		"a//b/":                 "a/b",
+	}
+	for in, want := range cases {
+		if got := Clean(in); got != want {
+			t.Errorf("Clean(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRel(t *testing.T) {
+	root := t.TempDir()
+	cases := []struct {
+		target string
+		want   string
+		ok     bool
+	}{
+		{root, ".", true},
+		{filepath.Join(root, "src", "a.go"), "src/a.go", true},
+		{filepath.Join(root, "..foo", "a.go"), "..foo/a.go", true},
+		{filepath.Join(root, "..", "sibling"), "", false},
+	}
+	for _, tc := range cases {
+		got, ok := Rel(root, tc.target)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("Rel(%q) = %q, %v; want %q, %v", tc.target, got, ok, tc.want, tc.ok)
+		}
+	}
+	if runtime.GOOS == "windows" {
+		if _, ok := Rel(`C:\repo`, `D:\repo\a.go`); ok {
+			t.Error("expected a path on another volume to be outside the root")
+		}
+		if got, ok := Rel(`C:\repo`, `c:\repo\src\a.go`); !ok || got != "src/a.go" {
+			t.Errorf("expected volume names to compare case-insensitively, got %q, %v", got, ok)
+		}
+	}
+}
+
+func TestJoinRoundTrips(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{"a.go", "src/pkg/a.go", `src\pkg\b.go`} {
+		got, ok := Rel(root, Join(root, rel))
+		if !ok || got != Clean(rel) {
+			t.Errorf("Rel(Join(%q)) = %q, %v", rel, got, ok)
+		}
+	}
+}
+
+func TestIsUNC(t *testing.T) {
+	cases := map[string]bool{
+		`\\nas\share`:            true,
+		`\\nas\share\repo`:       true,
+		"//nas/share/repo":       true,
+		`\\?\UNC\nas\share\repo`: true,
+		`\\?\C:\repo`:            false,
+		`\\.\pipe\gts`:           false,
+		`\\nas`:                  false,
+		`\\nas\`:                 false,
+		`C:\repo`:                false,
+		"/home/me/repo":          false,
+		"///x/y":                 false,
+		`\\网络驱动器\共享\仓库`:          true,
+	}
+	for in, want := range cases {
+		if got := IsUNC(in); got != want {
+			t.Errorf("IsUNC(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
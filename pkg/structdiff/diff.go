@@ -2,6 +2,7 @@
 package structdiff
 
 import (
+	"reflect"
 	"sort"
 	"strings"
 
@@ -9,13 +10,15 @@ import (
 )
 
 type SymbolRef struct {
-	File      string `json:"file"`
-	Kind      string `json:"kind"`
-	Name      string `json:"name"`
-	Signature string `json:"signature,omitempty"`
-	Receiver  string `json:"receiver,omitempty"`
-	StartLine int    `json:"start_line"`
-	EndLine   int    `json:"end_line"`
+	File       string `json:"file"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Signature  string `json:"signature,omitempty"`
+	Receiver   string `json:"receiver,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+	Exported   bool   `json:"exported,omitempty"`
+	StartLine  int    `json:"start_line"`
+	EndLine    int    `json:"end_line"`
 }
 
 type ModifiedSymbol struct {
@@ -35,6 +38,9 @@ type Stats struct {
 	RemovedSymbols  int `json:"removed_symbols"`
 	ModifiedSymbols int `json:"modified_symbols"`
 	ChangedFiles    int `json:"changed_files"`
+	// APIChanges counts added, removed, and modified symbols that are
+	// exported on either side of the comparison.
+	APIChanges int `json:"api_changes,omitempty"`
 }
 
 type Report struct {
@@ -108,10 +114,84 @@ func Compare(before, after *model.Index) Report {
 		RemovedSymbols:  len(report.RemovedSymbols),
 		ModifiedSymbols: len(report.ModifiedSymbols),
 		ChangedFiles:    countChangedFiles(report),
+		APIChanges:      countAPIChanges(report),
 	}
 	return report
 }
 
+func countAPIChanges(report Report) int {
+	count := 0
+	for _, symbol := range report.AddedSymbols {
+		if symbol.Exported {
+			count++
+		}
+	}
+	for _, symbol := range report.RemovedSymbols {
+		if symbol.Exported {
+			count++
+		}
+	}
+	for _, modified := range report.ModifiedSymbols {
+		if modified.Before.Exported || modified.After.Exported {
+			count++
+		}
+	}
+	return count
+}
+
+// ChangedFiles returns the paths in after that are new or whose size,
+// content, imports, or symbols differ from before, sorted. Content is compared
+// by hash when both snapshots record one and by modification time otherwise,
+// so snapshots checked out at different times still compare equal.
+func ChangedFiles(before, after *model.Index) []string {
+	if after == nil {
+		return nil
+	}
+	previous := map[string]model.FileSummary{}
+	if before != nil {
+		for _, file := range before.Files {
+			previous[file.Path] = file
+		}
+	}
+
+	changed := make([]string, 0, 16)
+	for _, file := range after.Files {
+		old, ok := previous[file.Path]
+		if !ok || fileChanged(old, file) {
+			changed = append(changed, file.Path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func fileChanged(before, after model.FileSummary) bool {
+	if before.SizeBytes != after.SizeBytes {
+		return true
+	}
+	if before.ContentHash != "" && after.ContentHash != "" {
+		if before.ContentHash != after.ContentHash {
+			return true
+		}
+	} else if before.ModTimeUnixNano != after.ModTimeUnixNano {
+		return true
+	}
+	if len(before.Imports) != len(after.Imports) || len(before.Symbols) != len(after.Symbols) {
+		return true
+	}
+	for i := range before.Imports {
+		if before.Imports[i] != after.Imports[i] {
+			return true
+		}
+	}
+	for i := range before.Symbols {
+		if !reflect.DeepEqual(before.Symbols[i], after.Symbols[i]) {
+			return true
+		}
+	}
+	return false
+}
+
 func flattenSymbols(idx *model.Index) map[string]model.Symbol {
 	flat := make(map[string]model.Symbol, symbolCapacity(idx))
 	if idx == nil {
@@ -143,13 +223,15 @@ func symbolKey(symbol model.Symbol) string {
 
 func toSymbolRef(symbol model.Symbol) SymbolRef {
 	return SymbolRef{
-		File:      symbol.File,
-		Kind:      symbol.Kind,
-		Name:      symbol.Name,
-		Signature: symbol.Signature,
-		Receiver:  symbol.Receiver,
-		StartLine: symbol.StartLine,
-		EndLine:   symbol.EndLine,
+		File:       symbol.File,
+		Kind:       symbol.Kind,
+		Name:       symbol.Name,
+		Signature:  symbol.Signature,
+		Receiver:   symbol.Receiver,
+		Visibility: symbol.Visibility,
+		Exported:   symbol.Exported,
+		StartLine:  symbol.StartLine,
+		EndLine:    symbol.EndLine,
 	}
 }
 
@@ -161,6 +243,10 @@ func changedFields(before, after model.Symbol) []string {
 	if before.StartLine != after.StartLine || before.EndLine != after.EndLine {
 		fields = append(fields, "span")
 	}
+	// Indexes written before visibility was recorded leave it empty.
+	if before.Visibility != "" && after.Visibility != "" && before.Visibility != after.Visibility {
+		fields = append(fields, "visibility")
+	}
 	return fields
 }
 
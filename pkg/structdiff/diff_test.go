@@ -902,3 +902,75 @@ func TestCompareEmptyIndexes(t *testing.T) {
 		}
 	})
 }
+
+func TestChangedFiles(t *testing.T) {
+	before := &model.Index{
+		Files: []model.FileSummary{
+			{Path: "same.go", SizeBytes: 10, ModTimeUnixNano: 1},
+			{Path: "touched.go", SizeBytes: 10, ModTimeUnixNano: 1},
+			{Path: "symbols.go", SizeBytes: 10, ModTimeUnixNano: 1, Symbols: []model.Symbol{{Name: "A"}}},
+			{Path: "deleted.go"},
+			{Path: "rechecked.go", SizeBytes: 10, ModTimeUnixNano: 1, ContentHash: "aa"},
+			{Path: "edited.go", SizeBytes: 10, ModTimeUnixNano: 1, ContentHash: "aa"},
+		},
+	}
+	after := &model.Index{
+		Files: []model.FileSummary{
+			{Path: "same.go", SizeBytes: 10, ModTimeUnixNano: 1},
+			{Path: "touched.go", SizeBytes: 10, ModTimeUnixNano: 2},
+			{Path: "symbols.go", SizeBytes: 10, ModTimeUnixNano: 1, Symbols: []model.Symbol{{Name: "B"}}},
+			{Path: "new.go"},
+			{Path: "rechecked.go", SizeBytes: 10, ModTimeUnixNano: 2, ContentHash: "aa"},
+			{Path: "edited.go", SizeBytes: 10, ModTimeUnixNano: 1, ContentHash: "bb"},
+		},
+	}
+
+	got := ChangedFiles(before, after)
+	want := []string{"edited.go", "new.go", "symbols.go", "touched.go"}
+	if len(got) != len(want) {
+		t.Fatalf("ChangedFiles = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ChangedFiles = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCompareVisibilityChange(t *testing.T) {
+	symbol := model.Symbol{
+		File:       "calc.py",
+		Kind:       "function_definition",
+		Name:       "add",
+		Signature:  "def add(a, b)",
+		Visibility: "public",
+		Exported:   true,
+		StartLine:  1,
+		EndLine:    2,
+	}
+	hidden := symbol
+	hidden.Visibility = "private"
+	hidden.Exported = false
+
+	before := &model.Index{Files: []model.FileSummary{{Path: "calc.py", Symbols: []model.Symbol{symbol}}}}
+	after := &model.Index{Files: []model.FileSummary{{Path: "calc.py", Symbols: []model.Symbol{hidden}}}}
+
+	report := Compare(before, after)
+	if len(report.ModifiedSymbols) != 1 {
+		t.Fatalf("expected 1 modified symbol, got %d", len(report.ModifiedSymbols))
+	}
+	if fields := report.ModifiedSymbols[0].Fields; len(fields) != 1 || fields[0] != "visibility" {
+		t.Fatalf("expected fields [visibility], got %v", fields)
+	}
+	if report.Stats.APIChanges != 1 {
+		t.Fatalf("expected 1 API change, got %d", report.Stats.APIChanges)
+	}
+
+	legacy := symbol
+	legacy.Visibility = ""
+	legacy.Exported = false
+	report = Compare(&model.Index{Files: []model.FileSummary{{Path: "calc.py", Symbols: []model.Symbol{legacy}}}}, before)
+	if len(report.ModifiedSymbols) != 0 {
+		t.Fatalf("expected no change against an index without visibility, got %+v", report.ModifiedSymbols)
+	}
+}
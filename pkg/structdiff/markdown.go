@@ -0,0 +1,122 @@
+package structdiff
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// WriteMarkdown renders report as release-notes scaffolding: one section per
+// package, the directory of the changed files, with Added, Removed, and
+// Changed lists of signatures in code spans. Symbols that only moved are left
+// out, as are import changes. Headings start at level 3 so the output can be
+// pasted under a version heading.
+func WriteMarkdown(w io.Writer, report Report) {
+	type section struct {
+		added, removed, changed []string
+	}
+	sections := map[string]*section{}
+	get := func(file string) *section {
+		pkg := path.Dir(strings.ReplaceAll(file, "\\", "/"))
+		if sections[pkg] == nil {
+			sections[pkg] = &section{}
+		}
+		return sections[pkg]
+	}
+
+	for _, item := range report.AddedSymbols {
+		s := get(item.File)
+		s.added = append(s.added, markdownSymbol(item))
+	}
+	for _, item := range report.RemovedSymbols {
+		s := get(item.File)
+		s.removed = append(s.removed, markdownSymbol(item))
+	}
+	for _, item := range report.ModifiedSymbols {
+		line := markdownChange(item)
+		if line == "" {
+			continue
+		}
+		s := get(item.After.File)
+		s.changed = append(s.changed, line)
+	}
+
+	if len(sections) == 0 {
+		fmt.Fprintln(w, "No symbol changes.")
+		return
+	}
+
+	packages := make([]string, 0, len(sections))
+	for pkg := range sections {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	for i, pkg := range packages {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "### %s\n", codeSpan(pkg))
+		s := sections[pkg]
+		writeMarkdownList(w, "Added", s.added)
+		writeMarkdownList(w, "Removed", s.removed)
+		writeMarkdownList(w, "Changed", s.changed)
+	}
+}
+
+func writeMarkdownList(w io.Writer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n#### %s\n\n", title)
+	for _, item := range items {
+		fmt.Fprintf(w, "- %s\n", item)
+	}
+}
+
+// markdownSymbol renders a symbol as its signature, or as its qualified
+// name and kind when it has none.
+func markdownSymbol(symbol SymbolRef) string {
+	if strings.TrimSpace(symbol.Signature) != "" {
+		return codeSpan(symbol.Signature)
+	}
+	name := symbol.Name
+	if symbol.Receiver != "" {
+		name = symbol.Receiver + "." + name
+	}
+	return codeSpan(name) + " (" + strings.TrimSuffix(symbol.Kind, "_definition") + ")"
+}
+
+// markdownChange renders a signature or visibility change, or returns ""
+// when the symbol only moved.
+func markdownChange(item ModifiedSymbol) string {
+	var line string
+	for _, field := range item.Fields {
+		switch field {
+		case "signature":
+			line = markdownSymbol(item.Before) + " → " + markdownSymbol(item.After)
+		case "visibility":
+			if line == "" {
+				line = markdownSymbol(item.After)
+			}
+			line += fmt.Sprintf(" (%s → %s)", item.Before.Visibility, item.After.Visibility)
+		}
+	}
+	return line
+}
+
+// codeSpan wraps text, with whitespace collapsed, in a code span, fencing
+// it with enough backticks to hold the ones it contains.
+func codeSpan(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return fence + text + fence
+}
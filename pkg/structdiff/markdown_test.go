@@ -0,0 +1,75 @@
+package structdiff
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	report := Report{
+		AddedSymbols: []SymbolRef{
+			{File: "pkg/api/client.go", Kind: "function_definition", Name: "NewClient", Signature: "func NewClient(opts ...Option) *Client"},
+			{File: "main.go", Kind: "type_definition", Name: "Config"},
+		},
+		RemovedSymbols: []SymbolRef{
+			{File: "pkg/api/legacy.go", Kind: "method_definition", Name: "Dial", Receiver: "Client", Signature: "func (c *Client) Dial(addr string) error"},
+		},
+		ModifiedSymbols: []ModifiedSymbol{
+			{
+				Before: SymbolRef{File: "pkg/api/client.go", Kind: "function_definition", Name: "Do", Signature: "func Do(req *Request)"},
+				After:  SymbolRef{File: "pkg/api/client.go", Kind: "function_definition", Name: "Do", Signature: "func Do(ctx context.Context, req *Request)"},
+				Fields: []string{"signature", "span"},
+			},
+			{
+				Before: SymbolRef{File: "pkg/api/client.go", Kind: "function_definition", Name: "moved", Signature: "func moved()"},
+				After:  SymbolRef{File: "pkg/api/client.go", Kind: "function_definition", Name: "moved", Signature: "func moved()"},
+				Fields: []string{"span"},
+			},
+			{
+				Before: SymbolRef{File: "lib/util.py", Kind: "function_definition", Name: "helper", Signature: "def helper():", Visibility: "public"},
+				After:  SymbolRef{File: "lib/util.py", Kind: "function_definition", Name: "helper", Signature: "def helper():", Visibility: "private"},
+				Fields: []string{"visibility"},
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	WriteMarkdown(&out, report)
+	want := "### `.`\n" +
+		"\n#### Added\n\n" +
+		"- `Config` (type)\n" +
+		"\n### `lib`\n" +
+		"\n#### Changed\n\n" +
+		"- `def helper():` (public → private)\n" +
+		"\n### `pkg/api`\n" +
+		"\n#### Added\n\n" +
+		"- `func NewClient(opts ...Option) *Client`\n" +
+		"\n#### Removed\n\n" +
+		"- `func (c *Client) Dial(addr string) error`\n" +
+		"\n#### Changed\n\n" +
+		"- `func Do(req *Request)` → `func Do(ctx context.Context, req *Request)`\n"
+	if got := out.String(); got != want {
+		t.Fatalf("WriteMarkdown output mismatch:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteMarkdownEmpty(t *testing.T) {
+	var out bytes.Buffer
+	WriteMarkdown(&out, Report{ModifiedSymbols: []ModifiedSymbol{{Fields: []string{"span"}}}})
+	if got := out.String(); got != "No symbol changes.\n" {
+		t.Fatalf("unexpected output for span-only report: %q", got)
+	}
+}
+
+func TestCodeSpan(t *testing.T) {
+	cases := map[string]string{
+		"func  F()\n\tint": "`func F() int`",
+		"x := `raw`":       "`` x := `raw` ``",
+		"`quoted`":         "`` `quoted` ``",
+	}
+	for input, want := range cases {
+		if got := codeSpan(input); got != want {
+			t.Errorf("codeSpan(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
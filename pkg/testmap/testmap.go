@@ -76,7 +76,7 @@ func Map(idx *model.Index, opts Options) (*Report, error) {
 		if opts.Kind != "" {
 			switch opts.Kind {
 			case "function":
-				if def.Kind != "function_definition" {
+				if !model.IsFunctionKind(def.Kind) {
 					continue
 				}
 			case "method":
@@ -0,0 +1,180 @@
+// Package textpos converts source positions between byte offsets and the
+// line/column forms editors use. The index and refactor edits count columns
+// in bytes, while LSP clients count UTF-16 code units by default, so any
+// line holding emoji or CJK text needs converting before it crosses that
+// boundary.
+package textpos
+
+import (
+	"unicode/utf8"
+)
+
+// Encoding is the unit a column is counted in. The values are the LSP
+// PositionEncodingKind names.
+type Encoding string
+
+const (
+	UTF8  Encoding = "utf-8"  // bytes
+	UTF16 Encoding = "utf-16" // UTF-16 code units, the LSP default
+	UTF32 Encoding = "utf-32" // runes
+)
+
+// ParseEncoding returns the Encoding named name and reports whether it is
+// one of UTF8, UTF16, or UTF32.
+func ParseEncoding(name string) (Encoding, bool) {
+	switch enc := Encoding(name); enc {
+	case UTF8, UTF16, UTF32:
+		return enc, true
+	}
+	return "", false
+}
+
+// Map indexes the line starts of a source so positions can be converted
+// without rescanning it. Lines and columns given to and returned by Map are
+// 0-based. Invalid UTF-8 bytes count as one rune, and one UTF-16 unit, each.
+type Map struct {
+	src   []byte
+	lines []int // byte offset of the start of each line
+}
+
+// New returns a Map of src. Lines end at "\n"; a "\r" before it stays part
+// of the line.
+func New(src []byte) *Map {
+	lines := []int{0}
+	for i, b := range src {
+		if b == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return &Map{src: src, lines: lines}
+}
+
+// LineCount returns the number of lines, counting the one after a trailing
+// newline.
+func (m *Map) LineCount() int {
+	return len(m.lines)
+}
+
+// line returns the bytes of line, without its newline, or nil when line is
+// out of range.
+func (m *Map) line(line int) []byte {
+	if line < 0 || line >= len(m.lines) {
+		return nil
+	}
+	end := len(m.src)
+	if line+1 < len(m.lines) {
+		end = m.lines[line+1] - 1
+	}
+	return m.src[m.lines[line]:end]
+}
+
+// Offset returns the byte offset of the position at column col, counted in
+// enc, on line. Positions past the end of the line clamp to it, and lines
+// past the end of the source clamp to its end, as LSP asks of servers.
+func (m *Map) Offset(line, col int, enc Encoding) int {
+	if line < 0 {
+		return 0
+	}
+	if line >= len(m.lines) {
+		return len(m.src)
+	}
+	return m.lines[line] + ByteColumn(m.line(line), col, enc)
+}
+
+// Position returns the line and the column, counted in enc, of byte offset.
+// An offset inside a multi-byte character maps to the column of that
+// character.
+func (m *Map) Position(offset int, enc Encoding) (line, col int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(m.src) {
+		offset = len(m.src)
+	}
+	lo, hi := 0, len(m.lines)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if m.lines[mid] <= offset {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo, Column(m.line(lo), offset-m.lines[lo], enc)
+}
+
+// Column converts byteCol, a byte column on line, to a column counted in enc.
+func (m *Map) Column(line, byteCol int, enc Encoding) int {
+	return Column(m.line(line), byteCol, enc)
+}
+
+// ByteColumn converts col, a column on line counted in enc, to a byte column.
+func (m *Map) ByteColumn(line, col int, enc Encoding) int {
+	return ByteColumn(m.line(line), col, enc)
+}
+
+// Column converts byteCol, a byte offset into text, to the number of enc
+// units before it. byteCol is clamped to text; one inside a multi-byte
+// character counts up to the start of that character.
+func Column(text []byte, byteCol int, enc Encoding) int {
+	if byteCol > len(text) {
+		byteCol = len(text)
+	}
+	if enc == UTF8 {
+		if byteCol < 0 {
+			return 0
+		}
+		return byteCol
+	}
+	units := 0
+	for i := 0; i < byteCol; {
+		r, size := utf8.DecodeRune(text[i:])
+		if i+size > byteCol {
+			break
+		}
+		units += runeUnits(r, enc)
+		i += size
+	}
+	return units
+}
+
+// ByteColumn converts col, a count of enc units into text, to a byte
+// offset. col is clamped to text; one landing inside a UTF-16 surrogate pair
+// maps to the start of its character.
+func ByteColumn(text []byte, col int, enc Encoding) int {
+	if col <= 0 {
+		return 0
+	}
+	if enc == UTF8 {
+		if col > len(text) {
+			return len(text)
+		}
+		return col
+	}
+	units := 0
+	i := 0
+	for i < len(text) {
+		r, size := utf8.DecodeRune(text[i:])
+		units += runeUnits(r, enc)
+		if units > col {
+			break
+		}
+		i += size
+		if units == col {
+			break
+		}
+	}
+	return i
+}
+
+// Len returns the length of text counted in enc.
+func Len(text []byte, enc Encoding) int {
+	return Column(text, len(text), enc)
+}
+
+func runeUnits(r rune, enc Encoding) int {
+	if enc == UTF16 && r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
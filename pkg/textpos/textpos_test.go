@@ -0,0 +1,91 @@
+package textpos
+
+import "testing"
+
+func TestColumnConversions(t *testing.T) {
+	// "é" is 2 bytes, "世" 3 bytes, and "😀" 4 bytes and a surrogate pair.
+	line := []byte("aé世😀b")
+	cases := []struct {
+		byteCol            int
+		utf8, utf16, utf32 int
+	}{
+		{0, 0, 0, 0},
+		{1, 1, 1, 1},
+		{3, 3, 2, 2},
+		{6, 6, 3, 3},
+		{10, 10, 5, 4},
+		{11, 11, 6, 5},
+	}
+	for _, tc := range cases {
+		for enc, want := range map[Encoding]int{UTF8: tc.utf8, UTF16: tc.utf16, UTF32: tc.utf32} {
+			if got := Column(line, tc.byteCol, enc); got != want {
+				t.Errorf("Column(%d, %s) = %d, want %d", tc.byteCol, enc, got, want)
+			}
+			if got := ByteColumn(line, want, enc); got != tc.byteCol {
+				t.Errorf("ByteColumn(%d, %s) = %d, want %d", want, enc, got, tc.byteCol)
+			}
+		}
+	}
+
+	// A column between the halves of a surrogate pair, or inside a multi-byte
+	// character, maps to the start of the character; columns past the end
+	// clamp.
+	if got := ByteColumn(line, 4, UTF16); got != 6 {
+		t.Errorf("ByteColumn inside surrogate pair = %d, want 6", got)
+	}
+	if got := Column(line, 8, UTF16); got != 3 {
+		t.Errorf("Column inside emoji = %d, want 3", got)
+	}
+	if got := ByteColumn(line, 99, UTF16); got != len(line) {
+		t.Errorf("ByteColumn past end = %d, want %d", got, len(line))
+	}
+	if got := Len(line, UTF16); got != 6 {
+		t.Errorf("Len = %d, want 6", got)
+	}
+}
+
+func TestMapOffsetAndPosition(t *testing.T) {
+	src := []byte("x := \"😀\"\r\ny := 1\n")
+	m := New(src)
+	if m.LineCount() != 3 {
+		t.Fatalf("LineCount = %d, want 3", m.LineCount())
+	}
+
+	yOffset := len("x := \"😀\"\r\n")
+	if got := m.Offset(1, 0, UTF16); got != yOffset {
+		t.Fatalf("Offset(1, 0) = %d, want %d", got, yOffset)
+	}
+	if line, col := m.Position(yOffset+5, UTF16); line != 1 || col != 5 {
+		t.Fatalf("Position = %d:%d, want 1:5", line, col)
+	}
+
+	// The closing quote follows the emoji: byte 10, UTF-16 column 8.
+	if line, col := m.Position(10, UTF16); line != 0 || col != 8 {
+		t.Fatalf("Position(10) = %d:%d, want 0:8", line, col)
+	}
+	if got := m.Offset(0, 8, UTF16); got != 10 {
+		t.Fatalf("Offset(0, 8) = %d, want 10", got)
+	}
+
+	// Out-of-range positions clamp to the line or the source.
+	if got := m.Offset(0, 99, UTF16); got != yOffset-1 {
+		t.Fatalf("Offset past line end = %d, want %d", got, yOffset-1)
+	}
+	if got := m.Offset(9, 0, UTF16); got != len(src) {
+		t.Fatalf("Offset past last line = %d, want %d", got, len(src))
+	}
+	if line, col := m.Position(len(src), UTF16); line != 2 || col != 0 {
+		t.Fatalf("Position(end) = %d:%d, want 2:0", line, col)
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	for _, name := range []string{"utf-8", "utf-16", "utf-32"} {
+		if enc, ok := ParseEncoding(name); !ok || string(enc) != name {
+			t.Errorf("ParseEncoding(%q) = %q, %v", name, enc, ok)
+		}
+	}
+	if _, ok := ParseEncoding("latin1"); ok {
+		t.Error("expected latin1 to be rejected")
+	}
+}
@@ -0,0 +1,96 @@
+package xref
+
+import "sort"
+
+// PackageNode is one package touched by a walk.
+type PackageNode struct {
+	Package     string `json:"package"`
+	Definitions int    `json:"definitions"`
+}
+
+// PackageEdge is the set of walked call edges from one package to another.
+type PackageEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Calls int    `json:"calls"` // total call sites
+	Edges int    `json:"edges"` // distinct caller/callee pairs
+}
+
+// PackageGraph is a walk collapsed to package level. Calls within a package
+// are counted in Internal rather than listed as edges.
+type PackageGraph struct {
+	Packages []PackageNode `json:"packages"`
+	Edges    []PackageEdge `json:"edges,omitempty"`
+	Internal int           `json:"internal_calls"`
+}
+
+// FindDefinitionsAny returns the callable definitions matching any of the
+// patterns, without duplicates.
+func (g *Graph) FindDefinitionsAny(patterns []string, regexMode bool) ([]Definition, error) {
+	seen := map[string]bool{}
+	matches := make([]Definition, 0, 16)
+	for _, pattern := range patterns {
+		found, err := g.FindDefinitions(pattern, regexMode)
+		if err != nil {
+			return nil, err
+		}
+		for _, definition := range found {
+			if seen[definition.ID] {
+				continue
+			}
+			seen[definition.ID] = true
+			matches = append(matches, definition)
+		}
+	}
+	sortDefinitions(matches)
+	return matches, nil
+}
+
+// AggregatePackages collapses the walk's nodes and edges to packages.
+func (w Walk) AggregatePackages() PackageGraph {
+	counts := map[string]int{}
+	for _, node := range w.Nodes {
+		counts[node.Package]++
+	}
+
+	result := PackageGraph{Packages: make([]PackageNode, 0, len(counts))}
+	for pkg, count := range counts {
+		result.Packages = append(result.Packages, PackageNode{Package: pkg, Definitions: count})
+	}
+	sort.Slice(result.Packages, func(i, j int) bool {
+		return result.Packages[i].Package < result.Packages[j].Package
+	})
+	if w.graph == nil {
+		return result
+	}
+
+	byPair := map[[2]string]*PackageEdge{}
+	for _, edge := range w.Edges {
+		from := w.graph.EdgeCaller(edge).Package
+		to := w.graph.EdgeCallee(edge).Package
+		if from == to {
+			result.Internal += edge.Count
+			continue
+		}
+		pair := [2]string{from, to}
+		aggregated, ok := byPair[pair]
+		if !ok {
+			aggregated = &PackageEdge{From: from, To: to}
+			byPair[pair] = aggregated
+		}
+		aggregated.Calls += edge.Count
+		aggregated.Edges++
+	}
+
+	result.Edges = make([]PackageEdge, 0, len(byPair))
+	for _, edge := range byPair {
+		result.Edges = append(result.Edges, *edge)
+	}
+	sort.Slice(result.Edges, func(i, j int) bool {
+		if result.Edges[i].From == result.Edges[j].From {
+			return result.Edges[i].To < result.Edges[j].To
+		}
+		return result.Edges[i].From < result.Edges[j].From
+	})
+	return result
+}
@@ -0,0 +1,105 @@
+package xref
+
+import (
+	"sort"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// FieldUsage is a struct field or class member together with the number of
+// reads and writes of its name that could reach it.
+type FieldUsage struct {
+	Definition
+	Container string `json:"container,omitempty"`
+	Reads     int    `json:"reads"`
+	Writes    int    `json:"writes"`
+}
+
+// FieldUsages counts the reference.field accesses that may refer to each
+// field_definition in the index. Accesses are matched by name, since the
+// index does not know the receiver's type: a public field matches accesses in
+// any file of the same language, a private one only those in its own package
+// (Go) or file (other languages). Name matching over-counts, so a field with
+// no reads is never read, but a field with reads may still be unused.
+func FieldUsages(idx *model.Index) []FieldUsage {
+	if idx == nil {
+		return nil
+	}
+
+	type access struct {
+		file     string
+		pkg      string
+		language string
+		write    bool
+	}
+	accesses := map[string][]access{}
+	for _, file := range idx.Files {
+		pkg := packageFromPath(file.Path)
+		language := LanguageFamily(file.Language)
+		for _, reference := range file.References {
+			if reference.Kind != "reference.field" {
+				continue
+			}
+			accesses[reference.Name] = append(accesses[reference.Name], access{
+				file:     file.Path,
+				pkg:      pkg,
+				language: language,
+				write:    reference.Access == "write",
+			})
+		}
+	}
+
+	var usages []FieldUsage
+	for _, file := range idx.Files {
+		pkg := packageFromPath(file.Path)
+		language := LanguageFamily(file.Language)
+		for _, symbol := range file.Symbols {
+			if symbol.Kind != "field_definition" {
+				continue
+			}
+			usage := FieldUsage{
+				Definition: definitionFromSymbol(file.Path, pkg, symbol),
+				Container:  symbol.ContainerPath,
+			}
+			private := symbol.Visibility == "private"
+			for _, candidate := range accesses[symbol.Name] {
+				if candidate.language != language {
+					continue
+				}
+				if private && file.Language == "go" && candidate.pkg != pkg {
+					continue
+				}
+				if private && file.Language != "go" && candidate.file != file.Path {
+					continue
+				}
+				if candidate.write {
+					usage.Writes++
+				} else {
+					usage.Reads++
+				}
+			}
+			usages = append(usages, usage)
+		}
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].File == usages[j].File {
+			if usages[i].StartLine == usages[j].StartLine {
+				return usages[i].Name < usages[j].Name
+			}
+			return usages[i].StartLine < usages[j].StartLine
+		}
+		return usages[i].File < usages[j].File
+	})
+	return usages
+}
+
+// LanguageFamily groups languages whose files can access each other's
+// members, mapping the JavaScript dialects to "javascript".
+func LanguageFamily(language string) string {
+	switch language {
+	case "javascript", "typescript", "tsx", "jsx":
+		return "javascript"
+	}
+	return language
+}
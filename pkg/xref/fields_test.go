@@ -0,0 +1,72 @@
+package xref
+
+import (
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+func TestFieldUsages(t *testing.T) {
+	idx := &model.Index{
+		Files: []model.FileSummary{
+			{
+				Path:     "server/server.go",
+				Language: "go",
+				Symbols: []model.Symbol{
+					{Kind: "type_definition", Name: "Server", StartLine: 3, EndLine: 7},
+					{Kind: "field_definition", Name: "Name", ContainerPath: "Server", Visibility: "public", Exported: true, StartLine: 4, EndLine: 4},
+					{Kind: "field_definition", Name: "addr", ContainerPath: "Server", Visibility: "private", StartLine: 5, EndLine: 5},
+					{Kind: "field_definition", Name: "count", ContainerPath: "Server", Visibility: "private", StartLine: 6, EndLine: 6},
+				},
+				References: []model.Reference{
+					{Kind: "reference.field", Name: "count", Access: "write", StartLine: 10},
+					{Kind: "reference.call", Name: "addr", StartLine: 11},
+				},
+			},
+			{
+				Path:     "client/client.go",
+				Language: "go",
+				References: []model.Reference{
+					{Kind: "reference.field", Name: "Name", Access: "read", StartLine: 5},
+					{Kind: "reference.field", Name: "count", Access: "read", StartLine: 6},
+				},
+			},
+			{
+				Path:     "web/app.ts",
+				Language: "typescript",
+				References: []model.Reference{
+					{Kind: "reference.field", Name: "addr", Access: "read", StartLine: 2},
+				},
+			},
+		},
+	}
+
+	got := map[string][2]int{}
+	for _, usage := range FieldUsages(idx) {
+		got[usage.Container+"."+usage.Name] = [2]int{usage.Reads, usage.Writes}
+	}
+	want := map[string][2]int{
+		"Server.Name":  {1, 0}, // public: read from another package
+		"Server.addr":  {0, 0}, // calls and other languages do not count
+		"Server.count": {0, 1}, // private: the read in client/ is another package
+	}
+	for name, counts := range want {
+		if got[name] != counts {
+			t.Errorf("%s: reads/writes = %v, want %v", name, got[name], counts)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected usages %v", got)
+	}
+}
+
+func TestLanguageFamily(t *testing.T) {
+	for _, language := range []string{"javascript", "typescript", "tsx", "jsx"} {
+		if got := LanguageFamily(language); got != "javascript" {
+			t.Errorf("LanguageFamily(%q) = %q, want javascript", language, got)
+		}
+	}
+	if got := LanguageFamily("go"); got != "go" {
+		t.Errorf("LanguageFamily(go) = %q, want go", got)
+	}
+}
@@ -0,0 +1,374 @@
+package xref
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// Incremental keeps a call graph current as files change, for long-running
+// processes such as the daemon and the LSP server. Resolving call references
+// dominates Build, so Incremental resolves per file and, when a file changes,
+// re-resolves only that file and the files calling a name it defined before
+// or defines now. The result is the graph Build would return for the same
+// files. Incremental is not safe for concurrent use; the graphs it returns
+// are never modified afterwards.
+type Incremental struct {
+	root       string
+	modulePath string
+	files      map[string]model.FileSummary
+	calls      map[string][]resolvedCall
+	unresolved map[string][]UnresolvedCall
+	// callers maps a called name to the files with call references to it.
+	callers map[string]map[string]bool
+
+	// table is updated in place as files change: a file's definitions are
+	// cleared where they sit and its new ones appended, so indices held by
+	// the lookup maps stay valid. defsByFile records where each file's
+	// definitions are, and packageFiles how many files each package has.
+	table        definitionTable
+	defsByFile   map[string][]int
+	packageFiles map[string]int
+	cleared      int
+
+	graph *Graph
+}
+
+// NewIncremental resolves every file of idx and returns an Incremental
+// holding the result.
+func NewIncremental(idx *model.Index) (*Incremental, error) {
+	if idx == nil {
+		return nil, fmt.Errorf("index is nil")
+	}
+	inc := &Incremental{
+		root:       idx.Root,
+		modulePath: modulePathFromRoot(idx.Root),
+		files:      make(map[string]model.FileSummary, len(idx.Files)),
+		calls:      make(map[string][]resolvedCall, len(idx.Files)),
+		unresolved: map[string][]UnresolvedCall{},
+		callers:    map[string]map[string]bool{},
+	}
+	affected := make(map[string]bool, len(idx.Files))
+	for _, file := range idx.Files {
+		inc.files[file.Path] = file
+		inc.indexCallers(file, true)
+		affected[file.Path] = true
+	}
+	inc.table = newDefinitionTable(inc.sortedFiles())
+	inc.packageFiles = make(map[string]int, len(inc.table.packages))
+	for path := range inc.files {
+		inc.packageFiles[packageFromPath(path)]++
+	}
+	inc.defsByFile = definitionsByFile(inc.table.definitions)
+	inc.resolve(affected)
+	return inc, nil
+}
+
+// Sync brings the graph up to date with idx, adding, replacing, and removing
+// only the files whose summaries differ from the ones it holds. A file counts
+// as unchanged when its content hash matches, or, without hashes, when the
+// summaries are equal. idx must have the root the Incremental was created
+// with.
+func (inc *Incremental) Sync(idx *model.Index) {
+	if idx == nil {
+		return
+	}
+	seen := make(map[string]bool, len(idx.Files))
+	for _, file := range idx.Files {
+		seen[file.Path] = true
+		if previous, ok := inc.files[file.Path]; ok && sameSummary(previous, file) {
+			continue
+		}
+		inc.AddFileEdges(file)
+	}
+	for path := range inc.files {
+		if !seen[path] {
+			inc.RemoveFileEdges(path)
+		}
+	}
+}
+
+func sameSummary(previous, next model.FileSummary) bool {
+	if previous.ContentHash != "" && next.ContentHash != "" {
+		return previous.ContentHash == next.ContentHash
+	}
+	return reflect.DeepEqual(previous, next)
+}
+
+// RemoveFileEdges drops path's definitions and the calls made from it, and
+// re-resolves calls elsewhere that targeted its definitions.
+func (inc *Incremental) RemoveFileEdges(path string) {
+	previous, ok := inc.files[path]
+	if !ok {
+		return
+	}
+	inc.indexCallers(previous, false)
+	delete(inc.files, path)
+	delete(inc.calls, path)
+	delete(inc.unresolved, path)
+	inc.update(previous, model.FileSummary{})
+}
+
+// AddFileEdges adds file, replacing any earlier summary of the same path,
+// resolves its calls, and re-resolves calls elsewhere whose target may have
+// changed.
+func (inc *Incremental) AddFileEdges(file model.FileSummary) {
+	previous, existed := inc.files[file.Path]
+	if existed {
+		inc.indexCallers(previous, false)
+	}
+	inc.files[file.Path] = file
+	inc.indexCallers(file, true)
+	inc.update(previous, file)
+}
+
+// Graph returns the current call graph. It is assembled on the first call
+// after a change and shared until the next one.
+func (inc *Incremental) Graph() *Graph {
+	if inc.graph != nil {
+		return inc.graph
+	}
+	files := inc.sortedFiles()
+	calls := make([][]resolvedCall, 0, len(files))
+	unresolved := make([]UnresolvedCall, 0, 32)
+	for _, file := range files {
+		calls = append(calls, inc.calls[file.Path])
+		unresolved = append(unresolved, inc.unresolved[file.Path]...)
+	}
+	live := make([]Definition, 0, len(inc.table.defByID))
+	for _, def := range inc.table.definitions {
+		if def.ID != "" {
+			live = append(live, def)
+		}
+	}
+	// The graph gets its own table, so later updates never reach it.
+	table := indexDefinitions(live)
+	graph := table.graph(inc.root, calls, unresolved)
+	inc.graph = &graph
+	return inc.graph
+}
+
+// update swaps the definitions of previous for those of next in the table,
+// either of which may be empty, and re-resolves the files that could be
+// affected: next itself and every caller of a callable either defines. A new
+// or vanished package changes how qualified calls resolve, so it re-resolves
+// everything.
+func (inc *Incremental) update(previous, next model.FileSummary) {
+	packagesBefore := len(inc.table.packages)
+	if previous.Path != "" {
+		inc.removeDefinitions(previous.Path)
+	}
+	if next.Path != "" {
+		inc.addDefinitions(next)
+	}
+	if inc.cleared > len(inc.table.definitions)/2 {
+		inc.compact()
+	}
+	inc.graph = nil
+
+	affected := map[string]bool{}
+	if len(inc.table.packages) != packagesBefore || !samePackages(inc.table.packages, previous, next) {
+		for path := range inc.files {
+			affected[path] = true
+		}
+	} else {
+		if next.Path != "" {
+			affected[next.Path] = true
+		}
+		for _, summary := range []model.FileSummary{previous, next} {
+			for _, symbol := range summary.Symbols {
+				if !isCallableKind(symbol.Kind) {
+					continue
+				}
+				for path := range inc.callers[symbol.Name] {
+					affected[path] = true
+				}
+			}
+		}
+	}
+	inc.resolve(affected)
+}
+
+// samePackages reports whether the packages of previous and next, when set,
+// are still in packages, i.e. the change neither added nor removed one.
+func samePackages(packages map[string]struct{}, previous, next model.FileSummary) bool {
+	for _, summary := range []model.FileSummary{previous, next} {
+		if summary.Path == "" {
+			continue
+		}
+		if _, ok := packages[packageFromPath(summary.Path)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// removeDefinitions clears the definitions of path from the table and drops
+// them from its lookup maps.
+func (inc *Incremental) removeDefinitions(path string) {
+	t := &inc.table
+	for _, i := range inc.defsByFile[path] {
+		def := &t.definitions[i]
+		if t.defByID[def.ID] == i {
+			delete(t.defByID, def.ID)
+		}
+		if def.Callable {
+			removeIndex(t.callableByName, def.Name, i)
+			removeIndex(t.callableByPkgName, keyPackageName(def.Package, def.Name), i)
+			removeIndex(t.callableByFileName, keyFileName(def.File, def.Name), i)
+			if def.Kind == "method_definition" && def.Receiver != "" {
+				removeIndex(t.methodsByName, def.Name, i)
+			}
+		}
+		*def = Definition{}
+		inc.cleared++
+	}
+	delete(inc.defsByFile, path)
+	delete(t.callableByFile, path)
+	delete(t.languages, path)
+	pkg := packageFromPath(path)
+	if inc.packageFiles[pkg]--; inc.packageFiles[pkg] <= 0 {
+		delete(inc.packageFiles, pkg)
+		delete(t.packages, pkg)
+	}
+}
+
+// addDefinitions appends the definitions of file to the table and files them
+// in the lookup maps, keeping each list in definition order as Build would.
+func (inc *Incremental) addDefinitions(file model.FileSummary) {
+	t := &inc.table
+	pkg := packageFromPath(file.Path)
+	inc.packageFiles[pkg]++
+	t.packages[pkg] = struct{}{}
+	t.languages[file.Path] = LanguageFamily(file.Language)
+
+	defs := make([]Definition, 0, len(file.Symbols))
+	for _, symbol := range file.Symbols {
+		defs = append(defs, definitionFromSymbol(file.Path, pkg, symbol))
+	}
+	sortDefinitions(defs)
+	indices := make([]int, 0, len(defs))
+	for _, def := range defs {
+		i := len(t.definitions)
+		t.definitions = append(t.definitions, def)
+		indices = append(indices, i)
+		t.defByID[def.ID] = i
+		if !def.Callable {
+			continue
+		}
+		insertIndex(t.callableByName, def.Name, i, t.definitions)
+		insertIndex(t.callableByPkgName, keyPackageName(def.Package, def.Name), i, t.definitions)
+		insertIndex(t.callableByFileName, keyFileName(def.File, def.Name), i, t.definitions)
+		t.callableByFile[def.File] = append(t.callableByFile[def.File], i)
+		if def.Kind == "method_definition" && def.Receiver != "" {
+			insertIndex(t.methodsByName, def.Name, i, t.definitions)
+		}
+	}
+	if len(indices) > 0 {
+		inc.defsByFile[file.Path] = indices
+	}
+}
+
+// compact rebuilds the table without the cleared definitions once they make
+// up half of it.
+func (inc *Incremental) compact() {
+	live := make([]Definition, 0, len(inc.table.defByID))
+	for _, def := range inc.table.definitions {
+		if def.ID != "" {
+			live = append(live, def)
+		}
+	}
+	table := indexDefinitions(live)
+	table.packages = inc.table.packages
+	table.languages = inc.table.languages
+	inc.table = table
+	inc.defsByFile = definitionsByFile(table.definitions)
+	inc.cleared = 0
+}
+
+// definitionsByFile maps each file to the indices of its definitions.
+func definitionsByFile(definitions []Definition) map[string][]int {
+	byFile := map[string][]int{}
+	for i, def := range definitions {
+		byFile[def.File] = append(byFile[def.File], i)
+	}
+	return byFile
+}
+
+// insertIndex adds i to the list at key, after any definition that sorts
+// before or equal to definitions[i].
+func insertIndex(lists map[string][]int, key string, i int, definitions []Definition) {
+	list := lists[key]
+	at := sort.Search(len(list), func(k int) bool { return definitionLess(&definitions[i], &definitions[list[k]]) })
+	list = append(list, 0)
+	copy(list[at+1:], list[at:])
+	list[at] = i
+	lists[key] = list
+}
+
+// removeIndex drops i from the list at key, and the key once it is empty.
+func removeIndex(lists map[string][]int, key string, i int) {
+	list := lists[key]
+	for k, v := range list {
+		if v == i {
+			list = append(list[:k], list[k+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(lists, key)
+		return
+	}
+	lists[key] = list
+}
+
+func (inc *Incremental) resolve(paths map[string]bool) {
+	for path := range paths {
+		file, ok := inc.files[path]
+		if !ok {
+			continue
+		}
+		calls, unresolved := inc.table.resolveFile(file, inc.modulePath)
+		inc.calls[path] = calls
+		if len(unresolved) > 0 {
+			inc.unresolved[path] = unresolved
+		} else {
+			delete(inc.unresolved, path)
+		}
+	}
+}
+
+// indexCallers records (add) or forgets the call references of file in the
+// callers index.
+func (inc *Incremental) indexCallers(file model.FileSummary, add bool) {
+	for _, ref := range file.References {
+		if !isCallReference(ref.Kind) {
+			continue
+		}
+		name := baseName(ref.Name)
+		files := inc.callers[name]
+		if add {
+			if files == nil {
+				files = map[string]bool{}
+				inc.callers[name] = files
+			}
+			files[file.Path] = true
+			continue
+		}
+		delete(files, file.Path)
+		if len(files) == 0 {
+			delete(inc.callers, name)
+		}
+	}
+}
+
+func (inc *Incremental) sortedFiles() []model.FileSummary {
+	files := make([]model.FileSummary, 0, len(inc.files))
+	for _, file := range inc.files {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
@@ -0,0 +1,147 @@
+package xref
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+// callerFile returns a summary of path defining each function in calls, one
+// per three lines, with a call on its middle line to each listed callee.
+func callerFile(path string, calls map[string][]string) model.FileSummary {
+	names := make([]string, 0, len(calls))
+	for name := range calls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	file := model.FileSummary{Path: path}
+	for i, name := range names {
+		start := 3*i + 1
+		file.Symbols = append(file.Symbols, model.Symbol{File: path, Kind: "function_definition", Name: name, StartLine: start, EndLine: start + 2})
+		for j, callee := range calls[name] {
+			file.References = append(file.References, model.Reference{File: path, Kind: "reference.call", Name: callee, StartLine: start + 1, EndLine: start + 1, StartColumn: j, EndColumn: j + 1})
+		}
+	}
+	return file
+}
+
+func TestIncrementalMatchesBuild(t *testing.T) {
+	files := map[string]model.FileSummary{}
+	put := func(file model.FileSummary) { files[file.Path] = file }
+	put(callerFile("a.go", map[string][]string{"A": {"B", "Missing"}}))
+	put(callerFile("b.go", map[string][]string{"B": nil, "Shared": nil}))
+	put(callerFile("c/c.go", map[string][]string{"C": {"A", "B"}, "Shared": {"Shared"}}))
+
+	index := func() *model.Index {
+		idx := &model.Index{Root: "/tmp/repo"}
+		for _, file := range files {
+			idx.Files = append(idx.Files, file)
+		}
+		sort.Slice(idx.Files, func(i, j int) bool { return idx.Files[i].Path < idx.Files[j].Path })
+		return idx
+	}
+	inc, err := NewIncremental(index())
+	if err != nil {
+		t.Fatalf("NewIncremental returned error: %v", err)
+	}
+
+	check := func(step string) {
+		t.Helper()
+		want, err := Build(index())
+		if err != nil {
+			t.Fatalf("%s: Build returned error: %v", step, err)
+		}
+		got := inc.Graph()
+		if !reflect.DeepEqual(got.MaterializeEdges(got.Edges), want.MaterializeEdges(want.Edges)) {
+			t.Fatalf("%s: edges differ\n got: %+v\nwant: %+v", step, got.MaterializeEdges(got.Edges), want.MaterializeEdges(want.Edges))
+		}
+		if !reflect.DeepEqual(got.Unresolved, want.Unresolved) {
+			t.Fatalf("%s: unresolved differ\n got: %+v\nwant: %+v", step, got.Unresolved, want.Unresolved)
+		}
+		if !reflect.DeepEqual(got.Definitions, want.Definitions) {
+			t.Fatalf("%s: definitions differ", step)
+		}
+	}
+	check("initial")
+
+	inc.RemoveFileEdges("b.go")
+	delete(files, "b.go")
+	check("remove b.go")
+
+	moved := callerFile("b2.go", map[string][]string{"B": {"C"}})
+	inc.AddFileEdges(moved)
+	put(moved)
+	check("add b2.go")
+
+	edited := callerFile("a.go", map[string][]string{"A": {"C"}, "Missing": nil})
+	inc.AddFileEdges(edited)
+	put(edited)
+	check("edit a.go")
+
+	newPackage := callerFile("d/d.go", map[string][]string{"D": {"Shared", "A"}})
+	inc.AddFileEdges(newPackage)
+	put(newPackage)
+	check("add package d")
+
+	inc.RemoveFileEdges("c/c.go")
+	delete(files, "c/c.go")
+	check("remove package c")
+
+	if inc.Graph() != inc.Graph() {
+		t.Fatal("expected Graph to be reused until the next change")
+	}
+}
+
+func TestIncrementalSync(t *testing.T) {
+	idx := &model.Index{Root: "/tmp/repo", Files: []model.FileSummary{
+		callerFile("a.go", map[string][]string{"A": {"B"}}),
+		callerFile("b.go", map[string][]string{"B": nil}),
+	}}
+	inc, err := NewIncremental(idx)
+	if err != nil {
+		t.Fatalf("NewIncremental returned error: %v", err)
+	}
+	unchanged := inc.Graph()
+	inc.Sync(idx)
+	if inc.Graph() != unchanged {
+		t.Fatal("expected Sync with an unchanged index to keep the graph")
+	}
+
+	// Editing b.go over and over clears more definitions than stay live, so
+	// the table is compacted along the way.
+	for i := 0; i < 8; i++ {
+		callees := []string{"A"}
+		if i%2 == 0 {
+			callees = nil
+		}
+		idx = &model.Index{Root: "/tmp/repo", Files: []model.FileSummary{
+			idx.Files[0],
+			callerFile("b.go", map[string][]string{"B": callees, "Extra": {"B"}}),
+			callerFile("c/c.go", map[string][]string{"C": {"Extra"}}),
+		}}
+		inc.Sync(idx)
+	}
+	idx = &model.Index{Root: "/tmp/repo", Files: idx.Files[1:]}
+	inc.Sync(idx)
+
+	want, err := Build(idx)
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	got := inc.Graph()
+	if !reflect.DeepEqual(got.MaterializeEdges(got.Edges), want.MaterializeEdges(want.Edges)) {
+		t.Fatalf("edges differ\n got: %+v\nwant: %+v", got.MaterializeEdges(got.Edges), want.MaterializeEdges(want.Edges))
+	}
+	if !reflect.DeepEqual(got.Unresolved, want.Unresolved) {
+		t.Fatalf("unresolved differ\n got: %+v\nwant: %+v", got.Unresolved, want.Unresolved)
+	}
+	if !reflect.DeepEqual(got.Definitions, want.Definitions) {
+		t.Fatalf("definitions differ\n got: %+v\nwant: %+v", got.Definitions, want.Definitions)
+	}
+	if inc.cleared > len(inc.table.definitions)/2 {
+		t.Fatalf("expected the table to be compacted, %d of %d definitions cleared", inc.cleared, len(inc.table.definitions))
+	}
+}
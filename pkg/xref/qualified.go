@@ -0,0 +1,135 @@
+package xref
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/odvcencio/gts-suite/pkg/model"
+)
+
+var importAliasPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+var majorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+var versionSuffixPattern = regexp.MustCompile(`\.v[0-9]+$`)
+
+// importAlias returns the default identifier an import path is referred to
+// by, e.g. "xref" for ".../pkg/xref", "yaml" for "gopkg.in/yaml.v3", or
+// "sqlite3" for ".../go-sqlite3".
+func importAlias(importPath string) string {
+	segments := strings.Split(strings.Trim(importPath, "/"), "/")
+	alias := segments[len(segments)-1]
+	if majorVersionPattern.MatchString(alias) && len(segments) > 1 {
+		alias = segments[len(segments)-2]
+	}
+	alias = versionSuffixPattern.ReplaceAllString(alias, "")
+	alias = strings.TrimPrefix(alias, "go-")
+	if !importAliasPattern.MatchString(alias) {
+		return ""
+	}
+	return alias
+}
+
+// resolveQualifiedCallee resolves a call using the qualifier recorded on its
+// reference. A result with neither ok nor a reason means the qualifier gave
+// no usable hint and name-based resolution should run instead.
+func resolveQualifiedCallee(
+	ref model.Reference,
+	caller Definition,
+	scope importScope,
+	modulePath string,
+	packages map[string]struct{},
+	definitions []Definition,
+	callableByPkgName map[string][]int,
+	callableByName map[string][]int,
+) calleeResolution {
+	qualifier := strings.TrimSpace(ref.Qualifier)
+	if qualifier == "" {
+		return calleeResolution{}
+	}
+
+	if importPath, ok := scope.aliases[qualifier]; ok {
+		pkg, local := packageFromImportPath(importPath, modulePath)
+		if _, indexed := packages[pkg]; !local || !indexed {
+			return calleeResolution{reason: "external_package"}
+		}
+		candidates := functionIndices(definitions, uniqueDefIndices(definitions, callableByPkgName[keyPackageName(pkg, ref.Name)]))
+		switch len(candidates) {
+		case 0:
+			return calleeResolution{reason: "not_found"}
+		case 1:
+			return calleeResolution{idx: candidates[0], resolution: "qualified", ok: true}
+		default:
+			return calleeResolution{reason: "ambiguous_qualified", candidateCount: len(candidates)}
+		}
+	}
+
+	typeName := qualifierTypeName(qualifier, caller)
+	if typeName == "" {
+		return calleeResolution{}
+	}
+	candidates := methodsOfType(definitions, uniqueDefIndices(definitions, callableByName[ref.Name]), typeName)
+	if len(candidates) > 1 {
+		if local := indicesInPackage(definitions, candidates, caller.Package); len(local) > 0 {
+			candidates = local
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return calleeResolution{}
+	case 1:
+		return calleeResolution{idx: candidates[0], resolution: "receiver", ok: true}
+	default:
+		return calleeResolution{candidates: candidates, polyScope: "receiver", ok: true}
+	}
+}
+
+// qualifierTypeName returns the type a qualifier refers to: the caller's own
+// type for self, this, or the caller's Go receiver variable, otherwise the
+// last segment of the qualifier, which matches static calls like Foo::new.
+func qualifierTypeName(qualifier string, caller Definition) string {
+	if caller.Receiver != "" {
+		switch qualifier {
+		case "self", "this", "cls", "Self":
+			return model.ReceiverType(caller.Receiver)
+		}
+		if fields := strings.Fields(caller.Receiver); len(fields) > 1 && fields[0] == qualifier {
+			return model.ReceiverType(caller.Receiver)
+		}
+	}
+	for _, separator := range []string{"::", "->", "?.", "."} {
+		if idx := strings.LastIndex(qualifier, separator); idx >= 0 {
+			qualifier = qualifier[idx+len(separator):]
+		}
+	}
+	return qualifier
+}
+
+func functionIndices(definitions []Definition, candidates []int) []int {
+	out := make([]int, 0, len(candidates))
+	for _, ci := range candidates {
+		if definitions[ci].Receiver == "" {
+			out = append(out, ci)
+		}
+	}
+	return out
+}
+
+func methodsOfType(definitions []Definition, candidates []int, typeName string) []int {
+	out := make([]int, 0, len(candidates))
+	for _, ci := range candidates {
+		receiver := definitions[ci].Receiver
+		if receiver != "" && model.ReceiverType(receiver) == typeName {
+			out = append(out, ci)
+		}
+	}
+	return out
+}
+
+func indicesInPackage(definitions []Definition, candidates []int, pkg string) []int {
+	out := make([]int, 0, len(candidates))
+	for _, ci := range candidates {
+		if definitions[ci].Package == pkg {
+			out = append(out, ci)
+		}
+	}
+	return out
+}
@@ -24,6 +24,7 @@ type Definition struct {
 	StartLine int    `json:"start_line"`
 	EndLine   int    `json:"end_line"`
 	Callable  bool   `json:"callable"`
+	Exported  bool   `json:"exported,omitempty"`
 }
 
 type CallSample struct {
@@ -60,6 +61,7 @@ type UnresolvedCall struct {
 	StartColumn    int         `json:"start_column"`
 	EndLine        int         `json:"end_line"`
 	EndColumn      int         `json:"end_column"`
+	Qualifier      string      `json:"qualifier,omitempty"`
 	Caller         *Definition `json:"caller,omitempty"`
 	Reason         string      `json:"reason"`
 	CandidateCount int         `json:"candidate_count,omitempty"`
@@ -133,6 +135,7 @@ type importScope struct {
 	paths        map[string]struct{}
 	packages     map[string]struct{}
 	tokens       map[string]struct{}
+	aliases      map[string]string // default identifier -> import path
 	hasPathHints bool
 }
 
@@ -154,128 +157,286 @@ func (w Walk) MaterializedEdges() []MaterializedEdge {
 }
 
 func Build(idx *model.Index) (Graph, error) {
+	return BuildWithOptions(idx, Options{})
+}
+
+// Options controls BuildWithOptions.
+type Options struct {
+	// Dynamic adds edges for dynamic dispatch: a method call through a value,
+	// such as s.Get(), also links the caller to every other method of the same
+	// name and parameter count in the same language, with resolution
+	// "interface". The index does not record interface method sets, so these
+	// are the implementations the call could reach, not proven targets. They
+	// let a reverse walk from a concrete method find callers that reach it
+	// through an interface.
+	Dynamic bool
+}
+
+// BuildWithOptions is Build with options.
+func BuildWithOptions(idx *model.Index, opts Options) (Graph, error) {
 	if idx == nil {
 		return Graph{}, fmt.Errorf("index is nil")
 	}
 
-	definitions := make([]Definition, 0, idx.SymbolCount())
-	defByID := map[string]int{}
-	callableByName := map[string][]int{}
-	callableByPkgName := map[string][]int{}
-	callableByFileName := map[string][]int{}
-	callableByFile := map[string][]int{}
-
+	table := newDefinitionTable(idx.Files)
+	table.dynamic = opts.Dynamic
+	modulePath := modulePathFromRoot(idx.Root)
+	calls := make([][]resolvedCall, 0, len(idx.Files))
+	unresolved := make([]UnresolvedCall, 0, 32)
 	for _, file := range idx.Files {
+		fileCalls, fileUnresolved := table.resolveFile(file, modulePath)
+		calls = append(calls, fileCalls)
+		unresolved = append(unresolved, fileUnresolved...)
+	}
+	return table.graph(idx.Root, calls, unresolved), nil
+}
+
+// definitionTable holds the sorted definitions of a set of files and the
+// lookup maps call resolution uses. Values are indices into definitions.
+type definitionTable struct {
+	definitions        []Definition
+	defByID            map[string]int
+	callableByName     map[string][]int // name -> callables
+	callableByPkgName  map[string][]int // pkg\x00name -> callables
+	callableByFileName map[string][]int // file\x00name -> callables
+	callableByFile     map[string][]int // file -> callables
+	packages           map[string]struct{}
+
+	// Dynamic dispatch, for Options.Dynamic.
+	dynamic       bool
+	methodsByName map[string][]int  // name -> methods with a receiver
+	languages     map[string]string // file -> language family
+}
+
+func newDefinitionTable(files []model.FileSummary) definitionTable {
+	symbolCount := 0
+	for _, file := range files {
+		symbolCount += len(file.Symbols)
+	}
+	definitions := make([]Definition, 0, symbolCount)
+	languages := make(map[string]string, len(files))
+	packages := make(map[string]struct{}, len(files))
+	for _, file := range files {
 		pkg := packageFromPath(file.Path)
+		packages[pkg] = struct{}{}
+		languages[file.Path] = LanguageFamily(file.Language)
 		for _, symbol := range file.Symbols {
-			def := definitionFromSymbol(file.Path, pkg, symbol)
-			idx := len(definitions)
-			definitions = append(definitions, def)
-			defByID[def.ID] = idx
-
-			if !def.Callable {
-				continue
-			}
-			callableByName[def.Name] = append(callableByName[def.Name], idx)
-			callableByPkgName[keyPackageName(pkg, def.Name)] = append(callableByPkgName[keyPackageName(pkg, def.Name)], idx)
-			callableByFileName[keyFileName(def.File, def.Name)] = append(callableByFileName[keyFileName(def.File, def.Name)], idx)
-			callableByFile[def.File] = append(callableByFile[def.File], idx)
+			definitions = append(definitions, definitionFromSymbol(file.Path, pkg, symbol))
 		}
 	}
 
-	sortDefinitions(definitions)
-	// Rebuild defByID after sorting since indices changed.
-	for i := range definitions {
-		defByID[definitions[i].ID] = i
-	}
-	// Rebuild callable maps after sorting.
-	callableByName = map[string][]int{}
-	callableByPkgName = map[string][]int{}
-	callableByFileName = map[string][]int{}
-	callableByFile = map[string][]int{}
-	for i := range definitions {
-		def := &definitions[i]
+	table := indexDefinitions(definitions)
+	table.languages = languages
+	table.packages = packages
+	return table
+}
+
+// indexDefinitions sorts definitions and returns a table over them with the
+// lookup maps filled in; packages and languages are left to the caller.
+func indexDefinitions(definitions []Definition) definitionTable {
+	table := definitionTable{
+		definitions:        definitions,
+		defByID:            make(map[string]int, len(definitions)),
+		callableByName:     map[string][]int{},
+		callableByPkgName:  map[string][]int{},
+		callableByFileName: map[string][]int{},
+		callableByFile:     map[string][]int{},
+		methodsByName:      map[string][]int{},
+	}
+	sortDefinitions(table.definitions)
+	for i := range table.definitions {
+		def := &table.definitions[i]
+		table.defByID[def.ID] = i
 		if !def.Callable {
 			continue
 		}
-		callableByName[def.Name] = append(callableByName[def.Name], i)
-		callableByPkgName[keyPackageName(def.Package, def.Name)] = append(callableByPkgName[keyPackageName(def.Package, def.Name)], i)
-		callableByFileName[keyFileName(def.File, def.Name)] = append(callableByFileName[keyFileName(def.File, def.Name)], i)
-		callableByFile[def.File] = append(callableByFile[def.File], i)
+		table.callableByName[def.Name] = append(table.callableByName[def.Name], i)
+		table.callableByPkgName[keyPackageName(def.Package, def.Name)] = append(table.callableByPkgName[keyPackageName(def.Package, def.Name)], i)
+		table.callableByFileName[keyFileName(def.File, def.Name)] = append(table.callableByFileName[keyFileName(def.File, def.Name)], i)
+		table.callableByFile[def.File] = append(table.callableByFile[def.File], i)
+		if def.Kind == "method_definition" && def.Receiver != "" {
+			table.methodsByName[def.Name] = append(table.methodsByName[def.Name], i)
+		}
 	}
+	return table
+}
 
-	edgeByPair := map[string]*internalEdge{}
-	unresolved := make([]UnresolvedCall, 0, 32)
-	modulePath := modulePathFromRoot(idx.Root)
-
-	for _, file := range idx.Files {
-		pkg := packageFromPath(file.Path)
-		scope := buildImportScope(file.Imports, modulePath)
-		callableIndices := callableByFile[file.Path]
-		for _, ref := range file.References {
-			if !isCallReference(ref.Kind) {
+// implementations returns the methods a dynamically dispatched call to one
+// of callees could also reach: methods of other receivers with the same name,
+// parameter count, and language. Callees that are not methods have none.
+func (t *definitionTable) implementations(callees []int) []int {
+	seen := make(map[int]bool, len(callees))
+	for _, ci := range callees {
+		seen[ci] = true
+	}
+	var impls []int
+	for _, ci := range callees {
+		callee := &t.definitions[ci]
+		if callee.Kind != "method_definition" || callee.Receiver == "" {
+			continue
+		}
+		arity := parameterCount(callee.Signature, callee.Name)
+		language := t.languages[callee.File]
+		for _, mi := range t.methodsByName[callee.Name] {
+			method := &t.definitions[mi]
+			if seen[mi] || method.Receiver == callee.Receiver || t.languages[method.File] != language {
 				continue
 			}
-
-			callerIdx := findEnclosingCallableIdx(definitions, callableIndices, ref.StartLine)
-			if callerIdx == -1 {
-				unresolved = append(unresolved, unresolvedFromRef(file.Path, pkg, ref, nil, "outside_callable", 0))
+			if n := parameterCount(method.Signature, method.Name); arity >= 0 && n >= 0 && n != arity {
 				continue
 			}
+			seen[mi] = true
+			impls = append(impls, mi)
+		}
+	}
+	return impls
+}
 
-			res := resolveCalleeIdx(file.Path, pkg, ref.Name, scope, definitions, callableByFileName, callableByPkgName, callableByName)
-			if !res.ok {
-				callerCopy := definitions[callerIdx]
-				unresolved = append(unresolved, unresolvedFromRef(file.Path, pkg, ref, &callerCopy, res.reason, res.candidateCount))
-				continue
+// parameterCount counts the parameters in the list following name in
+// signature, or returns -1 when the signature has no such list.
+func parameterCount(signature, name string) int {
+	start := strings.Index(signature, name+"(")
+	if start < 0 {
+		return -1
+	}
+	params := signature[start+len(name)+1:]
+	depth, count, empty := 0, 1, true
+	for _, r := range params {
+		switch r {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			if depth == 0 {
+				if empty {
+					return 0
+				}
+				return count
 			}
-
-			sample := CallSample{
-				File:        file.Path,
-				StartLine:   ref.StartLine,
-				StartColumn: ref.StartColumn,
-				Kind:        ref.Kind,
-				Name:        ref.Name,
+			depth--
+		case ',':
+			if depth == 0 {
+				count++
 			}
+		case ' ', '\t', '\n':
+			continue
+		}
+		empty = false
+	}
+	return -1
+}
+
+// resolvedCall is one resolved call reference. Definitions are recorded by
+// ID so the call stays valid when the table is rebuilt.
+type resolvedCall struct {
+	callerID   string
+	calleeID   string
+	resolution string
+	sample     CallSample
+}
+
+// resolveFile resolves the call references of file against the table. A call
+// dispatched polymorphically yields one resolvedCall per candidate.
+func (t *definitionTable) resolveFile(file model.FileSummary, modulePath string) ([]resolvedCall, []UnresolvedCall) {
+	var calls []resolvedCall
+	var unresolved []UnresolvedCall
+	pkg := packageFromPath(file.Path)
+	scope := buildImportScope(file.Imports, modulePath)
+	callableIndices := t.callableByFile[file.Path]
+	closures := closureColumns(file)
+	calls = append(calls, t.closureCalls(file.Path, callableIndices)...)
+	for _, ref := range file.References {
+		if !isCallReference(ref.Kind) {
+			continue
+		}
+		ref.Name = baseName(ref.Name)
+
+		callerIdx := findEnclosingCallableIdx(t.definitions, callableIndices, ref.StartLine, ref.StartColumn, closures)
+		if callerIdx == -1 {
+			unresolved = append(unresolved, unresolvedFromRef(file.Path, pkg, ref, nil, "outside_callable", 0))
+			continue
+		}
 
-			// Polymorphic dispatch: create edges to ALL candidate methods.
-			calleeIndices := res.candidates
-			resolution := res.resolution
-			if len(calleeIndices) == 0 {
-				calleeIndices = []int{res.idx}
-			} else {
-				resolution = "poly_" + res.polyScope
+		res := resolveQualifiedCallee(ref, t.definitions[callerIdx], scope, modulePath, t.packages, t.definitions, t.callableByPkgName, t.callableByName)
+		if !res.ok && res.reason == "" {
+			res = resolveCalleeIdx(file.Path, pkg, ref.Name, scope, t.definitions, t.callableByFileName, t.callableByPkgName, t.callableByName)
+		}
+		if !res.ok {
+			callerCopy := t.definitions[callerIdx]
+			unresolved = append(unresolved, unresolvedFromRef(file.Path, pkg, ref, &callerCopy, res.reason, res.candidateCount))
+			continue
+		}
+
+		sample := CallSample{
+			File:        file.Path,
+			StartLine:   ref.StartLine,
+			StartColumn: ref.StartColumn,
+			Kind:        ref.Kind,
+			Name:        ref.Name,
+		}
+
+		// Polymorphic dispatch: create edges to ALL candidate methods.
+		calleeIndices := res.candidates
+		resolution := res.resolution
+		if len(calleeIndices) == 0 {
+			calleeIndices = []int{res.idx}
+		} else {
+			resolution = "poly_" + res.polyScope
+		}
+		for _, calleeIdx := range calleeIndices {
+			calls = append(calls, resolvedCall{
+				callerID:   t.definitions[callerIdx].ID,
+				calleeID:   t.definitions[calleeIdx].ID,
+				resolution: resolution,
+				sample:     sample,
+			})
+		}
+		if t.dynamic && ref.Qualifier != "" {
+			for _, implIdx := range t.implementations(calleeIndices) {
+				calls = append(calls, resolvedCall{
+					callerID:   t.definitions[callerIdx].ID,
+					calleeID:   t.definitions[implIdx].ID,
+					resolution: "interface",
+					sample:     sample,
+				})
 			}
+		}
+	}
+	return calls, unresolved
+}
 
-			for _, calleeIdx := range calleeIndices {
-				pairKey := keyPair(definitions[callerIdx].ID, definitions[calleeIdx].ID)
-				edge, exists := edgeByPair[pairKey]
-				if !exists {
-					edge = &internalEdge{
-						callerIdx:  callerIdx,
-						calleeIdx:  calleeIdx,
-						resolution: resolution,
-						count:      0,
-						samples:    make([]CallSample, 0, 3),
-					}
-					edgeByPair[pairKey] = edge
-				}
-				edge.count++
-				if len(edge.samples) < 3 {
-					edge.samples = append(edge.samples, sample)
+// graph merges resolved calls, given per file in file order, into edges over
+// the table's definitions.
+func (t *definitionTable) graph(root string, calls [][]resolvedCall, unresolved []UnresolvedCall) Graph {
+	edgeByPair := map[string]*internalEdge{}
+	for _, fileCalls := range calls {
+		for _, call := range fileCalls {
+			callerIdx, callerOK := t.defByID[call.callerID]
+			calleeIdx, calleeOK := t.defByID[call.calleeID]
+			if !callerOK || !calleeOK {
+				continue
+			}
+			pairKey := keyPair(call.callerID, call.calleeID)
+			edge, exists := edgeByPair[pairKey]
+			if !exists {
+				edge = &internalEdge{
+					callerIdx:  callerIdx,
+					calleeIdx:  calleeIdx,
+					resolution: call.resolution,
+					samples:    make([]CallSample, 0, 3),
 				}
+				edgeByPair[pairKey] = edge
+			}
+			edge.count++
+			if len(edge.samples) < 3 {
+				edge.samples = append(edge.samples, call.sample)
 			}
 		}
 	}
 
-	// Materialize edges from internal edges, referencing the backing definitions slice.
 	edges := make([]Edge, 0, len(edgeByPair))
-	outgoingByDef := map[string][]int{}
-	incomingByDef := map[string][]int{}
 	outgoingCount := map[string]int{}
 	incomingCount := map[string]int{}
 	for _, ie := range edgeByPair {
-		edgeIdx := len(edges)
 		edges = append(edges, Edge{
 			CallerIdx:  ie.callerIdx,
 			CalleeIdx:  ie.calleeIdx,
@@ -283,23 +444,18 @@ func Build(idx *model.Index) (Graph, error) {
 			Count:      ie.count,
 			Samples:    ie.samples,
 		})
-		callerID := definitions[ie.callerIdx].ID
-		calleeID := definitions[ie.calleeIdx].ID
-		outgoingByDef[callerID] = append(outgoingByDef[callerID], edgeIdx)
-		incomingByDef[calleeID] = append(incomingByDef[calleeID], edgeIdx)
-		outgoingCount[callerID] += ie.count
-		incomingCount[calleeID] += ie.count
+		outgoingCount[t.definitions[ie.callerIdx].ID] += ie.count
+		incomingCount[t.definitions[ie.calleeIdx].ID] += ie.count
 	}
 
 	sort.Slice(edges, func(i, j int) bool {
-		return edgeLessWithDefs(definitions, edges[i], edges[j])
+		return edgeLessWithDefs(t.definitions, edges[i], edges[j])
 	})
-	// Rebuild edge index maps after sorting since edge indices changed.
-	outgoingByDef = map[string][]int{}
-	incomingByDef = map[string][]int{}
+	outgoingByDef := map[string][]int{}
+	incomingByDef := map[string][]int{}
 	for i := range edges {
-		callerID := definitions[edges[i].CallerIdx].ID
-		calleeID := definitions[edges[i].CalleeIdx].ID
+		callerID := t.definitions[edges[i].CallerIdx].ID
+		calleeID := t.definitions[edges[i].CalleeIdx].ID
 		outgoingByDef[callerID] = append(outgoingByDef[callerID], i)
 		incomingByDef[calleeID] = append(incomingByDef[calleeID], i)
 	}
@@ -318,20 +474,20 @@ func Build(idx *model.Index) (Graph, error) {
 	})
 
 	return Graph{
-		Root:               idx.Root,
-		Definitions:        definitions,
+		Root:               root,
+		Definitions:        t.definitions,
 		Edges:              edges,
 		Unresolved:         unresolved,
-		defByID:            defByID,
-		callableByName:     callableByName,
-		callableByPkgName:  callableByPkgName,
-		callableByFileName: callableByFileName,
-		callableByFile:     callableByFile,
+		defByID:            t.defByID,
+		callableByName:     t.callableByName,
+		callableByPkgName:  t.callableByPkgName,
+		callableByFileName: t.callableByFileName,
+		callableByFile:     t.callableByFile,
 		outgoingByDef:      outgoingByDef,
 		incomingByDef:      incomingByDef,
 		outgoingCount:      outgoingCount,
 		incomingCount:      incomingCount,
-	}, nil
+	}
 }
 
 func (g *Graph) FindDefinitions(pattern string, regexMode bool) ([]Definition, error) {
@@ -340,7 +496,8 @@ func (g *Graph) FindDefinitions(pattern string, regexMode bool) ([]Definition, e
 		return nil, fmt.Errorf("definition matcher cannot be empty")
 	}
 
-	match := func(name string) bool { return name == pattern }
+	exact := baseName(pattern)
+	match := func(name string) bool { return name == exact }
 	if regexMode {
 		compiled, err := regexp.Compile(pattern)
 		if err != nil {
@@ -371,6 +528,28 @@ func (g *Graph) OutgoingCount(defID string) int {
 	return g.outgoingCount[defID]
 }
 
+// TopLevelCallNames returns the names called outside any callable, such as
+// the root component a React entry point renders with
+// createRoot(el).render(<App />).
+func (g *Graph) TopLevelCallNames() map[string]bool {
+	names := map[string]bool{}
+	for _, call := range g.Unresolved {
+		if call.Reason == "outside_callable" {
+			names[call.Name] = true
+		}
+	}
+	return names
+}
+
+// DefinitionOf returns the definition of symbol, declared in file.
+func (g *Graph) DefinitionOf(file string, symbol model.Symbol) (Definition, bool) {
+	i, ok := g.defByID[keyDefinition(file, symbol.Kind, symbol.Name, symbol.StartLine)]
+	if !ok {
+		return Definition{}, false
+	}
+	return g.Definitions[i], true
+}
+
 func (g *Graph) OutgoingEdges(defID string) []Edge {
 	indices := g.outgoingByDef[defID]
 	if len(indices) == 0 {
@@ -396,6 +575,13 @@ func (g *Graph) IncomingEdges(defID string) []Edge {
 }
 
 func (g *Graph) Walk(rootIDs []string, depth int, reverse bool) Walk {
+	return g.WalkFiltered(rootIDs, depth, reverse, nil)
+}
+
+// WalkFiltered is Walk restricted to the definitions keep accepts: edges to
+// a rejected definition are neither reported nor followed. Roots are always
+// kept. A nil keep accepts every definition.
+func (g *Graph) WalkFiltered(rootIDs []string, depth int, reverse bool, keep func(Definition) bool) Walk {
 	if depth <= 0 {
 		depth = 1
 	}
@@ -446,12 +632,17 @@ func (g *Graph) Walk(rootIDs []string, depth int, reverse bool) Walk {
 			edge := &g.Edges[ei]
 			callerID := g.Definitions[edge.CallerIdx].ID
 			calleeID := g.Definitions[edge.CalleeIdx].ID
-			edgeSet[keyPair(callerID, calleeID)] = ei
 
-			nextID := calleeID
+			nextIdx := edge.CalleeIdx
 			if reverse {
-				nextID = callerID
+				nextIdx = edge.CallerIdx
 			}
+			if keep != nil && !rootSet[g.Definitions[nextIdx].ID] && !keep(g.Definitions[nextIdx]) {
+				continue
+			}
+			edgeSet[keyPair(callerID, calleeID)] = ei
+
+			nextID := g.Definitions[nextIdx].ID
 			if visitedNodes[nextID] {
 				continue
 			}
@@ -577,6 +768,7 @@ func unresolvedFromRef(filePath, pkg string, ref model.Reference, caller *Defini
 		StartColumn:    ref.StartColumn,
 		EndLine:        ref.EndLine,
 		EndColumn:      ref.EndColumn,
+		Qualifier:      ref.Qualifier,
 		Caller:         caller,
 		Reason:         reason,
 		CandidateCount: candidateCount,
@@ -595,39 +787,134 @@ func definitionFromSymbol(filePath, pkg string, symbol model.Symbol) Definition
 		StartLine: symbol.StartLine,
 		EndLine:   symbol.EndLine,
 		Callable:  isCallableKind(symbol.Kind),
+		Exported:  symbol.Exported,
 	}
 }
 
 // findEnclosingCallableIdx finds the enclosing callable definition for a given line,
-// returning its index in the definitions slice (or -1 if not found).
-func findEnclosingCallableIdx(definitions []Definition, callableIndices []int, line int) int {
+// returning its index in the definitions slice (or -1 if not found). A
+// closure, which may share its first and last lines with code outside it,
+// encloses only the columns given for it in closures.
+func findEnclosingCallableIdx(definitions []Definition, callableIndices []int, line, column int, closures map[string]closureSpan) int {
 	if len(callableIndices) == 0 {
 		return -1
 	}
 
 	bestIdx := -1
 	bestSpan := 0
+	bestColumn := 0
 	for _, ci := range callableIndices {
 		def := &definitions[ci]
 		if line < def.StartLine || line > def.EndLine {
 			continue
 		}
+		startColumn := 0
+		if closure, ok := closures[def.ID]; ok {
+			if !closure.contains(line, column) {
+				continue
+			}
+			startColumn = closure.startColumn
+		}
 		span := def.EndLine - def.StartLine
-		if bestIdx == -1 || span < bestSpan || (span == bestSpan && def.StartLine > definitions[bestIdx].StartLine) {
+		if bestIdx == -1 || span < bestSpan || (span == bestSpan && (def.StartLine > definitions[bestIdx].StartLine ||
+			def.StartLine == definitions[bestIdx].StartLine && startColumn > bestColumn)) {
 			bestIdx = ci
 			bestSpan = span
+			bestColumn = startColumn
 		}
 	}
 
 	return bestIdx
 }
 
+// closureSpan is the extent of a closure_definition.
+type closureSpan struct {
+	startLine, startColumn int
+	endLine, endColumn     int
+}
+
+// contains reports whether the position lies in the span. Unknown columns
+// (zero) are treated as inside.
+func (c closureSpan) contains(line, column int) bool {
+	if column <= 0 || c.startColumn <= 0 {
+		return true
+	}
+	if line == c.startLine && column < c.startColumn {
+		return false
+	}
+	return line != c.endLine || column < c.endColumn
+}
+
+// closureColumns returns the spans of the closures of file by definition ID.
+func closureColumns(file model.FileSummary) map[string]closureSpan {
+	var spans map[string]closureSpan
+	for _, symbol := range file.Symbols {
+		if symbol.Kind != "closure_definition" {
+			continue
+		}
+		if spans == nil {
+			spans = map[string]closureSpan{}
+		}
+		spans[keyDefinition(file.Path, symbol.Kind, symbol.Name, symbol.StartLine)] = closureSpan{
+			startLine:   symbol.StartLine,
+			startColumn: symbol.StartColumn,
+			endLine:     symbol.EndLine,
+			endColumn:   symbol.EndColumn,
+		}
+	}
+	return spans
+}
+
+// closureCalls links each closure of a file to the function or closure that
+// creates it, with resolution "closure", so walks pass from a function into
+// its goroutine bodies and callbacks. A closure named "Serve.func1" belongs
+// to the innermost enclosing "Serve".
+func (t *definitionTable) closureCalls(filePath string, callableIndices []int) []resolvedCall {
+	var calls []resolvedCall
+	for _, ci := range callableIndices {
+		closure := &t.definitions[ci]
+		if closure.Kind != "closure_definition" {
+			continue
+		}
+		cut := strings.LastIndex(closure.Name, ".func")
+		if cut < 0 {
+			continue
+		}
+		parentName := closure.Name[:cut]
+		parentIdx := -1
+		for _, pi := range callableIndices {
+			parent := &t.definitions[pi]
+			if pi == ci || parent.Name != parentName || parent.StartLine > closure.StartLine || parent.EndLine < closure.EndLine {
+				continue
+			}
+			if parentIdx < 0 || parent.EndLine-parent.StartLine < t.definitions[parentIdx].EndLine-t.definitions[parentIdx].StartLine {
+				parentIdx = pi
+			}
+		}
+		if parentIdx < 0 {
+			continue
+		}
+		calls = append(calls, resolvedCall{
+			callerID:   t.definitions[parentIdx].ID,
+			calleeID:   closure.ID,
+			resolution: "closure",
+			sample: CallSample{
+				File:      filePath,
+				StartLine: closure.StartLine,
+				Kind:      "closure",
+				Name:      closure.Name,
+			},
+		})
+	}
+	return calls
+}
+
 func isCallableKind(kind string) bool {
 	switch kind {
-	case "function_definition", "method_definition":
+	case "method_definition", "closure_definition":
 		return true
 	default:
-		return false
+		return model.IsFunctionKind(kind)
 	}
 }
 
@@ -636,6 +923,7 @@ func buildImportScope(imports []string, modulePath string) importScope {
 		paths:    map[string]struct{}{},
 		packages: map[string]struct{}{},
 		tokens:   map[string]struct{}{},
+		aliases:  map[string]string{},
 	}
 	modulePath = normalizePathKey(modulePath)
 
@@ -654,6 +942,9 @@ func buildImportScope(imports []string, modulePath string) importScope {
 					continue
 				}
 				scope.paths[imp] = struct{}{}
+				if alias := importAlias(imp); alias != "" {
+					scope.aliases[alias] = imp
+				}
 				if pkg, ok := packageFromImportPath(imp, modulePath); ok {
 					scope.packages[pkg] = struct{}{}
 				}
@@ -865,19 +1156,34 @@ func isCallReference(kind string) bool {
 	return strings.HasPrefix(strings.TrimSpace(kind), "reference.call")
 }
 
+// baseName strips the type arguments of a generic instantiation, so a call
+// to Map[int, string] is matched to the definition of Map.
+func baseName(name string) string {
+	if !strings.HasSuffix(name, "]") {
+		return name
+	}
+	if open := strings.IndexByte(name, '['); open > 0 {
+		return name[:open]
+	}
+	return name
+}
+
 func sortDefinitions(items []Definition) {
-	sort.Slice(items, func(i, j int) bool {
-		if items[i].File == items[j].File {
-			if items[i].StartLine == items[j].StartLine {
-				if items[i].Kind == items[j].Kind {
-					return items[i].Name < items[j].Name
-				}
-				return items[i].Kind < items[j].Kind
+	sort.Slice(items, func(i, j int) bool { return definitionLess(&items[i], &items[j]) })
+}
+
+// definitionLess orders definitions by file, start line, kind, and name.
+func definitionLess(left, right *Definition) bool {
+	if left.File == right.File {
+		if left.StartLine == right.StartLine {
+			if left.Kind == right.Kind {
+				return left.Name < right.Name
 			}
-			return items[i].StartLine < items[j].StartLine
+			return left.Kind < right.Kind
 		}
-		return items[i].File < items[j].File
-	})
+		return left.StartLine < right.StartLine
+	}
+	return left.File < right.File
 }
 
 func edgeLessWithDefs(defs []Definition, left, right Edge) bool {
@@ -936,6 +1242,12 @@ func uniqueDefIndices(definitions []Definition, indices []int) []int {
 	return unique
 }
 
+// PackageOf returns the package a file's definitions belong to: its
+// directory, or "." at the root.
+func PackageOf(path string) string {
+	return packageFromPath(path)
+}
+
 func packageFromPath(path string) string {
 	cleaned := filepath.ToSlash(filepath.Clean(path))
 	dir := filepath.ToSlash(filepath.Dir(cleaned))
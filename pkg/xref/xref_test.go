@@ -1199,3 +1199,291 @@ func TestBuildAmbiguousFunctionsNotPolymorphic(t *testing.T) {
 		t.Fatalf("expected reason ambiguous_global, got %q", graph.Unresolved[0].Reason)
 	}
 }
+
+func TestBuildQualifiedCallResolution(t *testing.T) {
+	idx := &model.Index{
+		Root: "/tmp/repo",
+		Files: []model.FileSummary{
+			{
+				Path: "alpha/a.go",
+				Symbols: []model.Symbol{
+					{File: "alpha/a.go", Kind: "function_definition", Name: "Close", StartLine: 1, EndLine: 1},
+				},
+			},
+			{
+				Path: "beta/b.go",
+				Symbols: []model.Symbol{
+					{File: "beta/b.go", Kind: "function_definition", Name: "Close", StartLine: 1, EndLine: 1},
+				},
+			},
+			{
+				Path:    "app/main.go",
+				Imports: []string{"alpha", "os"},
+				Symbols: []model.Symbol{
+					{File: "app/main.go", Kind: "type_definition", Name: "Server", StartLine: 1, EndLine: 1},
+					{File: "app/main.go", Kind: "method_definition", Name: "Close", Receiver: "s *Server", StartLine: 3, EndLine: 3},
+					{File: "app/main.go", Kind: "method_definition", Name: "Run", Receiver: "s *Server", StartLine: 5, EndLine: 9},
+				},
+				References: []model.Reference{
+					{File: "app/main.go", Kind: "reference.call", Name: "Close", Qualifier: "alpha", StartLine: 6, EndLine: 6, StartColumn: 8},
+					{File: "app/main.go", Kind: "reference.call", Name: "Close", Qualifier: "s", StartLine: 7, EndLine: 7, StartColumn: 4},
+					{File: "app/main.go", Kind: "reference.call", Name: "Exit", Qualifier: "os", StartLine: 8, EndLine: 8, StartColumn: 5},
+				},
+			},
+		},
+	}
+
+	graph, err := Build(idx)
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	resolutions := map[string]string{}
+	for _, edge := range graph.Edges {
+		callee := graph.EdgeCallee(edge)
+		resolutions[callee.Package+"."+callee.Name] = edge.Resolution
+	}
+	if len(graph.Edges) != 2 || resolutions["alpha.Close"] != "qualified" || resolutions["app.Close"] != "receiver" {
+		t.Fatalf("unexpected edges %+v", graph.MaterializeEdges(graph.Edges))
+	}
+	if len(graph.Unresolved) != 1 || graph.Unresolved[0].Reason != "external_package" {
+		t.Fatalf("expected os.Exit to be unresolved as external_package, got %+v", graph.Unresolved)
+	}
+}
+
+func TestImportAlias(t *testing.T) {
+	for input, want := range map[string]string{
+		"github.com/odvcencio/gts-suite/pkg/xref": "xref",
+		"gopkg.in/yaml.v3":                        "yaml",
+		"github.com/jackc/pgx/v5":                 "pgx",
+		"github.com/mattn/go-sqlite3":             "sqlite3",
+		"node:fs":                                 "",
+	} {
+		if got := importAlias(input); got != want {
+			t.Fatalf("importAlias(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestWalkAggregatePackages(t *testing.T) {
+	idx := &model.Index{
+		Root: "/tmp/repo",
+		Files: []model.FileSummary{
+			{
+				Path: "api/handler.go",
+				Symbols: []model.Symbol{
+					{File: "api/handler.go", Kind: "function_definition", Name: "Handle", StartLine: 1, EndLine: 5},
+					{File: "api/handler.go", Kind: "function_definition", Name: "validate", StartLine: 7, EndLine: 8},
+				},
+				References: []model.Reference{
+					{File: "api/handler.go", Kind: "reference.call", Name: "validate", StartLine: 2, StartColumn: 2},
+					{File: "api/handler.go", Kind: "reference.call", Name: "Save", StartLine: 3, StartColumn: 2},
+					{File: "api/handler.go", Kind: "reference.call", Name: "Save", StartLine: 4, StartColumn: 2},
+				},
+			},
+			{
+				Path: "store/store.go",
+				Symbols: []model.Symbol{
+					{File: "store/store.go", Kind: "function_definition", Name: "Save", StartLine: 1, EndLine: 3},
+					{File: "store/store.go", Kind: "function_definition", Name: "Load", StartLine: 5, EndLine: 6},
+				},
+			},
+		},
+	}
+
+	graph, err := Build(idx)
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	roots, err := graph.FindDefinitionsAny([]string{"Handle", "Load", "Handle"}, false)
+	if err != nil {
+		t.Fatalf("FindDefinitionsAny returned error: %v", err)
+	}
+	if len(roots) != 2 {
+		t.Fatalf("expected 2 unique roots, got %+v", roots)
+	}
+
+	walk := graph.Walk([]string{roots[0].ID, roots[1].ID}, 2, false)
+	packages := walk.AggregatePackages()
+	if len(packages.Packages) != 2 || packages.Packages[0].Package != "api" || packages.Packages[0].Definitions != 2 {
+		t.Fatalf("unexpected packages %+v", packages.Packages)
+	}
+	if len(packages.Edges) != 1 {
+		t.Fatalf("expected one package edge, got %+v", packages.Edges)
+	}
+	edge := packages.Edges[0]
+	if edge.From != "api" || edge.To != "store" || edge.Calls != 2 || edge.Edges != 1 {
+		t.Fatalf("unexpected package edge %+v", edge)
+	}
+	if packages.Internal != 1 {
+		t.Fatalf("expected 1 internal call, got %d", packages.Internal)
+	}
+}
+
+func TestBuildDynamicLinksInterfaceImplementations(t *testing.T) {
+	method := func(file, receiver, signature string, line int) model.Symbol {
+		return model.Symbol{File: file, Kind: "method_definition", Name: "Get", Receiver: receiver, Signature: signature, StartLine: line, EndLine: line}
+	}
+	idx := &model.Index{
+		Root: "/tmp/repo",
+		Files: []model.FileSummary{
+			{
+				Path:     "store/mem.go",
+				Language: "go",
+				Symbols: []model.Symbol{
+					method("store/mem.go", "*Mem", "func (m *Mem) Get(key string) string", 3),
+					{File: "store/mem.go", Kind: "function_definition", Name: "Lookup", Signature: "func Lookup(s Store) string", StartLine: 5, EndLine: 7},
+				},
+				References: []model.Reference{
+					{File: "store/mem.go", Kind: "reference.call", Name: "Get", Qualifier: "s", StartLine: 6, EndLine: 6, StartColumn: 9, EndColumn: 12},
+				},
+			},
+			{
+				Path:     "disk/disk.go",
+				Language: "go",
+				Symbols: []model.Symbol{
+					method("disk/disk.go", "Disk", "func (d Disk) Get(key string) string", 3),
+					method("disk/disk.go", "Cache", "func (c Cache) Get() string", 5),
+				},
+			},
+		},
+	}
+
+	static, err := Build(idx)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(static.Edges) != 1 {
+		t.Fatalf("expected 1 static edge, got %d", len(static.Edges))
+	}
+
+	graph, err := BuildWithOptions(idx, Options{Dynamic: true})
+	if err != nil {
+		t.Fatalf("BuildWithOptions: %v", err)
+	}
+	disk, err := graph.FindDefinitions("Get", false)
+	if err != nil {
+		t.Fatalf("FindDefinitions: %v", err)
+	}
+	var diskID string
+	for _, def := range disk {
+		if def.Receiver == "Disk" {
+			diskID = def.ID
+		}
+	}
+	walk := graph.Walk([]string{diskID}, 1, true)
+	if len(walk.Edges) != 1 {
+		t.Fatalf("expected Lookup to reach Disk.Get, got %d edges", len(walk.Edges))
+	}
+	edge := walk.Edges[0]
+	if graph.EdgeCaller(edge).Name != "Lookup" || edge.Resolution != "interface" {
+		t.Fatalf("unexpected edge %+v from %s", edge, graph.EdgeCaller(edge).Name)
+	}
+	if len(graph.Edges) != 2 {
+		t.Fatalf("expected Cache.Get, with no parameters, to be left out; got %d edges", len(graph.Edges))
+	}
+}
+
+func TestParameterCount(t *testing.T) {
+	cases := map[string]int{
+		"func (m *Mem) Get(key string) string":           1,
+		"func (m *Mem) Get() string":                     0,
+		"func (m *Mem) Get(ctx context.Context, k, v T)": 3,
+		"func (m *Mem) Get(fn func(a, b int) error)":     1,
+		"def Get(self, key)":                             2,
+		"Get":                                            -1,
+	}
+	for signature, want := range cases {
+		if got := parameterCount(signature, "Get"); got != want {
+			t.Errorf("parameterCount(%q) = %d, want %d", signature, got, want)
+		}
+	}
+}
+
+func TestBuildAttributesCallsToClosures(t *testing.T) {
+	idx := &model.Index{
+		Root: "/tmp/repo",
+		Files: []model.FileSummary{
+			{
+				Path: "server.go",
+				Symbols: []model.Symbol{
+					{File: "server.go", Kind: "function_definition", Name: "handle", StartLine: 1, EndLine: 1},
+					{File: "server.go", Kind: "function_definition", Name: "Serve", StartLine: 3, EndLine: 8, StartColumn: 1, EndColumn: 2},
+					{File: "server.go", Kind: "closure_definition", Name: "Serve.func1", StartLine: 4, EndLine: 6, StartColumn: 5, EndColumn: 3},
+					{File: "server.go", Kind: "closure_definition", Name: "Serve.func2", StartLine: 7, EndLine: 7, StartColumn: 8, EndColumn: 30},
+				},
+				References: []model.Reference{
+					{File: "server.go", Kind: "reference.call", Name: "handle", StartLine: 5, EndLine: 5, StartColumn: 3, EndColumn: 9},
+					{File: "server.go", Kind: "reference.call", Name: "handle", StartLine: 7, EndLine: 7, StartColumn: 20, EndColumn: 26},
+					{File: "server.go", Kind: "reference.call", Name: "handle", StartLine: 7, EndLine: 7, StartColumn: 31, EndColumn: 37},
+				},
+			},
+		},
+	}
+
+	graph, err := Build(idx)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	got := map[string]string{}
+	for _, edge := range graph.Edges {
+		got[graph.EdgeCaller(edge).Name+"->"+graph.EdgeCallee(edge).Name] = edge.Resolution
+	}
+	want := map[string]string{
+		"Serve->Serve.func1":  "closure",
+		"Serve->Serve.func2":  "closure",
+		"Serve.func1->handle": "file",
+		"Serve.func2->handle": "file",
+		"Serve->handle":       "file",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("edges = %v, want %v", got, want)
+	}
+	for key, resolution := range want {
+		if got[key] != resolution {
+			t.Fatalf("edge %s resolution = %q, want %q (edges %v)", key, got[key], resolution, got)
+		}
+	}
+	for _, def := range graph.Definitions {
+		if def.Kind == "closure_definition" && graph.IncomingCount(def.ID) == 0 {
+			t.Fatalf("closure %s has no incoming edge", def.Name)
+		}
+	}
+}
+
+func TestBuildResolvesGenericInstantiations(t *testing.T) {
+	idx := &model.Index{
+		Root: "/tmp/repo",
+		Files: []model.FileSummary{
+			{
+				Path: "main.go",
+				Symbols: []model.Symbol{
+					{File: "main.go", Kind: "function_definition", Name: "Map", Signature: "func Map[T, U any](xs []T, f func(T) U) []U", StartLine: 1, EndLine: 3},
+					{File: "main.go", Kind: "function_definition", Name: "main", StartLine: 5, EndLine: 7},
+				},
+				References: []model.Reference{
+					{File: "main.go", Kind: "reference.call", Name: "Map[int, string]", StartLine: 6, EndLine: 6, StartColumn: 2, EndColumn: 18},
+				},
+			},
+		},
+	}
+
+	graph, err := Build(idx)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(graph.Edges) != 1 || len(graph.Unresolved) != 0 {
+		t.Fatalf("expected Map[int, string] to resolve to Map, got edges=%d unresolved=%+v", len(graph.Edges), graph.Unresolved)
+	}
+	if callee := graph.EdgeCallee(graph.Edges[0]); callee.Name != "Map" {
+		t.Fatalf("unexpected callee %s", callee.Name)
+	}
+
+	defs, err := graph.FindDefinitions("Map[int, string]", false)
+	if err != nil {
+		t.Fatalf("FindDefinitions: %v", err)
+	}
+	if len(defs) != 1 || defs[0].Name != "Map" {
+		t.Fatalf("expected instantiation to find Map, got %+v", defs)
+	}
+}
@@ -47,7 +47,7 @@ func Generate(idx *model.Index, root string, opts Options) ([]GeneratedRule, err
 
 	for _, f := range idx.Files {
 		for _, sym := range f.Symbols {
-			if sym.Kind != "function_definition" && sym.Kind != "method_definition" {
+			if !model.IsFunctionKind(sym.Kind) && sym.Kind != "method_definition" {
 				continue
 			}
 			totalFunctions++